@@ -27,6 +27,7 @@ import (
 	"aahframe.work/ainsp"
 	"aahframe.work/aruntime"
 	"aahframe.work/aruntime/diagnosis"
+	"aahframe.work/broker"
 	"aahframe.work/cache"
 	"aahframe.work/config"
 	"aahframe.work/console"
@@ -34,9 +35,11 @@ import (
 	"aahframe.work/i18n"
 	"aahframe.work/internal/settings"
 	"aahframe.work/log"
+	"aahframe.work/migrate"
 	"aahframe.work/router"
 	"aahframe.work/security"
 	"aahframe.work/security/acrypto"
+	"aahframe.work/security/authserver"
 	"aahframe.work/security/session"
 	"aahframe.work/valpar"
 	"aahframe.work/vfs"
@@ -63,6 +66,13 @@ func App() *Application {
 	return defaultApp
 }
 
+// NewApp method creates a new aah application instance. Application uses
+// `aah.App()`, new instance is mainly for testing purpose; see package
+// `aahtest`.
+func NewApp() *Application {
+	return newApp()
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // aah application instance
 //______________________________________________________________________________
@@ -75,7 +85,9 @@ func newApp() *Application {
 		settings: &settings.Settings{
 			VirtualBaseDir: "/app",
 		},
-		cacheMgr: cache.NewManager(),
+		cacheMgr:  cache.NewManager(),
+		brokerMgr: broker.NewManager(),
+		injector:  newInjector(),
 	}
 	aahApp.cli.Commands = make([]console.Command, 0)
 
@@ -85,6 +97,7 @@ func newApp() *Application {
 		registry: &ainsp.TargetRegistry{
 			Registry:   make(map[string]*ainsp.Target),
 			SearchType: ctxPtrType,
+			InjectTag:  injectTag,
 		},
 	}
 	aahApp.he.ctxPool.New = func() interface{} { return aahApp.he.newContext() }
@@ -95,6 +108,9 @@ func newApp() *Application {
 		mu:          sync.RWMutex{},
 	}
 
+	aahApp.supervisor = newSupervisor(aahApp)
+	aahApp.webhookMgr = newWebhookManager(aahApp)
+	aahApp.goroutines = newGoroutines()
 	aahApp.logger, _ = log.New(config.NewEmpty())
 
 	return aahApp
@@ -106,7 +122,11 @@ type Application struct {
 	buildInfo      *BuildInfo
 	settings       *settings.Settings
 	cli            *console.Application
+	cmdMiddlewares []CommandMiddleware
+	consoleEval    ConsoleEvaluatorFunc
 	cfg            *config.Config
+	cfgProvider    config.Provider
+	cfgWatchStop   chan struct{}
 	vfs            *vfs.VFS
 	tlsCfg         *tls.Config
 	he             *HTTPEngine
@@ -118,15 +138,47 @@ type Application struct {
 	bindMgr        *bindManager
 	i18n           i18n.I18ner
 	securityMgr    *security.Manager
+	authServer     *authserver.Server
 	viewMgr        *viewManager
 	staticMgr      *staticManager
+	proxyMgr       *proxyManager
+	mirrorMgr      *mirrorManager
 	errorMgr       *errorManager
 	cacheMgr       *cache.Manager
+	brokerMgr      *broker.Manager
+	webhookMgr     *WebhookManager
+	webhookProvs   map[string]ahttp.WebhookProvider
+	webhookGuard   ahttp.WebhookReplayGuard
+	msgHandlers    []*messageHandler
+	supervisor     *supervisor
+	goroutines     *goroutines
+	panicReporter  PanicReporterFunc
+	throttle       *loginThrottle
+	rateLimiter    *rateLimiter
+	quota          *quotaManager
+	quotaStore     QuotaStore
+	idempotency    *idempotencyManager
+	singleflight   *singleflight
+	rewriteRules   []*rewriteRule
+	redirectRules  []*redirectRule
+	tenantResolver TenantResolver
 	sc             chan os.Signal
 	logger         log.Loggerer
+	moduleLoggers  map[string]*log.Logger
+	timingStats    timingStats
+	connMetrics    connMetrics
+	canaryStats    canaryStats
+	upgrader       upgrader
 	accessLog      *accessLogger
 	dumpLog        *dumpLogger
 	diagnosis      *diagnosis.Diagnosis
+	dsMgr          *dataSourceManager
+	migrator       *migrate.Migrator
+	seeds          []*Seed
+	injector       *injector
+	sanitizer      Sanitizer
+	cookieMgr      *cookieManager
+	binders        map[reflect.Type]ContextualBinder
 }
 
 // InitForCLI method is for purpose aah CLI tool. IT IS NOT FOR AAH USER.
@@ -141,16 +193,53 @@ func (a *Application) InitForCLI(importPath string) error {
 	if err = a.initConfig(); err != nil {
 		return err
 	}
-	if err = a.settings.Refresh(a.Config()); err != nil {
+	if err = a.refreshSettings(); err != nil {
 		return err
 	}
-	if err = a.initRouter(); err != nil {
-		return err
+	if !a.IsWorker() {
+		if err = a.initRouter(); err != nil {
+			return err
+		}
+		if err = a.initRewrite(); err != nil {
+			return err
+		}
+		if err = a.initRedirect(); err != nil {
+			return err
+		}
 	}
 	_ = a.Log().(*log.Logger).SetLevel("debug")
 	return nil
 }
 
+// InitForTest method initializes the aah application for the given import path
+// or physical path without starting the server. If middlewares are not added
+// then framework default middleware stack is used. It is mainly used by
+// package `aahtest`.
+func (a *Application) InitForTest(importPath string) error {
+	a.settings.ImportPath = path.Clean(importPath)
+	var err error
+	if err = a.initPath(); err != nil {
+		return err
+	}
+	if err = a.initConfig(); err != nil {
+		return err
+	}
+	if err = a.initApp(); err != nil {
+		return err
+	}
+	if len(a.he.mwStack) == 0 {
+		a.he.Middlewares(
+			RouteMiddleware,
+			CORSMiddleware,
+			BindMiddleware,
+			AntiCSRFMiddleware,
+			AuthcAuthzMiddleware,
+			ActionMiddleware,
+		)
+	}
+	return nil
+}
+
 // Name method returns aah application name from app config `name` otherwise
 // app name of the base directory.
 func (a *Application) Name() string {
@@ -168,7 +257,7 @@ func (a *Application) InstanceName() string {
 	return a.Config().StringDefault("instance_name", "")
 }
 
-// Type method returns aah application type info e.g.: web, api, websocket.
+// Type method returns aah application type info e.g.: web, api, websocket, worker.
 //
 // Value of `type` from `aah.conf`.
 func (a *Application) Type() string {
@@ -302,6 +391,15 @@ func (a *Application) IsWebSocketEnabled() bool {
 	return a.cfg.BoolDefault("server.websocket.enable", false)
 }
 
+// IsWorker method returns true if aah application type is `worker`. Worker
+// application runs event store, supervised workers, broker consumers and
+// scheduled tasks without HTTP and WebSocket listeners.
+//
+// Value of `type` from `aah.conf`.
+func (a *Application) IsWorker() bool {
+	return a.Type() == "worker"
+}
+
 // NewChildLogger method create a child logger from aah application default logger.
 func (a *Application) NewChildLogger(fields log.Fields) log.Loggerer {
 	return a.Log().WithFields(fields)
@@ -380,8 +478,10 @@ func (a *Application) Validator() *validator.Validate {
 	return valpar.Validator()
 }
 
-// SetMinifier method sets the given minifier func into aah framework.
-// Note: currently minifier is called only for HTML contentType.
+// SetMinifier method sets the given minifier func into aah framework, it
+// replaces the existing minifier chain. Minifier is called for content types
+// configured in `render.minify.types`, default is HTML. Use `AddMinifier` to
+// chain multiple minifiers.
 func (a *Application) SetMinifier(fn MinifierFunc) {
 	if a.viewMgr == nil {
 		a.viewMgr = &viewManager{a: a}
@@ -405,6 +505,35 @@ func (a *Application) AddController(c interface{}, methods []*ainsp.Method) {
 	a.HTTPEngine().registry.Add(c, methods)
 }
 
+// AddRequestType method registers the request body DTO type for the given
+// controller action. It is used by OpenAPI spec generation and dump log
+// redaction, for the actions which reads request body on its own.
+//
+// 	app.AddRequestType((*controllers.UserController)(nil), "Create", &models.UserDTO{})
+func (a *Application) AddRequestType(controller interface{}, action string, dto interface{}) error {
+	method, err := a.lookupControllerAction(controller, action)
+	if err != nil {
+		return err
+	}
+	method.Request = reflect.TypeOf(dto)
+	return nil
+}
+
+// AddResponseType method registers the typed response for the given
+// controller action and HTTP status code. It is used by OpenAPI spec
+// generation (schema and example payload), typed reply validation and dump
+// log redaction. See `Reply().OkT`.
+//
+// 	app.AddResponseType((*controllers.UserController)(nil), "Get", http.StatusOK, &models.UserDTO{})
+func (a *Application) AddResponseType(controller interface{}, action string, code int, dto interface{}) error {
+	method, err := a.lookupControllerAction(controller, action)
+	if err != nil {
+		return err
+	}
+	method.AddResponse(&ainsp.Response{Code: code, Type: reflect.TypeOf(dto)})
+	return nil
+}
+
 // AddWebSocket method adds given WebSocket into WebSocket registry.
 func (a *Application) AddWebSocket(w interface{}, methods []*ainsp.Method) {
 	a.WSEngine().AddWebSocket(w, methods)
@@ -440,12 +569,43 @@ func (a *Application) AddValueParser(typ reflect.Type, parser valpar.Parser) err
 	return valpar.AddValueParser(typ, parser)
 }
 
+// AddContextualBinder method adds given contextual binder for the action
+// parameter type. Binder has access to request context, so the values like
+// current user, tenant, session values or parsed header could be injected
+// into action parameters. It takes precedence over value parser of the type.
+//
+// 	app.AddContextualBinder(reflect.TypeOf((*models.CurrentUser)(nil)),
+// 		func(ctx *aah.Context, key string) (interface{}, error) {
+// 			return models.FindUser(ctx.Subject().PrimaryPrincipal().Value)
+// 		})
+func (a *Application) AddContextualBinder(typ reflect.Type, binder ContextualBinder) error {
+	if typ == nil || binder == nil {
+		return errors.New("aah: type or contextual binder is nil")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if a.binders == nil {
+		a.binders = make(map[reflect.Type]ContextualBinder)
+	}
+	if _, found := a.binders[typ]; found {
+		return fmt.Errorf("aah: contextual binder for type '%v' already exists", typ)
+	}
+	a.binders[typ] = binder
+	return nil
+}
+
 // AddCommand method adds the aah application CLI commands. Introduced in v0.12.0 release
 // aah application binary fully compliant using module console and POSIX flags.
+//
+// Command `Category` is shown in the app help, global flags `--envprofile`,
+// `--config` and `--log-level` are applied before the command action and
+// command middlewares are applied on its action, refer to
+// `Application.AddCommandMiddleware`.
 func (a *Application) AddCommand(cmds ...console.Command) error {
 	for _, cmd := range cmds {
 		name := strings.ToLower(cmd.Name)
-		if name == "run" || name == "vfs" || name == "help" {
+		if ess.IsSliceContainsString(builtinCommands, name) {
 			return fmt.Errorf("aah: reserved command name '%s' cannot be used", name)
 		}
 		for _, c := range a.cli.Commands {
@@ -477,12 +637,30 @@ func (a *Application) AddCommand(cmds ...console.Command) error {
 	return nil
 }
 
+// AddCommandMiddleware method adds the given middlewares into aah application
+// CLI commands, it's applied on every command and subcommand action including
+// framework built-in commands. Middlewares are executed in the order of added.
+// Method `next` must be called to continue the command execution, so it's
+// suitable for persistent pre-run and post-run hooks.
+//
+//	aah.App().AddCommandMiddleware(func(c *console.Context, next console.ActionFunc) error {
+//		aah.App().Log().Infof("Command '%s' started", c.Command.FullName())
+//		err := next(c)
+//		aah.App().Log().Infof("Command '%s' completed", c.Command.FullName())
+//		return err
+//	})
+func (a *Application) AddCommandMiddleware(mws ...CommandMiddleware) {
+	a.Lock()
+	defer a.Unlock()
+	a.cmdMiddlewares = append(a.cmdMiddlewares, mws...)
+}
+
 func (a *Application) wrapCmdAction(caFn func(*console.Context) error) func(*console.Context) error {
 	return func(c *console.Context) error {
-		profileName := c.String("envprofile")
+		profileName := cliEnvProfile(c)
 		if len(profileName) > 0 {
-			a.Config().SetString("env.active", c.String("envprofile"))
-			if err := a.settings.Refresh(a.Config()); err != nil {
+			a.Config().SetString("env.active", profileName)
+			if err := a.refreshSettings(); err != nil {
 				return err
 			}
 		}
@@ -640,55 +818,105 @@ func (a *Application) initApp() error {
 		a.EventStore().sortEventSubscribers(event)
 	}
 	a.EventStore().PublishSync(&Event{Name: EventOnInit}) // publish `OnInit` server event
-	if err = a.settings.Refresh(a.Config()); err != nil {
+	if err = a.refreshSettings(); err != nil {
+		return err
+	}
+	overridden, err := a.overrideConfigFromEnv()
+	if err != nil {
 		return err
 	}
 	if err = a.initLog(); err != nil {
 		return err
 	}
+	if len(overridden) > 0 {
+		a.Log().Infof("Config values overridden from environment variables: %s", strings.Join(overridden, ", "))
+	}
 	if err = a.initI18n(); err != nil {
 		return err
 	}
 	if err = a.initSecurity(); err != nil {
 		return err
 	}
-	if err = a.initRouter(); err != nil {
-		return err
+	if !a.IsWorker() {
+		if err = a.initRouter(); err != nil {
+			return err
+		}
+		if err = a.initSanitizer(); err != nil {
+			return err
+		}
+		if err = a.initBind(); err != nil {
+			return err
+		}
+		if err = a.initIdempotency(); err != nil {
+			return err
+		}
+		if err = a.initSingleflight(); err != nil {
+			return err
+		}
+		if err = a.initView(); err != nil {
+			return err
+		}
+		a.initMinify()
+		if err = a.initStatic(); err != nil {
+			return err
+		}
+		if err = a.initError(); err != nil {
+			return err
+		}
+		if a.settings.AccessLogEnabled {
+			if err = a.initAccessLog(); err != nil {
+				return err
+			}
+		}
+		if a.settings.DumpLogEnabled {
+			if err = a.initDumpLog(); err != nil {
+				return err
+			}
+		}
+		if a.IsWebSocketEnabled() {
+			if a.wse, err = ws.New(&moduleApp{Application: a, name: "ws"}); err != nil {
+				return err
+			}
+			a.wse.SetEventSubscriber(a.eventStore.subscribeFunc)
+		}
 	}
-	if err = a.initBind(); err != nil {
+	if err := a.CacheManager().InitProviders(a.Config(), a.ModuleLog("cache")); err != nil {
 		return err
 	}
-	if err = a.initView(); err != nil {
+	if err = a.initDataSource(); err != nil {
 		return err
 	}
-	if err = a.initStatic(); err != nil {
+	if err = a.initBroker(); err != nil {
 		return err
 	}
-	if err = a.initError(); err != nil {
+	if err = a.initSupervisor(); err != nil {
 		return err
 	}
-	if a.settings.AccessLogEnabled {
-		if err = a.initAccessLog(); err != nil {
-			return err
-		}
-	}
-	if a.settings.DumpLogEnabled {
-		if err = a.initDumpLog(); err != nil {
-			return err
-		}
-	}
-	if a.IsWebSocketEnabled() {
-		if a.wse, err = ws.New(a); err != nil {
-			return err
-		}
+	if err = a.initWebhook(); err != nil {
+		return err
 	}
-	if err := a.CacheManager().InitProviders(a.Config(), a.Log()); err != nil {
+	if err = a.initPools(); err != nil {
 		return err
 	}
+	if a.isMigrateAutoRun() {
+		a.OnStart(a.autoMigrate, 1)
+	}
 	a.settings.Initialized = true
 	return nil
 }
 
+func (a *Application) lookupControllerAction(controller interface{}, action string) (*ainsp.Method, error) {
+	target := a.HTTPEngine().registry.LookupByType(controller)
+	if target == nil {
+		return nil, fmt.Errorf("aah: controller '%T' not found", controller)
+	}
+	method := target.Lookup(action)
+	if method == nil {
+		return nil, fmt.Errorf("aah: action '%s.%s' not found", target.Name, action)
+	}
+	return method, nil
+}
+
 func (a *Application) binaryFilename() string {
 	if a.buildInfo == nil {
 		return ""
@@ -717,6 +945,7 @@ func (a *Application) aahRecover() {
 
 		a.Log().Error("Recovered from panic:")
 		a.Log().Error(b.String())
+		a.reportPanic(nil, r, strace)
 	}
 }
 
@@ -729,16 +958,116 @@ func (a *Application) Config() *config.Config {
 	return a.cfg
 }
 
+// SetConfigProvider method sets the remote or centralized config provider,
+// for e.g.: etcd, Consul, HTTP endpoint. Provider config values are merged on
+// top of `aah.conf` and changes trigger the config hot-reload.
+//
+// 	func init() {
+// 		aah.App().SetConfigProvider(config.NewHTTPProvider("https://config.example.com/myapp.conf", 30*time.Second))
+// 	}
+func (a *Application) SetConfigProvider(provider config.Provider) {
+	a.cfgProvider = provider
+}
+
 func (a *Application) initConfig() error {
 	cfg, err := config.LoadFile(path.Join(a.VirtualBaseDir(), "config", "aah.conf"))
 	if err != nil {
 		return fmt.Errorf("aah.conf: %s", err)
 	}
 
+	if a.cfgProvider != nil {
+		pcfg, err := a.cfgProvider.Load()
+		if err != nil {
+			return fmt.Errorf("config provider: %s", err)
+		}
+		if err = cfg.Merge(pcfg); err != nil {
+			return fmt.Errorf("config provider: %s", err)
+		}
+	}
+
+	warnings, err := frameworkConfigSchema().Validate(cfg)
+	for _, w := range warnings {
+		log.Warnf("DEPRECATED: config %s", w)
+	}
+	if err != nil {
+		return fmt.Errorf("aah.conf: %s", err)
+	}
+
 	a.cfg = cfg
 	return nil
 }
 
+// refreshSettings method loads the env profile config files and refreshes
+// the app settings for active env profile.
+func (a *Application) refreshSettings() error {
+	if err := a.loadProfileConfig(); err != nil {
+		return err
+	}
+	return a.settings.Refresh(a.Config())
+}
+
+// loadProfileConfig method loads the env profile config files of active env
+// profile if exists and merges into app config, it keeps large profile
+// overrides out of the primary config files-
+//  - `config/aah.<profile>.conf` into `env.<profile> { ... }`
+//  - `config/routes.<profile>.conf` into `env.<profile>.routes { ... }`
+//
+// Values from profile config files take precedence over `env.<profile>`
+// values of `aah.conf`.
+func (a *Application) loadProfileConfig() error {
+	profile := strings.TrimPrefix(a.Config().StringDefault("env.active", settings.DefaultEnvProfile), settings.ProfilePrefix)
+	overlay := config.NewEmpty()
+	for _, name := range []string{"aah", "routes"} {
+		fname := name + "." + profile + ".conf"
+		file := path.Join(a.VirtualBaseDir(), "config", fname)
+		if !a.VFS().IsExists(file) {
+			continue
+		}
+		pcfg, err := config.LoadFile(file)
+		if err != nil {
+			return fmt.Errorf("%s: %s", fname, err)
+		}
+		key := settings.ProfilePrefix + profile
+		if name == "routes" {
+			key += ".routes"
+		}
+		if err = overlay.Merge2Section(key, pcfg); err != nil {
+			return fmt.Errorf("%s: %s", fname, err)
+		}
+	}
+	if len(overlay.Keys()) == 0 {
+		return nil
+	}
+	return a.Config().Merge(overlay)
+}
+
+// overrideConfigFromEnv method applies the environment variable overrides
+// `AAH_*` onto config values after the env profile is activated, so the
+// precedence order is (lowest to highest):
+//
+//  - `aah.conf` and its includes
+//  - external config file `--config`
+//  - active env profile `env.<profile> { ... }`
+//  - env profile config files `config/aah.<profile>.conf`
+//  - environment variables, prefix is configured at `env.override.prefix`
+func (a *Application) overrideConfigFromEnv() ([]string, error) {
+	cfg := a.Config()
+	if !cfg.BoolDefault("env.override.enable", true) {
+		return nil, nil
+	}
+	overridden, err := cfg.OverrideFromEnv(cfg.StringDefault("env.override.prefix", "AAH"))
+	if err != nil {
+		return nil, err
+	}
+	if len(overridden) > 0 {
+		// reinitialize settings with overridden values
+		if err := a.settings.Refresh(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return overridden, nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Log Definitions
 //______________________________________________________________________________
@@ -753,19 +1082,65 @@ func (a *Application) AddLoggerHook(name string, hook log.HookFunc) error {
 	return a.Log().(*log.Logger).AddHook(name, hook)
 }
 
+// SetLogLevel method sets the given logging level into aah application
+// logger, it takes effect immediately for application logger and its child
+// loggers. For e.g.: INFO, WARN, DEBUG, etc. Case-insensitive.
+//
+// Event `OnLogConfigChange` is published on successful change.
+func (a *Application) SetLogLevel(level string) error {
+	if err := a.Log().(*log.Logger).SetLevel(level); err != nil {
+		return err
+	}
+	a.Config().SetString("log.level", level)
+	a.EventStore().PublishSync(&Event{Name: EventOnLogConfigChange, Data: a.Log()})
+	return nil
+}
+
+// SetLogReceiver method sets the given log receiver into aah application
+// logger, it takes effect immediately for application logger and its child
+// loggers. Given options are applied under config section `log { ... }`,
+// for e.g.: `file`, `format`, `color`, etc.
+//
+// 	aah.App().SetLogReceiver("file", map[string]interface{}{
+// 		"file": "myapp-debug.log",
+// 	})
+//
+// Event `OnLogConfigChange` is published on successful change.
+func (a *Application) SetLogReceiver(receiver string, opts map[string]interface{}) error {
+	cfg := a.Config()
+	for k, v := range opts {
+		key := "log." + k
+		switch t := v.(type) {
+		case string:
+			cfg.SetString(key, t)
+		case bool:
+			cfg.SetBool(key, t)
+		case int:
+			cfg.SetInt(key, t)
+		case int64:
+			cfg.SetInt64(key, t)
+		case float64:
+			cfg.SetFloat64(key, t)
+		default:
+			return fmt.Errorf("aah: unsupported log receiver option value type '%T' for key '%s'", v, k)
+		}
+	}
+	cfg.SetString("log.receiver", receiver)
+	a.resolveLogFile()
+
+	if err := a.Log().(*log.Logger).SetReceiverByName(receiver); err != nil {
+		return err
+	}
+	a.EventStore().PublishSync(&Event{Name: EventOnLogConfigChange, Data: a.Log()})
+	return nil
+}
+
 func (a *Application) initLog() error {
 	if !a.Config().IsExists("log") {
 		a.Log().Warn("Section 'log { ... }' configuration does not exists, initializing app logger with default values.")
 	}
 
-	if a.Config().StringDefault("log.receiver", "") == "file" {
-		file := a.Config().StringDefault("log.file", "")
-		if ess.IsStrEmpty(file) {
-			a.Config().SetString("log.file", filepath.Join(a.logsDir(), a.binaryFilename()+".log"))
-		} else if !filepath.IsAbs(file) {
-			a.Config().SetString("log.file", filepath.Join(a.logsDir(), file))
-		}
-	}
+	a.resolveLogFile()
 
 	if !a.Config().IsExists("log.pattern") {
 		a.Config().SetString("log.pattern", "%time:2006-01-02 15:04:05.000 %level:-5 %appname %insname %reqid %principal %message %fields")
@@ -780,12 +1155,27 @@ func (a *Application) initLog() error {
 		"appname": a.Name(),
 		"insname": a.InstanceName(),
 	})
+	if err = a.initModuleLoggers(al); err != nil {
+		return err
+	}
 
 	a.logger = al
 	log.SetDefaultLogger(al)
 	return nil
 }
 
+func (a *Application) resolveLogFile() {
+	if strings.ToLower(a.Config().StringDefault("log.receiver", "")) != "file" {
+		return
+	}
+	file := a.Config().StringDefault("log.file", "")
+	if ess.IsStrEmpty(file) {
+		a.Config().SetString("log.file", filepath.Join(a.logsDir(), a.binaryFilename()+".log"))
+	} else if !filepath.IsAbs(file) {
+		a.Config().SetString("log.file", filepath.Join(a.logsDir(), file))
+	}
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // i18n Definitions
 //______________________________________________________________________________
@@ -844,7 +1234,7 @@ func (a *Application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if h := r.Header[ahttp.HeaderUpgrade]; len(h) > 0 {
-		if h[0] == "websocket" || h[0] == "Websocket" {
+		if (h[0] == "websocket" || h[0] == "Websocket") && !a.proxyMgr.IsProxyRequest(r) {
 			a.wse.Handle(w, r)
 			return
 		}
@@ -874,6 +1264,25 @@ func (a *Application) listenForHotReload() {
 	}
 }
 
+// watchConfigProvider method watches the config provider for changes and
+// performs the config hot-reload on change.
+func (a *Application) watchConfigProvider(stop <-chan struct{}) {
+	err := a.cfgProvider.Watch(stop, func() {
+		a.Log().Warn("Config change received from config provider")
+		a.performHotReload()
+	})
+	if err != nil {
+		a.Log().Errorf("Config provider watch: %v", err)
+	}
+}
+
+func (a *Application) stopConfigProviderWatch() {
+	if a.cfgWatchStop != nil {
+		close(a.cfgWatchStop)
+		a.cfgWatchStop = nil
+	}
+}
+
 func (a *Application) performHotReload() {
 	a.settings.HotReload = true
 	defer func() { a.settings.HotReload = false }()
@@ -892,10 +1301,14 @@ func (a *Application) performHotReload() {
 	// Set activeProfile into reloaded configuration
 	a.Config().SetString("env.active", activeProfile)
 
-	if err = a.settings.Refresh(a.Config()); err != nil {
+	if err = a.refreshSettings(); err != nil {
 		a.Log().Errorf("Unable to reinitialize aah application settings: %v", err)
 		return
 	}
+	if _, err = a.overrideConfigFromEnv(); err != nil {
+		a.Log().Errorf("Unable to apply config environment overrides: %v", err)
+		return
+	}
 	a.Log().Info("Configuration values reinitialize succeeded")
 
 	if err = a.initLog(); err != nil {
@@ -903,6 +1316,7 @@ func (a *Application) performHotReload() {
 		return
 	}
 	a.Log().Info("Logging reinitialize succeeded")
+	a.EventStore().PublishSync(&Event{Name: EventOnLogConfigChange, Data: a.Log()})
 
 	if err = a.initI18n(); err != nil {
 		a.Log().Errorf("Unable to reinitialize application i18n: %v", err)
@@ -912,18 +1326,34 @@ func (a *Application) performHotReload() {
 		a.Log().Info("I18n reinitialize succeeded")
 	}
 
-	if err = a.initRouter(); err != nil {
-		a.Log().Errorf("Unable to reinitialize application %v", err)
-		return
-	}
-	a.Log().Info("Router reinitialize succeeded")
+	if !a.IsWorker() {
+		if err = a.initRouter(); err != nil {
+			a.Log().Errorf("Unable to reinitialize application %v", err)
+			return
+		}
+		a.Log().Info("Router reinitialize succeeded")
 
-	if err = a.initView(); err != nil {
-		a.Log().Errorf("Unable to reinitialize application views: %v", err)
-		return
-	}
-	if a.Type() == "web" {
-		a.Log().Info("View engine reinitialize succeeded")
+		if err = a.initRewrite(); err != nil {
+			a.Log().Errorf("Unable to reinitialize application rewrite rules: %v", err)
+			return
+		}
+		if err = a.initRedirect(); err != nil {
+			a.Log().Errorf("Unable to reinitialize application redirect rules: %v", err)
+			return
+		}
+
+		if err = a.initView(); err != nil {
+			a.Log().Errorf("Unable to reinitialize application views: %v", err)
+			return
+		}
+		if a.Type() == "web" {
+			a.Log().Info("View engine reinitialize succeeded")
+		}
+
+		if err = a.errorMgr.initViews(); err != nil {
+			a.Log().Errorf("Unable to reinitialize application error views: %v", err)
+			return
+		}
 	}
 
 	if err = a.initSecurity(); err != nil {
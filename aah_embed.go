@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.16
+
+package aah
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// EmbedFS method mounts the given embedded app tree into default aah
+// application, refer to `Application.EmbedFS`.
+func EmbedFS(fsys fs.FS) error {
+	return App().EmbedFS(fsys)
+}
+
+// EmbedFS method mounts the given embedded app tree (for e.g.: `embed.FS`)
+// into VFS at `/app` and marks the application as packaged. Single binary
+// is produced with plain `go build`, without aah CLI generated VFS code.
+//
+// 	//go:embed aah.project config i18n static views
+// 	var appFS embed.FS
+//
+// 	func main() {
+// 		if err := aah.EmbedFS(appFS); err != nil {
+// 			log.Fatal(err)
+// 		}
+// 		if err := aah.App().Run(os.Args); err != nil {
+// 			log.Fatal(err)
+// 		}
+// 	}
+//
+// Build info is derived from the binary if not set via
+// `Application.SetBuildInfo`.
+func (a *Application) EmbedFS(fsys fs.FS) error {
+	if err := a.VFS().AddMountFS(a.VirtualBaseDir(), fsys); err != nil {
+		return err
+	}
+	a.SetPackaged(true)
+	if a.BuildInfo() == nil {
+		a.SetBuildInfo(binaryBuildInfo())
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// binaryBuildInfo method returns the build info of the running binary.
+func binaryBuildInfo() *BuildInfo {
+	bi := &BuildInfo{
+		Version:    "(devel)",
+		Timestamp:  time.Now().Format(time.RFC3339),
+		AahVersion: Version,
+		GoVersion:  runtime.Version(),
+	}
+	if ep, err := os.Executable(); err == nil {
+		bi.BinaryName = filepath.Base(ep)
+		if fi, err := os.Stat(ep); err == nil {
+			bi.Timestamp = fi.ModTime().Format(time.RFC3339)
+		}
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && len(info.Main.Version) > 0 {
+		bi.Version = info.Main.Version
+	}
+	return bi
+}
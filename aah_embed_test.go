@@ -0,0 +1,35 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.16
+
+package aah
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppEmbedFS(t *testing.T) {
+	a := newApp()
+	a.SetBuildInfo(nil)
+	err := a.EmbedFS(os.DirFS(filepath.Join(testdataBaseDir(), "webapp1")))
+	assert.Nil(t, err)
+	assert.True(t, a.IsPackaged())
+	assert.True(t, a.VFS().IsEmbeddedMode())
+	assert.NotNil(t, a.BuildInfo())
+	assert.Equal(t, Version, a.BuildInfo().AahVersion)
+	assert.True(t, len(a.BuildInfo().BinaryName) > 0)
+
+	assert.Nil(t, a.initPath())
+	assert.Nil(t, a.initConfig())
+	assert.Equal(t, "webapp1", a.Config().StringDefault("name", ""))
+	assert.True(t, a.VFS().IsExists("/app/views/common/head_tags.html"))
+
+	ep, _ := os.Executable()
+	assert.Equal(t, filepath.Dir(ep), a.BaseDir())
+}
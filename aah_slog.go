@@ -0,0 +1,41 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.21
+
+package aah
+
+import (
+	"log/slog"
+
+	"aahframe.work/log"
+)
+
+// Slog method returns the `*slog.Logger` backed by aah application logger,
+// so apps and libraries that standardize on `log/slog` share the same log
+// pipeline. Record attributes are logged as log fields.
+//
+// 	slog.SetDefault(aah.App().Slog())
+func (a *Application) Slog() *slog.Logger {
+	return a.Log().(*log.Logger).ToSlog()
+}
+
+// SetLogHandler method sets the given `slog.Handler` as aah application log
+// receiver, it takes effect immediately for application logger and its
+// child loggers. Log pattern is not applicable, slog handler formats the
+// log record.
+//
+// 	aah.App().SetLogHandler(slog.NewJSONHandler(os.Stdout, nil))
+//
+// Event `OnLogConfigChange` is published on successful change.
+func (a *Application) SetLogHandler(h slog.Handler) error {
+	if h == nil {
+		return log.ErrLogReceiverIsNil
+	}
+	if err := a.Log().(*log.Logger).SetReceiver(log.NewSlogReceiver(h)); err != nil {
+		return err
+	}
+	a.EventStore().PublishSync(&Event{Name: EventOnLogConfigChange, Data: a.Log()})
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.21
+
+package aah
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogHandler(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`log {
+    level = "info"
+  }`)
+	assert.Nil(t, a.initLog())
+
+	var changeCnt int
+	a.OnLogConfigChange(func(e *Event) { changeCnt++ })
+
+	buf := new(bytes.Buffer)
+	assert.Equal(t, log.ErrLogReceiverIsNil, a.SetLogHandler(nil))
+	assert.Nil(t, a.SetLogHandler(slog.NewTextHandler(buf, nil)))
+	a.Log().WithField("user", "jeeva").Info("shared slog pipeline")
+	a.Slog().Debug("filtered by aah log level")
+	a.Slog().Warn("from slog", "attempt", 3)
+
+	out := buf.String()
+	assert.Contains(t, out, `level=INFO msg="shared slog pipeline"`)
+	assert.Contains(t, out, "user=jeeva")
+	assert.Contains(t, out, `level=WARN msg="from slog" app_name=`)
+	assert.Contains(t, out, "attempt=3")
+	assert.NotContains(t, out, "filtered by aah log level")
+	assert.Equal(t, 1, changeCnt)
+}
@@ -287,6 +287,117 @@ func TestHotAppReload(t *testing.T) {
 	ts.app.performHotReload()
 }
 
+func TestAppConfigEnvOverrides(t *testing.T) {
+	os.Setenv("AAH_SERVER_HEADER", "env-server")
+	os.Setenv("AAH_SERVER__PORT", "9090")
+	defer os.Unsetenv("AAH_SERVER_HEADER")
+	defer os.Unsetenv("AAH_SERVER__PORT")
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	assert.Equal(t, "env-server", a.settings.ServerHeader)
+	assert.Equal(t, "9090", a.HTTPPort())
+	assert.Equal(t, "env:AAH_SERVER_HEADER", a.Config().Sources()["env.dev.server.header"])
+	assert.True(t, strings.HasSuffix(a.Config().Sources()["name"], "aah.conf"))
+
+	// disabled
+	a.Config().SetBool("env.override.enable", false)
+	overridden, err := a.overrideConfigFromEnv()
+	assert.Nil(t, err)
+	assert.Nil(t, overridden)
+
+	// invalid value
+	os.Setenv("AAH_SERVER_WEBSOCKET_ENABLE", "yes-please")
+	defer os.Unsetenv("AAH_SERVER_WEBSOCKET_ENABLE")
+	a.Config().SetBool("env.override.enable", true)
+	overridden, err = a.overrideConfigFromEnv()
+	assert.Nil(t, overridden)
+	assert.Equal(t, "config: invalid value of env 'AAH_SERVER_WEBSOCKET_ENABLE' for 'server.websocket.enable': invalid syntax", err.Error())
+}
+
+func TestAppProfileConfigFiles(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	assert.False(t, a.Config().BoolDefault("staging_overlay", false))
+
+	a.Config().SetString("env.active", "staging")
+	err := a.refreshSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", a.EnvProfile())
+	assert.True(t, a.Config().BoolDefault("staging_overlay", false))
+	assert.Equal(t, "9090", a.HTTPPort())
+	assert.Equal(t, "webapp1 staging routes", a.Config().StringDefault("routes.domains.localhost.name", ""))
+
+	// other profile is not affected
+	a.Config().SetString("env.active", "dev")
+	err = a.refreshSettings()
+	assert.Nil(t, err)
+	assert.False(t, a.Config().BoolDefault("staging_overlay", false))
+}
+
+func TestAppConfigSchema(t *testing.T) {
+	cfg, err := config.ParseString(`
+server {
+  ssl {
+    enabel = true
+  }
+}
+log {
+  receiver = "FILE"
+  level = "verbose"
+}
+`)
+	assert.Nil(t, err)
+	_, err = frameworkConfigSchema().Validate(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{
+		"'log.level' value 'verbose' is not one of [fatal, panic, error, warn, info, debug, trace]",
+		"'server.ssl.enabel' unknown key, did you mean 'server.ssl.enable'?",
+	}, err.(*config.SchemaError).Issues)
+}
+
+type testConfigProvider struct {
+	content string
+}
+
+func (p *testConfigProvider) Load() (*config.Config, error) {
+	return config.ParseString(p.content)
+}
+
+func (p *testConfigProvider) Watch(stop <-chan struct{}, onChange func()) error {
+	onChange()
+	<-stop
+	return nil
+}
+
+func TestAppConfigProvider(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	p := &testConfigProvider{content: "server {\n  header = \"remote-server\"\n}\n"}
+	a.SetConfigProvider(p)
+	assert.Nil(t, a.initConfig())
+	assert.Equal(t, "remote-server", a.Config().StringDefault("server.header", ""))
+	assert.Equal(t, "webapp1", a.Config().StringDefault("name", ""))
+
+	reloaded := make(chan bool, 1)
+	a.OnConfigHotReload(func(e *Event) { reloaded <- true })
+	p.content = "server {\n  header = \"remote-server-v2\"\n}\n"
+	stop := make(chan struct{})
+	go a.watchConfigProvider(stop)
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting config hot-reload")
+	}
+	close(stop)
+	assert.Equal(t, "remote-server-v2", a.Config().StringDefault("server.header", ""))
+
+	p.content = "server {"
+	assert.NotNil(t, a.initConfig())
+}
+
 func TestLogInitRelativeFilePath(t *testing.T) {
 	logPath := filepath.Join(testdataBaseDir(), "sample-test-app.log")
 	defer ess.DeleteFiles(logPath)
@@ -327,6 +438,49 @@ func TestLogInitNoFilePath(t *testing.T) {
 	})
 }
 
+func TestSetLogLevelAndReceiver(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "runtime-switch.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(`log {
+    level = "info"
+  }`)
+	a.cfg = cfg
+	err := a.initLog()
+	assert.Nil(t, err)
+
+	var changeCnt int
+	a.OnLogConfigChange(func(e *Event) {
+		changeCnt++
+		assert.NotNil(t, e.Data)
+	})
+
+	child := a.Log().(*log.Logger).New(log.Fields{"module": "test"})
+	assert.False(t, child.IsLevelDebug())
+
+	err = a.SetLogLevel("debug")
+	assert.Nil(t, err)
+	assert.True(t, a.Log().IsLevelDebug())
+	assert.True(t, child.IsLevelDebug())
+	assert.Equal(t, "debug", a.Config().StringDefault("log.level", ""))
+
+	err = a.SetLogLevel("unknown")
+	assert.Equal(t, "log: unknown log level 'unknown'", err.Error())
+
+	err = a.SetLogReceiver("file", map[string]interface{}{"file": logPath})
+	assert.Nil(t, err)
+	child.Info("written into file receiver")
+	assert.True(t, ess.IsFileExists(logPath))
+
+	err = a.SetLogReceiver("file", map[string]interface{}{"file": []string{logPath}})
+	assert.Equal(t, "aah: unsupported log receiver option value type '[]string' for key 'file'", err.Error())
+
+	err = a.SetLogReceiver("smtp", nil)
+	assert.Equal(t, "log: unknown receiver 'smtp'", err.Error())
+	assert.Equal(t, 2, changeCnt)
+}
+
 func TestAccessLogInitAbsPath(t *testing.T) {
 	logPath := filepath.Join(testdataBaseDir(), "sample-test-access.log")
 	defer ess.DeleteFiles(logPath)
@@ -343,6 +497,47 @@ func TestAccessLogInitAbsPath(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAccessLogRotateConfig(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "sample-test-rotate-access.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    rotate {
+      policy = "daily"
+      max_backups = 7
+    }
+  }
+  server {
+    access_log {
+      file = "%s"
+      rotate {
+        policy = "size"
+        max_size = "10mb"
+        max_backups = 14
+        compress = true
+      }
+    }
+  }`, filepath.ToSlash(logPath)))
+	a.cfg = cfg
+
+	lcfg := config.NewEmpty()
+	err := a.mergeLogRotateConfig(lcfg, "server.access_log.rotate")
+	assert.Nil(t, err)
+	assert.Equal(t, "size", lcfg.StringDefault("log.rotate.policy", ""))
+	assert.Equal(t, 14, lcfg.IntDefault("log.rotate.max_backups", 0))
+	assert.True(t, lcfg.BoolDefault("log.rotate.compress", false))
+
+	// fallback to app log rotate config
+	lcfg = config.NewEmpty()
+	err = a.mergeLogRotateConfig(lcfg, "server.dump_log.rotate")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, lcfg.IntDefault("log.rotate.max_backups", 0))
+
+	err = a.initAccessLog()
+	assert.Nil(t, err)
+}
+
 type testErrorController1 struct {
 }
 
@@ -479,6 +674,7 @@ func (ts *testServer) manualInit() {
 		BindMiddleware,
 		AntiCSRFMiddleware,
 		AuthcAuthzMiddleware,
+		IdempotencyMiddleware,
 		ActionMiddleware,
 	)
 
@@ -505,6 +701,12 @@ func (ts *testServer) manualInit() {
 				{Name: "info", Type: reflect.TypeOf((**sampleJSON)(nil))},
 			},
 		},
+		{
+			Name: "GitHubWebhook",
+			Parameters: []*ainsp.Parameter{
+				{Name: "info", Type: reflect.TypeOf((**sampleJSON)(nil))},
+			},
+		},
 		{Name: "XML"},
 		{
 			Name: "JSONP",
@@ -520,7 +722,7 @@ func (ts *testServer) manualInit() {
 	})
 
 	// reset controller namespace and key
-	cregistry := &ainsp.TargetRegistry{Registry: make(map[string]*ainsp.Target), SearchType: ctxPtrType}
+	cregistry := &ainsp.TargetRegistry{Registry: make(map[string]*ainsp.Target), SearchType: ctxPtrType, InjectTag: injectTag}
 	for k, v := range ts.app.he.registry.Registry {
 		v.Namespace = ""
 		cregistry.Registry[path.Base(k)] = v
@@ -595,6 +797,14 @@ func (s *testSiteController) CreateRecord(info *sampleJSON) {
 	})
 }
 
+func (s *testSiteController) GitHubWebhook(info *sampleJSON) {
+	if err := s.Req.VerifyWebhook(s.a.WebhookProvider("github")); err != nil {
+		s.Reply().Unauthorized().Text(err.Error())
+		return
+	}
+	s.Reply().JSON(Data{"data": info})
+}
+
 func (s *testSiteController) XML() {
 	s.Reply().XML(Data{
 		"message": "This is XML payload result",
@@ -721,3 +931,47 @@ func responseBody(res *http.Response) string {
 	io.Copy(buf, body)
 	return buf.String()
 }
+
+func TestModuleLogLevels(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`log {
+    level = "info"
+    levels {
+      router = "warn"
+      security = "trace"
+      app = "error"
+    }
+  }`)
+	assert.Nil(t, a.initLog())
+	assert.True(t, a.Log().IsLevelError())
+	assert.True(t, a.ModuleLog("router").IsLevelWarn())
+	assert.True(t, a.ModuleLog("security").IsLevelTrace())
+	assert.Equal(t, a.Log(), a.ModuleLog("static"))
+	assert.True(t, (&moduleApp{Application: a, name: "router"}).Log().IsLevelWarn())
+
+	// app level change does not affect module level
+	assert.Nil(t, a.SetLogLevel("debug"))
+	assert.True(t, a.ModuleLog("router").IsLevelWarn())
+
+	buf := new(bytes.Buffer)
+	a.Log().(*log.Logger).SetWriter(buf)
+	assert.Nil(t, a.Log().(*log.Logger).SetPattern("%level %reqid %message %fields"))
+	ctx := newContext(nil, httptest.NewRequest(ahttp.MethodGet, "http://localhost/", nil))
+	ctx.a = a
+	ctx.Req.Header.Set(a.settings.RequestIDHeaderKey, "req-1")
+	ctx.moduleLog("router").Info("filtered by router module level")
+	ctx.moduleLog("router").Warn("route not found")
+	ctx.moduleLog("static").Debug("static resource")
+	out := buf.String()
+	assert.NotContains(t, out, "filtered by router module level")
+	assert.Contains(t, out, "route not found")
+	assert.Contains(t, out, "req-1 route not found fields[module: router]")
+	assert.Contains(t, out, "static resource")
+
+	a.cfg, _ = config.ParseString(`log {
+    levels {
+      router = "verbose"
+    }
+  }`)
+	assert.Equal(t, "log: unknown log level 'verbose', module 'router'", a.initLog().Error())
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package aahtest is the official test kit for aah application. It provides
+// test server, fluent request builder, response assertions, test context for
+// unit testing controller actions without running the server and subject,
+// session fakes.
+//
+// 	func TestUserShow(t *testing.T) {
+// 	  ts := aahtest.NewTestServer(t, aahtest.NewTestApp(t, "github.com/user/app", func(app *aah.Application) {
+// 	    app.AddController((*controllers.UserController)(nil), userMethods)
+// 	  }))
+// 	  defer ts.Close()
+//
+// 	  ts.Get("/users/1").Header("Accept", "application/json").Do().
+// 	    AssertStatus(http.StatusOK).
+// 	    AssertJSON(map[string]interface{}{"id": 1, "name": "Jeeva"})
+// 	}
+package aahtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work"
+	"aahframe.work/log"
+)
+
+// NewTestApp method creates and initializes a new aah application for the
+// given import path or physical path without starting the server. Setup funcs
+// are called prior to application initialize, it's the place to add
+// controllers, middlewares, event subscribers, etc. Application log is
+// discarded, use `app.Log().(*log.Logger).SetWriter(os.Stdout)` to see them.
+func NewTestApp(t testing.TB, importPath string, setup ...func(*aah.Application)) *aah.Application {
+	t.Helper()
+	app := aah.NewApp()
+	for _, fn := range setup {
+		fn(app)
+	}
+	if err := app.InitForTest(importPath); err != nil {
+		t.Fatalf("aahtest: app init failure: %v", err)
+	}
+	if l, ok := app.Log().(*log.Logger); ok {
+		l.SetWriter(ioutil.Discard)
+	}
+	return app
+}
+
+// NewTestServer method starts the test server for the given aah application.
+// Caller should call `Close` when finished, to shut it down.
+func NewTestServer(t testing.TB, app *aah.Application) *TestServer {
+	ts := &TestServer{t: t, app: app, server: httptest.NewServer(app)}
+	ts.URL = ts.server.URL
+	return ts
+}
+
+// TestServer provides capabilities to test aah application end-to-end over
+// real HTTP server.
+type TestServer struct {
+	URL    string
+	t      testing.TB
+	app    *aah.Application
+	server *httptest.Server
+}
+
+// App method returns the aah application instance of test server.
+func (ts *TestServer) App() *aah.Application {
+	return ts.app
+}
+
+// Client method returns the HTTP client configured for test server. By
+// default redirects are not followed.
+func (ts *TestServer) Client() *http.Client {
+	c := ts.server.Client()
+	c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return c
+}
+
+// Close method shuts down the test server.
+func (ts *TestServer) Close() {
+	ts.server.Close()
+}
+
+// NewRequest method returns the request builder for the given HTTP method
+// and path.
+func (ts *TestServer) NewRequest(method, path string) *RequestBuilder {
+	return newRequestBuilder(ts.t, ts.app, method, ts.URL+path).server(ts)
+}
+
+// Get method returns the request builder for HTTP GET method.
+func (ts *TestServer) Get(path string) *RequestBuilder {
+	return ts.NewRequest(http.MethodGet, path)
+}
+
+// Post method returns the request builder for HTTP POST method.
+func (ts *TestServer) Post(path string) *RequestBuilder {
+	return ts.NewRequest(http.MethodPost, path)
+}
+
+// Put method returns the request builder for HTTP PUT method.
+func (ts *TestServer) Put(path string) *RequestBuilder {
+	return ts.NewRequest(http.MethodPut, path)
+}
+
+// Patch method returns the request builder for HTTP PATCH method.
+func (ts *TestServer) Patch(path string) *RequestBuilder {
+	return ts.NewRequest(http.MethodPatch, path)
+}
+
+// Delete method returns the request builder for HTTP DELETE method.
+func (ts *TestServer) Delete(path string) *RequestBuilder {
+	return ts.NewRequest(http.MethodDelete, path)
+}
+
+// Route method returns the request builder for the given route name of root
+// domain, HTTP method is from route definition and path is composed with
+// given args. See `router.Domain.RouteURL`.
+//
+// 	ts.Route("show_user", 1).Do().AssertStatus(http.StatusOK)
+func (ts *TestServer) Route(routeName string, args ...interface{}) *RequestBuilder {
+	ts.t.Helper()
+	method, path := routePath(ts.t, ts.app, routeName, args...)
+	return ts.NewRequest(method, path)
+}
+
+func routePath(t testing.TB, app *aah.Application, routeName string, args ...interface{}) (string, string) {
+	t.Helper()
+	domain := app.Router().RootDomain()
+	if domain == nil {
+		t.Fatalf("aahtest: root domain not found")
+		return "", ""
+	}
+	route := domain.LookupByName(routeName)
+	if route == nil {
+		t.Fatalf("aahtest: route '%s' not found", routeName)
+		return "", ""
+	}
+	return route.Method, domain.RouteURL(routeName, args...)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aahtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"aahframe.work"
+	"aahframe.work/ainsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestServer(t *testing.T) {
+	app := newTestApp(t)
+	ts := NewTestServer(t, app)
+	defer ts.Close()
+	assert.Equal(t, app, ts.App())
+
+	ts.Get("/users/10").Accept("application/json").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON(map[string]interface{}{"id": 10, "name": "user10"})
+
+	ts.Route("user_create").JSON(map[string]interface{}{"name": "jeeva"}).Do().
+		AssertStatus(http.StatusCreated).
+		AssertContains(`"jeeva"`)
+
+	ts.Post("/users/form").Form(url.Values{"name": []string{"form user"}}).Do().
+		AssertStatus(http.StatusOK).
+		AssertContains("form user")
+
+	ts.Get("/users/search").Query("q", "aah").Do().
+		AssertStatus(http.StatusOK).
+		AssertContentType("text/plain").
+		AssertContains("search: aah").
+		AssertNotContains("aah framework")
+
+	ts.Get("/users/go-home").Do().AssertRedirect("/")
+
+	ts.Get("/not-exists").Do().AssertStatus(http.StatusNotFound)
+
+	// subject session
+	ts.Get("/users/me").Do().AssertStatus(http.StatusOK).AssertContains("anonymous")
+	ts.Get("/users/me").Session(NewSubject(app, "jeeva").Session).Do().
+		AssertStatus(http.StatusOK).AssertContains("jeeva")
+}
+
+func TestTestContext(t *testing.T) {
+	app := newTestApp(t)
+
+	tc := NewRequest(t, app, http.MethodGet, "/users/20").Context()
+	c := &userController{Context: tc.Context}
+	c.Show(20)
+	res := tc.Result()
+	res.AssertStatus(http.StatusOK).AssertJSON(map[string]interface{}{"id": 20, "name": "user20"})
+
+	var u user
+	assert.Nil(t, res.DecodeJSON(&u))
+	assert.Equal(t, "user20", u.Name)
+
+	tc = Route(t, app, "user_me").Context()
+	tc.SetSubject(NewSubject(app, "jeeva", "admin"))
+	assert.True(t, tc.Subject().HasRole("admin"))
+	c = &userController{Context: tc.Context}
+	c.Me()
+	tc.Result().AssertContains("jeeva")
+
+	// without server
+	Route(t, app, "user_show", 30).Accept("application/json").Do().
+		AssertStatus(http.StatusOK).
+		AssertHeader("Content-Type", "application/json; charset=utf-8")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Test app
+//______________________________________________________________________________
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type userController struct {
+	*aah.Context
+}
+
+func (c *userController) Show(id int) {
+	c.Reply().JSON(&user{ID: id, Name: "user" + c.Req.PathValue("id")})
+}
+
+func (c *userController) Create(u *user) {
+	c.Reply().Created().JSON(u)
+}
+
+func (c *userController) FormSubmit() {
+	c.Reply().Text("name: %s", c.Req.FormValue("name"))
+}
+
+func (c *userController) Search(q string) {
+	c.Reply().Text("search: %s", q)
+}
+
+func (c *userController) GoHome() {
+	c.Reply().Redirect("/")
+}
+
+func (c *userController) Me() {
+	if c.Subject().IsAuthenticated() {
+		c.Reply().Text("hello %s", c.Subject().PrimaryPrincipal().Value)
+		return
+	}
+	c.Reply().Text("hello anonymous")
+}
+
+func newTestApp(t *testing.T) *aah.Application {
+	dir, err := ioutil.TempDir("", "aahtest")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	writeFile(t, filepath.Join(dir, "config", "aah.conf"), `
+name = "aahtest"
+type = "api"
+include "./security.conf"
+
+env {
+  dev {
+  }
+}
+`)
+	writeFile(t, filepath.Join(dir, "config", "security.conf"), `
+security {
+  session {
+    mode = "stateful"
+    sign_key = "eFWLXEewECptbDVXExokRTLONWxrTjfV"
+    enc_key = "KYqklJsgeclPpZutTeQKNOTWlpksRBwA"
+  }
+}
+`)
+	writeFile(t, filepath.Join(dir, "config", "routes.conf"), `
+domains {
+  localhost {
+    host = "localhost"
+    routes {
+      user_show {
+        path = "/users/:id"
+        controller = "userController"
+        action = "Show"
+      }
+      user_create {
+        path = "/users"
+        method = "POST"
+        controller = "userController"
+        action = "Create"
+      }
+      user_form {
+        path = "/users/form"
+        method = "POST"
+        controller = "userController"
+        action = "FormSubmit"
+      }
+      user_search {
+        path = "/users/search"
+        controller = "userController"
+        action = "Search"
+      }
+      user_go_home {
+        path = "/users/go-home"
+        controller = "userController"
+        action = "GoHome"
+      }
+      user_me {
+        path = "/users/me"
+        controller = "userController"
+        action = "Me"
+      }
+    }
+  }
+}
+`)
+
+	return NewTestApp(t, dir, func(app *aah.Application) {
+		app.AddController((*userController)(nil), []*ainsp.Method{
+			{Name: "Show", Parameters: []*ainsp.Parameter{{Name: "id", Type: reflect.TypeOf((*int)(nil))}}},
+			{Name: "Create", Parameters: []*ainsp.Parameter{{Name: "u", Type: reflect.TypeOf((**user)(nil))}}},
+			{Name: "FormSubmit"},
+			{Name: "Search", Parameters: []*ainsp.Parameter{{Name: "q", Type: reflect.TypeOf((*string)(nil))}}},
+			{Name: "GoHome"},
+			{Name: "Me"},
+		})
+	})
+}
+
+func writeFile(t *testing.T, name, content string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(name), 0755))
+	assert.Nil(t, ioutil.WriteFile(name, []byte(content), 0644))
+}
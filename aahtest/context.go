@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aahtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work"
+	"aahframe.work/security"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+	"aahframe.work/security/session"
+)
+
+// TestContext is used to unit test the controller actions without running
+// the server. It embeds `aah.Context`, so it could be set into controller
+// directly and response is recorded via `httptest.ResponseRecorder`.
+//
+// 	tc := aahtest.NewRequest(t, app, http.MethodGet, "/users/1").Context()
+// 	c := &controllers.UserController{Context: tc.Context}
+// 	c.Show(1)
+// 	tc.Result().AssertStatus(http.StatusOK).AssertJSON(expected)
+type TestContext struct {
+	*aah.Context
+	Recorder *httptest.ResponseRecorder
+	t        testing.TB
+	app      *aah.Application
+}
+
+// NewTestContext method creates the test context for the given request.
+// Domain and route are resolved from request host and path if exists.
+func NewTestContext(t testing.TB, app *aah.Application, r *http.Request) *TestContext {
+	w := httptest.NewRecorder()
+	return &TestContext{
+		Context:  app.HTTPEngine().NewContext(w, r),
+		Recorder: w,
+		t:        t,
+		app:      app,
+	}
+}
+
+// Context method returns the test context for the composed request.
+func (rb *RequestBuilder) Context() *TestContext {
+	rb.t.Helper()
+	return NewTestContext(rb.t, rb.app, rb.Build())
+}
+
+// SetSubject method sets the given subject as current request subject.
+func (tc *TestContext) SetSubject(s *security.Subject) *TestContext {
+	sub := tc.Subject()
+	sub.AuthenticationInfo = s.AuthenticationInfo
+	sub.AuthorizationInfo = s.AuthorizationInfo
+	sub.Session = s.Session
+	return tc
+}
+
+// Result method writes the context reply into recorder and returns the
+// response for assertions.
+func (tc *TestContext) Result() *Response {
+	tc.t.Helper()
+	tc.app.HTTPEngine().WriteReply(tc.Context)
+	return newResponse(tc.t, tc.Recorder.Result())
+}
+
+// NewSubject method creates an authenticated subject fake with given username
+// as primary principal and roles, permissions could be added via
+// `AuthorizationInfo`. Subject session holds the authentication info, so it
+// could be used with `RequestBuilder.Session` on stateful session store.
+func NewSubject(app *aah.Application, username string, roles ...string) *security.Subject {
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals, &authc.Principal{
+		Realm:     "aahtest",
+		Claim:     "Username",
+		Value:     username,
+		IsPrimary: true,
+	})
+
+	s := NewSession(app)
+	s.IsAuthenticated = true
+	s.Set(aah.KeyViewArgAuthcInfo, authcInfo)
+
+	return &security.Subject{
+		AuthenticationInfo: authcInfo,
+		AuthorizationInfo:  authz.NewAuthorizationInfo().AddRole(roles...),
+		Session:            s,
+	}
+}
+
+// NewSession method creates a new session from application session manager.
+func NewSession(app *aah.Application) *session.Session {
+	return app.SessionManager().NewSession()
+}
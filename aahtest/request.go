@@ -0,0 +1,173 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aahtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"aahframe.work"
+	"aahframe.work/ahttp"
+	"aahframe.work/security/session"
+)
+
+// RequestBuilder is fluent HTTP request builder for test server and test
+// context.
+type RequestBuilder struct {
+	t       testing.TB
+	app     *aah.Application
+	ts      *TestServer
+	method  string
+	url     string
+	header  http.Header
+	query   url.Values
+	cookies []*http.Cookie
+	body    io.Reader
+	err     error
+}
+
+func newRequestBuilder(t testing.TB, app *aah.Application, method, target string) *RequestBuilder {
+	return &RequestBuilder{
+		t:      t,
+		app:    app,
+		method: method,
+		url:    target,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+}
+
+// Header method sets the request header for the given key and value.
+func (rb *RequestBuilder) Header(key, value string) *RequestBuilder {
+	rb.header.Set(key, value)
+	return rb
+}
+
+// Accept method sets the request header `Accept`.
+func (rb *RequestBuilder) Accept(contentType string) *RequestBuilder {
+	return rb.Header(ahttp.HeaderAccept, contentType)
+}
+
+// Query method adds the given key and value into request query string.
+func (rb *RequestBuilder) Query(key, value string) *RequestBuilder {
+	rb.query.Add(key, value)
+	return rb
+}
+
+// Cookie method adds the given cookie into request.
+func (rb *RequestBuilder) Cookie(c *http.Cookie) *RequestBuilder {
+	rb.cookies = append(rb.cookies, c)
+	return rb
+}
+
+// Body method sets the request body with given content type.
+func (rb *RequestBuilder) Body(contentType string, body io.Reader) *RequestBuilder {
+	rb.body = body
+	return rb.Header(ahttp.HeaderContentType, contentType)
+}
+
+// JSON method sets the request body as JSON of given value.
+func (rb *RequestBuilder) JSON(v interface{}) *RequestBuilder {
+	b, err := json.Marshal(v)
+	if err != nil {
+		rb.err = err
+		return rb
+	}
+	return rb.Body(ahttp.ContentTypeJSON.String(), bytes.NewReader(b))
+}
+
+// Form method sets the request body as URL encoded form of given values.
+func (rb *RequestBuilder) Form(values url.Values) *RequestBuilder {
+	return rb.Body(ahttp.ContentTypeForm.String(), strings.NewReader(values.Encode()))
+}
+
+// Session method adds the session cookie of the given session into request.
+// Session store have to be stateful, see `NewSubject` for authenticated
+// session.
+func (rb *RequestBuilder) Session(s *session.Session) *RequestBuilder {
+	w := httptest.NewRecorder()
+	if err := rb.app.SessionManager().SaveSession(w, s); err != nil {
+		rb.err = err
+		return rb
+	}
+	for _, c := range w.Result().Cookies() {
+		rb.Cookie(c)
+	}
+	return rb
+}
+
+// Build method returns the composed HTTP request.
+func (rb *RequestBuilder) Build() *http.Request {
+	rb.t.Helper()
+	if rb.err != nil {
+		rb.t.Fatalf("aahtest: request build: %v", rb.err)
+	}
+
+	r, err := http.NewRequest(rb.method, rb.url, rb.body)
+	if err != nil {
+		rb.t.Fatalf("aahtest: request build: %v", err)
+	}
+	if len(rb.query) > 0 {
+		q := r.URL.Query()
+		for k, v := range rb.query {
+			q[k] = append(q[k], v...)
+		}
+		r.URL.RawQuery = q.Encode()
+	}
+	for k, v := range rb.header {
+		r.Header[k] = v
+	}
+	for _, c := range rb.cookies {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+// Do method sends the request to test server and returns the response. Without
+// test server, request is served via `httptest.ResponseRecorder`.
+func (rb *RequestBuilder) Do() *Response {
+	rb.t.Helper()
+	r := rb.Build()
+	if rb.ts == nil {
+		w := httptest.NewRecorder()
+		rb.app.ServeHTTP(w, r)
+		return newResponse(rb.t, w.Result())
+	}
+
+	res, err := rb.ts.Client().Do(r)
+	if err != nil {
+		rb.t.Fatalf("aahtest: request: %v", err)
+	}
+	return newResponse(rb.t, res)
+}
+
+func (rb *RequestBuilder) server(ts *TestServer) *RequestBuilder {
+	rb.ts = ts
+	return rb
+}
+
+// NewRequest method returns the request builder for the given HTTP method
+// and path, request is served via `httptest.ResponseRecorder` without
+// running the server.
+//
+// 	aahtest.NewRequest(t, app, http.MethodGet, "/users/1").Do().AssertStatus(http.StatusOK)
+func NewRequest(t testing.TB, app *aah.Application, method, path string) *RequestBuilder {
+	return newRequestBuilder(t, app, method, path)
+}
+
+// Route method returns the request builder for the given route name of root
+// domain, request is served via `httptest.ResponseRecorder` without running
+// the server. See `TestServer.Route`.
+func Route(t testing.TB, app *aah.Application, routeName string, args ...interface{}) *RequestBuilder {
+	t.Helper()
+	method, path := routePath(t, app, routeName, args...)
+	return newRequestBuilder(t, app, method, path)
+}
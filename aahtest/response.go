@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aahtest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+)
+
+// Response holds the HTTP response and its body, provides fluent assertion
+// methods. Failed assertion reports error via `testing.TB` and continues.
+type Response struct {
+	*http.Response
+	Body []byte
+	t    testing.TB
+}
+
+func newResponse(t testing.TB, res *http.Response) *Response {
+	t.Helper()
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("aahtest: response read: %v", err)
+	}
+	return &Response{Response: res, Body: body, t: t}
+}
+
+// String method returns the response body as string.
+func (r *Response) String() string {
+	return string(r.Body)
+}
+
+// DecodeJSON method decodes the response body into given value.
+func (r *Response) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// AssertStatus method asserts the response status code.
+func (r *Response) AssertStatus(code int) *Response {
+	r.t.Helper()
+	if r.StatusCode != code {
+		r.t.Errorf("aahtest: expected status %d, got %d", code, r.StatusCode)
+	}
+	return r
+}
+
+// AssertHeader method asserts the response header value.
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header.Get(key); got != value {
+		r.t.Errorf("aahtest: expected header '%s' value '%s', got '%s'", key, value, got)
+	}
+	return r
+}
+
+// AssertContentType method asserts the response content type, parameters
+// are ignored. For e.g.: `application/json`.
+func (r *Response) AssertContentType(mime string) *Response {
+	r.t.Helper()
+	ct := r.Header.Get(ahttp.HeaderContentType)
+	if idx := strings.IndexByte(ct, ';'); idx > 0 {
+		ct = ct[:idx]
+	}
+	if !strings.EqualFold(strings.TrimSpace(ct), mime) {
+		r.t.Errorf("aahtest: expected content type '%s', got '%s'", mime, ct)
+	}
+	return r
+}
+
+// AssertContains method asserts the response body contains all the given
+// values.
+func (r *Response) AssertContains(values ...string) *Response {
+	r.t.Helper()
+	for _, v := range values {
+		if !strings.Contains(r.String(), v) {
+			r.t.Errorf("aahtest: expected response body to contain '%s'", v)
+		}
+	}
+	return r
+}
+
+// AssertNotContains method asserts the response body does not contain any of
+// the given values.
+func (r *Response) AssertNotContains(values ...string) *Response {
+	r.t.Helper()
+	for _, v := range values {
+		if strings.Contains(r.String(), v) {
+			r.t.Errorf("aahtest: expected response body not to contain '%s'", v)
+		}
+	}
+	return r
+}
+
+// AssertHTML method asserts the response is HTML and body contains all the
+// given values.
+func (r *Response) AssertHTML(values ...string) *Response {
+	r.t.Helper()
+	return r.AssertContentType(ahttp.ContentTypeHTML.Mime).AssertContains(values...)
+}
+
+// AssertJSON method asserts the response is JSON and body is equal to the
+// given value, comparison is done on JSON decoded form so field order and
+// formatting does not matter.
+func (r *Response) AssertJSON(expected interface{}) *Response {
+	r.t.Helper()
+	r.AssertContentType(ahttp.ContentTypeJSON.Mime)
+
+	var got, want interface{}
+	if err := json.Unmarshal(r.Body, &got); err != nil {
+		r.t.Errorf("aahtest: invalid JSON response: %v", err)
+		return r
+	}
+	b, err := json.Marshal(expected)
+	if err != nil {
+		r.t.Errorf("aahtest: invalid expected JSON value: %v", err)
+		return r
+	}
+	_ = json.Unmarshal(b, &want)
+	if !reflect.DeepEqual(got, want) {
+		r.t.Errorf("aahtest: expected JSON %s, got %s", b, r.Body)
+	}
+	return r
+}
+
+// AssertRedirect method asserts the response is redirect to given location.
+func (r *Response) AssertRedirect(location string) *Response {
+	r.t.Helper()
+	if r.StatusCode < http.StatusMultipleChoices || r.StatusCode > http.StatusPermanentRedirect {
+		r.t.Errorf("aahtest: expected redirect status, got %d", r.StatusCode)
+	}
+	return r.AssertHeader(ahttp.HeaderLocation, location)
+}
+
+// Cookie method returns the response cookie for the given name otherwise nil.
+func (r *Response) Cookie(name string) *http.Cookie {
+	for _, c := range r.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
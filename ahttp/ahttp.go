@@ -86,6 +86,20 @@ func ReleaseResponseWriter(aw ResponseWriter) {
 	}
 }
 
+// AcquireURLParams method gets the URL params from pool with zero length,
+// backing array is reused across the acquisitions.
+func AcquireURLParams() *URLParams {
+	return urlParamsPool.Get().(*URLParams)
+}
+
+// ReleaseURLParams method resets the URL params and puts back to pool.
+func ReleaseURLParams(p *URLParams) {
+	if p != nil {
+		*p = (*p)[:0]
+		urlParamsPool.Put(p)
+	}
+}
+
 // WrapGzipWriter wraps `ahttp.ResponseWriter` with Gzip writer.
 func WrapGzipWriter(w io.Writer) ResponseWriter {
 	gr := grPool.Get().(*GzipResponse)
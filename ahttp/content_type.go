@@ -45,6 +45,27 @@ var (
 
 	// ContentTypeCSSText content type for stylesheets/CSS.
 	ContentTypeCSSText = parseMediaType("text/css; charset=utf-8")
+
+	// ContentTypeCSV content type for comma separated values.
+	ContentTypeCSV = parseMediaType("text/csv; charset=utf-8")
+
+	// ContentTypeXlsx content type for Office Open XML spreadsheet.
+	ContentTypeXlsx = parseMediaType("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	// ContentTypeProblemJSON content type for problem details RFC 7807.
+	ContentTypeProblemJSON = parseMediaType("application/problem+json; charset=utf-8")
+
+	// ContentTypeJSONPatch content type for JSON Patch RFC 6902.
+	ContentTypeJSONPatch = parseMediaType("application/json-patch+json; charset=utf-8")
+
+	// ContentTypeMergePatch content type for JSON Merge Patch RFC 7396.
+	ContentTypeMergePatch = parseMediaType("application/merge-patch+json; charset=utf-8")
+
+	// ContentTypeRSS content type for RSS 2.0 feed.
+	ContentTypeRSS = parseMediaType("application/rss+xml; charset=utf-8")
+
+	// ContentTypeAtom content type for Atom feed RFC 4287.
+	ContentTypeAtom = parseMediaType("application/atom+xml; charset=utf-8")
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -53,10 +74,11 @@ var (
 
 // ContentType is represents request and response content type values
 type ContentType struct {
-	Mime   string
-	raw    string
-	Exts   []string
-	Params map[string]string
+	Mime       string
+	raw        string
+	vendorMime string
+	Exts       []string
+	Params     map[string]string
 }
 
 // IsEqual method returns true if its equals to current content-type instance
@@ -102,6 +124,16 @@ func (c *ContentType) Vendor() string {
 	return c.GetParam("vendor")
 }
 
+// VendorMime method returns Accept header vendor media type as-is if present
+// otherwise empty string
+// 	For e.g.:
+// 		Accept: application/vnd.myapp.v2+json
+//
+// 		Method returns `application/vnd.myapp.v2+json`
+func (c *ContentType) VendorMime() string {
+	return c.vendorMime
+}
+
 // GetParam method returns the media type paramater of Accept Content-Type header
 // otherwise returns empty string
 // value.
@@ -20,76 +20,89 @@ const vendorTreePrefix = "vnd."
 
 // HTTP Header names
 const (
-	HeaderAccept                          = "Accept"
-	HeaderAcceptEncoding                  = "Accept-Encoding"
-	HeaderAcceptLanguage                  = "Accept-Language"
-	HeaderAcceptRanges                    = "Accept-Ranges"
-	HeaderAccessControlAllowCredentials   = "Access-Control-Allow-Credentials"
-	HeaderAccessControlAllowHeaders       = "Access-Control-Allow-Headers"
-	HeaderAccessControlAllowMethods       = "Access-Control-Allow-Methods"
-	HeaderAccessControlAllowOrigin        = "Access-Control-Allow-Origin"
-	HeaderAccessControlExposeHeaders      = "Access-Control-Expose-Headers"
-	HeaderAccessControlMaxAge             = "Access-Control-Max-Age"
-	HeaderAccessControlRequestHeaders     = "Access-Control-Request-Headers"
-	HeaderAccessControlRequestMethod      = "Access-Control-Request-Method"
-	HeaderAge                             = "Age"
-	HeaderAllow                           = "Allow"
-	HeaderAuthorization                   = "Authorization"
-	HeaderCacheControl                    = "Cache-Control"
-	HeaderConnection                      = "Connection"
-	HeaderContentDisposition              = "Content-Disposition"
-	HeaderContentEncoding                 = "Content-Encoding"
-	HeaderContentLength                   = "Content-Length"
-	HeaderContentType                     = "Content-Type"
-	HeaderContentSecurityPolicy           = "Content-Security-Policy"
-	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
-	HeaderCookie                          = "Cookie"
-	HeaderDate                            = "Date"
-	HeaderETag                            = "Etag"
-	HeaderExpires                         = "Expires"
-	HeaderHost                            = "Host"
-	HeaderIfMatch                         = "If-Match"
-	HeaderIfModifiedSince                 = "If-Modified-Since"
-	HeaderIfNoneMatch                     = "If-None-Match"
-	HeaderIfRange                         = "If-Range"
-	HeaderIfUnmodifiedSince               = "If-Unmodified-Since"
-	HeaderKeepAlive                       = "Keep-Alive"
-	HeaderLastModified                    = "Last-Modified"
-	HeaderLocation                        = "Location"
-	HeaderOrigin                          = "Origin"
-	HeaderMethod                          = "Method"
-	HeaderPublicKeyPins                   = "Public-Key-Pins"
-	HeaderRange                           = "Range"
-	HeaderReferer                         = "Referer"
-	HeaderReferrerPolicy                  = "Referrer-Policy"
-	HeaderRetryAfter                      = "Retry-After"
-	HeaderServer                          = "Server"
-	HeaderSetCookie                       = "Set-Cookie"
-	HeaderStatus                          = "Status"
-	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
-	HeaderTransferEncoding                = "Transfer-Encoding"
-	HeaderUpgrade                         = "Upgrade"
-	HeaderUserAgent                       = "User-Agent"
-	HeaderVary                            = "Vary"
-	HeaderWWWAuthenticate                 = "Www-Authenticate"
-	HeaderXContentTypeOptions             = "X-Content-Type-Options"
-	HeaderXDNSPrefetchControl             = "X-Dns-Prefetch-Control"
-	HeaderXCSRFToken                      = "X-Csrf-Token"
-	HeaderXForwardedFor                   = "X-Forwarded-For"
-	HeaderXForwardedHost                  = "X-Forwarded-Host"
-	HeaderXForwardedPort                  = "X-Forwarded-Port"
-	HeaderXForwardedProto                 = "X-Forwarded-Proto"
-	HeaderXForwardedProtocol              = "X-Forwarded-Protocol"
-	HeaderXForwardedSsl                   = "X-Forwarded-Ssl"
-	HeaderXUrlScheme                      = "X-Url-Scheme"
-	HeaderXForwardedServer                = "X-Forwarded-Server"
-	HeaderXFrameOptions                   = "X-Frame-Options"
-	HeaderXHTTPMethodOverride             = "X-Http-Method-Override"
-	HeaderXPermittedCrossDomainPolicies   = "X-Permitted-Cross-Domain-Policies"
-	HeaderXRealIP                         = "X-Real-Ip"
-	HeaderXRequestedWith                  = "X-Requested-With"
-	HeaderXRequestID                      = "X-Request-Id"
-	HeaderXXSSProtection                  = "X-Xss-Protection"
+	HeaderAccept                             = "Accept"
+	HeaderAcceptEncoding                     = "Accept-Encoding"
+	HeaderAcceptLanguage                     = "Accept-Language"
+	HeaderAcceptRanges                       = "Accept-Ranges"
+	HeaderAccessControlAllowCredentials      = "Access-Control-Allow-Credentials"
+	HeaderAccessControlAllowHeaders          = "Access-Control-Allow-Headers"
+	HeaderAccessControlAllowMethods          = "Access-Control-Allow-Methods"
+	HeaderAccessControlAllowOrigin           = "Access-Control-Allow-Origin"
+	HeaderAccessControlAllowPrivateNetwork   = "Access-Control-Allow-Private-Network"
+	HeaderAccessControlExposeHeaders         = "Access-Control-Expose-Headers"
+	HeaderAccessControlMaxAge                = "Access-Control-Max-Age"
+	HeaderAccessControlRequestHeaders        = "Access-Control-Request-Headers"
+	HeaderAccessControlRequestMethod         = "Access-Control-Request-Method"
+	HeaderAccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
+	HeaderAge                                = "Age"
+	HeaderAllow                              = "Allow"
+	HeaderAuthorization                      = "Authorization"
+	HeaderCacheControl                       = "Cache-Control"
+	HeaderConnection                         = "Connection"
+	HeaderContentDisposition                 = "Content-Disposition"
+	HeaderContentEncoding                    = "Content-Encoding"
+	HeaderContentLength                      = "Content-Length"
+	HeaderContentType                        = "Content-Type"
+	HeaderContentSecurityPolicy              = "Content-Security-Policy"
+	HeaderContentSecurityPolicyReportOnly    = "Content-Security-Policy-Report-Only"
+	HeaderCookie                             = "Cookie"
+	HeaderDate                               = "Date"
+	HeaderETag                               = "Etag"
+	HeaderExpires                            = "Expires"
+	HeaderHost                               = "Host"
+	HeaderIdempotencyKey                     = "Idempotency-Key"
+	HeaderIdempotentReplayed                 = "Idempotent-Replayed"
+	HeaderIfMatch                            = "If-Match"
+	HeaderIfModifiedSince                    = "If-Modified-Since"
+	HeaderIfNoneMatch                        = "If-None-Match"
+	HeaderIfRange                            = "If-Range"
+	HeaderIfUnmodifiedSince                  = "If-Unmodified-Since"
+	HeaderKeepAlive                          = "Keep-Alive"
+	HeaderLastModified                       = "Last-Modified"
+	HeaderLink                               = "Link"
+	HeaderLocation                           = "Location"
+	HeaderOrigin                             = "Origin"
+	HeaderMethod                             = "Method"
+	HeaderPublicKeyPins                      = "Public-Key-Pins"
+	HeaderRange                              = "Range"
+	HeaderRateLimitLimit                     = "RateLimit-Limit"
+	HeaderRateLimitRemaining                 = "RateLimit-Remaining"
+	HeaderRateLimitReset                     = "RateLimit-Reset"
+	HeaderReferer                            = "Referer"
+	HeaderReferrerPolicy                     = "Referrer-Policy"
+	HeaderRetryAfter                         = "Retry-After"
+	HeaderServer                             = "Server"
+	HeaderServerTiming                       = "Server-Timing"
+	HeaderSetCookie                          = "Set-Cookie"
+	HeaderStatus                             = "Status"
+	HeaderStrictTransportSecurity            = "Strict-Transport-Security"
+	HeaderTransferEncoding                   = "Transfer-Encoding"
+	HeaderUpgrade                            = "Upgrade"
+	HeaderUserAgent                          = "User-Agent"
+	HeaderVary                               = "Vary"
+	HeaderWWWAuthenticate                    = "Www-Authenticate"
+	HeaderXContentTypeOptions                = "X-Content-Type-Options"
+	HeaderXDNSPrefetchControl                = "X-Dns-Prefetch-Control"
+	HeaderXCSRFToken                         = "X-Csrf-Token"
+	HeaderXForwardedFor                      = "X-Forwarded-For"
+	HeaderXForwardedHost                     = "X-Forwarded-Host"
+	HeaderXForwardedMethod                   = "X-Forwarded-Method"
+	HeaderXForwardedPort                     = "X-Forwarded-Port"
+	HeaderXForwardedProto                    = "X-Forwarded-Proto"
+	HeaderXForwardedProtocol                 = "X-Forwarded-Protocol"
+	HeaderXForwardedSsl                      = "X-Forwarded-Ssl"
+	HeaderXUrlScheme                         = "X-Url-Scheme"
+	HeaderXForwardedServer                   = "X-Forwarded-Server"
+	HeaderXForwardedURI                      = "X-Forwarded-Uri"
+	HeaderXFrameOptions                      = "X-Frame-Options"
+	HeaderXHTTPMethodOverride                = "X-Http-Method-Override"
+	HeaderXOriginalMethod                    = "X-Original-Method"
+	HeaderXOriginalURI                       = "X-Original-Uri"
+	HeaderXPermittedCrossDomainPolicies      = "X-Permitted-Cross-Domain-Policies"
+	HeaderXRealIP                            = "X-Real-Ip"
+	HeaderXRequestedWith                     = "X-Requested-With"
+	HeaderXRequestID                         = "X-Request-Id"
+	HeaderXXSSProtection                     = "X-Xss-Protection"
 )
 
 type (
@@ -132,22 +145,51 @@ func NegotiateContentType(req *http.Request) *ContentType {
 
 	// 3) Accept Header Vendor Types
 	// RFC4288 https://tools.ietf.org/html/rfc4288#section-3.2
-	if parts, yes := isVendorType(spec.Value); yes {
-		subparts := strings.Split(parts[1], "+")
-		if strings.Contains(subparts[0], "-v") {
-			verparts := strings.Split(subparts[0], "-v")
-			spec.Params["vendor"] = strings.TrimPrefix(verparts[0], vendorTreePrefix)
-			spec.Params["version"] = verparts[1]
-		} else {
-			spec.Params["vendor"] = strings.TrimPrefix(subparts[0], vendorTreePrefix)
+	vendorMime := parseVendorType(spec)
+
+	exts, _ := mime.ExtensionsByType(spec.Value)
+	ct := newContentType(spec.Value, exts, spec.Params)
+	ct.vendorMime = vendorMime
+	return ct
+}
+
+// Negotiate method negotiates the best offer from the given offers for HTTP
+// `Accept` header as per RFC7231 https://tools.ietf.org/html/rfc7231#section-5.3.2.
+// Accept header media ranges are honored with quality factor and
+// specificity; exact type, then `type/*` and `*/*`. In case of tie, offers
+// order is preferred. Vendor media types are matched with its structured
+// syntax suffix type too, for e.g.: `application/vnd.myapp.v2+json` matches
+// with offer `application/json`.
+//
+// It returns first offer if Accept header is not present, otherwise empty string
+// when none of the offers acceptable.
+func Negotiate(req *http.Request, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	specs := ParseAccept(req, HeaderAccept)
+	if len(specs) == 0 {
+		return offers[0]
+	}
+
+	bestOffer, bestQ, bestSpecificity := "", float32(0), -1
+	for _, offer := range offers {
+		// quality factor of offer is from the most specific matching media range
+		offerMime := strings.ToLower(offer)
+		q, specificity := float32(0), -1
+		for _, spec := range specs {
+			if sp := matchMediaRange(strings.ToLower(spec.Value), offerMime); sp > specificity {
+				q, specificity = spec.Q, sp
+			}
 		}
 
-		// Rewrite the Content-Type
-		spec.Value = parts[0] + "/" + subparts[1]
+		if q > bestQ || (q == bestQ && q > 0 && specificity > bestSpecificity) {
+			bestOffer, bestQ, bestSpecificity = offer, q, specificity
+		}
 	}
 
-	exts, _ := mime.ExtensionsByType(spec.Value)
-	return newContentType(spec.Value, exts, spec.Params)
+	return bestOffer
 }
 
 // NegotiateLocale method negotiates the `Accept-Language` from the given HTTP
@@ -317,12 +359,88 @@ func (specs AcceptSpecs) Less(i, j int) bool { return specs[i].Q > specs[j].Q }
 // Unexported methods
 //___________________________________
 
+// parseVendorType method parses the vendor and version info from the vendor
+// media type and rewrites the spec value into its suffix type. Supported
+// version formats are `-v2` and `.v2`.
+// 	For e.g.:
+// 		application/vnd.mycompany.myapp.customer-v2+json
+// 		application/vnd.myapp.v2+json
+//
+// It returns the original vendor media type otherwise empty string.
+func parseVendorType(spec *AcceptSpec) string {
+	parts, yes := isVendorType(spec.Value)
+	if !yes {
+		return ""
+	}
+
+	vendorMime := spec.Value
+	subparts := strings.SplitN(parts[1], "+", 2)
+	vendor := strings.TrimPrefix(subparts[0], vendorTreePrefix)
+	if strings.Contains(vendor, "-v") {
+		verparts := strings.SplitN(vendor, "-v", 2)
+		spec.Params["version"] = verparts[1]
+		vendor = verparts[0]
+	} else if idx := strings.LastIndex(vendor, ".v"); idx > 0 && isVersion(vendor[idx+2:]) {
+		spec.Params["version"] = vendor[idx+2:]
+		vendor = vendor[:idx]
+	}
+	spec.Params["vendor"] = vendor
+
+	// Rewrite the Content-Type
+	if len(subparts) == 2 {
+		spec.Value = parts[0] + "/" + subparts[1]
+	}
+	return vendorMime
+}
+
+// matchMediaRange method returns the specificity of media range match with
+// the given offer; 3 - exact match, 2 - vendor suffix match, 1 - `type/*`,
+// 0 - `*/*` and -1 for no match.
+func matchMediaRange(mediaRange, offer string) int {
+	switch {
+	case mediaRange == offer:
+		return 3
+	case mediaRange == "*/*" || mediaRange == "*":
+		return 0
+	}
+
+	rparts := strings.SplitN(mediaRange, "/", 2)
+	oparts := strings.SplitN(offer, "/", 2)
+	if len(rparts) != 2 || len(oparts) != 2 || rparts[0] != oparts[0] {
+		return -1
+	}
+	if rparts[1] == "*" {
+		return 1
+	}
+
+	// structured syntax suffix of vendor type, RFC6839
+	if strings.HasPrefix(rparts[1], vendorTreePrefix) {
+		if idx := strings.LastIndex(rparts[1], "+"); idx > 0 && rparts[1][idx+1:] == oparts[1] {
+			return 2
+		}
+	}
+	return -1
+}
+
+// isVersion method returns true if value is version number like 2, 2.1.
+func isVersion(v string) bool {
+	if len(v) == 0 || v[0] < '0' || v[0] > '9' {
+		return false
+	}
+	for _, c := range v {
+		if (c < '0' || c > '9') && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
 // isVendorType method check the mime type is vendor type as per
 // RFC4288 https://tools.ietf.org/html/rfc4288#section-3.2 - Vendor Tree
 // i.e. `vnd.` prefix.
 func isVendorType(mime string) ([]string, bool) {
 	parts := strings.Split(mime, "/")
-	return parts, strings.HasPrefix(parts[1], vendorTreePrefix)
+	return parts, len(parts) == 2 && strings.HasPrefix(parts[1], vendorTreePrefix)
 }
 
 // parseMediaType method parses a media type value and any optional
@@ -154,3 +154,45 @@ func createRawHTTPRequest(hdrKey, value string) *http.Request {
 		Header: hdr,
 	}
 }
+
+func TestHTTPNegotiate(t *testing.T) {
+	testcases := []struct {
+		accept string
+		offers []string
+		result string
+	}{
+		{accept: "", offers: []string{"application/json", "application/xml"}, result: "application/json"},
+		{accept: "application/xml;q=0.9, application/json", offers: []string{"application/xml", "application/json"}, result: "application/json"},
+		{accept: "text/html, application/xhtml+xml, application/xml;q=0.9, */*;q=0.8", offers: []string{"application/json", "text/html"}, result: "text/html"},
+		{accept: "text/*, application/json;q=0.5", offers: []string{"application/json", "text/plain"}, result: "text/plain"},
+		{accept: "text/plain;q=0, */*", offers: []string{"text/plain", "application/json"}, result: "application/json"},
+		{accept: "text/plain;q=0", offers: []string{"text/plain"}, result: ""},
+		{accept: "image/png", offers: []string{"application/json", "application/xml"}, result: ""},
+		{accept: "application/vnd.myapp.v2+json", offers: []string{"application/xml", "application/json"}, result: "application/json"},
+		{accept: "application/vnd.myapp.v2+json", offers: []string{"application/json", "application/vnd.myapp.v2+json"}, result: "application/vnd.myapp.v2+json"},
+		{accept: "application/json", offers: nil, result: ""},
+	}
+
+	for _, tc := range testcases {
+		req := createRawHTTPRequest(HeaderAccept, tc.accept)
+		assert.Equal(t, tc.result, Negotiate(req, tc.offers...), "accept: %s", tc.accept)
+	}
+}
+
+func TestHTTPAcceptHeaderVendorTypeDotVersion(t *testing.T) {
+	req := createRawHTTPRequest(HeaderAccept, "application/vnd.myapp.v2+json")
+	ctype := NegotiateContentType(req)
+	assert.Equal(t, "application/json", ctype.Mime)
+	assert.Equal(t, "myapp", ctype.Vendor())
+	assert.Equal(t, "2", ctype.Version())
+	assert.Equal(t, "application/vnd.myapp.v2+json", ctype.VendorMime())
+
+	req = createRawHTTPRequest(HeaderAccept, "application/vnd.myapp.vision+json")
+	ctype = NegotiateContentType(req)
+	assert.Equal(t, "myapp.vision", ctype.Vendor())
+	assert.Equal(t, "", ctype.Version())
+
+	req = createRawHTTPRequest(HeaderAccept, "application/json")
+	ctype = NegotiateContentType(req)
+	assert.Equal(t, "", ctype.VendorMime())
+}
@@ -0,0 +1,456 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch operations as per RFC 6902.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+var (
+	// ErrPatchTestFailed returned when JSON Patch `test` operation fails.
+	ErrPatchTestFailed = errors.New("ahttp: json patch test operation failed")
+
+	// ErrPatchPathNotFound returned when JSON Patch operation path does not
+	// exist in the document.
+	ErrPatchPathNotFound = errors.New("ahttp: json patch path not found")
+
+	// ErrPatchInvalidTarget returned when given patch target is not a non-nil
+	// pointer.
+	ErrPatchInvalidTarget = errors.New("ahttp: patch target must be a non-nil pointer")
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JSON Patch
+//___________________________________
+
+// PatchOperation struct holds single JSON Patch operation as per RFC 6902.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// String method is Stringer interface.
+func (po *PatchOperation) String() string {
+	if len(po.From) > 0 {
+		return fmt.Sprintf("%s %s -> %s", po.Op, po.From, po.Path)
+	}
+	return po.Op + " " + po.Path
+}
+
+// JSONPatch type is JSON Patch document as per RFC 6902, it's list of
+// operations applied in order.
+type JSONPatch []*PatchOperation
+
+// ParseJSONPatch method parses and validates the JSON Patch document from
+// given reader.
+func ParseJSONPatch(r io.Reader) (JSONPatch, error) {
+	var patch JSONPatch
+	if err := json.NewDecoder(r).Decode(&patch); err != nil {
+		return nil, fmt.Errorf("ahttp: invalid json patch document: %v", err)
+	}
+	for idx, op := range patch {
+		if op == nil {
+			return nil, fmt.Errorf("ahttp: json patch operation[%d] is null", idx)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("ahttp: json patch operation[%d] %v", idx, err)
+		}
+		switch op.Op {
+		case PatchOpAdd, PatchOpReplace, PatchOpTest:
+			if op.Value == nil {
+				return nil, fmt.Errorf("ahttp: json patch operation[%d] '%s' value is missing", idx, op.Op)
+			}
+		case PatchOpMove, PatchOpCopy:
+			if _, err := parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("ahttp: json patch operation[%d] from %v", idx, err)
+			}
+		case PatchOpRemove:
+		default:
+			return nil, fmt.Errorf("ahttp: json patch operation[%d] '%s' is not supported", idx, op.Op)
+		}
+	}
+	return patch, nil
+}
+
+// Apply method applies the JSON Patch operations on given destination value,
+// destination have to be non-nil pointer. Value is marshalled to JSON
+// document, patched and unmarshalled back into destination. Destination is
+// not modified if any of the operation fails.
+//
+// 	patch, err := ctx.Req.JSONPatch()
+// 	if err == nil {
+// 		err = patch.Apply(user)
+// 	}
+func (p JSONPatch) Apply(dst interface{}) error {
+	return patchValue(dst, p.applyDoc)
+}
+
+// ApplyJSON method applies the JSON Patch operations on given JSON document
+// and returns the patched JSON document.
+func (p JSONPatch) ApplyJSON(doc []byte) ([]byte, error) {
+	d, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	if d, err = p.applyDoc(d); err != nil {
+		return nil, err
+	}
+	return json.Marshal(d)
+}
+
+func (p JSONPatch) applyDoc(doc interface{}) (interface{}, error) {
+	var err error
+	for _, op := range p {
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("ahttp: json patch '%s': %v", op, err)
+		}
+	}
+	return doc, nil
+}
+
+func (po *PatchOperation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePointer(po.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch po.Op {
+	case PatchOpAdd:
+		value, err := decodeJSON(po.Value)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	case PatchOpRemove:
+		doc, _, err = patchRemove(doc, path)
+		return doc, err
+	case PatchOpReplace:
+		value, err := decodeJSON(po.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = patchGet(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = patchRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	case PatchOpMove:
+		from, err := parsePointer(po.From)
+		if err != nil {
+			return nil, err
+		}
+		if po.Path != po.From && strings.HasPrefix(po.Path, po.From+"/") {
+			return nil, errors.New("'from' location cannot be a prefix of 'path'")
+		}
+		var value interface{}
+		if doc, value, err = patchRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	case PatchOpCopy:
+		from, err := parsePointer(po.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := patchGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = deepCopyJSON(value); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	case PatchOpTest:
+		value, err := patchGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalJSON(value, po.Value) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("operation '%s' is not supported", po.Op)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JSON Merge Patch
+//___________________________________
+
+// MergePatch method applies the JSON Merge Patch (RFC 7396) on given JSON
+// document and returns the patched JSON document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	d, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(d, p))
+}
+
+// ApplyMergePatch method applies the JSON Merge Patch (RFC 7396) on given
+// destination value, destination have to be non-nil pointer. Fields with
+// `null` in the patch are reset to zero value.
+func ApplyMergePatch(dst interface{}, patch []byte) error {
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return err
+	}
+	return patchValue(dst, func(doc interface{}) (interface{}, error) {
+		return mergePatch(doc, p), nil
+	})
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{})
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergePatch(tm[k], v)
+		}
+	}
+	return tm
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// patchValue method marshals the destination value into JSON document,
+// applies given patch func and unmarshals back into destination.
+func patchValue(dst interface{}, fn func(doc interface{}) (interface{}, error)) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrPatchInvalidTarget
+	}
+
+	b, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeJSON(b)
+	if err != nil {
+		return err
+	}
+	if doc, err = fn(doc); err != nil {
+		return err
+	}
+	if b, err = json.Marshal(doc); err != nil {
+		return err
+	}
+
+	v := reflect.New(rv.Type().Elem())
+	if err = json.Unmarshal(b, v.Interface()); err != nil {
+		return err
+	}
+	rv.Elem().Set(v.Elem())
+	return nil
+}
+
+// parsePointer method parses the JSON Pointer (RFC 6901) into reference
+// tokens.
+func parsePointer(p string) ([]string, error) {
+	if len(p) == 0 {
+		return []string{}, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("path '%s' is not a valid json pointer", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for idx, t := range tokens {
+		tokens[idx] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func patchGet(doc interface{}, path []string) (interface{}, error) {
+	for _, key := range path {
+		switch n := doc.(type) {
+		case map[string]interface{}:
+			v, found := n[key]
+			if !found {
+				return nil, ErrPatchPathNotFound
+			}
+			doc = v
+		case []interface{}:
+			idx, err := arrayIndex(key, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[idx]
+		default:
+			return nil, ErrPatchPathNotFound
+		}
+	}
+	return doc, nil
+}
+
+func patchAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return patchParent(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch n := parent.(type) {
+		case map[string]interface{}:
+			n[key] = value
+			return n, nil
+		case []interface{}:
+			if key == "-" {
+				return append(n, value), nil
+			}
+			idx, err := arrayIndex(key, len(n))
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[idx+1:], n[idx:])
+			n[idx] = value
+			return n, nil
+		}
+		return nil, ErrPatchPathNotFound
+	})
+}
+
+func patchRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("root document cannot be removed")
+	}
+	var removed interface{}
+	doc, err := patchParent(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch n := parent.(type) {
+		case map[string]interface{}:
+			v, found := n[key]
+			if !found {
+				return nil, ErrPatchPathNotFound
+			}
+			removed = v
+			delete(n, key)
+			return n, nil
+		case []interface{}:
+			idx, err := arrayIndex(key, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = n[idx]
+			return append(n[:idx], n[idx+1:]...), nil
+		}
+		return nil, ErrPatchPathNotFound
+	})
+	return doc, removed, err
+}
+
+// patchParent method walks the document till the parent of given path and
+// calls the func with parent and last reference token. Func returns updated
+// parent, since array could be reallocated.
+func patchParent(doc interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	key := path[0]
+	switch n := doc.(type) {
+	case map[string]interface{}:
+		child, found := n[key]
+		if !found {
+			return nil, ErrPatchPathNotFound
+		}
+		v, err := patchParent(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = v
+		return n, nil
+	case []interface{}:
+		idx, err := arrayIndex(key, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := patchParent(n[idx], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = v
+		return n, nil
+	}
+	return nil, ErrPatchPathNotFound
+}
+
+func arrayIndex(key string, max int) (int, error) {
+	if len(key) > 1 && key[0] == '0' {
+		return 0, fmt.Errorf("array index '%s' is invalid", key)
+	}
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx < 0 || idx > max {
+		return 0, fmt.Errorf("array index '%s' is out of bounds", key)
+	}
+	return idx, nil
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func deepCopyJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
+
+// equalJSON method compares the values as JSON, numbers are compared by its
+// value.
+func equalJSON(v interface{}, raw json.RawMessage) bool {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	var a, e interface{}
+	if json.Unmarshal(b, &a) != nil || json.Unmarshal(raw, &e) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, e)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPatchApply(t *testing.T) {
+	testcases := []struct {
+		label, doc, patch, result, err string
+	}{
+		{
+			label:  "add object member",
+			doc:    `{"foo":"bar"}`,
+			patch:  `[{"op":"add","path":"/baz","value":"qux"}]`,
+			result: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			label:  "add array element",
+			doc:    `{"foo":["bar","baz"]}`,
+			patch:  `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":"end"}]`,
+			result: `{"foo":["bar","qux","baz","end"]}`,
+		},
+		{
+			label:  "remove",
+			doc:    `{"baz":"qux","foo":["bar","qux","baz"]}`,
+			patch:  `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/1"}]`,
+			result: `{"foo":["bar","baz"]}`,
+		},
+		{
+			label:  "replace",
+			doc:    `{"baz":"qux","foo":"bar"}`,
+			patch:  `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			result: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			label:  "move and copy",
+			doc:    `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:  `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"},{"op":"copy","from":"/qux","path":"/quux"}]`,
+			result: `{"foo":{"bar":"baz"},"quux":{"corge":"grault","thud":"fred"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			label:  "test and escaped pointer",
+			doc:    `{"a/b":{"m~n":10}}`,
+			patch:  `[{"op":"test","path":"/a~1b/m~0n","value":10.0},{"op":"replace","path":"/a~1b/m~0n","value":null}]`,
+			result: `{"a/b":{"m~n":null}}`,
+		},
+		{
+			label: "test failed",
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   "ahttp: json patch 'test /baz': ahttp: json patch test operation failed",
+		},
+		{
+			label: "path not found",
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"add","path":"/foo/bar","value":1}]`,
+			err:   "ahttp: json patch 'add /foo/bar': ahttp: json patch path not found",
+		},
+		{
+			label: "array out of bounds",
+			doc:   `{"foo":[1]}`,
+			patch: `[{"op":"replace","path":"/foo/1","value":2}]`,
+			err:   "ahttp: json patch 'replace /foo/1': array index '1' is out of bounds",
+		},
+		{
+			label: "move into own child",
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			err:   "ahttp: json patch 'move /foo -> /foo/bar/baz': 'from' location cannot be a prefix of 'path'",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			patch, err := ParseJSONPatch(strings.NewReader(tc.patch))
+			assert.Nil(t, err)
+			result, err := patch.ApplyJSON([]byte(tc.doc))
+			if len(tc.err) > 0 {
+				assert.Equal(t, tc.err, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, string(result))
+		})
+	}
+}
+
+func TestJSONPatchParseErrors(t *testing.T) {
+	testcases := []struct {
+		patch, err string
+	}{
+		{`{"op":"add"}`, "ahttp: invalid json patch document: json: cannot unmarshal object into Go value of type ahttp.JSONPatch"},
+		{`[{"op":"add","path":"/a"}]`, "ahttp: json patch operation[0] 'add' value is missing"},
+		{`[{"op":"remove","path":"a"}]`, "ahttp: json patch operation[0] path 'a' is not a valid json pointer"},
+		{`[{"op":"copy","path":"/a","from":"b"}]`, "ahttp: json patch operation[0] from path 'b' is not a valid json pointer"},
+		{`[{"op":"delete","path":"/a"}]`, "ahttp: json patch operation[0] 'delete' is not supported"},
+		{`[null]`, "ahttp: json patch operation[0] is null"},
+	}
+
+	for _, tc := range testcases {
+		_, err := ParseJSONPatch(strings.NewReader(tc.patch))
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
+
+func TestJSONPatchAndMergePatchOnValue(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type user struct {
+		ID      int64    `json:"id"`
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Address *address `json:"address"`
+	}
+
+	// JSON Patch via request
+	req := httptest.NewRequest("PATCH", "http://localhost:8080/users/1",
+		strings.NewReader(`[{"op":"replace","path":"/name","value":"Jeeva"},{"op":"add","path":"/tags/-","value":"admin"}]`))
+	aahReq := ParseRequest(req, &Request{})
+	patch, err := aahReq.JSONPatch()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(patch))
+	patch, err = aahReq.JSONPatch() // cached
+	assert.Nil(t, err)
+	assert.Equal(t, "replace /name", patch[0].String())
+
+	u := &user{ID: 9007199254740993, Name: "jeeva", Tags: []string{"user"}, Address: &address{City: "Chennai", Zip: "600001"}}
+	assert.Nil(t, patch.Apply(u))
+	assert.Equal(t, &user{ID: 9007199254740993, Name: "Jeeva", Tags: []string{"user", "admin"},
+		Address: &address{City: "Chennai", Zip: "600001"}}, u)
+
+	// failed patch does not modify destination
+	patch, _ = ParseJSONPatch(strings.NewReader(`[{"op":"replace","path":"/name","value":"x"},{"op":"remove","path":"/unknown"}]`))
+	assert.NotNil(t, patch.Apply(u))
+	assert.Equal(t, "Jeeva", u.Name)
+	assert.Equal(t, ErrPatchInvalidTarget, patch.Apply(*u))
+
+	// JSON Merge Patch via request
+	req = httptest.NewRequest("PATCH", "http://localhost:8080/users/1",
+		strings.NewReader(`{"name":"Jeevanandam","address":{"zip":null},"tags":null}`))
+	aahReq = ParseRequest(req, &Request{})
+	assert.Nil(t, aahReq.ApplyMergePatch(u))
+	assert.Equal(t, &user{ID: 9007199254740993, Name: "Jeevanandam", Address: &address{City: "Chennai"}}, u)
+
+	aahReq.Reset()
+	assert.Nil(t, aahReq.mergePatch)
+
+	req = httptest.NewRequest("PATCH", "http://localhost:8080/users/1", strings.NewReader(`{"name":`))
+	aahReq = ParseRequest(req, &Request{})
+	assert.Equal(t, "ahttp: invalid json merge patch document", aahReq.ApplyMergePatch(u).Error())
+
+	// RFC 7396 examples
+	result, err := MergePatch([]byte(`{"a":"b","c":{"d":"e","f":"g"}}`), []byte(`{"a":"z","c":{"f":null}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"z","c":{"d":"e"}}`, string(result))
+
+	result, err = MergePatch([]byte(`{"a":["b"]}`), []byte(`{"a":[{"c":"d"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":[{"c":"d"}]}`, string(result))
+
+	result, err = MergePatch([]byte(`{"a":"foo"}`), []byte(`["c"]`))
+	assert.Nil(t, err)
+	assert.Equal(t, `["c"]`, string(result))
+}
@@ -5,9 +5,12 @@
 package ahttp
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -23,7 +26,10 @@ const (
 	ajaxHeaderValue  = "XMLHttpRequest"
 )
 
-var requestPool = &sync.Pool{New: func() interface{} { return &Request{} }}
+var (
+	requestPool   = &sync.Pool{New: func() interface{} { return &Request{} }}
+	urlParamsPool = &sync.Pool{New: func() interface{} { return &URLParams{} }}
+)
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
@@ -84,6 +90,10 @@ type Request struct {
 	contentType       *ContentType
 	acceptContentType *ContentType
 	acceptEncoding    *AcceptSpec
+	jsonPatch         JSONPatch
+	mergePatch        []byte
+	rawBody           []byte
+	bodyBuf           *bytes.Buffer
 }
 
 // AcceptContentType method returns negotiated value.
@@ -254,6 +264,91 @@ func (r *Request) Body() io.ReadCloser {
 	return r.Unwrap().Body
 }
 
+// BufferBody method buffers the request body bytes as it's read by the
+// request parsers and auto binding, so that `RawBody` returns the complete
+// body afterwards. It has to be called before reading the request body.
+//
+// Note: aah BindMiddleware calls it for the route that has `raw_body = true`
+// in the routes.conf.
+func (r *Request) BufferBody() {
+	if r.bodyBuf == nil && r.rawBody == nil && r.Body() != nil {
+		r.bodyBuf = new(bytes.Buffer)
+		r.Unwrap().Body = ioutil.NopCloser(io.TeeReader(r.Body(), r.bodyBuf))
+	}
+}
+
+// RawBody method returns the request body bytes, it's available even after
+// the body is consumed by auto binding if route has `raw_body = true`
+// (see `BufferBody`), for e.g.:
+// signature verification, audit logging, proxying, etc. Body bytes are
+// cached and the request body is replaced with it so that subsequent
+// readers read the same content.
+//
+// Note: The request body size is subject to route `max_body_size`, error
+// is returned if it exceeds.
+func (r *Request) RawBody() ([]byte, error) {
+	if r.rawBody == nil {
+		if r.Body() == nil {
+			return []byte{}, nil
+		}
+		b, err := ioutil.ReadAll(r.Body())
+		if err != nil {
+			return nil, err
+		}
+		if r.bodyBuf != nil {
+			b, r.bodyBuf = r.bodyBuf.Bytes(), nil
+		}
+		r.rawBody = b
+		r.Unwrap().Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+	return r.rawBody, nil
+}
+
+// JSONPatch method reads the request body as JSON Patch document (RFC 6902)
+// and returns the parsed operations. Parsed document is cached for subsequent
+// calls. See `JSONPatch.Apply`.
+func (r *Request) JSONPatch() (JSONPatch, error) {
+	if r.jsonPatch == nil {
+		patch, err := ParseJSONPatch(r.Body())
+		if err != nil {
+			return nil, err
+		}
+		r.jsonPatch = patch
+	}
+	return r.jsonPatch, nil
+}
+
+// MergePatch method reads the request body as JSON Merge Patch document
+// (RFC 7396) and returns it. Document is cached for subsequent calls.
+func (r *Request) MergePatch() ([]byte, error) {
+	if r.mergePatch == nil {
+		b, err := ioutil.ReadAll(r.Body())
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(b) {
+			return nil, errors.New("ahttp: invalid json merge patch document")
+		}
+		r.mergePatch = b
+	}
+	return r.mergePatch, nil
+}
+
+// ApplyMergePatch method applies the request body JSON Merge Patch (RFC 7396)
+// on given destination value, destination have to be non-nil pointer.
+//
+// 	user := models.FindUser(id)
+// 	if err := ctx.Req.ApplyMergePatch(user); err != nil {
+// 		// handle error
+// 	}
+func (r *Request) ApplyMergePatch(dst interface{}) error {
+	patch, err := r.MergePatch()
+	if err != nil {
+		return err
+	}
+	return ApplyMergePatch(dst, patch)
+}
+
 // Unwrap method returns the underlying *http.Request instance of Go HTTP server,
 // direct interaction with raw object is not encouraged. Use it appropriately.
 func (r *Request) Unwrap() *http.Request {
@@ -296,6 +391,10 @@ func (r *Request) Reset() {
 	r.contentType = nil
 	r.acceptContentType = nil
 	r.acceptEncoding = nil
+	r.jsonPatch = nil
+	r.mergePatch = nil
+	r.rawBody = nil
+	r.bodyBuf = nil
 }
 
 func (r *Request) cleanupMutlipart() {
@@ -7,6 +7,7 @@ package ahttp
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"mime/multipart"
 	"net/http"
@@ -163,6 +164,16 @@ func TestHTTPRequestParams(t *testing.T) {
 	ReleaseRequest(aahReq3)
 }
 
+func TestHTTPURLParamsPool(t *testing.T) {
+	params := AcquireURLParams()
+	assert.Equal(t, 0, len(*params))
+	*params = append(*params, URLParam{Key: "id", Value: "100"})
+	assert.Equal(t, "100", params.Get("id"))
+	ReleaseURLParams(params)
+	assert.Equal(t, 0, len(*params))
+	ReleaseURLParams(nil)
+}
+
 func TestHTTPRequestCookies(t *testing.T) {
 	req := createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
 	req.Method = MethodGet
@@ -266,6 +277,36 @@ func TestRequestSaveFileForExistingFile(t *testing.T) {
 	assert.Equal(t, int64(0), size)
 }
 
+func TestRequestRawBody(t *testing.T) {
+	body := `{"name":"aah","tags":["web","framework"]}`
+	r := httptest.NewRequest(MethodPost, "http://localhost:8080/records", strings.NewReader(body))
+	req := AcquireRequest(r)
+	req.BufferBody()
+
+	var v map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body()).Decode(&v))
+	assert.Equal(t, "aah", v["name"])
+
+	b, err := req.RawBody()
+	assert.Nil(t, err)
+	assert.Equal(t, body, string(b))
+	b, _ = req.RawBody()
+	assert.Equal(t, body, string(b))
+
+	// body exceeds max size
+	r = httptest.NewRequest(MethodPost, "http://localhost:8080/records", strings.NewReader(body))
+	r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 10)
+	req = AcquireRequest(r)
+	req.BufferBody()
+	b, err = req.RawBody()
+	assert.Nil(t, b)
+	assert.NotNil(t, err)
+
+	req.Reset()
+	assert.Nil(t, req.bodyBuf)
+	assert.Nil(t, req.rawBody)
+}
+
 func TestURLParams(t *testing.T) {
 	params := URLParams{
 		{
@@ -0,0 +1,247 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookTolerance is the maximum age of the inbound webhook timestamp.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	// ErrWebhookProviderIsNil returned when webhook provider is not supplied.
+	ErrWebhookProviderIsNil = errors.New("ahttp: webhook provider is nil")
+
+	// ErrWebhookSignatureMissing returned when webhook signature or timestamp
+	// header is not present.
+	ErrWebhookSignatureMissing = errors.New("ahttp: webhook signature missing")
+
+	// ErrWebhookSignatureInvalid returned when webhook signature does not
+	// match.
+	ErrWebhookSignatureInvalid = errors.New("ahttp: webhook signature invalid")
+
+	// ErrWebhookTimestampExpired returned when webhook timestamp is outside
+	// of the tolerance.
+	ErrWebhookTimestampExpired = errors.New("ahttp: webhook timestamp expired")
+
+	// ErrWebhookReplayed returned when webhook request is already seen.
+	ErrWebhookReplayed = errors.New("ahttp: webhook request replayed")
+)
+
+type (
+	// WebhookProvider interface is used to verify the inbound webhook request
+	// signature of the provider. See `GitHubWebhook`, `StripeWebhook` and
+	// `SlackWebhook`.
+	WebhookProvider interface {
+		Verify(header http.Header, body []byte) error
+	}
+
+	// WebhookReplayGuard interface is used to reject the replayed webhook
+	// requests. `Seen` method records the given key for ttl duration and
+	// returns true if it's already recorded.
+	WebhookReplayGuard interface {
+		Seen(key string, ttl time.Duration) bool
+	}
+
+	// GitHubWebhook verifies the header `X-Hub-Signature-256`, replay key is
+	// the header `X-GitHub-Delivery`.
+	GitHubWebhook struct {
+		Secret      string
+		Tolerance   time.Duration
+		ReplayGuard WebhookReplayGuard
+	}
+
+	// StripeWebhook verifies the header `Stripe-Signature` and its timestamp
+	// is within the tolerance.
+	StripeWebhook struct {
+		Secret      string
+		Tolerance   time.Duration
+		ReplayGuard WebhookReplayGuard
+	}
+
+	// SlackWebhook verifies the header `X-Slack-Signature` using signing
+	// secret and `X-Slack-Request-Timestamp` is within the tolerance.
+	SlackWebhook struct {
+		Secret      string
+		Tolerance   time.Duration
+		ReplayGuard WebhookReplayGuard
+	}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Request methods
+//___________________________________
+
+// VerifyWebhook method verifies the inbound webhook request signature using
+// given provider with constant time comparison.
+//
+// 	if err := ctx.Req.VerifyWebhook(&ahttp.GitHubWebhook{Secret: secret}); err != nil {
+// 		ctx.Reply().Unauthorized().Text("invalid signature")
+// 		return
+// 	}
+func (r *Request) VerifyWebhook(provider WebhookProvider) error {
+	if provider == nil {
+		return ErrWebhookProviderIsNil
+	}
+	body, err := r.RawBody()
+	if err != nil {
+		return err
+	}
+	return provider.Verify(r.Header, body)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Webhook providers
+//___________________________________
+
+// Verify method verifies the GitHub webhook signature.
+func (g *GitHubWebhook) Verify(header http.Header, body []byte) error {
+	sig := header.Get("X-Hub-Signature-256")
+	if !strings.HasPrefix(sig, "sha256=") {
+		return ErrWebhookSignatureMissing
+	}
+	if !webhookMACEqual(g.Secret, body, sig[7:]) {
+		return ErrWebhookSignatureInvalid
+	}
+	if id := header.Get("X-GitHub-Delivery"); len(id) > 0 {
+		return checkWebhookReplay(g.ReplayGuard, "github:"+id, g.Tolerance)
+	}
+	return nil
+}
+
+// Verify method verifies the Stripe webhook signature, any one of `v1`
+// signature matches is valid.
+func (s *StripeWebhook) Verify(header http.Header, body []byte) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sigs = append(sigs, kv[1])
+		}
+	}
+	if len(ts) == 0 || len(sigs) == 0 {
+		return ErrWebhookSignatureMissing
+	}
+	if err := checkWebhookTimestamp(ts, s.Tolerance); err != nil {
+		return err
+	}
+
+	payload := append([]byte(ts+"."), body...)
+	for _, sig := range sigs {
+		if webhookMACEqual(s.Secret, payload, sig) {
+			return checkWebhookReplay(s.ReplayGuard, "stripe:"+sig, s.Tolerance)
+		}
+	}
+	return ErrWebhookSignatureInvalid
+}
+
+// Verify method verifies the Slack request signature.
+func (s *SlackWebhook) Verify(header http.Header, body []byte) error {
+	ts, sig := header.Get("X-Slack-Request-Timestamp"), header.Get("X-Slack-Signature")
+	if len(ts) == 0 || !strings.HasPrefix(sig, "v0=") {
+		return ErrWebhookSignatureMissing
+	}
+	if err := checkWebhookTimestamp(ts, s.Tolerance); err != nil {
+		return err
+	}
+	if !webhookMACEqual(s.Secret, append([]byte("v0:"+ts+":"), body...), sig[3:]) {
+		return ErrWebhookSignatureInvalid
+	}
+	return checkWebhookReplay(s.ReplayGuard, "slack:"+sig, s.Tolerance)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Memory replay guard
+//___________________________________
+
+// NewMemoryReplayGuard method returns the in-memory webhook replay guard,
+// suitable for single instance deployment.
+func NewMemoryReplayGuard() WebhookReplayGuard {
+	return &memoryReplayGuard{seen: make(map[string]time.Time)}
+}
+
+type memoryReplayGuard struct {
+	sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+func (g *memoryReplayGuard) Seen(key string, ttl time.Duration) bool {
+	now := time.Now()
+	g.Lock()
+	defer g.Unlock()
+	if now.Sub(g.lastSweep) >= time.Minute {
+		for k, exp := range g.seen {
+			if now.After(exp) {
+				delete(g.seen, k)
+			}
+		}
+		g.lastSweep = now
+	}
+	if exp, found := g.seen[key]; found && now.Before(exp) {
+		return true
+	}
+	g.seen[key] = now.Add(ttl)
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// webhookMACEqual method compares the hex encoded HMAC SHA-256 signature in
+// constant time.
+func webhookMACEqual(secret string, payload []byte, sig string) bool {
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), got)
+}
+
+func checkWebhookTimestamp(ts string, tolerance time.Duration) error {
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrWebhookSignatureMissing
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	if d := time.Since(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return ErrWebhookTimestampExpired
+	}
+	return nil
+}
+
+func checkWebhookReplay(guard WebhookReplayGuard, key string, ttl time.Duration) error {
+	if guard == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultWebhookTolerance
+	}
+	if guard.Seen(key, ttl) {
+		return ErrWebhookReplayed
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookGitHub(t *testing.T) {
+	body := `{"action":"opened"}`
+	newReq := func(sig, id string) *Request {
+		r := httptest.NewRequest(MethodPost, "http://localhost:8080/webhooks/github", strings.NewReader(body))
+		r.Header.Set("X-Hub-Signature-256", sig)
+		r.Header.Set("X-GitHub-Delivery", id)
+		return AcquireRequest(r)
+	}
+	p := &GitHubWebhook{Secret: "s3cr3t", ReplayGuard: NewMemoryReplayGuard()}
+
+	req := newReq("sha256="+testWebhookMAC("s3cr3t", body), "d1")
+	assert.Nil(t, req.VerifyWebhook(p))
+
+	// body is still readable
+	b, err := ioutil.ReadAll(req.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, body, string(b))
+
+	assert.Equal(t, ErrWebhookReplayed, newReq("sha256="+testWebhookMAC("s3cr3t", body), "d1").VerifyWebhook(p))
+	assert.Equal(t, ErrWebhookSignatureInvalid, newReq("sha256="+testWebhookMAC("other", body), "d2").VerifyWebhook(p))
+	assert.Equal(t, ErrWebhookSignatureInvalid, newReq("sha256=nothex", "d3").VerifyWebhook(p))
+	assert.Equal(t, ErrWebhookSignatureMissing, newReq("", "d4").VerifyWebhook(p))
+	assert.Equal(t, ErrWebhookProviderIsNil, newReq("", "d5").VerifyWebhook(nil))
+}
+
+func TestWebhookStripe(t *testing.T) {
+	body := `{"type":"charge.succeeded"}`
+	p := &StripeWebhook{Secret: "whsec", ReplayGuard: NewMemoryReplayGuard()}
+	header := func(ts int64, secret string) http.Header {
+		t := strconv.FormatInt(ts, 10)
+		hdr := http.Header{}
+		hdr.Set("Stripe-Signature", "t="+t+",v1="+testWebhookMAC("bad", t+"."+body)+",v1="+testWebhookMAC(secret, t+"."+body))
+		return hdr
+	}
+
+	now := time.Now().Unix()
+	assert.Nil(t, p.Verify(header(now, "whsec"), []byte(body)))
+	assert.Equal(t, ErrWebhookReplayed, p.Verify(header(now, "whsec"), []byte(body)))
+	assert.Equal(t, ErrWebhookSignatureInvalid, p.Verify(header(now, "other"), []byte(body)))
+	assert.Equal(t, ErrWebhookTimestampExpired, p.Verify(header(now-600, "whsec"), []byte(body)))
+	assert.Equal(t, ErrWebhookSignatureMissing, p.Verify(http.Header{"Stripe-Signature": {"t=123"}}, []byte(body)))
+	assert.Equal(t, ErrWebhookSignatureMissing, p.Verify(http.Header{"Stripe-Signature": {"t=abc,v1=00"}}, []byte(body)))
+
+	p.Tolerance = 20 * time.Minute
+	assert.Nil(t, p.Verify(header(now-600, "whsec"), []byte(body)))
+}
+
+func TestWebhookSlack(t *testing.T) {
+	body := "token=abc&team_id=T1"
+	p := &SlackWebhook{Secret: "signing"}
+	header := func(ts int64, secret string) http.Header {
+		t := strconv.FormatInt(ts, 10)
+		hdr := http.Header{}
+		hdr.Set("X-Slack-Request-Timestamp", t)
+		hdr.Set("X-Slack-Signature", "v0="+testWebhookMAC(secret, "v0:"+t+":"+body))
+		return hdr
+	}
+
+	now := time.Now().Unix()
+	assert.Nil(t, p.Verify(header(now, "signing"), []byte(body)))
+	assert.Nil(t, p.Verify(header(now, "signing"), []byte(body)), "no replay guard")
+	assert.Equal(t, ErrWebhookSignatureInvalid, p.Verify(header(now, "other"), []byte(body)))
+	assert.Equal(t, ErrWebhookTimestampExpired, p.Verify(header(now+600, "signing"), []byte(body)))
+	assert.Equal(t, ErrWebhookSignatureMissing, p.Verify(http.Header{}, []byte(body)))
+}
+
+func TestWebhookMemoryReplayGuard(t *testing.T) {
+	g := NewMemoryReplayGuard()
+	assert.False(t, g.Seen("k1", time.Minute))
+	assert.True(t, g.Seen("k1", time.Minute))
+	assert.False(t, g.Seen("k2", time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	assert.False(t, g.Seen("k2", time.Minute))
+}
+
+func testWebhookMAC(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -104,7 +104,7 @@ func Inspect(dir, importPath string, excludes ess.Excludes, registeredActions ma
 		pfset := token.NewFileSet()
 		pkgs, err = parser.ParseDir(pfset, srcPath, func(f os.FileInfo) bool {
 			return !f.IsDir() && !excludes.Match(f.Name())
-		}, 0)
+		}, parser.ParseComments)
 
 		if err != nil {
 			if errList, ok := err.(scanner.ErrorList); ok {
@@ -198,3 +198,32 @@ func FindFieldIndexes(targetTyp reflect.Type, searchTyp reflect.Type) [][]int {
 
 	return indexes
 }
+
+// FindTaggedFieldIndexes method returns the index positions of exported struct
+// fields which have given tag key, including the fields of embedded
+// struct (non-pointer).
+//
+// For e.g.: `inject:""`
+func FindTaggedFieldIndexes(targetTyp reflect.Type, tagKey string) [][]int {
+	var indexes [][]int
+	for targetTyp.Kind() == reflect.Ptr {
+		targetTyp = targetTyp.Elem()
+	}
+	if targetTyp.Kind() != reflect.Struct {
+		return indexes
+	}
+
+	for i := 0; i < targetTyp.NumField(); i++ {
+		field := targetTyp.Field(i)
+		if _, found := field.Tag.Lookup(tagKey); found && len(field.PkgPath) == 0 {
+			indexes = append(indexes, []int{i})
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, index := range FindTaggedFieldIndexes(field.Type, tagKey) {
+				indexes = append(indexes, append([]int{i}, index...))
+			}
+		}
+	}
+	return indexes
+}
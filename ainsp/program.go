@@ -126,6 +126,7 @@ type typeInfo struct {
 type methodInfo struct {
 	Name       string
 	StructName string
+	Doc        string
 	Parameters []*parameterInfo
 }
 
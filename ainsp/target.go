@@ -19,6 +19,10 @@ import (
 type TargetRegistry struct {
 	Registry   map[string]*Target
 	SearchType reflect.Type
+
+	// InjectTag is struct field tag key to discover the fields to be
+	// injected, for e.g.: `inject`.
+	InjectTag string
 }
 
 // Add method adds given target struct and its methods after processing.
@@ -59,10 +63,31 @@ func (tr *TargetRegistry) Add(t interface{}, methods []*Method) {
 	}
 	target.NoSuffixName = noSuffixName
 
+	// Constructor and dependency injection fields
+	if ctor, found := reflect.PtrTo(ttyp).MethodByName(ConstructorName); found &&
+		ctor.Type.NumOut() == 1 && ctor.Type.Out(0) == reflect.PtrTo(ttyp) {
+		target.Constructor = &ctor
+	}
+	if len(tr.InjectTag) > 0 {
+		target.InjectIndexes = FindTaggedFieldIndexes(ttyp, tr.InjectTag)
+	}
+
 	// adding to registry
 	tr.Registry[key] = target
 }
 
+// LookupByType method returns `Target` info from registry for given target
+// struct or pointer otherwise nil.
+func (tr *TargetRegistry) LookupByType(t interface{}) *Target {
+	ttyp := atype(t)
+	for _, target := range tr.Registry {
+		if target.Type == ttyp {
+			return target
+		}
+	}
+	return nil
+}
+
 // Lookup method returns `Target` info from registry for given `fqName`
 // (fully qualified name) otherwise nil.
 //
@@ -86,7 +111,17 @@ func (tr *TargetRegistry) Lookup(fqName string) *Target {
 // Target struct and related types
 //______________________________________________________________________________
 
+// ConstructorName is the target method name which is used to construct the
+// target instance with its dependencies, for e.g.:
+//
+// 	func (c *UserController) New(ctx *aah.Context, repo *UserRepository) *UserController
+const ConstructorName = "New"
+
 // Target struct holds info about targeted controller, websocket, etc.
+//
+// Constructor is the target `New` method which returns the target pointer
+// type otherwise nil. InjectIndexes are the field index positions which are
+// tagged with registry `InjectTag`.
 type Target struct {
 	Name            string
 	FqName          string
@@ -95,12 +130,30 @@ type Target struct {
 	Type            reflect.Type
 	Methods         map[string]*Method
 	EmbeddedIndexes [][]int
+	InjectIndexes   [][]int
+	Constructor     *reflect.Method
 }
 
-// Method holds single method information of target.
+// Method holds single method information of target. Doc is the method doc
+// comment, used for API documentation purpose.
+//
+// Request is the request body DTO type of the method and Responses are
+// typed responses per HTTP status code, used for API documentation,
+// validation and dump log redaction.
 type Method struct {
 	Name       string
+	Doc        string
 	Parameters []*Parameter
+	Request    reflect.Type
+	Responses  []*Response
+}
+
+// Response holds the typed response information of method for HTTP
+// status code.
+type Response struct {
+	Code        int
+	Description string
+	Type        reflect.Type
 }
 
 // Parameter holds parameter information of method.
@@ -118,3 +171,26 @@ func (t *Target) Lookup(methodName string) *Method {
 	}
 	return nil
 }
+
+// Response method returns the typed response info for given HTTP status code
+// otherwise nil.
+func (m *Method) Response(code int) *Response {
+	for _, r := range m.Responses {
+		if r.Code == code {
+			return r
+		}
+	}
+	return nil
+}
+
+// AddResponse method adds the typed response info for HTTP status code,
+// existing one gets replaced.
+func (m *Method) AddResponse(r *Response) {
+	for i, er := range m.Responses {
+		if er.Code == r.Code {
+			m.Responses[i] = r
+			return
+		}
+	}
+	m.Responses = append(m.Responses, r)
+}
@@ -104,6 +104,20 @@ func TestTargetRegisterForTargets(t *testing.T) {
 	method = target.Lookup("MethodNotExists")
 	assert.Nil(t, method)
 
+	// lookup by type
+	t.Log("lookup by type")
+	assert.Equal(t, target, tr.LookupByType((*Level3)(nil)))
+	assert.Nil(t, tr.LookupByType((*Anonymous1)(nil)))
+
+	// typed responses
+	t.Log("typed responses")
+	method = target.Lookup("Testing")
+	assert.Nil(t, method.Response(200))
+	method.AddResponse(&Response{Code: 200, Type: reflect.TypeOf(Anonymous1{})})
+	method.AddResponse(&Response{Code: 200, Type: reflect.TypeOf(&Anonymous1{})})
+	assert.Equal(t, 1, len(method.Responses))
+	assert.Equal(t, reflect.TypeOf(&Anonymous1{}), method.Response(200).Type)
+
 }
 
 func TestTypeEmbeddedIndexes(t *testing.T) {
@@ -122,3 +136,38 @@ func testTypeEmbeddedIndexes(t *testing.T, c interface{}, expected [][]int) {
 		t.Errorf("Indexes do not match. expected %v actual %v", expected, actual)
 	}
 }
+
+type (
+	InjectEmbed struct {
+		Repo *Anonymous1 `inject:""`
+	}
+
+	InjectTarget struct {
+		*Context
+		InjectEmbed
+		Name   string `inject:""`
+		Skip   string
+		hidden string `inject:""`
+	}
+)
+
+func (t *InjectTarget) New(ctx *Context) *InjectTarget { return t }
+
+func TestTargetConstructorAndInject(t *testing.T) {
+	tr := &TargetRegistry{
+		Registry:   make(map[string]*Target),
+		SearchType: ctxPtrType,
+		InjectTag:  "inject",
+	}
+	tr.Add((*InjectTarget)(nil), nil)
+	tr.Add((*Level1)(nil), nil)
+
+	target := tr.LookupByType((*InjectTarget)(nil))
+	assert.NotNil(t, target.Constructor)
+	assert.Equal(t, ConstructorName, target.Constructor.Name)
+	assert.Equal(t, [][]int{{1, 0}, {2}}, target.InjectIndexes)
+
+	target = tr.LookupByType((*Level1)(nil))
+	assert.Nil(t, target.Constructor)
+	assert.Nil(t, target.InjectIndexes)
+}
@@ -96,6 +96,9 @@ func processMethods(pkg *packageInfo, routeMethods map[string]map[string]uint8,
 
 	controllerName := getName(fn.Recv.List[0].Type)
 	method := &methodInfo{Name: actionName, StructName: controllerName, Parameters: []*parameterInfo{}}
+	if fn.Doc != nil {
+		method.Doc = strings.TrimSpace(fn.Doc.Text())
+	}
 
 	// processed so set to level 2, used to display unimplemented action details
 	// TODO for controller check too
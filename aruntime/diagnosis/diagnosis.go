@@ -17,6 +17,7 @@ import (
 	"runtime/pprof"
 	"runtime/trace"
 	"strings"
+	"sync"
 	"time"
 
 	"aahframe.work/config"
@@ -52,6 +53,8 @@ type Diagnosis struct {
 	log                log.Loggerer
 	pathPrefix         string
 	serverWriteTimeout time.Duration
+	statsMu            sync.RWMutex
+	stats              map[string]func() interface{}
 }
 
 // IsHTTPMode method returns true if diagnosis enabled in HTTP mode otherwise false.
@@ -59,6 +62,18 @@ func (d *Diagnosis) IsHTTPMode() bool {
 	return d.Mode == "http"
 }
 
+// AddStats method adds the named application stats provider, its value is
+// served as JSON on diagnosis endpoint `/diagnosis/stats`. For e.g.:
+// connection metrics, server timings, etc.
+func (d *Diagnosis) AddStats(name string, fn func() interface{}) {
+	d.statsMu.Lock()
+	defer d.statsMu.Unlock()
+	if d.stats == nil {
+		d.stats = make(map[string]func() interface{})
+	}
+	d.stats[name] = fn
+}
+
 // Run method runs diagnosis solutions on current aah application based on
 // given diagnosis configuration on application startup.
 func (d *Diagnosis) Run() {
@@ -94,6 +109,7 @@ func (d *Diagnosis) createHTTPServer() {
 	mux.HandleFunc(d.pathPrefix+"/pprof/profile", d.cpuProfileHandler)
 	mux.HandleFunc(d.pathPrefix+"/pprof/symbol", d.symbolHandler)
 	mux.HandleFunc(d.pathPrefix+"/pprof/trace", d.traceHandler)
+	mux.HandleFunc(d.pathPrefix+"/stats", d.statsHandler)
 	var err error
 	d.serverWriteTimeout, err = time.ParseDuration(d.Config.StringDefault("runtime.diagnosis.http.timeout.write", "2m"))
 	if err != nil {
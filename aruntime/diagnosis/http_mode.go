@@ -11,6 +11,7 @@ package diagnosis
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -156,7 +157,7 @@ code {
 </tbody>
 </table>
 </center>
-<br>
+<p>Application stats (connections, server timing, etc.): <a href={{ $.PathPrefix }}/stats>stats</a></p>
 <div>
 <h3>Query Parameter(s):</h3>
 <p>Parameter: debug</p>
@@ -228,12 +229,31 @@ func (d *Diagnosis) dynamicProfileHandler(w http.ResponseWriter, r *http.Request
 	_, _ = w.Write([]byte("Unknown profile"))
 }
 
+// StatsHandler responds with the application stats in JSON form,
+// refer to method `Diagnosis.AddStats`.
+func (d *Diagnosis) statsHandler(w http.ResponseWriter, r *http.Request) {
+	d.statsMu.RLock()
+	stats := make(map[string]interface{}, len(d.stats))
+	for name, fn := range d.stats {
+		stats[name] = fn()
+	}
+	d.statsMu.RUnlock()
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(stats); err != nil {
+		d.log.Error(err)
+	}
+}
+
 // CmdlineHandler responds with the running program's
 // command line, with arguments separated by NUL bytes.
 func (d *Diagnosis) cmdlineHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	fmt.Fprintf(w, strings.Join(os.Args, "\x00"))
+	fmt.Fprint(w, strings.Join(os.Args, "\x00"))
 }
 
 // CPUProfileHandler responds with the pprof-formatted cpu profile.
@@ -0,0 +1,190 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+	"aahframe.work/security/authserver"
+)
+
+const (
+	authServerTarget            = "authServerController"
+	authServerRouteNamePrefix   = "auth_server_"
+	authServerDefaultPathPrefix = "/oauth2"
+	authServerDefaultConsent    = "/authserver/consent.html"
+	authServerDiscoveryPath     = "/.well-known/openid-configuration"
+	authServerMaxBodySize       = 64 << 10 // OAuth2 requests are small form bodies
+)
+
+// AuthServer method returns the OAuth2/OIDC authorization server instance if
+// it's enabled via `security.auth_server.enable` otherwise nil.
+func (a *Application) AuthServer() *authserver.Server {
+	return a.authServer
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Auth Server controller
+//______________________________________________________________________________
+
+// authServerController serves the OAuth2/OIDC authorization server endpoints.
+type authServerController struct {
+	*Context
+}
+
+// Authorize method handles the authorization endpoint, subject is
+// authenticated per `security.auth_server.auth`. Consent screen is rendered
+// via view engine on `GET` and the consent form is submitted to same URL via
+// `POST` with form value `consent` (`allow` or `deny`). Trusted clients skip
+// the consent screen.
+func (c *authServerController) Authorize() {
+	as := c.a.AuthServer()
+	ar, e := as.ParseAuthorizeRequest(c.Req.Unwrap())
+	if e != nil {
+		c.Log().Warnf("Auth server authorize request: %v", e)
+		if ar == nil {
+			c.replyError(e)
+		} else {
+			c.Reply().Redirect(as.ErrorRedirectURL(ar, e))
+		}
+		return
+	}
+
+	subject := c.Subject().PrimaryPrincipal()
+	if subject == nil {
+		c.Reply().Unauthorized().Error(newError(ErrNotAuthenticated, http.StatusUnauthorized))
+		return
+	}
+
+	if ar.Client.Trusted || !c.a.Config().BoolDefault("security.auth_server.consent", true) {
+		c.Reply().Redirect(as.IssueCode(ar, subject.Value))
+		return
+	}
+
+	if c.Req.Method == ahttp.MethodPost {
+		if c.Req.FormValue("consent") == "allow" {
+			c.Log().Infof("Auth server consent is granted by '%s' to client '%s'", subject.Value, ar.Client.ID)
+			c.Reply().Redirect(as.IssueCode(ar, subject.Value))
+		} else {
+			c.Reply().Redirect(as.ErrorRedirectURL(ar, &authserver.Error{Code: "access_denied", Description: "user denied the consent"}))
+		}
+		return
+	}
+
+	if c.a.ViewEngine() == nil {
+		c.Log().Error("Auth server consent screen requires view engine")
+		c.replyError(&authserver.Error{Code: "server_error", Status: http.StatusInternalServerError})
+		return
+	}
+	c.Reply().HTMLf(c.a.Config().StringDefault("security.auth_server.consent_view", authServerDefaultConsent), Data{
+		"AuthorizeRequest": ar,
+		"Client":           ar.Client,
+		"Scopes":           ar.Scopes,
+	})
+}
+
+// Token method handles the token endpoint.
+func (c *authServerController) Token() {
+	tr, e := c.a.AuthServer().Token(c.Req.Unwrap())
+	if e != nil {
+		c.Log().Warnf("Auth server token request: %v", e)
+		c.replyError(e)
+		return
+	}
+	c.Reply().Header(ahttp.HeaderCacheControl, "no-store").JSON(tr)
+}
+
+// Introspect method handles the token introspection endpoint.
+func (c *authServerController) Introspect() {
+	ti, e := c.a.AuthServer().Introspect(c.Req.Unwrap())
+	if e != nil {
+		c.replyError(e)
+		return
+	}
+	c.Reply().JSON(ti)
+}
+
+// JWKS method publishes the token signing public key.
+func (c *authServerController) JWKS() {
+	c.Reply().JSON(c.a.AuthServer().JWKS())
+}
+
+// Discovery method serves the OpenID Connect discovery metadata, it's
+// composed from configured issuer, not from the request host.
+func (c *authServerController) Discovery() {
+	c.Reply().JSON(c.a.AuthServer().Metadata(authServerPathPrefix(c.a)))
+}
+
+func (c *authServerController) replyError(e *authserver.Error) {
+	if e.Status == http.StatusUnauthorized {
+		c.Reply().Header(ahttp.HeaderWWWAuthenticate, `Basic realm="oauth2"`)
+	}
+	c.Reply().Status(e.Status).Header(ahttp.HeaderCacheControl, "no-store").JSON(e)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initAuthServer() error {
+	if !a.Config().BoolDefault("security.auth_server.enable", false) {
+		return nil
+	}
+
+	as, err := authserver.New(a.Config())
+	if err != nil {
+		return err
+	}
+	a.authServer = as
+	return nil
+}
+
+// addAuthServerRoutes method adds authorization server endpoints into all
+// domains if it's enabled.
+func (a *Application) addAuthServerRoutes(rtr *router.Router) error {
+	if a.authServer == nil {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(authServerTarget) == nil {
+		a.AddController((*authServerController)(nil), []*ainsp.Method{
+			{Name: "Authorize"}, {Name: "Token"}, {Name: "Introspect"},
+			{Name: "JWKS"}, {Name: "Discovery"},
+		})
+	}
+
+	prefix := authServerPathPrefix(a)
+	auth := a.Config().StringDefault("security.auth_server.auth", "authenticated")
+	routes := []*router.Route{
+		{Name: "authorize", Path: prefix + "/authorize", Method: ahttp.MethodGet, Action: "Authorize", Auth: auth},
+		{Name: "authorize_consent", Path: prefix + "/authorize", Method: ahttp.MethodPost, Action: "Authorize", Auth: auth, IsAntiCSRFCheck: true},
+		{Name: "token", Path: prefix + "/token", Method: ahttp.MethodPost, Action: "Token", Auth: "anonymous"},
+		{Name: "introspect", Path: prefix + "/introspect", Method: ahttp.MethodPost, Action: "Introspect", Auth: "anonymous"},
+		{Name: "jwks", Path: prefix + "/jwks", Method: ahttp.MethodGet, Action: "JWKS", Auth: "anonymous"},
+		{Name: "discovery", Path: authServerDiscoveryPath, Method: ahttp.MethodGet, Action: "Discovery", Auth: "anonymous"},
+	}
+	for _, d := range rtr.Domains {
+		for _, r := range routes {
+			rt := *r
+			rt.Name = authServerRouteNamePrefix + r.Name + "__aah"
+			rt.Target = authServerTarget
+			if rt.Method == ahttp.MethodPost {
+				rt.MaxBodySize = authServerMaxBodySize
+			}
+			if err := d.AddRoute(&rt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func authServerPathPrefix(a *Application) string {
+	return strings.TrimRight(a.Config().StringDefault("security.auth_server.path_prefix", authServerDefaultPathPrefix), "/")
+}
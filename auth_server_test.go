@@ -0,0 +1,135 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security/authserver"
+	"aahframe.work/security/scheme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthServerEndpoints(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Auth Server]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    basic_auth {
+		      scheme = "basic"
+		    }
+		  }
+		  auth_server {
+		    enable = true
+		    issuer = "` + ts.URL + `"
+		    auth = "basic_auth"
+		    clients {
+		      reports_app {
+		        secret = "e0f4b2c9a1d84e6f"
+		        redirect_uris = ["https://reports.example.com/callback"]
+		        grant_types = ["authorization_code", "client_credentials"]
+		        scopes = ["orders:read"]
+		        trusted = true
+		      }
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initSecurity())
+	assert.NotNil(t, ts.app.AuthServer())
+
+	basicAuth := ts.app.SecurityManager().AuthScheme("basic_auth").(*scheme.BasicAuth)
+	assert.Nil(t, basicAuth.SetAuthenticator(&testBasicAuth{}))
+	assert.Nil(t, basicAuth.SetAuthorizer(&testBasicAuth{}))
+
+	assert.Nil(t, ts.app.addAuthServerRoutes(ts.app.Router()))
+	domain := ts.app.Router().Lookup("localhost:8080")
+	assert.NotNil(t, domain.LookupByName("auth_server_token__aah"))
+
+	httpClient := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	decode := func(resp *http.Response) map[string]interface{} {
+		m := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal([]byte(responseBody(resp)), &m))
+		return m
+	}
+
+	// discovery and jwks
+	resp, err := httpClient.Get(ts.URL + "/.well-known/openid-configuration")
+	assert.Nil(t, err)
+	md := decode(resp)
+	assert.Equal(t, ts.URL, md["issuer"])
+	assert.Equal(t, ts.URL+"/oauth2/token", md["token_endpoint"])
+
+	resp, err = httpClient.Get(ts.URL + "/oauth2/jwks")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(decode(resp)["keys"].([]interface{})))
+
+	// client credentials
+	resp, err = httpClient.PostForm(ts.URL+"/oauth2/token", url.Values{"grant_type": {"client_credentials"},
+		"client_id": {"reports_app"}, "client_secret": {"e0f4b2c9a1d84e6f"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "no-store", resp.Header.Get(ahttp.HeaderCacheControl))
+	token := decode(resp)
+	assert.Equal(t, "orders:read", token["scope"])
+
+	resp, err = httpClient.PostForm(ts.URL+"/oauth2/token", url.Values{"grant_type": {"client_credentials"}})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "invalid_client", decode(resp)["error"])
+
+	req, _ := http.NewRequest(ahttp.MethodPost, ts.URL+"/oauth2/introspect",
+		strings.NewReader(url.Values{"token": {token["access_token"].(string)}}.Encode()))
+	req.Header.Set(ahttp.HeaderContentType, "application/x-www-form-urlencoded")
+	req.SetBasicAuth("reports_app", "e0f4b2c9a1d84e6f")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, true, decode(resp)["active"])
+
+	// authorize, trusted client skips consent
+	authorizeURL := ts.URL + "/oauth2/authorize?response_type=code&client_id=reports_app&state=af0ifjsldkj"
+	resp, err = httpClient.Get(authorizeURL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ = http.NewRequest(ahttp.MethodGet, authorizeURL, nil)
+	req.SetBasicAuth("jeeva", "welcome123")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	u, _ := url.Parse(resp.Header.Get(ahttp.HeaderLocation))
+	assert.Equal(t, "reports.example.com", u.Host)
+	assert.Equal(t, "af0ifjsldkj", u.Query().Get("state"))
+
+	req, _ = http.NewRequest(ahttp.MethodPost, ts.URL+"/oauth2/token", strings.NewReader(url.Values{
+		"grant_type": {authserver.GrantAuthorizationCode}, "code": {u.Query().Get("code")}}.Encode()))
+	req.Header.Set(ahttp.HeaderContentType, "application/x-www-form-urlencoded")
+	req.SetBasicAuth("reports_app", "e0f4b2c9a1d84e6f")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, decode(resp)["access_token"])
+
+	// invalid redirect uri is not redirected
+	req, _ = http.NewRequest(ahttp.MethodGet, authorizeURL+"&redirect_uri=https://evil.example.com", nil)
+	req.SetBasicAuth("jeeva", "welcome123")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
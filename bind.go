@@ -31,6 +31,14 @@ var (
 	emptyArg = make([]reflect.Value, 0)
 )
 
+// ContextualBinder func type is used to bind the action parameter value from
+// request context, for e.g.: current user from session, tenant, parsed header
+// value, etc. The key is the action parameter name.
+//
+// If binder returns `*aah.Error` then it's used as-is for reply, otherwise
+// any error replies HTTP 400 Bad Request. See `Application.AddContextualBinder`.
+type ContextualBinder func(ctx *Context, key string) (interface{}, error)
+
 type requestParser func(ctx *Context) flowResult
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -41,13 +49,27 @@ type requestParser func(ctx *Context) flowResult
 // parameters (Path, Form, Query, Multipart) stores into context. Request
 // params are made available in View via template functions.
 func BindMiddleware(ctx *Context, m *Middleware) {
+	// Request body is forwarded as-is for reverse proxy route
+	if ctx.route != nil && ctx.route.IsProxy() {
+		if ctx.route.MaxBodySize > 0 {
+			ctx.Req.Unwrap().Body = http.MaxBytesReader(ctx.Res, ctx.Req.Body(), ctx.route.MaxBodySize)
+		}
+		m.Next(ctx)
+		return
+	}
+
+	sw := ctx.startTiming(TimingBinding)
+	defer sw.Stop()
+
 	if ctx.a.I18n() != nil {
 		// i18n locale HTTP header `Accept-Language` value override via
-		// Path Variable and URL Query Param (config i18n { param_name { ... } }).
+		// Path Variable, URL Query Param (config i18n { param_name { ... } })
+		// and route locale prefix (routes.conf domain `locale_prefix`).
 		// Note: Query parameter takes precedence of all.
 		if locale := firstNonZeroString(
 			ctx.Req.QueryValue(ctx.a.bindMgr.keyQueryParamName),
-			ctx.Req.PathValue(ctx.a.bindMgr.keyPathParamName)); len(locale) > 0 {
+			ctx.Req.PathValue(ctx.a.bindMgr.keyPathParamName),
+			ctx.RouteLocale()); len(locale) > 0 {
 			ctx.Req.SetLocale(ahttp.NewLocale(locale))
 		}
 	}
@@ -84,6 +106,13 @@ func BindMiddleware(ctx *Context, m *Middleware) {
 			ctx.Set(keyAahRequestBodyBuf, reqBuf)
 		}
 
+		// Buffer the request body of route `raw_body = true` so that it's
+		// available via `ctx.Req.RawBody()` after the binding, for e.g.:
+		// inbound webhooks `ctx.Req.VerifyWebhook`.
+		if ctx.route.RawBody {
+			ctx.Req.BufferBody()
+		}
+
 		// Parse request content by Content-Type
 		if parser, found := ctx.a.bindMgr.requestParsers[ctx.Req.ContentType().Mime]; found {
 			if res := parser(ctx); res == flowAbort {
@@ -92,6 +121,7 @@ func BindMiddleware(ctx *Context, m *Middleware) {
 		}
 	}
 
+	sw.Stop()
 	m.Next(ctx)
 }
 
@@ -107,7 +137,8 @@ func (a *Application) initBind() error {
 		keyQueryParamName:         cfg.StringDefault("i18n.param_name.query", keyOverrideI18nName),
 		contentNegotiationEnabled: cfg.BoolDefault("request.content_negotiation.enable", false),
 		requestParsers:            make(map[string]requestParser),
-		payloadSupported:          regexp.MustCompile(`(POST|PUT|DELETE)`),
+		payloadSupported:          regexp.MustCompile(`(POST|PUT|PATCH|DELETE)`),
+		redirectBack:              cfg.BoolDefault("request.auto_bind.redirect_back", false),
 	}
 
 	// Content Negotitaion, GitHub #75
@@ -136,6 +167,8 @@ func (a *Application) initBind() error {
 	// Auto Parse and Bind, GitHub #26
 	bindMgr.requestParsers[ahttp.ContentTypeMultipartForm.Mime] = multipartFormParser
 	bindMgr.requestParsers[ahttp.ContentTypeForm.Mime] = formParser
+	bindMgr.requestParsers[ahttp.ContentTypeJSONPatch.Mime] = jsonPatchParser
+	bindMgr.requestParsers[ahttp.ContentTypeMergePatch.Mime] = mergePatchParser
 
 	bindMgr.autobindPriority = reverseSlice(strings.Split(cfg.StringDefault("request.auto_bind.priority", "PFQ"), ""))
 	timeFormats, found := cfg.StringList("format.time")
@@ -153,6 +186,13 @@ func (a *Application) initBind() error {
 	return nil
 }
 
+func (a *Application) contextualBinder(typ reflect.Type) (ContextualBinder, bool) {
+	a.RLock()
+	defer a.RUnlock()
+	binder, found := a.binders[typ]
+	return binder, found
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Bind Manager
 //______________________________________________________________________________
@@ -166,6 +206,7 @@ type bindManager struct {
 	autobindPriority          []string
 	requestParsers            map[string]requestParser
 	payloadSupported          *regexp.Regexp
+	redirectBack              bool
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -186,6 +227,28 @@ func formParser(ctx *Context) flowResult {
 	return flowCont
 }
 
+// jsonPatchParser parses the JSON Patch document, malformed document replies
+// HTTP 400 Bad Request. Parsed operations available via `ctx.Req.JSONPatch()`.
+func jsonPatchParser(ctx *Context) flowResult {
+	if _, err := ctx.Req.JSONPatch(); err != nil {
+		ctx.Log().Errorf("Unable to parse json patch: %s", err)
+		ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err))
+		return flowAbort
+	}
+	return flowCont
+}
+
+// mergePatchParser reads the JSON Merge Patch document, malformed document
+// replies HTTP 400 Bad Request. See `ctx.Req.ApplyMergePatch`.
+func mergePatchParser(ctx *Context) flowResult {
+	if _, err := ctx.Req.MergePatch(); err != nil {
+		ctx.Log().Errorf("Unable to parse json merge patch: %s", err)
+		ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err))
+		return flowAbort
+	}
+	return flowCont
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Context - Action Parameters Auto Parse
 //______________________________________________________________________________
@@ -201,16 +264,32 @@ func (ctx *Context) parseParameters() ([]reflect.Value, *Error) {
 	// Parse and Bind parameters
 	params := ctx.createParams()
 	var err error
+	var sourced bool
 	actionArgs := make([]reflect.Value, paramCnt)
 	for idx, val := range ctx.action.Parameters {
+		if binder, found := ctx.a.contextualBinder(val.Type); found {
+			result, err := ctx.bindContextual(binder, val.Name, val.Type)
+			if err != nil {
+				return nil, err
+			}
+			actionArgs[idx] = result
+			continue
+		}
+
 		var result reflect.Value
 		if vpFn, found := valpar.ValueParser(val.Type); found {
 			result, err = vpFn(val.Name, val.Type, params)
 		} else if val.Kind == reflect.Struct {
+			if !sourced {
+				ctx.addSourceParams(params)
+				sourced = true
+			}
 			ct := ctx.Req.ContentType().Mime
 			if ct == ahttp.ContentTypeJSON.Mime || ct == ahttp.ContentTypeXML.Mime ||
 				ct == ahttp.ContentTypeJSONText.Mime || ct == ahttp.ContentTypeXMLText.Mime {
-				result, err = valpar.Body(ct, ctx.Req.Body(), val.Type)
+				if result, err = valpar.Body(ct, ctx.Req.Body(), val.Type); err == nil {
+					err = valpar.Sources(result, params)
+				}
 			} else {
 				result, err = valpar.Struct("", val.Type, params)
 			}
@@ -230,6 +309,9 @@ func (ctx *Context) parseParameters() ([]reflect.Value, *Error) {
 			if errs, _ := ctx.a.Validate(result.Interface()); errs != nil {
 				ctx.Log().Errorf("Param validation failed [name: %s, type: %s], Validation Errors:\n%v",
 					val.Name, val.Type, errs.Error())
+				if ctx.a.bindMgr.redirectBack && isFormContentType(ctx.Req) {
+					return nil, newErrorWithData(ErrValidation, http.StatusBadRequest, valpar.NewErrors(val.Type, errs))
+				}
 				return nil, newErrorWithData(ErrValidation, http.StatusBadRequest, errs)
 			}
 		}
@@ -263,6 +345,49 @@ func (ctx *Context) createParams() url.Values {
 	return params
 }
 
+// bindContextual method binds the action parameter value using given
+// contextual binder and verifies the value type with parameter type.
+func (ctx *Context) bindContextual(binder ContextualBinder, name string, typ reflect.Type) (reflect.Value, *Error) {
+	v, err := binder(ctx, name)
+	if err != nil {
+		ctx.Log().Errorf("Contextual binder failed [param: %s, type: %s]: %v", name, typ, err)
+		if e, ok := err.(*Error); ok {
+			return reflect.Value{}, e
+		}
+		return reflect.Value{}, newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err)
+	}
+
+	if v == nil {
+		return reflect.Zero(typ), nil
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(typ) {
+		ctx.Log().Errorf("Contextual binder value type '%s' is not assignable to param type [param: %s, type: %s]",
+			rv.Type(), name, typ)
+		return reflect.Value{}, newError(ErrInvalidRequestParameter, http.StatusInternalServerError)
+	}
+	return rv, nil
+}
+
+// addSourceParams method adds the request path, query, header and cookie
+// values with bind source prefix into given params, so that struct fields
+// could be bound from specific source, for e.g.: `bind:"h:X-Api-Key"`.
+// See `valpar.SourceKey`.
+func (ctx *Context) addSourceParams(params url.Values) {
+	for _, p := range ctx.Req.URLParams {
+		params.Set(valpar.SourcePath+":"+p.Key, p.Value)
+	}
+	for k, v := range ctx.Req.URL().Query() {
+		params[valpar.SourceQuery+":"+k] = v
+	}
+	for k, v := range ctx.Req.Header {
+		params[valpar.SourceHeader+":"+k] = v
+	}
+	for _, c := range ctx.Req.Cookies() {
+		params.Add(valpar.SourceCookie+":"+c.Name, c.Value)
+	}
+}
+
 func reverseSlice(s []string) []string {
 	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
 		s[i], s[j] = s[j], s[i]
@@ -15,9 +15,12 @@ import (
 	"time"
 
 	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
 	"aahframe.work/log"
+	"aahframe.work/router"
+	"aahframe.work/valpar"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +62,220 @@ func TestBindParamContentNegotiation(t *testing.T) {
 	assert.Equal(t, http.StatusNotAcceptable, ctx2.Reply().err.Code)
 }
 
+func TestBindParamSources(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	type listParams struct {
+		ID     int    `bind:"p:id"`
+		Page   int    `bind:"q:page"`
+		APIKey string `bind:"h:X-Api-Key" validate:"required"`
+		Hint   string `bind:"c:session_hint"`
+		Name   string `bind:"name" json:"name"`
+	}
+
+	newCtx := func(body string, apiKey string) *Context {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users/1001/items?page=2", strings.NewReader(body))
+		r.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+		if len(apiKey) > 0 {
+			r.Header.Set("X-Api-Key", apiKey)
+		}
+		r.AddCookie(&http.Cookie{Name: "session_hint", Value: "hint1"})
+		ctx := newContext(nil, r)
+		ctx.a = a
+		ctx.Req.URLParams = ahttp.URLParams{{Key: "id", Value: "1001"}}
+		ctx.action = &ainsp.Method{Name: "Items", Parameters: []*ainsp.Parameter{
+			{Name: "params", Type: reflect.TypeOf(listParams{}), Kind: reflect.Struct},
+		}}
+		return ctx
+	}
+
+	// mix of JSON body, path, query, header and cookie
+	args, err := newCtx(`{"name":"aah"}`, "key123").parseParameters()
+	assert.Nil(t, err)
+	p := args[0].Interface().(listParams)
+	assert.Equal(t, listParams{ID: 1001, Page: 2, APIKey: "key123", Hint: "hint1", Name: "aah"}, p)
+
+	// validation
+	_, err = newCtx(`{"name":"aah"}`, "").parseParameters()
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Reason)
+}
+
+func TestBindContextualBinder(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	type currentUser struct {
+		Email string
+	}
+	userType := reflect.TypeOf((*currentUser)(nil))
+	err := a.AddContextualBinder(userType, func(ctx *Context, key string) (interface{}, error) {
+		switch email := ctx.Req.Header.Get("X-User-Email"); email {
+		case "":
+			return nil, nil
+		case "blocked@example.com":
+			return nil, newError(ErrAccessDenied, http.StatusForbidden)
+		case "invalid":
+			return "invalid", nil
+		default:
+			return &currentUser{Email: email}, nil
+		}
+	})
+	assert.Nil(t, err)
+
+	err = a.AddContextualBinder(userType, func(ctx *Context, key string) (interface{}, error) { return nil, nil })
+	assert.Equal(t, "aah: contextual binder for type '*aah.currentUser' already exists", err.Error())
+	err = a.AddContextualBinder(nil, nil)
+	assert.Equal(t, "aah: type or contextual binder is nil", err.Error())
+
+	testcases := []struct {
+		label  string
+		email  string
+		user   *currentUser
+		reason error
+		code   int
+	}{
+		{label: "bound", email: "jeeva@example.com", user: &currentUser{Email: "jeeva@example.com"}},
+		{label: "nil value", email: ""},
+		{label: "aah error", email: "blocked@example.com", reason: ErrAccessDenied, code: http.StatusForbidden},
+		{label: "type mismatch", email: "invalid", reason: ErrInvalidRequestParameter, code: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/profile?page=2", nil)
+			r.Header.Set("X-User-Email", tc.email)
+			ctx := newContext(nil, r)
+			ctx.a = a
+			ctx.action = &ainsp.Method{Name: "Profile", Parameters: []*ainsp.Parameter{
+				{Name: "user", Type: userType, Kind: reflect.Ptr},
+				{Name: "page", Type: reflect.TypeOf(0), Kind: reflect.Int},
+			}}
+
+			args, err := ctx.parseParameters()
+			if tc.reason != nil {
+				assert.Equal(t, tc.reason, err.Reason)
+				assert.Equal(t, tc.code, err.Code)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.user, args[0].Interface())
+			assert.Equal(t, 2, args[1].Interface())
+		})
+	}
+}
+
+func TestBindParamOptional(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	r := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/users/1?age=", nil)
+	ctx := newContext(nil, r)
+	ctx.a = a
+	ctx.action = &ainsp.Method{Name: "Patch", Parameters: []*ainsp.Parameter{
+		{Name: "page", Type: reflect.TypeOf((*int)(nil)), Kind: reflect.Int},
+		{Name: "age", Type: reflect.TypeOf(valpar.OptionalInt{}), Kind: reflect.Struct},
+		{Name: "name", Type: reflect.TypeOf(valpar.OptionalString{}), Kind: reflect.Struct},
+	}}
+
+	args, err := ctx.parseParameters()
+	assert.Nil(t, err)
+	assert.True(t, args[0].IsNil())
+	assert.Equal(t, valpar.OptionalInt{Present: true, Null: true}, args[1].Interface())
+	assert.Equal(t, valpar.OptionalString{}, args[2].Interface())
+}
+
+func TestBindPatchContentTypes(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	testcases := []struct {
+		label, contentType, body string
+		code                     int
+	}{
+		{"json patch", ahttp.ContentTypeJSONPatch.Mime, `[{"op":"remove","path":"/name"}]`, 0},
+		{"malformed json patch", ahttp.ContentTypeJSONPatch.Mime, `[{"op":"add","path":"/name"}]`, http.StatusBadRequest},
+		{"merge patch", ahttp.ContentTypeMergePatch.Mime, `{"name":null}`, 0},
+		{"malformed merge patch", ahttp.ContentTypeMergePatch.Mime, `{"name"`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/users/1", strings.NewReader(tc.body))
+			r.Header.Set(ahttp.HeaderContentType, tc.contentType)
+			ctx := newContext(nil, r)
+			ctx.a = a
+			ctx.route = &router.Route{MaxBodySize: 1024}
+			nextCalled := false
+			BindMiddleware(ctx, &Middleware{next: func(ctx *Context, m *Middleware) { nextCalled = true }})
+			if tc.code > 0 {
+				assert.False(t, nextCalled)
+				assert.Equal(t, tc.code, ctx.Reply().Code)
+				return
+			}
+			assert.True(t, nextCalled)
+		})
+	}
+
+	// apply parsed patch
+	r := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/users/1", strings.NewReader(`{"name":"aah"}`))
+	ctx := newContext(nil, r)
+	user := &struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}{Name: "Jeeva", Email: "jeeva@example.com"}
+	assert.Nil(t, ctx.Req.ApplyMergePatch(user))
+	assert.Equal(t, "aah", user.Name)
+	assert.Equal(t, "jeeva@example.com", user.Email)
+}
+
+func TestBindRawBody(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	testcases := []struct {
+		label, contentType, body, rawBody string
+		route                             *router.Route
+	}{
+		{"form", ahttp.ContentTypeForm.Mime, "name=aah&lang=go", "", &router.Route{MaxBodySize: 1024}},
+		{"form raw body", ahttp.ContentTypeForm.Mime, "name=aah&lang=go", "name=aah&lang=go", &router.Route{MaxBodySize: 1024, RawBody: true}},
+		{"merge patch raw body", ahttp.ContentTypeMergePatch.Mime, `{"name":null}`, `{"name":null}`, &router.Route{MaxBodySize: 1024, RawBody: true}},
+		{"multipart", ahttp.ContentTypeMultipartForm.Mime + "; boundary=aah", "--aah--\r\n", "", &router.Route{MaxBodySize: 1024}},
+		{"multipart raw body", ahttp.ContentTypeMultipartForm.Mime + "; boundary=aah", "--aah--\r\n", "--aah--\r\n", &router.Route{MaxBodySize: 1024, RawBody: true}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "http://localhost:8080/records", strings.NewReader(tc.body))
+			r.Header.Set(ahttp.HeaderContentType, tc.contentType)
+			ctx := newContext(nil, r)
+			ctx.a = a
+			ctx.route = tc.route
+			var rawBody []byte
+			BindMiddleware(ctx, &Middleware{next: func(ctx *Context, m *Middleware) {
+				rawBody, _ = ctx.Req.RawBody()
+			}})
+			assert.Equal(t, tc.rawBody, string(rawBody))
+		})
+	}
+}
+
 func TestBindAddValueParser(t *testing.T) {
 	app := newApp()
 	err := app.AddValueParser(reflect.TypeOf(time.Time{}), func(key string, typ reflect.Type, params url.Values) (reflect.Value, error) {
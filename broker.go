@@ -0,0 +1,168 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"sort"
+
+	"aahframe.work/aruntime"
+	"aahframe.work/broker"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// BrokerManager method returns aah application broker manager. Broker
+// providers are added via `AddProvider` before the app initialization.
+func (a *Application) BrokerManager() *broker.Manager {
+	return a.brokerMgr
+}
+
+// Broker method returns the managed broker connection for the given name
+// otherwise nil. Brokers are declared in the `aah.conf` under
+// `broker { ... }`, provider specific settings goes along with it.
+//
+// 	broker {
+// 	  orders {
+// 	    provider = "nats"
+// 	    url = "nats://localhost:4222"
+// 	  }
+// 	}
+//
+// Publishing a message:
+//
+// 	err := aah.App().Broker("orders").Publish(&broker.Message{
+// 		Topic: "order.created",
+// 		Body:  b,
+// 	})
+//
+// Broker connections are drained and closed on app shutdown.
+func (a *Application) Broker(name string) broker.Broker {
+	return a.brokerMgr.Broker(name)
+}
+
+// OnMessage method registers the message handler for the given topic.
+// Optionally broker name can be supplied, default is the broker named
+// `default` or the only broker configured.
+//
+// Handler is invoked with panic recovery, panics and errors are logged with
+// app logger and returned to broker as an error. During app shutdown,
+// in-flight handlers are drained upto `server.timeout.grace_shutdown`.
+//
+// 	func init() {
+// 		aah.App().OnMessage("order.created", func(m *broker.Message) error {
+// 			// process the message
+// 			return nil
+// 		})
+// 	}
+func (a *Application) OnMessage(topic string, fn broker.Handler, brokerName ...string) {
+	mh := &messageHandler{topic: topic, fn: fn}
+	if len(brokerName) > 0 {
+		mh.brokerName = brokerName[0]
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if !a.settings.Initialized {
+		a.msgHandlers = append(a.msgHandlers, mh)
+		return
+	}
+	if err := a.subscribeMessage(mh); err != nil {
+		a.Log().Error(err)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+type messageHandler struct {
+	brokerName string
+	topic      string
+	fn         broker.Handler
+}
+
+func (a *Application) initBroker() error {
+	cfg := a.Config()
+	if err := a.BrokerManager().InitProviders(cfg, a.Log()); err != nil {
+		return err
+	}
+
+	names := cfg.KeysByPath("broker")
+	sort.Strings(names)
+	for _, name := range names {
+		keyPrefix := "broker." + name
+		bcfg := &broker.Config{
+			Name:         name,
+			ProviderName: cfg.StringDefault(keyPrefix+".provider", ""),
+			KeyPrefix:    keyPrefix,
+		}
+		if len(bcfg.ProviderName) == 0 {
+			return fmt.Errorf("aah: broker '%s' provider is required", name)
+		}
+		if err := a.BrokerManager().CreateBroker(bcfg); err != nil {
+			return fmt.Errorf("aah: broker '%s': %v", name, err)
+		}
+		a.Log().Debugf("Broker '%s' initialized with provider '%s'", name, bcfg.ProviderName)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	for _, mh := range a.msgHandlers {
+		if err := a.subscribeMessage(mh); err != nil {
+			return err
+		}
+	}
+	a.msgHandlers = nil
+	return nil
+}
+
+func (a *Application) subscribeMessage(mh *messageHandler) error {
+	name := mh.brokerName
+	if len(name) == 0 {
+		name = "default"
+		if names := a.BrokerManager().BrokerNames(); len(names) == 1 {
+			name = names[0]
+		}
+	}
+	if err := a.BrokerManager().Subscribe(name, mh.topic, a.wrapMessageHandler(name, mh)); err != nil {
+		return fmt.Errorf("aah: message handler for topic '%s': %v", mh.topic, err)
+	}
+	a.Log().Debugf("Broker '%s' message handler subscribed to topic '%s'", name, mh.topic)
+	return nil
+}
+
+// wrapMessageHandler method wraps the application message handler with
+// panic recovery and logging.
+func (a *Application) wrapMessageHandler(name string, mh *messageHandler) broker.Handler {
+	return func(m *broker.Message) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				st := aruntime.NewStacktrace(r, a.Config())
+				buf := acquireBuilder()
+				defer releaseBuilder(buf)
+				st.Print(buf)
+
+				a.Log().Errorf("Broker '%s': recovered from panic on topic '%s'", name, m.Topic)
+				a.Log().Error(buf.String())
+				a.reportPanic(nil, r, st)
+				err = fmt.Errorf("aah: message handler panic: %v", r)
+			}
+		}()
+
+		if err = mh.fn(m); err != nil {
+			a.Log().Errorf("Broker '%s': message handler on topic '%s': %v", name, m.Topic, err)
+		}
+		return
+	}
+}
+
+func (a *Application) closeBrokers() {
+	if err := a.BrokerManager().Close(a.settings.ShutdownGraceTimeout); err != nil {
+		a.Log().Error(err)
+	}
+}
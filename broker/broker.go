@@ -0,0 +1,268 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package broker provides the message broker integration points for aah
+// application, for e.g.: Kafka, NATS, AMQP, etc.
+//
+// Broker implementations are pluggable via `Provider` interface, same as
+// aah cache. Brokers are configured in the `aah.conf` under `broker { ... }`
+// and managed by aah application, connections are drained and closed on
+// app shutdown.
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+// Broker errors
+var (
+	ErrBrokerClosed = errors.New("aah/broker: broker closed")
+	ErrDraining     = errors.New("aah/broker: draining, message not accepted")
+	ErrDrainTimeout = errors.New("aah/broker: drain timeout, in-flight messages not completed")
+)
+
+type (
+	// Message struct represents the message published to or received from
+	// the broker.
+	Message struct {
+		Topic     string
+		Key       string
+		Header    map[string]string
+		Body      []byte
+		Timestamp time.Time
+	}
+
+	// Handler func type is used to handle the message received from the
+	// broker. Non-nil error indicates the message is not processed, broker
+	// implementation decides on redelivery (for e.g.: nack).
+	Handler func(m *Message) error
+
+	// Publisher interface represents the message publish operation.
+	Publisher interface {
+		// Publish method publishes the message to the topic `Message.Topic`.
+		Publish(m *Message) error
+	}
+
+	// Subscriber interface represents the message subscribe operation.
+	Subscriber interface {
+		// Subscribe method subscribes the handler to the given topic, handler
+		// is invoked for each message received on the topic.
+		Subscribe(topic string, h Handler) error
+	}
+
+	// Broker interface represents the broker connection managed by aah.
+	Broker interface {
+		Publisher
+		Subscriber
+
+		// Name method returns the broker name.
+		Name() string
+
+		// Close method stops the message consumption and closes the broker
+		// connection.
+		Close() error
+	}
+
+	// Provider interface represents broker provider implementation.
+	Provider interface {
+		// Init method invoked by aah broker manager on application start to
+		// initialize broker provider.
+		Init(name string, appCfg *config.Config, logger log.Loggerer) error
+
+		// Create method invoked by aah broker manager to create broker
+		// connection specific to provider.
+		Create(cfg *Config) (Broker, error)
+	}
+
+	// Config struct represents the broker configuration.
+	Config struct {
+		Name         string
+		ProviderName string
+
+		// KeyPrefix is the config path of broker, for e.g.: `broker.orders`.
+		// Provider reads its specific configuration under it.
+		KeyPrefix string
+	}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package exported methods
+//______________________________________________________________________________
+
+// NewManager method returns new broker Manager.
+func NewManager() *Manager {
+	return &Manager{
+		brokers:   make(map[string]Broker),
+		providers: make(map[string]Provider),
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Manager type and its exported methods
+//______________________________________________________________________________
+
+// Manager struct represents the aah broker manager. It keeps track of
+// in-flight messages of subscribed handlers for graceful drain.
+type Manager struct {
+	mu        sync.RWMutex
+	brokers   map[string]Broker
+	providers map[string]Provider
+
+	dmu      sync.RWMutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// AddProvider method adds given provider by name. If provider name exists
+// it return an error otherwise nil.
+func (m *Manager) AddProvider(name string, provider Provider) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, f := m.providers[name]; f {
+		return fmt.Errorf("aah/broker: provider '%s' exists", name)
+	}
+	m.providers[name] = provider
+	return nil
+}
+
+// InitProviders method initializes the broker providers.
+func (m *Manager) InitProviders(appCfg *config.Config, logger log.Loggerer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for n, p := range m.providers {
+		if err := p.Init(n, appCfg, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Provider method returns provider by given name if exists otherwise nil.
+func (m *Manager) Provider(name string) Provider {
+	m.mu.RLock()
+	p := m.providers[name]
+	m.mu.RUnlock()
+	return p
+}
+
+// ProviderNames returns all provider names from broker manager.
+func (m *Manager) ProviderNames() []string {
+	var names []string
+	m.mu.RLock()
+	for n := range m.providers {
+		names = append(names, n)
+	}
+	m.mu.RUnlock()
+	return names
+}
+
+// CreateBroker method creates new broker connection in the broker manager
+// for configuration.
+func (m *Manager) CreateBroker(cfg *Config) error {
+	if len(cfg.Name) == 0 || len(cfg.ProviderName) == 0 {
+		return fmt.Errorf("aah/broker: name and provider name is required")
+	}
+
+	p := m.Provider(cfg.ProviderName)
+	if p == nil {
+		return fmt.Errorf("aah/broker: provider '%s' not exists", cfg.ProviderName)
+	}
+	b, err := p.Create(cfg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.brokers[cfg.Name] = b
+	m.mu.Unlock()
+	return nil
+}
+
+// Broker method returns broker by given name if exists otherwise nil.
+func (m *Manager) Broker(name string) Broker {
+	m.mu.RLock()
+	b := m.brokers[name]
+	m.mu.RUnlock()
+	return b
+}
+
+// BrokerNames method returns all broker names in sorted order.
+func (m *Manager) BrokerNames() []string {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.brokers))
+	for n := range m.brokers {
+		names = append(names, n)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Subscribe method subscribes the handler to the topic on the given broker.
+// Handler invocations are tracked for graceful drain, once the drain
+// started handler is not invoked and `ErrDraining` is returned to broker.
+func (m *Manager) Subscribe(brokerName, topic string, h Handler) error {
+	b := m.Broker(brokerName)
+	if b == nil {
+		return fmt.Errorf("aah/broker: broker '%s' not exists", brokerName)
+	}
+	return b.Subscribe(topic, m.track(h))
+}
+
+// Close method drains the in-flight messages upto given timeout and then
+// closes all the broker connections. It returns `ErrDrainTimeout` if
+// in-flight messages are not completed within timeout, however broker
+// connections are closed.
+func (m *Manager) Close(timeout time.Duration) error {
+	m.dmu.Lock()
+	m.draining = true
+	m.dmu.Unlock()
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		m.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		err = ErrDrainTimeout
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for n, b := range m.brokers {
+		if er := b.Close(); er != nil && err == nil {
+			err = fmt.Errorf("aah/broker: broker '%s' close: %v", n, er)
+		}
+		delete(m.brokers, n)
+	}
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Manager unexported methods
+//______________________________________________________________________________
+
+func (m *Manager) track(h Handler) Handler {
+	return func(msg *Message) error {
+		m.dmu.RLock()
+		if m.draining {
+			m.dmu.RUnlock()
+			return ErrDraining
+		}
+		m.inflight.Add(1)
+		m.dmu.RUnlock()
+		defer m.inflight.Done()
+		return h(msg)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerManager(t *testing.T) {
+	mgr := NewManager()
+	assert.Nil(t, mgr.AddProvider("provider1", &dummyProvider{}))
+	assert.Equal(t, errors.New("aah/broker: provider 'provider1' exists"),
+		mgr.AddProvider("provider1", &dummyProvider{}))
+
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	assert.Nil(t, mgr.InitProviders(config.NewEmpty(), l))
+	assert.NotNil(t, mgr.Provider("provider1"))
+	assert.Equal(t, []string{"provider1"}, mgr.ProviderNames())
+
+	assert.Equal(t, errors.New("aah/broker: name and provider name is required"),
+		mgr.CreateBroker(&Config{Name: "orders"}))
+	assert.Equal(t, errors.New("aah/broker: provider 'provider2' not exists"),
+		mgr.CreateBroker(&Config{Name: "orders", ProviderName: "provider2"}))
+	assert.Equal(t, errors.New("aah/broker: broker create error for failed"),
+		mgr.CreateBroker(&Config{Name: "failed", ProviderName: "provider1"}))
+
+	assert.Nil(t, mgr.CreateBroker(&Config{Name: "orders", ProviderName: "provider1"}))
+	assert.Nil(t, mgr.CreateBroker(&Config{Name: "audit", ProviderName: "provider1"}))
+	assert.Equal(t, []string{"audit", "orders"}, mgr.BrokerNames())
+	assert.Equal(t, "orders", mgr.Broker("orders").Name())
+	assert.Nil(t, mgr.Broker("unknown"))
+
+	assert.Equal(t, errors.New("aah/broker: broker 'unknown' not exists"),
+		mgr.Subscribe("unknown", "order.created", func(m *Message) error { return nil }))
+}
+
+func TestBrokerManagerDrain(t *testing.T) {
+	mgr := NewManager()
+	_ = mgr.AddProvider("provider1", &dummyProvider{})
+	assert.Nil(t, mgr.CreateBroker(&Config{Name: "orders", ProviderName: "provider1"}))
+	b := mgr.Broker("orders").(*dummyBroker)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	assert.Nil(t, mgr.Subscribe("orders", "order.created", func(m *Message) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	assert.Nil(t, mgr.Subscribe("orders", "order.updated", func(m *Message) error { return nil }))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Nil(t, b.Publish(&Message{Topic: "order.created"}))
+	}()
+	<-started
+
+	closed := make(chan error)
+	go func() { closed <- mgr.Close(time.Second) }()
+
+	// wait for drain to start, new messages are rejected
+	for i := 0; i < 500; i++ {
+		if err := b.Publish(&Message{Topic: "order.updated"}); err == ErrDraining {
+			break
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.Equal(t, ErrDraining, b.Publish(&Message{Topic: "order.updated"}))
+	assert.False(t, b.isClosed())
+
+	close(release)
+	wg.Wait()
+	assert.Nil(t, <-closed)
+	assert.True(t, b.isClosed())
+	assert.Equal(t, 0, len(mgr.BrokerNames()))
+
+	// drain timeout
+	mgr = NewManager()
+	_ = mgr.AddProvider("provider1", &dummyProvider{})
+	_ = mgr.CreateBroker(&Config{Name: "orders", ProviderName: "provider1"})
+	b = mgr.Broker("orders").(*dummyBroker)
+	started, block := make(chan struct{}), make(chan struct{})
+	defer close(block)
+	_ = mgr.Subscribe("orders", "order.created", func(m *Message) error {
+		close(started)
+		<-block
+		return nil
+	})
+	go func() { _ = b.Publish(&Message{Topic: "order.created"}) }()
+	<-started
+	assert.Equal(t, ErrDrainTimeout, mgr.Close(10*time.Millisecond))
+	assert.True(t, b.isClosed())
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Test types
+//______________________________________________________________________________
+
+type dummyProvider struct{}
+
+func (p *dummyProvider) Init(name string, appCfg *config.Config, logger log.Loggerer) error {
+	return nil
+}
+
+func (p *dummyProvider) Create(cfg *Config) (Broker, error) {
+	if cfg.Name == "failed" {
+		return nil, errors.New("aah/broker: broker create error for failed")
+	}
+	return &dummyBroker{name: cfg.Name, subs: make(map[string]Handler)}, nil
+}
+
+// dummyBroker delivers the message synchronously.
+type dummyBroker struct {
+	mu     sync.Mutex
+	name   string
+	subs   map[string]Handler
+	closed bool
+}
+
+func (b *dummyBroker) Name() string { return b.name }
+
+func (b *dummyBroker) Publish(m *Message) error {
+	b.mu.Lock()
+	h := b.subs[m.Topic]
+	b.mu.Unlock()
+	if h == nil {
+		return nil
+	}
+	return h(m)
+}
+
+func (b *dummyBroker) Subscribe(topic string, h Handler) error {
+	b.mu.Lock()
+	b.subs[topic] = h
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *dummyBroker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *dummyBroker) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
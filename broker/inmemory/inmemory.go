@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package inmemory is the in-process broker provider for aah application.
+// It's suitable for development, testing and single instance deployment,
+// messages are not persisted.
+//
+// 	func init() {
+// 		aah.App().BrokerManager().AddProvider("inmemory", new(inmemory.Provider))
+// 	}
+package inmemory
+
+import (
+	"sync"
+	"time"
+
+	"aahframe.work/broker"
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+var _ broker.Broker = (*inmemoryBroker)(nil)
+
+// Provider struct represents the in-memory broker provider.
+type Provider struct {
+	name   string
+	logger log.Loggerer
+}
+
+// Init method initializes the in-memory broker provider.
+func (p *Provider) Init(name string, _ *config.Config, logger log.Loggerer) error {
+	p.name = name
+	p.logger = logger
+	return nil
+}
+
+// Create method creates new in-memory broker.
+func (p *Provider) Create(cfg *broker.Config) (broker.Broker, error) {
+	b := &inmemoryBroker{
+		name:   cfg.Name,
+		logger: p.logger,
+		subs:   make(map[string][]broker.Handler),
+	}
+	p.logger.Infof("Broker: '%s' created using provider '%s'", cfg.Name, p.name)
+	return b, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// In-memory broker
+//______________________________________________________________________________
+
+// inmemoryBroker delivers the published message to topic subscribers
+// asynchronously.
+type inmemoryBroker struct {
+	name    string
+	logger  log.Loggerer
+	mu      sync.RWMutex
+	subs    map[string][]broker.Handler
+	closed  bool
+	pending sync.WaitGroup
+}
+
+func (b *inmemoryBroker) Name() string {
+	return b.name
+}
+
+func (b *inmemoryBroker) Publish(m *broker.Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return broker.ErrBrokerClosed
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+
+	for _, h := range b.subs[m.Topic] {
+		b.pending.Add(1)
+		go b.deliver(h, copyMessage(m))
+	}
+	return nil
+}
+
+func (b *inmemoryBroker) Subscribe(topic string, h broker.Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return broker.ErrBrokerClosed
+	}
+	b.subs[topic] = append(b.subs[topic], h)
+	return nil
+}
+
+func (b *inmemoryBroker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.subs = make(map[string][]broker.Handler)
+	b.mu.Unlock()
+	b.pending.Wait()
+	return nil
+}
+
+func (b *inmemoryBroker) deliver(h broker.Handler, m *broker.Message) {
+	defer b.pending.Done()
+	if err := h(m); err != nil {
+		b.logger.Debugf("Broker: '%s' message on topic '%s' not processed: %v", b.name, m.Topic, err)
+	}
+}
+
+func copyMessage(m *broker.Message) *broker.Message {
+	c := *m
+	if m.Header != nil {
+		c.Header = make(map[string]string, len(m.Header))
+		for k, v := range m.Header {
+			c.Header[k] = v
+		}
+	}
+	c.Body = append([]byte(nil), m.Body...)
+	return &c
+}
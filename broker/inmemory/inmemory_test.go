@@ -0,0 +1,58 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package inmemory
+
+import (
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"aahframe.work/broker"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryBroker(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	p := new(Provider)
+	assert.Nil(t, p.Init("inmemory", config.NewEmpty(), l))
+	b, err := p.Create(&broker.Config{Name: "orders", ProviderName: "inmemory", KeyPrefix: "broker.orders"})
+	assert.Nil(t, err)
+	assert.Equal(t, "orders", b.Name())
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var received []*broker.Message
+	handler := func(m *broker.Message) error {
+		defer wg.Done()
+		mu.Lock()
+		received = append(received, m)
+		mu.Unlock()
+		return errors.New("not processed")
+	}
+	assert.Nil(t, b.Subscribe("order.created", handler))
+	assert.Nil(t, b.Subscribe("order.created", handler))
+
+	msg := &broker.Message{Topic: "order.created", Key: "1", Header: map[string]string{"k": "v"}, Body: []byte("order")}
+	wg.Add(2)
+	assert.Nil(t, b.Publish(msg))
+	assert.Nil(t, b.Publish(&broker.Message{Topic: "order.unknown"}))
+	wg.Wait()
+
+	assert.Equal(t, 2, len(received))
+	assert.False(t, msg.Timestamp.IsZero())
+	for _, m := range received {
+		assert.Equal(t, "order", string(m.Body))
+		assert.Equal(t, "v", m.Header["k"])
+		assert.False(t, m == msg)
+	}
+
+	assert.Nil(t, b.Close())
+	assert.Equal(t, broker.ErrBrokerClosed, b.Publish(msg))
+	assert.Equal(t, broker.ErrBrokerClosed, b.Subscribe("order.created", handler))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"aahframe.work/broker"
+	"aahframe.work/broker/inmemory"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerOnMessage(t *testing.T) {
+	a := newApp()
+	cfg, err := config.ParseString(`broker {
+  orders {
+    provider = "inmemory"
+  }
+  audit {
+    provider = "inmemory"
+  }
+}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	assert.Nil(t, a.initLog())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.settings.ShutdownGraceTimeout = time.Second
+	assert.Nil(t, a.BrokerManager().AddProvider("inmemory", new(inmemory.Provider)))
+
+	results := make(chan string, 3)
+	a.OnMessage("order.created", func(m *broker.Message) error {
+		results <- "orders:" + string(m.Body)
+		return nil
+	}, "orders")
+	a.OnMessage("order.failed", func(m *broker.Message) error {
+		defer func() { results <- "failed" }()
+		return errors.New("unable to process")
+	}, "orders")
+	a.OnMessage("order.panic", func(m *broker.Message) error {
+		defer func() { results <- "panic" }()
+		panic("message handler panic")
+	}, "orders")
+	assert.Equal(t, 3, len(a.msgHandlers))
+
+	assert.Nil(t, a.initBroker())
+	assert.Nil(t, a.msgHandlers)
+	assert.Equal(t, []string{"audit", "orders"}, a.BrokerManager().BrokerNames())
+	assert.Nil(t, a.Broker("unknown"))
+
+	b := a.Broker("orders")
+	assert.Nil(t, b.Publish(&broker.Message{Topic: "order.created", Body: []byte("1001")}))
+	assert.Equal(t, "orders:1001", <-results)
+	assert.Nil(t, b.Publish(&broker.Message{Topic: "order.failed"}))
+	assert.Equal(t, "failed", <-results)
+	assert.Nil(t, b.Publish(&broker.Message{Topic: "order.panic"}))
+	assert.Equal(t, "panic", <-results)
+
+	// wrapped handler returns error to broker
+	fn := a.wrapMessageHandler("orders", &messageHandler{topic: "order.panic", fn: func(m *broker.Message) error {
+		panic("message handler panic")
+	}})
+	assert.Equal(t, "aah: message handler panic: message handler panic",
+		fn(&broker.Message{Topic: "order.panic"}).Error())
+
+	// registered after init, no default broker
+	a.settings.Initialized = true
+	a.OnMessage("audit.log", func(m *broker.Message) error { return nil })
+	assert.Nil(t, a.msgHandlers)
+	assert.Equal(t, "aah: message handler for topic 'audit.log': aah/broker: broker 'default' not exists",
+		a.subscribeMessage(&messageHandler{topic: "audit.log"}).Error())
+	a.OnMessage("audit.log", func(m *broker.Message) error { return nil }, "audit")
+
+	a.closeBrokers()
+	assert.Nil(t, a.Broker("orders"))
+}
+
+func TestBrokerInitErrors(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`broker {
+  orders {
+    url = "nats://localhost:4222"
+  }
+}`)
+	assert.Nil(t, a.initLog())
+	assert.Equal(t, "aah: broker 'orders' provider is required", a.initBroker().Error())
+
+	a = newApp()
+	a.cfg, _ = config.ParseString(`broker {
+  orders {
+    provider = "nats"
+  }
+}`)
+	assert.Nil(t, a.initLog())
+	assert.Equal(t, "aah: broker 'orders': aah/broker: provider 'nats' not exists", a.initBroker().Error())
+
+	// single broker is default
+	a = newApp()
+	a.cfg, _ = config.ParseString(`broker {
+  orders {
+    provider = "inmemory"
+  }
+}`)
+	assert.Nil(t, a.initLog())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	_ = a.BrokerManager().AddProvider("inmemory", new(inmemory.Provider))
+	a.OnMessage("order.created", func(m *broker.Message) error { return nil })
+	assert.Nil(t, a.initBroker())
+
+	a = newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	a.OnMessage("order.created", func(m *broker.Message) error { return nil }, "orders")
+	assert.Equal(t, "aah: message handler for topic 'order.created': aah/broker: broker 'orders' not exists",
+		a.initBroker().Error())
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"aahframe.work/essentials"
+)
+
+var (
+	defaultBufferTiers = []int{4 << 10, 64 << 10, 512 << 10}
+	bufPool            = newBufferPool(defaultBufferTiers, 1<<20)
+)
+
+// PoolStats struct holds the aah buffer pool metrics since application start,
+// it could be used for tuning `runtime.pools` configuration.
+type PoolStats struct {
+	Gets     int64 `json:"gets"`
+	Puts     int64 `json:"puts"`
+	News     int64 `json:"news"`
+	Discards int64 `json:"discards"`
+}
+
+// PoolStats method returns the aah buffer pool metrics.
+func (a *Application) PoolStats() PoolStats {
+	return bufPool.stats()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initPools() error {
+	cfg := a.Config()
+	keyPrefix := "runtime.pools.buffer"
+
+	maxRetained, err := ess.StrToBytes(cfg.StringDefault(keyPrefix+".max_retained_size", "1mb"))
+	if err != nil || maxRetained <= 0 {
+		return fmt.Errorf("aah: '%s.max_retained_size' value is not a valid size unit", keyPrefix)
+	}
+
+	var tiers []int
+	if sizes, found := cfg.StringList(keyPrefix + ".tiers"); found {
+		for _, s := range sizes {
+			size, err := ess.StrToBytes(s)
+			if err != nil || size <= 0 || size > maxRetained {
+				return fmt.Errorf("aah: '%s.tiers' value '%s' is not a valid size unit or "+
+					"greater than 'max_retained_size'", keyPrefix, s)
+			}
+			tiers = append(tiers, int(size))
+		}
+	}
+	if len(tiers) == 0 {
+		tiers = defaultBufferTiers
+	}
+
+	bufPool.configure(tiers, int(maxRetained))
+	return nil
+}
+
+func acquireBuffer() *bytes.Buffer {
+	return bufPool.get()
+}
+
+func releaseBuffer(b *bytes.Buffer) {
+	if b != nil {
+		bufPool.put(b)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Buffer pool
+//______________________________________________________________________________
+
+// bufferPool is size-tiered pool of buffers, released buffer goes into the
+// tier by its capacity. Buffer grown beyond max retained capacity is discarded
+// so that large responses do not pin the memory.
+type bufferPool struct {
+	gets, puts, news, discards int64
+
+	mu          sync.RWMutex
+	tiers       []int
+	pools       []*sync.Pool
+	maxRetained int
+}
+
+func newBufferPool(tiers []int, maxRetained int) *bufferPool {
+	bp := &bufferPool{}
+	bp.configure(tiers, maxRetained)
+	return bp
+}
+
+func (bp *bufferPool) configure(tiers []int, maxRetained int) {
+	sorted := append([]int(nil), tiers...)
+	sort.Ints(sorted)
+	pools := make([]*sync.Pool, len(sorted))
+	for i := range pools {
+		pools[i] = &sync.Pool{}
+	}
+
+	bp.mu.Lock()
+	bp.tiers, bp.pools, bp.maxRetained = sorted, pools, maxRetained
+	bp.mu.Unlock()
+}
+
+// get method returns the buffer from smallest non-empty tier otherwise
+// new buffer.
+func (bp *bufferPool) get() *bytes.Buffer {
+	atomic.AddInt64(&bp.gets, 1)
+	bp.mu.RLock()
+	defer bp.mu.RUnlock()
+	for _, p := range bp.pools {
+		if b, ok := p.Get().(*bytes.Buffer); ok {
+			return b
+		}
+	}
+	atomic.AddInt64(&bp.news, 1)
+	return new(bytes.Buffer)
+}
+
+func (bp *bufferPool) put(b *bytes.Buffer) {
+	bp.mu.RLock()
+	defer bp.mu.RUnlock()
+	if b.Cap() > bp.maxRetained {
+		atomic.AddInt64(&bp.discards, 1)
+		return
+	}
+	b.Reset()
+	atomic.AddInt64(&bp.puts, 1)
+	bp.pools[bp.tierIndex(b.Cap())].Put(b)
+}
+
+// tierIndex method returns the largest tier index which size is less than
+// or equal to given capacity, smaller capacity goes into first tier.
+func (bp *bufferPool) tierIndex(capacity int) int {
+	i := sort.SearchInts(bp.tiers, capacity+1) - 1
+	if i < 0 {
+		return 0
+	}
+	return i
+}
+
+func (bp *bufferPool) stats() PoolStats {
+	return PoolStats{
+		Gets:     atomic.LoadInt64(&bp.gets),
+		Puts:     atomic.LoadInt64(&bp.puts),
+		News:     atomic.LoadInt64(&bp.news),
+		Discards: atomic.LoadInt64(&bp.discards),
+	}
+}
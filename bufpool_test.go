@@ -0,0 +1,87 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferPool(t *testing.T) {
+	bp := newBufferPool([]int{64 << 10, 1 << 10}, 128<<10)
+	assert.Equal(t, []int{1 << 10, 64 << 10}, bp.tiers)
+	assert.Equal(t, 0, bp.tierIndex(512))
+	assert.Equal(t, 0, bp.tierIndex(1<<10))
+	assert.Equal(t, 0, bp.tierIndex(32<<10))
+	assert.Equal(t, 1, bp.tierIndex(64<<10))
+	assert.Equal(t, 1, bp.tierIndex(128<<10))
+
+	b := bp.get()
+	assert.Equal(t, 0, b.Len())
+	b.WriteString("aah buffer pool")
+	bp.put(b)
+
+	// large buffer gets discarded
+	lb := bp.get()
+	lb.Grow(256 << 10)
+	bp.put(lb)
+
+	s := bp.stats()
+	assert.Equal(t, int64(2), s.Gets)
+	assert.Equal(t, int64(1), s.Puts)
+	assert.Equal(t, int64(1), s.Discards)
+	assert.True(t, s.News >= 1)
+
+	b = bp.get()
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestBufferPoolConfig(t *testing.T) {
+	defer bufPool.configure(defaultBufferTiers, 1<<20)
+
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initPools())
+	assert.Equal(t, defaultBufferTiers, bufPool.tiers)
+	assert.Equal(t, 1<<20, bufPool.maxRetained)
+
+	a.cfg, _ = config.ParseString(`runtime {
+  pools {
+    buffer {
+      tiers = ["16kb", "2kb"]
+      max_retained_size = "256kb"
+    }
+  }
+}`)
+	assert.Nil(t, a.initPools())
+	assert.Equal(t, []int{2 << 10, 16 << 10}, bufPool.tiers)
+	assert.Equal(t, 256<<10, bufPool.maxRetained)
+
+	before := a.PoolStats()
+	releaseBuffer(bytes.NewBuffer(make([]byte, 0, 512<<10)))
+	assert.Equal(t, before.Discards+1, a.PoolStats().Discards)
+
+	a.cfg, _ = config.ParseString(`runtime {
+  pools {
+    buffer {
+      max_retained_size = "none"
+    }
+  }
+}`)
+	assert.Equal(t, "aah: 'runtime.pools.buffer.max_retained_size' value is not a valid size unit", a.initPools().Error())
+
+	a.cfg, _ = config.ParseString(`runtime {
+  pools {
+    buffer {
+      tiers = ["4mb"]
+    }
+  }
+}`)
+	assert.Equal(t, "aah: 'runtime.pools.buffer.tiers' value '4mb' is not a valid size unit or "+
+		"greater than 'max_retained_size'", a.initPools().Error())
+}
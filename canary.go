@@ -0,0 +1,90 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"sync"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/router"
+)
+
+// CanaryStat struct holds the request metrics of canary route variant.
+type CanaryStat struct {
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+}
+
+// CanaryStats method returns the request metrics of canary routes by route
+// name and variant (`stable`, `canary`). Errors are the responses with status
+// code 5xx. It could be used to compare the canary with stable before
+// increasing the weight.
+func (a *Application) CanaryStats() map[string]map[string]CanaryStat {
+	a.canaryStats.Lock()
+	defer a.canaryStats.Unlock()
+	stats := make(map[string]map[string]CanaryStat, len(a.canaryStats.routes))
+	for name, variants := range a.canaryStats.routes {
+		stats[name] = make(map[string]CanaryStat, len(variants))
+		for v, s := range variants {
+			stats[name][v] = s
+		}
+	}
+	return stats
+}
+
+// CanaryVariant method returns the canary variant name (`stable`, `canary`)
+// of the current request if route has canary configured otherwise empty string.
+func (ctx *Context) CanaryVariant() string {
+	return ctx.canary
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type canaryStats struct {
+	sync.Mutex
+	routes map[string]map[string]CanaryStat
+}
+
+func (cs *canaryStats) add(routeName, variant string, statusCode int) {
+	cs.Lock()
+	defer cs.Unlock()
+	if cs.routes == nil {
+		cs.routes = make(map[string]map[string]CanaryStat)
+	}
+	if _, found := cs.routes[routeName]; !found {
+		cs.routes[routeName] = make(map[string]CanaryStat)
+	}
+	s := cs.routes[routeName][variant]
+	s.Requests++
+	if statusCode >= http.StatusInternalServerError {
+		s.Errors++
+	}
+	cs.routes[routeName][variant] = s
+}
+
+// canaryTarget method resolves the canary variant of the request and returns
+// the controller target and action name of the variant. Newly assigned
+// variant is made sticky via cookie.
+func (ctx *Context) canaryTarget(route *router.Route) (string, string) {
+	c := route.Canary
+	variant, assigned := c.Variant(ctx.Req.Unwrap())
+	if assigned {
+		ctx.Reply().Cookie(&http.Cookie{
+			Name:     c.Cookie,
+			Value:    variant,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   ctx.Req.Scheme == ahttp.SchemeHTTPS,
+		})
+	}
+	ctx.canary = variant
+	if variant == router.VariantCanary {
+		return c.Target, c.Action
+	}
+	return route.Target, route.Action
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryRoute(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	err := ts.app.Router().RootDomain().AddRoute(&router.Route{
+		Name:   "canary_text",
+		Path:   "/canary-text.html",
+		Method: ahttp.MethodGet,
+		Target: "testSiteController",
+		Action: "Text",
+		Canary: &router.Canary{Weight: 100, Target: "testSiteController", Action: "XML",
+			Cookie: "aah_canary_canary_text", Header: "X-Canary"},
+	})
+	assert.Nil(t, err)
+
+	// assigned by weight, sticky cookie is set
+	req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/canary-text.html", nil)
+	result := fireRequest(t, req)
+	assert.Equal(t, 200, result.StatusCode)
+	assert.True(t, strings.Contains(result.Body, "This is XML payload result"))
+	assert.True(t, strings.Contains(result.Header.Get(ahttp.HeaderSetCookie), "aah_canary_canary_text=canary"))
+
+	// sticky cookie
+	req, _ = http.NewRequest(ahttp.MethodGet, ts.URL+"/canary-text.html", nil)
+	req.AddCookie(&http.Cookie{Name: "aah_canary_canary_text", Value: "stable"})
+	result = fireRequest(t, req)
+	assert.True(t, strings.Contains(result.Body, "This is text render response"))
+	assert.False(t, strings.Contains(strings.Join(result.Header[ahttp.HeaderSetCookie], ";"), "aah_canary_canary_text"))
+
+	// forced via header
+	req, _ = http.NewRequest(ahttp.MethodGet, ts.URL+"/canary-text.html", nil)
+	req.Header.Set("X-Canary", "Stable")
+	result = fireRequest(t, req)
+	assert.True(t, strings.Contains(result.Body, "This is text render response"))
+
+	stats := ts.app.CanaryStats()
+	assert.Equal(t, CanaryStat{Requests: 1}, stats["canary_text"][router.VariantCanary])
+	assert.Equal(t, CanaryStat{Requests: 2}, stats["canary_text"][router.VariantStable])
+}
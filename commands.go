@@ -5,7 +5,10 @@
 package aah
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -19,6 +22,13 @@ import (
 	"aahframe.work/essentials"
 )
 
+// CommandMiddleware type is used to implement middleware for aah application
+// CLI commands, refer to `Application.AddCommandMiddleware`.
+type CommandMiddleware func(c *console.Context, next console.ActionFunc) error
+
+// builtinCommands are reserved command names of aah application binary.
+var builtinCommands = []string{"run", "vfs", "help", "openapi", "generate", "migrate", "routes", "config", "doctor", "console", "seed"}
+
 func (a *Application) initCli() {
 	bi := a.BuildInfo()
 	a.cli.Name = bi.BinaryName
@@ -30,15 +40,29 @@ func (a *Application) initCli() {
 	a.cli.Version = bi.Version
 	a.cli.Copyright = a.Config().StringDefault("copyright", "")
 	a.cli.Metadata["BuildTimestamp"] = bi.Timestamp
-	a.cli.Commands = append([]console.Command{a.cliCmdRun(), a.cliCmdVfs()}, a.cli.Commands...)
+	a.cli.Commands = append([]console.Command{a.cliCmdRun(), a.cliCmdVfs(), a.cliCmdOpenAPI(), a.cliCmdGenerate(), a.cliCmdMigrate(), a.cliCmdRoutes(), a.cliCmdConfig(), a.cliCmdDoctor(), a.cliCmdConsole(), a.cliCmdSeed()}, a.cli.Commands...)
 	a.cli.Commands = append(a.cli.Commands, a.cliCmdHelp())
+	a.cli.Commands = a.applyCmdMiddlewares(a.cli.Commands)
 	a.cli.HideHelp = true
 	a.cli.Flags = []console.Flag{
 		console.BoolFlag{
 			Name:  "help, h",
 			Usage: "Shows app help",
 		},
+		console.StringFlag{
+			Name:  "envprofile, e",
+			Usage: "Environment profile name to activate for any command, command flag takes precedence",
+		},
+		console.StringFlag{
+			Name:  "config, c",
+			Usage: "External config `FILE` for adding or overriding 'config/**/*.conf' values for any command",
+		},
+		console.StringFlag{
+			Name:  "log-level, l",
+			Usage: "Log level for any command (e.g: error, warn, info, debug, trace)",
+		},
 	}
+	a.cli.Before = a.applyCliGlobalFlags
 	a.cli.Action = func(c *console.Context) error {
 		if c.GlobalBool("help") || c.Bool("h") {
 			return console.ShowAppHelp(c)
@@ -61,6 +85,81 @@ func (a *Application) initCli() {
 	})
 }
 
+// applyCliGlobalFlags method applies the app global flags `--config`,
+// `--envprofile` and `--log-level` before any command runs.
+func (a *Application) applyCliGlobalFlags(c *console.Context) error {
+	if err := a.mergeExternalConfig(c.String("config")); err != nil {
+		return err
+	}
+	if envProfile := c.String("envprofile"); len(envProfile) > 0 {
+		a.Config().SetString("env.active", envProfile)
+	}
+	if level := c.String("log-level"); len(level) > 0 {
+		if err := a.SetLogLevel(level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeExternalConfig method merges the given external config file into
+// aah application config.
+func (a *Application) mergeExternalConfig(extCfgFile string) error {
+	if ess.IsStrEmpty(extCfgFile) {
+		return nil
+	}
+	cpath, err := filepath.Abs(extCfgFile)
+	if err != nil {
+		return fmt.Errorf("Unable to resolve external config: %s", extCfgFile)
+	}
+	extCfg, err := config.LoadFile(cpath)
+	if err != nil {
+		return fmt.Errorf("Unable to load external config, error: %s", err)
+	}
+	if err = a.Config().Merge(extCfg); err != nil {
+		return fmt.Errorf("Unable to merge external config into aah application[%s]: %s", a.Name(), err)
+	}
+	return nil
+}
+
+// applyCmdMiddlewares method wraps the command and its subcommands action
+// with registered command middlewares.
+func (a *Application) applyCmdMiddlewares(cmds []console.Command) []console.Command {
+	if len(a.cmdMiddlewares) == 0 {
+		return cmds
+	}
+	for i := range cmds {
+		cmds[i].Subcommands = a.applyCmdMiddlewares(cmds[i].Subcommands)
+		switch action := cmds[i].Action.(type) {
+		case func(*console.Context) error:
+			cmds[i].Action = a.wrapCmdMiddlewares(action)
+		case console.ActionFunc:
+			cmds[i].Action = a.wrapCmdMiddlewares(action)
+		}
+	}
+	return cmds
+}
+
+func (a *Application) wrapCmdMiddlewares(action console.ActionFunc) func(*console.Context) error {
+	next := action
+	for i := len(a.cmdMiddlewares) - 1; i >= 0; i-- {
+		mw, n := a.cmdMiddlewares[i], next
+		next = func(c *console.Context) error { return mw(c, n) }
+	}
+	return next
+}
+
+// cliEnvProfile method returns the environment profile name from command
+// flag `--envprofile`, if not set then from parent command or global flag.
+func cliEnvProfile(c *console.Context) string {
+	for ctx := c; ctx != nil; ctx = ctx.Parent() {
+		if ctx.IsSet("envprofile") {
+			return ctx.String("envprofile")
+		}
+	}
+	return c.String("envprofile")
+}
+
 func (a *Application) cliCmdHelp() console.Command {
 	return console.Command{
 		Name:      "help",
@@ -93,6 +192,10 @@ func (a *Application) cliCmdRun() console.Command {
 				Name:  "config, c",
 				Usage: "External config `FILE` for adding or overriding 'config/**/*.conf' values",
 			},
+			console.BoolFlag{
+				Name:  "verify-assets",
+				Usage: "Verifies the app files against integrity manifest of packaged app before start",
+			},
 			console.StringFlag{Name: "importpath", Hidden: true}, // For aah CLI purpose
 			console.StringFlag{Name: "proxyport", Hidden: true},  // For aah CLI purpose
 		},
@@ -100,22 +203,11 @@ func (a *Application) cliCmdRun() console.Command {
 			a.Log().Infof("aah framework v%s, requires >= go1.11", a.BuildInfo().AahVersion)
 
 			// External config file
-			extCfgFile := c.String("config")
-			if !ess.IsStrEmpty(extCfgFile) {
-				cpath, err := filepath.Abs(extCfgFile)
-				if err != nil {
-					return fmt.Errorf("Unable to resolve external config: %s", extCfgFile)
-				}
-				extCfg, err := config.LoadFile(cpath)
-				if err != nil {
-					return fmt.Errorf("Unable to load external config, error: %s", err)
-				}
-				if err = a.Config().Merge(extCfg); err != nil {
-					return fmt.Errorf("Unable to merge external config into aah application[%s]: %s", a.Name(), err)
-				}
+			if err := a.mergeExternalConfig(c.String("config")); err != nil {
+				return err
 			}
 
-			envProfile := c.String("envprofile")
+			envProfile := cliEnvProfile(c)
 			if !ess.IsStrEmpty(envProfile) {
 				a.Config().SetString("env.active", envProfile)
 			}
@@ -128,6 +220,13 @@ func (a *Application) cliCmdRun() console.Command {
 				return err
 			}
 
+			if c.Bool("verify-assets") {
+				if err := a.VFS().VerifyManifest(); err != nil {
+					return err
+				}
+				a.Log().Info("App files integrity verified")
+			}
+
 			// Diagnosis and Profiling
 			if a.Config().BoolDefault("runtime.diagnosis.enable", false) {
 				var err error
@@ -135,6 +234,8 @@ func (a *Application) cliCmdRun() console.Command {
 				if err != nil {
 					return err
 				}
+				a.diagnosis.AddStats("connections", func() interface{} { return a.ConnStats() })
+				a.diagnosis.AddStats("server_timing", func() interface{} { return a.ServerTimingStats() })
 				go a.diagnosis.Run()
 			}
 
@@ -209,6 +310,134 @@ func (a *Application) cliCmdVfs() console.Command {
 					})
 				},
 			},
+			{
+				Name:    "manifest",
+				Aliases: []string{"m"},
+				Usage:   "Prints the integrity manifest (SHA-256 checksum) of app files as JSON",
+				Description: `Prints the integrity manifest (SHA-256 checksum) of app files as JSON, it is
+	used by packaged app for integrity check 'run --verify-assets'.
+
+		Example:
+			<app-binary> vfs manifest`,
+				Action: func(c *console.Context) error {
+					m, err := a.VFS().GenerateManifest(a.VirtualBaseDir())
+					if err != nil {
+						return err
+					}
+					enc := json.NewEncoder(c.App.Writer)
+					enc.SetIndent("", "  ")
+					return enc.Encode(m)
+				},
+			},
 		},
 	}
 }
+
+func (a *Application) cliCmdOpenAPI() console.Command {
+	return console.Command{
+		Name:  "openapi",
+		Usage: "Generates OpenAPI 3 spec from app routes and controllers",
+		Description: `Generates OpenAPI 3 spec in JSON format from app routes, controllers
+	and its action doc comments. Spec is written into stdout, if output file is not given.
+
+		Example:
+			<app-binary> openapi --output openapi.json`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+			},
+			console.StringFlag{
+				Name:  "host",
+				Usage: "Domain host name from 'routes.conf', default is root domain",
+			},
+			console.StringFlag{
+				Name:  "output, o",
+				Usage: "Output `FILE` path to write the spec",
+			},
+		},
+		Action: func(c *console.Context) error {
+			a.Config().SetString("env.active", cliEnvProfile(c))
+			if err := a.refreshSettings(); err != nil {
+				return err
+			}
+			if err := a.initLog(); err != nil {
+				return err
+			}
+			if err := a.initSecurity(); err != nil {
+				return err
+			}
+			if err := a.initRouter(); err != nil {
+				return err
+			}
+
+			spec, err := a.OpenAPISpec(c.String("host"))
+			if err != nil {
+				return err
+			}
+
+			output := c.String("output")
+			if ess.IsStrEmpty(output) {
+				_, err = c.App.Writer.Write(append(spec, '\n'))
+				return err
+			}
+			return ioutil.WriteFile(output, spec, 0644)
+		},
+	}
+}
+
+func (a *Application) cliCmdConfig() console.Command {
+	cryptFlags := []console.Flag{
+		console.StringFlag{
+			Name:  "value, v",
+			Usage: "Config value to encrypt or decrypt",
+		},
+	}
+	return console.Command{
+		Name:  "config",
+		Usage: "Encrypts or decrypts the sensitive config values",
+		Description: `Encrypts or decrypts the sensitive config values using master key supplied
+	via environment variable 'AAH_CONFIG_MASTER_KEY'. Encrypted value 'ENC(...)'
+	is decrypted transparently on config load.
+
+		Example:
+			<app-binary> config encrypt --value "my-sign-key"
+			<app-binary> config decrypt --value "ENC(...)"`,
+		Subcommands: []console.Command{
+			{
+				Name:  "encrypt",
+				Usage: "Encrypts the given config value",
+				Flags: cryptFlags,
+				Action: func(c *console.Context) error {
+					return a.configCrypt(c, config.Encrypt)
+				},
+			},
+			{
+				Name:  "decrypt",
+				Usage: "Decrypts the given encrypted config value",
+				Flags: cryptFlags,
+				Action: func(c *console.Context) error {
+					return a.configCrypt(c, config.Decrypt)
+				},
+			},
+		},
+	}
+}
+
+func (a *Application) configCrypt(c *console.Context, fn func([]byte, string) (string, error)) error {
+	value := c.String("value")
+	if ess.IsStrEmpty(value) {
+		return errors.New("aah: config value is required")
+	}
+	key, err := config.MasterKey()
+	if err != nil {
+		return err
+	}
+	result, err := fn(key, value)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.App.Writer, result)
+	return nil
+}
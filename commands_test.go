@@ -0,0 +1,134 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/console"
+	"aahframe.work/vfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCliCmdConfigEncryptDecrypt(t *testing.T) {
+	os.Setenv(config.EnvMasterKey, "cli-master-key")
+	defer os.Unsetenv(config.EnvMasterKey)
+
+	a := newApp()
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdConfig()}
+
+	err := cliApp.Run([]string{"app", "config", "encrypt", "--value", "my-sign-key"})
+	assert.Nil(t, err)
+	enc := strings.TrimSpace(buf.String())
+	assert.True(t, config.IsEncrypted(enc))
+
+	buf.Reset()
+	err = cliApp.Run([]string{"app", "config", "decrypt", "--value", enc})
+	assert.Nil(t, err)
+	assert.Equal(t, "my-sign-key", strings.TrimSpace(buf.String()))
+
+	err = cliApp.Run([]string{"app", "config", "encrypt"})
+	assert.Equal(t, "aah: config value is required", err.Error())
+
+	os.Unsetenv(config.EnvMasterKey)
+	err = cliApp.Run([]string{"app", "config", "decrypt", "--value", enc})
+	assert.Equal(t, config.ErrMasterKeyNotFound, err)
+}
+
+func TestCliCmdMiddlewareAndGlobalFlags(t *testing.T) {
+	a := newApp()
+	a.SetBuildInfo(&BuildInfo{BinaryName: "testapp", Version: "1.0.0", Timestamp: "2018-10-21T14:52:04-07:00"})
+	cfg, err := config.ParseString(`
+	env {
+		qa { }
+		prod { }
+	}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	err = a.AddCommand(console.Command{Name: "doctor"})
+	assert.Equal(t, "aah: reserved command name 'doctor' cannot be used", err.Error())
+
+	var profiles []string
+	err = a.AddCommand(console.Command{
+		Name:     "users",
+		Category: "Admin",
+		Subcommands: []console.Command{
+			{
+				Name: "list",
+				Action: func(c *console.Context) error {
+					profiles = append(profiles, cliEnvProfile(c))
+					return nil
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	var calls []string
+	a.AddCommandMiddleware(func(c *console.Context, next console.ActionFunc) error {
+		calls = append(calls, "pre:"+c.Command.FullName())
+		err := next(c)
+		calls = append(calls, "post:"+c.Command.FullName())
+		return err
+	}, func(c *console.Context, next console.ActionFunc) error {
+		calls = append(calls, "inner")
+		return next(c)
+	})
+
+	a.initCli()
+	var buf bytes.Buffer
+	a.cli.Writer = &buf
+
+	err = a.cli.Run([]string{"testapp", "--envprofile", "qa", "--log-level", "debug", "users", "list"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"qa"}, profiles)
+	assert.Equal(t, []string{"pre:users list", "inner", "post:users list"}, calls)
+	assert.Equal(t, "qa", a.Config().StringDefault("env.active", ""))
+	assert.Equal(t, "debug", a.Config().StringDefault("log.level", ""))
+
+	// command flag takes precedence over global flag
+	err = a.cli.Run([]string{"testapp", "-e", "qa", "users", "list", "-e", "prod"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"qa", "prod"}, profiles)
+
+	err = a.cli.Run([]string{"testapp", "--log-level", "unknown", "users", "list"})
+	assert.NotNil(t, err)
+
+	buf.Reset()
+	err = a.cli.Run([]string{"testapp", "help"})
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(buf.String(), "Admin:"))
+	assert.True(t, strings.Contains(buf.String(), "--log-level"))
+}
+
+func TestCliCmdVfsManifest(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdVfs()}
+	err := cliApp.Run([]string{"app", "vfs", "manifest"})
+	assert.Nil(t, err)
+
+	var m vfs.Manifest
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	sum, err := a.VFS().Checksum("/app/config/aah.conf")
+	assert.Nil(t, err)
+	assert.Equal(t, sum, m["/app/config/aah.conf"])
+
+	a.VFS().SetManifest(m)
+	assert.Nil(t, a.VFS().VerifyManifest())
+}
@@ -42,6 +42,7 @@ type Config struct {
 	sync.RWMutex
 	profile string
 	cfg     *forge.Section
+	sources map[string]string
 }
 
 // Profile returns current active profile
@@ -386,6 +387,9 @@ func (c *Config) Merge(source *Config) error {
 	if source == nil {
 		return errors.New("source is nil")
 	}
+	for k, v := range source.Sources() {
+		c.addSource(k, v)
+	}
 	c.Lock()
 	defer c.Unlock()
 	return c.cfg.Merge(source.cfg)
@@ -429,19 +433,26 @@ func (c *Config) ToJSON() string {
 // Config load/parse methods
 //______________________________________________________________________________
 
-// LoadFile loads the configuration from given config file.
+// LoadFile loads the configuration from given config file. Encrypted
+// values `ENC(...)` are decrypted transparently, refer `Encrypt`.
 func LoadFile(filename string) (*Config, error) {
 	setting, err := loadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	return newConfig(setting), nil
+	if err = decryptSection(setting); err != nil {
+		return nil, err
+	}
+	c := newConfig(setting)
+	c.addSources(setting, filename)
+	return c, nil
 }
 
 // LoadFiles loads the configuration from given config files.
 // It does merging of configuration in the order they are given.
 func LoadFiles(files ...string) (*Config, error) {
 	settings := forge.NewSection()
+	c := newConfig(settings)
 	for _, filename := range files {
 		setting, err := loadFile(filename)
 		if err != nil {
@@ -450,8 +461,12 @@ func LoadFiles(files ...string) (*Config, error) {
 		if err = settings.Merge(setting); err != nil {
 			return nil, err
 		}
+		c.addSources(setting, filename)
 	}
-	return newConfig(settings), nil
+	if err := decryptSection(settings); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 // ParseString parses the configuration values from string
@@ -460,6 +475,9 @@ func ParseString(cfg string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = decryptSection(setting); err != nil {
+		return nil, err
+	}
 	return newConfig(setting), nil
 }
 
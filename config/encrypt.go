@@ -0,0 +1,203 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-aah/forge"
+)
+
+// EnvMasterKey is the environment variable name of master key, used to
+// encrypt and decrypt the config values.
+const EnvMasterKey = "AAH_CONFIG_MASTER_KEY"
+
+const (
+	encPrefix = "ENC("
+	encSuffix = ")"
+)
+
+var (
+	// ErrMasterKeyNotFound returned when config has encrypted values and master
+	// key is not supplied via `AAH_CONFIG_MASTER_KEY` or `MasterKeyProvider`.
+	ErrMasterKeyNotFound = errors.New("config: master key not found")
+
+	// ErrInvalidEncryptedValue returned when encrypted value is malformed or
+	// cannot be decrypted with the master key.
+	ErrInvalidEncryptedValue = errors.New("config: invalid encrypted value")
+
+	masterKeyProvider MasterKeyProvider = envMasterKey
+)
+
+// MasterKeyProvider func type is used to supply the master key for
+// encrypted config values, for e.g.: fetch it from KMS.
+type MasterKeyProvider func() ([]byte, error)
+
+// SetMasterKeyProvider method sets the given master key provider, it's used
+// by `LoadFile`, `LoadFiles` and `ParseString` to decrypt the config values.
+// Default provider reads the environment variable `AAH_CONFIG_MASTER_KEY`.
+func SetMasterKeyProvider(provider MasterKeyProvider) {
+	if provider == nil {
+		provider = envMasterKey
+	}
+	masterKeyProvider = provider
+}
+
+// IsEncrypted method returns true if given value is in encrypted form
+// `ENC(...)` otherwise false.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encPrefix) && strings.HasSuffix(value, encSuffix)
+}
+
+// Encrypt method encrypts the given value using AES-GCM with master key and
+// returns the value in the form `ENC(...)`, it can be used in config file.
+//
+// For e.g.:
+// 	security {
+// 		session {
+// 			sign_key = "ENC(mN3wKx0v...)"
+// 		}
+// 	}
+func Encrypt(masterKey []byte, value string) (string, error) {
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encPrefix + base64.RawURLEncoding.EncodeToString(sealed) + encSuffix, nil
+}
+
+// Decrypt method decrypts the given `ENC(...)` value using master key.
+func Decrypt(masterKey []byte, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", ErrInvalidEncryptedValue
+	}
+
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(value[len(encPrefix) : len(value)-len(encSuffix)])
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrInvalidEncryptedValue
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidEncryptedValue
+	}
+	return string(plain), nil
+}
+
+// MasterKey method returns the master key from configured master key provider.
+func MasterKey() ([]byte, error) {
+	return masterKeyProvider()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func envMasterKey() ([]byte, error) {
+	if key := os.Getenv(EnvMasterKey); len(key) > 0 {
+		return []byte(key), nil
+	}
+	return nil, ErrMasterKeyNotFound
+}
+
+// newGCM method creates AES-256 GCM cipher, master key is derived
+// into 32 bytes using SHA-256.
+func newGCM(masterKey []byte) (cipher.AEAD, error) {
+	if len(masterKey) == 0 {
+		return nil, ErrMasterKeyNotFound
+	}
+	key := sha256.Sum256(masterKey)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptSection method walks through the given section and decrypts the
+// `ENC(...)` values in-place. Master key is obtained only if the section
+// has encrypted values.
+func decryptSection(sec *forge.Section) error {
+	d := &decrypter{}
+	return d.section("", sec)
+}
+
+type decrypter struct {
+	key []byte
+}
+
+func (d *decrypter) section(path string, sec *forge.Section) error {
+	for _, k := range sec.Keys() {
+		v, err := sec.Get(k)
+		if err != nil {
+			continue
+		}
+		if err = d.value(path+k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decrypter) value(path string, v forge.Value) error {
+	switch v.GetType() {
+	case forge.SECTION:
+		return d.section(path+".", v.(*forge.Section))
+	case forge.LIST:
+		for i, lv := range v.(*forge.List).GetValues() {
+			if err := d.value(fmt.Sprintf("%s[%d]", path, i), lv); err != nil {
+				return err
+			}
+		}
+	case forge.STRING:
+		s, _ := v.GetValue().(string)
+		if !IsEncrypted(s) {
+			return nil
+		}
+		plain, err := d.decrypt(path, s)
+		if err != nil {
+			return err
+		}
+		return v.UpdateValue(plain)
+	}
+	return nil
+}
+
+// decrypt method decrypts the given `ENC(...)` value, master key is obtained
+// once on first use.
+func (d *decrypter) decrypt(path, s string) (string, error) {
+	if d.key == nil {
+		key, err := MasterKey()
+		if err != nil {
+			return "", fmt.Errorf("config: unable to decrypt '%s': %v", path, err)
+		}
+		d.key = key
+	}
+	plain, err := Decrypt(d.key, s)
+	if err != nil {
+		return "", fmt.Errorf("config: unable to decrypt '%s': %v", path, err)
+	}
+	return plain, nil
+}
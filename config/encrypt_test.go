@@ -0,0 +1,87 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigEncryptDecrypt(t *testing.T) {
+	key := []byte("my-master-key")
+	enc, err := Encrypt(key, "s3cr3t-sign-key")
+	assert.Nil(t, err)
+	assert.True(t, IsEncrypted(enc))
+	assert.False(t, strings.Contains(enc, "s3cr3t"))
+
+	plain, err := Decrypt(key, enc)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t-sign-key", plain)
+
+	_, err = Decrypt([]byte("wrong-key"), enc)
+	assert.Equal(t, ErrInvalidEncryptedValue, err)
+
+	_, err = Decrypt(key, "ENC(not-valid!)")
+	assert.Equal(t, ErrInvalidEncryptedValue, err)
+
+	_, err = Decrypt(key, "plain-value")
+	assert.Equal(t, ErrInvalidEncryptedValue, err)
+
+	_, err = Encrypt(nil, "value")
+	assert.Equal(t, ErrMasterKeyNotFound, err)
+}
+
+func TestConfigLoadEncryptedValues(t *testing.T) {
+	key := []byte("my-master-key")
+	signKey, _ := Encrypt(key, "s3cr3t-sign-key")
+	password, _ := Encrypt(key, "db-password")
+	cfgStr := `
+security {
+  session {
+    sign_key = "` + signKey + `"
+  }
+}
+datasources {
+  hosts = ["localhost", "` + password + `"]
+  password = "` + password + `"
+}`
+
+	// master key not supplied
+	os.Unsetenv(EnvMasterKey)
+	_, err := ParseString(cfgStr)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrMasterKeyNotFound.Error()))
+
+	// via environment variable
+	os.Setenv(EnvMasterKey, string(key))
+	defer os.Unsetenv(EnvMasterKey)
+	cfg, err := ParseString(cfgStr)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t-sign-key", cfg.StringDefault("security.session.sign_key", ""))
+	assert.Equal(t, "db-password", cfg.StringDefault("datasources.password", ""))
+	hosts, _ := cfg.StringList("datasources.hosts")
+	assert.Equal(t, []string{"localhost", "db-password"}, hosts)
+
+	// via master key provider, for e.g.: KMS
+	os.Unsetenv(EnvMasterKey)
+	SetMasterKeyProvider(func() ([]byte, error) { return key, nil })
+	cfg, err = ParseString(cfgStr)
+	assert.Nil(t, err)
+	assert.Equal(t, "db-password", cfg.StringDefault("datasources.password", ""))
+
+	SetMasterKeyProvider(func() ([]byte, error) { return nil, errors.New("kms unavailable") })
+	_, err = ParseString(cfgStr)
+	assert.True(t, strings.Contains(err.Error(), "kms unavailable"))
+
+	// no encrypted values, master key is not required
+	SetMasterKeyProvider(nil)
+	cfg, err = ParseString("name = \"plain\"\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", cfg.StringDefault("name", ""))
+}
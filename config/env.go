@@ -0,0 +1,209 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-aah/forge"
+)
+
+// envSectionSep is the section separator of environment variable name for
+// config keys that are not present in the config, for e.g.:
+// `AAH_SERVER__TIMEOUT__READ` maps to `server.timeout.read`.
+const envSectionSep = "__"
+
+// OverrideFromEnv method overrides the config values from environment
+// variables that have given prefix and returns overridden keys, sorted.
+// Environment variable name of the config key is prefix + key in upper case
+// with `.` replaced by `_`, for e.g.: with prefix `AAH`
+//
+// 	AAH_SERVER_PORT=9090                  -> server.port
+// 	AAH_SECURITY_SESSION_SIGN_KEY=...     -> security.session.sign_key
+// 	AAH_SERVER__TIMEOUT__READ=60s         -> server.timeout.read
+//
+// Keys of active profile are mapped without profile prefix. The config key
+// which is not present in the config can be supplied with `__` as section
+// separator. Environment value is converted into existing value type;
+// list values are comma separated. Encrypted value `ENC(...)` is decrypted
+// with master key, refer `Encrypt`.
+//
+// It returns an error if environment value cannot be decrypted or converted
+// into existing value type, config is partially overridden in that case.
+//
+// NOTE: Call this method after the profile is activated, so the overrides
+// take precedence over profile values.
+func (c *Config) OverrideFromEnv(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_"
+
+	var overridden []string
+	d := &decrypter{}
+	known := make(map[string]bool)
+	for _, key := range c.overridableKeys() {
+		name := prefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+		known[name] = true
+		if value, found := os.LookupEnv(name); found {
+			if err := c.setFromEnv(d, key, name, value); err != nil {
+				return nil, err
+			}
+			overridden = append(overridden, key)
+		}
+	}
+
+	for _, env := range os.Environ() {
+		idx := strings.IndexByte(env, '=')
+		name, value := env[:idx], env[idx+1:]
+		if known[name] || !strings.HasPrefix(name, prefix) || !strings.Contains(name, envSectionSep) {
+			continue
+		}
+		key := strings.ToLower(strings.Replace(strings.TrimPrefix(name, prefix), envSectionSep, ".", -1))
+		if key == "" || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") {
+			continue
+		}
+		if err := c.setFromEnv(d, key, name, value); err != nil {
+			return nil, err
+		}
+		overridden = append(overridden, key)
+	}
+
+	sort.Strings(overridden)
+	return overridden, nil
+}
+
+// Sources method returns the origin of config values, key is the config key
+// (with profile prefix if it belongs to profile) and value is either
+// config file path or `env:<NAME>` for environment variable overrides.
+// Values set programmatically via `Set*` methods are not tracked.
+func (c *Config) Sources() map[string]string {
+	c.RLock()
+	defer c.RUnlock()
+	sources := make(map[string]string, len(c.sources))
+	for k, v := range c.sources {
+		sources[k] = v
+	}
+	return sources
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// overridableKeys method returns the leaf keys of config, keys of active
+// profile are returned without profile prefix and other profile keys
+// are skipped.
+func (c *Config) overridableKeys() []string {
+	c.RLock()
+	keys := leafKeys("", c.cfg)
+	profile := c.profile
+	c.RUnlock()
+
+	if len(profile) == 0 {
+		return keys
+	}
+
+	var profileParent string
+	if idx := strings.LastIndexByte(profile, '.'); idx > 0 {
+		profileParent = profile[:idx+1]
+	}
+	seen := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(key, profile+".") {
+			key = strings.TrimPrefix(key, profile+".")
+		} else if len(profileParent) > 0 && strings.HasPrefix(key, profileParent) &&
+			strings.Contains(strings.TrimPrefix(key, profileParent), ".") {
+			continue // other profile
+		}
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func (c *Config) setFromEnv(d *decrypter, key, name, value string) error {
+	if IsEncrypted(value) {
+		var err error
+		if value, err = d.decrypt("env:"+name, value); err != nil {
+			return err
+		}
+	}
+
+	existing, _ := c.Get(key)
+	switch existing.(type) {
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return envValueError(key, name, err)
+		}
+		c.SetBool(key, b)
+	case int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return envValueError(key, name, err)
+		}
+		c.SetInt64(key, i)
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return envValueError(key, name, err)
+		}
+		c.SetFloat64(key, f)
+	case []interface{}:
+		lst := forge.NewList()
+		for _, v := range strings.Split(value, ",") {
+			lst.Append(forge.NewString(strings.TrimSpace(v)))
+		}
+		c.addValue(key, lst)
+	default:
+		c.SetString(key, value)
+	}
+	c.addSource(c.prepareKey(key), "env:"+name)
+	return nil
+}
+
+func envValueError(key, name string, err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+	return fmt.Errorf("config: invalid value of env '%s' for '%s': %v", name, key, err)
+}
+
+func (c *Config) addSource(key, source string) {
+	c.Lock()
+	defer c.Unlock()
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[key] = source
+}
+
+func (c *Config) addSources(sec *forge.Section, source string) {
+	for _, key := range leafKeys("", sec) {
+		c.addSource(key, source)
+	}
+}
+
+// leafKeys method returns the non-section keys of given section recursively.
+func leafKeys(path string, sec *forge.Section) []string {
+	var keys []string
+	for _, k := range sec.Keys() {
+		v, err := sec.Get(k)
+		if err != nil {
+			continue
+		}
+		if s, ok := v.(*forge.Section); ok {
+			keys = append(keys, leafKeys(path+k+".", s)...)
+			continue
+		}
+		keys = append(keys, path+k)
+	}
+	return keys
+}
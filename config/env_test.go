@@ -0,0 +1,153 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigOverrideFromEnv(t *testing.T) {
+	cfgStr := `
+server {
+  port = "8080"
+  max_header_bytes = "1mb"
+  timeout {
+    grace_shutdown = "60s"
+  }
+}
+request {
+  id {
+    enable = true
+  }
+}
+worker {
+  count = 4
+  hosts = ["a", "b"]
+}
+env {
+  active = "prod"
+  prod {
+    server {
+      port = "80"
+    }
+  }
+  dev {
+    server {
+      port = "8000"
+    }
+  }
+}
+`
+	dir, _ := ioutil.TempDir("", "aah-config")
+	defer os.RemoveAll(dir)
+	fpath := filepath.Join(dir, "aah.conf")
+	assert.Nil(t, ioutil.WriteFile(fpath, []byte(cfgStr), 0644))
+	cfg, err := LoadFile(fpath)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.SetProfile("env.prod"))
+
+	envs := map[string]string{
+		"AAH_SERVER_PORT":             "9090",
+		"AAH_SERVER_MAX_HEADER_BYTES": "2mb",
+		"AAH_REQUEST_ID_ENABLE":       "false",
+		"AAH_WORKER_COUNT":            "8",
+		"AAH_WORKER_HOSTS":            "x, y, z",
+		"AAH_SERVER__TIMEOUT__READ":   "30s",
+		"AAH_ENV_DEV_SERVER_PORT":     "7000",
+		"OTHER_SERVER_PORT":           "1",
+	}
+	for k, v := range envs {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	keys, err := cfg.OverrideFromEnv("AAH")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"request.id.enable", "server.max_header_bytes", "server.port",
+		"server.timeout.read", "worker.count", "worker.hosts"}, keys)
+
+	assert.Equal(t, "9090", cfg.StringDefault("server.port", ""))
+	assert.Equal(t, "2mb", cfg.StringDefault("server.max_header_bytes", ""))
+	assert.False(t, cfg.BoolDefault("request.id.enable", true))
+	assert.Equal(t, 8, cfg.IntDefault("worker.count", 0))
+	hosts, _ := cfg.StringList("worker.hosts")
+	assert.Equal(t, []string{"x", "y", "z"}, hosts)
+	assert.Equal(t, "30s", cfg.StringDefault("server.timeout.read", ""))
+	assert.Equal(t, "60s", cfg.StringDefault("server.timeout.grace_shutdown", ""))
+
+	// sources
+	sources := cfg.Sources()
+	assert.Equal(t, fpath, sources["server.timeout.grace_shutdown"])
+	assert.Equal(t, fpath, sources["env.dev.server.port"])
+	assert.Equal(t, "env:AAH_SERVER_PORT", sources["env.prod.server.port"])
+	assert.Equal(t, "env:AAH_SERVER__TIMEOUT__READ", sources["env.prod.server.timeout.read"])
+
+	// other profile is not affected
+	cfg.ClearProfile()
+	assert.Equal(t, "8000", cfg.StringDefault("env.dev.server.port", ""))
+	assert.Equal(t, "8080", cfg.StringDefault("server.port", ""))
+}
+
+func TestConfigOverrideFromEnvError(t *testing.T) {
+	cfg, err := ParseString(`
+worker {
+  count = 4
+}
+security {
+  sign_key = "plain"
+}
+`)
+	assert.Nil(t, err)
+
+	// invalid value is neither applied nor recorded
+	os.Setenv("AAH_WORKER_COUNT", "four")
+	keys, err := cfg.OverrideFromEnv("AAH")
+	assert.Nil(t, keys)
+	assert.Equal(t, "config: invalid value of env 'AAH_WORKER_COUNT' for 'worker.count': invalid syntax", err.Error())
+	assert.Equal(t, 4, cfg.IntDefault("worker.count", 0))
+	_, found := cfg.Sources()["worker.count"]
+	assert.False(t, found)
+	os.Unsetenv("AAH_WORKER_COUNT")
+
+	// encrypted value is decrypted
+	key := []byte("env-master-key")
+	enc, err := Encrypt(key, "s3cr3t")
+	assert.Nil(t, err)
+	os.Setenv("AAH_SECURITY_SIGN_KEY", enc)
+	defer os.Unsetenv("AAH_SECURITY_SIGN_KEY")
+	SetMasterKeyProvider(func() ([]byte, error) { return key, nil })
+	keys, err = cfg.OverrideFromEnv("AAH")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"security.sign_key"}, keys)
+	assert.Equal(t, "s3cr3t", cfg.StringDefault("security.sign_key", ""))
+
+	SetMasterKeyProvider(nil)
+	_, err = cfg.OverrideFromEnv("AAH")
+	assert.Equal(t, "config: unable to decrypt 'env:AAH_SECURITY_SIGN_KEY': config: master key not found", err.Error())
+}
+
+func TestConfigSourcesMerge(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "aah-config")
+	defer os.RemoveAll(dir)
+	f1, f2 := filepath.Join(dir, "one.conf"), filepath.Join(dir, "two.conf")
+	assert.Nil(t, ioutil.WriteFile(f1, []byte("app {\n  name = \"one\"\n  desc = \"first\"\n}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(f2, []byte("app {\n  name = \"two\"\n}\n"), 0644))
+
+	cfg, err := LoadFiles(f1, f2)
+	assert.Nil(t, err)
+	assert.Equal(t, "two", cfg.StringDefault("app.name", ""))
+	assert.Equal(t, f2, cfg.Sources()["app.name"])
+	assert.Equal(t, f1, cfg.Sources()["app.desc"])
+
+	ext, err := LoadFile(f1)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.Merge(ext))
+	assert.Equal(t, f1, cfg.Sources()["app.name"])
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrProviderNotModified returned by provider when config is not
+// changed since last load.
+var ErrProviderNotModified = errors.New("config: not modified")
+
+// Provider interface is used to supply and watch the config from remote or
+// centralized source, for e.g.: etcd, Consul, HTTP endpoint. Provider config
+// values are merged on top of config files.
+type Provider interface {
+	// Load method returns the config from provider source.
+	Load() (*Config, error)
+
+	// Watch method watches the provider source for changes and calls `onChange`
+	// on every change. It blocks until the `stop` channel is closed.
+	Watch(stop <-chan struct{}, onChange func()) error
+}
+
+var _ Provider = (*HTTPProvider)(nil)
+
+// HTTPProvider struct implements `Provider` interface to supply the config
+// from HTTP endpoint, response body is config in aah config syntax. It
+// watches the changes by polling the endpoint on given interval.
+//
+// For e.g.:
+// 	aah.App().SetConfigProvider(config.NewHTTPProvider("https://config.example.com/myapp.conf", 30*time.Second))
+type HTTPProvider struct {
+	URL      string
+	Interval time.Duration
+	Header   http.Header
+	Client   *http.Client
+
+	mu       sync.Mutex
+	etag     string
+	checksum [sha256.Size]byte
+}
+
+// NewHTTPProvider method creates the HTTP config provider for given URL and
+// poll interval.
+func NewHTTPProvider(url string, interval time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		URL:      url,
+		Interval: interval,
+		Header:   http.Header{},
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Load method fetches the config from HTTP endpoint.
+func (p *HTTPProvider) Load() (*Config, error) {
+	body, err := p.fetch(false)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseString(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("config: provider '%s': %v", p.URL, err)
+	}
+	cfg.addSources(cfg.cfg, p.URL)
+	return cfg, nil
+}
+
+// Watch method polls the HTTP endpoint on interval and calls `onChange` if
+// the config is changed.
+func (p *HTTPProvider) Watch(stop <-chan struct{}, onChange func()) error {
+	if p.Interval <= 0 {
+		return errors.New("config: provider poll interval must be greater than zero")
+	}
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			if _, err := p.fetch(true); err == nil {
+				onChange()
+			}
+		}
+	}
+}
+
+// fetch method returns the response body, if `changesOnly` is true it returns
+// `ErrProviderNotModified` when content is same as last fetch.
+func (p *HTTPProvider) fetch(changesOnly bool) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.Header {
+		req.Header[k] = v
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if changesOnly && len(p.etag) > 0 {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrProviderNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("config: provider '%s' responded with status %d", p.URL, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(body)
+	if changesOnly && checksum == p.checksum {
+		return nil, ErrProviderNotModified
+	}
+	p.etag = resp.Header.Get("ETag")
+	p.checksum = checksum
+	return body, nil
+}
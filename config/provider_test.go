@@ -0,0 +1,83 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigHTTPProvider(t *testing.T) {
+	var mu sync.Mutex
+	content := "server {\n  port = \"9090\"\n}\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Config-Token"))
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write([]byte(content))
+	}))
+	defer ts.Close()
+
+	p := NewHTTPProvider(ts.URL, 20*time.Millisecond)
+	p.Header.Set("X-Config-Token", "secret")
+	cfg, err := p.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "9090", cfg.StringDefault("server.port", ""))
+	assert.Equal(t, ts.URL, cfg.Sources()["server.port"])
+
+	changed := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- p.Watch(stop, func() { changed <- struct{}{} })
+	}()
+
+	// no change
+	select {
+	case <-changed:
+		t.Fatal("not expecting change notification")
+	case <-time.After(80 * time.Millisecond):
+	}
+
+	mu.Lock()
+	content = "server {\n  port = \"8080\"\n}\n"
+	mu.Unlock()
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expecting change notification")
+	}
+	close(stop)
+	assert.Nil(t, <-done)
+
+	cfg, err = p.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "8080", cfg.StringDefault("server.port", ""))
+}
+
+func TestConfigHTTPProviderErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/invalid" {
+			_, _ = w.Write([]byte("server {"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := NewHTTPProvider(ts.URL+"/missing", time.Second).Load()
+	assert.Equal(t, "config: provider '"+ts.URL+"/missing' responded with status 404", err.Error())
+
+	_, err = NewHTTPProvider(ts.URL+"/invalid", time.Second).Load()
+	assert.NotNil(t, err)
+
+	err = NewHTTPProvider(ts.URL, 0).Watch(make(chan struct{}), func() {})
+	assert.Equal(t, "config: provider poll interval must be greater than zero", err.Error())
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aahframe.work/essentials"
+	"github.com/go-aah/forge"
+)
+
+// Value types of schema rule.
+const (
+	TypeString KeyType = iota
+	TypeBool
+	TypeInt
+	TypeFloat
+	TypeList
+	TypeDuration
+	TypeBytes
+)
+
+// suggestDistance is the maximum edit distance between unknown key and
+// schema key to suggest it.
+const suggestDistance = 2
+
+var keyTypeNames = [...]string{
+	TypeString:   "string",
+	TypeBool:     "bool",
+	TypeInt:      "int",
+	TypeFloat:    "float",
+	TypeList:     "list",
+	TypeDuration: "time unit",
+	TypeBytes:    "size unit",
+}
+
+// KeyType is used to declare the value type of config key in schema.
+type KeyType uint8
+
+func (kt KeyType) String() string {
+	return keyTypeNames[kt]
+}
+
+// Rule struct declares the config key type, range, allowed values and
+// deprecation of config key.
+type Rule struct {
+	Key  string
+	Type KeyType
+
+	// Min and Max is the range of `TypeInt` value, applied only
+	// when `Min < Max`.
+	Min int64
+	Max int64
+
+	// Enum is allowed values of `TypeString` value, case-insensitive.
+	Enum []string
+
+	// Deprecated is the replacement config key of deprecated config key.
+	Deprecated string
+}
+
+// Schema struct holds the config key rules to validate the config values
+// with consolidated report, instead of silent default value fallback
+// hiding the typos.
+type Schema struct {
+	// ProfilePrefix is the parent of profile sections, for e.g.: `env.`.
+	// Keys within profile are validated without profile prefix.
+	ProfilePrefix string
+
+	rules    map[string]*Rule
+	sections map[string]bool
+}
+
+// NewSchema method creates the config schema with given rules.
+func NewSchema(rules ...*Rule) *Schema {
+	s := &Schema{rules: make(map[string]*Rule), sections: make(map[string]bool)}
+	s.Add(rules...)
+	return s
+}
+
+// Add method adds the given rules into schema.
+func (s *Schema) Add(rules ...*Rule) {
+	for _, r := range rules {
+		s.rules[r.Key] = r
+		s.sections[strings.SplitN(r.Key, ".", 2)[0]] = true
+	}
+}
+
+// Rule method returns the rule of given config key if exists otherwise nil.
+func (s *Schema) Rule(key string) *Rule {
+	return s.rules[key]
+}
+
+// Validate method validates the given config against schema rules. It returns
+// deprecation warnings and `SchemaError` with all the issues found.
+//
+// Unknown keys within the schema sections are reported only if it's
+// similar to schema key, for e.g.:
+// 	config: 'server.ssl.enabel' unknown key, did you mean 'server.ssl.enable'?
+func (s *Schema) Validate(cfg *Config) ([]string, error) {
+	cfg.RLock()
+	keys := leafKeys("", cfg.cfg)
+	cfg.RUnlock()
+	sort.Strings(keys)
+
+	var warnings, issues []string
+	for _, key := range keys {
+		rule, rkey := s.lookup(key)
+		if rule == nil {
+			if suggest := s.suggest(rkey); len(suggest) > 0 {
+				issues = append(issues, fmt.Sprintf("'%s' unknown key, did you mean '%s'?", key, suggest))
+			}
+			continue
+		}
+		if len(rule.Deprecated) > 0 {
+			warnings = append(warnings, fmt.Sprintf("'%s' is deprecated, use '%s' instead", key, rule.Deprecated))
+		}
+
+		v, _ := cfg.getraw(key)
+		if err := rule.validate(v); err != nil {
+			issues = append(issues, fmt.Sprintf("'%s' %v", key, err))
+		}
+	}
+
+	if len(issues) > 0 {
+		return warnings, &SchemaError{Issues: issues}
+	}
+	return warnings, nil
+}
+
+// SchemaError struct holds the consolidated config validation issues.
+type SchemaError struct {
+	Issues []string
+}
+
+// Error method is to satisfy error interface.
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("config: %d validation issue(s)\n\t- %s",
+		len(e.Issues), strings.Join(e.Issues, "\n\t- "))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// lookup method returns the rule of given key and the key used for lookup,
+// profile prefix is removed if key is not found as-is.
+func (s *Schema) lookup(key string) (*Rule, string) {
+	if r, found := s.rules[key]; found {
+		return r, key
+	}
+	if len(s.ProfilePrefix) > 0 && strings.HasPrefix(key, s.ProfilePrefix) {
+		parts := strings.SplitN(strings.TrimPrefix(key, s.ProfilePrefix), ".", 2)
+		if len(parts) == 2 {
+			key = parts[1]
+			return s.rules[key], key
+		}
+	}
+	return nil, key
+}
+
+// suggest method returns the closest schema key for the unknown key
+// within schema sections otherwise empty string.
+func (s *Schema) suggest(key string) string {
+	if !s.sections[strings.SplitN(key, ".", 2)[0]] {
+		return ""
+	}
+	var suggest string
+	distance := suggestDistance + 1
+	for rkey := range s.rules {
+		if d := editDistance(key, rkey); d < distance || (d == distance && rkey < suggest) {
+			suggest, distance = rkey, d
+		}
+	}
+	return suggest
+}
+
+func (r *Rule) validate(v forge.Value) error {
+	if v.GetType() == forge.NULL {
+		return nil
+	}
+
+	switch r.Type {
+	case TypeBool:
+		if v.GetType() != forge.BOOLEAN {
+			return fmt.Errorf("expects %s value", r.Type)
+		}
+	case TypeInt:
+		i, ok := v.GetValue().(int64)
+		if !ok {
+			return fmt.Errorf("expects %s value", r.Type)
+		}
+		if r.Min < r.Max && (i < r.Min || i > r.Max) {
+			return fmt.Errorf("value %d is out of range [%d, %d]", i, r.Min, r.Max)
+		}
+	case TypeFloat:
+		if t := v.GetType(); t != forge.FLOAT && t != forge.INTEGER {
+			return fmt.Errorf("expects %s value", r.Type)
+		}
+	case TypeList:
+		if v.GetType() != forge.LIST {
+			return fmt.Errorf("expects %s value", r.Type)
+		}
+	default:
+		str, ok := v.GetValue().(string)
+		if !ok {
+			return fmt.Errorf("expects %s value", r.Type)
+		}
+		return r.validateString(str)
+	}
+	return nil
+}
+
+func (r *Rule) validateString(str string) error {
+	if len(str) == 0 {
+		return nil
+	}
+	switch r.Type {
+	case TypeDuration:
+		if _, err := time.ParseDuration(str); err != nil {
+			return fmt.Errorf("value '%s' is not a valid %s", str, r.Type)
+		}
+	case TypeBytes:
+		if _, err := ess.StrToBytes(str); err != nil {
+			return fmt.Errorf("value '%s' is not a valid %s", str, r.Type)
+		}
+	}
+	if len(r.Enum) > 0 {
+		for _, e := range r.Enum {
+			if strings.EqualFold(e, str) {
+				return nil
+			}
+		}
+		return fmt.Errorf("value '%s' is not one of [%s]", str, strings.Join(r.Enum, ", "))
+	}
+	return nil
+}
+
+// editDistance method returns the Levenshtein distance of given strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
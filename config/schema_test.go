@@ -0,0 +1,117 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSchemaValidate(t *testing.T) {
+	schema := NewSchema(
+		&Rule{Key: "server.port", Type: TypeString},
+		&Rule{Key: "server.ssl.enable", Type: TypeBool},
+		&Rule{Key: "server.timeout.read", Type: TypeDuration},
+		&Rule{Key: "server.max_header_bytes", Type: TypeBytes},
+		&Rule{Key: "server.redirect.code", Type: TypeInt, Min: 300, Max: 308},
+		&Rule{Key: "server.hosts", Type: TypeList},
+		&Rule{Key: "log.level", Type: TypeString, Enum: []string{"info", "debug"}},
+		&Rule{Key: "log.rotate.mode", Type: TypeString, Deprecated: "log.rotate.policy"},
+		&Rule{Key: "ratio", Type: TypeFloat},
+	)
+	schema.ProfilePrefix = "env."
+
+	// valid config
+	cfg, err := ParseString(`
+server {
+  port = "8080"
+  ssl {
+    enable = true
+  }
+  timeout {
+    read = "90s"
+  }
+  max_header_bytes = "1mb"
+  redirect {
+    code = 301
+  }
+  hosts = ["a", "b"]
+}
+log {
+  level = "INFO"
+}
+ratio = 1
+app {
+  custom_key = "not in schema"
+}
+`)
+	assert.Nil(t, err)
+	warnings, err := schema.Validate(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, warnings)
+
+	// invalid config
+	cfg, err = ParseString(`
+server {
+  port = 8080
+  ssl {
+    enabel = true
+  }
+  timeout {
+    read = "90"
+  }
+  max_header_bytes = "1xb"
+  redirect {
+    code = 200
+  }
+  hosts = "a"
+  custom_settings = "far from schema keys"
+}
+log {
+  level = "verbose"
+  rotate {
+    mode = "daily"
+  }
+}
+ratio = "one"
+env {
+  prod {
+    server {
+      ssl {
+        enable = "yes"
+      }
+    }
+  }
+}
+`)
+	assert.Nil(t, err)
+	warnings, err = schema.Validate(cfg)
+	assert.Equal(t, []string{"'log.rotate.mode' is deprecated, use 'log.rotate.policy' instead"}, warnings)
+	assert.NotNil(t, err)
+
+	serr := err.(*SchemaError)
+	assert.Equal(t, []string{
+		"'env.prod.server.ssl.enable' expects bool value",
+		"'log.level' value 'verbose' is not one of [info, debug]",
+		"'ratio' expects float value",
+		"'server.hosts' expects list value",
+		"'server.max_header_bytes' value '1xb' is not a valid size unit",
+		"'server.port' expects string value",
+		"'server.redirect.code' value 200 is out of range [300, 308]",
+		"'server.ssl.enabel' unknown key, did you mean 'server.ssl.enable'?",
+		"'server.timeout.read' value '90' is not a valid time unit",
+	}, serr.Issues)
+	assert.Contains(t, err.Error(), "config: 9 validation issue(s)")
+	assert.NotNil(t, schema.Rule("server.port"))
+	assert.Nil(t, schema.Rule("server.unknown"))
+}
+
+func TestConfigEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("enable", "enable"))
+	assert.Equal(t, 2, editDistance("enabel", "enable"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 6, editDistance("", "enable"))
+}
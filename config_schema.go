@@ -0,0 +1,206 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"aahframe.work/config"
+)
+
+// frameworkConfigSchema method returns the config schema of aah framework
+// config keys, it's validated on config load.
+func frameworkConfigSchema() *config.Schema {
+	s := config.NewSchema(
+		// app
+		&config.Rule{Key: "name", Type: config.TypeString},
+		&config.Rule{Key: "desc", Type: config.TypeString},
+		&config.Rule{Key: "type", Type: config.TypeString, Enum: []string{"web", "api", "websocket", "worker"}},
+		&config.Rule{Key: "instance_name", Type: config.TypeString},
+		&config.Rule{Key: "pid_file", Type: config.TypeString},
+		&config.Rule{Key: "env.active", Type: config.TypeString},
+		&config.Rule{Key: "env.override.enable", Type: config.TypeBool},
+		&config.Rule{Key: "env.override.prefix", Type: config.TypeString},
+
+		// server
+		&config.Rule{Key: "server.address", Type: config.TypeString},
+		&config.Rule{Key: "server.port", Type: config.TypeString},
+		&config.Rule{Key: "server.header", Type: config.TypeString},
+		&config.Rule{Key: "server.keep_alive", Type: config.TypeBool},
+		&config.Rule{Key: "server.max_header_bytes", Type: config.TypeBytes},
+		&config.Rule{Key: "server.timeout.read", Type: config.TypeDuration},
+		&config.Rule{Key: "server.timeout.write", Type: config.TypeDuration},
+		&config.Rule{Key: "server.timeout.grace_shutdown", Type: config.TypeDuration},
+		&config.Rule{Key: "server.ssl.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.ssl.cert", Type: config.TypeString},
+		&config.Rule{Key: "server.ssl.key", Type: config.TypeString},
+		&config.Rule{Key: "server.ssl.lets_encrypt.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.ssl.lets_encrypt.host_policy", Type: config.TypeList},
+		&config.Rule{Key: "server.ssl.lets_encrypt.renew_before", Type: config.TypeInt, Min: 1, Max: 30},
+		&config.Rule{Key: "server.ssl.lets_encrypt.email", Type: config.TypeString},
+		&config.Rule{Key: "server.ssl.lets_encrypt.cache_dir", Type: config.TypeString},
+		&config.Rule{Key: "server.ssl.redirect_http.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.ssl.redirect_http.port", Type: config.TypeString},
+		&config.Rule{Key: "server.redirect.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.redirect.to", Type: config.TypeString, Enum: []string{"www", "non-www"}},
+		&config.Rule{Key: "server.redirect.code", Type: config.TypeInt, Min: 300, Max: 308},
+		&config.Rule{Key: "server.access_log.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.access_log.file", Type: config.TypeString},
+		&config.Rule{Key: "server.access_log.pattern", Type: config.TypeString},
+		&config.Rule{Key: "server.access_log.static_file", Type: config.TypeBool},
+		&config.Rule{Key: "server.access_log.channel_buffer_size", Type: config.TypeInt, Min: 1, Max: 1 << 20},
+		&config.Rule{Key: "server.access_log.sampling.rate", Type: config.TypeFloat},
+		&config.Rule{Key: "server.dump_log.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.dump_log.file", Type: config.TypeString},
+		&config.Rule{Key: "server.dump_log.request_body", Type: config.TypeBool},
+		&config.Rule{Key: "server.dump_log.response_body", Type: config.TypeBool},
+		&config.Rule{Key: "server.timing.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.timing.header", Type: config.TypeBool},
+		&config.Rule{Key: "server.max_connections", Type: config.TypeInt},
+		&config.Rule{Key: "server.upgrade.enable", Type: config.TypeBool},
+		&config.Rule{Key: "server.upgrade.signal", Type: config.TypeString},
+		&config.Rule{Key: "server.websocket.enable", Type: config.TypeBool},
+
+		// request
+		&config.Rule{Key: "request.max_body_size", Type: config.TypeBytes},
+		&config.Rule{Key: "request.timeout", Type: config.TypeDuration},
+		&config.Rule{Key: "request.id.enable", Type: config.TypeBool},
+		&config.Rule{Key: "request.id.header", Type: config.TypeString},
+		&config.Rule{Key: "request.content_negotiation.enable", Type: config.TypeBool},
+		&config.Rule{Key: "request.auto_bind.priority", Type: config.TypeString},
+		&config.Rule{Key: "request.auto_bind.tag_name", Type: config.TypeString},
+		&config.Rule{Key: "request.auto_bind.redirect_back", Type: config.TypeBool},
+
+		// render
+		&config.Rule{Key: "render.default", Type: config.TypeString},
+		&config.Rule{Key: "render.gzip.enable", Type: config.TypeBool},
+		&config.Rule{Key: "render.gzip.level", Type: config.TypeInt, Min: 1, Max: 9},
+		&config.Rule{Key: "render.minify.enable", Type: config.TypeBool},
+		&config.Rule{Key: "render.secure_json.prefix", Type: config.TypeString},
+		&config.Rule{Key: "render.pagination.per_page", Type: config.TypeInt, Min: 1, Max: 1 << 16},
+		&config.Rule{Key: "render.pagination.max_per_page", Type: config.TypeInt, Min: 1, Max: 1 << 16},
+
+		// static
+		&config.Rule{Key: "static.dir_listing.show_hidden", Type: config.TypeBool},
+		&config.Rule{Key: "static.dir_listing.date_format", Type: config.TypeString},
+		&config.Rule{Key: "static.precompressed", Type: config.TypeBool},
+		&config.Rule{Key: "static.etag", Type: config.TypeBool},
+
+		// view
+		&config.Rule{Key: "view.enable", Type: config.TypeBool},
+		&config.Rule{Key: "view.engine", Type: config.TypeString},
+		&config.Rule{Key: "view.ext", Type: config.TypeString},
+		&config.Rule{Key: "view.case_sensitive", Type: config.TypeBool},
+		&config.Rule{Key: "view.default_layout", Type: config.TypeBool},
+		&config.Rule{Key: "view.delimiters", Type: config.TypeString},
+
+		// log
+		&config.Rule{Key: "log.receiver", Type: config.TypeString, Enum: []string{"console", "file", "syslog", "journal", "http"}},
+		&config.Rule{Key: "log.level", Type: config.TypeString, Enum: []string{"fatal", "panic", "error", "warn", "info", "debug", "trace"}},
+		&config.Rule{Key: "log.format", Type: config.TypeString, Enum: []string{"text", "json"}},
+		&config.Rule{Key: "log.pattern", Type: config.TypeString},
+		&config.Rule{Key: "log.file", Type: config.TypeString},
+		&config.Rule{Key: "log.rotate.policy", Type: config.TypeString, Enum: []string{"daily", "lines", "size"}},
+		&config.Rule{Key: "log.rotate.mode", Type: config.TypeString, Deprecated: "log.rotate.policy"},
+		&config.Rule{Key: "log.rotate.lines", Type: config.TypeInt},
+		&config.Rule{Key: "log.rotate.size", Type: config.TypeString},
+		&config.Rule{Key: "log.rotate.compress", Type: config.TypeBool},
+		&config.Rule{Key: "log.rotate.max_backups", Type: config.TypeInt},
+		&config.Rule{Key: "log.rotate.max_size", Type: config.TypeString},
+
+		// runtime
+		&config.Rule{Key: "runtime.debug.stack_buffer_size", Type: config.TypeBytes},
+		&config.Rule{Key: "runtime.debug.all_goroutines", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.debug.strip_src_base", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.debug.context_audit", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.config_hotreload.enable", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.config_hotreload.signal", Type: config.TypeString},
+		&config.Rule{Key: "runtime.diagnosis.enable", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.diagnosis.mode", Type: config.TypeString},
+		&config.Rule{Key: "runtime.diagnosis.http.address", Type: config.TypeString},
+		&config.Rule{Key: "runtime.diagnosis.http.timeout.write", Type: config.TypeDuration},
+		&config.Rule{Key: "runtime.supervisor.backoff.min", Type: config.TypeDuration},
+		&config.Rule{Key: "runtime.supervisor.backoff.max", Type: config.TypeDuration},
+		&config.Rule{Key: "runtime.supervisor.max_restarts", Type: config.TypeInt, Min: 0, Max: 1 << 20},
+		&config.Rule{Key: "webhook.enable", Type: config.TypeBool},
+		&config.Rule{Key: "webhook.workers", Type: config.TypeInt, Min: 1, Max: 1024},
+		&config.Rule{Key: "webhook.queue_size", Type: config.TypeInt, Min: 1},
+		&config.Rule{Key: "webhook.timeout", Type: config.TypeDuration},
+		&config.Rule{Key: "webhook.max_attempts", Type: config.TypeInt, Min: 1, Max: 100},
+		&config.Rule{Key: "webhook.backoff.min", Type: config.TypeDuration},
+		&config.Rule{Key: "webhook.backoff.max", Type: config.TypeDuration},
+		&config.Rule{Key: "webhook.retention", Type: config.TypeInt, Min: 1},
+		&config.Rule{Key: "webhook.signature_header", Type: config.TypeString},
+		&config.Rule{Key: "runtime.pools.buffer.max_retained_size", Type: config.TypeBytes},
+		&config.Rule{Key: "runtime.pools.buffer.tiers", Type: config.TypeList},
+		&config.Rule{Key: "runtime.health.enable", Type: config.TypeBool},
+		&config.Rule{Key: "runtime.health.path", Type: config.TypeString},
+
+		// rewrites
+		&config.Rule{Key: "rewrites.enable", Type: config.TypeBool},
+
+		// error views
+		&config.Rule{Key: "error_views.enable", Type: config.TypeBool},
+
+		// security
+		&config.Rule{Key: "security.http_header.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.session.mode", Type: config.TypeString, Enum: []string{"stateless", "stateful"}},
+		&config.Rule{Key: "security.session.store.type", Type: config.TypeString},
+		&config.Rule{Key: "security.session.sign_key", Type: config.TypeString},
+		&config.Rule{Key: "security.session.enc_key", Type: config.TypeString},
+		&config.Rule{Key: "security.session.ttl", Type: config.TypeString},
+		&config.Rule{Key: "security.session.idle_timeout", Type: config.TypeDuration},
+		&config.Rule{Key: "security.session.absolute_timeout", Type: config.TypeDuration},
+		&config.Rule{Key: "security.session.max_concurrent", Type: config.TypeInt},
+		&config.Rule{Key: "security.session.concurrent_policy", Type: config.TypeString, Enum: []string{"deny_new", "kick_oldest"}},
+		&config.Rule{Key: "security.anti_csrf.mode", Type: config.TypeString, Enum: []string{"standard", "double_submit"}},
+		&config.Rule{Key: "security.guest.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.guest.principal", Type: config.TypeString},
+		&config.Rule{Key: "security.guest.roles", Type: config.TypeList},
+		&config.Rule{Key: "security.guest.permissions", Type: config.TypeList},
+		&config.Rule{Key: "security.guest.lazy_session", Type: config.TypeBool},
+		&config.Rule{Key: "security.throttle.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.throttle.cache", Type: config.TypeString},
+		&config.Rule{Key: "security.throttle.base_delay", Type: config.TypeDuration},
+		&config.Rule{Key: "security.throttle.max_delay", Type: config.TypeDuration},
+		&config.Rule{Key: "security.throttle.lockout_threshold", Type: config.TypeInt, Min: 1, Max: 1000},
+		&config.Rule{Key: "security.throttle.lockout_duration", Type: config.TypeDuration},
+		&config.Rule{Key: "security.throttle.retention", Type: config.TypeDuration},
+		&config.Rule{Key: "security.rate_limit.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.rate_limit.limit", Type: config.TypeInt, Min: 0},
+		&config.Rule{Key: "security.rate_limit.anonymous_limit", Type: config.TypeInt, Min: 0},
+		&config.Rule{Key: "security.rate_limit.window", Type: config.TypeDuration},
+		&config.Rule{Key: "security.rate_limit.tags", Type: config.TypeList},
+		&config.Rule{Key: "security.quota.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.quota.cache", Type: config.TypeString},
+		&config.Rule{Key: "security.quota.period", Type: config.TypeString, Enum: []string{"daily", "monthly"}},
+		&config.Rule{Key: "security.quota.limit", Type: config.TypeInt, Min: 0},
+		&config.Rule{Key: "security.quota.admin.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.quota.admin.path", Type: config.TypeString},
+		&config.Rule{Key: "security.quota.admin.auth", Type: config.TypeString},
+		&config.Rule{Key: "security.quota.admin.role", Type: config.TypeString},
+		&config.Rule{Key: "security.forward_auth.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.forward_auth.path", Type: config.TypeString},
+		&config.Rule{Key: "security.forward_auth.auth", Type: config.TypeString},
+		&config.Rule{Key: "security.forward_auth.header_prefix", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.enable", Type: config.TypeBool},
+		&config.Rule{Key: "security.auth_server.issuer", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.path_prefix", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.auth", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.consent", Type: config.TypeBool},
+		&config.Rule{Key: "security.auth_server.consent_view", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.signing_key_file", Type: config.TypeString},
+		&config.Rule{Key: "security.auth_server.access_token_ttl", Type: config.TypeDuration},
+		&config.Rule{Key: "security.auth_server.id_token_ttl", Type: config.TypeDuration},
+		&config.Rule{Key: "security.auth_server.code_ttl", Type: config.TypeDuration},
+		&config.Rule{Key: "security.password_policy.min_length", Type: config.TypeInt},
+		&config.Rule{Key: "security.password_policy.max_length", Type: config.TypeInt},
+		&config.Rule{Key: "security.password_policy.require_uppercase", Type: config.TypeBool},
+		&config.Rule{Key: "security.password_policy.require_lowercase", Type: config.TypeBool},
+		&config.Rule{Key: "security.password_policy.require_digit", Type: config.TypeBool},
+		&config.Rule{Key: "security.password_policy.require_special", Type: config.TypeBool},
+		&config.Rule{Key: "security.password_policy.breached_list", Type: config.TypeString},
+	)
+	s.ProfilePrefix = "env."
+	return s
+}
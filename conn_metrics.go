@@ -0,0 +1,165 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ConnStats struct holds the aah server connection and listener metrics since
+// application start, it could be used for capacity planning.
+type ConnStats struct {
+	Open               int64            `json:"open"`
+	Accepted           int64            `json:"accepted"`
+	Rejected           int64            `json:"rejected"`
+	TLSHandshakes      int64            `json:"tls_handshakes"`
+	TLSHandshakeErrors int64            `json:"tls_handshake_errors"`
+	TLSVersions        map[string]int64 `json:"tls_versions,omitempty"`
+	TLSCipherSuites    map[string]int64 `json:"tls_cipher_suites,omitempty"`
+}
+
+// ConnStats method returns the aah server connection and listener metrics.
+func (a *Application) ConnStats() ConnStats {
+	return a.connMetrics.stats()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// listen method creates the server listener for the given network and
+// address, wrapped with connection metrics and `server.max_connections` limit.
+// On binary upgrade the listener is inherited from the old process and
+// the old process is signaled to shutdown.
+func (a *Application) listen(network, address string) (net.Listener, error) {
+	l, err := a.inheritOrListen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	a.notifyUpgradeParent()
+	maxConns := int64(a.Config().IntDefault("server.max_connections", 0))
+	if maxConns > 0 {
+		a.Log().Infof("App Max Connections: %d", maxConns)
+	}
+	return &metricsListener{Listener: l, m: &a.connMetrics, maxConns: maxConns}, nil
+}
+
+// trackTLS method adds TLS handshake metrics on the server TLS config and
+// connection state hook.
+func (a *Application) trackTLS() {
+	if a.server.TLSConfig == nil {
+		a.server.TLSConfig = &tls.Config{}
+	}
+	verifyConn := a.server.TLSConfig.VerifyConnection
+	a.server.TLSConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if verifyConn != nil {
+			if err := verifyConn(cs); err != nil {
+				return err
+			}
+		}
+		a.connMetrics.addHandshake(cs)
+		return nil
+	}
+
+	connState := a.server.ConnState
+	a.server.ConnState = func(c net.Conn, state http.ConnState) {
+		if connState != nil {
+			connState(c, state)
+		}
+		if state != http.StateClosed {
+			return
+		}
+		if tc, ok := c.(*tls.Conn); ok && !tc.ConnectionState().HandshakeComplete {
+			atomic.AddInt64(&a.connMetrics.tlsHandshakeErrors, 1)
+		}
+	}
+}
+
+type connMetrics struct {
+	open               int64
+	accepted           int64
+	rejected           int64
+	tlsHandshakes      int64
+	tlsHandshakeErrors int64
+
+	mu              sync.Mutex
+	tlsVersions     map[string]int64
+	tlsCipherSuites map[string]int64
+}
+
+func (m *connMetrics) addHandshake(cs tls.ConnectionState) {
+	atomic.AddInt64(&m.tlsHandshakes, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tlsVersions == nil {
+		m.tlsVersions = make(map[string]int64)
+		m.tlsCipherSuites = make(map[string]int64)
+	}
+	m.tlsVersions[tls.VersionName(cs.Version)]++
+	m.tlsCipherSuites[tls.CipherSuiteName(cs.CipherSuite)]++
+}
+
+func (m *connMetrics) stats() ConnStats {
+	s := ConnStats{
+		Open:               atomic.LoadInt64(&m.open),
+		Accepted:           atomic.LoadInt64(&m.accepted),
+		Rejected:           atomic.LoadInt64(&m.rejected),
+		TLSHandshakes:      atomic.LoadInt64(&m.tlsHandshakes),
+		TLSHandshakeErrors: atomic.LoadInt64(&m.tlsHandshakeErrors),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.tlsVersions) > 0 {
+		s.TLSVersions = make(map[string]int64, len(m.tlsVersions))
+		for k, v := range m.tlsVersions {
+			s.TLSVersions[k] = v
+		}
+		s.TLSCipherSuites = make(map[string]int64, len(m.tlsCipherSuites))
+		for k, v := range m.tlsCipherSuites {
+			s.TLSCipherSuites[k] = v
+		}
+	}
+	return s
+}
+
+// metricsListener tracks the accepted, open and rejected connections.
+// Connections beyond `maxConns` are closed immediately as rejected.
+type metricsListener struct {
+	net.Listener
+	m        *connMetrics
+	maxConns int64
+}
+
+func (l *metricsListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		atomic.AddInt64(&l.m.accepted, 1)
+		if open := atomic.AddInt64(&l.m.open, 1); l.maxConns > 0 && open > l.maxConns {
+			atomic.AddInt64(&l.m.open, -1)
+			atomic.AddInt64(&l.m.rejected, 1)
+			_ = c.Close()
+			continue
+		}
+		return &metricsConn{Conn: c, m: l.m}, nil
+	}
+}
+
+type metricsConn struct {
+	net.Conn
+	m    *connMetrics
+	once sync.Once
+}
+
+func (c *metricsConn) Close() error {
+	c.once.Do(func() { atomic.AddInt64(&c.m.open, -1) })
+	return c.Conn.Close()
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnMetrics(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.Config().SetInt("server.max_connections", 1)
+
+	ln, err := a.listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	_ = ts.Listener.Close()
+	ts.Listener = ln
+	a.server = ts.Config
+	a.trackTLS()
+	ts.TLS = a.server.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	// successful TLS handshake, connection is kept alive
+	resp, err := ts.Client().Get(ts.URL)
+	assert.Nil(t, err)
+	_, _ = ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	stats := a.ConnStats()
+	assert.Equal(t, int64(1), stats.Accepted)
+	assert.Equal(t, int64(1), stats.Open)
+	assert.Equal(t, int64(1), stats.TLSHandshakes)
+	assert.Equal(t, int64(1), stats.TLSVersions["TLS 1.3"])
+	assert.Equal(t, 1, len(stats.TLSCipherSuites))
+
+	// beyond max connections
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return a.ConnStats().Rejected == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	_ = conn.Close()
+
+	// TLS handshake failure
+	ts.Client().CloseIdleConnections()
+	assert.Eventually(t, func() bool {
+		return a.ConnStats().Open == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	conn, err = net.Dial("tcp", ln.Addr().String())
+	assert.Nil(t, err)
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	_, _ = ioutil.ReadAll(conn)
+	_ = conn.Close()
+	assert.Eventually(t, func() bool {
+		return a.ConnStats().TLSHandshakeErrors == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	stats = a.ConnStats()
+	assert.Equal(t, int64(3), stats.Accepted)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(0), stats.Open)
+	assert.Equal(t, int64(1), stats.TLSHandshakes)
+}
+
+func TestConnMetricsTLSVerify(t *testing.T) {
+	a := newApp()
+	a.server = &http.Server{TLSConfig: &tls.Config{
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return tls.AlertError(40)
+		},
+	}}
+	a.trackTLS()
+	assert.NotNil(t, a.server.TLSConfig.VerifyConnection(tls.ConnectionState{Version: tls.VersionTLS12}))
+	assert.Equal(t, int64(0), a.ConnStats().TLSHandshakes)
+	assert.Nil(t, a.ConnStats().TLSVersions)
+}
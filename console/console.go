@@ -43,6 +43,9 @@ type (
 	// BoolFlag is a flag with type bool
 	BoolFlag = cli.BoolFlag
 
+	// StringSliceFlag is a flag with type *StringSlice
+	StringSliceFlag = cli.StringSliceFlag
+
 	// IntFlag is a flag with type int
 	IntFlag = cli.IntFlag
 
@@ -66,6 +69,17 @@ type (
 
 	// CommandsByName is a sorter interface for commands.
 	CommandsByName = cli.CommandsByName
+
+	// ActionFunc is the action to execute when no subcommands are specified.
+	ActionFunc = cli.ActionFunc
+
+	// BeforeFunc is an action to execute before any subcommands are run, but after
+	// the context is ready. If a non-nil error is returned, no subcommands are run.
+	BeforeFunc = cli.BeforeFunc
+
+	// AfterFunc is an action to execute after any subcommands are run, but after the
+	// subcommand has finished. It is run even if Action() panics.
+	AfterFunc = cli.AfterFunc
 )
 
 // NewApp creates a new console Application with some reasonable
@@ -5,11 +5,13 @@
 package aah
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -35,7 +37,8 @@ var (
 
 // Context type for aah framework, gets embedded in application controller.
 //
-// Note: this is not standard package `context.Context`.
+// Note: this is not standard package `context.Context`, use
+// `ctx.GoContext()` to get request scoped one.
 type Context struct {
 	// Req is HTTP request instance
 	Req *ahttp.Request
@@ -60,32 +63,94 @@ type Context struct {
 	domain     *router.Domain
 	route      *router.Route
 	subject    *security.Subject
+	tenant     *Tenant
+	quota      *Quota
 	reply      *Reply
 	viewArgs   map[string]interface{}
 	values     map[string]interface{}
+	instances  map[reflect.Type]reflect.Value
 	abort      bool
 	decorated  bool
 	logger     log.Loggerer
+	goCancel   context.CancelFunc
+	goCancels  []context.CancelFunc
+
+	keyedValues map[uint64]interface{}
+	mwReached   *Middleware
+	mwAbort     *MiddlewareAbort
+	clientWatch *clientWatch
+	clientGone  bool
+	timings     []Timing
+	canary      string
+	released    *contextRelease
 }
 
 // Reply method gives you control and convenient way to write
 // a response effectively.
 func (ctx *Context) Reply() *Reply {
+	ctx.checkReleased()
 	if ctx.reply == nil {
 		ctx.reply = newReply(ctx)
 	}
 	return ctx.reply
 }
 
+// GoContext method returns the request scoped Go `context.Context`. It gets
+// cancelled when the client connection closes or the request processing
+// exceeds `request.timeout`, pass it to the database calls and outbound
+// requests so they stop doing work for gone clients.
+//
+// 	rows, err := aah.App().DB("default").QueryContext(c.GoContext(), query, args...)
+func (ctx *Context) GoContext() context.Context {
+	ctx.checkReleased()
+	if ctx.Req == nil || ctx.Req.Unwrap() == nil {
+		return context.Background()
+	}
+	return ctx.Req.Unwrap().Context()
+}
+
+// OnClientGone method registers the func that gets called when the client
+// connection closes before the reply is written, it's called in a separate
+// goroutine. It lets the long running actions (exports, SSE, proxies) abort
+// early. If client has already gone, func is called immediately.
+//
+// Client gone requests are marked in the access log via `%clientgone`.
+//
+// 	func (c *ReportController) Export() {
+// 		aborted := make(chan struct{})
+// 		c.OnClientGone(func() { close(aborted) })
+// 		...
+// 	}
+func (ctx *Context) OnClientGone(fn func()) {
+	ctx.checkReleased()
+	if ctx.clientWatch == nil {
+		ctx.clientWatch = newClientWatch(ctx.GoContext())
+	}
+	if !ctx.clientWatch.add(fn) {
+		fn()
+	}
+}
+
+// IsClientGone method returns true if the client connection closed before
+// the reply is written.
+func (ctx *Context) IsClientGone() bool {
+	if ctx.clientWatch != nil && ctx.clientWatch.isGone() {
+		return true
+	}
+	return ctx.clientGone || ctx.GoContext().Err() == context.Canceled
+}
+
 // ViewArgs method returns aah framework and request related info that can be
 // used in template or view rendering, etc.
 func (ctx *Context) ViewArgs() map[string]interface{} {
+	ctx.checkReleased()
 	return ctx.viewArgs
 }
 
 // AddViewArg method adds given key and value into `viewArgs`. These view args
 // values accessible on templates. Chained call is possible.
 func (ctx *Context) AddViewArg(key string, value interface{}) *Context {
+	ctx.checkReleased()
 	if ctx.viewArgs == nil {
 		ctx.viewArgs = make(map[string]interface{})
 	}
@@ -96,13 +161,41 @@ func (ctx *Context) AddViewArg(key string, value interface{}) *Context {
 // RouteURL method returns the URL for given route name and args.
 // See `router.Domain.RouteURL` for more information.
 func (ctx *Context) RouteURL(routeName string, args ...interface{}) string {
-	return ctx.a.Router().CreateRouteURL(ctx.Req.Host, routeName, nil, args...)
+	return ctx.a.Router().CreateLocaleRouteURL(ctx.Req.Host, ctx.RouteLocale(), ctx.APIVersion(), routeName, nil, args...)
 }
 
 // RouteURLNamedArgs method returns the URL for given route name and key-value paris.
 // See `router.Domain.RouteURLNamedArgs` for more information.
 func (ctx *Context) RouteURLNamedArgs(routeName string, args map[string]interface{}) string {
-	return ctx.a.Router().CreateRouteURL(ctx.Req.Host, routeName, args)
+	return ctx.a.Router().CreateLocaleRouteURL(ctx.Req.Host, ctx.RouteLocale(), ctx.APIVersion(), routeName, args)
+}
+
+// APIVersion method returns the API version of the current request. Version
+// value is from route `version` attribute if it's declared otherwise it's
+// resolved per domain `api_version` strategy. For e.g.: `v1`, `2`, etc.
+//
+// Route URL methods `ctx.RouteURL` and `ctx.RouteURLNamedArgs` composes
+// reverse URL within this API version.
+func (ctx *Context) APIVersion() string {
+	if ctx.route != nil && len(ctx.route.Version) > 0 {
+		return ctx.route.Version
+	}
+	if ctx.domain != nil && ctx.domain.APIVersion != nil {
+		return ctx.domain.APIVersion.Resolve(ctx.Req.Unwrap())
+	}
+	return ""
+}
+
+// RouteLocale method returns the locale of current route if it's served with
+// locale prefix (domain `locale_prefix`) otherwise empty string.
+//
+// Route URL methods `ctx.RouteURL` and `ctx.RouteURLNamedArgs` composes
+// reverse URL with this locale prefix.
+func (ctx *Context) RouteLocale() string {
+	if ctx.route != nil {
+		return ctx.route.Locale
+	}
+	return ""
 }
 
 // Msg method returns the i18n value for given key otherwise empty string returned.
@@ -113,7 +206,22 @@ func (ctx *Context) Msg(key string, args ...interface{}) string {
 // Msgl method returns the i18n value for given local and key otherwise
 // empty string returned.
 func (ctx *Context) Msgl(locale *ahttp.Locale, key string, args ...interface{}) string {
-	return ctx.a.I18n().Lookup(locale, key, args...)
+	return ctx.a.i18nLookup(ctx.tenant, locale, key, args...)
+}
+
+// Negotiate method negotiates the best offer from the given content type
+// offers with the request `Accept` header, honors quality factor and vendor
+// media types. It returns empty string if none of the offers acceptable.
+// See `ahttp.Negotiate` for more information.
+//
+// 	switch ctx.Negotiate("application/json", "text/csv") {
+// 	case "application/json":
+// 		// ...
+// 	case "text/csv":
+// 		// ...
+// 	}
+func (ctx *Context) Negotiate(offers ...string) string {
+	return ahttp.Negotiate(ctx.Req.Unwrap(), offers...)
 }
 
 // Subdomain method returns the subdomain from the incoming request if available
@@ -129,8 +237,10 @@ func (ctx *Context) Subdomain() string {
 
 // Subject method the subject (aka application user) of current request.
 func (ctx *Context) Subject() *security.Subject {
+	ctx.checkReleased()
 	if ctx.subject == nil {
 		ctx.subject = security.AcquireSubject()
+		ctx.subject.OnRunAs(ctx.publishRunAsEvent)
 	}
 	return ctx.subject
 }
@@ -139,6 +249,7 @@ func (ctx *Context) Subject() *security.Subject {
 // to identify whether sesison is newly created or restored from the request
 // which was already created.
 func (ctx *Context) Session() *session.Session {
+	ctx.checkReleased()
 	if ctx.Subject().Session == nil {
 		ctx.subject.Session = ctx.a.SessionManager().NewSession()
 	}
@@ -156,9 +267,17 @@ func (ctx *Context) Session() *session.Session {
 //    3) If it's called in Mapped <Action> then After<Action> interceptor and
 // 	After interceptor will not execute; framework starts processing response.
 func (ctx *Context) Abort() {
+	ctx.checkReleased()
 	ctx.abort = true
 }
 
+// Route method returns the route of current request, it is nil until the
+// request is routed or route not found. Route tags could be used in the
+// cross-cutting code, for e.g.: `ctx.Route().HasTag("billing")`.
+func (ctx *Context) Route() *router.Route {
+	return ctx.route
+}
+
 // IsStaticRoute method returns true if it's static route otherwise false.
 func (ctx *Context) IsStaticRoute() bool {
 	if ctx.route != nil {
@@ -229,16 +348,36 @@ func (ctx *Context) reset() {
 	ctx.domain = nil
 	ctx.route = nil
 	ctx.subject = nil
+	ctx.tenant = nil
+	ctx.quota = nil
 	ctx.reply = nil
 	ctx.viewArgs = nil
 	ctx.values = nil
+	ctx.keyedValues = nil
+	ctx.mwReached = nil
+	ctx.mwAbort = nil
+	ctx.instances = nil
 	ctx.abort = false
 	ctx.decorated = false
 	ctx.logger = nil
+	ctx.goCancel = nil
+	ctx.goCancels = nil
+	ctx.clientWatch = nil
+	ctx.clientGone = false
+	ctx.timings = nil
+	ctx.canary = ""
+}
+
+// MiddlewareAbort method returns the details of the middleware which
+// short-circuited the middleware chain otherwise nil. It is available after
+// the middleware chain execution, for e.g.: in `OnPreReply` event.
+func (ctx *Context) MiddlewareAbort() *MiddlewareAbort {
+	return ctx.mwAbort
 }
 
 // Set method is used to set value for the given key in the current request flow.
 func (ctx *Context) Set(key string, value interface{}) {
+	ctx.checkReleased()
 	if ctx.values == nil {
 		ctx.values = make(map[string]interface{})
 	}
@@ -247,12 +386,14 @@ func (ctx *Context) Set(key string, value interface{}) {
 
 // Get method returns the value for the given key, otherwise it returns nil.
 func (ctx *Context) Get(key string) interface{} {
+	ctx.checkReleased()
 	return ctx.values[key]
 }
 
 // Log method adds field `Request ID` into current log context and returns
 // the logger.
 func (ctx *Context) Log() log.Loggerer {
+	ctx.checkReleased()
 	if ctx.logger == nil {
 		if h := ctx.Req.Header[ctx.a.settings.RequestIDHeaderKey]; len(h) > 0 {
 			ctx.logger = ctx.a.Log().WithFields(log.Fields{
@@ -269,6 +410,19 @@ func (ctx *Context) Log() log.Loggerer {
 // Context Unexported methods
 //______________________________________________________________________________
 
+// recordShortCircuit method records the middleware which did not proceed
+// further in the chain, if not already recorded via `Middleware.Abort`.
+func (ctx *Context) recordShortCircuit() {
+	if ctx.mwAbort != nil || ctx.mwReached == nil || ctx.mwReached.further == nil {
+		return
+	}
+	ma := &MiddlewareAbort{Middleware: ctx.mwReached.owner, Status: ctx.Reply().Code}
+	if e := ctx.Reply().err; e != nil {
+		ma.Err = e.Reason
+	}
+	ctx.mwAbort = ma
+}
+
 func (ctx *Context) setRequestID() {
 	h := ctx.Req.Header[ctx.a.settings.RequestIDHeaderKey]
 	if len(h) == 0 {
@@ -283,26 +437,34 @@ func (ctx *Context) setRequestID() {
 // setTarget method sets contoller, action, embedded context into
 // controller.
 func (ctx *Context) setTarget(route *router.Route) error {
-	if ctx.route == nil || ctx.target != nil {
+	if ctx.route == nil || ctx.route.IsProxy() || ctx.target != nil {
 		return nil
 	}
 
-	if ctx.controller = ctx.e.registry.Lookup(route.Target); ctx.controller == nil {
+	targetName, actionName := route.Target, route.Action
+	if route.Canary != nil {
+		targetName, actionName = ctx.canaryTarget(route)
+	}
+
+	if ctx.controller = ctx.e.registry.Lookup(targetName); ctx.controller == nil {
 		return errTargetNotFound
 	}
 
-	if ctx.action = ctx.controller.Lookup(route.Action); ctx.action == nil {
+	if ctx.action = ctx.controller.Lookup(actionName); ctx.action == nil {
 		return errTargetNotFound
 	}
 
-	target := reflect.New(ctx.controller.Type)
-	ctx.target = target.Interface()
+	target, err := ctx.a.injector.construct(ctx.a, ctx, ctx.controller)
+	if err != nil {
+		return err
+	}
 
 	// check action method exists or not
-	ctx.actionrv = reflect.ValueOf(ctx.target).MethodByName(ctx.action.Name)
+	ctx.actionrv = target.MethodByName(ctx.action.Name)
 	if !ctx.actionrv.IsValid() {
 		return errTargetNotFound
 	}
+	ctx.target = target.Interface()
 
 	targetElem := target.Elem()
 	ctxrv := reflect.ValueOf(ctx)
@@ -333,6 +495,11 @@ func (ctx *Context) writeCookies() {
 
 	if ctx.a.SessionManager().IsStateful() && ctx.a.SessionManager().IsPath(ctx.Req.Path) {
 		if ctx.subject != nil && ctx.subject.Session != nil {
+			if ctx.tenant != nil {
+				if domain := ctx.tenant.Config.StringDefault("security.session.domain", ""); len(domain) > 0 {
+					ctx.subject.Session.SetCookieDomain(domain)
+				}
+			}
 			if err := ctx.a.SessionManager().SaveSession(ctx.Res, ctx.subject.Session); err != nil {
 				ctx.Log().Error(err)
 			}
@@ -384,6 +551,13 @@ func (ctx *Context) writeHeaders() {
 			}
 		}
 	}
+
+	// Response header policy of route or domain from routes config
+	if ctx.route != nil && ctx.route.Headers != nil {
+		ctx.route.Headers.Apply(ctx.Res.Header())
+	} else if ctx.route == nil && ctx.domain != nil && ctx.domain.Headers != nil {
+		ctx.domain.Headers.Apply(ctx.Res.Header())
+	}
 }
 
 // hasAccess method checks the subject's access by defined access rule in the
@@ -391,3 +565,98 @@ func (ctx *Context) writeHeaders() {
 func (ctx *Context) hasAccess() (bool, []*authz.Reason) {
 	return ctx.route.HasAccess(ctx.Subject())
 }
+
+// markReplied method stops watching the client connection, reply is getting
+// written. Client gone callbacks are fired if the client has already gone.
+func (ctx *Context) markReplied() {
+	gone := ctx.GoContext().Err() == context.Canceled
+	if ctx.clientWatch != nil {
+		if gone {
+			ctx.clientWatch.gone()
+		}
+		ctx.clientWatch.stop()
+		gone = ctx.clientWatch.isGone()
+	}
+	if gone && !ctx.clientGone {
+		ctx.clientGone = true
+		ctx.Log().Debugf("Client gone before the reply is written: %s", ctx.Req.Path)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Client watch
+//______________________________________________________________________________
+
+const (
+	clientWatching uint8 = iota
+	clientReplied
+	clientGone
+)
+
+// clientWatch watches the request Go context for client disconnect till the
+// reply gets written. It's not tied to the pooled context, so the watcher
+// goroutine never touches reused context.
+type clientWatch struct {
+	mu    sync.Mutex
+	state uint8
+	fns   []func()
+	done  chan struct{}
+}
+
+func newClientWatch(goCtx context.Context) *clientWatch {
+	cw := &clientWatch{done: make(chan struct{})}
+	go func() {
+		select {
+		case <-cw.done:
+		case <-goCtx.Done():
+			// request timeout is not a client disconnect
+			if goCtx.Err() == context.Canceled {
+				cw.gone()
+			}
+		}
+	}()
+	return cw
+}
+
+// add method adds the func, returns false if client has already gone.
+func (cw *clientWatch) add(fn func()) bool {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	if cw.state == clientGone {
+		return false
+	}
+	cw.fns = append(cw.fns, fn)
+	return true
+}
+
+func (cw *clientWatch) gone() {
+	cw.mu.Lock()
+	if cw.state != clientWatching {
+		cw.mu.Unlock()
+		return
+	}
+	cw.state = clientGone
+	fns := cw.fns
+	cw.fns = nil
+	cw.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+func (cw *clientWatch) stop() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	if cw.state == clientWatching {
+		cw.state = clientReplied
+		cw.fns = nil
+		close(cw.done)
+	}
+}
+
+func (cw *clientWatch) isGone() bool {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return cw.state == clientGone
+}
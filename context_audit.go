@@ -0,0 +1,51 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// contextRelease holds the request details of the released context, it is
+// used by context audit mode `runtime.debug.context_audit`.
+type contextRelease struct {
+	method    string
+	path      string
+	requestID string
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context Unexported methods
+//______________________________________________________________________________
+
+// newContextRelease method captures the request details before the context
+// gets reset.
+func (ctx *Context) newContextRelease() *contextRelease {
+	cr := &contextRelease{}
+	if ctx.Req != nil {
+		cr.method, cr.path = ctx.Req.Method, ctx.Req.Path
+		cr.requestID = ctx.Req.Header.Get(ctx.a.settings.RequestIDHeaderKey)
+	}
+	return cr
+}
+
+// checkReleased method panics if the context is used after the release in
+// context audit mode. Panic message has the leaking call site, i.e. caller
+// of the context method.
+func (ctx *Context) checkReleased() {
+	if ctx.released == nil {
+		return
+	}
+	site := "unknown"
+	if pc, file, line, ok := runtime.Caller(2); ok {
+		site = fmt.Sprintf("%s:%d", file, line)
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			site = fn.Name() + " (" + site + ")"
+		}
+	}
+	panic(fmt.Sprintf("aah: context used after release, request '%s %s' [%s], leaking call site %s",
+		ctx.released.method, ctx.released.path, ctx.released.requestID, site))
+}
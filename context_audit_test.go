@@ -0,0 +1,71 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextAuditUseAfterRelease(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	newAuditCtx := func() *Context {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+		req.Header.Set(ahttp.HeaderXRequestID, "req-1001")
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a, ctx.e = a, a.he
+		return ctx
+	}
+
+	// audit mode disabled, context goes back to pool
+	ctx := newAuditCtx()
+	a.he.releaseContext(ctx)
+	assert.Nil(t, ctx.released)
+	assert.NotPanics(t, func() { ctx.Set("key", "value") })
+
+	a.settings.ContextAuditEnabled = true
+	defer func() { a.settings.ContextAuditEnabled = false }()
+
+	ctx = newAuditCtx()
+	ctx.Set("key", "value")
+	a.he.releaseContext(ctx)
+	assert.NotNil(t, ctx.released)
+	assert.Equal(t, "/orders", ctx.released.path)
+	assert.Equal(t, "req-1001", ctx.released.requestID)
+
+	for name, fn := range map[string]func(){
+		"Reply":     func() { ctx.Reply().Text("leaked") },
+		"Get":       func() { _ = ctx.Get("key") },
+		"Set":       func() { ctx.Set("key", "value") },
+		"Log":       func() { ctx.Log().Info("leaked") },
+		"Subject":   func() { _ = ctx.Subject() },
+		"GoContext": func() { _ = ctx.GoContext() },
+	} {
+		msg := capturePanic(fn)
+		assert.True(t, strings.HasPrefix(msg, "aah: context used after release, request 'GET /orders' [req-1001]"), name)
+		assert.True(t, strings.Contains(msg, "context_audit_test.go"), name)
+	}
+}
+
+func capturePanic(fn func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	fn()
+	return
+}
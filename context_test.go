@@ -5,12 +5,19 @@
 package aah
 
 import (
+	"context"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
+	"aahframe.work/essentials"
 	"aahframe.work/log"
 	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +42,23 @@ func testSubdomainValue(t *testing.T, host, subdomain string, isSubdomain bool)
 	assert.Equal(t, subdomain, ctx.Subdomain())
 }
 
+func TestContextAPIVersion(t *testing.T) {
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/users", nil)
+	req.Header.Set("X-API-Version", "2")
+	ctx := &Context{
+		Req:    ahttp.AcquireRequest(req),
+		domain: &router.Domain{},
+		route:  &router.Route{},
+	}
+	assert.Equal(t, "", ctx.APIVersion())
+
+	ctx.domain.APIVersion = &router.APIVersion{Strategy: router.APIVersionByHeader, Header: "X-API-Version", Default: "1"}
+	assert.Equal(t, "2", ctx.APIVersion())
+
+	ctx.route.Version = "3"
+	assert.Equal(t, "3", ctx.APIVersion())
+}
+
 func TestContextSetURL(t *testing.T) {
 	a := newApp()
 	a.cfg = config.NewEmpty()
@@ -105,3 +129,108 @@ func TestContextSetMethod(t *testing.T) {
 	ctx.SetMethod("nomethod")
 	assert.Equal(t, "GET", ctx.Req.Method)
 }
+
+func TestContextGoContext(t *testing.T) {
+	ctx := &Context{}
+	assert.Equal(t, context.Background(), ctx.GoContext())
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Equal(t, 90*time.Second, a.settings.RequestTimeout)
+
+	var goCtx context.Context
+	a.he.Middlewares(func(ctx *Context, m *Middleware) {
+		goCtx = ctx.GoContext()
+		select {
+		case <-goCtx.Done():
+			ctx.Reply().ServiceUnavailable().Text(goCtx.Err().Error())
+		case <-time.After(time.Second):
+			ctx.Reply().Ok().Text("completed")
+		}
+	})
+
+	// request timeout
+	a.settings.RequestTimeout = 10 * time.Millisecond
+	w := httptest.NewRecorder()
+	a.he.Handle(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), w.Body.String())
+
+	// client disconnect
+	a.settings.RequestTimeout = 0
+	reqCtx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	w = httptest.NewRecorder()
+	a.he.Handle(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil).WithContext(reqCtx))
+	assert.Equal(t, context.Canceled.Error(), w.Body.String())
+
+	// cancelled once the request is completed
+	a.settings.RequestTimeout = 2 * time.Second
+	a.he.Middlewares(func(ctx *Context, m *Middleware) {
+		goCtx = ctx.GoContext()
+		ctx.Reply().Ok().Text("completed")
+	})
+	w = httptest.NewRecorder()
+	a.he.Handle(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, "completed", w.Body.String())
+	assert.Equal(t, context.Canceled, goCtx.Err())
+}
+
+func TestContextOnClientGone(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.settings.RequestTimeout = 0
+
+	var gone int32
+	a.he.Middlewares(func(ctx *Context, m *Middleware) {
+		aborted := make(chan struct{})
+		ctx.OnClientGone(func() {
+			atomic.AddInt32(&gone, 1)
+			close(aborted)
+		})
+		select {
+		case <-aborted:
+			assert.True(t, ctx.IsClientGone())
+			ctx.OnClientGone(func() { atomic.AddInt32(&gone, 1) }) // called immediately
+		case <-time.After(10 * time.Millisecond):
+			ctx.Reply().Ok().Text("completed")
+		}
+	})
+
+	// client disconnect
+	reqCtx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(5*time.Millisecond, cancel)
+	a.he.Handle(httptest.NewRecorder(), httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil).WithContext(reqCtx))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&gone))
+
+	// reply written, cancelled afterwards
+	atomic.StoreInt32(&gone, 0)
+	reqCtx, cancel = context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	a.he.Handle(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil).WithContext(reqCtx))
+	cancel()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "completed", w.Body.String())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&gone))
+
+	// gone detected while writing the reply, without watcher
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+	reqCtx, cancel = context.WithCancel(context.Background())
+	ctx := newContext(httptest.NewRecorder(), req.WithContext(reqCtx))
+	ctx.a = a
+	assert.False(t, ctx.IsClientGone())
+	cancel()
+	assert.True(t, ctx.IsClientGone())
+	ctx.Reply().Ok().Text("completed")
+	a.he.writeReply(ctx)
+	assert.True(t, ctx.clientGone)
+
+	// access log
+	al := &accessLog{Request: ctx.Req, ClientGone: true}
+	aal := &accessLogger{a: a, fmtFlags: []ess.FmtFlagPart{{Flag: fmtFlagClientGone}}}
+	aal.logPool = &sync.Pool{New: func() interface{} { return new(accessLog) }}
+	assert.Equal(t, "client-gone", aal.accessLogFormatter(al))
+	assert.Equal(t, "-", aal.accessLogFormatter(&accessLog{}))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"aahframe.work/security/cookie"
+)
+
+// Cookie errors
+var (
+	ErrCookieSignKeyMissing = errors.New("aah: cookie sign key is not configured")
+	ErrCookieEncKeyMissing  = errors.New("aah: cookie encryption key is not configured")
+)
+
+func (a *Application) initCookies() error {
+	cfg := a.Config()
+	keyPrefix := "security.cookie"
+	sessPrefix := "security.session"
+
+	opts := &cookie.Options{
+		Domain:   cfg.StringDefault(keyPrefix+".domain", ""),
+		Path:     cfg.StringDefault(keyPrefix+".path", "/"),
+		HTTPOnly: cfg.BoolDefault(keyPrefix+".http_only", true),
+		Secure:   cfg.BoolDefault(keyPrefix+".secure", a.IsSSLEnabled()),
+		SameSite: cfg.StringDefault(keyPrefix+".samesite", "lax"),
+	}
+	if ttl, found := cfg.String(keyPrefix + ".ttl"); found {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return fmt.Errorf("aah: '%s.ttl' value is not a valid time unit", keyPrefix)
+		}
+		opts.MaxAge = int64(d.Seconds())
+	}
+
+	// Keys are shared with session unless configured, so key rotation
+	// via `old_sign_key` and `old_enc_key` applies to both
+	key := func(name string) string {
+		return cfg.StringDefault(keyPrefix+"."+name, cfg.StringDefault(sessPrefix+"."+name, ""))
+	}
+	mgr, err := cookie.NewManager(opts, key("sign_key"), key("enc_key"), key("old_sign_key"), key("old_enc_key"))
+	if err != nil {
+		return err
+	}
+
+	a.cookieMgr = &cookieManager{opts: mgr.Options, encMgr: mgr, signMgr: mgr.SignOnly()}
+	return nil
+}
+
+type cookieManager struct {
+	opts    *cookie.Options
+	signMgr *cookie.Manager
+	encMgr  *cookie.Manager
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Cookies
+//______________________________________________________________________________
+
+// Cookies method returns the cookies manager of current request, used to
+// set and get signed, encrypted cookies. Cookie attributes SameSite, Secure,
+// HttpOnly, etc. defaults from config `security.cookie { ... }`.
+func (ctx *Context) Cookies() *Cookies {
+	return &Cookies{ctx: ctx, mgr: ctx.a.cookieMgr}
+}
+
+// Cookies struct is used to manage signed and encrypted cookies, signed
+// value is readable by the client however tamper proof. Sign and encryption
+// keys are shared with session unless configured in `security.cookie`.
+type Cookies struct {
+	ctx *Context
+	mgr *cookieManager
+}
+
+// Get method returns the plain value of the named cookie from request,
+// otherwise empty string.
+func (c *Cookies) Get(name string) string {
+	if ck, err := c.ctx.Req.Cookie(name); err == nil {
+		return ck.Value
+	}
+	return ""
+}
+
+// SetSigned method adds the signed cookie for the given name and value into
+// response.
+func (c *Cookies) SetSigned(name, value string) error {
+	if !c.mgr.signMgr.IsSignEnabled() {
+		return ErrCookieSignKeyMissing
+	}
+	return c.set(c.mgr.signMgr, name, value)
+}
+
+// GetSigned method returns the verified value of the signed cookie.
+func (c *Cookies) GetSigned(name string) (string, error) {
+	if !c.mgr.signMgr.IsSignEnabled() {
+		return "", ErrCookieSignKeyMissing
+	}
+	return c.get(c.mgr.signMgr, name)
+}
+
+// SetEncrypted method adds the encrypted and signed cookie for the given name
+// and value into response.
+func (c *Cookies) SetEncrypted(name, value string) error {
+	if !c.mgr.encMgr.IsEncryptEnabled() {
+		return ErrCookieEncKeyMissing
+	}
+	return c.set(c.mgr.encMgr, name, value)
+}
+
+// GetEncrypted method returns the decrypted value of the encrypted cookie.
+func (c *Cookies) GetEncrypted(name string) (string, error) {
+	if !c.mgr.encMgr.IsEncryptEnabled() {
+		return "", ErrCookieEncKeyMissing
+	}
+	return c.get(c.mgr.encMgr, name)
+}
+
+// Delete method expires the named cookie on the client.
+func (c *Cookies) Delete(name string) {
+	opts := *c.mgr.opts
+	opts.Name = name
+	opts.MaxAge = -1
+	c.ctx.Reply().Cookie(cookie.NewWithOptions("", &opts))
+}
+
+func (c *Cookies) set(m *cookie.Manager, name, value string) error {
+	v, err := m.EncodeNamed(name, []byte(value))
+	if err != nil {
+		return err
+	}
+	opts := *c.mgr.opts
+	opts.Name = name
+	c.ctx.Reply().Cookie(cookie.NewWithOptions(v, &opts))
+	return nil
+}
+
+func (c *Cookies) get(m *cookie.Manager, name string) (string, error) {
+	ck, err := c.ctx.Req.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if ck.Value == "" {
+		return "", http.ErrNoCookie
+	}
+	b, err := m.DecodeNamed(name, ck.Value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookiesSignedEncrypted(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	newCtx := func(cookies ...*http.Cookie) *Context {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		return ctx
+	}
+
+	// keys are not configured
+	ctx := newCtx()
+	assert.Equal(t, ErrCookieSignKeyMissing, ctx.Cookies().SetSigned("user_pref", "dark"))
+	assert.Equal(t, ErrCookieEncKeyMissing, ctx.Cookies().SetEncrypted("user_token", "secret"))
+	_, err := ctx.Cookies().GetSigned("user_pref")
+	assert.Equal(t, ErrCookieSignKeyMissing, err)
+	_, err = ctx.Cookies().GetEncrypted("user_token")
+	assert.Equal(t, ErrCookieEncKeyMissing, err)
+
+	// keys shared from session config
+	a.Config().SetString("security.session.sign_key", "6440c2ed05652cd452a6ee5125f4135e665348a82be1784c06e414d79a9e27c1")
+	a.Config().SetString("security.session.enc_key", "9547aab75a1f57dcfaf38c68dfbbc80f")
+	assert.Nil(t, a.initCookies())
+
+	ctx = newCtx()
+	assert.Nil(t, ctx.Cookies().SetSigned("user_pref", "dark"))
+	assert.Nil(t, ctx.Cookies().SetEncrypted("user_token", "secret"))
+	cookies := ctx.Reply().cookies
+	assert.Equal(t, 2, len(cookies))
+	assert.Equal(t, "user_pref", cookies[0].Name)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.False(t, strings.Contains(cookies[1].Value, "secret"))
+
+	ctx = newCtx(cookies...)
+	v, err := ctx.Cookies().GetSigned("user_pref")
+	assert.Nil(t, err)
+	assert.Equal(t, "dark", v)
+	v, err = ctx.Cookies().GetEncrypted("user_token")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", v)
+	assert.Equal(t, cookies[0].Value, ctx.Cookies().Get("user_pref"))
+
+	// cookie value of other name is not accepted
+	ctx = newCtx(&http.Cookie{Name: "admin_pref", Value: cookies[0].Value})
+	_, err = ctx.Cookies().GetSigned("admin_pref")
+	assert.NotNil(t, err)
+
+	// not exists
+	_, err = ctx.Cookies().GetSigned("not_exists")
+	assert.Equal(t, http.ErrNoCookie, err)
+	assert.Equal(t, "", ctx.Cookies().Get("not_exists"))
+
+	// delete
+	ctx.Cookies().Delete("user_pref")
+	assert.Equal(t, -1, ctx.Reply().cookies[0].MaxAge)
+
+	// invalid ttl
+	a.Config().SetString("security.cookie.ttl", "1x")
+	assert.Equal(t, "aah: 'security.cookie.ttl' value is not a valid time unit", a.initCookies().Error())
+}
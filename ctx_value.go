@@ -0,0 +1,60 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import "sync/atomic"
+
+var ctxKeySeq uint64
+
+// CtxKey type is typed key for per-request value store, create it via
+// `NewCtxKey`. Each key is unique, even for the same value type.
+type CtxKey[T any] struct {
+	id uint64
+}
+
+// NewCtxKey method returns the new typed key for per-request value store.
+// Typed key eliminates the `interface{}` casts of `ctx.Set/Get`.
+//
+// 	var currentUserKey = aah.NewCtxKey[*models.User]()
+//
+// 	// in middleware
+// 	aah.SetValue(ctx, currentUserKey, user)
+//
+// 	// in controller action
+// 	if user, found := aah.Value(ctx, currentUserKey); found {
+// 		...
+// 	}
+//
+// Note: Go methods cannot have type parameters, hence the package funcs
+// instead of `ctx.SetValue/Value`.
+func NewCtxKey[T any]() *CtxKey[T] {
+	return &CtxKey[T]{id: atomic.AddUint64(&ctxKeySeq, 1)}
+}
+
+// SetValue method sets the value for given typed key in the current request
+// flow.
+func SetValue[T any](ctx *Context, key *CtxKey[T], value T) {
+	ctx.checkReleased()
+	if ctx.keyedValues == nil {
+		ctx.keyedValues = make(map[uint64]interface{})
+	}
+	ctx.keyedValues[key.id] = value
+}
+
+// Value method returns the value for given typed key and true if it exists
+// in the current request flow otherwise zero value and false.
+func Value[T any](ctx *Context, key *CtxKey[T]) (T, bool) {
+	ctx.checkReleased()
+	v, found := ctx.keyedValues[key.id]
+	t, _ := v.(T)
+	return t, found
+}
+
+// DeleteValue method deletes the value for given typed key from the current
+// request flow.
+func DeleteValue[T any](ctx *Context, key *CtxKey[T]) {
+	ctx.checkReleased()
+	delete(ctx.keyedValues, key.id)
+}
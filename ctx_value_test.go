@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxValueUser struct {
+	Name string
+}
+
+func TestContextTypedValue(t *testing.T) {
+	userKey := NewCtxKey[*ctxValueUser]()
+	countKey := NewCtxKey[int]()
+	otherCountKey := NewCtxKey[int]()
+	errKey := NewCtxKey[error]()
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/", nil))
+
+	user, found := Value(ctx, userKey)
+	assert.False(t, found)
+	assert.Nil(t, user)
+
+	SetValue(ctx, userKey, &ctxValueUser{Name: "jeeva"})
+	SetValue(ctx, countKey, 10)
+	SetValue(ctx, errKey, nil)
+
+	user, found = Value(ctx, userKey)
+	assert.True(t, found)
+	assert.Equal(t, "jeeva", user.Name)
+
+	count, found := Value(ctx, countKey)
+	assert.True(t, found)
+	assert.Equal(t, 10, count)
+
+	// keys are unique for the same type
+	count, found = Value(ctx, otherCountKey)
+	assert.False(t, found)
+	assert.Equal(t, 0, count)
+
+	err, found := Value(ctx, errKey)
+	assert.True(t, found)
+	assert.Nil(t, err)
+
+	DeleteValue(ctx, countKey)
+	_, found = Value(ctx, countKey)
+	assert.False(t, found)
+
+	// typed values are independent of string keyed values
+	ctx.Set("user", "string value")
+	assert.Equal(t, "string value", ctx.Get("user"))
+
+	ctx.reset()
+	_, found = Value(ctx, userKey)
+	assert.False(t, found)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSource(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, a.DB("default"))
+	assert.Equal(t, []string{}, a.DBNames())
+	assert.Equal(t, 0, len(a.DBStats()))
+	assert.Equal(t, 0, len(a.DBHealthCheck(time.Second)))
+
+	cfg, err := config.ParseString(`datasource {
+  default {
+    driver = "aahtestdb"
+    dsn = "default"
+    max_open = 10
+    max_idle = 2
+    max_lifetime = "30m"
+    ping_on_start = true
+    health_check_interval = "1ms"
+  }
+  reporting {
+    driver = "aahtestdb"
+    dsn = "reporting"
+  }
+}
+migrate {
+  datasource = "reporting"
+}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	err = a.initLog()
+	assert.Nil(t, err)
+
+	err = a.initDataSource()
+	assert.Nil(t, err)
+	assert.NotNil(t, a.DB("default"))
+	assert.Nil(t, a.DB("unknown"))
+	assert.Equal(t, []string{"default", "reporting"}, a.DBNames())
+	assert.Equal(t, 10, a.DBStats()["default"].MaxOpenConnections)
+	assert.Equal(t, map[string]error{"default": nil, "reporting": nil}, a.DBHealthCheck(time.Second))
+	assert.Equal(t, "aah: datasource 'unknown' not exists", a.dsMgr.Ping("unknown", time.Second).Error())
+
+	// migrate using datasource, no dialect for test driver
+	_, err = a.Migrator()
+	assert.Equal(t, "migrate: dialect not found: 'aahtestdb'", err.Error())
+	assert.NotNil(t, a.DB("reporting"))
+
+	time.Sleep(5 * time.Millisecond)
+	a.closeDataSources()
+	assert.Nil(t, a.DB("default"))
+
+	// invalid configurations
+	a.cfg, _ = config.ParseString(`datasource {
+  default {
+    driver = "aahtestdb"
+  }
+}`)
+	assert.Equal(t, "aah: datasource 'default' driver and dsn is required", a.initDataSource().Error())
+
+	a.cfg, _ = config.ParseString(`datasource {
+  default {
+    driver = "notexists"
+    dsn = "default"
+  }
+}`)
+	assert.NotNil(t, a.initDataSource())
+
+	a.cfg, _ = config.ParseString(`datasource {
+  default {
+    driver = "aahtestdb"
+    dsn = "fail"
+    ping_on_start = true
+  }
+}`)
+	assert.Equal(t, "aah: datasource 'default' ping: test ping failed", a.initDataSource().Error())
+}
+
+func init() {
+	sql.Register("aahtestdb", &testDBDriver{})
+}
+
+type testDBDriver struct{}
+
+func (testDBDriver) Open(name string) (driver.Conn, error) { return &testDBConn{dsn: name}, nil }
+
+type testDBConn struct{ dsn string }
+
+func (c *testDBConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *testDBConn) Close() error              { return nil }
+func (c *testDBConn) Begin() (driver.Tx, error) { return nil, errors.New("not implemented") }
+func (c *testDBConn) Ping(_ context.Context) error {
+	if c.dsn == "fail" {
+		return errors.New("test ping failed")
+	}
+	return nil
+}
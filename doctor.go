@@ -0,0 +1,314 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"aahframe.work/config"
+	"aahframe.work/console"
+	"aahframe.work/essentials"
+)
+
+// Doctor check names reported by `Application.Doctor`.
+const (
+	DoctorCheckToolchain = "toolchain"
+	DoctorCheckModule    = "module"
+	DoctorCheckFiles     = "files"
+	DoctorCheckConfig    = "config"
+	DoctorCheckTLS       = "tls"
+	DoctorCheckPort      = "port"
+)
+
+// minGoMinorVersion is the minimum Go version `go1.11` supported by aah.
+const minGoMinorVersion = 11
+
+// DoctorIssue holds the single issue found by doctor along with
+// actionable fix.
+type DoctorIssue struct {
+	Check   string
+	Message string
+	Fix     string
+}
+
+// String method is stringer interface implementation.
+func (di *DoctorIssue) String() string {
+	if len(di.Fix) == 0 {
+		return fmt.Sprintf("[%s] %s", di.Check, di.Message)
+	}
+	return fmt.Sprintf("[%s] %s\n\tfix: %s", di.Check, di.Message, di.Fix)
+}
+
+// Doctor method diagnoses the application project and environment, and
+// reports the issues found-
+//  - Go toolchain compatibility, for e.g.: deprecated `go build -i` flag
+//  - `go.mod` module path vs application import path consistency
+//  - missing project files, for e.g.: `aah.project`, `config/routes.conf`
+//  - invalid or deprecated config keys
+//  - TLS certificate and key file existence and permissions
+//  - HTTP and redirect server port availability
+//
+// Also available via app binary command `<app-binary> doctor`.
+func (a *Application) Doctor() []*DoctorIssue {
+	var issues []*DoctorIssue
+	issues = append(issues, a.doctorToolchain()...)
+	issues = append(issues, a.doctorModule()...)
+	issues = append(issues, a.doctorFiles()...)
+	issues = append(issues, a.doctorConfig()...)
+	issues = append(issues, a.doctorTLS()...)
+	issues = append(issues, a.doctorPorts()...)
+	return issues
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) cliCmdDoctor() console.Command {
+	return console.Command{
+		Name:  "doctor",
+		Usage: "Diagnoses the app project and environment for common issues",
+		Description: `Diagnoses the app project and environment for common issues and prints
+	actionable fixes. It verifies Go toolchain compatibility, go.mod module path,
+	missing project files, invalid config keys, TLS file permissions and
+	port availability.
+
+		Example:
+			<app-binary> doctor --envprofile prod`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+			},
+		},
+		Action: func(c *console.Context) error {
+			a.Config().SetString("env.active", cliEnvProfile(c))
+			if err := a.refreshSettings(); err != nil {
+				return err
+			}
+			issues := a.Doctor()
+			for _, issue := range issues {
+				fmt.Fprintln(c.App.Writer, issue)
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("aah: doctor found %d issue(s)", len(issues))
+			}
+			fmt.Fprintln(c.App.Writer, "No issues found, app is healthy")
+			return nil
+		},
+	}
+}
+
+func (a *Application) doctorToolchain() []*DoctorIssue {
+	var issues []*DoctorIssue
+	goVersion := runtime.Version()
+	if a.BuildInfo() != nil && len(a.BuildInfo().GoVersion) > 0 {
+		goVersion = a.BuildInfo().GoVersion
+	}
+	if minor, ok := goMinorVersion(goVersion); ok && minor < minGoMinorVersion {
+		issues = append(issues, &DoctorIssue{Check: DoctorCheckToolchain,
+			Message: fmt.Sprintf("Go version '%s' is not supported, aah requires >= go1.%d", goVersion, minGoMinorVersion),
+			Fix:     fmt.Sprintf("upgrade Go toolchain to go1.%d or later", minGoMinorVersion)})
+	}
+
+	projCfg := a.loadProjectConfig()
+	if projCfg == nil {
+		return issues
+	}
+	flags, _ := projCfg.StringList("build.flags")
+	for _, f := range flags {
+		if f == "-i" {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckToolchain,
+				Message: "'aah.project' build flag '-i' is deprecated, Go build cache is used since go1.10",
+				Fix:     "remove '-i' from 'build.flags' in 'aah.project'"})
+		}
+	}
+	return issues
+}
+
+func (a *Application) doctorModule() []*DoctorIssue {
+	if a.IsPackaged() || len(a.BaseDir()) == 0 {
+		return nil
+	}
+	modFile := filepath.Join(a.BaseDir(), "go.mod")
+	if !ess.IsFileExists(modFile) {
+		return []*DoctorIssue{{Check: DoctorCheckModule,
+			Message: "'go.mod' not found in app base directory",
+			Fix:     fmt.Sprintf("run 'go mod init %s' in '%s'", a.ImportPath(), a.BaseDir())}}
+	}
+	modPath, err := goModulePath(modFile)
+	if err != nil {
+		return []*DoctorIssue{{Check: DoctorCheckModule, Message: fmt.Sprintf("'go.mod': %v", err)}}
+	}
+	if len(modPath) == 0 {
+		return []*DoctorIssue{{Check: DoctorCheckModule,
+			Message: "'go.mod' module directive is missing",
+			Fix:     fmt.Sprintf("add 'module %s' into 'go.mod'", a.ImportPath())}}
+	}
+	importPath := filepath.ToSlash(a.ImportPath())
+	if len(importPath) > 0 && !filepath.IsAbs(a.ImportPath()) && modPath != importPath {
+		return []*DoctorIssue{{Check: DoctorCheckModule,
+			Message: fmt.Sprintf("'go.mod' module path '%s' does not match app import path '%s'", modPath, importPath),
+			Fix:     fmt.Sprintf("update 'go.mod' module directive to 'module %s' or use import path '%s'", importPath, modPath)}}
+	}
+	return nil
+}
+
+func (a *Application) doctorFiles() []*DoctorIssue {
+	files := []string{"config/aah.conf", "config/security.conf"}
+	if !a.IsWorker() {
+		files = append(files, "config/routes.conf")
+	}
+	if a.Type() == "web" && a.Config().BoolDefault("view.enable", true) {
+		files = append(files, "views")
+	}
+	if a.Config().IsExists("i18n") || a.VFS().IsExists(path.Join(a.VirtualBaseDir(), "i18n")) {
+		files = append(files, "i18n")
+	}
+
+	var issues []*DoctorIssue
+	if !a.IsPackaged() && len(a.BaseDir()) > 0 && !ess.IsFileExists(filepath.Join(a.BaseDir(), "aah.project")) {
+		issues = append(issues, &DoctorIssue{Check: DoctorCheckFiles,
+			Message: "'aah.project' not found in app base directory",
+			Fix:     "create 'aah.project' file, refer to https://docs.aahframework.org/aah-project-file.html"})
+	}
+	for _, f := range files {
+		if !a.VFS().IsExists(path.Join(a.VirtualBaseDir(), f)) {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckFiles,
+				Message: fmt.Sprintf("'%s' not found in app base directory", f),
+				Fix:     fmt.Sprintf("create '%s' as per aah project layout", f)})
+		}
+	}
+	return issues
+}
+
+func (a *Application) doctorConfig() []*DoctorIssue {
+	var issues []*DoctorIssue
+	warnings, err := frameworkConfigSchema().Validate(a.Config())
+	for _, w := range warnings {
+		issues = append(issues, &DoctorIssue{Check: DoctorCheckConfig, Message: w,
+			Fix: "replace the deprecated key in config file"})
+	}
+	if se, ok := err.(*config.SchemaError); ok {
+		for _, i := range se.Issues {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckConfig, Message: i,
+				Fix: "correct the config key or value as per https://docs.aahframework.org/app-config.html"})
+		}
+	}
+	return issues
+}
+
+func (a *Application) doctorTLS() []*DoctorIssue {
+	if !a.IsSSLEnabled() || a.IsLetsEncryptEnabled() {
+		return nil
+	}
+	var issues []*DoctorIssue
+	for _, f := range []struct{ key, file string }{
+		{"server.ssl.cert", a.settings.SSLCert},
+		{"server.ssl.key", a.settings.SSLKey},
+	} {
+		if len(f.file) == 0 {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckTLS,
+				Message: fmt.Sprintf("'%s' value is required for TLS", f.key),
+				Fix:     fmt.Sprintf("configure '%s' in 'aah.conf'", f.key)})
+			continue
+		}
+		fi, err := os.Stat(f.file)
+		if err != nil {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckTLS,
+				Message: fmt.Sprintf("'%s' file '%s' is not accessible: %v", f.key, f.file, err),
+				Fix:     fmt.Sprintf("verify the file path and read permission of '%s'", f.file)})
+			continue
+		}
+		if f.key == "server.ssl.key" && runtime.GOOS != "windows" && fi.Mode().Perm()&0077 != 0 {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckTLS,
+				Message: fmt.Sprintf("'%s' file '%s' is accessible by group or others, mode %s", f.key, f.file, fi.Mode().Perm()),
+				Fix:     fmt.Sprintf("run 'chmod 600 %s'", f.file)})
+		}
+	}
+	return issues
+}
+
+func (a *Application) doctorPorts() []*DoctorIssue {
+	address := a.HTTPAddress()
+	if strings.HasPrefix(address, "unix") {
+		return nil
+	}
+	ports := []struct{ key, port string }{{"server.port", a.parsePort(a.Config().StringDefault("server.port", ""))}}
+	if a.IsSSLEnabled() && a.Config().BoolDefault("server.ssl.redirect_http.enable", false) {
+		if port, found := a.Config().String("server.ssl.redirect_http.port"); found {
+			ports = append(ports, struct{ key, port string }{"server.ssl.redirect_http.port", port})
+		}
+	}
+
+	var issues []*DoctorIssue
+	for _, p := range ports {
+		addr := net.JoinHostPort(address, p.port)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			issues = append(issues, &DoctorIssue{Check: DoctorCheckPort,
+				Message: fmt.Sprintf("'%s' address '%s' is not available: %v", p.key, addr, err),
+				Fix:     fmt.Sprintf("stop the process using port %s or change '%s'", p.port, p.key)})
+			continue
+		}
+		_ = l.Close()
+	}
+	return issues
+}
+
+func (a *Application) loadProjectConfig() *config.Config {
+	if len(a.BaseDir()) == 0 {
+		return nil
+	}
+	projFile := filepath.Join(a.BaseDir(), "aah.project")
+	if !ess.IsFileExists(projFile) {
+		return nil
+	}
+	cfg, err := config.LoadFile(projFile)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// goModulePath method returns the module path from given `go.mod` file.
+func goModulePath(modFile string) (string, error) {
+	f, err := os.Open(modFile)
+	if err != nil {
+		return "", err
+	}
+	defer ess.CloseQuietly(f)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// goMinorVersion method returns the minor version of Go version string,
+// for e.g.: `go1.11.4` returns `11`.
+func goMinorVersion(v string) (int, bool) {
+	if !strings.HasPrefix(v, "go1.") {
+		return 0, false
+	}
+	v = strings.TrimPrefix(v, "go1.")
+	if i := strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		v = v[:i]
+	}
+	minor, err := strconv.Atoi(v)
+	return minor, err == nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/console"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctor(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	a.Config().SetString("server.address", "127.0.0.1")
+	a.Config().SetString("server.port", port)
+
+	keyFile, err := ioutil.TempFile("", "aah-doctor-key")
+	assert.Nil(t, err)
+	defer os.Remove(keyFile.Name())
+	assert.Nil(t, os.Chmod(keyFile.Name(), 0644))
+	a.settings.SSLEnabled = true
+	a.settings.SSLCert = filepath.Join(importPath, "no-cert.pem")
+	a.settings.SSLKey = keyFile.Name()
+
+	a.Config().SetString("server.ssl.enabel", "true")
+
+	issues := a.Doctor()
+	checks := map[string][]string{}
+	for _, issue := range issues {
+		checks[issue.Check] = append(checks[issue.Check], issue.String())
+	}
+
+	assert.Equal(t, 1, len(checks[DoctorCheckToolchain]))
+	assert.Equal(t, "[toolchain] 'aah.project' build flag '-i' is deprecated, Go build cache is used since go1.10\n"+
+		"\tfix: remove '-i' from 'build.flags' in 'aah.project'", checks[DoctorCheckToolchain][0])
+	assert.Equal(t, 1, len(checks[DoctorCheckModule]))
+	assert.True(t, strings.Contains(checks[DoctorCheckModule][0], "'go.mod' not found"))
+	assert.Equal(t, 0, len(checks[DoctorCheckFiles]), "%v", checks[DoctorCheckFiles])
+	assert.Equal(t, 1, len(checks[DoctorCheckConfig]))
+	assert.True(t, strings.Contains(checks[DoctorCheckConfig][0], "did you mean 'server.ssl.enable'?"))
+	assert.Equal(t, 2, len(checks[DoctorCheckTLS]))
+	assert.True(t, strings.Contains(checks[DoctorCheckTLS][0], "no-cert.pem' is not accessible"))
+	assert.True(t, strings.Contains(checks[DoctorCheckTLS][1], "chmod 600"))
+	assert.Equal(t, 1, len(checks[DoctorCheckPort]))
+	assert.True(t, strings.Contains(checks[DoctorCheckPort][0], "stop the process using port "+port))
+
+	// CLI command
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdDoctor()}
+	err = cliApp.Run([]string{"app", "doctor"})
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "aah: doctor found"))
+	assert.True(t, strings.Contains(buf.String(), "[toolchain]"))
+}
+
+func TestDoctorModulePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-doctor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	a := newApp()
+	a.settings.BaseDir = dir
+	a.settings.ImportPath = "example.com/myapp"
+
+	modFile := filepath.Join(dir, "go.mod")
+	assert.Nil(t, ioutil.WriteFile(modFile, []byte("module example.com/other\n\nrequire aahframe.work v0.13.0\n"), 0644))
+	issues := a.doctorModule()
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, "'go.mod' module path 'example.com/other' does not match app import path 'example.com/myapp'", issues[0].Message)
+
+	assert.Nil(t, ioutil.WriteFile(modFile, []byte("module \"example.com/myapp\"\n"), 0644))
+	assert.Nil(t, a.doctorModule())
+
+	assert.Nil(t, ioutil.WriteFile(modFile, []byte("require aahframe.work v0.13.0\n"), 0644))
+	issues = a.doctorModule()
+	assert.Equal(t, "'go.mod' module directive is missing", issues[0].Message)
+
+	for v, expected := range map[string]int{"go1.11.4": 11, "go1.21rc1": 21, "go1.9": 9} {
+		minor, ok := goMinorVersion(v)
+		assert.True(t, ok)
+		assert.Equal(t, expected, minor)
+	}
+	_, ok := goMinorVersion("devel +abc")
+	assert.False(t, ok)
+}
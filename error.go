@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"path"
 	"strings"
 
 	"aahframe.work/ahttp"
@@ -31,12 +32,19 @@ var (
 	ErrAccessDenied               = errors.New("aah: access denied")
 	ErrAuthenticationFailed       = errors.New("aah: authentication failed")
 	ErrAuthorizationFailed        = errors.New("aah: authorization failed")
+	ErrAuthenticationThrottled    = errors.New("aah: authentication throttled")
+	ErrRateLimitExceeded          = errors.New("aah: rate limit exceeded")
+	ErrQuotaExceeded              = errors.New("aah: quota exceeded")
 	ErrSessionAuthenticationInfo  = errors.New("aah: session authentication info")
 	ErrUnableToGetPrincipal       = errors.New("aah: unable to get principal")
+	ErrTenantNotFound             = errors.New("aah: tenant not found")
+	ErrProxyUpgradeNotAllowed     = errors.New("aah: proxy websocket upgrade not allowed")
 	ErrGeneric                    = errors.New("aah: generic error")
 	ErrValidation                 = errors.New("aah: validation error")
 	ErrRenderResponse             = errors.New("aah: render response error")
 	ErrWriteResponse              = errors.New("aah: write response error")
+	ErrIdempotencyKeyInProgress   = errors.New("aah: idempotency key request in progress")
+	ErrIdempotencyKeyMismatch     = errors.New("aah: idempotency key reused with different request")
 )
 
 var defaultErrorHTMLTemplate = template.Must(template.New("error_template").Parse(`<!DOCTYPE html>
@@ -115,7 +123,7 @@ func (a *Application) initError() error {
 	a.errorMgr = &errorManager{
 		a: a,
 	}
-	return nil
+	return a.errorMgr.initViews()
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -125,6 +133,7 @@ func (a *Application) initError() error {
 type errorManager struct {
 	a           *Application
 	handlerFunc ErrorHandlerFunc
+	viewRules   []*errorViewRule
 }
 
 func (er *errorManager) SetHandler(handlerFn ErrorHandlerFunc) {
@@ -135,7 +144,10 @@ func (er *errorManager) SetHandler(handlerFn ErrorHandlerFunc) {
 }
 
 func (er *errorManager) Handle(ctx *Context) {
-	if err := ctx.setTarget(ctx.route); err == errTargetNotFound {
+	// Auth scheme routes (e.g. oauth2, webauthn) are handled by middleware and do not have target
+	if ctx.route != nil && len(ctx.route.Target) == 0 && len(ctx.route.Auth) > 0 {
+		ctx.Log().Trace("Route is handled by auth scheme, skip controller error handler")
+	} else if err := ctx.setTarget(ctx.route); err == errTargetNotFound {
 		// No controller or action found for the route
 		ctx.Log().Warnf("Target not found (controller:%s action:%s)", ctx.route.Target, ctx.route.Action)
 		ctx.Reply().NotFound().Error(newError(ErrControllerOrActionNotFound, http.StatusNotFound))
@@ -160,14 +172,21 @@ func (er *errorManager) Handle(ctx *Context) {
 }
 
 // DefaultHandler method is used when custom error handler is not register
-// in the aah. It writes the response based on HTTP Content-Type.
+// in the aah. It writes the response based on HTTP Content-Type and the
+// matching error view rule from config `error_views.rules`.
 func (er *errorManager) DefaultHandler(ctx *Context, err *Error) bool {
 	ct := ctx.Reply().ContType
 	if len(ct) == 0 {
 		ct = ctx.detectContentType()
-		if ctx.a.viewMgr == nil && strings.HasPrefix(ct, ahttp.ContentTypeHTML.Mime) {
-			ct = ahttp.ContentTypePlainText.Mime
-		}
+	}
+
+	rule := er.findView(ctx, err)
+	if rule != nil {
+		ctx.Log().Tracef("Error view rule '%s' matched", rule.Name)
+		ct = er.viewContentType(rule, ct)
+	}
+	if ctx.a.viewMgr == nil && strings.HasPrefix(ct, ahttp.ContentTypeHTML.Mime) {
+		ct = ahttp.ContentTypePlainText.Mime
 	}
 
 	ct = util.OnlyMIME(ct)
@@ -175,8 +194,11 @@ func (er *errorManager) DefaultHandler(ctx *Context, err *Error) bool {
 	// Set HTTP response code
 	ctx.Reply().Status(err.Code)
 
-	// Set it to nil do not expose any app internal info
-	err.Data = nil
+	// Set it to nil do not expose any app internal info, except allowed
+	// methods info of 405 response
+	if err.Reason != ErrHTTPMethodNotAllowed {
+		err.Data = nil
+	}
 
 	switch ct {
 	case ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeJSONText.Mime:
@@ -187,14 +209,19 @@ func (er *errorManager) DefaultHandler(ctx *Context, err *Error) bool {
 		html := &htmlRender{
 			Template: defaultErrorHTMLTemplate,
 			Filename: fmt.Sprintf("%d%s", err.Code, ctx.a.viewMgr.fileExt),
-			ViewArgs: Data{"Error": err},
+			ViewArgs: Data{"Error": err, "ErrorChain": err.Chain()},
 		}
 
-		if ctx.a.viewMgr != nil {
-			tmpl, terr := ctx.a.ViewEngine().Get("", "errors", html.Filename)
-			if tmpl != nil || terr == nil {
-				html.Template = tmpl
-			}
+		tmplDir := "errors"
+		if rule != nil && !rule.IsFormat() {
+			tmplDir, html.Filename = path.Split(rule.View)
+			tmplDir = strings.Trim(tmplDir, "/")
+		}
+		tmpl, terr := ctx.a.ViewEngine().Get("", tmplDir, html.Filename)
+		if tmpl != nil || terr == nil {
+			html.Template = tmpl
+		} else if rule != nil && !rule.IsFormat() {
+			ctx.Log().Warnf("Error view template '%s' not found for rule '%s'", rule.View, rule.Name)
 		}
 
 		ctx.Reply().Rdr = html
@@ -205,27 +232,115 @@ func (er *errorManager) DefaultHandler(ctx *Context, err *Error) bool {
 	return true
 }
 
+// allowedMethodsData method returns the structured body of auto 'OPTIONS'
+// and 405 responses for API type application. Documentation URL is from
+// config `api.documentation_url` otherwise OpenAPI spec path if enabled.
+//
+// 	{
+// 	  "allowed_methods": ["GET", "POST", "OPTIONS"],
+// 	  "documentation_url": "/openapi.json"
+// 	}
+func (er *errorManager) allowedMethodsData(reply *Reply) Data {
+	data := Data{"allowed_methods": strings.Split(reply.ctx.Res.Header().Get(ahttp.HeaderAllow), ", ")}
+	cfg := er.a.Config()
+	docURL := cfg.StringDefault("api.documentation_url", "")
+	if len(docURL) == 0 && cfg.BoolDefault("openapi.enable", false) {
+		docURL = cfg.StringDefault("openapi.path", openAPIDefaultPath)
+	}
+	if len(docURL) > 0 {
+		data["documentation_url"] = docURL
+	}
+	return data
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Error type
 //______________________________________________________________________________
 
 // Error structure is used to represent the error information in aah framework.
+//
+// Field `Cause` carries the originating error, for e.g.: bind or validation
+// error, recovered panic value, etc. aah.Error supports wrapped error chain,
+// so `errors.Is` and `errors.As` checks matches the `Reason` and `Cause`
+// chain.
+//
+// 	func (c *UserController) HandleError(err *aah.Error) bool {
+// 		if errors.Is(err, sql.ErrNoRows) {
+// 			c.Reply().NotFound().JSON(aah.Data{"message": "user not found"})
+// 			return true
+// 		}
+// 		return false
+// 	}
 type Error struct {
-	Reason  error       `json:"-" xml:"-"`
-	Code    int         `json:"code,omitempty" xml:"code,omitempty"`
-	Message string      `json:"message,omitempty" xml:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty" xml:"data,omitempty"`
+	Reason  error                  `json:"-" xml:"-"`
+	Cause   error                  `json:"-" xml:"-"`
+	Code    int                    `json:"code,omitempty" xml:"code,omitempty"`
+	Message string                 `json:"message,omitempty" xml:"message,omitempty"`
+	Data    interface{}            `json:"data,omitempty" xml:"data,omitempty"`
+	Meta    map[string]interface{} `json:"-" xml:"-"`
 }
 
 // Error method is to comply error interface.
 func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%v, code '%v', message '%s', cause '%v'", e.Reason, e.Code, e.Message, e.Cause)
+	}
 	return fmt.Sprintf("%v, code '%v', message '%s'", e.Reason, e.Code, e.Message)
 }
 
+// Unwrap method returns the `Reason` and `Cause` errors, it's used by
+// `errors.Is` and `errors.As`.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Reason != nil {
+		errs = append(errs, e.Reason)
+	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+// WithCause method sets the originating error and returns aah error for
+// chained call.
+func (e *Error) WithCause(cause error) *Error {
+	e.Cause = cause
+	return e
+}
+
+// WithMeta method adds the key/value metadata into aah error, it's not
+// written on the response. Metadata is useful in the error handlers and
+// panic/error reporters.
+func (e *Error) WithMeta(key string, value interface{}) *Error {
+	if e.Meta == nil {
+		e.Meta = make(map[string]interface{})
+	}
+	e.Meta[key] = value
+	return e
+}
+
+// Chain method returns the error chain of `Reason` followed by `Cause`,
+// each wrapped error is unwrapped to the end.
+func (e *Error) Chain() []error {
+	var chain []error
+	for _, err := range e.Unwrap() {
+		for ; err != nil; err = errors.Unwrap(err) {
+			chain = append(chain, err)
+		}
+	}
+	return chain
+}
+
 func newError(err error, code int) *Error {
 	return &Error{Reason: err, Code: code, Message: http.StatusText(code)}
 }
 
+// newErrorWithData method creates the aah error with data, if data is
+// an error then it's preserved as `Cause`.
 func newErrorWithData(err error, code int, data interface{}) *Error {
-	return &Error{Reason: err, Code: code, Message: http.StatusText(code), Data: data}
+	e := &Error{Reason: err, Code: code, Message: http.StatusText(code), Data: data}
+	if cause, ok := data.(error); ok {
+		e.Cause = cause
+	}
+	return e
 }
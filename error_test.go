@@ -0,0 +1,50 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorWrappedChain(t *testing.T) {
+	cause := fmt.Errorf("open config: %w", os.ErrNotExist)
+	err := newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, cause)
+	assert.Equal(t, cause, err.Cause)
+	assert.Equal(t, cause, err.Data)
+	assert.True(t, errors.Is(err, ErrInvalidRequestParameter))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.False(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, []error{ErrInvalidRequestParameter, cause, os.ErrNotExist}, err.Chain())
+	assert.Equal(t, "aah: invalid request parameter, code '400', message 'Bad Request', cause 'open config: file does not exist'", err.Error())
+
+	var pathErr *os.PathError
+	err = newError(ErrGeneric, http.StatusInternalServerError).
+		WithCause(&os.PathError{Op: "open", Path: "/tmp/a", Err: os.ErrPermission})
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, "/tmp/a", pathErr.Path)
+
+	// wrapped aah error
+	wrapped := fmt.Errorf("handler: %w", err)
+	var aahErr *Error
+	assert.True(t, errors.As(wrapped, &aahErr))
+	assert.True(t, errors.Is(wrapped, os.ErrPermission))
+
+	// non-error data
+	err = newErrorWithData(ErrPanicRecovery, http.StatusInternalServerError, "panic value")
+	assert.Nil(t, err.Cause)
+	assert.Equal(t, []error{ErrPanicRecovery}, err.Chain())
+	assert.Equal(t, "aah: panic recovery, code '500', message 'Internal Server Error'", err.Error())
+
+	err.WithMeta("user_id", 10).WithMeta("order_id", "A100")
+	assert.Equal(t, map[string]interface{}{"user_id": 10, "order_id": "A100"}, err.Meta)
+
+	assert.Nil(t, (&Error{}).Unwrap())
+}
@@ -0,0 +1,204 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+)
+
+const keyErrorViewRules = "error_views.rules"
+
+// error view formats, any other `view` value is treated as template path
+// relative to `views` directory.
+const (
+	errorViewJSON = "json"
+	errorViewXML  = "xml"
+	errorViewText = "text"
+)
+
+// errorViewRule holds the single error view rule configured in the section
+// `error_views.rules`.
+type errorViewRule struct {
+	Name    string
+	Order   int
+	View    string
+	Codes   []int
+	Routes  []string
+	Paths   []string
+	Domains []string
+	Tags    []string
+}
+
+// String method is Stringer interface.
+func (ev *errorViewRule) String() string {
+	return fmt.Sprintf("errorview(name:%s view:%s codes:%v routes:%v paths:%v domains:%v tags:%v)",
+		ev.Name, ev.View, ev.Codes, ev.Routes, ev.Paths, ev.Domains, ev.Tags)
+}
+
+// IsFormat method returns true if rule view is one of the reply format
+// `json`, `xml` or `text` otherwise false.
+func (ev *errorViewRule) IsFormat() bool {
+	switch ev.View {
+	case errorViewJSON, errorViewXML, errorViewText:
+		return true
+	}
+	return false
+}
+
+// Match method returns true if the rule criteria matches with given error
+// and request. Empty criteria matches all.
+func (ev *errorViewRule) Match(ctx *Context, err *Error) bool {
+	if len(ev.Codes) > 0 && !containsInt(ev.Codes, err.Code) {
+		return false
+	}
+	if len(ev.Domains) > 0 && !matchAnyGlob(ev.Domains, stripPort(ctx.Req.Host)) {
+		return false
+	}
+	if len(ev.Paths) > 0 && !matchAnyGlob(ev.Paths, ctx.Req.Path) {
+		return false
+	}
+	if len(ev.Routes) > 0 && (ctx.route == nil || !matchAnyGlob(ev.Routes, ctx.route.Name)) {
+		return false
+	}
+	if len(ev.Tags) > 0 && !ctx.route.HasAnyTag(ev.Tags...) {
+		return false
+	}
+	return true
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Error Manager unexported methods
+//______________________________________________________________________________
+
+func (er *errorManager) initViews() error {
+	cfg := er.a.Config()
+	er.viewRules = nil
+	if !cfg.BoolDefault("error_views.enable", true) {
+		return nil
+	}
+
+	var rules []*errorViewRule
+	for _, name := range cfg.KeysByPath(keyErrorViewRules) {
+		rule, err := parseErrorViewRule(cfg, name)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Order == rules[j].Order {
+			return rules[i].Name < rules[j].Name
+		}
+		return rules[i].Order < rules[j].Order
+	})
+	er.viewRules = rules
+	return nil
+}
+
+// findView method returns the first matching error view rule for the
+// given error and request otherwise nil.
+func (er *errorManager) findView(ctx *Context, err *Error) *errorViewRule {
+	for _, rule := range er.viewRules {
+		if rule.Match(ctx, err) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// viewContentType method returns the content type for the error view rule
+// format, template view is rendered for HTML content type only.
+func (er *errorManager) viewContentType(rule *errorViewRule, ct string) string {
+	switch rule.View {
+	case errorViewJSON:
+		return ahttp.ContentTypeJSON.Mime
+	case errorViewXML:
+		return ahttp.ContentTypeXML.Mime
+	case errorViewText:
+		return ahttp.ContentTypePlainText.Mime
+	}
+	return ct
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseErrorViewRule(cfg *config.Config, name string) (*errorViewRule, error) {
+	keyPrefix := keyErrorViewRules + "." + name + "."
+	view, found := cfg.String(keyPrefix + "view")
+	if !found || len(strings.TrimSpace(view)) == 0 {
+		return nil, fmt.Errorf("error_views: '%s.view' value is missing", name)
+	}
+
+	rule := &errorViewRule{
+		Name:  name,
+		Order: cfg.IntDefault(keyPrefix+"order", 0),
+		View:  strings.TrimSpace(view),
+	}
+	if !rule.IsFormat() && len(path.Ext(rule.View)) == 0 {
+		return nil, fmt.Errorf("error_views: '%s.view' value '%s' is neither format nor template file", name, rule.View)
+	}
+
+	rule.Codes, _ = cfg.IntList(keyPrefix + "codes")
+	for _, c := range rule.Codes {
+		if c < 400 || c > 599 {
+			return nil, fmt.Errorf("error_views: '%s.codes' value '%d' is not an error status code", name, c)
+		}
+	}
+
+	var err error
+	if rule.Routes, err = errorViewPatterns(cfg, name, "routes"); err != nil {
+		return nil, err
+	}
+	if rule.Paths, err = errorViewPatterns(cfg, name, "paths"); err != nil {
+		return nil, err
+	}
+	if rule.Domains, err = errorViewPatterns(cfg, name, "domains"); err != nil {
+		return nil, err
+	}
+	rule.Tags, _ = cfg.StringList(keyPrefix + "tags")
+	return rule, nil
+}
+
+func errorViewPatterns(cfg *config.Config, name, key string) ([]string, error) {
+	patterns, _ := cfg.StringList(keyErrorViewRules + "." + name + "." + key)
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("error_views: '%s.%s' value '%s' is not a valid pattern", name, key, p)
+		}
+	}
+	return patterns, nil
+}
+
+// matchAnyGlob method reports whether the value matches any of the given
+// `path.Match` patterns, pattern suffix `/**` matches the prefix.
+func matchAnyGlob(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "/**") && strings.HasPrefix(s, p[:len(p)-2]) {
+			return true
+		}
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, i := range values {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorViews(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	a.cfg, _ = config.ParseString(`error_views {
+  rules {
+    api_errors {
+      view = "json"
+      paths = ["/api/**"]
+    }
+    not_found {
+      view = "errors/500.html"
+      codes = [404]
+      domains = ["*.example.com"]
+      order = 1
+    }
+    user_errors {
+      view = "xml"
+      routes = ["user_*"]
+      order = 2
+    }
+    public_errors {
+      view = "text"
+      tags = ["public"]
+      order = 3
+    }
+  }
+}`)
+	assert.Nil(t, a.errorMgr.initViews())
+	assert.Equal(t, 4, len(a.errorMgr.viewRules))
+	assert.Equal(t, "api_errors", a.errorMgr.viewRules[0].Name)
+	assert.Equal(t, "errorview(name:not_found view:errors/500.html codes:[404] routes:[] paths:[] domains:[*.example.com] tags:[])",
+		a.errorMgr.viewRules[1].String())
+
+	newErrCtx := func(target string) *Context {
+		req := httptest.NewRequest(ahttp.MethodGet, target, nil)
+		req.Header.Set(ahttp.HeaderAccept, ahttp.ContentTypeHTML.Mime)
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		return ctx
+	}
+
+	// path pattern
+	ctx := newErrCtx("http://localhost:8080/api/v1/users")
+	assert.True(t, a.errorMgr.DefaultHandler(ctx, newError(ErrRouteNotFound, http.StatusNotFound)))
+	_, ok := ctx.Reply().Rdr.(*jsonRender)
+	assert.True(t, ok)
+
+	// status code and domain pattern, template view
+	reason := fmt.Errorf("lookup user: %w", ErrRouteNotFound)
+	ctx = newErrCtx("http://www.example.com/users/1")
+	assert.True(t, a.errorMgr.DefaultHandler(ctx, newError(reason, http.StatusNotFound)))
+	html, ok := ctx.Reply().Rdr.(*htmlRender)
+	assert.True(t, ok)
+	assert.Equal(t, "500.html", html.Filename)
+	assert.NotEqual(t, defaultErrorHTMLTemplate, html.Template)
+	assert.Equal(t, []error{reason, ErrRouteNotFound}, html.ViewArgs["ErrorChain"])
+
+	// route name pattern
+	ctx = newErrCtx("http://localhost:8080/users/1")
+	ctx.route = &router.Route{Name: "user_profile"}
+	assert.True(t, a.errorMgr.DefaultHandler(ctx, newError(ErrAccessDenied, http.StatusForbidden)))
+	_, ok = ctx.Reply().Rdr.(*xmlRender)
+	assert.True(t, ok)
+
+	// route tag
+	ctx = newErrCtx("http://localhost:8080/about")
+	ctx.route = &router.Route{Name: "about", Tags: []string{"public"}}
+	assert.True(t, a.errorMgr.DefaultHandler(ctx, newError(ErrRouteNotFound, http.StatusNotFound)))
+	_, ok = ctx.Reply().Rdr.(*textRender)
+	assert.True(t, ok)
+
+	// no match, default error template
+	ctx = newErrCtx("http://localhost:8080/users/1")
+	assert.True(t, a.errorMgr.DefaultHandler(ctx, newError(ErrRouteNotFound, http.StatusNotFound)))
+	html, ok = ctx.Reply().Rdr.(*htmlRender)
+	assert.True(t, ok)
+	assert.Equal(t, "404.html", html.Filename)
+}
+
+func TestErrorViewsConfigErrors(t *testing.T) {
+	a := newApp()
+	a.errorMgr = &errorManager{a: a}
+	testcases := []struct {
+		rule, msg string
+	}{
+		{rule: `codes = [404]`, msg: "error_views: 'r1.view' value is missing"},
+		{rule: `view = "errors"`, msg: "error_views: 'r1.view' value 'errors' is neither format nor template file"},
+		{rule: `view = "json"
+      codes = [200]`, msg: "error_views: 'r1.codes' value '200' is not an error status code"},
+		{rule: `view = "json"
+      paths = ["/a/["]`, msg: "error_views: 'r1.paths' value '/a/[' is not a valid pattern"},
+	}
+	for _, tc := range testcases {
+		var err error
+		a.cfg, err = config.ParseString(fmt.Sprintf(`error_views {
+  rules {
+    r1 {
+      %s
+    }
+  }
+}`, tc.rule))
+		assert.Nil(t, err)
+		err = a.errorMgr.initViews()
+		assert.NotNil(t, err)
+		if err != nil {
+			assert.Equal(t, tc.msg, err.Error())
+		}
+	}
+
+	a.cfg, _ = config.ParseString(`error_views {
+  enable = false
+  rules {
+    r1 {
+      view = "json"
+    }
+  }
+}`)
+	assert.Nil(t, a.errorMgr.initViews())
+	assert.Nil(t, a.errorMgr.viewRules)
+}
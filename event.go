@@ -39,6 +39,12 @@ const (
 	// config `runtime.config_hotreload.signal`.
 	EventOnConfigHotReload = "OnConfigHotReload"
 
+	// EventOnLogConfigChange is published just after aah application logger
+	// level or receiver gets changed via `SetLogLevel`, `SetLogReceiver` and
+	// config hot-reload. Event data is the current application logger, so
+	// modules holding cached loggers can refresh it.
+	EventOnLogConfigChange = "OnLogConfigChange"
+
 	//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 	// HTTP Engine events
 	//______________________________________________________________________________
@@ -92,6 +98,35 @@ const (
 	// EventOnPostAuth is published once the Authentication and Authorization
 	// info gets populated into Subject.
 	EventOnPostAuth = "OnPostAuth"
+
+	// EventOnRunAs is published synchronously once the Subject starts running
+	// as another identity via `ctx.Subject().RunAs`. Event data is `*aah.Context`,
+	// use it for audit of real and run as identity.
+	EventOnRunAs = "OnRunAs"
+
+	// EventOnReleaseRunAs is published synchronously just before the Subject
+	// releases the run as identity via `ctx.Subject().ReleaseRunAs`. Event data
+	// is `*aah.Context`.
+	EventOnReleaseRunAs = "OnReleaseRunAs"
+
+	// EventOnAuthcFailed is published synchronously on failed login attempt of
+	// `form` and `basic` auth scheme when login throttle is enabled
+	// `security.throttle.enable`. Event data is `*aah.Context`.
+	EventOnAuthcFailed = "OnAuthcFailed"
+
+	// EventOnAuthcLockout is published synchronously when the login attempts
+	// reaches the `security.throttle.lockout_threshold`. Event data is
+	// `*aah.Context`.
+	EventOnAuthcLockout = "OnAuthcLockout"
+
+	// EventOnQuotaExceeded is published synchronously when the authenticated
+	// Subject exceeds its request quota `security.quota.*`. Event data is
+	// `*aah.Context`, use `ctx.Quota()` for usage details.
+	EventOnQuotaExceeded = "OnQuotaExceeded"
+
+	// EventOnWebhookDeadLetter is published synchronously when the webhook
+	// delivery is moved to dead letters. Event data is `*aah.WebhookDelivery`.
+	EventOnWebhookDeadLetter = "OnWebhookDeadLetter"
 )
 
 type (
@@ -159,6 +194,16 @@ func (a *Application) OnConfigHotReload(ecb EventCallbackFunc, priority ...int)
 	})
 }
 
+// OnLogConfigChange method is to subscribe to aah application `OnLogConfigChange`
+// event. `OnLogConfigChange` is published just after aah application logger
+// level or receiver gets changed.
+func (a *Application) OnLogConfigChange(ecb EventCallbackFunc, priority ...int) {
+	a.SubscribeEvent(EventOnLogConfigChange, EventCallback{
+		Callback: ecb,
+		priority: parsePriority(priority),
+	})
+}
+
 func (a *Application) subcribeAppEvent(eventName string, ecb EventCallbackFunc, priority []int) {
 	a.SubscribeEvent(eventName, EventCallback{
 		Callback: ecb,
@@ -223,12 +268,13 @@ func (es *EventStore) IsEventExists(eventName string) bool {
 // Publish method publishes events to subscribed callbacks asynchronously. It
 // means each subscribed callback executed via goroutine.
 func (es *EventStore) Publish(e *Event) {
-	if !es.IsEventExists(e.Name) {
+	subscribers, found := es.eventSubscribers(e.Name)
+	if !found {
 		return
 	}
 	es.a.Log().Debugf("Publishing event '%s' in asynchronous mode", e.Name)
 	wg := sync.WaitGroup{}
-	for idx, ec := range es.subscribers[e.Name] {
+	for idx, ec := range subscribers {
 		if ec.CallOnce {
 			if !ec.published {
 				wg.Add(1)
@@ -254,11 +300,12 @@ func (es *EventStore) Publish(e *Event) {
 
 // PublishSync method publishes events to subscribed callbacks synchronously.
 func (es *EventStore) PublishSync(e *Event) {
-	if !es.IsEventExists(e.Name) {
+	subscribers, found := es.eventSubscribers(e.Name)
+	if !found {
 		return
 	}
 	es.a.Log().Debugf("Publishing event '%s' in synchronous mode", e.Name)
-	for idx, ec := range es.subscribers[e.Name] {
+	for idx, ec := range subscribers {
 		if ec.CallOnce {
 			if !ec.published {
 				ec.Callback(e)
@@ -298,7 +345,8 @@ func (es *EventStore) Unsubscribe(event string, callback EventCallbackFunc) {
 	for idx := len(es.subscribers[event]) - 1; idx >= 0; idx-- {
 		ec := es.subscribers[event][idx]
 		if util.FuncEqual(ec.Callback, callback) {
-			es.subscribers[event] = append(es.subscribers[event][:idx], es.subscribers[event][idx+1:]...)
+			// new slice, publish might be iterating the current one
+			es.subscribers[event] = append(es.subscribers[event][:idx:idx], es.subscribers[event][idx+1:]...)
 			es.a.Log().Debugf("Callback: %s, unsubscribed from event: %s", ess.GetFunctionInfo(callback).QualifiedName, event)
 			return
 		}
@@ -315,6 +363,24 @@ func (es *EventStore) SubscriberCount(eventName string) int {
 	return 0
 }
 
+// eventSubscribers method returns the subscribers of given event, it's safe
+// to iterate while subscribers are being added or removed.
+func (es *EventStore) eventSubscribers(eventName string) (EventCallbacks, bool) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	subscribers, found := es.subscribers[eventName]
+	return subscribers, found
+}
+
+// subscribeFunc method subscribes the given func to the event and returns
+// the func to unsubscribe it. It is used by WebSocket engine to bridge the
+// application events into WebSocket connections.
+func (es *EventStore) subscribeFunc(eventName string, fn func(data interface{})) func() {
+	ecf := func(e *Event) { fn(e.Data) }
+	es.Subscribe(eventName, EventCallback{Callback: ecf})
+	return func() { es.Unsubscribe(eventName, ecf) }
+}
+
 func (es *EventStore) sortEventSubscribers(eventName string) {
 	if es.IsEventExists(eventName) {
 		ec := es.subscribers[eventName]
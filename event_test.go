@@ -153,3 +153,27 @@ func TestEventSubscribeAndUnsubscribeAndPublish(t *testing.T) {
 
 	ts.app.PublishEventSync("myEvent2", "myEvent2 is fired sync")
 }
+
+func TestEventSubscribeFunc(t *testing.T) {
+	es := &EventStore{a: newApp(), subscribers: make(map[string]EventCallbacks)}
+
+	var received []interface{}
+	unsubscribe1 := es.subscribeFunc("order.updated", func(data interface{}) {
+		received = append(received, data)
+	})
+	unsubscribe2 := es.subscribeFunc("order.updated", func(data interface{}) {
+		received = append(received, data)
+	})
+	assert.Equal(t, 2, es.SubscriberCount("order.updated"))
+
+	es.PublishSync(&Event{Name: "order.updated", Data: 1001})
+	assert.Equal(t, []interface{}{1001, 1001}, received)
+
+	unsubscribe1()
+	assert.Equal(t, 1, es.SubscriberCount("order.updated"))
+	es.PublishSync(&Event{Name: "order.updated", Data: 1002})
+	assert.Equal(t, []interface{}{1001, 1001, 1002}, received)
+
+	unsubscribe2()
+	assert.Equal(t, 0, es.SubscriberCount("order.updated"))
+}
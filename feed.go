@@ -0,0 +1,333 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+const (
+	// FeedFormatRSS is RSS 2.0 feed format.
+	FeedFormatRSS = "rss"
+
+	// FeedFormatAtom is Atom feed format RFC 4287.
+	FeedFormatAtom = "atom"
+
+	keyFeedFormatParam  = "format"
+	atomNamespace       = "http://www.w3.org/2005/Atom"
+	rssContentNamespace = "http://purl.org/rss/1.0/modules/content/"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Feed types
+//______________________________________________________________________________
+
+// Feed type holds the syndication feed values, it's rendered as RSS 2.0 or
+// Atom by `Reply().Feed`.
+//
+// `ID` is used as Atom feed id, if it's empty then `Link` is used. `Updated`
+// is used for `Last-Modified` header, if it's zero then latest item time
+// is used.
+type Feed struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	Copyright   string
+	Author      *FeedAuthor
+	Updated     time.Time
+	Items       []*FeedItem
+}
+
+// FeedItem type holds the single entry of the feed.
+//
+// `ID` is used as RSS guid and Atom entry id, if it's empty then `Link` is
+// used. `Content` is full content of the entry and `Description` is summary.
+type FeedItem struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Author      *FeedAuthor
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// FeedAuthor type holds the author details of the feed or feed item.
+type FeedAuthor struct {
+	Name  string
+	Email string
+}
+
+// LastModified method returns the feed updated time if it's set otherwise
+// latest published or updated time of the feed items.
+func (f *Feed) LastModified() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+	var t time.Time
+	for _, item := range f.Items {
+		if it := item.modTime(); it.After(t) {
+			t = it
+		}
+	}
+	return t
+}
+
+func (fi *FeedItem) modTime() time.Time {
+	if fi.Updated.After(fi.Published) {
+		return fi.Updated
+	}
+	return fi.Published
+}
+
+func (fi *FeedItem) id() string {
+	if len(fi.ID) > 0 {
+		return fi.ID
+	}
+	return fi.Link
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Feed Render
+//______________________________________________________________________________
+
+// feedRender renders the feed as RSS 2.0 or Atom into response.
+type feedRender struct {
+	Format string
+	Feed   *Feed
+}
+
+// Render method writes feed XML into HTTP response.
+func (f *feedRender) Render(w io.Writer) error {
+	if _, err := w.Write(xmlHeaderBytes); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if f.Format == FeedFormatAtom {
+		return enc.Encode(toAtomFeed(f.Feed))
+	}
+	return enc.Encode(toRSSFeed(f.Feed))
+}
+
+// feedFormat method returns the feed format for the request. Query param
+// `format` takes precedence over `Accept` header, RSS is the default.
+func feedFormat(req *ahttp.Request) string {
+	switch strings.ToLower(req.QueryValue(keyFeedFormatParam)) {
+	case FeedFormatAtom:
+		return FeedFormatAtom
+	case FeedFormatRSS:
+		return FeedFormatRSS
+	}
+	if ahttp.Negotiate(req.Unwrap(), ahttp.ContentTypeRSS.Mime,
+		ahttp.ContentTypeAtom.Mime) == ahttp.ContentTypeAtom.Mime {
+		return FeedFormatAtom
+	}
+	return FeedFormatRSS
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// RSS 2.0
+//______________________________________________________________________________
+
+type rssFeed struct {
+	XMLName   xml.Name    `xml:"rss"`
+	Version   string      `xml:"version,attr"`
+	ContentNS string      `xml:"xmlns:content,attr"`
+	Channel   *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string     `xml:"title"`
+	Link           string     `xml:"link"`
+	Description    string     `xml:"description"`
+	Language       string     `xml:"language,omitempty"`
+	Copyright      string     `xml:"copyright,omitempty"`
+	ManagingEditor string     `xml:"managingEditor,omitempty"`
+	LastBuildDate  string     `xml:"lastBuildDate,omitempty"`
+	Items          []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Content     *cdata   `xml:"content:encoded,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category,omitempty"`
+	GUID        *rssGUID `xml:"guid,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+func toRSSFeed(f *Feed) *rssFeed {
+	ch := &rssChannel{
+		Title:          f.Title,
+		Link:           f.Link,
+		Description:    f.Description,
+		Language:       f.Language,
+		Copyright:      f.Copyright,
+		ManagingEditor: rssAuthor(f.Author),
+		LastBuildDate:  rssTime(f.LastModified()),
+	}
+	for _, item := range f.Items {
+		ri := &rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Author:      rssAuthor(item.Author),
+			Categories:  item.Categories,
+			PubDate:     rssTime(item.Published),
+		}
+		if len(item.Content) > 0 {
+			ri.Content = &cdata{Value: item.Content}
+		}
+		if id := item.id(); len(id) > 0 {
+			ri.GUID = &rssGUID{IsPermaLink: id == item.Link, Value: id}
+		}
+		ch.Items = append(ch.Items, ri)
+	}
+	return &rssFeed{Version: "2.0", ContentNS: rssContentNamespace, Channel: ch}
+}
+
+// rssAuthor method returns the RSS author value, RSS requires email address
+// so name is added in parenthesis, i.e. `jeeva@example.com (Jeeva)`.
+func rssAuthor(a *FeedAuthor) string {
+	if a == nil || len(a.Email) == 0 {
+		return ""
+	}
+	if len(a.Name) == 0 {
+		return a.Email
+	}
+	return fmt.Sprintf("%s (%s)", a.Email, a.Name)
+}
+
+func rssTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Atom
+//______________________________________________________________________________
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"feed"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle,omitempty"`
+	Updated  string       `xml:"updated"`
+	Rights   string       `xml:"rights,omitempty"`
+	Link     *atomLink    `xml:"link,omitempty"`
+	Author   *atomAuthor  `xml:"author,omitempty"`
+	Entries  []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string          `xml:"id"`
+	Title      string          `xml:"title"`
+	Updated    string          `xml:"updated"`
+	Published  string          `xml:"published,omitempty"`
+	Link       *atomLink       `xml:"link,omitempty"`
+	Author     *atomAuthor     `xml:"author,omitempty"`
+	Summary    *atomText       `xml:"summary,omitempty"`
+	Content    *atomText       `xml:"content,omitempty"`
+	Categories []*atomCategory `xml:"category,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func toAtomFeed(f *Feed) *atomFeed {
+	af := &atomFeed{
+		Xmlns:    atomNamespace,
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  atomTime(f.LastModified()),
+		Rights:   f.Copyright,
+		Author:   toAtomAuthor(f.Author),
+	}
+	if len(af.ID) == 0 {
+		af.ID = f.Link
+	}
+	if len(f.Link) > 0 {
+		af.Link = &atomLink{Href: f.Link, Rel: "alternate"}
+	}
+	for _, item := range f.Items {
+		ae := &atomEntry{
+			ID:      item.id(),
+			Title:   item.Title,
+			Updated: atomTime(item.modTime()),
+			Author:  toAtomAuthor(item.Author),
+		}
+		if !item.Published.IsZero() {
+			ae.Published = atomTime(item.Published)
+		}
+		if len(item.Link) > 0 {
+			ae.Link = &atomLink{Href: item.Link, Rel: "alternate"}
+		}
+		if len(item.Description) > 0 {
+			ae.Summary = &atomText{Type: "html", Value: item.Description}
+		}
+		if len(item.Content) > 0 {
+			ae.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		for _, c := range item.Categories {
+			ae.Categories = append(ae.Categories, &atomCategory{Term: c})
+		}
+		af.Entries = append(af.Entries, ae)
+	}
+	return af
+}
+
+func toAtomAuthor(a *FeedAuthor) *atomAuthor {
+	if a == nil || (len(a.Name) == 0 && len(a.Email) == 0) {
+		return nil
+	}
+	return &atomAuthor{Name: a.Name, Email: a.Email}
+}
+
+// atomTime method returns the time in RFC 3339 format, Atom requires
+// `updated` element so zero time is formatted too.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
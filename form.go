@@ -0,0 +1,194 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/gob"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	ess "aahframe.work/essentials"
+	"aahframe.work/valpar"
+)
+
+const (
+	keyFormInput        = "_aahFormInput"
+	keyFormErrors       = "_aahFormErrors"
+	keyViewArgFormFlash = "_aahFormFlash"
+)
+
+func init() {
+	gob.Register(map[string][]string{})
+	gob.Register(valpar.Errors{})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context - Form flow methods
+//______________________________________________________________________________
+
+// RedirectBackWithErrors method stores the request form input and given
+// validation errors into flash and redirects back to the request referer,
+// otherwise current request path. Template funcs `formfield`, `formerrors` and
+// `oldinput` renders them on the next request.
+//
+// Note: Password fields and Anti-CSRF token are not stored into flash.
+func (ctx *Context) RedirectBackWithErrors(errs valpar.Errors) {
+	ctx.FlashFormInput()
+	ctx.Session().SetFlash(keyFormErrors, errs)
+
+	target := ctx.Req.Referer()
+	if len(target) == 0 {
+		target = ctx.Req.Path
+	}
+	ctx.Reply().RedirectWithStatus(target, http.StatusSeeOther)
+}
+
+// FlashFormInput method stores the request form input into flash for the
+// next request, see template func `oldinput`.
+func (ctx *Context) FlashFormInput() {
+	if err := ctx.Req.Unwrap().ParseForm(); err != nil {
+		ctx.Log().Error(err)
+		return
+	}
+
+	csrfField := ctx.a.SecurityManager().AntiCSRF.FormFieldName()
+	input := make(map[string][]string)
+	for k, v := range ctx.Req.Unwrap().PostForm {
+		if k == csrfField || strings.Contains(strings.ToLower(k), "password") {
+			continue
+		}
+		input[k] = v
+	}
+	ctx.Session().SetFlash(keyFormInput, input)
+}
+
+// formRedirectBack method returns true if validation errors redirected back
+// to the form per config `request.auto_bind.redirect_back`.
+func (ctx *Context) formRedirectBack(err *Error) bool {
+	if err.Reason != ErrValidation || ctx.a.SessionManager() == nil {
+		return false
+	}
+
+	verrs, ok := err.Data.(valpar.Errors)
+	if !ok {
+		return false
+	}
+	ctx.Log().Debugf("Validation failed, redirecting back to the form: %s", ctx.Req.Referer())
+	ctx.RedirectBackWithErrors(verrs)
+	return true
+}
+
+func isFormContentType(r *ahttp.Request) bool {
+	ct := r.ContentType().Mime
+	return ct == ahttp.ContentTypeForm.Mime || ct == ahttp.ContentTypeMultipartForm.Mime
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Form view functions
+//______________________________________________________________________________
+
+type formFlash struct {
+	input  map[string][]string
+	errors valpar.Errors
+}
+
+// tmplOldInput method returns the previous value of form field from flash.
+// Mapped to Go template func.
+func (vm *viewManager) tmplOldInput(viewArgs map[string]interface{}, name string) string {
+	if v := vm.formFlash(viewArgs).input[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// tmplFormErrors method returns the validation error messages of given form
+// fields from flash, if field names not supplied then all. Mapped to Go
+// template func.
+func (vm *viewManager) tmplFormErrors(viewArgs map[string]interface{}, names ...string) []string {
+	var msgs []string
+	for _, e := range vm.formFlash(viewArgs).errors {
+		if len(names) == 0 || ess.IsSliceContainsString(names, e.Field) {
+			msgs = append(msgs, vm.formErrorMsg(viewArgs, e))
+		}
+	}
+	return msgs
+}
+
+// tmplFormField method renders the HTML input of form field with previous
+// value and inline validation errors. Anti-CSRF form field name renders the
+// hidden input with token. Attributes are supplied as key and value pairs.
+// Mapped to Go template func.
+//
+// For e.g.:
+// 	{{ formfield . "anti_csrf_token" }}
+// 	{{ formfield . "email" "type" "email" "class" "form-control" }}
+func (vm *viewManager) tmplFormField(viewArgs map[string]interface{}, name string, attrs ...string) template.HTML {
+	esc := template.HTMLEscapeString
+	if name == vm.a.SecurityManager().AntiCSRF.FormFieldName() {
+		/* #nosec */
+		return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+			esc(name), esc(vm.tmplAntiCSRFToken(viewArgs))))
+	}
+
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	fmt.Fprintf(buf, `<input name="%s"`, esc(name))
+	inputType := "text"
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if strings.EqualFold(attrs[i], "type") {
+			inputType = strings.ToLower(attrs[i+1])
+		}
+		fmt.Fprintf(buf, ` %s="%s"`, esc(attrs[i]), esc(attrs[i+1]))
+	}
+	if inputType != "password" {
+		fmt.Fprintf(buf, ` value="%s"`, esc(vm.tmplOldInput(viewArgs, name)))
+	}
+
+	msgs := vm.tmplFormErrors(viewArgs, name)
+	if len(msgs) > 0 {
+		buf.WriteString(` aria-invalid="true"`)
+	}
+	buf.WriteString(">")
+	for _, msg := range msgs {
+		fmt.Fprintf(buf, `<span class="form-error">%s</span>`, esc(msg))
+	}
+
+	/* #nosec */
+	return template.HTML(buf.String())
+}
+
+// formFlash method returns the form input and errors from flash, it's
+// cached in view args since flash value is deleted on read.
+func (vm *viewManager) formFlash(viewArgs map[string]interface{}) *formFlash {
+	if ff, ok := viewArgs[keyViewArgFormFlash].(*formFlash); ok {
+		return ff
+	}
+
+	ff := &formFlash{}
+	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil && sub.Session != nil {
+		ff.input, _ = sub.Session.GetFlash(keyFormInput).(map[string][]string)
+		ff.errors, _ = sub.Session.GetFlash(keyFormErrors).(valpar.Errors)
+	}
+	viewArgs[keyViewArgFormFlash] = ff
+	return ff
+}
+
+// formErrorMsg method returns the validation error message, resolved in
+// the order of `Error.Msg`, i18n message by `Error.Key` and default one.
+func (vm *viewManager) formErrorMsg(viewArgs map[string]interface{}, e *valpar.Error) string {
+	if len(e.Msg) > 0 {
+		return e.Msg
+	}
+	if len(e.Key) > 0 && vm.a.I18n() != nil {
+		locale, _ := viewArgs[keyLocale].(*ahttp.Locale)
+		if msg := vm.a.I18n().Lookup(locale, e.Key, e.Field); len(msg) > 0 && msg != e.Key {
+			return msg
+		}
+	}
+	return fmt.Sprintf("%s is invalid, failed on '%s' constraint", e.Field, e.Constraint)
+}
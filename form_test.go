@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"aahframe.work/valpar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormRedirectBackWithErrors(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	form := url.Values{
+		"name":            {"<b>jeeva</b>"},
+		"email":           {"jeeva@"},
+		"password":        {"secret"},
+		"anti_csrf_token": {"token"},
+	}
+	req := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/signup", strings.NewReader(form.Encode()))
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeForm.String())
+	req.Header.Set(ahttp.HeaderReferer, "/signup?plan=pro")
+	ctx := newContext(httptest.NewRecorder(), req)
+	ctx.a = a
+
+	// not a validation error
+	assert.False(t, ctx.formRedirectBack(newError(ErrInvalidRequestParameter, http.StatusBadRequest)))
+
+	verr := newErrorWithData(ErrValidation, http.StatusBadRequest, valpar.Errors{
+		{Field: "email", Value: "jeeva@", Key: "validation.email", Constraint: "email"},
+		{Field: "name", Msg: "name is too short"},
+	})
+	assert.True(t, ctx.formRedirectBack(verr))
+	assert.True(t, ctx.Reply().redirect)
+	assert.Equal(t, http.StatusSeeOther, ctx.Reply().Code)
+	assert.Equal(t, "/signup?plan=pro", ctx.Reply().path)
+
+	input := ctx.Session().Get(flashKey(keyFormInput)).(map[string][]string)
+	assert.Equal(t, 2, len(input))
+	assert.Equal(t, []string{"jeeva@"}, input["email"])
+
+	// template funcs on next request
+	viewArgs := map[string]interface{}{KeyViewArgSubject: ctx.Subject()}
+	assert.Equal(t, "<b>jeeva</b>", a.viewMgr.tmplOldInput(viewArgs, "name"))
+	assert.Equal(t, "", a.viewMgr.tmplOldInput(viewArgs, "password"))
+	assert.Equal(t, []string{
+		"email is invalid, failed on 'email' constraint",
+		"name is too short",
+	}, a.viewMgr.tmplFormErrors(viewArgs))
+	assert.Equal(t, []string{"name is too short"}, a.viewMgr.tmplFormErrors(viewArgs, "name"))
+
+	// flash values are consumed and cached in view args
+	assert.Nil(t, ctx.Session().Get(flashKey(keyFormInput)))
+	assert.Nil(t, ctx.Session().Get(flashKey(keyFormErrors)))
+
+	assert.Equal(t, `<input name="name" class="form-control" value="&lt;b&gt;jeeva&lt;/b&gt;" aria-invalid="true">`+
+		`<span class="form-error">name is too short</span>`,
+		string(a.viewMgr.tmplFormField(viewArgs, "name", "class", "form-control")))
+	assert.Equal(t, `<input name="password" type="password">`,
+		string(a.viewMgr.tmplFormField(viewArgs, "password", "type", "password")))
+	assert.Equal(t, `<input type="hidden" name="anti_csrf_token" value="">`,
+		string(a.viewMgr.tmplFormField(viewArgs, "anti_csrf_token")))
+}
+
+func flashKey(key string) string {
+	return "_flash_" + key
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+)
+
+const (
+	forwardAuthTarget       = "forwardAuthController"
+	forwardAuthRouteName    = "forward_auth_check__aah"
+	forwardAuthDefaultPath  = "/_auth/check"
+	forwardAuthHeaderPrefix = "X-Auth-"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Forward Auth controller
+//______________________________________________________________________________
+
+// forwardAuthController serves the auth sub-request endpoint for reverse
+// proxies such as nginx `auth_request` and Traefik `forwardAuth`.
+type forwardAuthController struct {
+	*Context
+}
+
+// Check method authenticates and authorizes the original request described
+// by headers `X-Forwarded-Method`, `X-Forwarded-Uri` and `X-Forwarded-Host`
+// (or nginx `X-Original-Method`, `X-Original-Uri`) using configured auth
+// schemes. It replies `200 OK` with identity headers on success, `401` on
+// authentication failure and `403` on authorization failure.
+func (c *forwardAuthController) Check() {
+	req := c.forwardedRequest()
+	var route *router.Route
+	authNames := c.a.Config().StringDefault("security.forward_auth.auth", "")
+	if domain := c.a.Router().Lookup(req.Host); domain != nil {
+		route, _, _ = domain.Lookup(req)
+		if len(authNames) == 0 {
+			if route != nil {
+				authNames = route.Auth
+			} else {
+				authNames = domain.DefaultAuth
+			}
+		}
+	}
+
+	c.Log().Debugf("Forward auth check for %s %s, auth: %s", req.Method, req.URL.Path, authNames)
+	if !c.a.settings.AuthSchemeExists || len(authNames) == 0 || authNames == "anonymous" {
+		c.Reply().Ok().Text("")
+		return
+	}
+
+	var authSchemeKey string
+	if c.Subject().IsAuthenticated() && c.Session().GetString(keyAuthScheme) != "" {
+		authSchemeKey = c.Session().GetString(keyAuthScheme)
+		populateAuthorizationInfo(c.a.SecurityManager().AuthScheme(authSchemeKey), c.Context)
+	} else if authNames == "authenticated" {
+		c.Reply().Unauthorized().Error(newError(ErrNotAuthenticated, http.StatusUnauthorized))
+		return
+	} else if authSchemeKey = c.authenticate(authNames); len(authSchemeKey) == 0 {
+		return
+	}
+
+	if route != nil {
+		if result, reasons := route.HasAccess(c.Subject()); !result {
+			c.Log().Warnf("Forward auth authorization failed:%s", reason2String(reasons))
+			c.Reply().Forbidden().Error(newErrorWithData(ErrAuthorizationFailed, http.StatusForbidden, reasons))
+			return
+		}
+	}
+
+	prefix := c.a.Config().StringDefault("security.forward_auth.header_prefix", forwardAuthHeaderPrefix)
+	c.Reply().
+		Header(prefix+"Subject", c.Subject().PrimaryPrincipal().Value).
+		Header(prefix+"Scheme", authSchemeKey)
+	if c.Subject().AuthorizationInfo != nil {
+		c.Reply().Header(prefix+"Roles", c.Subject().AuthorizationInfo.Roles())
+	}
+	c.Reply().Ok().Text("")
+}
+
+// authenticate method authenticates the request with given auth schemes and
+// returns the successful auth scheme key otherwise empty string. Auth schemes
+// `form` and `oauth2` are skipped since it requires interactive login, its
+// authenticated session is honored.
+func (c *forwardAuthController) authenticate(authNames string) string {
+	for _, s := range strings.Split(authNames, ",") {
+		authScheme := c.a.SecurityManager().AuthScheme(strings.TrimSpace(s))
+		if authScheme == nil {
+			continue
+		}
+		if authScheme.Scheme() == "form" || authScheme.Scheme() == "oauth2" {
+			c.Log().Debugf("Forward auth skips interactive auth scheme: %s", authScheme.Key())
+			continue
+		}
+		if doAuthScheme(authScheme, c.Context) == flowCont {
+			return authScheme.Key()
+		}
+	}
+
+	if c.Reply().err == nil {
+		c.Reply().Unauthorized().Error(newError(ErrNotAuthenticated, http.StatusUnauthorized))
+	}
+	return ""
+}
+
+// forwardedRequest method returns the original request sent by reverse proxy
+// in the headers.
+func (c *forwardAuthController) forwardedRequest() *http.Request {
+	r := c.Req.Unwrap()
+	method := firstNonEmpty(r.Header.Get(ahttp.HeaderXForwardedMethod),
+		r.Header.Get(ahttp.HeaderXOriginalMethod), ahttp.MethodGet)
+	uri := firstNonEmpty(r.Header.Get(ahttp.HeaderXForwardedURI),
+		r.Header.Get(ahttp.HeaderXOriginalURI), "/")
+
+	req, err := http.NewRequest(strings.ToUpper(method), uri, nil)
+	if err != nil {
+		c.Log().Warnf("Forward auth invalid original request URI '%s': %v", uri, err)
+		req, _ = http.NewRequest(ahttp.MethodGet, "/", nil)
+	}
+	req.Host = ahttp.Host(r)
+	return req
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// addForwardAuthRoutes method adds forward auth check route into all domains
+// if it's enabled.
+func (a *Application) addForwardAuthRoutes(rtr *router.Router) error {
+	cfg := a.Config()
+	if !cfg.BoolDefault("security.forward_auth.enable", false) {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(forwardAuthTarget) == nil {
+		a.AddController((*forwardAuthController)(nil), []*ainsp.Method{{Name: "Check"}})
+	}
+
+	for _, d := range rtr.Domains {
+		if err := d.AddRoute(&router.Route{
+			Name:   forwardAuthRouteName,
+			Path:   cfg.StringDefault("security.forward_auth.path", forwardAuthDefaultPath),
+			Method: ahttp.MethodGet,
+			Target: forwardAuthTarget,
+			Action: "Check",
+			Auth:   "anonymous",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
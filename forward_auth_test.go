@@ -0,0 +1,100 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security/scheme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardAuthCheck(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Forward Auth]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    basic_auth {
+		      scheme = "basic"
+		      realm_name = "Forward Auth"
+		      authenticator = "security/Authentication"
+		      authorizer = "security/Authorization"
+		    }
+		  }
+		  forward_auth {
+		    enable = true
+		  }
+		}
+	`)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initSecurity())
+
+	basicAuth := ts.app.SecurityManager().AuthScheme("basic_auth").(*scheme.BasicAuth)
+	assert.Nil(t, basicAuth.SetAuthenticator(&testBasicAuth{}))
+	assert.Nil(t, basicAuth.SetAuthorizer(&testBasicAuth{}))
+
+	assert.Nil(t, ts.app.addForwardAuthRoutes(ts.app.Router()))
+	domain := ts.app.Router().Lookup("localhost:8080")
+	assert.NotNil(t, domain.LookupByName(forwardAuthRouteName))
+
+	httpClient := new(http.Client)
+	check := func(user, pass string, hdrs map[string]string) *http.Response {
+		req, err := http.NewRequest(ahttp.MethodGet, ts.URL+forwardAuthDefaultPath, nil)
+		assert.Nil(t, err)
+		for k, v := range hdrs {
+			req.Header.Set(k, v)
+		}
+		if len(user) > 0 {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := httpClient.Do(req)
+		assert.Nil(t, err)
+		responseBody(resp)
+		return resp
+	}
+
+	// route auth is anonymous
+	resp := check("", "", map[string]string{
+		ahttp.HeaderXForwardedMethod: ahttp.MethodGet,
+		ahttp.HeaderXForwardedURI:    "/get-text.html",
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "", resp.Header.Get("X-Auth-Subject"))
+
+	ts.app.Config().SetString("security.forward_auth.auth", "basic_auth")
+
+	// not authenticated
+	resp = check("", "", map[string]string{ahttp.HeaderXOriginalURI: "/get-text.html"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Basic realm="Forward Auth"`, resp.Header.Get(ahttp.HeaderWWWAuthenticate))
+
+	// wrong credentials
+	resp = check("jeeva", "wrongpass", map[string]string{ahttp.HeaderXOriginalURI: "/get-text.html"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// authenticated
+	resp = check("jeeva", "welcome123", map[string]string{
+		ahttp.HeaderXForwardedMethod: ahttp.MethodPost,
+		ahttp.HeaderXForwardedURI:    "/get-text.html?a=b",
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "jeeva", resp.Header.Get("X-Auth-Subject"))
+	assert.Equal(t, "basic_auth", resp.Header.Get("X-Auth-Scheme"))
+
+	// custom header prefix
+	ts.app.Config().SetString("security.forward_auth.header_prefix", "X-User-")
+	resp = check("jeeva", "welcome123", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "jeeva", resp.Header.Get("X-User-Subject"))
+}
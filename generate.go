@@ -0,0 +1,569 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/console"
+	"aahframe.work/essentials"
+)
+
+var (
+	// ErrGenerateFileExists returned when generator target file already exists
+	// and force flag is not supplied.
+	ErrGenerateFileExists = errors.New("aah: file already exists, use --force to overwrite")
+
+	// ErrGenerateRoutesSection returned when `routes { ... }` section not found
+	// in the routes configuration file.
+	ErrGenerateRoutesSection = errors.New("aah: 'routes' section not found in routes.conf")
+)
+
+func (a *Application) cliCmdGenerate() console.Command {
+	forceFlag := console.BoolFlag{
+		Name:  "force, f",
+		Usage: "Overwrites the files if it exists",
+	}
+	return console.Command{
+		Name:    "generate",
+		Aliases: []string{"g"},
+		Usage:   "Generates controller, model and route for the app",
+		Description: `Generates controller, model and route for the app as per aah project layout.
+	To know more about individual sub-commands details:
+		<app-binary> generate help controller`,
+		Subcommands: []console.Command{
+			{
+				Name:      "controller",
+				Aliases:   []string{"c"},
+				Usage:     "Generates controller with actions, routes, view stubs and test skeleton",
+				ArgsUsage: "<name>",
+				Description: `Generates controller file into 'app/controllers', routes into 'config/routes.conf',
+	view stubs into 'views/pages' and test skeleton. Controller name can have namespace.
+
+		Example:
+			<app-binary> generate controller User --actions=Index,Show,Create
+			<app-binary> generate controller admin/User --actions=Index --no-view`,
+				Flags: []console.Flag{
+					console.StringFlag{
+						Name:  "actions, a",
+						Value: "Index",
+						Usage: "Comma separated action names",
+					},
+					console.BoolFlag{
+						Name:  "no-view",
+						Usage: "Skips the view stubs generation",
+					},
+					console.BoolFlag{
+						Name:  "no-route",
+						Usage: "Skips the routes generation",
+					},
+					console.BoolFlag{
+						Name:  "no-test",
+						Usage: "Skips the test skeleton generation",
+					},
+					forceFlag,
+				},
+				Action: func(c *console.Context) error {
+					g := a.newGenerator(c)
+					return g.Controller(c.Args().First(), splitNames(c.String("actions")), generateOptions{
+						View:  !c.Bool("no-view") && a.Config().BoolDefault("view.enable", true),
+						Route: !c.Bool("no-route"),
+						Test:  !c.Bool("no-test"),
+					})
+				},
+			},
+			{
+				Name:      "model",
+				Aliases:   []string{"m"},
+				Usage:     "Generates model struct",
+				ArgsUsage: "<name>",
+				Description: `Generates model struct into 'app/models'.
+
+		Example:
+			<app-binary> generate model User --fields=Name:string,Email:string,Age:int`,
+				Flags: []console.Flag{
+					console.StringFlag{
+						Name:  "fields",
+						Usage: "Comma separated field name and type, i.e. name:type",
+					},
+					forceFlag,
+				},
+				Action: func(c *console.Context) error {
+					return a.newGenerator(c).Model(c.Args().First(), splitNames(c.String("fields")))
+				},
+			},
+			{
+				Name:      "route",
+				Aliases:   []string{"r"},
+				Usage:     "Adds route entry into routes.conf",
+				ArgsUsage: "<name>",
+				Description: `Adds route entry into 'config/routes.conf'.
+
+		Example:
+			<app-binary> generate route user_show --path=/users/:id --controller=UserController --action=Show`,
+				Flags: []console.Flag{
+					console.StringFlag{
+						Name:  "path",
+						Usage: "Route path",
+					},
+					console.StringFlag{
+						Name:  "method",
+						Value: ahttp.MethodGet,
+						Usage: "Route HTTP method",
+					},
+					console.StringFlag{
+						Name:  "controller",
+						Usage: "Route controller name",
+					},
+					console.StringFlag{
+						Name:  "action",
+						Usage: "Route controller action name",
+					},
+				},
+				Action: func(c *console.Context) error {
+					return a.newGenerator(c).Route(&generateRoute{
+						Name:       c.Args().First(),
+						Path:       c.String("path"),
+						Method:     strings.ToUpper(c.String("method")),
+						Controller: c.String("controller"),
+						Action:     c.String("action"),
+					})
+				},
+			},
+		},
+	}
+}
+
+func (a *Application) newGenerator(c *console.Context) *generator {
+	return &generator{
+		baseDir: a.BaseDir(),
+		force:   c.Bool("force"),
+		w:       c.App.Writer,
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// generator
+//______________________________________________________________________________
+
+type generateOptions struct {
+	View  bool
+	Route bool
+	Test  bool
+}
+
+type generateRoute struct {
+	Name       string
+	Path       string
+	Method     string
+	Controller string
+	Action     string
+}
+
+type generateField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// generator creates the source files as per aah project layout, i.e.
+// `app/controllers`, `app/models`, `views/pages` and `config/routes.conf`.
+type generator struct {
+	baseDir string
+	force   bool
+	w       io.Writer
+}
+
+// Controller method generates the controller file with given actions, its
+// routes, view stubs and test skeleton.
+func (g *generator) Controller(name string, actions []string, opts generateOptions) error {
+	namespace, name := splitNamespace(name)
+	if len(name) == 0 {
+		return errors.New("aah: controller name is required")
+	}
+	name = strings.TrimSuffix(exportedName(name), "Controller")
+	if len(actions) == 0 {
+		actions = []string{"Index"}
+	}
+	for i := range actions {
+		actions[i] = exportedName(actions[i])
+	}
+
+	pkgName := "controllers"
+	if len(namespace) > 0 {
+		pkgName = path.Base(namespace)
+	}
+	ctrlDir := filepath.Join(g.baseDir, "app", "controllers", filepath.FromSlash(namespace))
+	fileName := toSnakeCase(name)
+	data := map[string]interface{}{
+		"Package":    pkgName,
+		"Controller": name + "Controller",
+		"Actions":    actions,
+		"View":       opts.View,
+	}
+
+	if err := g.writeTemplate(filepath.Join(ctrlDir, fileName+".go"), controllerTmpl, data); err != nil {
+		return err
+	}
+	if opts.Test {
+		if err := g.writeTemplate(filepath.Join(ctrlDir, fileName+"_test.go"), controllerTestTmpl, data); err != nil {
+			return err
+		}
+	}
+	if opts.View {
+		viewDir := filepath.Join(g.baseDir, "views", "pages", filepath.FromSlash(strings.ToLower(namespace)), strings.ToLower(name))
+		for _, action := range actions {
+			vdata := map[string]interface{}{"Controller": name, "Action": action}
+			if err := g.writeTemplate(filepath.Join(viewDir, strings.ToLower(action)+".html"), viewTmpl, vdata); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Route {
+		target := path.Join(namespace, name+"Controller")
+		routePrefix := "/" + path.Join(namespace, toKebabCase(name)+"s")
+		namePrefix := strings.Replace(path.Join(namespace, toSnakeCase(name)), "/", "_", -1)
+		var routes []*generateRoute
+		for _, action := range actions {
+			r := &generateRoute{
+				Name:       namePrefix + "_" + toSnakeCase(action),
+				Path:       routePrefix,
+				Method:     ahttp.MethodGet,
+				Controller: target,
+				Action:     action,
+			}
+			switch action {
+			case "Index":
+			case "Create":
+				r.Method = ahttp.MethodPost
+			case "Show":
+				r.Path += "/:id"
+			case "Update":
+				r.Method, r.Path = ahttp.MethodPut, r.Path+"/:id"
+			case "Delete":
+				r.Method, r.Path = ahttp.MethodDelete, r.Path+"/:id"
+			default:
+				r.Path += "/" + toKebabCase(action)
+			}
+			routes = append(routes, r)
+		}
+		return g.addRoutes(routes...)
+	}
+	return nil
+}
+
+// Model method generates the model struct with given fields, field is
+// `name:type` pair.
+func (g *generator) Model(name string, fields []string) error {
+	namespace, name := splitNamespace(name)
+	if len(name) == 0 {
+		return errors.New("aah: model name is required")
+	}
+	name = exportedName(name)
+
+	pkgName := "models"
+	if len(namespace) > 0 {
+		pkgName = path.Base(namespace)
+	}
+	var gfields []*generateField
+	var imports []string
+	for _, f := range fields {
+		parts := strings.SplitN(f, ":", 2)
+		ftype := "string"
+		if len(parts) == 2 && len(parts[1]) > 0 {
+			ftype = parts[1]
+		}
+		if strings.Contains(ftype, "time.") && !ess.IsSliceContainsString(imports, "time") {
+			imports = append(imports, "time")
+		}
+		gfields = append(gfields, &generateField{
+			Name: exportedName(parts[0]),
+			Type: ftype,
+			Tag:  fmt.Sprintf("`json:\"%s\"`", toSnakeCase(parts[0])),
+		})
+	}
+
+	fpath := filepath.Join(g.baseDir, "app", "models", filepath.FromSlash(namespace), toSnakeCase(name)+".go")
+	return g.writeTemplate(fpath, modelTmpl, map[string]interface{}{
+		"Package": pkgName,
+		"Imports": imports,
+		"Model":   name,
+		"Fields":  gfields,
+	})
+}
+
+// Route method adds the given route entry into routes configuration.
+func (g *generator) Route(r *generateRoute) error {
+	if len(r.Name) == 0 || len(r.Path) == 0 || len(r.Controller) == 0 {
+		return errors.New("aah: route name, path and controller are required")
+	}
+	return g.addRoutes(r)
+}
+
+func (g *generator) addRoutes(routes ...*generateRoute) error {
+	fpath := filepath.Join(g.baseDir, "config", "routes.conf")
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return err
+	}
+
+	content := string(b)
+	for _, r := range routes {
+		if content, err = insertRoute(content, r); err != nil {
+			return err
+		}
+		fmt.Fprintf(g.w, "Route '%s' added into %s\n", r.Name, fpath)
+	}
+	return ioutil.WriteFile(fpath, []byte(content), 0644)
+}
+
+func (g *generator) writeTemplate(fpath, tmpl string, data interface{}) error {
+	if ess.IsFileExists(fpath) && !g.force {
+		return fmt.Errorf("%v: %s", ErrGenerateFileExists, fpath)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := template.Must(template.New("generate").Parse(tmpl)).Execute(buf, data); err != nil {
+		return err
+	}
+	b := buf.Bytes()
+	if filepath.Ext(fpath) == ".go" {
+		var err error
+		if b, err = format.Source(b); err != nil {
+			return err
+		}
+	}
+	if err := ess.MkDirAll(filepath.Dir(fpath), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(fpath, b, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(g.w, "Created %s\n", fpath)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// insertRoute method adds the route entry at the end of first `routes { ... }`
+// section of the routes configuration content. If route name already exists
+// in that section then it returns error.
+func insertRoute(content string, r *generateRoute) (string, error) {
+	start, end := routesSection(content)
+	if start == -1 {
+		return content, ErrGenerateRoutesSection
+	}
+
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	indent := content[lineStart:start]
+	indent = indent[:len(indent)-len(strings.TrimLeftFunc(indent, unicode.IsSpace))] + "  "
+	for _, line := range strings.Split(content[start:end], "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[0] == r.Name && fields[1] == "{" {
+			return content, fmt.Errorf("aah: route name '%s' already exists", r.Name)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "\n%s%s {\n", indent, r.Name)
+	fmt.Fprintf(buf, "%s  path = %q\n", indent, r.Path)
+	if len(r.Method) > 0 && r.Method != ahttp.MethodGet {
+		fmt.Fprintf(buf, "%s  method = %q\n", indent, r.Method)
+	}
+	fmt.Fprintf(buf, "%s  controller = %q\n", indent, r.Controller)
+	if len(r.Action) > 0 {
+		fmt.Fprintf(buf, "%s  action = %q\n", indent, r.Action)
+	}
+	fmt.Fprintf(buf, "%s}\n", indent)
+
+	// insert before the line of closing brace
+	insertAt := strings.LastIndexByte(content[:end], '\n') + 1
+	body := strings.TrimRight(content[:insertAt], " \t\n")
+	return body + "\n" + buf.String() + content[insertAt:], nil
+}
+
+// routesSection method returns the index of `routes` section key and its
+// closing brace index, otherwise -1.
+func routesSection(content string) (int, int) {
+	start, depth := -1, 0
+	lines := strings.SplitAfter(content, "\n")
+	offset := 0
+	for _, line := range lines {
+		lineOffset := offset
+		offset += len(line)
+		code := stripConfComment(line)
+		if start == -1 {
+			trimmed := strings.TrimSpace(code)
+			if strings.HasPrefix(trimmed, "routes") && strings.HasSuffix(trimmed, "{") &&
+				strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, "routes"), "{")) == "" {
+				start = lineOffset + strings.Index(line, "routes")
+				depth = 1
+			}
+			continue
+		}
+		inQuote := false
+		for i := 0; i < len(code); i++ {
+			switch code[i] {
+			case '"':
+				inQuote = !inQuote
+			case '{':
+				if !inQuote {
+					depth++
+				}
+			case '}':
+				if !inQuote {
+					if depth--; depth == 0 {
+						return start, lineOffset + i
+					}
+				}
+			}
+		}
+	}
+	return -1, -1
+}
+
+// stripConfComment method removes the `#` and `//` comments from the config
+// line, quoted values are kept as-is.
+func stripConfComment(line string) string {
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && line[i] == '#':
+			return line[:i]
+		case !inQuote && line[i] == '/' && i+1 < len(line) && line[i+1] == '/':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func splitNamespace(name string) (string, string) {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	if idx := strings.LastIndexByte(name, '/'); idx > -1 {
+		return strings.ToLower(name[:idx]), name[idx+1:]
+	}
+	return "", name
+}
+
+func splitNames(s string) []string {
+	var names []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			names = append(names, v)
+		}
+	}
+	return names
+}
+
+// exportedName method converts the given name into Go exported identifier.
+// For e.g.: user_profile => UserProfile
+func exportedName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// toSnakeCase method converts the given Go identifier into snake case.
+// For e.g.: UserProfile => user_profile
+func toSnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func toKebabCase(name string) string {
+	return strings.Replace(toSnakeCase(name), "_", "-", -1)
+}
+
+const controllerTmpl = `package {{ .Package }}
+
+import (
+	"aahframe.work"
+)
+
+// {{ .Controller }} struct is generated by aah generate command.
+type {{ .Controller }} struct {
+	*aah.Context
+}
+{{ range .Actions }}
+// {{ . }} method is the {{ $.Controller }} action.
+func (c *{{ $.Controller }}) {{ . }}() {
+{{- if $.View }}
+	c.Reply().Ok().HTML(aah.Data{})
+{{- else }}
+	c.Reply().Ok().JSON(aah.Data{})
+{{- end }}
+}
+{{ end -}}
+`
+
+const controllerTestTmpl = `package {{ .Package }}
+
+import (
+	"testing"
+)
+{{ range .Actions }}
+func Test{{ $.Controller }}{{ . }}(t *testing.T) {
+	t.Skip("TODO: implement test for {{ $.Controller }}.{{ . }}")
+}
+{{ end -}}
+`
+
+const modelTmpl = `package {{ .Package }}
+{{ if .Imports }}
+import (
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+{{ end }}
+// {{ .Model }} struct is generated by aah generate command.
+type {{ .Model }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} {{ .Tag }}
+{{- end }}
+}
+`
+
+const viewTmpl = `{{ "{{" }} define "title" {{ "}}" }}{{ .Controller }} - {{ .Action }}{{ "{{" }} end {{ "}}" }}
+
+{{ "{{" }} define "body" -{{ "}}" }}
+    <h1>{{ .Controller }} {{ .Action }}</h1>
+{{ "{{" }}- end {{ "}}" }}
+`
@@ -0,0 +1,114 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateController(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "aahgenerate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(baseDir)
+
+	routesConf := `domains {
+  localhost {
+    host = "localhost"
+
+    # routes section
+    routes {
+      index {
+        path = "/"
+        controller = "AppController"
+        # comment with brace }
+      }
+    }
+  }
+}
+`
+	err = os.MkdirAll(filepath.Join(baseDir, "config"), 0755)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(baseDir, "config", "routes.conf"), []byte(routesConf), 0644)
+	assert.Nil(t, err)
+
+	out := new(bytes.Buffer)
+	g := &generator{baseDir: baseDir, w: out}
+	err = g.Controller("UserProfile", []string{"Index", "Show", "Create", "export_all"}, generateOptions{View: true, Route: true, Test: true})
+	assert.Nil(t, err)
+
+	b, err := ioutil.ReadFile(filepath.Join(baseDir, "app", "controllers", "user_profile.go"))
+	assert.Nil(t, err)
+	src := string(b)
+	assert.True(t, strings.HasPrefix(src, "package controllers\n"))
+	assert.True(t, strings.Contains(src, "type UserProfileController struct {\n\t*aah.Context\n}"))
+	assert.True(t, strings.Contains(src, "func (c *UserProfileController) ExportAll() {"))
+	assert.True(t, ess.IsFileExists(filepath.Join(baseDir, "app", "controllers", "user_profile_test.go")))
+	assert.True(t, ess.IsFileExists(filepath.Join(baseDir, "views", "pages", "userprofile", "show.html")))
+
+	b, err = ioutil.ReadFile(filepath.Join(baseDir, "config", "routes.conf"))
+	assert.Nil(t, err)
+	cfg, err := config.ParseString(string(b))
+	assert.Nil(t, err)
+	routesKey := "domains.localhost.routes."
+	assert.Equal(t, "/user-profiles/:id", cfg.StringDefault(routesKey+"user_profile_show.path", ""))
+	assert.Equal(t, "POST", cfg.StringDefault(routesKey+"user_profile_create.method", ""))
+	assert.Equal(t, "/user-profiles/export-all", cfg.StringDefault(routesKey+"user_profile_export_all.path", ""))
+	assert.Equal(t, "UserProfileController", cfg.StringDefault(routesKey+"user_profile_index.controller", ""))
+	assert.Equal(t, "AppController", cfg.StringDefault(routesKey+"index.controller", ""))
+
+	// file exists
+	err = g.Controller("UserProfile", nil, generateOptions{})
+	assert.True(t, strings.HasPrefix(err.Error(), ErrGenerateFileExists.Error()))
+
+	// namespace and route exists
+	g.force = true
+	err = g.Controller("admin/User", []string{"Index"}, generateOptions{Route: true})
+	assert.Nil(t, err)
+	b, err = ioutil.ReadFile(filepath.Join(baseDir, "app", "controllers", "admin", "user.go"))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "package admin\n"))
+
+	err = g.Route(&generateRoute{Name: "admin_user_index", Path: "/admin/users", Controller: "admin/UserController"})
+	assert.Equal(t, "aah: route name 'admin_user_index' already exists", err.Error())
+	err = g.Route(&generateRoute{Name: "about"})
+	assert.NotNil(t, err)
+}
+
+func TestGenerateModel(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "aahgenerate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(baseDir)
+
+	g := &generator{baseDir: baseDir, w: new(bytes.Buffer)}
+	err = g.Model("user", []string{"name", "email:string", "age:int", "created_at:time.Time"})
+	assert.Nil(t, err)
+
+	b, err := ioutil.ReadFile(filepath.Join(baseDir, "app", "models", "user.go"))
+	assert.Nil(t, err)
+	src := string(b)
+	assert.True(t, strings.Contains(src, "import (\n\t\"time\"\n)"))
+	assert.True(t, strings.Contains(src, "\tName      string    `json:\"name\"`\n"))
+	assert.True(t, strings.Contains(src, "\tCreatedAt time.Time `json:\"created_at\"`\n"))
+
+	err = g.Model("", nil)
+	assert.NotNil(t, err)
+
+	_, err = insertRoute("domains {}", &generateRoute{Name: "index"})
+	assert.Equal(t, ErrGenerateRoutesSection, err)
+
+	assert.Equal(t, "user_profile", toSnakeCase("UserProfile"))
+	assert.Equal(t, "http_server", toSnakeCase("HTTPServer"))
+	assert.Equal(t, "UserProfile", exportedName("user_profile"))
+	assert.Equal(t, "name: \"a#b\" ", stripConfComment("name: \"a#b\" # comment"))
+}
@@ -1,5 +1,7 @@
 module aahframe.work
 
+go 1.18
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-aah/forge v0.8.0
@@ -0,0 +1,113 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"aahframe.work/aruntime"
+	"aahframe.work/log"
+)
+
+// Go method runs the given func in a new goroutine bound to the application
+// lifetime. Go context passed to the func gets cancelled on server shutdown,
+// aah waits for the goroutines to return upto `server.timeout.grace_shutdown`.
+// Panic in the func is recovered and logged.
+//
+// 	aah.App().Go(func(c context.Context) {
+// 		ticker := time.NewTicker(time.Minute)
+// 		defer ticker.Stop()
+// 		for {
+// 			select {
+// 			case <-c.Done():
+// 				return
+// 			case <-ticker.C:
+// 				refreshRates(c)
+// 			}
+// 		}
+// 	})
+func (a *Application) Go(fn func(c context.Context)) {
+	a.goroutines.wg.Add(1)
+	go func() {
+		defer a.goroutines.wg.Done()
+		a.runGo(a.goroutines.ctx, a.Log(), fn)
+	}()
+}
+
+// Go method runs the given func in a new goroutine bound to the request
+// lifecycle. Go context passed to the func gets cancelled when the reply is
+// written or the client disconnects. Func gets the logger with request fields
+// via `log.FromGoContext`, panic in the func is recovered and logged.
+//
+// Note: Do not use `aah.Context` within the func, it gets released after the
+// reply is written.
+//
+// 	ctx.Go(func(c context.Context) {
+// 		if err := notify(c, order); err != nil {
+// 			log.FromGoContext(c).Error(err)
+// 		}
+// 	})
+func (ctx *Context) Go(fn func(c context.Context)) {
+	ctx.checkReleased()
+	goCtx, cancel := context.WithCancel(ctx.GoContext())
+	ctx.goCancels = append(ctx.goCancels, cancel)
+	logger := ctx.Log()
+	go func() {
+		defer cancel()
+		ctx.a.runGo(goCtx, logger, fn)
+	}()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+type goroutines struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newGoroutines() *goroutines {
+	g := &goroutines{}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	return g
+}
+
+// shutdown method cancels the app goroutines and waits for them upto given
+// timeout.
+func (g *goroutines) shutdown(a *Application, timeout time.Duration) {
+	g.cancel()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		a.Log().Warnf("Goroutines did not return within %s", timeout)
+	}
+}
+
+// runGo method calls the func with panic recovery and the logger in Go
+// context.
+func (a *Application) runGo(c context.Context, logger log.Loggerer, fn func(c context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			st := aruntime.NewStacktrace(r, a.Config())
+			buf := acquireBuilder()
+			defer releaseBuilder(buf)
+			st.Print(buf)
+
+			logger.Errorf("Recovered from panic on goroutine: %v", r)
+			logger.Error(buf.String())
+			a.reportPanic(nil, r, st)
+		}
+	}()
+	fn(log.NewGoContext(c, logger))
+}
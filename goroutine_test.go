@@ -0,0 +1,86 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppGoShutdown(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	buf := new(bytes.Buffer)
+	a.Log().(*log.Logger).SetWriter(buf)
+
+	var returned int32
+	for i := 0; i < 3; i++ {
+		a.Go(func(c context.Context) {
+			<-c.Done()
+			atomic.AddInt32(&returned, 1)
+		})
+	}
+	a.Go(func(c context.Context) {
+		panic("app goroutine panic")
+	})
+
+	a.goroutines.shutdown(a, 2*time.Second)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&returned))
+	assert.True(t, strings.Contains(buf.String(), "Recovered from panic on goroutine: app goroutine panic"))
+
+	// goroutine does not return within timeout
+	a = newApp()
+	buf.Reset()
+	a.Log().(*log.Logger).SetWriter(buf)
+	block := make(chan struct{})
+	defer close(block)
+	a.Go(func(c context.Context) { <-block })
+	a.goroutines.shutdown(a, 10*time.Millisecond)
+	assert.True(t, strings.Contains(buf.String(), "Goroutines did not return within 10ms"))
+}
+
+func TestContextGo(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+	ctx := newContext(httptest.NewRecorder(), req)
+	ctx.a, ctx.e = a, a.he
+
+	started, done := make(chan struct{}), make(chan error, 1)
+	ctx.Go(func(c context.Context) {
+		assert.NotNil(t, log.FromGoContext(c))
+		close(started)
+		<-c.Done()
+		done <- c.Err()
+	})
+	ctx.Go(func(c context.Context) {
+		panic("request goroutine panic")
+	})
+
+	<-started
+	a.he.releaseContext(ctx)
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(2 * time.Second):
+		t.Error("request goroutine not cancelled on release")
+	}
+
+	assert.Equal(t, log.FromGoContext(context.Background()), log.FromGoContext(log.NewGoContext(context.Background(), nil)))
+}
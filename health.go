@@ -0,0 +1,109 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+)
+
+const (
+	healthTarget      = "healthController"
+	healthRouteName   = "health__aah"
+	healthDefaultPath = "/health"
+)
+
+// Health status values
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+// HealthStatus method returns the aah application health status composed
+// from supervised workers status.
+//
+//    - `down` if any worker has failed, i.e. reached max restarts
+//
+//    - `degraded` if any worker is restarting
+//
+//    - otherwise `up`
+func (a *Application) HealthStatus() string {
+	status := HealthStatusUp
+	for _, ws := range a.WorkerStatus() {
+		switch ws.State {
+		case WorkerStateFailed:
+			return HealthStatusDown
+		case WorkerStateRestarting:
+			status = HealthStatusDegraded
+		}
+	}
+	return status
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Health controller
+//______________________________________________________________________________
+
+// healthController serves the health endpoint.
+type healthController struct {
+	*Context
+}
+
+// Check method replies the app health status along with supervised workers
+// status. Status code is 503 if app health status is `down`.
+func (c *healthController) Check() {
+	status := c.a.HealthStatus()
+	code := http.StatusOK
+	if status == HealthStatusDown {
+		code = http.StatusServiceUnavailable
+	}
+	c.Reply().Status(code).
+		Header(ahttp.HeaderCacheControl, "no-cache, no-store, must-revalidate").
+		JSON(Data{"status": status, "workers": c.a.WorkerStatus()})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// addHealthRoutes method adds health route into all domains if it's enabled.
+//
+// 	runtime {
+// 	  health {
+// 	    # default value is false
+// 	    enable = true
+//
+// 	    # default value is "/health"
+// 	    path = "/health"
+// 	  }
+// 	}
+func (a *Application) addHealthRoutes(rtr *router.Router) error {
+	cfg := a.Config()
+	if !cfg.BoolDefault("runtime.health.enable", false) {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(healthTarget) == nil {
+		a.AddController((*healthController)(nil), []*ainsp.Method{{Name: "Check"}})
+	}
+
+	for _, d := range rtr.Domains {
+		if err := d.AddRoute(&router.Route{
+			Name:   healthRouteName,
+			Path:   cfg.StringDefault("runtime.health.path", healthDefaultPath),
+			Method: ahttp.MethodGet,
+			Target: healthTarget,
+			Action: "Check",
+			Auth:   "anonymous",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
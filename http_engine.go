@@ -5,9 +5,11 @@
 package aah
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -80,6 +82,14 @@ func (e *HTTPEngine) Handle(w http.ResponseWriter, r *http.Request) {
 		defer e.a.accessLog.Log(ctx)
 	}
 
+	// Request scoped Go context, it gets cancelled on client disconnect or
+	// request timeout
+	if e.a.settings.RequestTimeout > 0 {
+		var goCtx context.Context
+		goCtx, ctx.goCancel = context.WithTimeout(r.Context(), e.a.settings.RequestTimeout)
+		r = r.WithContext(goCtx)
+	}
+
 	ctx.Req, ctx.Res = ahttp.AcquireRequest(r), ahttp.AcquireResponseWriter(w)
 
 	// Recovery handling
@@ -89,14 +99,35 @@ func (e *HTTPEngine) Handle(w http.ResponseWriter, r *http.Request) {
 		ctx.setRequestID()
 	}
 
+	// Resolve tenant of the request
+	if !e.resolveTenant(ctx) {
+		e.writeReply(ctx)
+		return
+	}
+
+	// Rewrite and redirect rules
+	if e.doRewrite(ctx) {
+		e.writeReply(ctx)
+		return
+	}
+
 	// Load session from request if its `stateful` and subject authentication info.
+	// Guest session is created upon `ctx.Session()` if `security.guest.lazy_session` is enabled.
+	guest := ctx.a.SecurityManager().Guest
 	if ctx.a.SessionManager().IsStateful() {
 		ctx.Subject().Session = ctx.a.SessionManager().GetSession(ctx.Req.Unwrap())
-		if ctx.Session().IsKeyExists(KeyViewArgAuthcInfo) {
-			populateAuthenticationInfo(ctx.Session().Get(KeyViewArgAuthcInfo).(*authc.AuthenticationInfo), ctx)
+		if ctx.subject.Session != nil || guest == nil || !guest.LazySession {
+			if ctx.Session().IsKeyExists(KeyViewArgAuthcInfo) {
+				populateAuthenticationInfo(ctx.Session().Get(KeyViewArgAuthcInfo).(*authc.AuthenticationInfo), ctx)
+			}
 		}
 	}
 
+	// Populate guest principal for unauthenticated request
+	if guest != nil && !ctx.Subject().IsAuthenticated() {
+		guest.Populate(ctx.Subject())
+	}
+
 	// 'OnRequest' HTTP engine event
 	e.publishOnRequestEvent(ctx)
 
@@ -111,6 +142,7 @@ func (e *HTTPEngine) Handle(w http.ResponseWriter, r *http.Request) {
 		ctx.Reply().InternalServerError().Error(newError(ErrGeneric, http.StatusInternalServerError))
 	} else {
 		e.mwChain[0].Next(ctx)
+		ctx.recordShortCircuit()
 	}
 
 	e.writeReply(ctx)
@@ -121,6 +153,31 @@ func (e *HTTPEngine) Log() log.Loggerer {
 	return e.a.logger
 }
 
+// NewContext method creates a new aah context for the given response writer
+// and request; domain and route are resolved from request host and path if
+// exists. It is mainly for testing controllers without running the server,
+// see package `aahtest`. Created context is not pooled.
+func (e *HTTPEngine) NewContext(w http.ResponseWriter, r *http.Request) *Context {
+	ctx := e.newContext()
+	ctx.Req, ctx.Res = ahttp.AcquireRequest(r), ahttp.AcquireResponseWriter(w)
+	if ctx.domain = e.a.Router().Lookup(ctx.Req.Host); ctx.domain != nil {
+		route, urlParams, _ := ctx.domain.Lookup(r)
+		if route != nil {
+			ctx.route, ctx.Req.URLParams = route, urlParams
+			if ctx.controller = e.registry.Lookup(route.Target); ctx.controller != nil {
+				ctx.action = ctx.controller.Lookup(route.Action)
+			}
+		}
+	}
+	return ctx
+}
+
+// WriteReply method writes the context reply on the response writer. It is
+// mainly used along with `HTTPEngine.NewContext`, see package `aahtest`.
+func (e *HTTPEngine) WriteReply(ctx *Context) {
+	e.writeReply(ctx)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // HTTP Engine - Server Extensions
 //______________________________________________________________________________
@@ -279,6 +336,7 @@ func (e *HTTPEngine) handleRecovery(ctx *Context) {
 
 		st.Print(buf)
 		ctx.Log().Error(buf.String())
+		e.a.reportPanic(ctx, r, st)
 
 		err := ErrPanicRecovery
 		if er, ok := r.(error); ok && er == ErrRenderResponse {
@@ -292,6 +350,7 @@ func (e *HTTPEngine) handleRecovery(ctx *Context) {
 
 // writeReply method writes the response on the wire based on `Reply` instance.
 func (e *HTTPEngine) writeReply(ctx *Context) {
+	ctx.markReplied()
 	re := ctx.Reply()
 	if re.err != nil {
 		e.a.errorMgr.Handle(ctx)
@@ -315,10 +374,17 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 
 	if re.redirect { // handle redirects
 		ctx.Log().Debugf("Redirecting to '%s' with status '%d'", re.path, re.Code)
+		e.writeServerTiming(ctx)
 		http.Redirect(ctx.Res, ctx.Req.Unwrap(), re.path, re.Code)
+		e.a.idempotency.store(ctx)
 		return
 	}
 
+	// Conditional request, refer to `Reply().ETag` and `Reply().LastModified`
+	if re.isNotModified(ctx.Req) {
+		re.NotModified()
+	}
+
 	// Check ContentType and detect it if need be
 	if len(re.ContType) == 0 {
 		if _, ok := re.Rdr.(*binaryRender); !ok {
@@ -334,18 +400,24 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 
 	if bodyAllowedForStatus(re.Code) {
 		if e.a.viewMgr != nil && re.isHTML() {
+			sw := ctx.startTiming(TimingRender)
 			e.a.viewMgr.resolve(ctx)
+			sw.Stop()
 		}
 
 		e.writeOnWire(ctx)
 	} else {
 		ctx.Res.Header().Del(ahttp.HeaderContentType)
+		e.writeServerTiming(ctx)
 		ctx.Res.WriteHeader(re.Code)
 	}
 
 	// 'OnPostReply' HTTP event
 	e.publishOnPostReplyEvent(ctx)
 
+	// Store reply for idempotent retries, refer to `IdempotencyMiddleware`
+	e.a.idempotency.store(ctx)
+
 	// Dump request and response
 	if e.a.settings.DumpLogEnabled {
 		e.a.dumpLog.Dump(ctx)
@@ -354,27 +426,46 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 
 func (e *HTTPEngine) writeOnWire(ctx *Context) {
 	re := ctx.Reply()
-	if _, ok := re.Rdr.(*binaryRender); ok {
+	if re.isStream() {
 		e.writeBinary(ctx)
 		return
 	}
 
 	// Render it
 	if re.Rdr == nil {
+		e.writeServerTiming(ctx)
 		ctx.Res.WriteHeader(re.Code)
 		return
 	}
+	if sr, ok := e.qualifyStreamRender(ctx); ok {
+		e.writeStream(ctx, sr)
+		return
+	}
+
+	sw := ctx.startTiming(TimingRender)
 	re.body = acquireBuffer()
 	if err := re.Rdr.Render(re.body); err != nil {
 		ctx.Log().Error("Response render error: ", err)
 		panic(ErrRenderResponse)
 	}
+	sw.Stop()
+	e.a.idempotency.capture(ctx, re.body.Bytes())
+	e.writeServerTiming(ctx)
+
+	// HEAD request, body is discarded and its length is preserved
+	if ctx.Req.Method == ahttp.MethodHead {
+		ctx.Res.Header().Set(ahttp.HeaderContentLength, strconv.Itoa(re.body.Len()))
+		ctx.Res.WriteHeader(re.Code)
+		return
+	}
 
 	// Check response qualify for Gzip
 	if e.qualifyGzip(ctx) && re.body.Len() > defaultGzipMinSize {
 		ctx.Res = wrapGzipWriter(ctx.Res)
 	}
 
+	sw = ctx.startTiming(TimingWrite)
+	defer sw.Stop()
 	ctx.Res.WriteHeader(re.Code)
 	var w io.Writer = ctx.Res
 
@@ -388,12 +479,8 @@ func (e *HTTPEngine) writeOnWire(ctx *Context) {
 	// currently write error on wire is not propagated to error
 	// since we can't do anything after that.
 	// It could be network error, client is gone, etc.
-	if re.isHTML() {
-		if e.a.IsEnvProfile(settings.DefaultEnvProfile) || !e.minifierExists() {
-			if _, err := re.body.WriteTo(w); err != nil {
-				ctx.Log().Error(err)
-			}
-		} else if err := e.a.viewMgr.minifier(re.ContType, w, re.body); err != nil {
+	if !e.a.IsEnvProfile(settings.DefaultEnvProfile) && e.qualifyMinify(ctx) {
+		if err := e.a.viewMgr.minifier(re.ContType, w, re.body); err != nil {
 			ctx.Log().Error(err)
 		}
 	} else if _, err := re.body.WriteTo(w); err != nil {
@@ -401,6 +488,39 @@ func (e *HTTPEngine) writeOnWire(ctx *Context) {
 	}
 }
 
+// qualifyStreamRender method returns the stream render if reply could be
+// rendered directly on the wire without buffering the full body.
+func (e *HTTPEngine) qualifyStreamRender(ctx *Context) (StreamRender, bool) {
+	sr, ok := ctx.Reply().Rdr.(StreamRender)
+	if !ok || ctx.Req.Method == ahttp.MethodHead ||
+		(e.a.settings.DumpLogEnabled && e.a.dumpLog.logResponseBody) ||
+		(!e.a.IsEnvProfile(settings.DefaultEnvProfile) && e.qualifyMinify(ctx)) {
+		return nil, false
+	}
+	if _, found := ctx.Get(keyIdempotency).(*idempotencyState); found {
+		return nil, false
+	}
+	return sr, true
+}
+
+// writeStream method renders the reply directly on the wire. Response header
+// is written on the first write, so render error before that is replied as
+// internal server error.
+func (e *HTTPEngine) writeStream(ctx *Context, sr StreamRender) {
+	sw := ctx.startTiming(TimingRender)
+	defer sw.Stop()
+	w := &streamWriter{e: e, ctx: ctx}
+	if err := sr.RenderTo(w); err != nil {
+		ctx.Log().Error("Response render error: ", err)
+		if !w.written {
+			panic(ErrRenderResponse)
+		}
+	}
+	if !w.written {
+		w.writeHeader(0)
+	}
+}
+
 func (e *HTTPEngine) writeBinary(ctx *Context) {
 	re := ctx.Reply()
 
@@ -409,6 +529,9 @@ func (e *HTTPEngine) writeBinary(ctx *Context) {
 		ctx.Res = wrapGzipWriter(ctx.Res)
 	}
 
+	e.writeServerTiming(ctx)
+	sw := ctx.startTiming(TimingWrite)
+	defer sw.Stop()
 	ctx.Res.WriteHeader(re.Code)
 
 	// currently write error on wire is not propagated to error
@@ -423,17 +546,67 @@ func (e *HTTPEngine) minifierExists() bool {
 	return e.a.viewMgr != nil && e.a.viewMgr.minifier != nil
 }
 
+// minifyEnabled method returns true if minifier exists and HTML minify is
+// not disabled for the current route.
+func (e *HTTPEngine) minifyEnabled(ctx *Context) bool {
+	if ctx.route != nil && ctx.route.DisableMinify {
+		return false
+	}
+	return e.minifierExists()
+}
+
+// compressEnabled method returns the resolved response compression value of
+// the current route, otherwise domain and then 'render.gzip.enable'.
+func (e *HTTPEngine) compressEnabled(ctx *Context) bool {
+	if ctx.route != nil {
+		return !ctx.route.DisableCompress
+	}
+	if ctx.domain != nil {
+		return ctx.domain.Compress
+	}
+	return e.a.settings.GzipEnabled
+}
+
 func (e *HTTPEngine) qualifyGzip(ctx *Context) bool {
-	return e.a.settings.GzipEnabled && ctx.Req.IsGzipAccepted && ctx.Reply().gzip
+	return ctx.Req.IsGzipAccepted && ctx.Reply().gzip && e.compressEnabled(ctx)
 }
 
 func (e *HTTPEngine) releaseContext(ctx *Context) {
+	var released *contextRelease
+	if e.a.settings.ContextAuditEnabled {
+		released = ctx.newContextRelease()
+	}
 	ahttp.ReleaseResponseWriter(ctx.Res)
 	ahttp.ReleaseRequest(ctx.Req)
 	security.ReleaseSubject(ctx.subject)
 	releaseBuffer(ctx.Reply().Body())
+	if ctx.clientWatch != nil {
+		ctx.clientWatch.stop()
+	}
+	if ctx.goCancel != nil {
+		ctx.goCancel()
+	}
+	for _, cancel := range ctx.goCancels {
+		cancel()
+	}
+	if len(ctx.timings) > 0 {
+		var tags []string
+		if ctx.route != nil {
+			tags = ctx.route.Tags
+		}
+		e.a.timingStats.add(ctx.timings, tags)
+	}
+	if len(ctx.canary) > 0 {
+		e.a.canaryStats.add(ctx.route.Name, ctx.canary, ctx.Res.Status())
+	}
 
 	ctx.reset()
+
+	// Context audit mode, poisoned context is not reused
+	if released != nil {
+		ctx.released = released
+		return
+	}
 	e.ctxPool.Put(ctx)
 }
 
@@ -444,6 +617,17 @@ const (
 
 func (e *HTTPEngine) doRedirect(w http.ResponseWriter, r *http.Request) bool {
 	cfg := e.a.Config()
+	if len(e.a.redirectRules) > 0 {
+		if e.doRedirectRules(w, r) {
+			return true
+		}
+
+		// www/non-www redirect is applied along with rules only if configured
+		if !cfg.IsExists("server.redirect.to") {
+			return false
+		}
+	}
+
 	redirectTo := cfg.StringDefault("server.redirect.to", nonwww)
 	redirectCode := cfg.IntDefault("server.redirect.code", http.StatusMovedPermanently)
 	host := ahttp.Host(r)
@@ -479,3 +663,27 @@ func bodyAllowedForStatus(status int) bool {
 	}
 	return true
 }
+
+// streamWriter writes the response header on the first write, response is
+// gzipped if it qualifies and first chunk is larger than minimum gzip size.
+type streamWriter struct {
+	e       *HTTPEngine
+	ctx     *Context
+	written bool
+}
+
+func (sw *streamWriter) Write(b []byte) (int, error) {
+	if !sw.written {
+		sw.writeHeader(len(b))
+	}
+	return sw.ctx.Res.Write(b)
+}
+
+func (sw *streamWriter) writeHeader(size int) {
+	sw.written = true
+	if sw.e.qualifyGzip(sw.ctx) && size > defaultGzipMinSize {
+		sw.ctx.Res = wrapGzipWriter(sw.ctx.Res)
+	}
+	sw.e.writeServerTiming(sw.ctx)
+	sw.ctx.Res.WriteHeader(sw.ctx.Reply().Code)
+}
@@ -5,14 +5,19 @@
 package aah
 
 import (
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -335,3 +340,123 @@ func newContext(w http.ResponseWriter, r *http.Request) *Context {
 
 	return ctx
 }
+
+func TestHTTPEngineAutoHead(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	w := httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	getBody := w.Body.String()
+	getContentType := w.Header().Get(ahttp.HeaderContentType)
+
+	// auto head disabled
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodHead, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// auto head enabled
+	ts.app.Router().Lookup("localhost:8080").AutoHead = true
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodHead, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Body.String())
+	assert.Equal(t, getContentType, w.Header().Get(ahttp.HeaderContentType))
+	assert.Equal(t, strconv.Itoa(len(getBody)), w.Header().Get(ahttp.HeaderContentLength))
+}
+
+func TestHTTPEngineCompressMinify(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.SetMinifier(func(contentType string, w io.Writer, r io.Reader) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+	req.Header.Set(ahttp.HeaderAcceptEncoding, "gzip")
+	ctx := newContext(httptest.NewRecorder(), req)
+	ctx.a = a
+
+	// no route and domain, fallback to 'render.gzip.enable'
+	assert.Equal(t, a.settings.GzipEnabled, a.he.qualifyGzip(ctx))
+	assert.True(t, a.he.minifyEnabled(ctx))
+
+	ctx.domain = &router.Domain{Compress: false}
+	assert.False(t, a.he.qualifyGzip(ctx))
+
+	ctx.route = &router.Route{}
+	assert.True(t, a.he.qualifyGzip(ctx))
+	assert.True(t, a.he.minifyEnabled(ctx))
+
+	ctx.route = &router.Route{DisableCompress: true, DisableMinify: true}
+	assert.False(t, a.he.qualifyGzip(ctx))
+	assert.False(t, a.he.minifyEnabled(ctx))
+}
+
+func TestHTTPEngineStreamRender(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	newStreamCtx := func(method string, rec *httptest.ResponseRecorder) *Context {
+		req := httptest.NewRequest(method, "http://localhost:8080/", nil)
+		req.Header.Set(ahttp.HeaderAcceptEncoding, "gzip")
+		ctx := newContext(rec, req)
+		ctx.a, ctx.e = a, a.he
+		return ctx
+	}
+
+	// response dump log with body, buffered
+	rec := httptest.NewRecorder()
+	ctx := newStreamCtx(ahttp.MethodGet, rec)
+	ctx.Reply().JSON(Data{"message": "streamed"})
+	_, ok := a.he.qualifyStreamRender(ctx)
+	assert.False(t, ok)
+
+	// small payload, not gzipped
+	a.settings.DumpLogEnabled = false
+	_, ok = a.he.qualifyStreamRender(ctx)
+	assert.True(t, ok)
+	a.he.writeOnWire(ctx)
+	assert.Nil(t, ctx.Reply().Body())
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "", rec.Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, `{"message":"streamed"}`+"\n", rec.Body.String())
+
+	// large payload, gzipped
+	rec = httptest.NewRecorder()
+	ctx = newStreamCtx(ahttp.MethodGet, rec)
+	ctx.Reply().JSON(Data{"message": strings.Repeat("streamed ", 500)})
+	a.he.writeOnWire(ctx)
+	ahttp.ReleaseResponseWriter(ctx.Res)
+	assert.Equal(t, "gzip", rec.Header().Get(ahttp.HeaderContentEncoding))
+	assert.True(t, rec.Body.Len() < 4500)
+
+	// render error before the first write
+	rec = httptest.NewRecorder()
+	ctx = newStreamCtx(ahttp.MethodGet, rec)
+	ctx.Reply().JSON(make(chan int))
+	assert.PanicsWithValue(t, ErrRenderResponse, func() {
+		a.he.writeOnWire(ctx)
+	})
+	assert.False(t, rec.Flushed)
+	assert.Equal(t, 0, rec.Body.Len())
+
+	// HEAD request and non stream render are buffered
+	ctx = newStreamCtx(ahttp.MethodHead, httptest.NewRecorder())
+	ctx.Reply().JSON(Data{"message": "buffered"})
+	_, ok = a.he.qualifyStreamRender(ctx)
+	assert.False(t, ok)
+
+	ctx = newStreamCtx(ahttp.MethodGet, httptest.NewRecorder())
+	ctx.Reply().Render(RenderFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "buffered")
+		return err
+	}))
+	_, ok = a.he.qualifyStreamRender(ctx)
+	assert.False(t, ok)
+}
@@ -0,0 +1,335 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/cache"
+)
+
+const (
+	keyIdempotency             = "_aahIdempotency"
+	idempotencyKeyMaxLen       = 255
+	defaultIdempotencyCacheKey = "idempotency"
+)
+
+func init() {
+	gob.Register(&IdempotentReply{})
+}
+
+// IdempotentReply holds the first reply of the request made with
+// idempotency key, stored in the cache configured at
+// `request.idempotency.cache`. Subsequent retries with same key are
+// replied from it.
+type IdempotentReply struct {
+	RequestHash string
+	Completed   bool
+	Code        int
+	ContentType string
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Idempotency middleware
+//______________________________________________________________________________
+
+// IdempotencyMiddleware method makes the route safe to retry for the client
+// by honoring the request header `Idempotency-Key`. It is opt-in, applied
+// only to the routes which has `idempotent = true` in the routes.conf.
+//
+// The first reply of the request (status code, headers and body) is stored
+// in the cache for `request.idempotency.ttl` duration and replayed for the
+// retries with header `Idempotent-Replayed: true`. Key is scoped to route
+// and authenticated subject.
+//
+//    - Retry while the first request is in progress gets 409 Conflict
+//
+//    - Key reused with different request payload gets 422 Unprocessable Entity
+//
+//    - 5xx and streamed replies are not stored, so the client can retry
+//
+// Add it after the `AuthcAuthzMiddleware`.
+//
+// 	aah.App().HTTPEngine().Middlewares(
+// 		aah.RouteMiddleware,
+// 		aah.CORSMiddleware,
+// 		aah.BindMiddleware,
+// 		aah.AntiCSRFMiddleware,
+// 		aah.AuthcAuthzMiddleware,
+// 		aah.IdempotencyMiddleware,
+// 		aah.ActionMiddleware,
+// 	)
+func IdempotencyMiddleware(ctx *Context, m *Middleware) {
+	im := ctx.a.idempotency
+	if !im.isApplicable(ctx) {
+		m.Next(ctx)
+		return
+	}
+
+	key := strings.TrimSpace(ctx.Req.Header.Get(im.header))
+	if len(key) == 0 {
+		m.Next(ctx)
+		return
+	}
+	if len(key) > idempotencyKeyMaxLen {
+		ctx.Log().Warnf("Idempotency: '%s' value exceeds %d characters", im.header, idempotencyKeyMaxLen)
+		ctx.Reply().BadRequest().Error(newError(ErrInvalidRequestParameter, http.StatusBadRequest))
+		return
+	}
+
+	c := im.cache(ctx)
+	if c == nil {
+		m.Next(ctx)
+		return
+	}
+
+	hash, err := im.requestHash(ctx)
+	if err != nil {
+		ctx.Log().Errorf("Idempotency: unable to read request body: %s", err)
+		ctx.Reply().BadRequest().Error(newError(ErrInvalidRequestParameter, http.StatusBadRequest))
+		return
+	}
+
+	cacheKey := im.key(ctx, key)
+	if ir, ok := c.Get(cacheKey).(*IdempotentReply); ok {
+		im.replay(ctx, ir, hash)
+		return
+	}
+
+	// Lock the key till the reply gets stored
+	if err = c.Put(cacheKey, &IdempotentReply{RequestHash: hash, CreatedAt: time.Now()}, im.lockTimeout); err != nil {
+		if err == cache.ErrEntryExists {
+			im.inProgress(ctx)
+			return
+		}
+		ctx.Log().Errorf("Idempotency: %s", err)
+		m.Next(ctx)
+		return
+	}
+
+	ctx.Set(keyIdempotency, &idempotencyState{key: cacheKey, hash: hash})
+	m.Next(ctx)
+
+	// Reply written directly on the wire by the action, refer to `Reply().Done()`
+	if ctx.Reply().done {
+		im.release(ctx)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type idempotencyManager struct {
+	a           *Application
+	header      string
+	cacheName   string
+	ttl         time.Duration
+	lockTimeout time.Duration
+	methods     map[string]bool
+}
+
+type idempotencyState struct {
+	key      string
+	hash     string
+	body     []byte
+	captured bool
+}
+
+func (a *Application) initIdempotency() error {
+	cfg := a.Config()
+	keyPrefix := "request.idempotency"
+
+	im := &idempotencyManager{
+		a:         a,
+		header:    http.CanonicalHeaderKey(cfg.StringDefault(keyPrefix+".header", ahttp.HeaderIdempotencyKey)),
+		cacheName: cfg.StringDefault(keyPrefix+".cache", defaultIdempotencyCacheKey),
+		methods:   make(map[string]bool),
+	}
+	var err error
+	for key, d := range map[string]struct {
+		dst *time.Duration
+		def string
+	}{
+		"ttl":          {&im.ttl, "24h"},
+		"lock_timeout": {&im.lockTimeout, "1m"},
+	} {
+		if *d.dst, err = time.ParseDuration(cfg.StringDefault(keyPrefix+"."+key, d.def)); err != nil {
+			return fmt.Errorf("aah: '%s.%s' value is not a valid time unit", keyPrefix, key)
+		}
+	}
+
+	methods, found := cfg.StringList(keyPrefix + ".methods")
+	if !found {
+		methods = []string{ahttp.MethodPost, ahttp.MethodPatch}
+	}
+	for _, m := range methods {
+		im.methods[strings.ToUpper(strings.TrimSpace(m))] = true
+	}
+
+	a.idempotency = im
+	return nil
+}
+
+// isApplicable method returns true if the route is marked as `idempotent`
+// and request method is configured at `request.idempotency.methods`.
+func (im *idempotencyManager) isApplicable(ctx *Context) bool {
+	if im == nil || ctx.route == nil || !ctx.route.Idempotent {
+		return false
+	}
+	return im.methods[ctx.Req.Method]
+}
+
+// requestHash method returns the SHA-256 hash of request method, URI and
+// body. Request body is restored for the further processing.
+func (im *idempotencyManager) requestHash(ctx *Context) (string, error) {
+	h := sha256.New()
+	_, _ = h.Write([]byte(ctx.Req.Method + " " + ctx.Req.URL().RequestURI() + "\n"))
+
+	r := ctx.Req.Unwrap()
+	if r.PostForm != nil { // already parsed by `BindMiddleware`
+		_, _ = h.Write([]byte(r.PostForm.Encode()))
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		_ = r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(b))
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// replay method writes the stored reply for the retry request.
+func (im *idempotencyManager) replay(ctx *Context, ir *IdempotentReply, hash string) {
+	if !ir.Completed {
+		im.inProgress(ctx)
+		return
+	}
+	if ir.RequestHash != hash {
+		ctx.Log().Warnf("Idempotency: key reused with different request on route '%s'", ctx.route.Name)
+		ctx.Reply().Status(http.StatusUnprocessableEntity).
+			Error(newError(ErrIdempotencyKeyMismatch, http.StatusUnprocessableEntity))
+		return
+	}
+
+	ctx.Log().Debugf("Idempotency: replaying stored reply of route '%s'", ctx.route.Name)
+	for k, v := range ir.Header {
+		ctx.Res.Header()[k] = append([]string(nil), v...)
+	}
+	ctx.Res.Header().Set(ahttp.HeaderIdempotentReplayed, "true")
+	re := ctx.Reply().Status(ir.Code).ContentType(ir.ContentType)
+	if len(ir.Body) > 0 {
+		re.Binary(ir.Body)
+	}
+}
+
+func (im *idempotencyManager) inProgress(ctx *Context) {
+	ctx.Log().Warnf("Idempotency: request with same key is in progress on route '%s'", ctx.route.Name)
+	ctx.Reply().Conflict().Error(newError(ErrIdempotencyKeyInProgress, http.StatusConflict))
+}
+
+// capture method keeps the copy of rendered reply body, since body buffer
+// gets drained while writing on the wire.
+func (im *idempotencyManager) capture(ctx *Context, b []byte) {
+	if im == nil {
+		return
+	}
+	if st, ok := ctx.Get(keyIdempotency).(*idempotencyState); ok {
+		st.body = append([]byte(nil), b...)
+		st.captured = true
+	}
+}
+
+// store method stores the written reply into cache for `request.idempotency.ttl`
+// duration. Server errors and streamed replies are not stored, lock gets
+// released instead.
+func (im *idempotencyManager) store(ctx *Context) {
+	if im == nil {
+		return
+	}
+	st, ok := ctx.Get(keyIdempotency).(*idempotencyState)
+	if !ok {
+		return
+	}
+
+	re := ctx.Reply()
+	if re.Code >= http.StatusInternalServerError || (re.Rdr != nil && !re.redirect &&
+		bodyAllowedForStatus(re.Code) && !st.captured) {
+		im.release(ctx)
+		return
+	}
+
+	ir := &IdempotentReply{
+		RequestHash: st.hash,
+		Completed:   true,
+		Code:        re.Code,
+		ContentType: re.ContType,
+		Header:      make(http.Header),
+		Body:        st.body,
+		CreatedAt:   time.Now(),
+	}
+	for k, v := range ctx.Res.Header() {
+		switch k {
+		case ahttp.HeaderContentEncoding, ahttp.HeaderContentLength, ahttp.HeaderContentType,
+			ahttp.HeaderSetCookie, ahttp.HeaderDate, ahttp.HeaderIdempotentReplayed,
+			ctx.a.settings.RequestIDHeaderKey:
+			continue
+		}
+		ir.Header[k] = append([]string(nil), v...)
+	}
+
+	if c := im.cache(ctx); c != nil {
+		_ = c.Delete(st.key)
+		if err := c.Put(st.key, ir, im.ttl); err != nil {
+			ctx.Log().Error(err)
+		}
+	}
+}
+
+// release method removes the in-progress lock of idempotency key.
+func (im *idempotencyManager) release(ctx *Context) {
+	st, ok := ctx.Get(keyIdempotency).(*idempotencyState)
+	if !ok {
+		return
+	}
+	if c := im.cache(ctx); c != nil {
+		_ = c.Delete(st.key)
+	}
+	ctx.Set(keyIdempotency, nil)
+}
+
+func (im *idempotencyManager) cache(ctx *Context) cache.Cache {
+	c := im.a.CacheManager().Cache(im.cacheName)
+	if c == nil {
+		ctx.Log().Errorf("Idempotency: cache '%s' not exists", im.cacheName)
+	}
+	return c
+}
+
+func (im *idempotencyManager) key(ctx *Context, key string) string {
+	var principal string
+	if ctx.subject != nil && ctx.subject.IsAuthenticated() {
+		if p := ctx.subject.PrimaryPrincipal(); p != nil {
+			principal = p.Value
+		}
+	}
+	return "aah_idempotency_" + ctx.route.Name + "_" + strconv.Quote(principal) + "_" + strconv.Quote(key)
+}
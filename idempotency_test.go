@@ -0,0 +1,97 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Idempotency]: %s", ts.URL)
+
+	a := ts.app
+	assert.NotNil(t, a.idempotency)
+	assert.Equal(t, "Idempotency-Key", a.idempotency.header)
+	assert.Equal(t, 24*time.Hour, a.idempotency.ttl)
+	assert.True(t, a.Router().Lookup("localhost:8080").LookupByName("create_record").Idempotent)
+	assert.False(t, a.Router().Lookup("localhost:8080").LookupByName("form_submit").Idempotent)
+
+	tc := &testThrottleCache{entries: make(map[string]interface{})}
+	_ = a.CacheManager().AddProvider("idempotency_test", &testThrottleCacheProvider{c: tc})
+	assert.Nil(t, a.CacheManager().CreateCache(&cache.Config{Name: "idempotency", ProviderName: "idempotency_test"}))
+
+	secret := a.SecurityManager().AntiCSRF.GenerateSecret()
+	secretstr := a.SecurityManager().AntiCSRF.SaltCipherSecret(secret)
+	wt := httptest.NewRecorder()
+	assert.Nil(t, a.SecurityManager().AntiCSRF.SetCookie(wt, secret))
+	cookieValue := wt.Header().Get(ahttp.HeaderSetCookie)
+	newReq := func(key, body string) *http.Request {
+		req, err := http.NewRequest(ahttp.MethodPost, ts.URL+"/create-record", strings.NewReader(body))
+		assert.Nil(t, err)
+		req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+		req.Header.Set("X-Anti-CSRF-Token", secretstr)
+		req.Header.Set(ahttp.HeaderCookie, cookieValue)
+		if len(key) > 0 {
+			req.Header.Set(ahttp.HeaderIdempotencyKey, key)
+		}
+		return req
+	}
+
+	// without key
+	result := fireRequest(t, newReq("", `{"first_name":"Jeeva","number":1}`))
+	assert.Equal(t, 200, result.StatusCode)
+	assert.Equal(t, 0, len(tc.entries))
+
+	// first request gets stored
+	result = fireRequest(t, newReq("order-1001", `{"first_name":"Jeeva","number":1}`))
+	assert.Equal(t, 200, result.StatusCode)
+	assert.Equal(t, "", result.Header.Get(ahttp.HeaderIdempotentReplayed))
+	assert.True(t, strings.Contains(result.Body, "JSON Payload recevied successfully"))
+	assert.Equal(t, 1, len(tc.entries))
+	for _, v := range tc.entries {
+		ir := v.(*IdempotentReply)
+		assert.True(t, ir.Completed)
+		assert.Equal(t, 200, ir.Code)
+		assert.Equal(t, result.Body, string(ir.Body))
+		assert.Equal(t, "", ir.Header.Get(ahttp.HeaderXRequestID))
+	}
+
+	// retry gets replayed
+	retry := fireRequest(t, newReq("order-1001", `{"first_name":"Jeeva","number":1}`))
+	assert.Equal(t, 200, retry.StatusCode)
+	assert.Equal(t, "true", retry.Header.Get(ahttp.HeaderIdempotentReplayed))
+	assert.Equal(t, result.Body, retry.Body)
+	assert.Equal(t, result.Header.Get(ahttp.HeaderContentType), retry.Header.Get(ahttp.HeaderContentType))
+
+	// key reused with different payload
+	result = fireRequest(t, newReq("order-1001", `{"first_name":"Jeeva","number":2}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+
+	// request in progress
+	_ = tc.Put(`aah_idempotency_create_record_""_"order-1002"`, &IdempotentReply{RequestHash: "hash"}, time.Minute)
+	result = fireRequest(t, newReq("order-1002", `{"first_name":"Jeeva","number":2}`))
+	assert.Equal(t, http.StatusConflict, result.StatusCode)
+
+	// key too long
+	result = fireRequest(t, newReq(strings.Repeat("k", 256), `{"first_name":"Jeeva","number":1}`))
+	assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+
+	// invalid config
+	a.Config().SetString("request.idempotency.ttl", "1 day")
+	assert.Equal(t, "aah: 'request.idempotency.ttl' value is not a valid time unit", a.initIdempotency().Error())
+}
@@ -0,0 +1,301 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"aahframe.work/ainsp"
+)
+
+// Scope type is used to define the lifetime of provided dependency.
+type Scope uint8
+
+// Dependency scopes
+const (
+	// ScopeSingleton dependency instance is created once for the application.
+	ScopeSingleton Scope = iota
+
+	// ScopeRequest dependency instance is created once per request.
+	ScopeRequest
+)
+
+// ErrDependencyNotFound returned when provider not found for the given type.
+var ErrDependencyNotFound = errors.New("aah: dependency not found")
+
+// injectTag is the controller struct field tag key for dependency injection.
+const injectTag = "inject"
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	appPtrType  = reflect.TypeOf((*Application)(nil))
+	injectScope = map[Scope]string{ScopeSingleton: "singleton", ScopeRequest: "request"}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// Provide method registers the dependency provider into application DI
+// container. Provider is either constructor func or value.
+//
+// Constructor func returns dependency instance and optionally an error, its
+// parameters are resolved from the container. Parameter type `*aah.Context`
+// and `*aah.Application` are supplied by the framework. Default scope is
+// `ScopeSingleton`, value provider is always singleton.
+//
+// 	app.Provide(NewUserRepository)
+// 	app.Provide(func(ctx *aah.Context, repo *UserRepository) *UserService {
+// 	  return &UserService{Repo: repo, Log: ctx.Log()}
+// 	}, aah.ScopeRequest)
+// 	app.Provide(&Settings{PageSize: 20})
+//
+// Dependency is obtained via `ctx.Resolve` or `app.Resolve`.
+func (a *Application) Provide(provider interface{}, scope ...Scope) error {
+	return a.injector.Provide(provider, scope...)
+}
+
+// Resolve method resolves the singleton dependency into given target pointer.
+// For request scope dependency use `ctx.Resolve`.
+//
+// 	var repo *UserRepository
+// 	if err := app.Resolve(&repo); err != nil {
+// 	  return err
+// 	}
+func (a *Application) Resolve(target interface{}) error {
+	return a.injector.Resolve(a, nil, target)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context methods
+//______________________________________________________________________________
+
+// Resolve method resolves the dependency into given target pointer from
+// application DI container. Request scope dependencies are created once per
+// request and reused within the request.
+//
+// 	var svc *UserService
+// 	if err := ctx.Resolve(&svc); err != nil {
+// 	  ctx.Log().Error(err)
+// 	}
+func (ctx *Context) Resolve(target interface{}) error {
+	return ctx.a.injector.Resolve(ctx.a, ctx, target)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// injector
+//______________________________________________________________________________
+
+func newInjector() *injector {
+	return &injector{providers: make(map[reflect.Type]*dependency)}
+}
+
+// injector is a lightweight DI container, dependencies are resolved by type.
+type injector struct {
+	mu        sync.RWMutex
+	providers map[reflect.Type]*dependency
+}
+
+type dependency struct {
+	typ      reflect.Type
+	ctor     reflect.Value
+	params   []reflect.Type
+	hasErr   bool
+	scope    Scope
+	mu       sync.Mutex
+	instance reflect.Value
+}
+
+func (ij *injector) Provide(provider interface{}, scope ...Scope) error {
+	if provider == nil {
+		return errors.New("aah: provider is nil")
+	}
+
+	d := &dependency{scope: ScopeSingleton}
+	if len(scope) > 0 {
+		d.scope = scope[0]
+	}
+
+	pv := reflect.ValueOf(provider)
+	if pt := pv.Type(); pt.Kind() == reflect.Func {
+		if pt.NumOut() == 0 || pt.NumOut() > 2 || (pt.NumOut() == 2 && pt.Out(1) != errorType) {
+			return fmt.Errorf("aah: provider '%v' must return value and optionally an error", pt)
+		}
+		d.typ, d.ctor, d.hasErr = pt.Out(0), pv, pt.NumOut() == 2
+		for i := 0; i < pt.NumIn(); i++ {
+			d.params = append(d.params, pt.In(i))
+		}
+	} else {
+		d.typ, d.instance, d.scope = pt, pv, ScopeSingleton
+	}
+
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	if _, found := ij.providers[d.typ]; found {
+		return fmt.Errorf("aah: provider exists for type '%v'", d.typ)
+	}
+	ij.providers[d.typ] = d
+	return nil
+}
+
+func (ij *injector) Resolve(a *Application, ctx *Context, target interface{}) error {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return fmt.Errorf("aah: resolve target must be non-nil pointer, got '%T'", target)
+	}
+	v, err := ij.resolve(a, ctx, tv.Elem().Type(), nil)
+	if err != nil {
+		return err
+	}
+	tv.Elem().Set(v)
+	return nil
+}
+
+func (ij *injector) resolve(a *Application, ctx *Context, t reflect.Type, stack []reflect.Type) (reflect.Value, error) {
+	switch t {
+	case ctxPtrType:
+		if ctx == nil {
+			return reflect.Value{}, errors.New("aah: '*aah.Context' is only available in request scope")
+		}
+		return reflect.ValueOf(ctx), nil
+	case appPtrType:
+		return reflect.ValueOf(a), nil
+	}
+
+	d := ij.lookup(t)
+	if d == nil {
+		return reflect.Value{}, fmt.Errorf("%v: '%v'", ErrDependencyNotFound, t)
+	}
+	for _, st := range stack {
+		if st == d.typ {
+			return reflect.Value{}, fmt.Errorf("aah: dependency cycle detected '%s'", typesPath(append(stack, d.typ)))
+		}
+	}
+	stack = append(stack, d.typ)
+
+	switch d.scope {
+	case ScopeRequest:
+		if ctx == nil {
+			return reflect.Value{}, fmt.Errorf("aah: '%v' is request scope, use ctx.Resolve", d.typ)
+		}
+		if v, found := ctx.instances[d.typ]; found {
+			return v, nil
+		}
+		v, err := ij.create(a, ctx, d, stack)
+		if err != nil {
+			return v, err
+		}
+		if ctx.instances == nil {
+			ctx.instances = make(map[reflect.Type]reflect.Value)
+		}
+		ctx.instances[d.typ] = v
+		return v, nil
+	default:
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.instance.IsValid() {
+			return d.instance, nil
+		}
+		// singleton dependencies cannot depend on request scope
+		v, err := ij.create(a, nil, d, stack)
+		if err != nil {
+			return v, err
+		}
+		d.instance = v
+		return v, nil
+	}
+}
+
+func (ij *injector) create(a *Application, ctx *Context, d *dependency, stack []reflect.Type) (reflect.Value, error) {
+	out, err := ij.call(a, ctx, d.ctor, stack)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if d.hasErr && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("aah: provider '%v': %v", d.typ, out[1].Interface())
+	}
+	return out[0], nil
+}
+
+// call method invokes the given func with its parameters resolved from
+// container.
+func (ij *injector) call(a *Application, ctx *Context, fn reflect.Value, stack []reflect.Type) ([]reflect.Value, error) {
+	ft := fn.Type()
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		v, err := ij.resolve(a, ctx, ft.In(i), stack)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return fn.Call(args), nil
+}
+
+// construct method creates the target instance via its constructor if
+// exists and then injects the tagged fields from container.
+func (ij *injector) construct(a *Application, ctx *Context, t *ainsp.Target) (reflect.Value, error) {
+	target := reflect.New(t.Type)
+	if t.Constructor != nil {
+		out, err := ij.call(a, ctx, target.Method(t.Constructor.Index), nil)
+		if err != nil {
+			return target, fmt.Errorf("aah: %s.%s: %v", t.FqName, ainsp.ConstructorName, err)
+		}
+		if !out[0].IsNil() {
+			target = out[0]
+		}
+	}
+
+	targetElem := target.Elem()
+	for _, index := range t.InjectIndexes {
+		f := targetElem.FieldByIndex(index)
+		v, err := ij.resolve(a, ctx, f.Type(), nil)
+		if err != nil {
+			return target, fmt.Errorf("aah: %s field: %v", t.FqName, err)
+		}
+		f.Set(v)
+	}
+	return target, nil
+}
+
+// lookup method returns the dependency for exact type match otherwise the
+// only dependency which implements the given interface type.
+func (ij *injector) lookup(t reflect.Type) *dependency {
+	ij.mu.RLock()
+	defer ij.mu.RUnlock()
+	if d, found := ij.providers[t]; found {
+		return d
+	}
+	if t.Kind() != reflect.Interface {
+		return nil
+	}
+	var found *dependency
+	for pt, d := range ij.providers {
+		if pt.Implements(t) {
+			if found != nil {
+				return nil // ambiguous
+			}
+			found = d
+		}
+	}
+	return found
+}
+
+func typesPath(types []reflect.Type) string {
+	var names []string
+	for _, t := range types {
+		names = append(names, t.String())
+	}
+	return strings.Join(names, " -> ")
+}
+
+// String method is Stringer interface.
+func (s Scope) String() string {
+	return injectScope[s]
+}
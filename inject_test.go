@@ -0,0 +1,171 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	testRepo struct{ name string }
+
+	testService struct {
+		repo *testRepo
+		ctx  *Context
+	}
+
+	testNamer interface{ Name() string }
+
+	testCycleA struct{}
+	testCycleB struct{}
+
+	testInjectController struct {
+		*Context
+		Repo  *testRepo `inject:""`
+		Namer testNamer `inject:""`
+		svc   *testService
+	}
+
+	testInjectFailController struct {
+		*Context
+		Count int `inject:""`
+	}
+)
+
+func (c *testInjectController) New(svc *testService) *testInjectController {
+	c.svc = svc
+	return c
+}
+
+func (c *testInjectController) Index() {}
+
+func (c *testInjectFailController) Index() {}
+
+func (r *testRepo) Name() string { return r.name }
+
+func TestInjectProvideResolve(t *testing.T) {
+	a := newApp()
+	repoCnt := 0
+	assert.Nil(t, a.Provide(func(app *Application) (*testRepo, error) {
+		repoCnt++
+		assert.Equal(t, a, app)
+		return &testRepo{name: "users"}, nil
+	}))
+	assert.Nil(t, a.Provide(func(ctx *Context, repo *testRepo) *testService {
+		return &testService{repo: repo, ctx: ctx}
+	}, ScopeRequest))
+	assert.Nil(t, a.Provide(map[string]string{"page_size": "20"}))
+
+	// singleton
+	var repo1, repo2 *testRepo
+	assert.Nil(t, a.Resolve(&repo1))
+	assert.Nil(t, a.Resolve(&repo2))
+	assert.Equal(t, repo1, repo2)
+	assert.Equal(t, 1, repoCnt)
+
+	var m map[string]string
+	assert.Nil(t, a.Resolve(&m))
+	assert.Equal(t, "20", m["page_size"])
+
+	// interface
+	var namer testNamer
+	assert.Nil(t, a.Resolve(&namer))
+	assert.Equal(t, "users", namer.Name())
+
+	// request scope
+	var svc *testService
+	err := a.Resolve(&svc)
+	assert.Equal(t, "aah: '*aah.testService' is request scope, use ctx.Resolve", err.Error())
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.a = a
+	var svc1, svc2 *testService
+	assert.Nil(t, ctx.Resolve(&svc1))
+	assert.Nil(t, ctx.Resolve(&svc2))
+	assert.Equal(t, svc1, svc2)
+	assert.Equal(t, ctx, svc1.ctx)
+	assert.Equal(t, repo1, svc1.repo)
+
+	ctx.reset()
+	ctx.a = a
+	var svc3 *testService
+	assert.Nil(t, ctx.Resolve(&svc3))
+	assert.True(t, svc1 != svc3)
+}
+
+func TestInjectErrors(t *testing.T) {
+	a := newApp()
+	assert.Equal(t, "aah: provider is nil", a.Provide(nil).Error())
+	assert.NotNil(t, a.Provide(func() {}))
+	assert.NotNil(t, a.Provide(func() (*testRepo, string) { return nil, "" }))
+	assert.Nil(t, a.Provide(&testRepo{}))
+	assert.Equal(t, "aah: provider exists for type '*aah.testRepo'", a.Provide(&testRepo{}).Error())
+
+	var s string
+	err := a.Resolve(s)
+	assert.Equal(t, "aah: resolve target must be non-nil pointer, got 'string'", err.Error())
+	err = a.Resolve(&s)
+	assert.Equal(t, "aah: dependency not found: 'string'", err.Error())
+
+	var ctx *Context
+	err = a.Resolve(&ctx)
+	assert.Equal(t, "aah: '*aah.Context' is only available in request scope", err.Error())
+
+	assert.Nil(t, a.Provide(func(b *testCycleB) *testCycleA { return &testCycleA{} }))
+	assert.Nil(t, a.Provide(func(a *testCycleA) *testCycleB { return &testCycleB{} }))
+	var ca *testCycleA
+	err = a.Resolve(&ca)
+	assert.Equal(t, "aah: dependency cycle detected '*aah.testCycleA -> *aah.testCycleB -> *aah.testCycleA'", err.Error())
+
+	assert.Nil(t, a.Provide(func() (int, error) { return 0, errors.New("db down") }))
+	var i int
+	err = a.Resolve(&i)
+	assert.Equal(t, "aah: provider 'int': db down", err.Error())
+
+	assert.Equal(t, "request", ScopeRequest.String())
+}
+
+func TestInjectController(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, a.Provide(&testRepo{name: "users"}))
+	assert.Nil(t, a.Provide(func(ctx *Context, repo *testRepo) *testService {
+		return &testService{repo: repo, ctx: ctx}
+	}, ScopeRequest))
+	a.AddController((*testInjectController)(nil), []*ainsp.Method{{Name: "Index"}})
+	a.AddController((*testInjectFailController)(nil), []*ainsp.Method{{Name: "Index"}})
+
+	target := a.HTTPEngine().registry.LookupByType((*testInjectController)(nil))
+	assert.NotNil(t, target.Constructor)
+	assert.Equal(t, [][]int{{1}, {2}}, target.InjectIndexes)
+
+	ctx := a.HTTPEngine().newContext()
+	ctx.Req = ahttp.AcquireRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.route = &router.Route{Target: target.FqName, Action: "Index"}
+	err := ctx.setTarget(ctx.route)
+	assert.Nil(t, err)
+
+	c := ctx.target.(*testInjectController)
+	assert.Equal(t, ctx, c.Context)
+	assert.Equal(t, "users", c.Repo.Name())
+	assert.Equal(t, "users", c.Namer.Name())
+	assert.Equal(t, ctx, c.svc.ctx)
+	assert.Equal(t, c.Repo, c.svc.repo)
+
+	// dependency not found
+	target = a.HTTPEngine().registry.LookupByType((*testInjectFailController)(nil))
+	ctx = a.HTTPEngine().newContext()
+	ctx.route = &router.Route{Target: target.FqName, Action: "Index"}
+	err = ctx.setTarget(ctx.route)
+	assert.Equal(t, "aah: aahframe.work/testInjectFailController field: aah: dependency not found: 'int'", err.Error())
+	assert.Nil(t, ctx.target)
+}
@@ -36,14 +36,18 @@ type Settings struct {
 	SSLEnabled             bool
 	LetsEncryptEnabled     bool
 	GzipEnabled            bool
+	MinifyEnabled          bool
 	SecureHeadersEnabled   bool
 	AccessLogEnabled       bool
 	StaticAccessLogEnabled bool
 	DumpLogEnabled         bool
+	ServerTimingEnabled    bool
+	ServerTimingHeader     bool
 	Initialized            bool
 	HotReload              bool
 	HotReloadEnabled       bool
 	AuthSchemeExists       bool
+	ContextAuditEnabled    bool
 	Redirect               bool
 	Pid                    int
 	HTTPMaxHdrBytes        int
@@ -62,7 +66,9 @@ type Settings struct {
 	HotReloadSignalStr     string
 	HTTPReadTimeout        time.Duration
 	HTTPWriteTimeout       time.Duration
+	RequestTimeout         time.Duration
 	ShutdownGraceTimeout   time.Duration
+	MinifyTypes            []string
 	Autocert               *autocert.Manager
 
 	cfg *config.Config
@@ -94,6 +100,10 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 		return fmt.Errorf("'server.timeout.write': %s", err)
 	}
 
+	if s.RequestTimeout, err = time.ParseDuration(s.cfg.StringDefault("request.timeout", writeTimeout)); err != nil {
+		return fmt.Errorf("'request.timeout': %s", err)
+	}
+
 	maxHdrBytesStr := s.cfg.StringDefault("server.max_header_bytes", "1mb")
 	if maxHdrBytes, er := ess.StrToBytes(maxHdrBytesStr); er == nil {
 		s.HTTPMaxHdrBytes = int(maxHdrBytes)
@@ -126,7 +136,7 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 	}
 
 	s.Type = s.cfg.StringDefault("type", "")
-	if s.Type != "websocket" {
+	if s.Type != "websocket" && s.Type != "worker" {
 		if _, err = ess.StrToBytes(s.cfg.StringDefault("request.max_body_size", "5mb")); err != nil {
 			return errors.New("'request.max_body_size' value is not a valid size unit")
 		}
@@ -137,9 +147,16 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 		s.RequestIDHeaderKey = s.cfg.StringDefault("request.id.header", ahttp.HeaderXRequestID)
 		s.SecureHeadersEnabled = s.cfg.BoolDefault("security.http_header.enable", true)
 		s.GzipEnabled = s.cfg.BoolDefault("render.gzip.enable", true)
+		s.MinifyEnabled = s.cfg.BoolDefault("render.minify.enable", true)
+		if s.MinifyTypes, _ = s.cfg.StringList("render.minify.types"); len(s.MinifyTypes) == 0 {
+			s.MinifyTypes = []string{ahttp.ContentTypeHTML.Mime}
+		}
 		s.AccessLogEnabled = s.cfg.BoolDefault("server.access_log.enable", false)
 		s.StaticAccessLogEnabled = s.cfg.BoolDefault("server.access_log.static_file", true)
 		s.DumpLogEnabled = s.cfg.BoolDefault("server.dump_log.enable", false)
+		s.ServerTimingEnabled = s.cfg.BoolDefault("server.timing.enable", false)
+		s.ServerTimingHeader = s.ServerTimingEnabled &&
+			s.cfg.BoolDefault("server.timing.header", s.EnvProfile == DefaultEnvProfile)
 		if rd := s.cfg.StringDefault("render.default", ""); len(rd) > 0 {
 			s.DefaultContentType = util.MimeTypeByExtension("some." + rd)
 		}
@@ -152,6 +169,7 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 		}
 	}
 
+	s.ContextAuditEnabled = s.cfg.BoolDefault("runtime.debug.context_audit", false)
 	s.HotReloadEnabled = s.cfg.BoolDefault("runtime.config_hotreload.enable", true)
 	s.HotReloadSignalStr = strings.ToUpper(s.cfg.StringDefault("runtime.config_hotreload.signal", "SIGHUP"))
 
@@ -5,7 +5,6 @@
 package util
 
 import (
-	"html/template"
 	"io"
 	"mime"
 	"net/http"
@@ -100,17 +99,6 @@ func AddQueryString(u, k, v string) string {
 	return u + "&" + k + "=" + v
 }
 
-// SanitizeValue method to sanatizes type `string`, rest we can't do any.
-// It's a user responbility.
-func SanitizeValue(value interface{}) interface{} {
-	switch v := value.(type) {
-	case string:
-		return template.HTMLEscapeString(v)
-	default:
-		return v
-	}
-}
-
 // IsGzipWorthForFile method to decide whether gzipping file content it worth to do by
 // checking file extension. If its worth to do it returns true otherwise false.
 func IsGzipWorthForFile(name string) bool {
@@ -8,8 +8,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -20,6 +22,7 @@ import (
 	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
 	"aahframe.work/log"
+	"aahframe.work/router"
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -39,24 +42,28 @@ const (
 	fmtFlagResponseSize
 	fmtFlagResponseHeader
 	fmtFlagResponseTime
+	fmtFlagClientGone
+	fmtFlagMiddlewareAbort
 	fmtFlagCustom
 )
 
 var (
 	accessLogFmtFlags = map[string]ess.FmtFlag{
-		"clientip":  fmtFlagClientIP,
-		"reqtime":   fmtFlagRequestTime,
-		"requrl":    fmtFlagRequestURL,
-		"reqmethod": fmtFlagRequestMethod,
-		"reqproto":  fmtFlagRequestProto,
-		"reqid":     fmtFlagRequestID,
-		"reqhdr":    fmtFlagRequestHeader,
-		"querystr":  fmtFlagQueryString,
-		"resstatus": fmtFlagResponseStatus,
-		"ressize":   fmtFlagResponseSize,
-		"reshdr":    fmtFlagResponseHeader,
-		"restime":   fmtFlagResponseTime,
-		"custom":    fmtFlagCustom,
+		"clientip":   fmtFlagClientIP,
+		"reqtime":    fmtFlagRequestTime,
+		"requrl":     fmtFlagRequestURL,
+		"reqmethod":  fmtFlagRequestMethod,
+		"reqproto":   fmtFlagRequestProto,
+		"reqid":      fmtFlagRequestID,
+		"reqhdr":     fmtFlagRequestHeader,
+		"querystr":   fmtFlagQueryString,
+		"resstatus":  fmtFlagResponseStatus,
+		"ressize":    fmtFlagResponseSize,
+		"reshdr":     fmtFlagResponseHeader,
+		"restime":    fmtFlagResponseTime,
+		"clientgone": fmtFlagClientGone,
+		"mwabort":    fmtFlagMiddlewareAbort,
+		"custom":     fmtFlagCustom,
 	}
 
 	defaultAccessLogPattern = "%clientip %custom:- %reqtime %reqmethod %requrl %reqproto %resstatus %ressize %restime %reqhdr:referer"
@@ -80,6 +87,9 @@ func (a *Application) initAccessLog() error {
 	}
 
 	cfg.SetString("log.pattern", "%message")
+	if err := a.mergeLogRotateConfig(cfg, "server.access_log.rotate"); err != nil {
+		return err
+	}
 
 	// initialize request access log file
 	aaLog, err := log.New(cfg)
@@ -101,6 +111,13 @@ func (a *Application) initAccessLog() error {
 	}
 	aaLogger.fmtFlags = aaLogFmtFlags
 
+	// parse request access log sampling by rate and route tags
+	if a.Config().IsExists("server.access_log.sampling") {
+		if aaLogger.sampling, err = parseAccessLogSampling(a.Config()); err != nil {
+			return err
+		}
+	}
+
 	// initialize request access log channel
 	aaLogger.logChan = make(chan *accessLog, a.Config().IntDefault("server.access_log.channel_buffer_size", 500))
 
@@ -116,12 +133,23 @@ type accessLogger struct {
 	fmtFlags []ess.FmtFlagPart
 	logChan  chan *accessLog
 	logPool  *sync.Pool
+	sampling *accessLogSampling
+}
+
+// accessLogSampling holds the access log sampling rate, route tag rate
+// takes precedence over the rate.
+type accessLogSampling struct {
+	rate float64
+	tags map[string]float64
 }
 
 func (aal *accessLogger) Log(ctx *Context) {
 	if ctx.IsStaticRoute() && !aal.a.settings.StaticAccessLogEnabled {
 		return
 	}
+	if aal.sampling != nil && !aal.sampling.sampled(ctx.route) {
+		return
+	}
 	al := aal.logPool.Get().(*accessLog)
 	al.StartTime = ctx.Get(reqStartTimeKey).(time.Time)
 
@@ -139,10 +167,63 @@ func (aal *accessLogger) Log(ctx *Context) {
 	al.ResStatus = ctx.Res.Status()
 	al.ResBytes = ctx.Res.BytesWritten()
 	al.ResHdr = ctx.Res.Header()
+	al.ClientGone = ctx.clientGone
+	if ctx.mwAbort != nil {
+		al.MiddlewareAbort = ctx.mwAbort.Middleware
+	}
 
 	aal.logChan <- al
 }
 
+// sampled method reports whether the request is logged per sampling rate,
+// if route has multiple sampled tags the lowest rate is applied.
+func (s *accessLogSampling) sampled(route *router.Route) bool {
+	rate, tagged := s.rate, false
+	if route != nil {
+		for _, t := range route.Tags {
+			if r, found := s.tags[t]; found && (!tagged || r < rate) {
+				rate, tagged = r, true
+			}
+		}
+	}
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// parseAccessLogSampling method parses the access log sampling config
+// `server.access_log.sampling`, rates are in the range of 0 to 1.
+func parseAccessLogSampling(cfg *config.Config) (*accessLogSampling, error) {
+	keyPrefix := "server.access_log.sampling"
+	s := &accessLogSampling{rate: 1, tags: make(map[string]float64)}
+	rate := func(key string, def float64) (float64, error) {
+		r := def
+		if v, found := cfg.Get(key); found {
+			switch rv := v.(type) {
+			case float64:
+				r = rv
+			case int64:
+				r = float64(rv)
+			default:
+				r = -1
+			}
+		}
+		if r < 0 || r > 1 {
+			return 0, fmt.Errorf("aah: '%s' value '%v' is not in range 0 to 1", key, r)
+		}
+		return r, nil
+	}
+
+	var err error
+	if s.rate, err = rate(keyPrefix+".rate", 1); err != nil {
+		return nil, err
+	}
+	for _, tag := range cfg.KeysByPath(keyPrefix + ".tags") {
+		if s.tags[tag], err = rate(keyPrefix+".tags."+tag, 1); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
 func (aal *accessLogger) listenToLogChan() {
 	for al := range aal.logChan {
 		aal.logger.Print(aal.accessLogFormatter(al))
@@ -180,6 +261,18 @@ func (aal *accessLogger) accessLogFormatter(al *accessLog) string {
 			buf.WriteString(al.GetResponseHdr(part.Format))
 		case fmtFlagResponseTime:
 			buf.WriteString(fmt.Sprintf("%.4f", al.ElapsedDuration.Seconds()*1e3))
+		case fmtFlagClientGone:
+			if al.ClientGone {
+				buf.WriteString("client-gone")
+			} else {
+				buf.WriteByte('-')
+			}
+		case fmtFlagMiddlewareAbort:
+			if len(al.MiddlewareAbort) > 0 {
+				buf.WriteString(al.MiddlewareAbort)
+			} else {
+				buf.WriteByte('-')
+			}
 		case fmtFlagCustom:
 			buf.WriteString(part.Format)
 		}
@@ -202,6 +295,8 @@ type accessLog struct {
 	ResStatus       int
 	ResBytes        int
 	ResHdr          http.Header
+	ClientGone      bool
+	MiddlewareAbort string
 }
 
 // FmtRequestTime method returns the formatted request time. There are three
@@ -244,6 +339,8 @@ func (al *accessLog) Reset() {
 	al.ResStatus = 0
 	al.ResBytes = 0
 	al.ResHdr = nil
+	al.ClientGone = false
+	al.MiddlewareAbort = ""
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -253,6 +350,11 @@ func (al *accessLog) Reset() {
 const (
 	keyAahRequestBodyBuf  = "_aahRequestBodyBuf"
 	keyAahResponseBodyBuf = "_aahResponseBodyBuf"
+
+	dumpTag        = "dump"
+	dumpTagRedact  = "redact"
+	redactMask     = "*****"
+	redactMaxDepth = 10
 )
 
 func (a *Application) initDumpLog() error {
@@ -272,6 +374,9 @@ func (a *Application) initDumpLog() error {
 	}
 
 	cfg.SetString("log.pattern", "%message")
+	if err := a.mergeLogRotateConfig(cfg, "server.dump_log.rotate"); err != nil {
+		return err
+	}
 
 	adLog, err := log.New(cfg)
 	if err != nil {
@@ -288,6 +393,18 @@ func (a *Application) initDumpLog() error {
 	return nil
 }
 
+// mergeLogRotateConfig method merges the given rotate config section into
+// log file config. Falls back to application `log.rotate` config.
+func (a *Application) mergeLogRotateConfig(cfg *config.Config, key string) error {
+	rcfg, found := a.Config().GetSubConfig(key)
+	if !found {
+		if rcfg, found = a.Config().GetSubConfig("log.rotate"); !found {
+			return nil
+		}
+	}
+	return cfg.Merge2Section("log.rotate", rcfg)
+}
+
 type dumpLogger struct {
 	a               *Application
 	logger          *log.Logger
@@ -312,7 +429,7 @@ func (d *dumpLogger) Dump(ctx *Context) {
 	buf.WriteString(d.composeHeaders(ctx.Req.Header) + "\n")
 	if d.logRequestBody {
 		buf.WriteString("BODY:\n")
-		d.writeBody(keyAahRequestBodyBuf, ctx.Req.ContentType().Mime, buf, ctx)
+		d.writeBody(keyAahRequestBodyBuf, ctx.Req.ContentType().Mime, d.requestType(ctx), buf, ctx)
 	}
 
 	buf.WriteString("\n\n-----------------------------------------------------------------------\n\n")
@@ -320,11 +437,14 @@ func (d *dumpLogger) Dump(ctx *Context) {
 	// Response
 	buf.WriteString(fmt.Sprintf("STATUS: %d %s\n", ctx.Res.Status(), http.StatusText(ctx.Res.Status())))
 	buf.WriteString(fmt.Sprintf("BYTES WRITTEN: %d\n", ctx.Res.BytesWritten()))
+	if ctx.mwAbort != nil {
+		buf.WriteString(fmt.Sprintf("MIDDLEWARE ABORT: %s\n", ctx.mwAbort))
+	}
 	buf.WriteString("HEADERS:\n")
 	buf.WriteString(d.composeHeaders(ctx.Res.Header()) + "\n")
 	if d.logResponseBody {
 		buf.WriteString("BODY:\n")
-		d.writeBody(keyAahResponseBodyBuf, ctx.Reply().ContType, buf, ctx)
+		d.writeBody(keyAahResponseBodyBuf, ctx.Reply().ContType, d.responseType(ctx), buf, ctx)
 	}
 
 	buf.WriteString("\n\n=======================================================================")
@@ -332,7 +452,7 @@ func (d *dumpLogger) Dump(ctx *Context) {
 	d.logger.Print(buf.String())
 }
 
-func (d *dumpLogger) writeBody(key, ct string, w *bytes.Buffer, ctx *Context) {
+func (d *dumpLogger) writeBody(key, ct string, t reflect.Type, w *bytes.Buffer, ctx *Context) {
 	cbuf := ctx.Get(key)
 	if cbuf == nil {
 		w.WriteString("    ***** NO CONTENT *****")
@@ -345,7 +465,7 @@ func (d *dumpLogger) writeBody(key, ct string, w *bytes.Buffer, ctx *Context) {
 		ahttp.ContentTypeMultipartForm.Mime, ahttp.ContentTypePlainText.Mime:
 		_, _ = b.WriteTo(w)
 	case ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeJSONText.Mime:
-		_ = json.Indent(w, b.Bytes(), "", "    ")
+		_ = json.Indent(w, redactJSON(b.Bytes(), t), "", "    ")
 	case ahttp.ContentTypeXML.Mime, ahttp.ContentTypeXMLText.Mime:
 		// TODO XML formatting
 		_, _ = b.WriteTo(w)
@@ -353,6 +473,40 @@ func (d *dumpLogger) writeBody(key, ct string, w *bytes.Buffer, ctx *Context) {
 	releaseBuffer(b)
 }
 
+// requestType method returns the request body type of the action, registered
+// via `AddRequestType` or action struct parameter.
+func (d *dumpLogger) requestType(ctx *Context) reflect.Type {
+	if ctx.action == nil {
+		return nil
+	}
+	if ctx.action.Request != nil {
+		return ctx.action.Request
+	}
+	for _, p := range ctx.action.Parameters {
+		if _, found := ctx.a.contextualBinder(p.Type); found {
+			continue
+		}
+		if derefType(p.Type).Kind() == reflect.Struct {
+			return p.Type
+		}
+	}
+	return nil
+}
+
+// responseType method returns the response body type, from typed reply or
+// registered via `AddResponseType`.
+func (d *dumpLogger) responseType(ctx *Context) reflect.Type {
+	if t := ctx.Reply().dataType; t != nil {
+		return t
+	}
+	if ctx.action != nil {
+		if res := ctx.action.Response(ctx.Res.Status()); res != nil {
+			return res.Type
+		}
+	}
+	return nil
+}
+
 func (d *dumpLogger) composeHeaders(hdrs http.Header) string {
 	var str []string
 	for _, k := range sortHeaderKeys(hdrs) {
@@ -361,6 +515,91 @@ func (d *dumpLogger) composeHeaders(hdrs http.Header) string {
 	return strings.Join(str, "\n")
 }
 
+// redactJSON method masks the JSON values of struct fields tagged with
+// `dump:"redact"` in the given type. For e.g.:
+//
+//	Password string `json:"password" dump:"redact"`
+func redactJSON(b []byte, t reflect.Type) []byte {
+	if t == nil || !hasRedactField(derefType(t), 0) {
+		return b
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return b
+	}
+	if rb, err := json.Marshal(redactValue(v, t, 0)); err == nil {
+		return rb
+	}
+	return b
+}
+
+func redactValue(v interface{}, t reflect.Type, depth int) interface{} {
+	t = derefType(t)
+	if t == nil || depth > redactMaxDepth {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := v.(map[string]interface{}); ok {
+			redactStruct(m, t, depth)
+		}
+	case reflect.Slice, reflect.Array:
+		if l, ok := v.([]interface{}); ok {
+			for i := range l {
+				l[i] = redactValue(l[i], t.Elem(), depth+1)
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				m[k] = redactValue(m[k], t.Elem(), depth+1)
+			}
+		}
+	}
+	return v
+}
+
+func redactStruct(m map[string]interface{}, t reflect.Type, depth int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+		ft := derefType(f.Type)
+		if f.Anonymous && ft.Kind() == reflect.Struct && len(f.Tag.Get("json")) == 0 {
+			redactStruct(m, ft, depth)
+			continue
+		}
+		name := jsonFieldName(f)
+		if _, found := m[name]; !found {
+			continue
+		}
+		if f.Tag.Get(dumpTag) == dumpTagRedact {
+			m[name] = redactMask
+			continue
+		}
+		m[name] = redactValue(m[name], f.Type, depth+1)
+	}
+}
+
+func hasRedactField(t reflect.Type, depth int) bool {
+	if t == nil || depth > redactMaxDepth {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasRedactField(derefType(t.Elem()), depth+1)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get(dumpTag) == dumpTagRedact || hasRedactField(derefType(f.Type), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func sortHeaderKeys(hdrs http.Header) []string {
 	var keys []string
 	for key := range hdrs {
@@ -369,3 +608,89 @@ func sortHeaderKeys(hdrs http.Header) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Module Logger Definitions
+//______________________________________________________________________________
+
+const (
+	keyLogLevels = "log.levels"
+	logModuleApp = "app"
+)
+
+// ModuleLog method returns the logger of given module configured in the
+// section `log.levels { ... }`, for e.g.: `router`, `security`, `ws`,
+// `cache`, `static`. Module logger logs with field `module` at its own
+// level. It returns app logger if module is not configured.
+//
+// 	log {
+// 	  level = "info"
+// 	  levels {
+// 	    router = "warn"
+// 	    security = "debug"
+// 	  }
+// 	}
+func (a *Application) ModuleLog(name string) log.Loggerer {
+	if ml, found := a.moduleLoggers[name]; found {
+		return ml
+	}
+	return a.Log()
+}
+
+// initModuleLoggers method creates the child loggers for the modules
+// configured in the section `log.levels`, module `app` sets the level of
+// app logger.
+func (a *Application) initModuleLoggers(al *log.Logger) error {
+	a.moduleLoggers = make(map[string]*log.Logger)
+	cfg := a.Config()
+	for _, name := range cfg.KeysByPath(keyLogLevels) {
+		lvl, found := cfg.String(keyLogLevels + "." + name)
+		if !found {
+			return fmt.Errorf("log: '%s.%s' value is not a string", keyLogLevels, name)
+		}
+		if name == logModuleApp {
+			if err := al.SetLevel(lvl); err != nil {
+				return err
+			}
+			continue
+		}
+		ml := al.New(log.Fields{"module": name})
+		if err := ml.SetLevel(lvl); err != nil {
+			return fmt.Errorf("%s, module '%s'", err, name)
+		}
+		a.moduleLoggers[name] = ml
+	}
+	return nil
+}
+
+// moduleLog method returns the request logger of given module, it's module
+// logger with request ID and principal fields if module is configured in
+// the section `log.levels` otherwise `ctx.Log()`.
+func (ctx *Context) moduleLog(name string) log.Loggerer {
+	ml, found := ctx.a.moduleLoggers[name]
+	if !found {
+		return ctx.Log()
+	}
+	fields := make(log.Fields)
+	if h := ctx.Req.Header[ctx.a.settings.RequestIDHeaderKey]; len(h) > 0 {
+		fields["reqid"] = h[0]
+	}
+	if ctx.subject != nil && ctx.subject.IsAuthenticated() {
+		if p := ctx.subject.PrimaryPrincipal(); p != nil {
+			fields["principal"] = p.Value
+		}
+	}
+	return ml.WithFields(fields)
+}
+
+// moduleApp type is used to supply module logger to the framework
+// packages those uses aah application `Log()` method, for e.g.: router, ws.
+type moduleApp struct {
+	*Application
+	name string
+}
+
+// Log method returns the module logger.
+func (m *moduleApp) Log() log.Loggerer {
+	return m.ModuleLog(m.name)
+}
@@ -140,7 +140,7 @@ func WithField(key string, value interface{}) Loggerer {
 
 // Writer method returns the writer of default logger.
 func Writer() io.Writer {
-	return dl.receiver.Writer()
+	return dl.writer()
 }
 
 // SetWriter method sets the given writer into logger instance.
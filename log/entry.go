@@ -68,70 +68,70 @@ func (e *Entry) MarshalJSON() ([]byte, error) {
 
 // Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Error(v ...interface{}) {
-	if e.logger.level >= LevelError {
+	if e.logger.currentLevel() >= LevelError {
 		e.output(LevelError, fmt.Sprint(v...))
 	}
 }
 
 // Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Errorf(format string, v ...interface{}) {
-	if e.logger.level >= LevelError {
+	if e.logger.currentLevel() >= LevelError {
 		e.output(LevelError, fmt.Sprintf(format, v...))
 	}
 }
 
 // Warn logs message as `WARN`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Warn(v ...interface{}) {
-	if e.logger.level >= LevelWarn {
+	if e.logger.currentLevel() >= LevelWarn {
 		e.output(LevelWarn, fmt.Sprint(v...))
 	}
 }
 
 // Warnf logs message as `WARN`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Warnf(format string, v ...interface{}) {
-	if e.logger.level >= LevelWarn {
+	if e.logger.currentLevel() >= LevelWarn {
 		e.output(LevelWarn, fmt.Sprintf(format, v...))
 	}
 }
 
 // Info logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Info(v ...interface{}) {
-	if e.logger.level >= LevelInfo {
+	if e.logger.currentLevel() >= LevelInfo {
 		e.output(LevelInfo, fmt.Sprint(v...))
 	}
 }
 
 // Infof logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Infof(format string, v ...interface{}) {
-	if e.logger.level >= LevelInfo {
+	if e.logger.currentLevel() >= LevelInfo {
 		e.output(LevelInfo, fmt.Sprintf(format, v...))
 	}
 }
 
 // Debug logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Debug(v ...interface{}) {
-	if e.logger.level >= LevelDebug {
+	if e.logger.currentLevel() >= LevelDebug {
 		e.output(LevelDebug, fmt.Sprint(v...))
 	}
 }
 
 // Debugf logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Debugf(format string, v ...interface{}) {
-	if e.logger.level >= LevelDebug {
+	if e.logger.currentLevel() >= LevelDebug {
 		e.output(LevelDebug, fmt.Sprintf(format, v...))
 	}
 }
 
 // Trace logs message as `TRACE`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Trace(v ...interface{}) {
-	if e.logger.level >= LevelTrace {
+	if e.logger.currentLevel() >= LevelTrace {
 		e.output(LevelTrace, fmt.Sprint(v...))
 	}
 }
 
 // Tracef logs message as `TRACE`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Tracef(format string, v ...interface{}) {
-	if e.logger.level >= LevelTrace {
+	if e.logger.currentLevel() >= LevelTrace {
 		e.output(LevelTrace, fmt.Sprintf(format, v...))
 	}
 }
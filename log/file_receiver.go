@@ -5,11 +5,14 @@
 package log
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +20,10 @@ import (
 	"aahframe.work/essentials"
 )
 
-const defaultRotatePolicy = "daily"
+const (
+	defaultRotatePolicy = "daily"
+	gzipExt             = ".gz"
+)
 
 var (
 	// backupTimeFormat is used for timestamp with filename on rotation
@@ -41,6 +47,9 @@ type FileReceiver struct {
 	isUTC        bool
 	maxSize      int64
 	maxLines     int64
+	maxBackups   int
+	compress     bool
+	wg           sync.WaitGroup
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -78,13 +87,27 @@ func (f *FileReceiver) Init(cfg *config.Config) error {
 	case "lines":
 		f.maxLines = int64(cfg.IntDefault("log.rotate.lines", 0))
 	case "size":
-		maxSize, err := ess.StrToBytes(cfg.StringDefault("log.rotate.size", "512mb"))
+		maxSize, err := ess.StrToBytes(cfg.StringDefault("log.rotate.size",
+			cfg.StringDefault("log.rotate.max_size", "512mb")))
 		if err != nil {
 			return err
 		}
 		f.maxSize = maxSize
 	}
 
+	// Size limit in addition to daily and lines policy
+	if maxSize, found := cfg.String("log.rotate.max_size"); found && f.maxSize == 0 {
+		size, err := ess.StrToBytes(maxSize)
+		if err != nil {
+			return err
+		}
+		f.maxSize = size
+	}
+
+	// Retention
+	f.maxBackups = cfg.IntDefault("log.rotate.max_backups", 0)
+	f.compress = cfg.BoolDefault("log.rotate.compress", false)
+
 	f.mu = sync.Mutex{}
 
 	return nil
@@ -150,18 +173,29 @@ func (f *FileReceiver) Writer() io.Writer {
 	return f.out
 }
 
+// Close method closes the log file and waits for the in-progress backup
+// file compression.
+func (f *FileReceiver) Close() error {
+	f.mu.Lock()
+	f.close()
+	f.mu.Unlock()
+	f.wg.Wait()
+	return nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // FileReceiver Unexported methods
 //___________________________________
 
 func (f *FileReceiver) isRotate() bool {
+	if f.maxSize != 0 && f.stats.bytes >= f.maxSize {
+		return true
+	}
 	switch f.rotatePolicy {
 	case "daily":
 		return f.openDay != f.getDay()
 	case "lines":
 		return f.maxLines != 0 && f.stats.lines >= f.maxLines
-	case "size":
-		return f.maxSize != 0 && f.stats.bytes >= f.maxSize
 	default:
 		return false
 	}
@@ -170,14 +204,61 @@ func (f *FileReceiver) isRotate() bool {
 func (f *FileReceiver) rotateFile() error {
 	if _, err := os.Lstat(f.filename); err == nil {
 		f.close()
-		if err = os.Rename(f.filename, f.backupFileName()); err != nil {
+		backupName := f.backupFileName()
+		if err = os.Rename(f.filename, backupName); err != nil {
 			return err
 		}
+
+		if f.compress || f.maxBackups > 0 {
+			f.wg.Add(1)
+			go f.processBackups(backupName)
+		}
 	}
 
 	return f.openFile()
 }
 
+// processBackups method compresses the given backup file if enabled and then
+// removes the older backup files beyond `max_backups`.
+func (f *FileReceiver) processBackups(backupName string) {
+	defer f.wg.Done()
+	if f.compress {
+		if err := compressFile(backupName); err != nil {
+			Errorf("log: unable to compress backup file '%s': %v", backupName, err)
+		}
+	}
+
+	if f.maxBackups > 0 {
+		backups := f.backupFiles()
+		for len(backups) > f.maxBackups {
+			_ = os.Remove(backups[0])
+			backups = backups[1:]
+		}
+	}
+}
+
+// backupFiles method returns the backup files of log file in the oldest first
+// order. Backup filename has timestamp so sorting by name gives us the order.
+func (f *FileReceiver) backupFiles() []string {
+	ext := filepath.Ext(f.filename)
+	prefix := ess.StripExt(f.filename) + "-"
+	matches, _ := filepath.Glob(prefix + "*")
+	backups := make([]string, 0, len(matches))
+	for _, m := range matches {
+		name := strings.TrimSuffix(m, gzipExt)
+		if !strings.HasSuffix(name, ext) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], gzipExt) < strings.TrimSuffix(backups[j], gzipExt)
+	})
+	return backups
+}
+
 func (f *FileReceiver) openFile() error {
 	dir := filepath.Dir(f.filename)
 	_ = ess.MkDirAll(dir, filePermission)
@@ -220,6 +301,37 @@ func (f *FileReceiver) backupFileName() string {
 	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", baseName, t.Format(backupTimeFormat), ext))
 }
 
+func compressFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(src)
+
+	dst, err := os.OpenFile(name+gzipExt, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ess.CloseQuietly(dst)
+		if err != nil {
+			_ = os.Remove(name + gzipExt)
+		}
+	}()
+
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err != nil {
+		return err
+	}
+	if err = gw.Close(); err != nil {
+		return err
+	}
+
+	// source file is closed before remove, required on Windows
+	ess.CloseQuietly(src)
+	return os.Remove(name)
+}
+
 func (f *FileReceiver) getDay() int {
 	if f.isUTC {
 		return time.Now().UTC().Day()
@@ -7,7 +7,9 @@ package log
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
 	"github.com/stretchr/testify/assert"
@@ -176,3 +178,65 @@ func testFileLogger(t *testing.T, cfgStr string, loop int) {
 	assert.NotNil(t, logger.ToGoLogger())
 	logger.SetWriter(ioutil.Discard)
 }
+
+func TestFileLoggerRotateRetention(t *testing.T) {
+	defer cleaupFiles("retention-aah-filename*")
+	configStr := `
+  log {
+    receiver = "file"
+    level = "debug"
+    file = "retention-aah-filename.log"
+    rotate {
+      policy = "lines"
+      lines = 10
+      max_backups = 2
+      compress = true
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	receiver := logger.receiver.(*FileReceiver)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 10; j++ {
+			logger.Info("Yes, I would love to see")
+		}
+		// let's make sure backup filename timestamp differs
+		time.Sleep(2 * time.Millisecond)
+		logger.Info("rotate now")
+		receiver.wg.Wait()
+	}
+
+	backups := receiver.backupFiles()
+	assert.Equal(t, 2, len(backups))
+	for _, b := range backups {
+		assert.True(t, strings.HasSuffix(b, ".log.gz"))
+	}
+}
+
+func TestFileLoggerRotateMaxSize(t *testing.T) {
+	defer cleaupFiles("maxsize-aah-filename*")
+	configStr := `
+  log {
+    receiver = "file"
+    level = "debug"
+    file = "maxsize-aah-filename.log"
+    rotate {
+      policy = "daily"
+      max_size = "1kb"
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	receiver := logger.receiver.(*FileReceiver)
+	assert.Equal(t, int64(1024), receiver.maxSize)
+	for i := 0; i < 30; i++ {
+		logger.Info("Yes, I would love to see; it's more than 50 bytes")
+	}
+	assert.True(t, len(receiver.backupFiles()) > 0)
+}
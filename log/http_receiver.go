@@ -0,0 +1,423 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const (
+	httpFmtJSON   = "json"
+	httpFmtNDJSON = "ndjson"
+)
+
+var _ Receiver = (*HTTPReceiver)(nil)
+
+// HTTPReceiver ships the log entries in batches to HTTP endpoint, for e.g.:
+// Loki, OTLP logs gateway, Elasticsearch bulk, etc. Log entries are buffered
+// and delivered on reaching `batch_size` or every `flush_interval` whichever
+// comes first. Failed delivery is retried up to `max_retries` with linear
+// backoff of `retry_wait` without holding up the intake of new log entries.
+// Configuration goes under `log.receivers.http { ... }`.
+//
+// 	log {
+// 	  receiver = "http"
+// 	  receivers {
+// 	    http {
+// 	      url = "https://logs.example.com/ingest"
+// 	      format = "json" # json or ndjson
+// 	      batch_size = 100
+// 	      buffer_size = 1000
+// 	      flush_interval = "5s"
+// 	      max_retries = 3
+// 	      retry_wait = "1s"
+// 	      timeout = "10s"
+// 	      headers {
+// 	        Authorization = "Bearer token"
+// 	      }
+// 	    }
+// 	  }
+// 	}
+type HTTPReceiver struct {
+	url           string
+	format        string
+	headers       map[string]string
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryWait     time.Duration
+	client        *http.Client
+	out           io.Writer
+	flags         []ess.FmtFlagPart
+	isCallerInfo  bool
+	entryChan     chan []byte
+	flushChan     chan chan struct{}
+	stopChan      chan struct{}
+	doneChan      chan struct{}
+	dropped       int64
+	mu            sync.Mutex
+}
+
+type httpDelivery struct {
+	body    []byte
+	count   int
+	attempt int
+	due     time.Time
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTPReceiver methods
+//___________________________________
+
+// Init method initializes the HTTP receiver instance. On re-init, previous
+// delivery goroutine is flushed and stopped.
+func (h *HTTPReceiver) Init(cfg *config.Config) error {
+	_ = h.Close()
+
+	keyPrefix := "log.receivers.http."
+	h.url = cfg.StringDefault(keyPrefix+"url", "")
+	if ess.IsStrEmpty(h.url) {
+		return errors.New("log: http receiver 'url' is required")
+	}
+
+	h.format = cfg.StringDefault(keyPrefix+"format", httpFmtJSON)
+	if !(h.format == httpFmtJSON || h.format == httpFmtNDJSON) {
+		return fmt.Errorf("log: unsupported http receiver format '%s'", h.format)
+	}
+
+	h.headers = make(map[string]string)
+	for _, k := range cfg.KeysByPath(keyPrefix + "headers") {
+		h.headers[k] = cfg.StringDefault(keyPrefix+"headers."+k, "")
+	}
+
+	var err error
+	if h.flushInterval, err = parseDuration(cfg, keyPrefix+"flush_interval", "5s"); err != nil {
+		return err
+	}
+	if h.flushInterval <= 0 {
+		return errors.New("log: http receiver 'flush_interval' must be greater than zero")
+	}
+	if h.retryWait, err = parseDuration(cfg, keyPrefix+"retry_wait", "1s"); err != nil {
+		return err
+	}
+	timeout, err := parseDuration(cfg, keyPrefix+"timeout", "10s")
+	if err != nil {
+		return err
+	}
+
+	h.batchSize = cfg.IntDefault(keyPrefix+"batch_size", 100)
+	if h.batchSize <= 0 {
+		return errors.New("log: http receiver 'batch_size' must be greater than zero")
+	}
+	bufferSize := cfg.IntDefault(keyPrefix+"buffer_size", 1000)
+	if bufferSize < 0 {
+		return errors.New("log: http receiver 'buffer_size' must not be negative")
+	}
+
+	h.maxRetries = cfg.IntDefault(keyPrefix+"max_retries", 3)
+	h.client = &http.Client{Timeout: timeout}
+	h.entryChan = make(chan []byte, bufferSize)
+
+	h.mu.Lock()
+	h.flushChan = make(chan chan struct{})
+	h.stopChan = make(chan struct{})
+	h.doneChan = make(chan struct{})
+	go h.listen(h.flushChan, h.stopChan, h.doneChan)
+	h.mu.Unlock()
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern. HTTP receiver
+// always ships entries in the JSON format, pattern is used to identify
+// caller info.
+func (h *HTTPReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	h.flags = flags
+	h.isCallerInfo = isCallerInfo(h.flags)
+	return nil
+}
+
+// SetWriter method sets the given writer into HTTP receiver, batches gets
+// written into writer instead of HTTP endpoint.
+func (h *HTTPReceiver) SetWriter(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (h *HTTPReceiver) IsCallerInfo() bool {
+	return h.isCallerInfo
+}
+
+// Log method buffers the log entry for delivery. If the buffer is full, log
+// entry is dropped and counted, refer to `Dropped()`.
+func (h *HTTPReceiver) Log(entry *Entry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	select {
+	case h.entryChan <- b:
+	default:
+		atomic.AddInt64(&h.dropped, 1)
+	}
+}
+
+// Writer method returns the HTTP receiver writer, each write is shipped as
+// `INFO` log entry message.
+func (h *HTTPReceiver) Writer() io.Writer {
+	return httpReceiverWriter{h: h}
+}
+
+// Flush method delivers the buffered log entries and waits for its completion,
+// including the retries of failed deliveries.
+func (h *HTTPReceiver) Flush() {
+	h.mu.Lock()
+	flush, stopped := h.flushChan, h.doneChan
+	h.mu.Unlock()
+	if flush == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	select {
+	case flush <- done:
+		<-done
+	case <-stopped:
+	}
+}
+
+// Close method delivers the buffered log entries and stops the delivery
+// goroutine. Failed deliveries are attempted once more before it's dropped.
+func (h *HTTPReceiver) Close() error {
+	h.mu.Lock()
+	stop, stopped := h.stopChan, h.doneChan
+	h.stopChan = nil
+	h.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	<-stopped
+	return nil
+}
+
+// Dropped method returns the count of log entries dropped due to buffer full.
+func (h *HTTPReceiver) Dropped() int64 {
+	return atomic.LoadInt64(&h.dropped)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTPReceiver Unexported methods
+//___________________________________
+
+func (h *HTTPReceiver) listen(flushChan chan chan struct{}, stopChan, doneChan chan struct{}) {
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+	batch := make([][]byte, 0, h.batchSize)
+	var (
+		retries []*httpDelivery
+		waiters []chan struct{}
+		retryC  <-chan time.Time
+		retryAt time.Time
+	)
+	for {
+		select {
+		case b := <-h.entryChan:
+			batch = append(batch, b)
+			if len(batch) >= h.batchSize {
+				retries = h.deliver(h.newDelivery(batch), retries)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				retries = h.deliver(h.newDelivery(batch), retries)
+				batch = batch[:0]
+			}
+		case <-retryC:
+			retryC = nil
+			retries = h.retry(retries, time.Now())
+		case done := <-flushChan:
+			batch = h.drain(batch)
+			if len(batch) > 0 {
+				retries = h.deliver(h.newDelivery(batch), retries)
+				batch = batch[:0]
+			}
+			waiters = append(waiters, done)
+		case <-stopChan:
+			batch = h.drain(batch)
+			if len(batch) > 0 {
+				retries = append(retries, h.newDelivery(batch))
+			}
+			for _, d := range retries {
+				if !h.send(d.body) {
+					atomic.AddInt64(&h.dropped, int64(d.count))
+				}
+			}
+			for _, w := range waiters {
+				close(w)
+			}
+			close(doneChan)
+			return
+		}
+
+		// flush waiters are released once failed deliveries are resolved
+		if len(retries) == 0 {
+			for _, w := range waiters {
+				close(w)
+			}
+			waiters = waiters[:0]
+		} else if due := nextDue(retries); retryC == nil || due.Before(retryAt) {
+			retryC, retryAt = time.After(time.Until(due)), due
+		}
+	}
+}
+
+func (h *HTTPReceiver) drain(batch [][]byte) [][]byte {
+	for n := len(h.entryChan); n > 0; n-- {
+		batch = append(batch, <-h.entryChan)
+	}
+	return batch
+}
+
+func (h *HTTPReceiver) newDelivery(batch [][]byte) *httpDelivery {
+	return &httpDelivery{body: h.encode(batch), count: len(batch)}
+}
+
+// deliver method sends the given delivery, on failure it's added into retries
+// with the next attempt due time otherwise dropped on reaching max retries.
+func (h *HTTPReceiver) deliver(d *httpDelivery, retries []*httpDelivery) []*httpDelivery {
+	if h.send(d.body) {
+		return retries
+	}
+	if d.attempt >= h.maxRetries {
+		atomic.AddInt64(&h.dropped, int64(d.count))
+		return retries
+	}
+	d.attempt++
+	d.due = time.Now().Add(time.Duration(d.attempt) * h.retryWait)
+	return append(retries, d)
+}
+
+func (h *HTTPReceiver) retry(retries []*httpDelivery, now time.Time) []*httpDelivery {
+	var pending []*httpDelivery
+	for _, d := range retries {
+		if d.due.After(now) {
+			pending = append(pending, d)
+			continue
+		}
+		pending = h.deliver(d, pending)
+	}
+	return pending
+}
+
+func (h *HTTPReceiver) send(body []byte) bool {
+	h.mu.Lock()
+	out := h.out
+	h.mu.Unlock()
+	if out != nil {
+		_, _ = out.Write(body)
+		return true
+	}
+	return h.post(body)
+}
+
+func (h *HTTPReceiver) post(body []byte) bool {
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	if h.format == httpFmtNDJSON {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return false
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	ess.CloseQuietly(res.Body)
+
+	// client errors are not retryable except too many requests
+	return res.StatusCode < 300 ||
+		(res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests)
+}
+
+func (h *HTTPReceiver) encode(batch [][]byte) []byte {
+	buf := new(bytes.Buffer)
+	if h.format == httpFmtNDJSON {
+		for _, b := range batch {
+			buf.Write(b)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes()
+	}
+
+	buf.WriteByte('[')
+	for i, b := range batch {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+type httpReceiverWriter struct {
+	h *HTTPReceiver
+}
+
+func (w httpReceiverWriter) Write(p []byte) (int, error) {
+	w.h.Log(&Entry{
+		Level:   LevelInfo,
+		Time:    time.Now(),
+		Message: string(bytes.TrimRight(p, "\n")),
+	})
+	return len(p), nil
+}
+
+func nextDue(retries []*httpDelivery) time.Time {
+	due := retries[0].due
+	for _, d := range retries[1:] {
+		if d.due.Before(due) {
+			due = d.due
+		}
+	}
+	return due
+}
+
+func parseDuration(cfg *config.Config, key, defaultValue string) (time.Duration, error) {
+	d, err := time.ParseDuration(cfg.StringDefault(key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("log: config '%s': %s", key, err)
+	}
+	return d, nil
+}
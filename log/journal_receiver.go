@@ -0,0 +1,185 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const journalDefaultSocket = "/run/systemd/journal/socket"
+
+var (
+	// systemd journal priority values, same as syslog severity
+	levelToJournalPriority = levelToSyslogSeverity
+
+	_ Receiver = (*JournalReceiver)(nil)
+)
+
+// JournalReceiver writes the log entry into systemd-journal using its native
+// protocol. Entry fields are sent as journal fields in the upper case.
+// Configuration goes under `log.receivers.journal { ... }`.
+//
+// 	log {
+// 	  receiver = "journal"
+// 	  receivers {
+// 	    journal {
+// 	      socket = "/run/systemd/journal/socket"
+// 	      identifier = "myapp"
+// 	    }
+// 	  }
+// 	}
+type JournalReceiver struct {
+	socket       string
+	identifier   string
+	conn         net.Conn
+	out          io.Writer
+	flags        []ess.FmtFlagPart
+	isCallerInfo bool
+	mu           sync.Mutex
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JournalReceiver methods
+//___________________________________
+
+// Init method initializes the journal receiver instance.
+func (j *JournalReceiver) Init(cfg *config.Config) error {
+	keyPrefix := "log.receivers.journal."
+	j.socket = cfg.StringDefault(keyPrefix+"socket", journalDefaultSocket)
+	j.identifier = cfg.StringDefault(keyPrefix+"identifier", cfg.StringDefault("name", ""))
+
+	conn, err := net.Dial("unixgram", j.socket)
+	if err != nil {
+		return err
+	}
+
+	// on re-init, previous connection is closed
+	j.mu.Lock()
+	if j.conn != nil {
+		ess.CloseQuietly(j.conn)
+	}
+	j.conn = conn
+	j.out = conn
+	j.mu.Unlock()
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern.
+func (j *JournalReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	j.flags = flags
+	j.isCallerInfo = isCallerInfo(j.flags)
+	return nil
+}
+
+// SetWriter method sets the given writer into journal receiver.
+func (j *JournalReceiver) SetWriter(w io.Writer) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (j *JournalReceiver) IsCallerInfo() bool {
+	return j.isCallerInfo
+}
+
+// Log method writes the log entry into systemd-journal.
+func (j *JournalReceiver) Log(entry *Entry) {
+	buf := new(bytes.Buffer)
+	writeJournalField(buf, "MESSAGE", strings.TrimRight(string(textFormatter(j.flags, entry)), " \n"))
+	writeJournalField(buf, "PRIORITY", strconv.Itoa(levelToJournalPriority[entry.Level]))
+	if len(j.identifier) > 0 {
+		writeJournalField(buf, "SYSLOG_IDENTIFIER", j.identifier)
+	}
+	if len(entry.File) > 0 {
+		writeJournalField(buf, "CODE_FILE", entry.File)
+		writeJournalField(buf, "CODE_LINE", strconv.Itoa(entry.Line))
+	}
+	if len(entry.RequestID) > 0 {
+		writeJournalField(buf, "REQUEST_ID", entry.RequestID)
+	}
+	if len(entry.Principal) > 0 {
+		writeJournalField(buf, "PRINCIPAL", entry.Principal)
+	}
+	for k, v := range entry.Fields {
+		if !entry.isSkipField(k) {
+			writeJournalField(buf, journalFieldName(k), fmt.Sprint(v))
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.out.Write(buf.Bytes())
+}
+
+// Writer method returns the current log writer.
+func (j *JournalReceiver) Writer() io.Writer {
+	return j.out
+}
+
+// Close method closes the systemd-journal socket connection.
+func (j *JournalReceiver) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.conn == nil {
+		return nil
+	}
+	err := j.conn.Close()
+	j.conn = nil
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JournalReceiver Unexported methods
+//___________________________________
+
+// writeJournalField method writes the field in journal native protocol format,
+// value with newline is written in the binary safe format.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if strings.ContainsRune(value, '\n') {
+		buf.WriteByte('\n')
+		_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	} else {
+		buf.WriteByte('=')
+	}
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName method returns the journal compliant field name, it allows
+// only upper case letters, digits and underscores.
+func journalFieldName(name string) string {
+	name = strings.ToUpper(name)
+	b := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			b = append(b, c)
+		} else {
+			b = append(b, '_')
+		}
+	}
+	if len(b) > 0 && (b[0] == '_' || (b[0] >= '0' && b[0] <= '9')) {
+		b = append([]byte("F"), b...)
+	}
+	return string(b)
+}
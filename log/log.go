@@ -3,11 +3,13 @@
 // license that can be found in the LICENSE file.
 
 // Package log simple logger and provides capabilities to fulfill application
-// use cases. It supports two receivers `console` and `file` and extensible
+// use cases. It supports receivers `console`, `file`, `syslog`, `journal`
+// (systemd-journal) and `http` (batched log shipping) and extensible
 // by interface and Hook.
 //
 // Also provides standard logger crossover binding (drop-in replacement
-// for standard go logger) for unified logging.
+// for standard go logger) for unified logging and `log/slog` interop via
+// `SlogHandler` and `SlogReceiver`.
 //
 // 	log.Info("Welcome ", "to ", "aah ", "logger")
 // 	log.Infof("%v, %v, %v", "simple", "flexible", "logger")
@@ -18,6 +20,7 @@
 package log
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +28,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"aahframe.work/config"
 )
@@ -47,6 +51,10 @@ const (
 	LevelUnknown
 )
 
+// levelInherit is the level value of child logger which uses the parent
+// logger level.
+const levelInherit int32 = -1
+
 var (
 	// ErrLogReceiverIsNil returned when suppiled receiver is nil.
 	ErrLogReceiverIsNil = errors.New("log: receiver is nil")
@@ -68,9 +76,10 @@ type (
 	// it guarantees to serialize access to the Receivers.
 	Logger struct {
 		cfg      *config.Config
-		m        *sync.RWMutex
-		level    level
-		receiver Receiver
+		m        *sync.RWMutex // shared by the parent logger and its child loggers
+		parent   *Logger
+		level    int32    // accessed atomically, child logger uses parent level if not set
+		receiver Receiver // child logger uses parent receiver if not set
 		ctx      Fields
 		hooks    map[string]HookFunc
 	}
@@ -170,6 +179,23 @@ func NewWithContext(cfg *config.Config, ctx Fields) (*Logger, error) {
 	return l, nil
 }
 
+type goContextKey struct{}
+
+// NewGoContext method returns the copy of Go context with given logger, it
+// can be retrieved using `FromGoContext`.
+func NewGoContext(c context.Context, l Loggerer) context.Context {
+	return context.WithValue(c, goContextKey{}, l)
+}
+
+// FromGoContext method returns the logger from given Go context otherwise
+// default logger.
+func FromGoContext(c context.Context) Loggerer {
+	if l, ok := c.Value(goContextKey{}).(Loggerer); ok {
+		return l
+	}
+	return dl
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Logger methods
 //___________________________________
@@ -180,12 +206,24 @@ func NewWithContext(cfg *config.Config, ctx Fields) (*Logger, error) {
 // values gets logged with each log entry.
 //
 // Also you can use method `AddContext` to context to the current logger.
+//
+// Child logger follows the level and receiver of the parent logger, unless
+// it has its own set via `SetLevel` or `SetReceiver`. Parent logger doesn't
+// track its child loggers, so it can be created per request or job.
 func (l *Logger) New(fields Fields) *Logger {
-	nl := *l
-	nl.ctx = make(Fields)
+	l.m.RLock()
+	defer l.m.RUnlock()
+	nl := &Logger{
+		cfg:    l.cfg,
+		m:      l.m,
+		parent: l,
+		level:  levelInherit,
+		ctx:    make(Fields),
+		hooks:  l.hooks,
+	}
 	nl.AddContext(l.ctx)
 	nl.AddContext(fields)
-	return &nl
+	return nl
 }
 
 // AddContext method to add context values into current logger.
@@ -213,19 +251,20 @@ func (l *Logger) AddHook(name string, hook HookFunc) error {
 
 // Level method returns currently enabled logging level.
 func (l *Logger) Level() string {
-	return levelToLevelName[l.level]
+	return levelToLevelName[l.currentLevel()]
 }
 
 // SetLevel method sets the given logging level for the logger.
 // For e.g.: INFO, WARN, DEBUG, etc. Case-insensitive.
+//
+// Level set on the child logger is retained, parent logger level changes
+// are not applicable to it anymore.
 func (l *Logger) SetLevel(level string) error {
-	l.m.Lock()
-	defer l.m.Unlock()
 	levelFlag := levelByName(level)
 	if levelFlag == LevelUnknown {
 		return fmt.Errorf("log: unknown log level '%s'", level)
 	}
-	l.level = levelFlag
+	atomic.StoreInt32(&l.level, int32(levelFlag))
 	return nil
 }
 
@@ -233,35 +272,82 @@ func (l *Logger) SetLevel(level string) error {
 func (l *Logger) SetPattern(pattern string) error {
 	l.m.Lock()
 	defer l.m.Unlock()
-	if l.receiver == nil {
+	receiver := l.currentReceiver()
+	if receiver == nil {
 		return ErrLogReceiverIsNil
 	}
-	return l.receiver.SetPattern(pattern)
+	return receiver.SetPattern(pattern)
 }
 
-// SetReceiver method sets the given receiver into logger instance.
+// SetReceiver method sets the given receiver into logger instance. Previous
+// receiver is closed after in-progress log writes are completed.
 func (l *Logger) SetReceiver(receiver Receiver) error {
+	if receiver == nil {
+		return ErrLogReceiverIsNil
+	}
+
 	l.m.Lock()
-	defer l.m.Unlock()
+	if err := receiver.Init(l.cfg); err != nil {
+		l.m.Unlock()
+		return err
+	}
+	old := l.receiver
+	l.receiver = receiver
+	l.m.Unlock()
+
+	closeReceiver(old, receiver)
+	return nil
+}
 
+// SetReceiverByName method creates the receiver for given name and sets it
+// into logger instance. Receiver gets initialized with logger config values,
+// including `log.pattern`. For e.g.: console, file. Case-insensitive.
+func (l *Logger) SetReceiverByName(name string) error {
+	receiver := getReceiverByName(strings.ToUpper(name))
 	if receiver == nil {
-		return ErrLogReceiverIsNil
+		return fmt.Errorf("log: unknown receiver '%s'", name)
+	}
+
+	if err := receiver.Init(l.cfg); err != nil {
+		return err
+	}
+	if err := receiver.SetPattern(l.cfg.StringDefault("log.pattern", DefaultPattern)); err != nil {
+		return err
 	}
 
+	// log writes are completed on the previous receiver once the lock is
+	// acquired, so it's safe to close
+	l.m.Lock()
+	old := l.receiver
 	l.receiver = receiver
-	return l.receiver.Init(l.cfg)
+	l.m.Unlock()
+
+	closeReceiver(old, receiver)
+	return nil
 }
 
 // SetWriter method sets the given writer into logger instance.
 func (l *Logger) SetWriter(w io.Writer) {
 	l.m.Lock()
 	defer l.m.Unlock()
-	l.receiver.SetWriter(w)
+	l.currentReceiver().SetWriter(w)
+}
+
+// Close method delivers the buffered log entries and closes the logger
+// receiver resources, for e.g.: file, connection, delivery goroutine.
+// Typically called on application shutdown.
+func (l *Logger) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if c, ok := l.currentReceiver().(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
 
 // ToGoLogger method wraps the current log writer into Go Logger instance.
 func (l *Logger) ToGoLogger() *slog.Logger {
-	return slog.New(l.receiver.Writer(), "", slog.LstdFlags)
+	return slog.New(l.writer(), "", slog.LstdFlags)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -270,7 +356,7 @@ func (l *Logger) ToGoLogger() *slog.Logger {
 
 // Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Error(v ...interface{}) {
-	if l.level >= LevelError {
+	if l.currentLevel() >= LevelError {
 		e := acquireEntry(l)
 		e.Error(v...)
 		releaseEntry(e)
@@ -279,7 +365,7 @@ func (l *Logger) Error(v ...interface{}) {
 
 // Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.level >= LevelError {
+	if l.currentLevel() >= LevelError {
 		e := acquireEntry(l)
 		e.Errorf(format, v...)
 		releaseEntry(e)
@@ -288,7 +374,7 @@ func (l *Logger) Errorf(format string, v ...interface{}) {
 
 // Warn logs message as `WARN`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.level >= LevelWarn {
+	if l.currentLevel() >= LevelWarn {
 		e := acquireEntry(l)
 		e.Warn(v...)
 		releaseEntry(e)
@@ -297,7 +383,7 @@ func (l *Logger) Warn(v ...interface{}) {
 
 // Warnf logs message as `WARN`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.level >= LevelWarn {
+	if l.currentLevel() >= LevelWarn {
 		e := acquireEntry(l)
 		e.Warnf(format, v...)
 		releaseEntry(e)
@@ -306,7 +392,7 @@ func (l *Logger) Warnf(format string, v ...interface{}) {
 
 // Info logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Info(v ...interface{}) {
-	if l.level >= LevelInfo {
+	if l.currentLevel() >= LevelInfo {
 		e := acquireEntry(l)
 		e.Info(v...)
 		releaseEntry(e)
@@ -315,7 +401,7 @@ func (l *Logger) Info(v ...interface{}) {
 
 // Infof logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.level >= LevelInfo {
+	if l.currentLevel() >= LevelInfo {
 		e := acquireEntry(l)
 		e.Infof(format, v...)
 		releaseEntry(e)
@@ -324,7 +410,7 @@ func (l *Logger) Infof(format string, v ...interface{}) {
 
 // Debug logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.level >= LevelDebug {
+	if l.currentLevel() >= LevelDebug {
 		e := acquireEntry(l)
 		e.Debug(v...)
 		releaseEntry(e)
@@ -333,7 +419,7 @@ func (l *Logger) Debug(v ...interface{}) {
 
 // Debugf logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.level >= LevelDebug {
+	if l.currentLevel() >= LevelDebug {
 		e := acquireEntry(l)
 		e.Debugf(format, v...)
 		releaseEntry(e)
@@ -342,7 +428,7 @@ func (l *Logger) Debugf(format string, v ...interface{}) {
 
 // Trace logs message as `TRACE`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Trace(v ...interface{}) {
-	if l.level >= LevelTrace {
+	if l.currentLevel() >= LevelTrace {
 		e := acquireEntry(l)
 		e.Trace(v...)
 		releaseEntry(e)
@@ -351,7 +437,7 @@ func (l *Logger) Trace(v ...interface{}) {
 
 // Tracef logs message as `TRACE`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if l.level >= LevelTrace {
+	if l.currentLevel() >= LevelTrace {
 		e := acquireEntry(l)
 		e.Tracef(format, v...)
 		releaseEntry(e)
@@ -450,48 +536,78 @@ func (l *Logger) Panicln(v ...interface{}) {
 
 // IsLevelInfo method returns true if log level is INFO otherwise false.
 func (l *Logger) IsLevelInfo() bool {
-	return l.level == LevelInfo
+	return l.currentLevel() == LevelInfo
 }
 
 // IsLevelError method returns true if log level is ERROR otherwise false.
 func (l *Logger) IsLevelError() bool {
-	return l.level == LevelError
+	return l.currentLevel() == LevelError
 }
 
 // IsLevelWarn method returns true if log level is WARN otherwise false.
 func (l *Logger) IsLevelWarn() bool {
-	return l.level == LevelWarn
+	return l.currentLevel() == LevelWarn
 }
 
 // IsLevelDebug method returns true if log level is DEBUG otherwise false.
 func (l *Logger) IsLevelDebug() bool {
-	return l.level == LevelDebug
+	return l.currentLevel() == LevelDebug
 }
 
 // IsLevelTrace method returns true if log level is TRACE otherwise false.
 func (l *Logger) IsLevelTrace() bool {
-	return l.level == LevelTrace
+	return l.currentLevel() == LevelTrace
 }
 
 // IsLevelFatal method returns true if log level is FATAL otherwise false.
 func (l *Logger) IsLevelFatal() bool {
-	return l.level == LevelFatal
+	return l.currentLevel() == LevelFatal
 }
 
 // IsLevelPanic method returns true if log level is PANIC otherwise false.
 func (l *Logger) IsLevelPanic() bool {
-	return l.level == LevelPanic
+	return l.currentLevel() == LevelPanic
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
 
+// currentLevel method returns the logger level, child logger without its
+// own level returns the parent logger level.
+func (l *Logger) currentLevel() level {
+	for c := l; ; c = c.parent {
+		if lvl := atomic.LoadInt32(&c.level); lvl != levelInherit || c.parent == nil {
+			return level(lvl)
+		}
+	}
+}
+
+// currentReceiver method returns the logger receiver, child logger without
+// its own receiver returns the parent logger receiver. Caller must hold the
+// logger lock.
+func (l *Logger) currentReceiver() Receiver {
+	c := l
+	for c.receiver == nil && c.parent != nil {
+		c = c.parent
+	}
+	return c.receiver
+}
+
+func (l *Logger) writer() io.Writer {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	return l.currentReceiver().Writer()
+}
+
 func (l *Logger) output(e *Entry) {
-	if l.receiver.IsCallerInfo() {
+	l.m.RLock()
+	receiver := l.currentReceiver()
+	if receiver.IsCallerInfo() {
 		e.File, e.Line = fetchCallerInfo()
 	}
-	l.receiver.Log(e)
+	receiver.Log(e)
+	l.m.RUnlock()
 
 	// Execute logger hooks
 	go l.executeHooks(*e)
@@ -7,6 +7,7 @@ package log
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -76,6 +77,87 @@ func TestMisc(t *testing.T) {
 	stdLogger.Print("This is aah logger binds go logger")
 }
 
+func TestChildLoggerPropagation(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "info"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	child := logger.New(Fields{"module": "child"})
+	grandChild := child.New(Fields{"module": "grandchild"})
+	assert.True(t, grandChild.IsLevelInfo())
+
+	err = logger.SetLevel("trace")
+	assert.Nil(t, err)
+	assert.True(t, child.IsLevelTrace())
+	assert.True(t, grandChild.IsLevelTrace())
+
+	// child level change does not affect parent
+	err = child.SetLevel("warn")
+	assert.Nil(t, err)
+	assert.True(t, logger.IsLevelTrace())
+	assert.True(t, grandChild.IsLevelWarn())
+
+	// child level is retained on parent level change
+	err = logger.SetLevel("debug")
+	assert.Nil(t, err)
+	assert.True(t, child.IsLevelWarn())
+	assert.True(t, grandChild.IsLevelWarn())
+	assert.True(t, logger.New(nil).IsLevelDebug())
+
+	fileName := "test-child-receiver.log"
+	defer cleaupFiles(fileName)
+	cfg.SetString("log.file", fileName)
+	err = logger.SetReceiverByName("file")
+	assert.Nil(t, err)
+	_, ok := grandChild.currentReceiver().(*FileReceiver)
+	assert.True(t, ok)
+	assert.Nil(t, grandChild.receiver)
+
+	err = logger.SetReceiverByName("smtp")
+	assert.Equal(t, "log: unknown receiver 'smtp'", err.Error())
+}
+
+func TestLoggerConcurrentSwitch(t *testing.T) {
+	fileName1, fileName2 := "test-switch-1.log", "test-switch-2.log"
+	defer cleaupFiles(fileName1)
+	defer cleaupFiles(fileName2)
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "file"
+    level = "info"
+    file = "` + fileName1 + `"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	first := logger.receiver.(*FileReceiver)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l := logger.New(Fields{"worker": i})
+				l.Info("switching message")
+				_ = l.IsLevelDebug()
+			}
+		}(i)
+	}
+
+	cfg.SetString("log.file", fileName2)
+	for _, lvl := range []string{"debug", "warn", "info"} {
+		assert.Nil(t, logger.SetLevel(lvl))
+	}
+	assert.Nil(t, logger.SetReceiverByName("file"))
+	wg.Wait()
+
+	// previous receiver is closed after the switch
+	assert.True(t, first.isClosed)
+	assert.Nil(t, logger.Close())
+}
+
 func testPanic(logger *Logger, method, msg string) {
 	defer func() {
 		if r := recover(); r != nil {
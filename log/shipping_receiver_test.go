@@ -0,0 +1,250 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogReceiver(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "syslog"
+    pattern = "%level:-5 %message %fields"
+    receivers {
+      syslog {
+        address = "` + conn.LocalAddr().String() + `"
+        facility = "local0"
+        tag = "aahtest"
+      }
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.WithField("reqid", "req-id-1").Error("syslog error message")
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<131>1 "))
+	assert.True(t, strings.Contains(msg, " aahtest "))
+	assert.True(t, strings.Contains(msg, " req-id-1 - ERROR syslog error message"))
+
+	// re-init closes the previous connection
+	r := logger.receiver.(*SyslogReceiver)
+	prevConn := r.conn
+	assert.Nil(t, r.Init(cfg))
+	_, err = prevConn.Write([]byte("closed"))
+	assert.NotNil(t, err)
+	assert.Nil(t, logger.Close())
+	assert.Nil(t, r.conn)
+	assert.Nil(t, r.Close())
+
+	cfg.SetString("log.receivers.syslog.facility", "unknown")
+	_, err = New(cfg)
+	assert.Equal(t, "log: unsupported syslog facility 'unknown'", err.Error())
+}
+
+func TestJournalReceiver(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "journal.sock")
+	conn, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Skipf("unixgram is not supported: %v", err)
+	}
+	defer conn.Close()
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "journal"
+    pattern = "%message"
+    receivers {
+      journal {
+        socket = "` + socket + `"
+        identifier = "aahtest"
+      }
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.WithField("user-id", 10).Warn("multi\nline")
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "MESSAGE\n"))
+	assert.True(t, strings.Contains(msg, "PRIORITY=4\n"))
+	assert.True(t, strings.Contains(msg, "SYSLOG_IDENTIFIER=aahtest\n"))
+	assert.True(t, strings.Contains(msg, "USER_ID=10\n"))
+
+	assert.Equal(t, "F_ID", journalFieldName("_id"))
+}
+
+func TestHTTPReceiver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []map[string]interface{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var entries []map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&entries))
+		received = append(received, entries...)
+	}))
+	defer ts.Close()
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "http"
+    receivers {
+      http {
+        url = "` + ts.URL + `"
+        batch_size = 10
+        flush_interval = "1m"
+        retry_wait = "1ms"
+        headers {
+          Authorization = "Bearer token"
+        }
+      }
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.Info("first message")
+	logger.Errorf("second %s", "message")
+	logger.receiver.(*HTTPReceiver).Flush()
+
+	mu.Lock()
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "first message", received[0]["message"])
+	assert.Equal(t, "ERROR", received[1]["level"])
+	mu.Unlock()
+	assert.Equal(t, int64(0), logger.receiver.(*HTTPReceiver).Dropped())
+
+	cfg.SetString("log.receivers.http.url", "")
+	_, err = New(cfg)
+	assert.Equal(t, "log: http receiver 'url' is required", err.Error())
+
+	cfg.SetString("log.receivers.http.url", ts.URL)
+	cfg.SetString("log.receivers.http.flush_interval", "5 mins")
+	_, err = New(cfg)
+	assert.Equal(t, `log: config 'log.receivers.http.flush_interval': time: unknown unit " mins" in duration "5 mins"`, err.Error())
+
+	cfg.SetString("log.receivers.http.flush_interval", "0s")
+	_, err = New(cfg)
+	assert.Equal(t, "log: http receiver 'flush_interval' must be greater than zero", err.Error())
+
+	cfg.SetString("log.receivers.http.flush_interval", "1s")
+	cfg.SetInt("log.receivers.http.batch_size", 0)
+	_, err = New(cfg)
+	assert.Equal(t, "log: http receiver 'batch_size' must be greater than zero", err.Error())
+
+	cfg.SetInt("log.receivers.http.batch_size", 10)
+	cfg.SetInt("log.receivers.http.buffer_size", -1)
+	_, err = New(cfg)
+	assert.Equal(t, "log: http receiver 'buffer_size' must not be negative", err.Error())
+}
+
+func TestHTTPReceiverRetryAndClose(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var entries []map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&entries))
+		for _, e := range entries {
+			received = append(received, e["message"].(string))
+		}
+	}))
+	defer ts.Close()
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "http"
+    receivers {
+      http {
+        url = "` + ts.URL + `"
+        batch_size = 1
+        flush_interval = "1m"
+        retry_wait = "1h"
+      }
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	r := logger.receiver.(*HTTPReceiver)
+
+	// failed delivery waits for retry, new entries are still delivered
+	logger.Info("first message")
+	logger.Info("second message")
+	for i := 0; i < 100; i++ {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	mu.Lock()
+	assert.Equal(t, []string{"second message"}, received)
+	mu.Unlock()
+
+	// re-init stops the previous delivery goroutine, pending ones are delivered
+	prevDone := r.doneChan
+	assert.Nil(t, r.Init(cfg))
+	<-prevDone
+	mu.Lock()
+	assert.Equal(t, []string{"second message", "first message"}, received)
+	mu.Unlock()
+
+	logger.Info("third message")
+	assert.Nil(t, logger.Close())
+	assert.Nil(t, r.Close())
+	r.Flush()
+	mu.Lock()
+	assert.Equal(t, 3, len(received))
+	mu.Unlock()
+	assert.Equal(t, int64(0), r.Dropped())
+}
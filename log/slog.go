@@ -0,0 +1,244 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"aahframe.work/config"
+)
+
+var (
+	_ slog.Handler = (*SlogHandler)(nil)
+	_ Receiver     = (*SlogReceiver)(nil)
+)
+
+// aah log level mapping for `log/slog` levels, slog has no TRACE, FATAL and
+// PANIC levels.
+const (
+	SlogLevelTrace = slog.LevelDebug - 4
+	SlogLevelFatal = slog.LevelError + 4
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Slog Handler
+//___________________________________
+
+// SlogHandler is the `slog.Handler` backed by aah logger, slog records
+// are logged via aah logger receiver and hooks. Record attributes are mapped
+// into log `Fields`, group attributes are flattened with dot notation,
+// for e.g.: `http.status`.
+//
+// 	logger := log.NewSlogHandler(aah.App().Log().(*log.Logger))
+// 	slog.SetDefault(slog.New(logger))
+type SlogHandler struct {
+	logger *Logger
+	fields Fields
+	prefix string
+}
+
+// NewSlogHandler method creates the slog handler for the given aah logger.
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l, fields: make(Fields)}
+}
+
+// Enabled method reports whether the aah logger level allows the given
+// slog level.
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.logger.currentLevel() >= levelFromSlog(lvl)
+}
+
+// Handle method logs the slog record via aah logger.
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	lvl := levelFromSlog(r.Level)
+	if h.logger.currentLevel() < lvl {
+		return nil
+	}
+
+	e := acquireEntry(h.logger)
+	defer releaseEntry(e)
+	e.addFields(h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(e.Fields, h.prefix, a)
+		return true
+	})
+	e.output(lvl, r.Message)
+	return nil
+}
+
+// WithAttrs method returns the new slog handler with given attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := h.clone()
+	for _, a := range attrs {
+		addSlogAttr(nh.fields, nh.prefix, a)
+	}
+	return nh
+}
+
+// WithGroup method returns the new slog handler, subsequent attributes
+// keys are prefixed with group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	nh := h.clone()
+	nh.prefix = h.prefix + name + "."
+	return nh
+}
+
+// ToSlog method returns the `*slog.Logger` backed by aah logger.
+func (l *Logger) ToSlog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Slog Receiver
+//___________________________________
+
+// SlogReceiver is the aah log receiver backed by `slog.Handler`, so aah
+// logger shares the slog handlers pipeline with apps and libraries that
+// standardize on slog. Log entry fields are mapped into record attributes,
+// along with `app_name`, `instance_name`, `request_id` and `principal`.
+//
+// 	logger.SetReceiver(log.NewSlogReceiver(slog.NewJSONHandler(os.Stdout, nil)))
+type SlogReceiver struct {
+	handler slog.Handler
+}
+
+// NewSlogReceiver method creates the log receiver for the given slog handler.
+func NewSlogReceiver(h slog.Handler) *SlogReceiver {
+	return &SlogReceiver{handler: h}
+}
+
+// Init method does nothing, slog handler is configured by the caller.
+func (s *SlogReceiver) Init(cfg *config.Config) error {
+	return nil
+}
+
+// SetPattern method does nothing, slog handler takes care of format.
+func (s *SlogReceiver) SetPattern(pattern string) error {
+	return nil
+}
+
+// SetWriter method replaces the slog handler with text handler for
+// the given writer.
+func (s *SlogReceiver) SetWriter(w io.Writer) {
+	s.handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: SlogLevelTrace})
+}
+
+// IsCallerInfo method returns false, slog handler adds the source info
+// if enabled.
+func (s *SlogReceiver) IsCallerInfo() bool {
+	return false
+}
+
+// Writer method returns the writer, each write is logged as `INFO` record
+// into slog handler.
+func (s *SlogReceiver) Writer() io.Writer {
+	return &slogWriter{r: s}
+}
+
+// Log method logs the given entry into slog handler.
+func (s *SlogReceiver) Log(e *Entry) {
+	lvl := slogLevel(e.Level)
+	if !s.handler.Enabled(context.Background(), lvl) {
+		return
+	}
+
+	r := slog.NewRecord(e.Time, lvl, e.Message, 0)
+	for k, v := range map[string]string{
+		"app_name":      e.AppName,
+		"instance_name": e.InstanceName,
+		"request_id":    e.RequestID,
+		"principal":     e.Principal,
+	} {
+		if len(v) > 0 {
+			r.AddAttrs(slog.String(k, v))
+		}
+	}
+	for k, v := range e.Fields {
+		if !e.isSkipField(k) {
+			r.AddAttrs(slog.Any(k, v))
+		}
+	}
+	_ = s.handler.Handle(context.Background(), r)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+type slogWriter struct {
+	r *SlogReceiver
+}
+
+func (w *slogWriter) Write(p []byte) (int, error) {
+	w.r.Log(&Entry{Level: LevelInfo, Time: time.Now(), Message: string(bytes.TrimSpace(p))})
+	return len(p), nil
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	nh := &SlogHandler{logger: h.logger, fields: make(Fields), prefix: h.prefix}
+	for k, v := range h.fields {
+		nh.fields[k] = v
+	}
+	return nh
+}
+
+func addSlogAttr(fields Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if len(a.Key) > 0 {
+			p += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addSlogAttr(fields, p, ga)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
+
+func levelFromSlog(lvl slog.Level) level {
+	switch {
+	case lvl >= SlogLevelFatal:
+		return LevelFatal
+	case lvl >= slog.LevelError:
+		return LevelError
+	case lvl >= slog.LevelWarn:
+		return LevelWarn
+	case lvl >= slog.LevelInfo:
+		return LevelInfo
+	case lvl >= slog.LevelDebug:
+		return LevelDebug
+	}
+	return LevelTrace
+}
+
+func slogLevel(lvl level) slog.Level {
+	switch lvl {
+	case LevelFatal, LevelPanic:
+		return SlogLevelFatal
+	case LevelError:
+		return slog.LevelError
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelTrace:
+		return SlogLevelTrace
+	}
+	return slog.LevelInfo
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "debug"
+    format = "json"
+    color = false
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := new(bytes.Buffer)
+	logger.SetWriter(buf)
+
+	sl := logger.ToSlog()
+	assert.True(t, sl.Enabled(context.Background(), slog.LevelDebug))
+	assert.False(t, sl.Enabled(context.Background(), SlogLevelTrace))
+
+	sl.Debug("trace me not", "skip", true)
+	sl.Log(context.Background(), SlogLevelTrace, "not logged")
+	sl.With("module", "billing").WithGroup("http").Info("request served",
+		"status", 200, slog.Group("client", "ip", "10.0.0.1"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &m))
+	assert.Equal(t, "INFO", m["level"])
+	assert.Equal(t, "request served", m["message"])
+	fields := m["fields"].(map[string]interface{})
+	assert.Equal(t, "billing", fields["module"])
+	assert.Equal(t, float64(200), fields["http.status"])
+	assert.Equal(t, "10.0.0.1", fields["http.client.ip"])
+
+	assert.Equal(t, slog.Handler(NewSlogHandler(logger)), NewSlogHandler(logger).WithGroup(""))
+}
+
+func TestSlogReceiver(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "trace"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.AddContext(Fields{"appname": "myapp"})
+
+	buf := new(bytes.Buffer)
+	h := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	assert.Nil(t, logger.SetReceiver(NewSlogReceiver(h)))
+
+	logger.Trace("filtered by slog handler level")
+	logger.WithField("order_id", "A100").Warn("payment delayed")
+	logger.ToGoLogger().Print("from go logger")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &m))
+	assert.Equal(t, "WARN", m["level"])
+	assert.Equal(t, "payment delayed", m["msg"])
+	assert.Equal(t, "A100", m["order_id"])
+	assert.Equal(t, "myapp", m["app_name"])
+	_, found := m["appname"]
+	assert.False(t, found)
+
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &m))
+	assert.Equal(t, "INFO", m["level"])
+	assert.Contains(t, m["msg"], "from go logger")
+
+	buf.Reset()
+	logger.SetWriter(buf)
+	assert.Nil(t, logger.SetPattern("%message"))
+	logger.Error("now text")
+	assert.Contains(t, buf.String(), "level=ERROR msg=\"now text\"")
+	assert.False(t, NewSlogReceiver(h).IsCallerInfo())
+
+	assert.Equal(t, SlogLevelFatal, slogLevel(LevelPanic))
+	assert.Equal(t, SlogLevelTrace, slogLevel(LevelTrace))
+	assert.Equal(t, slog.LevelInfo, slogLevel(LevelInfo))
+	assert.Equal(t, LevelFatal, levelFromSlog(SlogLevelFatal))
+}
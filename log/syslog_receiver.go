@@ -0,0 +1,227 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const (
+	syslogVersion      = "1"
+	syslogNilValue     = "-"
+	syslogDefaultNet   = "udp"
+	syslogDefaultAddr  = "127.0.0.1:514"
+	syslogFacilityBase = 8
+)
+
+var (
+	syslogFacilities = map[string]int{
+		"kern":     0,
+		"user":     1,
+		"mail":     2,
+		"daemon":   3,
+		"auth":     4,
+		"syslog":   5,
+		"lpr":      6,
+		"news":     7,
+		"uucp":     8,
+		"cron":     9,
+		"authpriv": 10,
+		"ftp":      11,
+		"local0":   16,
+		"local1":   17,
+		"local2":   18,
+		"local3":   19,
+		"local4":   20,
+		"local5":   21,
+		"local6":   22,
+		"local7":   23,
+	}
+
+	// RFC5424 severity values
+	levelToSyslogSeverity = map[level]int{
+		LevelFatal: 2, // critical
+		LevelPanic: 1, // alert
+		LevelError: 3, // error
+		LevelWarn:  4, // warning
+		LevelInfo:  6, // informational
+		LevelDebug: 7, // debug
+		LevelTrace: 7, // debug
+	}
+
+	_ Receiver = (*SyslogReceiver)(nil)
+)
+
+// SyslogReceiver writes the log entry into syslog server in the RFC5424
+// message format. Configuration goes under `log.receivers.syslog { ... }`.
+//
+// 	log {
+// 	  receiver = "syslog"
+// 	  receivers {
+// 	    syslog {
+// 	      network = "udp"
+// 	      address = "127.0.0.1:514"
+// 	      facility = "local0"
+// 	      tag = "myapp"
+// 	    }
+// 	  }
+// 	}
+type SyslogReceiver struct {
+	network      string
+	address      string
+	tag          string
+	hostname     string
+	facility     int
+	conn         net.Conn
+	out          io.Writer
+	formatter    string
+	flags        []ess.FmtFlagPart
+	isCallerInfo bool
+	mu           sync.Mutex
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// SyslogReceiver methods
+//___________________________________
+
+// Init method initializes the syslog receiver instance.
+func (s *SyslogReceiver) Init(cfg *config.Config) error {
+	keyPrefix := "log.receivers.syslog."
+	s.network = cfg.StringDefault(keyPrefix+"network", syslogDefaultNet)
+	s.address = cfg.StringDefault(keyPrefix+"address", syslogDefaultAddr)
+	s.tag = cfg.StringDefault(keyPrefix+"tag", cfg.StringDefault("name", syslogNilValue))
+
+	facility := strings.ToLower(cfg.StringDefault(keyPrefix+"facility", "user"))
+	f, found := syslogFacilities[facility]
+	if !found {
+		return fmt.Errorf("log: unsupported syslog facility '%s'", facility)
+	}
+	s.facility = f
+
+	s.formatter = cfg.StringDefault("log.format", "text")
+	if !(s.formatter == textFmt || s.formatter == jsonFmt) {
+		return fmt.Errorf("log: unsupported format '%s'", s.formatter)
+	}
+
+	var err error
+	if s.hostname, err = os.Hostname(); err != nil {
+		s.hostname = syslogNilValue
+	}
+
+	conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	// on re-init, previous connection is closed
+	s.mu.Lock()
+	if s.conn != nil {
+		ess.CloseQuietly(s.conn)
+	}
+	s.conn = conn
+	s.out = conn
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern.
+func (s *SyslogReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	s.flags = flags
+	if s.formatter == textFmt {
+		s.isCallerInfo = isCallerInfo(s.flags)
+	}
+	return nil
+}
+
+// SetWriter method sets the given writer into syslog receiver.
+func (s *SyslogReceiver) SetWriter(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (s *SyslogReceiver) IsCallerInfo() bool {
+	return s.isCallerInfo
+}
+
+// Log method writes the log entry into syslog server.
+func (s *SyslogReceiver) Log(entry *Entry) {
+	var msg []byte
+	if s.formatter == textFmt {
+		msg = bytes.TrimRight(textFormatter(s.flags, entry), " \n")
+	} else {
+		msg, _ = json.Marshal(entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.out.Write(s.rfc5424(entry, msg))
+}
+
+// Writer method returns the current log writer.
+func (s *SyslogReceiver) Writer() io.Writer {
+	return s.out
+}
+
+// Close method closes the syslog server connection.
+func (s *SyslogReceiver) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// SyslogReceiver Unexported methods
+//___________________________________
+
+// rfc5424 method composes the syslog message.
+// 	<PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *SyslogReceiver) rfc5424(entry *Entry, msg []byte) []byte {
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	msgID := syslogNilValue
+	if len(entry.RequestID) > 0 {
+		msgID = entry.RequestID
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("<" + strconv.Itoa(s.facility*syslogFacilityBase+levelToSyslogSeverity[entry.Level]) + ">")
+	buf.WriteString(syslogVersion + space)
+	buf.WriteString(t.Format(time.RFC3339Nano) + space)
+	buf.WriteString(s.hostname + space)
+	buf.WriteString(s.tag + space)
+	buf.WriteString(strconv.Itoa(os.Getpid()) + space)
+	buf.WriteString(msgID + space)
+	buf.WriteString(syslogNilValue + space)
+	buf.Write(msg)
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
@@ -5,6 +5,7 @@
 package log
 
 import (
+	"io"
 	"runtime"
 	"strings"
 	"time"
@@ -101,11 +102,24 @@ func getReceiverByName(name string) Receiver {
 		return &FileReceiver{}
 	case "CONSOLE":
 		return &ConsoleReceiver{}
+	case "SYSLOG":
+		return &SyslogReceiver{}
+	case "JOURNAL":
+		return &JournalReceiver{}
+	case "HTTP":
+		return &HTTPReceiver{}
 	default:
 		return nil
 	}
 }
 
+// closeReceiver method closes the replaced receiver if it holds resources.
+func closeReceiver(old, new Receiver) {
+	if c, ok := old.(io.Closer); ok && old != new {
+		_ = c.Close()
+	}
+}
+
 func formatTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
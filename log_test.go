@@ -0,0 +1,48 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLogSampling(t *testing.T) {
+	cfg, _ := config.ParseString(`server {
+  access_log {
+    sampling {
+      rate = 1
+      tags {
+        health = 0
+        public = 0.0001
+        api = 1.0
+      }
+    }
+  }
+}`)
+	s, err := parseAccessLogSampling(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), s.rate)
+	assert.Equal(t, 3, len(s.tags))
+
+	assert.True(t, s.sampled(nil))
+	assert.True(t, s.sampled(&router.Route{Name: "index"}))
+	assert.True(t, s.sampled(&router.Route{Name: "orders", Tags: []string{"api"}}))
+	assert.False(t, s.sampled(&router.Route{Name: "health", Tags: []string{"api", "health"}}))
+
+	s.rate = 0
+	assert.False(t, s.sampled(&router.Route{Name: "index"}))
+	assert.True(t, s.sampled(&router.Route{Name: "orders", Tags: []string{"api"}}))
+
+	cfg.SetFloat64("server.access_log.sampling.tags.public", 1.5)
+	_, err = parseAccessLogSampling(cfg)
+	assert.Equal(t, "aah: 'server.access_log.sampling.tags.public' value '1.5' is not in range 0 to 1", err.Error())
+	cfg.SetString("server.access_log.sampling.rate", "all")
+	_, err = parseAccessLogSampling(cfg)
+	assert.Equal(t, "aah: 'server.access_log.sampling.rate' value '-1' is not in range 0 to 1", err.Error())
+}
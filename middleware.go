@@ -5,6 +5,7 @@
 package aah
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 
@@ -65,6 +66,25 @@ func ToMiddleware(handler interface{}) MiddlewareFunc {
 type Middleware struct {
 	next    MiddlewareFunc
 	further *Middleware
+	owner   string
+}
+
+// MiddlewareAbort struct holds the details of the middleware which
+// short-circuited the middleware chain, i.e. request never reached the end of
+// the chain (controller action). It is recorded on the context and logged in
+// the access log `%mwabort` and dump log.
+type MiddlewareAbort struct {
+	Middleware string
+	Status     int
+	Err        error
+}
+
+// String method is Stringer interface implementation.
+func (ma *MiddlewareAbort) String() string {
+	if ma.Err == nil {
+		return fmt.Sprintf("%s [%d]", ma.Middleware, ma.Status)
+	}
+	return fmt.Sprintf("%s [%d]: %v", ma.Middleware, ma.Status, ma.Err)
 }
 
 // Next method calls next middleware in the chain if available.
@@ -75,10 +95,25 @@ func (mw *Middleware) Next(ctx *Context) {
 	}
 
 	if mw.next != nil {
+		ctx.mwReached = mw.further
 		mw.next(ctx, mw.further)
 	}
 }
 
+// Abort method aborts the middleware chain with given HTTP status code and
+// error reply. The middleware and reason are recorded on the context, refer
+// `Context.MiddlewareAbort`.
+//
+// 	if !isAllowed(ctx.Req.ClientIP()) {
+// 		m.Abort(ctx, http.StatusForbidden, errors.New("ip address is not allowed"))
+// 		return
+// 	}
+func (mw *Middleware) Abort(ctx *Context, status int, err error) {
+	ctx.mwAbort = &MiddlewareAbort{Middleware: mw.owner, Status: status, Err: err}
+	ctx.Reply().Status(status).Error(newError(err, status))
+	ctx.Abort()
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // engine - Middleware
 //______________________________________________________________________________
@@ -104,6 +139,11 @@ func (e *HTTPEngine) invalidateMwChain() {
 	}
 
 	e.mwChain[cnt-1].further = &Middleware{}
+
+	// owner is the middleware func which receives the chain instance
+	for idx := 0; idx < cnt; idx++ {
+		e.mwChain[idx].further.owner = ess.GetFunctionInfo(e.mwStack[idx]).QualifiedName
+	}
 }
 
 type beforeInterceptor interface {
@@ -127,10 +167,37 @@ type finallyInterceptor interface {
 //				Panic, Panic<ActionName>, Finally, Finally<ActionName>)
 // 	- Invokes Controller Action
 func ActionMiddleware(ctx *Context, m *Middleware) {
+	// Reverse proxy route, forwards the request to proxy target
+	if ctx.route != nil && ctx.route.IsProxy() {
+		ctx.a.proxyMgr.Serve(ctx)
+		return
+	}
+
+	// Concurrent identical requests shares the single action execution,
+	// refer to route attribute `singleflight`
+	if ctx.a.singleflight.isApplicable(ctx) {
+		ctx.a.singleflight.do(ctx, invokeAction)
+		return
+	}
+
+	invokeAction(ctx)
+}
+
+// invokeAction method executes the interceptors and controller action of
+// the route.
+func invokeAction(ctx *Context) {
+	sw := ctx.startTiming(TimingAction)
+	defer sw.Stop()
+
 	if err := ctx.setTarget(ctx.route); err == errTargetNotFound {
 		// No controller or action found for the route
 		ctx.Reply().NotFound().Error(newError(ErrControllerOrActionNotFound, http.StatusNotFound))
 		return
+	} else if err != nil {
+		// controller dependencies could not be resolved
+		ctx.Log().Error(err)
+		ctx.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+		return
 	}
 
 	// Finally action and method. Always executed if present
@@ -184,8 +251,13 @@ func ActionMiddleware(ctx *Context, m *Middleware) {
 
 	if !ctx.abort {
 		// Parse Action Parameters
+		bsw := ctx.startTiming(TimingBinding)
 		actionArgs, err := ctx.parseParameters()
+		sw.exclude(bsw.Stop())
 		if err != nil { // Any error of parameter parsing result in 400 Bad Request
+			if ctx.formRedirectBack(err) {
+				return
+			}
 			ctx.Reply().BadRequest().Error(err)
 			return
 		}
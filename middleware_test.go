@@ -5,13 +5,16 @@
 package aah
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -77,3 +80,75 @@ func thirdPartyMiddleware3(w http.ResponseWriter, r *http.Request) {
 func invaildHandlerType(e *Event) {
 	fmt.Println("This is invaild handler type")
 }
+
+func TestMiddlewareAbort(t *testing.T) {
+	a := newApp()
+	e := a.he
+
+	var reachedEnd bool
+	e.Middlewares(
+		func(ctx *Context, m *Middleware) {
+			m.Next(ctx)
+		},
+		ipFilterMiddleware,
+		func(ctx *Context, m *Middleware) {
+			reachedEnd = true
+		},
+	)
+
+	newMwCtx := func(ip string) *Context {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/doc/v0.3/mydoc.html", nil)
+		req.RemoteAddr = ip + ":8080"
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a, ctx.e = a, e
+		return ctx
+	}
+
+	// aborted via m.Abort
+	ctx := newMwCtx("10.0.0.1")
+	e.mwChain[0].Next(ctx)
+	ctx.recordShortCircuit()
+	assert.False(t, reachedEnd)
+	assert.True(t, ctx.abort)
+	assert.Equal(t, http.StatusForbidden, ctx.Reply().Code)
+	ma := ctx.MiddlewareAbort()
+	assert.NotNil(t, ma)
+	assert.Equal(t, "aahframe.work.ipFilterMiddleware", ma.Middleware)
+	assert.Equal(t, "aahframe.work.ipFilterMiddleware [403]: ip address is not allowed", ma.String())
+
+	// short-circuited without calling m.Next
+	ctx = newMwCtx("10.0.0.2")
+	e.mwChain[0].Next(ctx)
+	ctx.recordShortCircuit()
+	assert.False(t, reachedEnd)
+	ma = ctx.MiddlewareAbort()
+	assert.NotNil(t, ma)
+	assert.Equal(t, "aahframe.work.ipFilterMiddleware", ma.Middleware)
+	assert.Equal(t, "aahframe.work.ipFilterMiddleware [429]", ma.String())
+
+	// reached end of the chain
+	ctx = newMwCtx("127.0.0.1")
+	e.mwChain[0].Next(ctx)
+	ctx.recordShortCircuit()
+	assert.True(t, reachedEnd)
+	assert.Nil(t, ctx.MiddlewareAbort())
+
+	// access log
+	al := &accessLog{Request: ctx.Req, MiddlewareAbort: "aahframe.work.ipFilterMiddleware"}
+	aal := &accessLogger{a: a, fmtFlags: []ess.FmtFlagPart{{Flag: fmtFlagMiddlewareAbort}}}
+	aal.logPool = &sync.Pool{New: func() interface{} { return new(accessLog) }}
+	assert.Equal(t, "aahframe.work.ipFilterMiddleware", aal.accessLogFormatter(al))
+	assert.Equal(t, "-", aal.accessLogFormatter(&accessLog{}))
+}
+
+func ipFilterMiddleware(ctx *Context, m *Middleware) {
+	switch ctx.Req.ClientIP() {
+	case "10.0.0.1":
+		m.Abort(ctx, http.StatusForbidden, errors.New("ip address is not allowed"))
+		return
+	case "10.0.0.2":
+		ctx.Reply().Status(http.StatusTooManyRequests)
+		return
+	}
+	m.Next(ctx)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	dialects = map[string]Dialect{
+		"postgres": &postgresDialect{},
+		"pgx":      &postgresDialect{},
+		"mysql":    &mysqlDialect{},
+		"sqlite3":  &sqliteDialect{},
+		"sqlite":   &sqliteDialect{},
+	}
+	dialectMu = &sync.RWMutex{}
+)
+
+// Dialect interface represents database specific SQL statements used by
+// migrator to track the applied migrations.
+type Dialect interface {
+	// CreateTableSQL method returns the SQL to create migrations table if
+	// not exists.
+	CreateTableSQL(table string) string
+
+	// SelectSQL method returns the SQL to select applied version and timestamp.
+	SelectSQL(table string) string
+
+	// InsertSQL method returns the SQL to insert version, name and applied
+	// timestamp.
+	InsertSQL(table string) string
+
+	// DeleteSQL method returns the SQL to delete version.
+	DeleteSQL(table string) string
+}
+
+// AddDialect method adds the given dialect for database driver name. If
+// dialect exists for driver name then it returns an error.
+func AddDialect(driver string, d Dialect) error {
+	dialectMu.Lock()
+	defer dialectMu.Unlock()
+	if _, found := dialects[driver]; found {
+		return fmt.Errorf("migrate: dialect exists for driver '%s'", driver)
+	}
+	dialects[driver] = d
+	return nil
+}
+
+// DialectByDriver method returns the dialect for database driver name
+// otherwise nil.
+func DialectByDriver(driver string) Dialect {
+	dialectMu.RLock()
+	defer dialectMu.RUnlock()
+	return dialects[driver]
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Dialects
+//______________________________________________________________________________
+
+type postgresDialect struct{}
+
+func (postgresDialect) CreateTableSQL(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)"
+}
+
+func (postgresDialect) SelectSQL(table string) string {
+	return "SELECT version, applied_at FROM " + table + " ORDER BY version"
+}
+
+func (postgresDialect) InsertSQL(table string) string {
+	return "INSERT INTO " + table + " (version, name, applied_at) VALUES ($1, $2, $3)"
+}
+
+func (postgresDialect) DeleteSQL(table string) string {
+	return "DELETE FROM " + table + " WHERE version = $1"
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) CreateTableSQL(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)"
+}
+
+func (mysqlDialect) SelectSQL(table string) string {
+	return "SELECT version, applied_at FROM " + table + " ORDER BY version"
+}
+
+func (mysqlDialect) InsertSQL(table string) string {
+	return "INSERT INTO " + table + " (version, name, applied_at) VALUES (?, ?, ?)"
+}
+
+func (mysqlDialect) DeleteSQL(table string) string {
+	return "DELETE FROM " + table + " WHERE version = ?"
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) CreateTableSQL(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at DATETIME NOT NULL)"
+}
+
+func (sqliteDialect) SelectSQL(table string) string {
+	return "SELECT version, applied_at FROM " + table + " ORDER BY version"
+}
+
+func (sqliteDialect) InsertSQL(table string) string {
+	return "INSERT INTO " + table + " (version, name, applied_at) VALUES (?, ?, ?)"
+}
+
+func (sqliteDialect) DeleteSQL(table string) string {
+	return "DELETE FROM " + table + " WHERE version = ?"
+}
@@ -0,0 +1,331 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package migrate provides SQL database schema migration feature for aah
+// application.
+//
+// Migrations are SQL files under app `migrations` directory with naming
+// convention `<version>_<name>.up.sql` and `<version>_<name>.down.sql`, for e.g.:
+//
+// 	migrations/20181015103000_create_users.up.sql
+// 	migrations/20181015103000_create_users.down.sql
+//
+// Applied migrations are tracked in the database table `schema_migrations`.
+// Database specific SQL is handled via `Dialect`, OOTB aah provides dialects
+// for postgres, mysql and sqlite3 drivers. Additional dialect can be added
+// via `migrate.AddDialect`.
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Migrate errors
+var (
+	ErrDialectNotFound = errors.New("migrate: dialect not found")
+	ErrNoMigration     = errors.New("migrate: no migration to apply")
+)
+
+// DefaultTable is the database table name to track applied migrations.
+const DefaultTable = "schema_migrations"
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// FileSystem interface is used to read migration files, for e.g.:
+// aah application VFS instance.
+type FileSystem interface {
+	ReadFile(filename string) ([]byte, error)
+	ReadDir(dirname string) ([]os.FileInfo, error)
+}
+
+// Migration struct holds single migration information.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String method is Stringer interface.
+func (m *Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Status struct holds migration apply status.
+type Status struct {
+	*Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//______________________________________________________________________________
+
+// New method returns the new migrator instance for given database, driver
+// name and migrations directory.
+func New(db *sql.DB, driver, dir string, fs FileSystem) (*Migrator, error) {
+	dialect := DialectByDriver(driver)
+	if dialect == nil {
+		return nil, fmt.Errorf("%v: '%s'", ErrDialectNotFound, driver)
+	}
+	return &Migrator{
+		DB:      db,
+		Dir:     dir,
+		Table:   DefaultTable,
+		Dialect: dialect,
+		fs:      fs,
+	}, nil
+}
+
+// Create method creates the new up and down migration files into given
+// directory on physical file system. Version is current UTC timestamp.
+func Create(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_"))
+	if len(name) == 0 {
+		return "", "", errors.New("migrate: migration name is required")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+
+	prefix := filepath.Join(dir, time.Now().UTC().Format("20060102150405")+"_"+name)
+	up, down := prefix+upSuffix, prefix+downSuffix
+	if err := ioutil.WriteFile(up, []byte("-- "+name+" up migration\n"), 0644); err != nil {
+		return "", "", err
+	}
+	if err := ioutil.WriteFile(down, []byte("-- "+name+" down migration\n"), 0644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Migrator
+//______________________________________________________________________________
+
+// Migrator struct applies and reverts the migrations on the database.
+type Migrator struct {
+	DB      *sql.DB
+	Dir     string
+	Table   string
+	Dialect Dialect
+
+	mu sync.Mutex
+	fs FileSystem
+}
+
+// Migrations method returns all the migrations from migrations directory
+// sorted by version.
+func (m *Migrator) Migrations() ([]*Migration, error) {
+	infos, err := m.fs.ReadDir(m.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	mm := make(map[int64]*Migration)
+	for _, info := range infos {
+		fname := info.Name()
+		if info.IsDir() || !strings.HasSuffix(fname, ".sql") {
+			continue
+		}
+		version, name, up, err := parseFilename(fname)
+		if err != nil {
+			return nil, err
+		}
+		b, err := m.fs.ReadFile(path.Join(m.Dir, fname))
+		if err != nil {
+			return nil, err
+		}
+		mg, found := mm[version]
+		if !found {
+			mg = &Migration{Version: version, Name: name}
+			mm[version] = mg
+		} else if mg.Name != name {
+			return nil, fmt.Errorf("migrate: duplicate migration version '%d'", version)
+		}
+		if up {
+			mg.Up = string(b)
+		} else {
+			mg.Down = string(b)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(mm))
+	for _, mg := range mm {
+		migrations = append(migrations, mg)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status method returns the apply status of all the migrations.
+func (m *Migrator) Status() ([]*Status, error) {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]*Status, 0, len(migrations))
+	for _, mg := range migrations {
+		at, found := applied[mg.Version]
+		status = append(status, &Status{Migration: mg, Applied: found, AppliedAt: at})
+	}
+	return status, nil
+}
+
+// Pending method returns the migrations which are not applied yet.
+func (m *Migrator) Pending() ([]*Migration, error) {
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, s := range status {
+		if !s.Applied {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up method applies the pending migrations, `n` limits the number of
+// migrations to apply, if it's zero then all the pending migrations are applied.
+// Each migration is applied within the transaction.
+func (m *Migrator) Up(n int) ([]*Migration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, ErrNoMigration
+	}
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+
+	var done []*Migration
+	for _, mg := range pending {
+		if err = m.apply(mg, mg.Up, m.Dialect.InsertSQL(m.Table), mg.Version, mg.Name, time.Now().UTC()); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down method reverts the applied migrations in reverse order, `n` limits the
+// number of migrations to revert, if it's zero then last applied migration is
+// reverted.
+func (m *Migrator) Down(n int) ([]*Migration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		n = 1
+	}
+
+	var done []*Migration
+	for i := len(status) - 1; i >= 0 && len(done) < n; i-- {
+		mg := status[i]
+		if !mg.Applied {
+			continue
+		}
+		if err = m.apply(mg.Migration, mg.Down, m.Dialect.DeleteSQL(m.Table), mg.Version); err != nil {
+			return done, err
+		}
+		done = append(done, mg.Migration)
+	}
+	if len(done) == 0 {
+		return nil, ErrNoMigration
+	}
+	return done, nil
+}
+
+func (m *Migrator) apply(mg *Migration, script, trackSQL string, args ...interface{}) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(script)) > 0 {
+		if _, err = tx.Exec(script); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migrate: %s: %v", mg, err)
+		}
+	}
+	if _, err = tx.Exec(trackSQL, args...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("migrate: %s: %v", mg, err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) applied() (map[int64]time.Time, error) {
+	if _, err := m.DB.Exec(m.Dialect.CreateTableSQL(m.Table)); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.Query(m.Dialect.SelectSQL(m.Table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+func parseFilename(fname string) (int64, string, bool, error) {
+	var up bool
+	switch {
+	case strings.HasSuffix(fname, upSuffix):
+		fname, up = strings.TrimSuffix(fname, upSuffix), true
+	case strings.HasSuffix(fname, downSuffix):
+		fname = strings.TrimSuffix(fname, downSuffix)
+	default:
+		return 0, "", false, fmt.Errorf("migrate: invalid migration filename '%s'", fname)
+	}
+
+	parts := strings.SplitN(fname, "_", 2)
+	version, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || len(parts) != 2 {
+		return 0, "", false, fmt.Errorf("migrate: invalid migration filename '%s'", fname)
+	}
+	return version, parts[1], up, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateUpDown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aahmigrate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	writeMigration(t, dir, "20181015103000_create_users", "CREATE TABLE users", "DROP TABLE users")
+	writeMigration(t, dir, "20181016103000_create_posts", "CREATE TABLE posts", "DROP TABLE posts")
+	writeMigration(t, dir, "20181017103000_add_index", "CREATE INDEX idx", "DROP INDEX idx")
+
+	db, err := sql.Open("migratetest", "updown")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	m, err := New(db, "sqlite3", dir, osFS{})
+	assert.Nil(t, err)
+
+	migrations, err := m.Migrations()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(migrations))
+	assert.Equal(t, "20181015103000_create_users", migrations[0].String())
+	assert.Equal(t, "DROP TABLE posts", migrations[1].Down)
+
+	done, err := m.Up(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(done))
+
+	status, err := m.Status()
+	assert.Nil(t, err)
+	assert.True(t, status[0].Applied)
+	assert.True(t, status[1].Applied)
+	assert.False(t, status[2].Applied)
+
+	done, err = m.Up(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "add_index", done[0].Name)
+
+	_, err = m.Up(0)
+	assert.Equal(t, ErrNoMigration, err)
+
+	done, err = m.Down(2)
+	assert.Nil(t, err)
+	assert.Equal(t, "add_index", done[0].Name)
+	assert.Equal(t, "create_posts", done[1].Name)
+
+	pending, err := m.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pending))
+
+	assert.Equal(t, []string{
+		"CREATE TABLE users", "CREATE TABLE posts", "CREATE INDEX idx",
+		"DROP INDEX idx", "DROP TABLE posts",
+	}, fakeStore("updown").scripts)
+
+	// failed migration
+	writeMigration(t, dir, "20181018103000_broken", "FAIL", "")
+	done, err = m.Up(0)
+	assert.Equal(t, 2, len(done))
+	assert.Equal(t, "migrate: 20181018103000_broken: fake error", err.Error())
+}
+
+func TestMigrateMisc(t *testing.T) {
+	_, err := New(nil, "unknown", "", osFS{})
+	assert.Equal(t, "migrate: dialect not found: 'unknown'", err.Error())
+
+	err = AddDialect("postgres", &postgresDialect{})
+	assert.NotNil(t, err)
+	err = AddDialect("cockroach", &postgresDialect{})
+	assert.Nil(t, err)
+	assert.NotNil(t, DialectByDriver("cockroach"))
+	assert.Equal(t, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+		DialectByDriver("postgres").InsertSQL(DefaultTable))
+
+	dir, err := ioutil.TempDir("", "aahmigrate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	up, down, err := Create(filepath.Join(dir, "migrations"), "Create Users")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(up, "_create_users.up.sql"))
+	assert.True(t, strings.HasSuffix(down, "_create_users.down.sql"))
+	_, _, err = Create(dir, " ")
+	assert.NotNil(t, err)
+
+	m, err := New(nil, "mysql", filepath.Join(dir, "not-exists"), osFS{})
+	assert.Nil(t, err)
+	migrations, err := m.Migrations()
+	assert.Nil(t, err)
+	assert.Nil(t, migrations)
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "invalid.sql"), []byte(""), 0644)
+	m.Dir = dir
+	_, err = m.Migrations()
+	assert.Equal(t, "migrate: invalid migration filename 'invalid.sql'", err.Error())
+}
+
+func writeMigration(t *testing.T, dir, name, up, down string) {
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+upSuffix), []byte(up), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+downSuffix), []byte(down), 0644))
+}
+
+type osFS struct{}
+
+func (osFS) ReadFile(filename string) ([]byte, error)      { return ioutil.ReadFile(filename) }
+func (osFS) ReadDir(dirname string) ([]os.FileInfo, error) { return ioutil.ReadDir(dirname) }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Fake SQL driver for migration tests
+//______________________________________________________________________________
+
+func init() {
+	sql.Register("migratetest", &fakeDriver{})
+}
+
+var (
+	fakeStores  = make(map[string]*fakeDB)
+	fakeStoreMu sync.Mutex
+)
+
+type fakeDB struct {
+	versions map[int64]time.Time
+	scripts  []string
+}
+
+func fakeStore(name string) *fakeDB {
+	fakeStoreMu.Lock()
+	defer fakeStoreMu.Unlock()
+	if _, found := fakeStores[name]; !found {
+		fakeStores[name] = &fakeDB{versions: make(map[int64]time.Time)}
+	}
+	return fakeStores[name]
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{db: fakeStore(name)}, nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Commit() error             { return nil }
+func (c *fakeConn) Rollback() error           { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	fakeStoreMu.Lock()
+	defer fakeStoreMu.Unlock()
+	switch {
+	case s.query == "FAIL":
+		return nil, errors.New("fake error")
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS"):
+	case strings.HasPrefix(s.query, "INSERT INTO"):
+		s.db.versions[args[0].(int64)] = args[2].(time.Time)
+	case strings.HasPrefix(s.query, "DELETE FROM"):
+		delete(s.db.versions, args[0].(int64))
+	default:
+		s.db.scripts = append(s.db.scripts, s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	fakeStoreMu.Lock()
+	defer fakeStoreMu.Unlock()
+	rows := &fakeRows{}
+	for v, at := range s.db.versions {
+		rows.values = append(rows.values, []driver.Value{v, at})
+	}
+	sort.Slice(rows.values, func(i, j int) bool { return rows.values[i][0].(int64) < rows.values[j][0].(int64) })
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+	idx    int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version", "applied_at"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.idx >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.idx])
+	r.idx++
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigratorConfig(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	a.settings.EnvProfile = "prod"
+
+	m, err := a.Migrator()
+	assert.Nil(t, m)
+	assert.Equal(t, ErrMigrateNotConfigured, err)
+
+	a.cfg.SetString("migrate.driver", "unknown")
+	a.cfg.SetString("migrate.dsn", "unknown")
+	_, err = a.Migrator()
+	assert.NotNil(t, err)
+
+	assert.False(t, a.isMigrateAutoRun())
+	a.cfg.SetBool("migrate.auto_run.enable", true)
+	assert.True(t, a.isMigrateAutoRun())
+
+	cfg, err := config.ParseString(`migrate {
+  auto_run {
+    enable = true
+    profiles = ["dev"]
+  }
+}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	assert.False(t, a.isMigrateAutoRun())
+	a.settings.EnvProfile = "dev"
+	assert.True(t, a.isMigrateAutoRun())
+}
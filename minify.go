@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io"
+	"strings"
+)
+
+// defaultMinifier is the built-in minifier of aah framework, it gets
+// registered by build tag `minify` (refer to `minify_tdewolff.go`).
+var defaultMinifier MinifierFunc
+
+// AddMinifier method appends the given minifier func into the minifier
+// chain of aah framework. Chained minifiers are applied in the order of
+// registration, output of one becomes input to the next.
+//
+// For e.g.:
+// 	aah.App().AddMinifier(htmlMinifier)
+// 	aah.App().AddMinifier(inlineCSSMinifier)
+func (a *Application) AddMinifier(fn MinifierFunc) {
+	if a.viewMgr == nil || a.viewMgr.minifier == nil {
+		a.SetMinifier(fn)
+		return
+	}
+	a.viewMgr.minifier = chainMinifier(a.viewMgr.minifier, fn)
+}
+
+func (a *Application) initMinify() {
+	if !a.settings.MinifyEnabled || defaultMinifier == nil ||
+		(a.viewMgr != nil && a.viewMgr.minifier != nil) {
+		return
+	}
+	a.SetMinifier(defaultMinifier)
+}
+
+// qualifyMinify method returns true if reply content type is configured in
+// `render.minify.types` and minify is enabled for the current route.
+func (e *HTTPEngine) qualifyMinify(ctx *Context) bool {
+	if !e.a.settings.MinifyEnabled || !e.minifyEnabled(ctx) {
+		return false
+	}
+	ct := ctx.Reply().ContType
+	if idx := strings.IndexByte(ct, ';'); idx > 0 {
+		ct = ct[:idx]
+	}
+	ct = strings.TrimSpace(ct)
+	for _, t := range e.a.settings.MinifyTypes {
+		if strings.EqualFold(t, ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// chainMinifier method returns the minifier func, which pipes the output of
+// first into the second one.
+func chainMinifier(first, second MinifierFunc) MinifierFunc {
+	return func(contentType string, w io.Writer, r io.Reader) error {
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+		if err := first(contentType, buf, r); err != nil {
+			return err
+		}
+		return second(contentType, w, buf)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build minify
+
+package aah
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/tdewolff/minify/v2"
+	"github.com/tdewolff/minify/v2/css"
+	"github.com/tdewolff/minify/v2/html"
+	"github.com/tdewolff/minify/v2/js"
+	"github.com/tdewolff/minify/v2/svg"
+)
+
+// Built-in minifier adapter of `github.com/tdewolff/minify` for HTML, CSS,
+// JS and SVG. It is included in the build with tag `minify`, for e.g.:
+// 	go build -tags minify
+//
+// Add the dependency into application `go.mod` before enabling it.
+func init() {
+	m := minify.New()
+	m.AddFunc("text/html", html.Minify)
+	m.AddFunc("text/css", css.Minify)
+	m.AddFunc("image/svg+xml", svg.Minify)
+	m.AddFuncRegexp(regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$"), js.Minify)
+
+	defaultMinifier = func(contentType string, w io.Writer, r io.Reader) error {
+		return m.Minify(contentType, w, r)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinifyChain(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	upper := func(contentType string, w io.Writer, r io.Reader) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes.ToUpper(b))
+		return err
+	}
+	trim := func(contentType string, w io.Writer, r io.Reader) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes.Join(bytes.Fields(b), []byte(" ")))
+		return err
+	}
+
+	a.AddMinifier(upper)
+	a.AddMinifier(trim)
+
+	var buf bytes.Buffer
+	err := a.viewMgr.minifier("text/html", &buf, strings.NewReader("  <p>\n  hello   aah </p>  "))
+	assert.Nil(t, err)
+	assert.Equal(t, "<P> HELLO AAH </P>", buf.String())
+
+	// default minifier is not applied when minifier exists
+	defaultMinifier = trim
+	defer func() { defaultMinifier = nil }()
+	a.SetMinifier(upper)
+	a.initMinify()
+	buf.Reset()
+	assert.Nil(t, a.viewMgr.minifier("text/html", &buf, strings.NewReader(" aah ")))
+	assert.Equal(t, " AAH ", buf.String())
+
+	a.viewMgr.minifier = nil
+	a.initMinify()
+	buf.Reset()
+	assert.Nil(t, a.viewMgr.minifier("text/html", &buf, strings.NewReader(" aah ")))
+	assert.Equal(t, "aah", buf.String())
+}
+
+func TestMinifyQualify(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	ctx.a = a
+	ctx.Reply().ContentType(ahttp.ContentTypeHTML.String())
+
+	// no minifier
+	assert.False(t, a.he.qualifyMinify(ctx))
+
+	a.SetMinifier(func(contentType string, w io.Writer, r io.Reader) error { return nil })
+	assert.Equal(t, []string{"text/html"}, a.settings.MinifyTypes)
+	assert.True(t, a.he.qualifyMinify(ctx))
+
+	ctx.Reply().ContType = ahttp.ContentTypeCSSText.String()
+	assert.False(t, a.he.qualifyMinify(ctx))
+	a.settings.MinifyTypes = append(a.settings.MinifyTypes, "text/css")
+	assert.True(t, a.he.qualifyMinify(ctx))
+
+	ctx.route = &router.Route{DisableMinify: true}
+	assert.False(t, a.he.qualifyMinify(ctx))
+
+	ctx.route = nil
+	a.settings.MinifyEnabled = false
+	assert.False(t, a.he.qualifyMinify(ctx))
+}
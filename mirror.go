@@ -0,0 +1,155 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/router"
+)
+
+// mirrorMaxInFlight is the maximum no. of mirrored requests in-flight,
+// mirroring is skipped beyond that so shadow traffic never piles up.
+const mirrorMaxInFlight = 100
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initMirror() {
+	a.mirrorMgr = &mirrorManager{
+		a: a,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   10 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		inflight: make(chan struct{}, mirrorMaxInFlight),
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Mirror Manager
+//______________________________________________________________________________
+
+type mirrorManager struct {
+	a        *Application
+	client   *http.Client
+	inflight chan struct{}
+}
+
+// Mirror method replays the copy of request to mirror target of the route
+// asynchronously if request is sampled, its response is discarded. Request
+// body is restored for the actual request processing.
+func (mm *mirrorManager) Mirror(ctx *Context) {
+	m := ctx.route.Mirror
+	if m.Sample < 1 && rand.Float64() >= m.Sample {
+		return
+	}
+
+	r := ctx.Req.Unwrap()
+	body, ok := bufferMirrorBody(r, m.MaxBodySize)
+	if !ok {
+		ctx.Log().Debugf("Mirror: request body exceeds the limit, skipped Route: %s, Path: %s", ctx.route.Name, ctx.Req.Path)
+		return
+	}
+
+	select {
+	case mm.inflight <- struct{}{}:
+	default:
+		ctx.Log().Warnf("Mirror: too many in-flight requests, skipped Route: %s, Path: %s", ctx.route.Name, ctx.Req.Path)
+		return
+	}
+
+	req := newMirrorRequest(r, m, body)
+	go func() {
+		defer func() { <-mm.inflight }()
+		goCtx, cancel := context.WithTimeout(context.Background(), m.Timeout)
+		defer cancel()
+		res, err := mm.client.Do(req.WithContext(goCtx))
+		if err != nil {
+			mm.a.Log().Debugf("Mirror: request to %s failed: %s", m.Target, err)
+			return
+		}
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		ess.CloseQuietly(res.Body)
+	}()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type mirrorBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferMirrorBody method reads the request body up to the limit and
+// restores the request body. It returns false if body exceeds the limit.
+func bufferMirrorBody(r *http.Request, limit int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > limit {
+		return nil, false
+	}
+	orig := r.Body
+	buf, err := ioutil.ReadAll(io.LimitReader(orig, limit+1))
+	r.Body = &mirrorBody{Reader: io.MultiReader(bytes.NewReader(buf), orig), Closer: orig}
+	return buf, err == nil && int64(len(buf)) <= limit
+}
+
+func newMirrorRequest(r *http.Request, m *router.Mirror, body []byte) *http.Request {
+	u := *r.URL
+	u.Scheme, u.Host = m.Target.Scheme, m.Target.Host
+	u.Path, u.RawPath = joinURLPath(m.Target.Path, r.URL.Path), ""
+	if len(m.Target.RawQuery) > 0 {
+		u.RawQuery = joinQuery(m.Target.RawQuery, r.URL.RawQuery)
+	}
+
+	req := &http.Request{
+		Method:        r.Method,
+		URL:           &u,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Host:          m.Target.Host,
+		ContentLength: int64(len(body)),
+		Body:          http.NoBody,
+	}
+	if len(body) > 0 {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	req.Header.Set(ahttp.HeaderXForwardedHost, r.Host)
+	return req
+}
+
+func joinQuery(a, b string) string {
+	if len(b) == 0 {
+		return a
+	}
+	return a + "&" + b
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+type mirroredRequest struct {
+	method, path, query, body, host string
+}
+
+func TestMirrorRoute(t *testing.T) {
+	mirrored := make(chan mirroredRequest, 2)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mirrored <- mirroredRequest{r.Method, r.URL.Path, r.URL.RawQuery, string(b),
+			r.Header.Get(ahttp.HeaderXForwardedHost)}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer target.Close()
+	targetURL, _ := url.Parse(target.URL + "/shadow?env=test")
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	domain := ts.app.Router().RootDomain()
+	err := domain.AddRoute(&router.Route{
+		Name:        "mirror_record",
+		Path:        "/mirror-record",
+		Method:      ahttp.MethodPost,
+		Target:      "testSiteController",
+		Action:      "CreateRecord",
+		MaxBodySize: 1024,
+		Mirror:      &router.Mirror{Sample: 1, MaxBodySize: 1024, Timeout: 5 * time.Second, Target: targetURL},
+	})
+	assert.Nil(t, err)
+
+	jsonStr := `{"first_name":"My firstname","number":8253645635463}`
+	req, _ := http.NewRequest(ahttp.MethodPost, ts.URL+"/mirror-record?id=1", strings.NewReader(jsonStr))
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+	result := fireRequest(t, req)
+	assert.Equal(t, 200, result.StatusCode)
+	assert.True(t, strings.Contains(result.Body, "8253645635463"))
+
+	select {
+	case mr := <-mirrored:
+		assert.Equal(t, ahttp.MethodPost, mr.method)
+		assert.Equal(t, "/shadow/mirror-record", mr.path)
+		assert.Equal(t, "env=test&id=1", mr.query)
+		assert.Equal(t, jsonStr, mr.body)
+		assert.True(t, strings.HasPrefix(mr.host, "127.0.0.1"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("mirrored request not received")
+	}
+
+	// body exceeds the limit, not mirrored
+	domain.LookupByName("mirror_record").Mirror.MaxBodySize = 10
+	req, _ = http.NewRequest(ahttp.MethodPost, ts.URL+"/mirror-record", strings.NewReader(jsonStr))
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+	result = fireRequest(t, req)
+	assert.Equal(t, 200, result.StatusCode)
+	assert.True(t, strings.Contains(result.Body, "8253645635463"))
+	select {
+	case <-mirrored:
+		t.Error("request body exceeds the limit, not expected to be mirrored")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestMirrorBufferBody(t *testing.T) {
+	r := httptest.NewRequest(ahttp.MethodPost, "/", strings.NewReader("mirror body content"))
+	r.ContentLength = -1
+	body, ok := bufferMirrorBody(r, 6)
+	assert.False(t, ok)
+	assert.Equal(t, "mirror ", string(body))
+	b, _ := ioutil.ReadAll(r.Body)
+	assert.Equal(t, "mirror body content", string(b))
+
+	r = httptest.NewRequest(ahttp.MethodGet, "/", nil)
+	body, ok = bufferMirrorBody(r, 6)
+	assert.True(t, ok)
+	assert.Nil(t, body)
+}
@@ -0,0 +1,775 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/internal/settings"
+	"aahframe.work/router"
+	"aahframe.work/security/scheme"
+	"aahframe.work/valpar"
+)
+
+const (
+	openAPIVersion        = "3.0.3"
+	openAPITarget         = "openAPIController"
+	openAPISpecRouteName  = "openapi_spec__aah"
+	openAPIUIRouteName    = "openapi_ui__aah"
+	openAPIDefaultPath    = "/openapi.json"
+	openAPIDefaultUIPath  = "/swagger"
+	openAPIValidateTag    = "validate"
+	openAPIComponentsPath = "#/components/schemas/"
+	openAPIExampleTag     = "example"
+	openAPIExampleTime    = "2006-01-02T15:04:05Z"
+
+	openAPIExampleMaxDepth = 5
+)
+
+var (
+	// ErrOpenAPIDomainNotFound returned when domain not found for given host
+	// while generating OpenAPI spec.
+	ErrOpenAPIDomainNotFound = errors.New("aah: openapi domain not found")
+
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+)
+
+// OpenAPISpec method generates the OpenAPI 3 specification in JSON format
+// for the given host domain. If host is empty then root domain is used.
+//
+// Specification is composed from routes of the domain, controller action
+// parameters with its types, validation tags (`validate`) and action doc
+// comments. Framework added routes, static and WebSocket routes are excluded.
+//
+// Configuration goes under `openapi { ... }` in the `aah.conf`.
+//
+// 	openapi {
+// 	  # default value is false
+// 	  enable = true
+//
+// 	  # default value is "/openapi.json"
+// 	  path = "/openapi.json"
+//
+// 	  # default values are app name, app version and app description
+// 	  title = "My API"
+// 	  version = "1.0.0"
+// 	  description = "My API description"
+//
+// 	  ui {
+// 	    # Swagger UI route, default value is true for `dev` env profile
+// 	    enable = true
+//
+// 	    # default value is "/swagger"
+// 	    path = "/swagger"
+// 	  }
+// 	}
+//
+// Also available via app binary command `<app-binary> openapi`.
+func (a *Application) OpenAPISpec(host string) ([]byte, error) {
+	var domain *router.Domain
+	if len(host) == 0 {
+		if domain = a.Router().RootDomain(); domain == nil && len(a.Router().Domains) > 0 {
+			domain = a.Router().Domains[0]
+		}
+	} else {
+		domain = a.Router().Lookup(host)
+	}
+	if domain == nil {
+		return nil, ErrOpenAPIDomainNotFound
+	}
+
+	cfg := a.Config()
+	doc := &openAPIDoc{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:       cfg.StringDefault("openapi.title", a.Name()),
+			Version:     cfg.StringDefault("openapi.version", a.openAPIAppVersion()),
+			Description: cfg.StringDefault("openapi.description", a.Desc()),
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+	}
+	sg := &openAPISchemaGen{schemas: make(map[string]*openAPISchema), pkgPaths: make(map[string]string)}
+	securitySchemes := make(map[string]*openAPISecurityScheme)
+
+	for _, route := range domain.Routes() {
+		if route.IsStatic || route.IsProxy() || route.IsFramework() || route.Method == "WS" || route.Method == "*" {
+			continue
+		}
+
+		op := &openAPIOperation{OperationID: route.Name, Tags: []string{route.Target}}
+		var action *ainsp.Method
+		if target := a.HTTPEngine().registry.Lookup(route.Target); target != nil {
+			op.Tags = []string{target.NoSuffixName}
+			action = target.Lookup(route.Action)
+		}
+		if action != nil && len(action.Doc) > 0 {
+			op.Summary, op.Description = splitDoc(action.Doc)
+		}
+
+		specPath, pathParams := openAPIPath(route.Path)
+		for _, name := range pathParams {
+			op.Parameters = append(op.Parameters, &openAPIParameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   constraintSchema(route.Constraints[name]),
+			})
+		}
+
+		if action != nil {
+			for _, p := range action.Parameters {
+				if _, found := a.contextualBinder(p.Type); found {
+					continue // not a request parameter
+				}
+				if idx := openAPIParamIndex(op.Parameters, p.Name); idx > -1 {
+					op.Parameters[idx].Schema = applyValidation(sg.schema(p.Type), route.Constraints[p.Name])
+					continue
+				}
+				sg.addActionParameter(op, route.Method, p.Type, p.Name)
+			}
+			if action.Request != nil && op.RequestBody == nil {
+				op.RequestBody = &openAPIRequestBody{
+					Required: true,
+					Content:  sg.content(action.Request),
+				}
+			}
+		}
+
+		if authScheme := a.SecurityManager().AuthScheme(route.Auth); authScheme != nil {
+			if ss := openAPISecuritySchemeOf(authScheme); ss != nil {
+				securitySchemes[route.Auth] = ss
+				op.Security = []map[string][]string{{route.Auth: {}}}
+			}
+		}
+
+		op.Responses = make(map[string]*openAPIResponse)
+		if action != nil {
+			for _, res := range action.Responses {
+				r := &openAPIResponse{Description: res.Description}
+				if len(r.Description) == 0 {
+					r.Description = http.StatusText(res.Code)
+				}
+				if res.Type != nil {
+					r.Content = sg.content(res.Type)
+				}
+				op.Responses[strconv.Itoa(res.Code)] = r
+			}
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["200"] = &openAPIResponse{Description: "Successful response"}
+		}
+
+		if _, found := doc.Paths[specPath]; !found {
+			doc.Paths[specPath] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[specPath][strings.ToLower(route.Method)] = op
+	}
+
+	if len(sg.schemas) > 0 || len(securitySchemes) > 0 {
+		doc.Components = &openAPIComponents{}
+		if len(sg.schemas) > 0 {
+			doc.Components.Schemas = sg.schemas
+		}
+		if len(securitySchemes) > 0 {
+			doc.Components.SecuritySchemes = securitySchemes
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// OpenAPI controller
+//______________________________________________________________________________
+
+// openAPIController serves the OpenAPI spec and Swagger UI routes.
+type openAPIController struct {
+	*Context
+}
+
+// Spec method serves the OpenAPI spec for request host domain.
+func (c *openAPIController) Spec() {
+	spec, err := c.a.OpenAPISpec(c.Req.Host)
+	if err != nil {
+		c.Log().Error(err)
+		c.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+		return
+	}
+	c.Reply().ContentType(ahttp.ContentTypeJSON.String()).Binary(spec)
+}
+
+// UI method serves the Swagger UI page for OpenAPI spec.
+func (c *openAPIController) UI() {
+	c.Reply().ContentType(ahttp.ContentTypeHTML.String()).
+		Text(openAPIUIPage, template.HTMLEscapeString(c.a.Name()), c.a.Config().StringDefault("openapi.path", openAPIDefaultPath))
+}
+
+const openAPIUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>%s - API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>window.ui = SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// OpenAPI document types
+//______________________________________________________________________________
+
+type openAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components *openAPIComponents                      `json:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema  *openAPISchema `json:"schema"`
+	Example interface{}    `json:"example,omitempty"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// OpenAPI schema generator
+//______________________________________________________________________________
+
+// openAPISchemaGen generates the schema for Go types, struct types are
+// registered into components and referred via `$ref`.
+type openAPISchemaGen struct {
+	schemas  map[string]*openAPISchema
+	pkgPaths map[string]string
+}
+
+func (sg *openAPISchemaGen) addActionParameter(op *openAPIOperation, method string, t reflect.Type, name string) {
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	if _, found := valpar.OptionalValueType(st); !found && st.Kind() == reflect.Struct &&
+		st != timeType && st != fileHeaderType {
+		switch method {
+		case ahttp.MethodPost, ahttp.MethodPut, ahttp.MethodPatch, ahttp.MethodDelete:
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content: map[string]*openAPIMediaType{
+					ahttp.ContentTypeJSON.Mime: {Schema: sg.schema(t)},
+				},
+			}
+		default:
+			// struct fields are bound from query parameters
+			for i := 0; i < st.NumField(); i++ {
+				f := st.Field(i)
+				if len(f.PkgPath) > 0 {
+					continue
+				}
+				pname := f.Name
+				if tag := f.Tag.Get(valpar.StructTagName); len(tag) > 0 && len(valpar.StructTagName) > 0 {
+					pname = strings.Split(tag, ",")[0]
+				}
+				if pname == "-" {
+					continue
+				}
+				vtag := f.Tag.Get(openAPIValidateTag)
+				op.Parameters = append(op.Parameters, &openAPIParameter{
+					Name:     pname,
+					In:       "query",
+					Required: isRequiredRule(vtag),
+					Schema:   applyValidation(sg.schema(f.Type), vtag),
+				})
+			}
+		}
+		return
+	}
+
+	if st == fileHeaderType {
+		schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{name: sg.schema(t)}}
+		op.RequestBody = &openAPIRequestBody{
+			Content: map[string]*openAPIMediaType{ahttp.ContentTypeMultipartForm.Mime: {Schema: schema}},
+		}
+		return
+	}
+
+	op.Parameters = append(op.Parameters, &openAPIParameter{
+		Name:   name,
+		In:     "query",
+		Schema: sg.schema(t),
+	})
+}
+
+// content method returns JSON media type content with schema and example
+// payload for given type.
+func (sg *openAPISchemaGen) content(t reflect.Type) map[string]*openAPIMediaType {
+	return map[string]*openAPIMediaType{
+		ahttp.ContentTypeJSON.Mime: {Schema: sg.schema(t), Example: openAPIExample(t, 0)},
+	}
+}
+
+func (sg *openAPISchemaGen) schema(t reflect.Type) *openAPISchema {
+	t = derefType(t)
+	if vt, found := valpar.OptionalValueType(t); found {
+		t = vt
+	}
+
+	switch t {
+	case timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case fileHeaderType:
+		return &openAPISchema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: sg.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: sg.schema(t.Elem())}
+	case reflect.Struct:
+		return sg.structSchema(t)
+	}
+	return &openAPISchema{}
+}
+
+func (sg *openAPISchemaGen) structSchema(t reflect.Type) *openAPISchema {
+	name := t.Name()
+	if len(name) == 0 { // anonymous struct
+		return sg.fillStructSchema(&openAPISchema{Type: "object"}, t)
+	}
+
+	// same type name from different packages
+	if p, found := sg.pkgPaths[name]; found && p != t.PkgPath() {
+		name = strings.Replace(t.PkgPath(), "/", "_", -1) + "_" + name
+	}
+	ref := &openAPISchema{Ref: openAPIComponentsPath + name}
+	if _, found := sg.schemas[name]; found {
+		return ref
+	}
+
+	// register before fill to handle recursive types
+	schema := &openAPISchema{Type: "object"}
+	sg.schemas[name] = schema
+	sg.pkgPaths[name] = t.PkgPath()
+	sg.fillStructSchema(schema, t)
+	return ref
+}
+
+func (sg *openAPISchemaGen) fillStructSchema(schema *openAPISchema, t reflect.Type) *openAPISchema {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && len(f.Tag.Get("json")) == 0 {
+			sg.fillStructSchema(schema, ft)
+			continue
+		}
+
+		name := jsonFieldName(f)
+		if name == "-" {
+			continue
+		}
+
+		vtag := f.Tag.Get(openAPIValidateTag)
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]*openAPISchema)
+		}
+		schema.Properties[name] = applyValidation(sg.schema(f.Type), vtag)
+		if isRequiredRule(vtag) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// addOpenAPIRoutes method adds OpenAPI spec and Swagger UI routes into all
+// domains if it's enabled.
+func (a *Application) addOpenAPIRoutes(rtr *router.Router) error {
+	cfg := a.Config()
+	if !cfg.BoolDefault("openapi.enable", false) {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(openAPITarget) == nil {
+		a.AddController((*openAPIController)(nil), []*ainsp.Method{{Name: "Spec"}, {Name: "UI"}})
+	}
+
+	uiEnabled := cfg.BoolDefault("openapi.ui.enable", a.IsEnvProfile(settings.DefaultEnvProfile))
+	for _, d := range rtr.Domains {
+		if err := d.AddRoute(&router.Route{
+			Name:   openAPISpecRouteName,
+			Path:   cfg.StringDefault("openapi.path", openAPIDefaultPath),
+			Method: ahttp.MethodGet,
+			Target: openAPITarget,
+			Action: "Spec",
+			Auth:   "anonymous",
+
+			DisableCompress: !d.Compress,
+		}); err != nil {
+			return err
+		}
+		if uiEnabled {
+			if err := d.AddRoute(&router.Route{
+				Name:   openAPIUIRouteName,
+				Path:   cfg.StringDefault("openapi.ui.path", openAPIDefaultUIPath),
+				Method: ahttp.MethodGet,
+				Target: openAPITarget,
+				Action: "UI",
+				Auth:   "anonymous",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Application) openAPIAppVersion() string {
+	if a.buildInfo != nil && len(a.buildInfo.Version) > 0 {
+		return a.buildInfo.Version
+	}
+	return "1.0.0"
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// openAPIPath method converts the route path into OpenAPI path template and
+// returns path parameter names.
+// For e.g.: /users/:id/*filepath => /users/{id}/{filepath}
+func openAPIPath(routePath string) (string, []string) {
+	var params []string
+	parts := strings.Split(routePath, "/")
+	for i, p := range parts {
+		if len(p) > 1 && (p[0] == ':' || p[0] == '*') {
+			params = append(params, p[1:])
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func openAPIParamIndex(params []*openAPIParameter, name string) int {
+	for i, p := range params {
+		if p.In == "path" && p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func openAPISecuritySchemeOf(authScheme scheme.Schemer) *openAPISecurityScheme {
+	switch s := authScheme.(type) {
+	case *scheme.BasicAuth:
+		return &openAPISecurityScheme{Type: "http", Scheme: "basic"}
+	case *scheme.GenericAuth:
+		return &openAPISecurityScheme{Type: "apiKey", In: "header", Name: s.IdentityHeader}
+	}
+	return nil
+}
+
+// constraintSchema method returns schema for route path parameter constraint.
+func constraintSchema(rules string) *openAPISchema {
+	return applyValidation(&openAPISchema{Type: "string"}, rules)
+}
+
+// applyValidation method applies the validation rules into schema, it
+// supports commonly used rules of `go-playground/validator`.
+func applyValidation(schema *openAPISchema, rules string) *openAPISchema {
+	if len(rules) == 0 || len(schema.Ref) > 0 {
+		return schema
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			break
+		}
+		name, value := rule, ""
+		if idx := strings.IndexByte(rule, '='); idx > 0 {
+			name, value = rule[:idx], rule[idx+1:]
+		}
+		switch name {
+		case "number", "numeric":
+			if schema.Type == "string" {
+				schema.Type = "number"
+			}
+		case "email", "uuid", "uri", "url", "ipv4", "ipv6", "hostname":
+			schema.Format = map[string]string{"url": "uri"}[name]
+			if len(schema.Format) == 0 {
+				schema.Format = name
+			}
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		case "min", "gte", "max", "lte", "len":
+			applyLimit(schema, name, value)
+		}
+	}
+	return schema
+}
+
+func applyLimit(schema *openAPISchema, name, value string) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	isMin := name == "min" || name == "gte" || name == "len"
+	isMax := name == "max" || name == "lte" || name == "len"
+	n := int(f)
+	switch schema.Type {
+	case "string":
+		if isMin {
+			schema.MinLength = &n
+		}
+		if isMax {
+			schema.MaxLength = &n
+		}
+	case "array":
+		if isMin {
+			schema.MinItems = &n
+		}
+		if isMax {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		if isMin {
+			schema.Minimum = &f
+		}
+		if isMax {
+			schema.Maximum = &f
+		}
+	}
+}
+
+func isRequiredRule(rules string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitDoc method splits the doc comment into summary (first sentence line)
+// and description.
+func splitDoc(doc string) (string, string) {
+	if idx := strings.IndexByte(doc, '\n'); idx > 0 {
+		return strings.TrimSpace(doc[:idx]), strings.TrimSpace(doc[idx+1:])
+	}
+	return doc, ""
+}
+
+// openAPIExample method generates the example payload for given type. Struct
+// field value can be supplied via `example` tag, otherwise zero-like sample
+// values are used.
+func openAPIExample(t reflect.Type, depth int) interface{} {
+	t = derefType(t)
+	if vt, found := valpar.OptionalValueType(t); found {
+		t = vt
+	}
+	if depth > openAPIExampleMaxDepth {
+		return nil
+	}
+
+	switch t {
+	case timeType:
+		return openAPIExampleTime
+	case fileHeaderType:
+		return "binary"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return 0
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "Ynl0ZXM="
+		}
+		if e := openAPIExample(t.Elem(), depth+1); e != nil {
+			return []interface{}{e}
+		}
+		return []interface{}{}
+	case reflect.Map:
+		if e := openAPIExample(t.Elem(), depth+1); e != nil {
+			return map[string]interface{}{"key": e}
+		}
+		return map[string]interface{}{}
+	case reflect.Struct:
+		example := make(map[string]interface{})
+		openAPIStructExample(example, t, depth)
+		return example
+	}
+	return nil
+}
+
+func openAPIStructExample(example map[string]interface{}, t reflect.Type, depth int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+		ft := derefType(f.Type)
+		if f.Anonymous && ft.Kind() == reflect.Struct && len(f.Tag.Get("json")) == 0 {
+			openAPIStructExample(example, ft, depth)
+			continue
+		}
+		name := jsonFieldName(f)
+		if name == "-" {
+			continue
+		}
+		if v, found := f.Tag.Lookup(openAPIExampleTag); found {
+			example[name] = exampleTagValue(ft, v)
+			continue
+		}
+		if e := openAPIExample(f.Type, depth+1); e != nil {
+			example[name] = e
+		}
+	}
+}
+
+// exampleTagValue method converts the `example` tag value as per field type.
+func exampleTagValue(t reflect.Type, v string) interface{} {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// jsonFieldName method returns the JSON name of struct field, returns "-" if
+// field is skipped in JSON.
+func jsonFieldName(f reflect.StructField) string {
+	if tag := f.Tag.Get("json"); len(tag) > 0 {
+		if tag = strings.Split(tag, ",")[0]; len(tag) > 0 {
+			return tag
+		}
+	}
+	return f.Name
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+var timeType = reflect.TypeOf(time.Time{})
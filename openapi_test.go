@@ -0,0 +1,157 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	ts.app.Config().SetBool("openapi.enable", true)
+	ts.app.Config().SetString("openapi.title", "webapp1 API")
+	err := ts.app.initRouter()
+	assert.Nil(t, err)
+
+	spec, err := ts.app.OpenAPISpec("")
+	assert.Nil(t, err)
+
+	var doc map[string]interface{}
+	err = json.Unmarshal(spec, &doc)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Equal(t, "webapp1 API", doc["info"].(map[string]interface{})["title"])
+	assert.Equal(t, "1.0.0", doc["info"].(map[string]interface{})["version"])
+
+	paths := doc["paths"].(map[string]interface{})
+	assert.NotNil(t, paths["/"])
+	assert.Nil(t, paths["/openapi.json"])
+	assert.Nil(t, paths["/assets/*filepath"])
+
+	createRecord := paths["/create-record"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.Equal(t, "create_record", createRecord["operationId"])
+	assert.Equal(t, []interface{}{"testSite"}, createRecord["tags"])
+	assert.True(t, strings.Contains(string(spec), `"$ref": "#/components/schemas/sampleJSON"`))
+	assert.True(t, strings.Contains(string(spec), `"first_name": {`))
+
+	// typed responses and request DTO
+	type recordDTO struct {
+		ID    int64  `json:"id" example:"42"`
+		Title string `json:"title"`
+	}
+	err = ts.app.AddResponseType((*testSiteController)(nil), "CreateRecord", http.StatusCreated, &recordDTO{})
+	assert.Nil(t, err)
+	err = ts.app.AddRequestType((*testSiteController)(nil), "Text", &recordDTO{})
+	assert.Nil(t, err)
+	err = ts.app.AddResponseType((*testSiteController)(nil), "NotExists", http.StatusOK, &recordDTO{})
+	assert.Equal(t, "aah: action 'testSiteController.NotExists' not found", err.Error())
+	err = ts.app.AddResponseType((*sample)(nil), "Index", http.StatusOK, &recordDTO{})
+	assert.Equal(t, "aah: controller '*aah.sample' not found", err.Error())
+
+	spec, err = ts.app.OpenAPISpec("")
+	assert.Nil(t, err)
+	doc = nil
+	err = json.Unmarshal(spec, &doc)
+	assert.Nil(t, err)
+	paths = doc["paths"].(map[string]interface{})
+	createRecord = paths["/create-record"].(map[string]interface{})["post"].(map[string]interface{})
+	created := createRecord["responses"].(map[string]interface{})["201"].(map[string]interface{})
+	assert.Equal(t, "Created", created["description"])
+	media := created["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	assert.Equal(t, "#/components/schemas/recordDTO", media["schema"].(map[string]interface{})["$ref"])
+	assert.Equal(t, map[string]interface{}{"id": float64(42), "title": "string"}, media["example"])
+	assert.Nil(t, createRecord["responses"].(map[string]interface{})["200"])
+
+	for _, op := range paths["/get-text.html"].(map[string]interface{}) {
+		assert.NotNil(t, op.(map[string]interface{})["requestBody"])
+	}
+
+	jsonp := paths["/get-jsonp"].(map[string]interface{})["get"].(map[string]interface{})
+	params := jsonp["parameters"].([]interface{})
+	assert.Equal(t, "callback", params[0].(map[string]interface{})["name"])
+	assert.Equal(t, "query", params[0].(map[string]interface{})["in"])
+
+	// spec and UI routes
+	resp, err := http.Get(ts.URL + "/openapi.json")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"))
+
+	resp, err = http.Get(ts.URL + "/swagger")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html"))
+}
+
+func TestOpenAPISchema(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type user struct {
+		ID        int64             `json:"id"`
+		Name      string            `json:"name" validate:"required,min=2,max=50"`
+		Email     string            `json:"email" validate:"required,email"`
+		Age       int               `json:"age" validate:"gte=18,lte=130"`
+		Role      string            `json:"role" validate:"oneof=admin user"`
+		Tags      []string          `json:"tags" validate:"max=5,dive,min=1"`
+		Address   *address          `json:"address"`
+		Meta      map[string]string `json:"meta"`
+		CreatedAt time.Time         `json:"created_at"`
+		Parent    *user             `json:"parent"`
+		Secret    string            `json:"-"`
+		internal  string
+	}
+
+	sg := &openAPISchemaGen{schemas: make(map[string]*openAPISchema), pkgPaths: make(map[string]string)}
+	ref := sg.schema(reflect.TypeOf(&user{}))
+	assert.Equal(t, "#/components/schemas/user", ref.Ref)
+
+	s := sg.schemas["user"]
+	assert.Equal(t, []string{"name", "email"}, s.Required)
+	assert.Equal(t, 10, len(s.Properties))
+	assert.Equal(t, "integer", s.Properties["id"].Type)
+	assert.Equal(t, "int64", s.Properties["id"].Format)
+	assert.Equal(t, 2, *s.Properties["name"].MinLength)
+	assert.Equal(t, 50, *s.Properties["name"].MaxLength)
+	assert.Equal(t, "email", s.Properties["email"].Format)
+	assert.Equal(t, float64(18), *s.Properties["age"].Minimum)
+	assert.Equal(t, float64(130), *s.Properties["age"].Maximum)
+	assert.Equal(t, []string{"admin", "user"}, s.Properties["role"].Enum)
+	assert.Equal(t, 5, *s.Properties["tags"].MaxItems)
+	assert.Nil(t, s.Properties["tags"].MinItems)
+	assert.Equal(t, "#/components/schemas/address", s.Properties["address"].Ref)
+	assert.Equal(t, "string", s.Properties["meta"].AdditionalProperties.Type)
+	assert.Equal(t, "date-time", s.Properties["created_at"].Format)
+	assert.Equal(t, "#/components/schemas/user", s.Properties["parent"].Ref)
+	assert.Equal(t, []string{"city"}, sg.schemas["address"].Required)
+
+	example := openAPIExample(reflect.TypeOf(&user{}), 0).(map[string]interface{})
+	assert.Equal(t, "string", example["name"])
+	assert.Equal(t, []interface{}{"string"}, example["tags"])
+	assert.Equal(t, openAPIExampleTime, example["created_at"])
+	assert.Equal(t, map[string]interface{}{"city": "string"}, example["address"])
+	assert.Nil(t, example["Secret"])
+
+	p, params := openAPIPath("/users/:id/files/*filepath")
+	assert.Equal(t, "/users/{id}/files/{filepath}", p)
+	assert.Equal(t, []string{"id", "filepath"}, params)
+
+	assert.Equal(t, "number", constraintSchema("required,number").Type)
+
+	summary, desc := splitDoc("List users.\nReturns paginated users list.")
+	assert.Equal(t, "List users.", summary)
+	assert.Equal(t, "Returns paginated users list.", desc)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"aahframe.work/aruntime"
+)
+
+// PanicReporterFunc func type is used to report the recovered panic to the
+// error trackers, for e.g.: Sentry, Rollbar, etc. Context is nil if panic is
+// not occurred in the request flow, for e.g.: supervised worker, broker
+// message handler.
+type PanicReporterFunc func(ctx *Context, err interface{}, stack []byte)
+
+// SetPanicReporter method sets the panic reporter, it's called on every
+// recovered panic along with request metadata and stack trace. Reporter is
+// called synchronously, send the report in the background to avoid blocking
+// the request.
+//
+// aah ships Sentry adapter, refer to `NewSentryReporter`.
+//
+// 	func init() {
+// 		sr, err := aah.NewSentryReporter("https://<key>@sentry.io/<project>")
+// 		if err != nil {
+// 			log.Fatal(err)
+// 		}
+// 		aah.App().SetPanicReporter(sr.Report)
+// 	}
+func (a *Application) SetPanicReporter(fn PanicReporterFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.panicReporter = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// reportPanic method calls the panic reporter if it's set, panic in the
+// reporter is recovered and logged.
+func (a *Application) reportPanic(ctx *Context, r interface{}, st *aruntime.Stacktrace) {
+	a.RLock()
+	fn := a.panicReporter
+	a.RUnlock()
+	if fn == nil {
+		return
+	}
+
+	defer func() {
+		if rr := recover(); rr != nil {
+			a.Log().Errorf("Panic reporter: recovered from panic: %v", rr)
+		}
+	}()
+	fn(ctx, r, []byte(st.Raw))
+}
@@ -0,0 +1,279 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+)
+
+// ErrSentryInvalidDSN returned when Sentry DSN is not valid.
+var ErrSentryInvalidDSN = errors.New("aah: invalid sentry dsn")
+
+// SentryReporter sends the recovered panic as an event to the Sentry via
+// HTTP store API, it has no external dependency. Event is sent in the
+// background with request metadata (URL, method, headers except
+// `Authorization` and `Cookie`, client IP), request ID, route name and
+// authenticated subject principal.
+//
+// 	sr, err := aah.NewSentryReporter("https://<key>@sentry.io/<project>")
+// 	if err != nil {
+// 		log.Fatal(err)
+// 	}
+// 	sr.Environment = aah.App().EnvProfile()
+// 	sr.Release = aah.App().BuildInfo().Version
+// 	aah.App().SetPanicReporter(sr.Report)
+type SentryReporter struct {
+	Environment string
+	Release     string
+	ServerName  string
+	Tags        map[string]string
+
+	// Client is used to send the event, default timeout is 10 seconds.
+	Client *http.Client
+
+	storeURL string
+	auth     string
+}
+
+// NewSentryReporter method creates the Sentry reporter for the given DSN,
+// for e.g.: `https://<key>@sentry.io/<project>`.
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || len(u.User.Username()) == 0 || len(u.Host) == 0 {
+		return nil, ErrSentryInvalidDSN
+	}
+	idx := strings.LastIndexByte(u.Path, '/')
+	project := u.Path[idx+1:]
+	if idx == -1 || len(project) == 0 {
+		return nil, ErrSentryInvalidDSN
+	}
+
+	sr := &SentryReporter{
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		storeURL: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, u.Path[:idx], project),
+		auth:     "Sentry sentry_version=7, sentry_client=aah/" + Version + ", sentry_key=" + u.User.Username(),
+	}
+	if secret, found := u.User.Password(); found {
+		sr.auth += ", sentry_secret=" + secret
+	}
+	sr.ServerName, _ = os.Hostname()
+	return sr, nil
+}
+
+// Report method composes the Sentry event and sends it in the background,
+// it's `PanicReporterFunc` compliant.
+func (sr *SentryReporter) Report(ctx *Context, err interface{}, stack []byte) {
+	b, er := json.Marshal(sr.event(ctx, err, stack))
+	if er != nil {
+		log.Errorf("Sentry: unable to compose event: %v", er)
+		return
+	}
+	go sr.send(b)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Sentry unexported types and methods
+//______________________________________________________________________________
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Message     string            `json:"message"`
+	Exception   *sentryExceptions `json:"exception"`
+	Request     *sentryRequest    `json:"request,omitempty"`
+	User        map[string]string `json:"user,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []*sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []*sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryRequest struct {
+	URL         string            `json:"url"`
+	Method      string            `json:"method"`
+	QueryString string            `json:"query_string,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+}
+
+func (sr *SentryReporter) event(ctx *Context, err interface{}, stack []byte) *sentryEvent {
+	value := fmt.Sprintf("%v", err)
+	se := &sentryEvent{
+		EventID:     sentryEventID(),
+		Timestamp:   time.Now().UTC().Format("2006-01-02T15:04:05"),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "aah",
+		ServerName:  sr.ServerName,
+		Release:     sr.Release,
+		Environment: sr.Environment,
+		Message:     value,
+		Exception: &sentryExceptions{Values: []*sentryException{{
+			Type:       fmt.Sprintf("%T", err),
+			Value:      value,
+			Stacktrace: &sentryStacktrace{Frames: parseSentryFrames(stack)},
+		}}},
+		Tags:  make(map[string]string),
+		Extra: map[string]string{"stacktrace": string(stack)},
+	}
+	for k, v := range sr.Tags {
+		se.Tags[k] = v
+	}
+	if ctx == nil || ctx.Req == nil {
+		return se
+	}
+
+	req := ctx.Req
+	se.Request = &sentryRequest{
+		URL:         req.Scheme + "://" + req.Host + req.Path,
+		Method:      req.Method,
+		QueryString: req.URL().RawQuery,
+		Headers:     make(map[string]string),
+		Env:         map[string]string{"REMOTE_ADDR": req.ClientIP()},
+	}
+	for k, v := range req.Header {
+		if k == ahttp.HeaderAuthorization || k == ahttp.HeaderCookie {
+			continue
+		}
+		se.Request.Headers[k] = strings.Join(v, ", ")
+	}
+	if ctx.a != nil {
+		if id := req.Header.Get(ctx.a.settings.RequestIDHeaderKey); len(id) > 0 {
+			se.Tags["request_id"] = id
+		}
+	}
+	if ctx.route != nil {
+		se.Tags["route"] = ctx.route.Name
+	}
+	if ctx.subject != nil && ctx.subject.IsAuthenticated() {
+		if p := ctx.subject.PrimaryPrincipal(); p != nil {
+			se.User = map[string]string{"id": p.Value}
+		}
+	}
+	return se
+}
+
+func (sr *SentryReporter) send(b []byte) {
+	req, err := http.NewRequest(ahttp.MethodPost, sr.storeURL, bytes.NewReader(b))
+	if err != nil {
+		log.Errorf("Sentry: %v", err)
+		return
+	}
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.Mime)
+	req.Header.Set("X-Sentry-Auth", sr.auth)
+	resp, err := sr.Client.Do(req)
+	if err != nil {
+		log.Errorf("Sentry: unable to send event: %v", err)
+		return
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		log.Errorf("Sentry: unable to send event, status: %s", resp.Status)
+	}
+}
+
+// parseSentryFrames method parses the first goroutine stack of Go debug
+// stack into Sentry frames, oldest frame first.
+func parseSentryFrames(stack []byte) []*sentryFrame {
+	var frames []*sentryFrame
+	var fn string
+	scanner := bufio.NewScanner(bytes.NewReader(stack))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			break // end of first goroutine
+		}
+		if !strings.HasPrefix(line, "\t") {
+			if strings.HasPrefix(line, "goroutine ") {
+				continue
+			}
+			fn = strings.TrimPrefix(line, "created by ")
+			if idx := strings.LastIndexByte(fn, '('); idx > 0 && strings.HasSuffix(fn, ")") {
+				fn = fn[:idx]
+			}
+			continue
+		}
+
+		// file line, for e.g.: `/path/to/file.go:123 +0x1d`
+		loc := strings.TrimSpace(line)
+		if idx := strings.LastIndex(loc, " +0x"); idx > 0 {
+			loc = loc[:idx]
+		}
+		idx := strings.LastIndexByte(loc, ':')
+		if idx == -1 || len(fn) == 0 {
+			continue
+		}
+		lineNo, _ := strconv.Atoi(loc[idx+1:])
+		f := &sentryFrame{Function: fn, Filename: loc[:idx], Lineno: lineNo}
+		if i := strings.LastIndexByte(fn, '/'); i > 0 {
+			if j := strings.IndexByte(fn[i:], '.'); j > 0 {
+				f.Module, f.Function = fn[:i+j], fn[i+j+1:]
+			}
+		} else if j := strings.IndexByte(fn, '.'); j > 0 {
+			f.Module, f.Function = fn[:j], fn[j+1:]
+		}
+		f.InApp = !strings.HasPrefix(f.Module, "runtime") && !strings.HasPrefix(f.Module, "aahframe.work")
+		frames = append(frames, f)
+		fn = ""
+	}
+
+	// Sentry expects oldest frame first
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+func sentryEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
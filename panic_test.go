@@ -0,0 +1,103 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/aruntime"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicReporter(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString("")
+	assert.Nil(t, a.initLog())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	st := aruntime.NewStacktrace("boom", a.Config())
+
+	// no reporter
+	a.reportPanic(nil, "boom", st)
+
+	var gotErr interface{}
+	var gotStack []byte
+	var gotCtx *Context
+	a.SetPanicReporter(func(ctx *Context, err interface{}, stack []byte) {
+		gotCtx, gotErr, gotStack = ctx, err, stack
+	})
+	ctx := newContext(nil, httptest.NewRequest(ahttp.MethodGet, "http://localhost/users?id=1", nil))
+	a.reportPanic(ctx, "boom", st)
+	assert.Equal(t, ctx, gotCtx)
+	assert.Equal(t, "boom", gotErr)
+	assert.True(t, len(gotStack) > 0)
+
+	// panic in reporter is recovered
+	a.SetPanicReporter(func(ctx *Context, err interface{}, stack []byte) {
+		panic("reporter panic")
+	})
+	a.reportPanic(nil, "boom", st)
+}
+
+func TestPanicSentryReporter(t *testing.T) {
+	for _, dsn := range []string{"", "https://sentry.io/1", "https://key@sentry.io/", "://key@sentry.io/1"} {
+		_, err := NewSentryReporter(dsn)
+		assert.Equal(t, ErrSentryInvalidDSN, err, dsn)
+	}
+
+	events := make(chan *sentryEvent, 1)
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("X-Sentry-Auth")
+		assert.Equal(t, "/prefix/api/42/store/", r.URL.Path)
+		se := &sentryEvent{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(se))
+		events <- se
+	}))
+	defer ts.Close()
+
+	sr, err := NewSentryReporter("http://public:secret@" + ts.Listener.Addr().String() + "/prefix/42")
+	assert.Nil(t, err)
+	sr.Environment = "prod"
+	sr.Release = "1.0.0"
+	sr.Tags = map[string]string{"region": "us-east"}
+
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost/users?id=1", nil)
+	req.Header.Set(ahttp.HeaderAuthorization, "Bearer token")
+	req.Header.Set(ahttp.HeaderAccept, ahttp.ContentTypeJSON.Mime)
+	ctx := newContext(nil, req)
+	sr.Report(ctx, "boom", debug.Stack())
+
+	select {
+	case se := <-events:
+		assert.Contains(t, auth, "sentry_key=public")
+		assert.Contains(t, auth, "sentry_secret=secret")
+		assert.Equal(t, 32, len(se.EventID))
+		assert.Equal(t, "boom", se.Message)
+		assert.Equal(t, "prod", se.Environment)
+		assert.Equal(t, "1.0.0", se.Release)
+		assert.Equal(t, "us-east", se.Tags["region"])
+		assert.Equal(t, "string", se.Exception.Values[0].Type)
+		frames := se.Exception.Values[0].Stacktrace.Frames
+		assert.True(t, len(frames) > 0)
+		assert.Contains(t, frames[len(frames)-2].Function, "TestPanicSentryReporter")
+		assert.Equal(t, ahttp.MethodGet, se.Request.Method)
+		assert.Equal(t, "id=1", se.Request.QueryString)
+		assert.Equal(t, ahttp.ContentTypeJSON.Mime, se.Request.Headers[ahttp.HeaderAccept])
+		_, found := se.Request.Headers[ahttp.HeaderAuthorization]
+		assert.False(t, found)
+	case <-time.After(5 * time.Second):
+		t.Fatal("sentry event not received")
+	}
+}
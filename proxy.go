@@ -0,0 +1,221 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"aahframe.work/router"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initProxy(rtr *router.Router) {
+	pm := &proxyManager{a: a, proxies: make(map[*router.Route]*httputil.ReverseProxy)}
+	_ = rtr.Walk(func(_ *router.Domain, route *router.Route) error {
+		if route.IsProxy() {
+			pm.proxies[route] = pm.newReverseProxy(route.Proxy)
+		}
+		return nil
+	})
+
+	// on hot reload, idle connections of previous proxy transports are closed
+	old := a.proxyMgr
+	a.proxyMgr = pm
+	old.closeIdleConnections()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Proxy Manager
+//______________________________________________________________________________
+
+type proxyManager struct {
+	sync.RWMutex
+	a       *Application
+	proxies map[*router.Route]*httputil.ReverseProxy
+}
+
+// Serve method forwards the request to proxy target of the route and writes
+// the target response.
+func (pm *proxyManager) Serve(ctx *Context) {
+	rp := pm.proxy(ctx.route)
+
+	r := ctx.Req.Unwrap()
+	if isWebSocketUpgrade(r) && !ctx.route.Proxy.WebSocket {
+		ctx.Log().Warnf("Proxy websocket upgrade not allowed, Route: %s, Path: %s", ctx.route.Name, ctx.Req.Path)
+		ctx.Reply().BadRequest().Error(newError(ErrProxyUpgradeNotAllowed, http.StatusBadRequest))
+		return
+	}
+
+	outReq := r.WithContext(r.Context())
+	if ctx.route.Proxy.StripPrefix {
+		u := *r.URL
+		u.Path = "/" + strings.TrimPrefix(ctx.Req.PathValue(router.ProxyPathParam), "/")
+		u.RawPath = ""
+		outReq.URL = &u
+	}
+
+	ctx.Log().Debugf("Proxying request to %s, Path: %s", ctx.route.Proxy.Target, outReq.URL.Path)
+	ctx.Reply().Done()
+
+	// 'OnPreReply' server extension point
+	pm.a.he.publishOnPreReplyEvent(ctx)
+
+	rp.ServeHTTP(ctx.Res, outReq)
+
+	// 'OnAfterReply' server extension point
+	pm.a.he.publishOnPostReplyEvent(ctx)
+}
+
+// IsProxyRequest method returns true if request maps to reverse proxy route,
+// it's used to forward the websocket upgrade request to proxy target.
+func (pm *proxyManager) IsProxyRequest(r *http.Request) bool {
+	if pm == nil {
+		return false
+	}
+	pm.RLock()
+	size := len(pm.proxies)
+	pm.RUnlock()
+	if size == 0 {
+		return false
+	}
+	domain := pm.a.Router().Lookup(ahttp.Host(r))
+	if domain == nil {
+		return false
+	}
+	route, _, _ := domain.Lookup(r)
+	return route != nil && route.IsProxy()
+}
+
+// proxy method returns the reverse proxy of the route, it's created once for
+// the route added after initialization so that its transport connections
+// are reused.
+func (pm *proxyManager) proxy(route *router.Route) *httputil.ReverseProxy {
+	pm.RLock()
+	rp, found := pm.proxies[route]
+	pm.RUnlock()
+	if found {
+		return rp
+	}
+
+	pm.Lock()
+	defer pm.Unlock()
+	if rp, found = pm.proxies[route]; !found {
+		rp = pm.newReverseProxy(route.Proxy)
+		pm.proxies[route] = rp
+	}
+	return rp
+}
+
+// closeIdleConnections method closes the idle connections of all the proxy
+// transports.
+func (pm *proxyManager) closeIdleConnections() {
+	if pm == nil {
+		return
+	}
+	pm.RLock()
+	defer pm.RUnlock()
+	for _, rp := range pm.proxies {
+		if t, ok := rp.Transport.(*http.Transport); ok {
+			t.CloseIdleConnections()
+		}
+	}
+}
+
+func (pm *proxyManager) newReverseProxy(p *router.Proxy) *httputil.ReverseProxy {
+	target := p.Target
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			if _, found := req.Header[ahttp.HeaderXForwardedProto]; !found {
+				proto := "http"
+				if req.TLS != nil {
+					proto = "https"
+				}
+				req.Header.Set(ahttp.HeaderXForwardedProto, proto)
+			}
+			if _, found := req.Header[ahttp.HeaderXForwardedHost]; !found {
+				req.Header.Set(ahttp.HeaderXForwardedHost, req.Host)
+			}
+
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = joinURLPath(target.Path, req.URL.Path)
+			req.URL.RawPath = ""
+			if len(target.RawQuery) == 0 || len(req.URL.RawQuery) == 0 {
+				req.URL.RawQuery = target.RawQuery + req.URL.RawQuery
+			} else {
+				req.URL.RawQuery = target.RawQuery + "&" + req.URL.RawQuery
+			}
+			if !p.PreserveHost {
+				req.Host = target.Host
+			}
+			if _, found := req.Header[ahttp.HeaderUserAgent]; !found {
+				// explicitly disable User-Agent so it's not set to default value
+				req.Header.Set(ahttp.HeaderUserAgent, "")
+			}
+			p.RequestHeaders.Apply(req.Header)
+		},
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   durationDefault(p.DialTimeout, 30*time.Second),
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       durationDefault(p.IdleConnTimeout, 90*time.Second),
+			ResponseHeaderTimeout: p.ResponseHeaderTimeout,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		ModifyResponse: func(res *http.Response) error {
+			p.ResponseHeaders.Apply(res.Header)
+
+			// 'OnHeaderReply' HTTP event
+			pm.a.he.publishOnHeaderReplyEvent(res.Header)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			pm.a.Log().WithFields(log.Fields{"target": target.String(), "path": r.URL.Path}).
+				Errorf("Proxy error: %v", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get(ahttp.HeaderUpgrade), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get(ahttp.HeaderConnection)), "upgrade")
+}
+
+func joinURLPath(a, b string) string {
+	aslash, bslash := strings.HasSuffix(a, "/"), strings.HasPrefix(b, "/")
+	switch {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	}
+	return a + b
+}
+
+func durationDefault(d, defaultValue time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return defaultValue
+}
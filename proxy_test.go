@@ -0,0 +1,153 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyRoute(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Server", "backend")
+		w.Header().Set("X-Backend-Path", r.URL.Path)
+		w.Header().Set("X-Backend-Query", r.URL.RawQuery)
+		w.Header().Set("X-Backend-Host", r.Host)
+		w.Header().Set("X-Backend-Env", r.Header.Get("X-Env"))
+		w.Header().Set("X-Backend-Cookie", r.Header.Get("Cookie"))
+		w.Header().Set("X-Backend-Forwarded-Host", r.Header.Get(ahttp.HeaderXForwardedHost))
+		w.Header().Set("X-Backend-Forwarded-Proto", r.Header.Get(ahttp.HeaderXForwardedProto))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.Method + " " + string(body)))
+	}))
+	defer backend.Close()
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	target, _ := url.Parse(backend.URL + "/api")
+	domain := ts.app.Router().RootDomain()
+	assert.Nil(t, domain.AddRoute(&router.Route{
+		Name:        "backend_proxy",
+		Path:        "/backend/*" + router.ProxyPathParam,
+		Method:      ahttp.MethodPost,
+		Auth:        "anonymous",
+		MaxBodySize: 10,
+		Proxy: &router.Proxy{
+			StripPrefix:     true,
+			Target:          target,
+			RequestHeaders:  &router.ProxyHeaders{Set: http.Header{"X-Env": []string{"test"}}, Remove: []string{"Cookie"}},
+			ResponseHeaders: &router.ProxyHeaders{Remove: []string{"Server"}},
+		},
+	}))
+	assert.Nil(t, domain.AddRoute(&router.Route{
+		Name:   "files_proxy",
+		Path:   "/files/*" + router.ProxyPathParam,
+		Method: ahttp.MethodGet,
+		Auth:   "anonymous",
+		Proxy: &router.Proxy{
+			PreserveHost:    true,
+			Target:          target,
+			RequestHeaders:  &router.ProxyHeaders{},
+			ResponseHeaders: &router.ProxyHeaders{},
+		},
+	}))
+	ts.app.initProxy(ts.app.Router())
+	assert.Equal(t, 2, len(ts.app.proxyMgr.proxies))
+
+	httpClient := new(http.Client)
+
+	// strip prefix and header rewrites
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/backend/users?page=2", strings.NewReader("hello"))
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+	req.Header.Set("Cookie", "aah_session=value")
+	resp, err := httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "POST hello", responseBody(resp))
+	assert.Equal(t, "/api/users", resp.Header.Get("X-Backend-Path"))
+	assert.Equal(t, "page=2", resp.Header.Get("X-Backend-Query"))
+	assert.Equal(t, target.Host, resp.Header.Get("X-Backend-Host"))
+	assert.Equal(t, "test", resp.Header.Get("X-Backend-Env"))
+	assert.Equal(t, "", resp.Header.Get("X-Backend-Cookie"))
+	assert.Equal(t, strings.TrimPrefix(ts.URL, "http://"), resp.Header.Get("X-Backend-Forwarded-Host"))
+	assert.Equal(t, "http", resp.Header.Get("X-Backend-Forwarded-Proto"))
+	assert.Equal(t, "", resp.Header.Get("Server"))
+
+	// max body size
+	resp, err = httpClient.Post(ts.URL+"/backend/users", ahttp.ContentTypeJSON.String(), strings.NewReader("more than ten bytes"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+	// preserve host and no strip prefix
+	resp, err = httpClient.Get(ts.URL + "/files/docs/a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "/api/files/docs/a.txt", resp.Header.Get("X-Backend-Path"))
+	assert.Equal(t, strings.TrimPrefix(ts.URL, "http://"), resp.Header.Get("X-Backend-Host"))
+	assert.Equal(t, "backend", resp.Header.Get("Server"))
+
+	// websocket upgrade not allowed
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/files/ws", nil)
+	req.Header.Set(ahttp.HeaderConnection, "Upgrade")
+	req.Header.Set(ahttp.HeaderUpgrade, "websocket")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// websocket upgrade allowed, forwarded to target
+	domain.LookupByName("files_proxy").Proxy.WebSocket = true
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "/api/files/ws", resp.Header.Get("X-Backend-Path"))
+
+	// target not reachable
+	backend.Close()
+	resp, err = httpClient.Get(ts.URL + "/files/docs/a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func TestProxyJoinURLPath(t *testing.T) {
+	assert.Equal(t, "/api/users", joinURLPath("/api", "/users"))
+	assert.Equal(t, "/api/users", joinURLPath("/api/", "/users"))
+	assert.Equal(t, "/api/users", joinURLPath("/api", "users"))
+	assert.Equal(t, "/users", joinURLPath("", "/users"))
+}
+
+func TestProxyRouteAddedAfterInit(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	ts.app.initProxy(ts.app.Router())
+	old := ts.app.proxyMgr
+
+	target, _ := url.Parse("http://localhost:8080/api")
+	route := &router.Route{Name: "late_proxy", Proxy: &router.Proxy{Target: target}}
+
+	// created once and reused by subsequent requests
+	rp1 := old.proxy(route)
+	rp2 := old.proxy(route)
+	assert.True(t, rp1 == rp2)
+	assert.Equal(t, 1, len(old.proxies))
+
+	// hot reload replaces the manager, previous one is closed without panic
+	ts.app.initProxy(ts.app.Router())
+	assert.False(t, old == ts.app.proxyMgr)
+	assert.Equal(t, 0, len(ts.app.proxyMgr.proxies))
+}
@@ -0,0 +1,324 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/cache"
+	"aahframe.work/router"
+)
+
+const (
+	quotaAdminTarget      = "quotaAdminController"
+	quotaAdminDefaultPath = "/_quota"
+	quotaPeriodDaily      = "daily"
+	quotaPeriodMonthly    = "monthly"
+)
+
+// QuotaStore interface is used to persist the request quota usage of
+// principal per period. Default store keeps the usage in the cache
+// configured at `security.quota.cache`, implement it for database store and
+// set it via `aah.App().SetQuotaStore(...)`.
+type QuotaStore interface {
+	// Usage method returns the usage count of the key, zero if not exists.
+	Usage(key string) (int64, error)
+
+	// Increment method increments the usage count of the key by one and
+	// returns the updated count. Usage is not needed after `expiresAt`.
+	Increment(key string, expiresAt time.Time) (int64, error)
+
+	// Reset method clears the usage count of the key.
+	Reset(key string) error
+}
+
+// Quota holds the request quota usage of the Subject for the current period.
+type Quota struct {
+	Key     string
+	Period  string
+	Limit   int64
+	Used    int64
+	ResetAt time.Time
+}
+
+// Remaining method returns the remaining requests of the current period,
+// `-1` if quota is unlimited.
+func (q *Quota) Remaining() int64 {
+	if q.Limit == 0 {
+		return -1
+	}
+	if r := q.Limit - q.Used; r > 0 {
+		return r
+	}
+	return 0
+}
+
+// IsExceeded method returns true if usage reached the quota limit.
+func (q *Quota) IsExceeded() bool {
+	return q.Limit > 0 && q.Used >= q.Limit
+}
+
+// Quota method returns the request quota usage of the authenticated Subject
+// for the current period, accounted by `security.quota.*` config. It
+// returns nil if quota is not enabled or applicable to the request.
+func (ctx *Context) Quota() *Quota {
+	return ctx.quota
+}
+
+// SetQuotaStore method sets the given store to persist the request quota
+// usage, it takes priority over the default cache store.
+func (a *Application) SetQuotaStore(store QuotaStore) {
+	a.quotaStore = store
+}
+
+type quotaManager struct {
+	a      *Application
+	period string
+	limit  int
+	tiers  []*rateLimitTier
+	store  QuotaStore
+}
+
+func (a *Application) initQuota() error {
+	cfg := a.Config()
+	keyPrefix := "security.quota"
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		return nil
+	}
+
+	qm := &quotaManager{
+		a:      a,
+		period: cfg.StringDefault(keyPrefix+".period", quotaPeriodMonthly),
+		limit:  cfg.IntDefault(keyPrefix+".limit", 0),
+	}
+	if qm.period != quotaPeriodDaily && qm.period != quotaPeriodMonthly {
+		return fmt.Errorf("aah: '%s.period' value is not supported, use '%s' or '%s'",
+			keyPrefix, quotaPeriodDaily, quotaPeriodMonthly)
+	}
+
+	var err error
+	if qm.tiers, err = parseLimitTiers(cfg, keyPrefix+".tiers"); err != nil {
+		return err
+	}
+
+	if qm.store = a.quotaStore; qm.store == nil {
+		qm.store = &cacheQuotaStore{a: a, cacheName: cfg.StringDefault(keyPrefix+".cache", "request_quota")}
+	}
+
+	a.quota = qm
+	return nil
+}
+
+// allowed method accounts the request into quota usage of the authenticated
+// Subject, it replies `429 Too Many Requests` when the quota is exceeded.
+// It returns true if request flow can continue.
+func (qm *quotaManager) allowed(ctx *Context) bool {
+	if qm == nil {
+		return true
+	}
+
+	key := subjectLimitKey(ctx.Subject())
+	if len(key) == 0 {
+		return true
+	}
+
+	q := qm.quota(key, time.Now())
+	q.Limit = int64(tierLimit(ctx.Subject(), qm.tiers, qm.limit))
+	ctx.quota = q
+
+	var err error
+	if q.Used, err = qm.store.Usage(qm.storeKey(q)); err != nil {
+		ctx.Log().Errorf("Quota: unable to get usage of '%s': %v", key, err)
+		return true
+	}
+	if q.IsExceeded() {
+		d := time.Until(q.ResetAt)
+		ctx.moduleLog("security").Warnf("Quota: '%s' exceeded the %s quota %d", key, q.Period, q.Limit)
+		ctx.a.eventStore.PublishSync(&Event{Name: EventOnQuotaExceeded, Data: ctx})
+		ctx.Reply().Header(ahttp.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+		ctx.Reply().Status(http.StatusTooManyRequests).Error(newError(ErrQuotaExceeded, http.StatusTooManyRequests))
+		return false
+	}
+
+	if used, err := qm.store.Increment(qm.storeKey(q), q.ResetAt); err != nil {
+		ctx.Log().Errorf("Quota: unable to increment usage of '%s': %v", key, err)
+	} else {
+		q.Used = used
+	}
+	return true
+}
+
+// quota method returns the quota of key for the period of given time.
+func (qm *quotaManager) quota(key string, now time.Time) *Quota {
+	now = now.UTC()
+	q := &Quota{Key: key, Period: qm.period}
+	if qm.period == quotaPeriodDaily {
+		q.ResetAt = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	} else {
+		q.ResetAt = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return q
+}
+
+// storeKey method returns the store key of quota which is scoped to period,
+// for e.g.: `aah_quota_"APIKey:key1"_20261015`.
+func (qm *quotaManager) storeKey(q *Quota) string {
+	start := q.ResetAt.AddDate(0, 0, -1)
+	if q.Period == quotaPeriodMonthly {
+		return "aah_quota_" + strconv.Quote(q.Key) + "_" + start.Format("200601")
+	}
+	return "aah_quota_" + strconv.Quote(q.Key) + "_" + start.Format("20060102")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Cache Quota Store
+//______________________________________________________________________________
+
+// cacheQuotaStore keeps the quota usage in the cache of aah cache manager.
+type cacheQuotaStore struct {
+	sync.Mutex
+	a         *Application
+	cacheName string
+}
+
+func (s *cacheQuotaStore) Usage(key string) (int64, error) {
+	c, err := s.cache()
+	if err != nil {
+		return 0, err
+	}
+	used, _ := c.Get(key).(int64)
+	return used, nil
+}
+
+func (s *cacheQuotaStore) Increment(key string, expiresAt time.Time) (int64, error) {
+	c, err := s.cache()
+	if err != nil {
+		return 0, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	used, _ := c.Get(key).(int64)
+	used++
+	_ = c.Delete(key)
+	return used, c.Put(key, used, time.Until(expiresAt))
+}
+
+func (s *cacheQuotaStore) Reset(key string) error {
+	c, err := s.cache()
+	if err != nil {
+		return err
+	}
+	return c.Delete(key)
+}
+
+func (s *cacheQuotaStore) cache() (cache.Cache, error) {
+	c := s.a.CacheManager().Cache(s.cacheName)
+	if c == nil {
+		return nil, fmt.Errorf("aah: quota cache '%s' not exists", s.cacheName)
+	}
+	return c, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Quota admin controller
+//______________________________________________________________________________
+
+// quotaAdminController serves the quota usage inspect and reset endpoints,
+// accessible only to the Subject which has role `security.quota.admin.role`.
+type quotaAdminController struct {
+	*Context
+}
+
+// Usage method replies the current period quota usage of path param key.
+func (c *quotaAdminController) Usage() {
+	qm := c.quotaManager()
+	if qm == nil {
+		return
+	}
+
+	key := c.Req.PathValue("key")
+	q := qm.quota(key, time.Now())
+	used, err := qm.store.Usage(qm.storeKey(q))
+	if err != nil {
+		c.Log().Errorf("Quota: unable to get usage of '%s': %v", key, err)
+		c.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+		return
+	}
+	c.Reply().Header(ahttp.HeaderCacheControl, "no-store").
+		JSON(Data{"key": key, "period": q.Period, "used": used, "reset_at": q.ResetAt})
+}
+
+// Reset method clears the current period quota usage of path param key.
+func (c *quotaAdminController) Reset() {
+	qm := c.quotaManager()
+	if qm == nil {
+		return
+	}
+
+	key := c.Req.PathValue("key")
+	if err := qm.store.Reset(qm.storeKey(qm.quota(key, time.Now()))); err != nil {
+		c.Log().Errorf("Quota: unable to reset usage of '%s': %v", key, err)
+		c.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+		return
+	}
+	c.Log().Infof("Quota: usage of '%s' is reset by '%s'", key, c.Subject().PrimaryPrincipal().Value)
+	c.Reply().NoContent()
+}
+
+func (c *quotaAdminController) quotaManager() *quotaManager {
+	if c.a.quota == nil {
+		c.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
+		return nil
+	}
+	role := c.a.Config().StringDefault("security.quota.admin.role", "admin")
+	if !c.Subject().HasRole(role) {
+		c.Reply().Forbidden().Error(newError(ErrAccessDenied, http.StatusForbidden))
+		return nil
+	}
+	return c.a.quota
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// addQuotaAdminRoutes method adds quota usage inspect and reset endpoints
+// into all domains if it's enabled.
+func (a *Application) addQuotaAdminRoutes(rtr *router.Router) error {
+	cfg := a.Config()
+	if !cfg.BoolDefault("security.quota.enable", false) ||
+		!cfg.BoolDefault("security.quota.admin.enable", false) {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(quotaAdminTarget) == nil {
+		a.AddController((*quotaAdminController)(nil), []*ainsp.Method{
+			{Name: "Usage"}, {Name: "Reset"},
+		})
+	}
+
+	path := cfg.StringDefault("security.quota.admin.path", quotaAdminDefaultPath) + "/:key"
+	auth := cfg.StringDefault("security.quota.admin.auth", "authenticated")
+	for _, d := range rtr.Domains {
+		for _, r := range []*router.Route{
+			{Name: "quota_usage__aah", Method: ahttp.MethodGet, Action: "Usage"},
+			{Name: "quota_reset__aah", Method: ahttp.MethodDelete, Action: "Reset"},
+		} {
+			r.Path, r.Target, r.Auth = path, quotaAdminTarget, auth
+			if err := d.AddRoute(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Nil(t, a.quota)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  quota {
+		    enable = true
+		    limit = 2
+		    tiers {
+		      pro {
+		        permission = "plan:pro"
+		        limit = 4
+		      }
+		    }
+		    admin {
+		      enable = true
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initQuota())
+	assert.Equal(t, quotaPeriodMonthly, a.quota.period)
+
+	tc := &testThrottleCache{entries: make(map[string]interface{})}
+	a.cacheMgr = cache.NewManager()
+	_ = a.CacheManager().AddProvider("test", &testThrottleCacheProvider{c: tc})
+	assert.Nil(t, a.CacheManager().CreateCache(&cache.Config{Name: "request_quota", ProviderName: "test"}))
+
+	var events []string
+	a.SubscribeEvent(EventOnQuotaExceeded, EventCallback{Callback: func(e *Event) {
+		events = append(events, e.Name)
+	}})
+
+	newCtx := func(method, id string, roles []string, permissions ...string) *Context {
+		req := httptest.NewRequest(method, "http://localhost:8080/api/orders", nil)
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		if len(id) > 0 {
+			ctx.Subject().Session = a.SessionManager().NewSession()
+			ctx.Subject().Session.IsAuthenticated = true
+			ctx.Subject().AuthenticationInfo = authc.NewAuthenticationInfo()
+			ctx.Subject().AuthenticationInfo.Principals = append(ctx.Subject().AuthenticationInfo.Principals,
+				&authc.Principal{Realm: "APIKey", Claim: "KeyID", Value: id, IsPrimary: true})
+			ctx.Subject().AuthorizationInfo = authz.NewAuthorizationInfo().
+				AddRole(roles...).AddPermissionString(permissions...)
+		}
+		return ctx
+	}
+
+	// unauthenticated request is not accounted
+	ctx := newCtx(ahttp.MethodGet, "", nil)
+	assert.True(t, a.quota.allowed(ctx))
+	assert.Nil(t, ctx.Quota())
+
+	for i := int64(1); i <= 2; i++ {
+		ctx = newCtx(ahttp.MethodGet, "key1", nil)
+		assert.True(t, a.quota.allowed(ctx))
+		assert.Equal(t, "APIKey:key1", ctx.Quota().Key)
+		assert.Equal(t, i, ctx.Quota().Used)
+		assert.Equal(t, 2-i, ctx.Quota().Remaining())
+	}
+	ctx = newCtx(ahttp.MethodGet, "key1", nil)
+	assert.False(t, a.quota.allowed(ctx))
+	assert.True(t, ctx.Quota().IsExceeded())
+	assert.Equal(t, http.StatusTooManyRequests, ctx.Reply().Code)
+	assert.Equal(t, ErrQuotaExceeded, ctx.Reply().err.Reason)
+	assert.NotEqual(t, "", ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+	assert.Equal(t, []string{EventOnQuotaExceeded}, events)
+
+	// tier override
+	for i := 0; i < 4; i++ {
+		assert.True(t, a.quota.allowed(newCtx(ahttp.MethodGet, "key2", nil, "plan:pro")))
+	}
+	ctx = newCtx(ahttp.MethodGet, "key2", nil, "plan:pro")
+	assert.False(t, a.quota.allowed(ctx))
+	assert.Equal(t, int64(4), ctx.Quota().Limit)
+
+	// admin endpoints
+	adminCtx := func(method string, roles ...string) *quotaAdminController {
+		ctx := newCtx(method, "admin1", roles)
+		ctx.Req.URLParams = ahttp.URLParams{{Key: "key", Value: "APIKey:key1"}}
+		return &quotaAdminController{Context: ctx}
+	}
+	c := adminCtx(ahttp.MethodGet)
+	c.Usage()
+	assert.Equal(t, http.StatusForbidden, c.Reply().Code)
+
+	c = adminCtx(ahttp.MethodGet, "admin")
+	c.Usage()
+	assert.Equal(t, http.StatusOK, c.Reply().Code)
+	assert.Equal(t, int64(2), c.Reply().Rdr.(*jsonRender).Data.(Data)["used"])
+
+	c = adminCtx(ahttp.MethodDelete, "admin")
+	c.Reset()
+	assert.Equal(t, http.StatusNoContent, c.Reply().Code)
+	assert.True(t, a.quota.allowed(newCtx(ahttp.MethodGet, "key1", nil)))
+
+	// period
+	now := time.Date(2026, time.December, 31, 23, 0, 0, 0, time.UTC)
+	q := a.quota.quota("APIKey:key1", now)
+	assert.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), q.ResetAt)
+	assert.Equal(t, `aah_quota_"APIKey:key1"_202612`, a.quota.storeKey(q))
+
+	a.quota.period = quotaPeriodDaily
+	q = a.quota.quota("APIKey:key1", now)
+	assert.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), q.ResetAt)
+	assert.Equal(t, `aah_quota_"APIKey:key1"_20261231`, a.quota.storeKey(q))
+
+	// routes
+	assert.Nil(t, a.initRouter())
+	domain := a.Router().Lookup("localhost:8080")
+	assert.NotNil(t, domain.LookupByName("quota_usage__aah"))
+	assert.NotNil(t, domain.LookupByName("quota_reset__aah"))
+
+	a.Config().SetString("security.quota.period", "weekly")
+	assert.Equal(t, "aah: 'security.quota.period' value is not supported, use 'daily' or 'monthly'", a.initQuota().Error())
+}
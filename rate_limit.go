@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security"
+)
+
+// rateLimiter is fixed window rate limiter per Subject, authenticated Subject
+// is keyed by its primary principal (for API key its key ID) and
+// unauthenticated by client IP. Limit is chosen from tiers using Subject
+// authorization info.
+type rateLimiter struct {
+	sync.Mutex
+	limit     int
+	anonLimit int
+	window    time.Duration
+	tiers     []*rateLimitTier
+	tags      []string
+	windows   map[string]*rateLimitWindow
+	lastSweep time.Time
+}
+
+// rateLimitTier overrides the limit for the Subject which has the role or
+// permission, used by rate limit and quota.
+type rateLimitTier struct {
+	name       string
+	role       string
+	permission string
+	limit      int
+}
+
+type rateLimitWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimitResult holds the rate limit state of the request, used for
+// `RateLimit-*` response headers.
+type rateLimitResult struct {
+	limit     int
+	remaining int
+	reset     time.Duration
+	allowed   bool
+}
+
+func (a *Application) initRateLimiter() error {
+	cfg := a.Config()
+	keyPrefix := "security.rate_limit"
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		return nil
+	}
+
+	window, err := time.ParseDuration(cfg.StringDefault(keyPrefix+".window", "1m"))
+	if err != nil || window <= 0 {
+		return fmt.Errorf("aah: '%s.window' value is not a valid time unit", keyPrefix)
+	}
+
+	rl := &rateLimiter{
+		limit:     cfg.IntDefault(keyPrefix+".limit", 60),
+		anonLimit: cfg.IntDefault(keyPrefix+".anonymous_limit", 0),
+		window:    window,
+		windows:   make(map[string]*rateLimitWindow),
+		lastSweep: time.Now(),
+	}
+
+	if rl.tiers, err = parseLimitTiers(cfg, keyPrefix+".tiers"); err != nil {
+		return err
+	}
+	rl.tags, _ = cfg.StringList(keyPrefix + ".tags")
+
+	a.rateLimiter = rl
+	return nil
+}
+
+// allowed method applies the rate limit on the request, it sets the
+// `RateLimit-*` response headers and replies `429 Too Many Requests` when the
+// limit is exceeded. It returns true if request flow can continue.
+func (rl *rateLimiter) allowed(ctx *Context) bool {
+	if rl == nil || (len(rl.tags) > 0 && !ctx.route.HasAnyTag(rl.tags...)) {
+		return true
+	}
+
+	key, limit := rl.keyAndLimit(ctx)
+	if limit == 0 {
+		return true
+	}
+
+	r := rl.take(key, limit, time.Now())
+	ctx.Reply().
+		Header(ahttp.HeaderRateLimitLimit, strconv.Itoa(r.limit)).
+		Header(ahttp.HeaderRateLimitRemaining, strconv.Itoa(r.remaining)).
+		Header(ahttp.HeaderRateLimitReset, strconv.FormatInt(int64(math.Ceil(r.reset.Seconds())), 10))
+	if r.allowed {
+		return true
+	}
+
+	ctx.moduleLog("security").Warnf("Rate limit: '%s' exceeded the limit %d, retry after %s", key, r.limit, r.reset)
+	ctx.Reply().Header(ahttp.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(r.reset.Seconds())), 10))
+	ctx.Reply().Status(http.StatusTooManyRequests).Error(newError(ErrRateLimitExceeded, http.StatusTooManyRequests))
+	return false
+}
+
+// keyAndLimit method returns the rate limit key and limit for the request
+// Subject.
+func (rl *rateLimiter) keyAndLimit(ctx *Context) (string, int) {
+	key := subjectLimitKey(ctx.Subject())
+	if len(key) == 0 {
+		return "ip:" + ctx.Req.ClientIP(), rl.anonLimit
+	}
+	return key, tierLimit(ctx.Subject(), rl.tiers, rl.limit)
+}
+
+// take method counts the request in the current window of the key.
+func (rl *rateLimiter) take(key string, limit int, now time.Time) *rateLimitResult {
+	rl.Lock()
+	defer rl.Unlock()
+
+	// Expired windows are removed once per window duration
+	if now.Sub(rl.lastSweep) >= rl.window {
+		for k, w := range rl.windows {
+			if now.Sub(w.start) >= rl.window {
+				delete(rl.windows, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	w, found := rl.windows[key]
+	if !found || now.Sub(w.start) >= rl.window {
+		w = &rateLimitWindow{start: now}
+		rl.windows[key] = w
+	}
+
+	r := &rateLimitResult{limit: limit, reset: w.start.Add(rl.window).Sub(now)}
+	if w.count >= limit {
+		return r
+	}
+	w.count++
+	r.remaining, r.allowed = limit-w.count, true
+	return r
+}
+
+// parseLimitTiers method parses the limit tiers from given config key.
+func parseLimitTiers(cfg *config.Config, tiersKey string) ([]*rateLimitTier, error) {
+	var tiers []*rateLimitTier
+	for _, name := range cfg.KeysByPath(tiersKey) {
+		tierKey := tiersKey + "." + name
+		t := &rateLimitTier{
+			name:       name,
+			role:       cfg.StringDefault(tierKey+".role", ""),
+			permission: cfg.StringDefault(tierKey+".permission", ""),
+			limit:      cfg.IntDefault(tierKey+".limit", -1),
+		}
+		if len(t.role) == 0 && len(t.permission) == 0 {
+			return nil, fmt.Errorf("aah: '%s' tier requires 'role' or 'permission'", tierKey)
+		}
+		if t.limit < 0 {
+			return nil, fmt.Errorf("aah: '%s.limit' is required", tierKey)
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers, nil
+}
+
+// subjectLimitKey method returns the key of authenticated Subject by its
+// primary principal `<realm>:<value>` otherwise empty string.
+func subjectLimitKey(subject *security.Subject) string {
+	if !subject.IsAuthenticated() || subject.AuthenticationInfo == nil {
+		return ""
+	}
+	if p := subject.PrimaryPrincipal(); p != nil {
+		return p.Realm + ":" + p.Value
+	}
+	return ""
+}
+
+// tierLimit method returns the limit for the Subject from matching tiers
+// otherwise given default limit. First matching tier overrides the default
+// limit, then higher one wins. Tier limit `0` is unlimited.
+func tierLimit(subject *security.Subject, tiers []*rateLimitTier, defaultLimit int) int {
+	if subject.AuthorizationInfo == nil {
+		return defaultLimit
+	}
+
+	limit, matched := defaultLimit, false
+	for _, t := range tiers {
+		if (len(t.role) > 0 && subject.HasRole(t.role)) ||
+			(len(t.permission) > 0 && subject.IsPermitted(t.permission)) {
+			if t.limit == 0 {
+				return 0
+			}
+			if !matched || t.limit > limit {
+				limit, matched = t.limit, true
+			}
+		}
+	}
+	return limit
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/router"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Nil(t, a.rateLimiter)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  rate_limit {
+		    enable = true
+		    limit = 2
+		    anonymous_limit = 1
+		    tiers {
+		      pro {
+		        permission = "plan:pro"
+		        limit = 3
+		      }
+		      internal {
+		        role = "internal"
+		        limit = 0
+		      }
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initRateLimiter())
+	assert.Equal(t, time.Minute, a.rateLimiter.window)
+	assert.Equal(t, 2, len(a.rateLimiter.tiers))
+
+	newCtx := func(id string, roles []string, permissions ...string) *Context {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/api/orders", nil)
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		if len(id) > 0 {
+			ctx.Subject().Session = a.SessionManager().NewSession()
+			ctx.Subject().Session.IsAuthenticated = true
+			ctx.Subject().AuthenticationInfo = authc.NewAuthenticationInfo()
+			ctx.Subject().AuthenticationInfo.Principals = append(ctx.Subject().AuthenticationInfo.Principals,
+				&authc.Principal{Realm: "APIKey", Claim: "KeyID", Value: id, IsPrimary: true})
+			ctx.Subject().AuthorizationInfo = authz.NewAuthorizationInfo().
+				AddRole(roles...).AddPermissionString(permissions...)
+		}
+		return ctx
+	}
+
+	// default limit keyed by primary principal
+	for i := 1; i >= 0; i-- {
+		ctx := newCtx("key1", nil)
+		assert.True(t, a.rateLimiter.allowed(ctx))
+		assert.Equal(t, "2", ctx.Res.Header().Get(ahttp.HeaderRateLimitLimit))
+		assert.Equal(t, strconv.Itoa(i), ctx.Res.Header().Get(ahttp.HeaderRateLimitRemaining))
+		assert.Equal(t, "60", ctx.Res.Header().Get(ahttp.HeaderRateLimitReset))
+	}
+	ctx := newCtx("key1", nil)
+	assert.False(t, a.rateLimiter.allowed(ctx))
+	assert.Equal(t, http.StatusTooManyRequests, ctx.Reply().Code)
+	assert.Equal(t, "0", ctx.Res.Header().Get(ahttp.HeaderRateLimitRemaining))
+	assert.Equal(t, "60", ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+	assert.Equal(t, ErrRateLimitExceeded, ctx.Reply().err.Reason)
+
+	// tier override from authorization info
+	for i := 0; i < 3; i++ {
+		assert.True(t, a.rateLimiter.allowed(newCtx("key2", nil, "plan:pro")))
+	}
+	ctx = newCtx("key2", nil, "plan:pro")
+	assert.False(t, a.rateLimiter.allowed(ctx))
+	assert.Equal(t, "3", ctx.Res.Header().Get(ahttp.HeaderRateLimitLimit))
+
+	// unlimited tier
+	for i := 0; i < 5; i++ {
+		ctx = newCtx("key3", []string{"internal"}, "plan:pro")
+		assert.True(t, a.rateLimiter.allowed(ctx))
+		assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderRateLimitLimit))
+	}
+
+	// unauthenticated keyed by client IP
+	assert.True(t, a.rateLimiter.allowed(newCtx("", nil)))
+	assert.False(t, a.rateLimiter.allowed(newCtx("", nil)))
+
+	// window reset
+	now := time.Now().Add(time.Minute)
+	r := a.rateLimiter.take("APIKey:key1", 2, now)
+	assert.True(t, r.allowed)
+	assert.Equal(t, 1, r.remaining)
+	assert.Equal(t, 1, len(a.rateLimiter.windows))
+
+	// applied only on tagged routes
+	a.rateLimiter.tags = []string{"api"}
+	ctx = newCtx("key1", nil)
+	assert.True(t, a.rateLimiter.allowed(ctx))
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderRateLimitLimit))
+	ctx = newCtx("key4", nil)
+	ctx.route = &router.Route{Name: "orders", Tags: []string{"api"}}
+	assert.True(t, ctx.Route().HasTag("api"))
+	assert.True(t, a.rateLimiter.allowed(ctx))
+	assert.Equal(t, "2", ctx.Res.Header().Get(ahttp.HeaderRateLimitLimit))
+
+	a.Config().SetString("security.rate_limit.tiers.pro.permission", "")
+	assert.Equal(t, "aah: 'security.rate_limit.tiers.pro' tier requires 'role' or 'permission'", a.initRateLimiter().Error())
+
+	a.Config().SetString("security.rate_limit.window", "1 minute")
+	assert.Equal(t, "aah: 'security.rate_limit.window' value is not a valid time unit", a.initRateLimiter().Error())
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+)
+
+const keyRedirectRules = "server.redirect.rules"
+
+// redirectRule holds the canonical host and scheme redirect rule configured
+// in the section `server.redirect.rules`.
+type redirectRule struct {
+	Name         string
+	Host         string
+	HTTPS        bool
+	HSTS         bool
+	PreservePath bool
+	Code         int
+	Aliases      []string
+	Exclude      []string
+}
+
+// String method is Stringer interface.
+func (rr *redirectRule) String() string {
+	return fmt.Sprintf("redirect(name:%s host:%s aliases:%v https:%v code:%d)",
+		rr.Name, rr.Host, rr.Aliases, rr.HTTPS, rr.Code)
+}
+
+// isAlias method returns true if given host (without port) is one of the
+// rule aliases.
+func (rr *redirectRule) isAlias(host string) bool {
+	for _, alias := range rr.Aliases {
+		if strings.EqualFold(alias, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcluded method returns true if given request path has any of the rule
+// excluded path prefixes.
+func (rr *redirectRule) isExcluded(p string) bool {
+	for _, prefix := range rr.Exclude {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initRedirect() error {
+	cfg := a.Config()
+	defaultCode := cfg.IntDefault("server.redirect.code", http.StatusMovedPermanently)
+
+	var rules []*redirectRule
+	for _, name := range cfg.KeysByPath(keyRedirectRules) {
+		rule, err := parseRedirectRule(cfg, name, defaultCode)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	a.redirectRules = rules
+	return nil
+}
+
+// redirectHTTPSPort method returns the port of HTTPS enforcement redirect
+// target. It's app port when app serves TLS on non-default port otherwise
+// empty, i.e. default port `443`.
+func (a *Application) redirectHTTPSPort() string {
+	if a.IsSSLEnabled() {
+		if port := a.HTTPPort(); port != "443" {
+			return port
+		}
+	}
+	return ""
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTP Engine methods
+//______________________________________________________________________________
+
+// doRedirectRules method applies the first matching canonical host and scheme
+// redirect rule. Request host is matched against rule aliases and canonical
+// host (for HTTPS enforcement). It returns true if redirect is written.
+func (e *HTTPEngine) doRedirectRules(w http.ResponseWriter, r *http.Request) bool {
+	host := ahttp.Host(r)
+	hostname := stripPort(host)
+	scheme := ahttp.Scheme(r)
+
+	for _, rule := range e.a.redirectRules {
+		isAlias := rule.isAlias(hostname)
+		isCanonical := strings.EqualFold(rule.Host, hostname) || strings.EqualFold(rule.Host, host)
+		if !isAlias && !isCanonical {
+			continue
+		}
+		if rule.isExcluded(r.URL.Path) {
+			return false
+		}
+
+		targetScheme, targetHost := scheme, host
+		if isAlias {
+			targetHost = rule.Host
+		}
+		if rule.HTTPS {
+			targetScheme = "https"
+			if scheme != targetScheme && targetHost == host {
+				// request port belongs to HTTP listener
+				targetHost = parseHost(host, e.a.redirectHTTPSPort())
+			}
+		}
+		if targetScheme == scheme && targetHost == host {
+			return false
+		}
+
+		target := targetScheme + "://" + targetHost + "/"
+		if rule.PreservePath {
+			target = targetScheme + "://" + targetHost + r.URL.RequestURI()
+		}
+
+		// HSTS is honored by browsers only over secure transport.
+		if rule.HSTS && scheme == "https" && e.a.securityMgr != nil &&
+			e.a.securityMgr.SecureHeaders != nil && len(e.a.securityMgr.SecureHeaders.STS) > 0 {
+			w.Header().Set(ahttp.HeaderStrictTransportSecurity, e.a.securityMgr.SecureHeaders.STS)
+		}
+		http.Redirect(w, r, target, rule.Code)
+		return true
+	}
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseRedirectRule(cfg *config.Config, name string, defaultCode int) (*redirectRule, error) {
+	keyPrefix := keyRedirectRules + "." + name + "."
+	host, found := cfg.String(keyPrefix + "host")
+	if !found || len(strings.TrimSpace(host)) == 0 {
+		return nil, fmt.Errorf("'%shost' value is missing", keyPrefix)
+	}
+
+	rule := &redirectRule{
+		Name:         name,
+		Host:         strings.TrimSpace(host),
+		HTTPS:        cfg.BoolDefault(keyPrefix+"https", false),
+		HSTS:         cfg.BoolDefault(keyPrefix+"hsts", true),
+		PreservePath: cfg.BoolDefault(keyPrefix+"preserve_path", true),
+		Code:         cfg.IntDefault(keyPrefix+"code", defaultCode),
+	}
+	if rule.Code < http.StatusMultipleChoices || rule.Code > http.StatusPermanentRedirect {
+		return nil, fmt.Errorf("'%scode' value '%d' is not a redirect status code", keyPrefix, rule.Code)
+	}
+
+	rule.Aliases, _ = cfg.StringList(keyPrefix + "aliases")
+	rule.Exclude, _ = cfg.StringList(keyPrefix + "exclude")
+	if len(rule.Aliases) == 0 && !rule.HTTPS {
+		return nil, fmt.Errorf("'%saliases' value is missing, required if 'https' is not enabled", keyPrefix)
+	}
+	return rule, nil
+}
+
+// stripPort method returns the host name without port.
+func stripPort(host string) string {
+	if idx := strings.LastIndexByte(host, ':'); idx > 0 && !strings.HasSuffix(host, "]") {
+		return host[:idx]
+	}
+	return host
+}
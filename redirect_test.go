@@ -0,0 +1,171 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRedirectRules(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`
+		server {
+			redirect {
+				enable = true
+				rules {
+					main_site {
+						host = "aahframework.org"
+						aliases = ["www.aahframework.org", "aahframework.net"]
+						https = true
+						exclude = ["/.well-known/acme-challenge/"]
+					}
+					docs_site {
+						host = "docs.aahframework.org"
+						aliases = ["doc.aahframework.org"]
+						preserve_path = false
+						code = 302
+					}
+				}
+			}
+		}
+	`)
+	assert.Nil(t, a.initRedirect())
+	assert.Equal(t, 2, len(a.redirectRules))
+	assert.Equal(t, "docs_site", a.redirectRules[0].Name)
+	assert.Equal(t, "redirect(name:main_site host:aahframework.org aliases:[www.aahframework.org aahframework.net] https:true code:301)",
+		a.redirectRules[1].String())
+
+	a.securityMgr = security.New()
+	a.securityMgr.SecureHeaders = &security.SecureHeaders{STS: "max-age=2592000"}
+
+	testcases := []struct {
+		label    string
+		fromURL  string
+		tls      bool
+		status   int
+		location string
+		sts      string
+	}{
+		{
+			label:    "alias to canonical https",
+			fromURL:  "http://www.aahframework.org/home.html?rt=login",
+			status:   http.StatusMovedPermanently,
+			location: "https://aahframework.org/home.html?rt=login",
+		},
+		{
+			label:    "alias to canonical over https with hsts",
+			fromURL:  "https://aahframework.net/home.html",
+			tls:      true,
+			status:   http.StatusMovedPermanently,
+			location: "https://aahframework.org/home.html",
+			sts:      "max-age=2592000",
+		},
+		{
+			label:    "canonical enforce https",
+			fromURL:  "http://aahframework.org/pricing",
+			status:   http.StatusMovedPermanently,
+			location: "https://aahframework.org/pricing",
+		},
+		{
+			label:    "canonical enforce https drops http port",
+			fromURL:  "http://aahframework.org:8080/pricing",
+			status:   http.StatusMovedPermanently,
+			location: "https://aahframework.org/pricing",
+		},
+		{
+			label:    "alias enforce https with port",
+			fromURL:  "http://www.aahframework.org:8080/pricing",
+			status:   http.StatusMovedPermanently,
+			location: "https://aahframework.org/pricing",
+		},
+		{
+			label:   "canonical already https",
+			fromURL: "https://aahframework.org/pricing",
+			tls:     true,
+			status:  http.StatusOK,
+		},
+		{
+			label:   "excluded path",
+			fromURL: "http://www.aahframework.org/.well-known/acme-challenge/token1",
+			status:  http.StatusOK,
+		},
+		{
+			label:    "alias without path preserve",
+			fromURL:  "http://doc.aahframework.org/guide.html",
+			status:   http.StatusFound,
+			location: "http://docs.aahframework.org/",
+		},
+		{
+			label:   "canonical without https enforcement",
+			fromURL: "http://docs.aahframework.org/guide.html",
+			status:  http.StatusOK,
+		},
+		{
+			label:   "no matching rule and www/non-www not configured",
+			fromURL: "http://www.example.com/",
+			status:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(ahttp.MethodGet, tc.fromURL, nil)
+			if tc.tls {
+				r.TLS = &tls.ConnectionState{}
+			} else {
+				r.TLS = nil
+			}
+			a.he.doRedirect(w, r)
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.location, w.Header().Get(ahttp.HeaderLocation))
+			assert.Equal(t, tc.sts, w.Header().Get(ahttp.HeaderStrictTransportSecurity))
+		})
+	}
+
+	// app serves TLS on non-default port
+	a.settings.SSLEnabled = true
+	a.cfg.SetString("server.port", "8443")
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(ahttp.MethodGet, "http://aahframework.org:8080/pricing", nil)
+	r.TLS = nil
+	a.he.doRedirect(w, r)
+	assert.Equal(t, "https://aahframework.org:8443/pricing", w.Header().Get(ahttp.HeaderLocation))
+	a.settings.SSLEnabled = false
+
+	// www/non-www along with rules
+	a.cfg.SetString("server.redirect.to", "non-www")
+	w = httptest.NewRecorder()
+	a.he.doRedirect(w, httptest.NewRequest(ahttp.MethodGet, "http://www.example.com/", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "http://example.com/", w.Header().Get(ahttp.HeaderLocation))
+}
+
+func TestServerRedirectRuleParseErrors(t *testing.T) {
+	testcases := []struct {
+		label, cfg, err string
+	}{
+		{"host missing", `aliases = ["www.aahframework.org"]`, "'server.redirect.rules.r1.host' value is missing"},
+		{"aliases missing", `host = "aahframework.org"`, "'server.redirect.rules.r1.aliases' value is missing, required if 'https' is not enabled"},
+		{"invalid code", "host = \"aahframework.org\"\nhttps = true\ncode = 200", "'server.redirect.rules.r1.code' value '200' is not a redirect status code"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			cfg, err := config.ParseString("server {\nredirect {\nrules {\nr1 {\n" + tc.cfg + "\n}\n}\n}\n}")
+			assert.Nil(t, err)
+			_, err = parseRedirectRule(cfg, "r1", http.StatusMovedPermanently)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
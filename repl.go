@@ -0,0 +1,338 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframe.work/console"
+)
+
+// ConsoleEvaluator interface is used to implement the evaluator of aah
+// console command `<app-binary> console`. Given source is the user input
+// line or script file content.
+type ConsoleEvaluator interface {
+	Eval(src string) (interface{}, error)
+}
+
+// ConsoleEvaluatorFunc type is used to create the console evaluator with
+// app context objects `app`, `config`, `cache` and `db`.
+type ConsoleEvaluatorFunc func(vars map[string]interface{}) (ConsoleEvaluator, error)
+
+// defaultConsoleEvaluator is the Go interpreter based evaluator of aah console,
+// it gets registered by build tag `yaegi` (refer to `repl_yaegi.go`).
+var defaultConsoleEvaluator ConsoleEvaluatorFunc
+
+// SetConsoleEvaluator method sets the given evaluator for aah console
+// command, it replaces the built-in evaluator.
+//
+// By default aah console evaluates the method call chain expressions on the
+// app context objects, for e.g.:
+// 	config.StringDefault("server.port", "8080")
+// 	db("default").Stats()
+// 	cache.Cache("users").Get("u1")
+//
+// The full Go interpreter (`github.com/traefik/yaegi`) is included in the
+// build with tag `yaegi`.
+func (a *Application) SetConsoleEvaluator(fn ConsoleEvaluatorFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.consoleEval = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) cliCmdConsole() console.Command {
+	return console.Command{
+		Name:  "console",
+		Usage: "Boots the app without HTTP listener and starts interactive console",
+		Description: `Boots the app (config, DI container, datasources, cache) without the HTTP
+	listener and starts interactive console for operational debugging and one-off
+	scripts. Objects 'app', 'config', 'cache' and 'db' are available in the console.
+
+	Type 'help' for available objects and 'exit' to quit the console.
+
+		Example:
+			<app-binary> console --envprofile prod
+			<app-binary> console --script scripts/cleanup.txt`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+			},
+			console.StringFlag{
+				Name:  "script, s",
+				Usage: "Script `FILE` to evaluate and exit, instead of interactive console",
+			},
+		},
+		Action: func(c *console.Context) error {
+			a.Config().SetString("env.active", cliEnvProfile(c))
+			if err := a.initApp(); err != nil {
+				return err
+			}
+			defer func() {
+				a.closeBrokers()
+				a.closeDataSources()
+			}()
+
+			ev, err := a.newConsoleEvaluator()
+			if err != nil {
+				return err
+			}
+			if script := c.String("script"); len(script) > 0 {
+				src, err := ioutil.ReadFile(script)
+				if err != nil {
+					return err
+				}
+				v, err := ev.Eval(string(src))
+				if err != nil {
+					return err
+				}
+				printConsoleValue(c.App.Writer, v)
+				return nil
+			}
+			a.runConsole(os.Stdin, c.App.Writer, ev)
+			return nil
+		},
+	}
+}
+
+func (a *Application) consoleVars() map[string]interface{} {
+	return map[string]interface{}{
+		"app":    a,
+		"config": a.Config(),
+		"cache":  a.CacheManager(),
+		"db":     a.DB,
+	}
+}
+
+func (a *Application) newConsoleEvaluator() (ConsoleEvaluator, error) {
+	fn := a.consoleEval
+	if fn == nil {
+		fn = defaultConsoleEvaluator
+	}
+	if fn == nil {
+		return &exprEvaluator{vars: a.consoleVars()}, nil
+	}
+	return fn(a.consoleVars())
+}
+
+// runConsole method reads the input line by line and evaluates it until
+// `exit` or end of input.
+func (a *Application) runConsole(r io.Reader, w io.Writer, ev ConsoleEvaluator) {
+	fmt.Fprintf(w, "aah console, app '%s' profile '%s'. Type 'help' for objects, 'exit' to quit.\n",
+		a.Name(), a.EnvProfile())
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, "aah> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case "exit", "quit":
+			return
+		case "help":
+			a.printConsoleHelp(w)
+			continue
+		}
+		v, err := ev.Eval(line)
+		if err != nil {
+			fmt.Fprintf(w, "error: %v\n", err)
+			continue
+		}
+		printConsoleValue(w, v)
+	}
+}
+
+func (a *Application) printConsoleHelp(w io.Writer) {
+	vars := a.consoleVars()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %T\n", name, vars[name])
+	}
+}
+
+func printConsoleValue(w io.Writer, v interface{}) {
+	if v == nil {
+		return
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		fmt.Fprintln(w, s.String())
+		return
+	}
+	fmt.Fprintf(w, "%+v\n", v)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// exprEvaluator
+//______________________________________________________________________________
+
+// exprEvaluator is the built-in console evaluator, it evaluates the Go
+// expressions of method calls, fields and literals using reflection. Script
+// is evaluated line by line, lines starting with `//` or `#` are skipped.
+type exprEvaluator struct {
+	vars map[string]interface{}
+}
+
+func (e *exprEvaluator) Eval(src string) (interface{}, error) {
+	var result interface{}
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		expr, err := parser.ParseExpr(line)
+		if err != nil {
+			return nil, err
+		}
+		v, err := e.eval(expr)
+		if err != nil {
+			return nil, err
+		}
+		result = nil
+		if v.IsValid() && v.CanInterface() {
+			result = v.Interface()
+		}
+	}
+	return result, nil
+}
+
+func (e *exprEvaluator) eval(expr ast.Expr) (reflect.Value, error) {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return e.eval(t.X)
+	case *ast.BasicLit:
+		return evalBasicLit(t)
+	case *ast.Ident:
+		switch t.Name {
+		case "true", "false":
+			return reflect.ValueOf(t.Name == "true"), nil
+		case "nil":
+			return reflect.Value{}, nil
+		}
+		v, found := e.vars[t.Name]
+		if !found {
+			return reflect.Value{}, fmt.Errorf("undefined: %s", t.Name)
+		}
+		return reflect.ValueOf(v), nil
+	case *ast.SelectorExpr:
+		x, err := e.eval(t.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return selectMember(x, t.Sel.Name)
+	case *ast.CallExpr:
+		fn, err := e.eval(t.Fun)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args := make([]reflect.Value, len(t.Args))
+		for i, arg := range t.Args {
+			if args[i], err = e.eval(arg); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+		return callFunc(fn, args)
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported expression '%T', build app with tag 'yaegi' for Go interpreter", expr)
+}
+
+func evalBasicLit(lit *ast.BasicLit) (reflect.Value, error) {
+	switch lit.Kind {
+	case token.STRING, token.CHAR:
+		s, err := strconv.Unquote(lit.Value)
+		return reflect.ValueOf(s), err
+	case token.INT:
+		i, err := strconv.ParseInt(lit.Value, 0, 64)
+		return reflect.ValueOf(int(i)), err
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(lit.Value, 64)
+		return reflect.ValueOf(f), err
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported literal: %s", lit.Value)
+}
+
+func selectMember(x reflect.Value, name string) (reflect.Value, error) {
+	if !x.IsValid() {
+		return reflect.Value{}, fmt.Errorf("nil value has no member '%s'", name)
+	}
+	if m := x.MethodByName(name); m.IsValid() {
+		return m, nil
+	}
+	iv := reflect.Indirect(x)
+	if iv.Kind() == reflect.Struct {
+		if f := iv.FieldByName(name); f.IsValid() && f.CanInterface() {
+			return f, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("'%s' has no exported member '%s'", x.Type(), name)
+}
+
+func callFunc(fn reflect.Value, args []reflect.Value) (reflect.Value, error) {
+	if fn.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("'%s' is not a func", fn.Type())
+	}
+	ft := fn.Type()
+	if (!ft.IsVariadic() && len(args) != ft.NumIn()) || (ft.IsVariadic() && len(args) < ft.NumIn()-1) {
+		return reflect.Value{}, fmt.Errorf("'%s' expects %d argument(s), given %d", ft, ft.NumIn(), len(args))
+	}
+	for i := range args {
+		var pt reflect.Type
+		if ft.IsVariadic() && i >= ft.NumIn()-1 {
+			pt = ft.In(ft.NumIn() - 1).Elem()
+		} else {
+			pt = ft.In(i)
+		}
+		if !args[i].IsValid() {
+			args[i] = reflect.Zero(pt)
+			continue
+		}
+		if !args[i].Type().ConvertibleTo(pt) {
+			return reflect.Value{}, fmt.Errorf("argument %d: cannot use '%s' as '%s'", i+1, args[i].Type(), pt)
+		}
+		args[i] = args[i].Convert(pt)
+	}
+
+	out := fn.Call(args)
+	if n := len(out); n > 0 && ft.Out(n-1) == errorType {
+		if !out[n-1].IsNil() {
+			return reflect.Value{}, out[n-1].Interface().(error)
+		}
+		out = out[:n-1]
+	}
+	switch len(out) {
+	case 0:
+		return reflect.Value{}, nil
+	case 1:
+		return out[0], nil
+	}
+	values := make([]interface{}, len(out))
+	for i, o := range out {
+		values[i] = o.Interface()
+	}
+	return reflect.ValueOf(values), nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/console"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleExprEvaluator(t *testing.T) {
+	cfg, err := config.ParseString(`
+	name = "console"
+	server {
+		port = 8080
+	}`)
+	assert.Nil(t, err)
+	ev := &exprEvaluator{vars: map[string]interface{}{
+		"config": cfg,
+		"join":   strings.Join,
+		"split":  strings.Split,
+	}}
+
+	testcases := []struct {
+		src      string
+		expected interface{}
+		err      string
+	}{
+		{src: `config.StringDefault("name", "")`, expected: "console"},
+		{src: `config.IntDefault("server.port", 0)`, expected: 8080},
+		{src: `config.String("name")`, expected: []interface{}{"console", true}},
+		{src: `config.IsExists("server.timeout")`, expected: false},
+		{src: "# comment\n(config.BoolDefault(\"flag\", true))", expected: true},
+		{src: `split("a,b", ",")`, expected: []string{"a", "b"}},
+		{src: `config.Unknown()`, err: "'*config.Config' has no exported member 'Unknown'"},
+		{src: `users.List()`, err: "undefined: users"},
+		{src: `config.IntDefault("server.port")`, err: "expects 2 argument(s), given 1"},
+		{src: `config.IntDefault("server.port", "x")`, err: "argument 2: cannot use 'string' as 'int'"},
+		{src: `1 + 2`, err: "unsupported expression '*ast.BinaryExpr'"},
+	}
+	for _, tc := range testcases {
+		v, err := ev.Eval(tc.src)
+		if len(tc.err) > 0 {
+			assert.NotNil(t, err, tc.src)
+			assert.True(t, strings.Contains(err.Error(), tc.err), err.Error())
+			continue
+		}
+		assert.Nil(t, err, tc.src)
+		assert.Equal(t, tc.expected, v, tc.src)
+	}
+}
+
+func TestConsoleCommand(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	var buf bytes.Buffer
+	ev, err := a.newConsoleEvaluator()
+	assert.Nil(t, err)
+	a.runConsole(strings.NewReader("help\n\napp.Name()\ndb(\"none\")\napp.Unknown\nexit\napp.Name()\n"), &buf, ev)
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "aah console, app 'webapp1' profile 'dev'"))
+	assert.True(t, strings.Contains(out, "  config   *config.Config\n"))
+	assert.True(t, strings.Contains(out, "aah> webapp1\n"))
+	assert.True(t, strings.Contains(out, "error: '*aah.Application' has no exported member 'Unknown'"))
+	assert.Equal(t, 1, strings.Count(out, "aah> webapp1\n"))
+
+	// script and custom evaluator
+	dir, err := ioutil.TempDir("", "aah-console")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "script.txt")
+	assert.Nil(t, ioutil.WriteFile(script, []byte("app.Name()\napp.EnvProfile()\n"), 0644))
+	buf.Reset()
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdConsole()}
+	err = cliApp.Run([]string{"app", "console", "--script", script})
+	assert.Nil(t, err)
+	assert.Equal(t, "dev\n", buf.String())
+
+	err = cliApp.Run([]string{"app", "console", "--script", filepath.Join(dir, "not-exists")})
+	assert.True(t, os.IsNotExist(err))
+
+	a.SetConsoleEvaluator(func(vars map[string]interface{}) (ConsoleEvaluator, error) {
+		return nil, errors.New("evaluator not available")
+	})
+	err = cliApp.Run([]string{"app", "console", "--script", script})
+	assert.Equal(t, "evaluator not available", err.Error())
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build yaegi
+
+package aah
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/traefik/yaegi/interp"
+	"github.com/traefik/yaegi/stdlib"
+)
+
+// Built-in console evaluator adapter of Go interpreter
+// `github.com/traefik/yaegi`. It is included in the build with tag `yaegi`,
+// for e.g.:
+// 	go build -tags yaegi
+//
+// Add the dependency into application `go.mod` before enabling it.
+func init() {
+	defaultConsoleEvaluator = newYaegiEvaluator
+}
+
+type yaegiEvaluator struct {
+	i *interp.Interpreter
+}
+
+func newYaegiEvaluator(vars map[string]interface{}) (ConsoleEvaluator, error) {
+	i := interp.New(interp.Options{})
+	if err := i.Use(stdlib.Symbols); err != nil {
+		return nil, err
+	}
+
+	// app context objects are exported via package `aah` and assigned to
+	// the console variables
+	symbols := make(map[string]reflect.Value)
+	var src strings.Builder
+	src.WriteString("import \"aah\"\n")
+	for name, v := range vars {
+		exported := strings.ToUpper(name[:1]) + name[1:]
+		symbols[exported] = reflect.ValueOf(v)
+		fmt.Fprintf(&src, "%s := aah.%s\n", name, exported)
+	}
+	if err := i.Use(interp.Exports{"aah/aah": symbols}); err != nil {
+		return nil, err
+	}
+	if _, err := i.Eval(src.String()); err != nil {
+		return nil, err
+	}
+	return &yaegiEvaluator{i: i}, nil
+}
+
+func (y *yaegiEvaluator) Eval(src string) (interface{}, error) {
+	v, err := y.i.Eval(src)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
@@ -15,11 +15,14 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/internal/util"
 )
 
@@ -37,6 +40,9 @@ type Reply struct {
 	body     *bytes.Buffer
 	cookies  []*http.Cookie
 	err      *Error
+	dataType reflect.Type
+	etag     string
+	modTime  time.Time
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -81,6 +87,11 @@ func (r *Reply) Found() *Reply {
 	return r.Status(http.StatusFound)
 }
 
+// NotModified method sets the HTTP Code as 304 RFC 7232, 4.1.
+func (r *Reply) NotModified() *Reply {
+	return r.Status(http.StatusNotModified)
+}
+
 // TemporaryRedirect method sets the HTTP Code as 307 RFC 7231, 6.4.7.
 func (r *Reply) TemporaryRedirect() *Reply {
 	return r.Status(http.StatusTemporaryRedirect)
@@ -187,6 +198,122 @@ func (r *Reply) XML(data interface{}) *Reply {
 	return r
 }
 
+// Negotiated method renders given data as JSON, XML or HTML response based on
+// content negotiation of request `Accept` header. HTML is offered only when
+// view engine is enabled, if data is not `aah.Data` then it is available as
+// `Data` in the view args.
+//
+// For vendor media types, for e.g.: `application/vnd.myapp.v2+json`, the
+// response Content-Type is vendor media type as requested. Use
+// `ctx.Req.AcceptContentType().Version()` to get the requested version.
+//
+// If none of the offers acceptable then it replies with HTTP 406.
+func (r *Reply) Negotiated(data interface{}) *Reply {
+	offers := []string{ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeXML.Mime}
+	if r.ctx.a.viewMgr != nil && r.ctx.a.viewMgr.engine != nil {
+		offers = append(offers, ahttp.ContentTypeHTML.Mime)
+	}
+
+	offer := r.ctx.Negotiate(offers...)
+	vendorMime := r.ctx.Req.AcceptContentType().VendorMime()
+	switch offer {
+	case ahttp.ContentTypeJSON.Mime:
+		if strings.HasSuffix(vendorMime, "+json") {
+			r.ContentType(vendorMime + "; charset=utf-8")
+		}
+		return r.JSON(data)
+	case ahttp.ContentTypeXML.Mime:
+		if strings.HasSuffix(vendorMime, "+xml") {
+			r.ContentType(vendorMime + "; charset=utf-8")
+		}
+		return r.XML(data)
+	case ahttp.ContentTypeHTML.Mime:
+		if d, ok := data.(Data); ok {
+			return r.HTML(d)
+		}
+		return r.HTML(Data{"Data": data})
+	}
+
+	return r.NotAcceptable().Error(newError(ErrContentTypeNotOffered, http.StatusNotAcceptable))
+}
+
+// OkT method sets the HTTP Status as 200 OK and renders given data with
+// content negotiation, see `Reply().Negotiated`. Data type is recorded for
+// dump log redaction and in `dev` profile it's verified against the response
+// type registered via `AddResponseType`.
+func (r *Reply) OkT(data interface{}) *Reply {
+	return r.Ok().typed(data)
+}
+
+// CreatedT method sets the HTTP Status as 201 Created and renders given data
+// with content negotiation, see `Reply().OkT`.
+func (r *Reply) CreatedT(data interface{}) *Reply {
+	return r.Created().typed(data)
+}
+
+// AcceptedT method sets the HTTP Status as 202 Accepted and renders given data
+// with content negotiation, see `Reply().OkT`.
+func (r *Reply) AcceptedT(data interface{}) *Reply {
+	return r.Accepted().typed(data)
+}
+
+// CreatedAt method sets the HTTP Status as 201 Created and sets the
+// `Location` header with reverse URL of given route name and arguments.
+//
+// 	c.Reply().CreatedAt("show_user", user.ID).JSON(user)
+func (r *Reply) CreatedAt(routeName string, args ...interface{}) *Reply {
+	location := r.ctx.RouteURL(routeName, args...)
+	if strings.HasPrefix(location, "//") {
+		location = r.ctx.Req.Scheme + ":" + location
+	}
+	return r.Created().Header(ahttp.HeaderLocation, location)
+}
+
+// Paginated method renders given page data as JSON with standard pagination
+// envelope and sets the `Link` header (RFC 8288) for first, prev, next and
+// last pages. Page size is from `ctx.PerPage()` and `total` is total number
+// of items.
+//
+// 	{
+// 	  "data": [ ... ],
+// 	  "pagination": { "page": 2, "per_page": 20, "total": 95, "total_pages": 5 }
+// 	}
+func (r *Reply) Paginated(data interface{}, page, total int) *Reply {
+	p := newPagination(page, r.ctx.PerPage(), total)
+	if links := r.ctx.pageLinks(p); len(links) > 0 {
+		r.Header(ahttp.HeaderLink, links)
+	}
+	return r.JSON(&PageEnvelope{Data: data, Pagination: p})
+}
+
+// NoContentIfNil method sets the HTTP Status as 204 No Content if given value
+// is nil, otherwise it renders the value with content negotiation, see
+// `Reply().OkT`.
+func (r *Reply) NoContentIfNil(v interface{}) *Reply {
+	if isNil(v) {
+		return r.NoContent()
+	}
+	return r.OkT(v)
+}
+
+// Problem method renders given problem details as JSON (RFC 7807) and sets
+// HTTP Content-Type as 'application/problem+json; charset=utf-8'. Problem
+// status is used as HTTP Status if present, otherwise reply status is set
+// into the problem details.
+func (r *Reply) Problem(p *Problem) *Reply {
+	if p.Status > 0 {
+		r.Status(p.Status)
+	} else {
+		p.Status = r.Code
+	}
+	if len(p.Title) == 0 {
+		p.Title = http.StatusText(p.Status)
+	}
+	r.ContentType(ahttp.ContentTypeProblemJSON.String())
+	r.Render(&jsonRender{Data: p})
+	return r
+}
+
 // Text method renders given data as Plain Text response with given values
 // and it sets HTTP Content-Type as 'text/plain; charset=utf-8'.
 func (r *Reply) Text(format string, values ...interface{}) *Reply {
@@ -195,6 +322,78 @@ func (r *Reply) Text(format string, values ...interface{}) *Reply {
 	return r
 }
 
+// CSV method streams given rows as CSV response, rows are written into
+// response as it's read, so it's suitable for large exports. It sets
+// HTTP 'Content-Type' as 'text/csv; charset=utf-8' and `Content-Disposition`
+// as `attachment` with file name `export.csv` if it's not set already.
+//
+// Rows value could be slice, array or channel of rows, see `aah.Sheet` for
+// supported row types. Optionally column mapping could be given to pick,
+// order and name the header of columns.
+//
+// 	ctx.Reply().
+// 		Header(ahttp.HeaderContentDisposition, `attachment; filename="users.csv"`).
+// 		CSV(users, aah.Column{Key: "Email", Header: "E-mail"}, aah.Column{Key: "Name"})
+func (r *Reply) CSV(rows interface{}, columns ...Column) *Reply {
+	r.ContentType(ahttp.ContentTypeCSV.String())
+	r.attachment("export.csv")
+	r.Render(&csvRender{Rows: rows, Columns: columns})
+	return r
+}
+
+// Xlsx method streams given sheets as Office Open XML spreadsheet (Excel)
+// response, rows are written into response as it's read. It sets HTTP
+// 'Content-Type' as 'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet'
+// and `Content-Disposition` as `attachment` with file name `export.xlsx` if it's
+// not set already.
+//
+// 	ctx.Reply().Xlsx(&aah.Sheet{
+// 		Name:    "Users",
+// 		Rows:    users,
+// 		Columns: []aah.Column{{Key: "Email", Header: "E-mail"}, {Key: "Name"}},
+// 	})
+func (r *Reply) Xlsx(sheets ...*Sheet) *Reply {
+	r.ContentType(ahttp.ContentTypeXlsx.String())
+	r.attachment("export.xlsx")
+	r.gzip = false // xlsx is already zip compressed
+	r.Render(&xlsxRender{Sheets: sheets})
+	return r
+}
+
+// Feed method renders given feed as RSS 2.0 or Atom response. Format is
+// determined from query param `format` (`rss` or `atom`) otherwise content
+// negotiation of request `Accept` header, default is RSS. It sets HTTP
+// 'Content-Type' as 'application/rss+xml; charset=utf-8' or
+// 'application/atom+xml; charset=utf-8'.
+//
+// Header `Last-Modified` is set from `Feed.LastModified()` and weak `ETag`
+// from format and last modified time, if it's not set already. So conditional
+// GET request gets 304 Not Modified.
+//
+// 	c.Reply().Feed(&aah.Feed{
+// 		Title: "aah blog",
+// 		Link:  "https://aahframework.org/blog",
+// 		Items: items,
+// 	})
+func (r *Reply) Feed(feed *Feed) *Reply {
+	format := feedFormat(r.ctx.Req)
+	if format == FeedFormatAtom {
+		r.ContentType(ahttp.ContentTypeAtom.String())
+	} else {
+		r.ContentType(ahttp.ContentTypeRSS.String())
+	}
+	r.HeaderAppend(ahttp.HeaderVary, ahttp.HeaderAccept)
+
+	if modTime := feed.LastModified(); !modTime.IsZero() {
+		r.LastModified(modTime)
+		if len(r.etag) == 0 {
+			r.ETag(fmt.Sprintf("W/%s-%d-%d", format, modTime.Unix(), len(feed.Items)))
+		}
+	}
+	r.Render(&feedRender{Format: format, Feed: feed})
+	return r
+}
+
 // Binary method writes given bytes into response. It auto-detects the
 // content type of the given bytes if header `Content-Type` is not set.
 func (r *Reply) Binary(b []byte) *Reply {
@@ -353,6 +552,42 @@ func (r *Reply) HeaderAppend(key, value string) *Reply {
 	return r
 }
 
+// ETag method sets the `ETag` header for the response, value is quoted if
+// it's not quoted already. Weak validator could be given with prefix `W/`.
+//
+// If the request header `If-None-Match` matches with ETag (weak comparison)
+// on GET or HEAD request then framework replies 304 Not Modified, response
+// body is not rendered.
+//
+// 	c.Reply().ETag(fmt.Sprintf("%d-%d", user.ID, user.Version)).JSON(user)
+func (r *Reply) ETag(v string) *Reply {
+	if len(v) == 0 {
+		return r
+	}
+	if !strings.HasSuffix(v, `"`) {
+		if strings.HasPrefix(v, "W/") {
+			v = `W/"` + v[2:] + `"`
+		} else {
+			v = `"` + v + `"`
+		}
+	}
+	r.etag = v
+	return r.Header(ahttp.HeaderETag, v)
+}
+
+// LastModified method sets the `Last-Modified` header for the response.
+//
+// If the request header `If-Modified-Since` is not before the given time on
+// GET or HEAD request then framework replies 304 Not Modified, response body
+// is not rendered. Header `If-None-Match` takes precedence if present.
+func (r *Reply) LastModified(t time.Time) *Reply {
+	if t.IsZero() {
+		return r
+	}
+	r.modTime = t.UTC().Truncate(time.Second)
+	return r.Header(ahttp.HeaderLastModified, r.modTime.Format(http.TimeFormat))
+}
+
 // Done method is used to indicate response has already been written using
 // `aah.Context.Res` so no further action is needed from framework.
 //
@@ -400,6 +635,61 @@ func (r *Reply) Body() *bytes.Buffer {
 	return r.body
 }
 
+func (r *Reply) attachment(filename string) {
+	if len(r.ctx.Res.Header().Get(ahttp.HeaderContentDisposition)) == 0 {
+		r.Header(ahttp.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	}
+}
+
+func (r *Reply) isStream() bool {
+	switch r.Rdr.(type) {
+	case *binaryRender, *csvRender, *xlsxRender:
+		return true
+	}
+	return false
+}
+
+func (r *Reply) typed(data interface{}) *Reply {
+	r.dataType = reflect.TypeOf(data)
+	if r.ctx.a.IsEnvProfile(settings.DefaultEnvProfile) && r.ctx.action != nil {
+		if res := r.ctx.action.Response(r.Code); res != nil && res.Type != nil &&
+			derefType(res.Type) != derefType(r.dataType) {
+			r.ctx.Log().Warnf("Typed reply '%v' does not match with registered response type '%v' for status %d on action '%s.%s'",
+				r.dataType, res.Type, r.Code, r.ctx.controller.FqName, r.ctx.action.Name)
+		}
+	}
+	return r.Negotiated(data)
+}
+
+// isNotModified method evaluates the conditional request headers against
+// reply ETag and Last-Modified values as per RFC 7232.
+func (r *Reply) isNotModified(req *ahttp.Request) bool {
+	if (len(r.etag) == 0 && r.modTime.IsZero()) || r.Code < 200 || r.Code > 299 ||
+		(req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return false
+	}
+
+	if inm := req.Header.Get(ahttp.HeaderIfNoneMatch); len(inm) > 0 {
+		if len(r.etag) == 0 {
+			return false
+		}
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(r.etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get(ahttp.HeaderIfModifiedSince); len(ims) > 0 && !r.modTime.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !r.modTime.After(t)
+		}
+	}
+	return false
+}
+
 func (r *Reply) isHTML() bool {
 	return ahttp.ContentTypeHTML.IsEqual(r.ContType)
 }
@@ -413,19 +703,6 @@ func newReply(ctx *Context) *Reply {
 	}
 }
 
-var bufPool = &sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
-
-func acquireBuffer() *bytes.Buffer {
-	return bufPool.Get().(*bytes.Buffer)
-}
-
-func releaseBuffer(b *bytes.Buffer) {
-	if b != nil {
-		b.Reset()
-		bufPool.Put(b)
-	}
-}
-
 var builderPool = &sync.Pool{New: func() interface{} { return new(strings.Builder) }}
 
 func acquireBuilder() *strings.Builder {
@@ -450,6 +727,16 @@ type Render interface {
 	Render(io.Writer) error
 }
 
+// StreamRender interface is implemented by the renders which could write the
+// response directly on the wire without buffering the full body. Render
+// which does not implement it is buffered before writing, for e.g.:
+// `RenderFunc`. Reply is buffered too if response dump log with body,
+// minify or idempotency is applicable to the request.
+type StreamRender interface {
+	Render
+	RenderTo(w io.Writer) error
+}
+
 // RenderFunc type is an adapter to allow the use of regular function as
 // custom Render.
 type RenderFunc func(w io.Writer) error
@@ -497,6 +784,11 @@ func (j *jsonRender) Render(w io.Writer) error {
 	return json.NewEncoder(w).Encode(j.Data)
 }
 
+// RenderTo method streams JSON directly into HTTP response.
+func (j *jsonRender) RenderTo(w io.Writer) error {
+	return j.Render(w)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // JSONP Render
 //______________________________________________________________________________
@@ -556,6 +848,11 @@ func (x *xmlRender) Render(w io.Writer) error {
 	return xml.NewEncoder(w).Encode(x.Data)
 }
 
+// RenderTo method streams XML directly into HTTP response.
+func (x *xmlRender) RenderTo(w io.Writer) error {
+	return x.Render(w)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Data
 //______________________________________________________________________________
@@ -5,17 +5,25 @@
 package aah
 
 import (
+	"archive/zip"
 	"bytes"
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -256,3 +264,364 @@ func TestHTMLRenderTmplNil(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "template is nil", err.Error())
 }
+
+func TestReplyNegotiated(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	err := a.initLog()
+	assert.Nil(t, err)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	data := map[string]interface{}{"name": "aah"}
+	testcases := []struct {
+		accept      string
+		contentType string
+		code        int
+	}{
+		{accept: "application/json", contentType: "application/json; charset=utf-8", code: http.StatusOK},
+		{accept: "application/xml, application/json;q=0.8", contentType: "application/xml; charset=utf-8", code: http.StatusOK},
+		{accept: "application/vnd.myapp.v2+json", contentType: "application/vnd.myapp.v2+json; charset=utf-8", code: http.StatusOK},
+		{accept: "text/html", contentType: "", code: http.StatusNotAcceptable},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/users/1", nil)
+		req.Header.Set(ahttp.HeaderAccept, tc.accept)
+		ctx := newContext(nil, req)
+		ctx.a = a
+		ctx.Req.SetAcceptContentType(ahttp.NegotiateContentType(req))
+		re := newReply(ctx)
+
+		re.Negotiated(data)
+		assert.Equal(t, tc.code, re.Code, "accept: %s", tc.accept)
+		assert.Equal(t, tc.contentType, re.ContType, "accept: %s", tc.accept)
+	}
+}
+
+func TestReplyCSVAndXlsx(t *testing.T) {
+	type user struct {
+		Name   string
+		Email  string
+		Age    int
+		secret string
+	}
+	users := []user{{Name: "John", Email: "john@example.com", Age: 28}, {Name: "Jane, Doe", Email: "jane@example.com", Age: 32}}
+
+	buf := new(bytes.Buffer)
+	err := (&csvRender{Rows: users}).Render(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "Name,Email,Age\nJohn,john@example.com,28\n\"Jane, Doe\",jane@example.com,32\n", buf.String())
+
+	// header mapping and channel rows
+	ch := make(chan *user, 2)
+	ch <- &users[0]
+	ch <- &users[1]
+	close(ch)
+	buf.Reset()
+	err = (&csvRender{Rows: ch, Columns: []Column{{Key: "Email", Header: "E-mail"}, {Key: "Name"}}}).Render(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "E-mail,Name\njohn@example.com,John\njane@example.com,\"Jane, Doe\"\n", buf.String())
+
+	// map and slice rows
+	buf.Reset()
+	err = (&csvRender{Rows: []Data{{"b": 2, "a": "x"}}}).Render(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b\nx,2\n", buf.String())
+
+	buf.Reset()
+	err = (&csvRender{Rows: [][]string{{"1", "2"}}, Columns: []Column{{Header: "A"}, {Header: "B"}}}).Render(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "A,B\n1,2\n", buf.String())
+
+	err = (&csvRender{Rows: "invalid"}).Render(buf)
+	assert.Equal(t, "aah: unsupported rows type 'string'", err.Error())
+
+	err = (&csvRender{Rows: make(chan<- []string)}).Render(buf)
+	assert.Equal(t, "aah: rows channel 'chan<- []string' is send-only", err.Error())
+
+	// xlsx
+	buf.Reset()
+	err = (&xlsxRender{Sheets: []*Sheet{{Name: "Users", Rows: users}, {Rows: [][]interface{}{{true, "a<b"}}}}}).Render(buf)
+	assert.Nil(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	entries := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		b, _ := ioutil.ReadAll(rc)
+		_ = rc.Close()
+		entries[f.Name] = string(b)
+	}
+	assert.Equal(t, 6, len(entries))
+	assert.True(t, strings.Contains(entries["xl/workbook.xml"], `<sheet name="Users" sheetId="1" r:id="rId1"/><sheet name="Sheet2" sheetId="2" r:id="rId2"/>`))
+	assert.True(t, strings.Contains(entries["xl/worksheets/sheet1.xml"], `<row r="2"><c r="A2" t="inlineStr"><is><t>John</t></is></c><c r="B2" t="inlineStr"><is><t>john@example.com</t></is></c><c r="C2"><v>28</v></c></row>`))
+	assert.True(t, strings.Contains(entries["xl/worksheets/sheet2.xml"], `<row r="1"><c r="A1" t="b"><v>1</v></c><c r="B1" t="inlineStr"><is><t>a&lt;b</t></is></c></row>`))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+
+	// reply
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:8080/export", nil))
+	re := newReply(ctx)
+	re.CSV(users)
+	assert.Equal(t, ahttp.ContentTypeCSV.String(), re.ContType)
+	assert.Equal(t, `attachment; filename="export.csv"`, ctx.Res.Header().Get(ahttp.HeaderContentDisposition))
+	assert.True(t, re.isStream())
+
+	ctx = newContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:8080/export", nil))
+	re = newReply(ctx)
+	re.Header(ahttp.HeaderContentDisposition, `attachment; filename="users.xlsx"`).Xlsx(&Sheet{Rows: users})
+	assert.Equal(t, ahttp.ContentTypeXlsx.String(), re.ContType)
+	assert.Equal(t, `attachment; filename="users.xlsx"`, ctx.Res.Header().Get(ahttp.HeaderContentDisposition))
+	assert.False(t, re.gzip)
+}
+
+func TestReplyTyped(t *testing.T) {
+	type account struct {
+		Username string `json:"username"`
+		Password string `json:"password" dump:"redact"`
+	}
+	type accounts struct {
+		Items []*account `json:"items"`
+		Total int        `json:"total"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/accounts", nil)
+	r.Header.Set(ahttp.HeaderAccept, ahttp.ContentTypeJSON.Mime)
+	ctx := newContext(httptest.NewRecorder(), r)
+	ctx.a = newApp()
+
+	re := ctx.Reply().CreatedT(&account{Username: "jeeva", Password: "welcome123"})
+	assert.Equal(t, http.StatusCreated, re.Code)
+	assert.Equal(t, reflect.TypeOf(&account{}), re.dataType)
+	assert.True(t, strings.HasPrefix(re.ContType, ahttp.ContentTypeJSON.Mime))
+
+	b := redactJSON([]byte(`{"items":[{"username":"jeeva","password":"welcome123"}],"total":1}`), reflect.TypeOf(accounts{}))
+	assert.Equal(t, `{"items":[{"password":"*****","username":"jeeva"}],"total":1}`, string(b))
+
+	b = redactJSON([]byte(`{"username":"jeeva"}`), reflect.TypeOf(sample{}))
+	assert.Equal(t, `{"username":"jeeva"}`, string(b))
+}
+
+func TestReplyRESTHelpers(t *testing.T) {
+	a := newTestApp(t, filepath.Join(testdataBaseDir(), "webapp1"))
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	newTestContext := func(target string) *Context {
+		ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		ctx.a = a
+		return ctx
+	}
+
+	// CreatedAt
+	ctx := newTestContext("http://localhost:8080/doc")
+	re := newReply(ctx).CreatedAt("version_home", "v0.12")
+	assert.Equal(t, http.StatusCreated, re.Code)
+	assert.Equal(t, "http://localhost:8080/doc/v0.12", ctx.Res.Header().Get(ahttp.HeaderLocation))
+
+	// Paginated
+	ctx = newTestContext("http://localhost:8080/users?page=2&per_page=10&q=aah")
+	re = newReply(ctx).Paginated([]string{"a", "b"}, ctx.Page(), 35)
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), re.ContType)
+	buf := new(bytes.Buffer)
+	assert.Nil(t, re.Rdr.Render(buf))
+	assert.Equal(t, `{"data":["a","b"],"pagination":{"page":2,"per_page":10,"total":35,"total_pages":4}}`, strings.TrimSpace(buf.String()))
+	assert.Equal(t, `</users?page=1&per_page=10&q=aah>; rel="first", </users?page=1&per_page=10&q=aah>; rel="prev", `+
+		`</users?page=3&per_page=10&q=aah>; rel="next", </users?page=4&per_page=10&q=aah>; rel="last"`,
+		ctx.Res.Header().Get(ahttp.HeaderLink))
+
+	ctx = newTestContext("http://localhost:8080/users?per_page=500")
+	assert.Equal(t, 1, ctx.Page())
+	assert.Equal(t, 100, ctx.PerPage())
+	newReply(ctx).Paginated([]string{}, ctx.Page(), 0)
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderLink))
+
+	// NoContentIfNil
+	var u *struct{ Name string }
+	ctx = newTestContext("http://localhost:8080/users/1")
+	ctx.Req.SetAcceptContentType(ahttp.NegotiateContentType(ctx.Req.Unwrap()))
+	re = newReply(ctx).NoContentIfNil(u)
+	assert.Equal(t, http.StatusNoContent, re.Code)
+	assert.Nil(t, re.Rdr)
+	re = newReply(ctx).NoContentIfNil(&struct{ Name string }{"aah"})
+	assert.Equal(t, http.StatusOK, re.Code)
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), re.ContType)
+
+	// Problem
+	re = newReply(newTestContext("http://localhost:8080/users/1")).Problem(&Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Status:     http.StatusForbidden,
+		Detail:     "Your current balance is 30, but that costs 50.",
+		Extensions: map[string]interface{}{"balance": 30},
+	})
+	assert.Equal(t, http.StatusForbidden, re.Code)
+	assert.Equal(t, ahttp.ContentTypeProblemJSON.String(), re.ContType)
+	buf.Reset()
+	assert.Nil(t, re.Rdr.Render(buf))
+	assert.Equal(t, `{"balance":30,"detail":"Your current balance is 30, but that costs 50.","status":403,`+
+		`"title":"Forbidden","type":"https://example.com/probs/out-of-credit"}`, strings.TrimSpace(buf.String()))
+
+	re = newReply(newTestContext("http://localhost:8080/users/1")).BadRequest().Problem(&Problem{Detail: "invalid"})
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+	buf.Reset()
+	assert.Nil(t, re.Rdr.Render(buf))
+	assert.Equal(t, `{"detail":"invalid","status":400,"title":"Bad Request"}`, strings.TrimSpace(buf.String()))
+}
+
+func TestReplyConditional(t *testing.T) {
+	a := newTestApp(t, filepath.Join(testdataBaseDir(), "webapp1"))
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	modTime := time.Date(2018, 6, 12, 10, 30, 20, 0, time.UTC)
+
+	testcases := []struct {
+		label   string
+		method  string
+		headers map[string]string
+		etag    string
+		modTime time.Time
+		code    int
+	}{
+		{label: "no condition", method: http.MethodGet, etag: "v1", code: http.StatusOK},
+		{label: "etag match", method: http.MethodGet, etag: "v1", code: http.StatusNotModified,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `"v0", "v1"`}},
+		{label: "etag weak match", method: http.MethodGet, etag: "W/v1", code: http.StatusNotModified,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `"v1"`}},
+		{label: "etag star", method: http.MethodHead, etag: `"v1"`, code: http.StatusNotModified,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `*`}},
+		{label: "etag mismatch", method: http.MethodGet, etag: "v2", code: http.StatusOK,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `W/"v1"`}},
+		{label: "etag post", method: http.MethodPost, etag: "v1", code: http.StatusOK,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `"v1"`}},
+		{label: "not modified since", method: http.MethodGet, modTime: modTime, code: http.StatusNotModified,
+			headers: map[string]string{ahttp.HeaderIfModifiedSince: modTime.Format(http.TimeFormat)}},
+		{label: "modified since", method: http.MethodGet, modTime: modTime.Add(time.Minute), code: http.StatusOK,
+			headers: map[string]string{ahttp.HeaderIfModifiedSince: modTime.Format(http.TimeFormat)}},
+		{label: "etag precedence", method: http.MethodGet, etag: "v2", modTime: modTime, code: http.StatusOK,
+			headers: map[string]string{ahttp.HeaderIfNoneMatch: `"v1"`, ahttp.HeaderIfModifiedSince: modTime.Format(http.TimeFormat)}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, "http://localhost:8080/users/1", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			ctx := newContext(w, req)
+			ctx.a = a
+			rendered := false
+			ctx.Reply().ETag(tc.etag).LastModified(tc.modTime).Render(RenderFunc(func(w io.Writer) error {
+				rendered = true
+				_, err := w.Write([]byte("user 1"))
+				return err
+			}))
+			a.he.writeReply(ctx)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, tc.code == http.StatusOK, rendered)
+			if len(tc.etag) > 0 {
+				assert.True(t, strings.HasSuffix(w.Header().Get(ahttp.HeaderETag), `"`))
+			}
+			if !tc.modTime.IsZero() {
+				assert.Equal(t, tc.modTime.Format(http.TimeFormat), w.Header().Get(ahttp.HeaderLastModified))
+			}
+		})
+	}
+}
+
+func TestReplyFeed(t *testing.T) {
+	a := newTestApp(t, filepath.Join(testdataBaseDir(), "webapp1"))
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	published := time.Date(2018, 6, 12, 10, 30, 20, 0, time.UTC)
+	feed := &Feed{
+		Title:       "aah blog",
+		Link:        "https://aahframework.org/blog",
+		Description: "aah framework news",
+		Author:      &FeedAuthor{Name: "Jeeva", Email: "jeeva@example.com"},
+		Items: []*FeedItem{
+			{
+				Title:       "aah v1.0 released",
+				Link:        "https://aahframework.org/blog/v1-released",
+				Description: "Release notes",
+				Content:     "<p>Hello</p>",
+				Categories:  []string{"release"},
+				Published:   published,
+			},
+			{
+				ID:        "tag:aahframework.org,2018:post-2",
+				Title:     "Roadmap",
+				Published: published.Add(-time.Hour),
+				Updated:   published.Add(time.Minute),
+			},
+		},
+	}
+	assert.Equal(t, published.Add(time.Minute), feed.LastModified())
+
+	testcases := []struct {
+		label       string
+		url         string
+		headers     map[string]string
+		code        int
+		contentType string
+		contains    []string
+	}{
+		{
+			label:       "default rss",
+			url:         "http://localhost:8080/blog/feed",
+			code:        http.StatusOK,
+			contentType: "application/rss+xml; charset=utf-8",
+			contains: []string{`<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">`, `<title>aah blog</title>`,
+				`<managingEditor>jeeva@example.com (Jeeva)</managingEditor>`,
+				`<content:encoded><![CDATA[<p>Hello</p>]]></content:encoded>`,
+				`<guid isPermaLink="true">https://aahframework.org/blog/v1-released</guid>`,
+				`<guid isPermaLink="false">tag:aahframework.org,2018:post-2</guid>`,
+				`<pubDate>Tue, 12 Jun 2018 10:30:20 +0000</pubDate>`},
+		},
+		{
+			label:       "accept atom",
+			url:         "http://localhost:8080/blog/feed",
+			headers:     map[string]string{ahttp.HeaderAccept: "application/atom+xml"},
+			code:        http.StatusOK,
+			contentType: "application/atom+xml; charset=utf-8",
+			contains: []string{`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				`<id>https://aahframework.org/blog</id>`, `<updated>2018-06-12T10:31:20Z</updated>`,
+				`<id>tag:aahframework.org,2018:post-2</id>`, `<category term="release"></category>`,
+				`<content type="html">&lt;p&gt;Hello&lt;/p&gt;</content>`},
+		},
+		{
+			label:       "format param precedence",
+			url:         "http://localhost:8080/blog/feed?format=rss",
+			headers:     map[string]string{ahttp.HeaderAccept: "application/atom+xml"},
+			code:        http.StatusOK,
+			contentType: "application/rss+xml; charset=utf-8",
+		},
+		{
+			label:       "not modified",
+			url:         "http://localhost:8080/blog/feed?format=atom",
+			headers:     map[string]string{ahttp.HeaderIfModifiedSince: published.Add(time.Minute).Format(http.TimeFormat)},
+			code:        http.StatusNotModified,
+			contentType: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			ctx := newContext(w, req)
+			ctx.a = a
+			ctx.Reply().Feed(feed)
+			a.he.writeReply(ctx)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, tc.contentType, w.Header().Get(ahttp.HeaderContentType))
+			assert.Equal(t, published.Add(time.Minute).Format(http.TimeFormat), w.Header().Get(ahttp.HeaderLastModified))
+			assert.True(t, strings.HasPrefix(w.Header().Get(ahttp.HeaderETag), `W/"`))
+			body := w.Body.String()
+			for _, s := range tc.contains {
+				assert.True(t, strings.Contains(body, s), "expected: %s\nbody: %s", s, body)
+			}
+		})
+	}
+}
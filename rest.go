@@ -0,0 +1,152 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPerPage = 20
+	defaultMaxPage = 100
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Problem details
+//______________________________________________________________________________
+
+// Problem type is problem details for HTTP APIs as per RFC 7807, used by
+// `Reply().Problem`. Extensions are additional members of problem details
+// JSON object.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON method is JSON marshaller interface implementation.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	if len(p.Type) > 0 {
+		m["type"] = p.Type
+	}
+	if len(p.Title) > 0 {
+		m["title"] = p.Title
+	}
+	if p.Status > 0 {
+		m["status"] = p.Status
+	}
+	if len(p.Detail) > 0 {
+		m["detail"] = p.Detail
+	}
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Pagination
+//______________________________________________________________________________
+
+// Pagination holds the page details of paginated reply.
+type Pagination struct {
+	Page       int `json:"page" xml:"page"`
+	PerPage    int `json:"per_page" xml:"per_page"`
+	Total      int `json:"total" xml:"total"`
+	TotalPages int `json:"total_pages" xml:"total_pages"`
+}
+
+// PageEnvelope is the standard envelope of paginated reply, see
+// `Reply().Paginated`.
+type PageEnvelope struct {
+	Data       interface{} `json:"data" xml:"data"`
+	Pagination *Pagination `json:"pagination" xml:"pagination"`
+}
+
+// PerPage method returns the page size of current request from query
+// parameter `per_page` otherwise config `render.pagination.per_page` value,
+// default is 20. Page size is limited to `render.pagination.max_per_page`,
+// default is 100.
+func (ctx *Context) PerPage() int {
+	cfg := ctx.a.Config()
+	perPage := cfg.IntDefault("render.pagination.per_page", defaultPerPage)
+	if v, err := strconv.Atoi(ctx.Req.QueryValue("per_page")); err == nil && v > 0 {
+		perPage = v
+	}
+	if max := cfg.IntDefault("render.pagination.max_per_page", defaultMaxPage); perPage > max {
+		perPage = max
+	}
+	return perPage
+}
+
+// Page method returns the current page number from query parameter `page`,
+// default is 1.
+func (ctx *Context) Page() int {
+	if v, err := strconv.Atoi(ctx.Req.QueryValue("page")); err == nil && v > 0 {
+		return v
+	}
+	return 1
+}
+
+func newPagination(page, perPage, total int) *Pagination {
+	if page < 1 {
+		page = 1
+	}
+	p := &Pagination{Page: page, PerPage: perPage, Total: total}
+	if perPage > 0 {
+		p.TotalPages = (total + perPage - 1) / perPage
+	}
+	return p
+}
+
+// pageLinks method composes the `Link` header value as per RFC 8288 for
+// first, prev, next and last pages of current request URL.
+func (ctx *Context) pageLinks(p *Pagination) string {
+	var links []string
+	link := func(page int, rel string) {
+		u := *ctx.Req.URL()
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(p.PerPage))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel))
+	}
+
+	if p.TotalPages == 0 {
+		return ""
+	}
+	link(1, "first")
+	if p.Page > 1 {
+		link(p.Page-1, "prev")
+	}
+	if p.Page < p.TotalPages {
+		link(p.Page+1, "next")
+	}
+	link(p.TotalPages, "last")
+	return strings.Join(links, ", ")
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
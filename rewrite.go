@@ -0,0 +1,153 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframe.work/config"
+)
+
+const keyRewriteRules = "rewrites.rules"
+
+// rewriteRule holds the single rewrite or redirect rule configured in the
+// section `rewrites.rules`.
+type rewriteRule struct {
+	Name  string
+	Order int
+	Code  int
+	To    string
+	From  *regexp.Regexp
+	Host  *regexp.Regexp
+}
+
+// String method is Stringer interface.
+func (rr *rewriteRule) String() string {
+	return fmt.Sprintf("rewrite(name:%s from:%s to:%s code:%d)", rr.Name, rr.From, rr.To, rr.Code)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initRewrite() error {
+	cfg := a.Config()
+	a.rewriteRules = nil
+	if !cfg.BoolDefault("rewrites.enable", true) {
+		return nil
+	}
+
+	var rules []*rewriteRule
+	for _, name := range cfg.KeysByPath(keyRewriteRules) {
+		rule, err := parseRewriteRule(cfg, name)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Order == rules[j].Order {
+			return rules[i].Name < rules[j].Name
+		}
+		return rules[i].Order < rules[j].Order
+	})
+	a.rewriteRules = rules
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTP Engine methods
+//______________________________________________________________________________
+
+// doRewrite method applies the first matching rewrite rule on the request
+// before routing. Internal rewrite updates the request path and query,
+// redirect rule prepares the redirect reply and returns true.
+func (e *HTTPEngine) doRewrite(ctx *Context) bool {
+	if len(e.a.rewriteRules) == 0 {
+		return false
+	}
+
+	r := ctx.Req.Unwrap()
+	host := stripPort(ctx.Req.Host)
+	for _, rule := range e.a.rewriteRules {
+		if rule.Host != nil && !rule.Host.MatchString(host) {
+			continue
+		}
+		m := rule.From.FindStringSubmatchIndex(r.URL.Path)
+		if m == nil {
+			continue
+		}
+
+		target := string(rule.From.ExpandString(nil, rule.To, r.URL.Path, m))
+		if rule.Code > 0 {
+			if len(r.URL.RawQuery) > 0 && !strings.Contains(target, "?") {
+				target += "?" + r.URL.RawQuery
+			}
+			ctx.Log().Debugf("Rewrite rule '%s' redirects '%s' to '%s'", rule.Name, r.URL.Path, target)
+			ctx.Reply().RedirectWithStatus(target, rule.Code)
+			return true
+		}
+
+		targetPath, targetQuery := target, ""
+		if idx := strings.IndexByte(target, '?'); idx >= 0 {
+			targetPath, targetQuery = target[:idx], target[idx+1:]
+		}
+		if len(targetQuery) > 0 && len(r.URL.RawQuery) > 0 {
+			targetQuery += "&" + r.URL.RawQuery
+		} else if len(targetQuery) == 0 {
+			targetQuery = r.URL.RawQuery
+		}
+		ctx.Log().Debugf("Rewrite rule '%s' rewrites '%s' to '%s'", rule.Name, r.URL.Path, targetPath)
+		r.URL.Path, r.URL.RawPath, r.URL.RawQuery = targetPath, "", targetQuery
+		ctx.Req.Path = targetPath
+		return false
+	}
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseRewriteRule(cfg *config.Config, name string) (*rewriteRule, error) {
+	keyPrefix := keyRewriteRules + "." + name + "."
+	from, found := cfg.String(keyPrefix + "from")
+	if !found {
+		return nil, fmt.Errorf("rewrites: '%s.from' value is missing", name)
+	}
+	to, found := cfg.String(keyPrefix + "to")
+	if !found {
+		return nil, fmt.Errorf("rewrites: '%s.to' value is missing", name)
+	}
+
+	rule := &rewriteRule{
+		Name:  name,
+		Order: cfg.IntDefault(keyPrefix+"order", 0),
+		Code:  cfg.IntDefault(keyPrefix+"code", 0),
+		To:    to,
+	}
+	switch rule.Code {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, fmt.Errorf("rewrites: '%s.code' value '%d' is not a redirect status code", name, rule.Code)
+	}
+
+	var err error
+	if rule.From, err = regexp.Compile(from); err != nil {
+		return nil, fmt.Errorf("rewrites: '%s.from' value is not a valid regex: %v", name, err)
+	}
+	if host := cfg.StringDefault(keyPrefix+"host", ""); len(host) > 0 {
+		if rule.Host, err = regexp.Compile(host); err != nil {
+			return nil, fmt.Errorf("rewrites: '%s.host' value is not a valid regex: %v", name, err)
+		}
+	}
+	return rule, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteRules(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Rewrite Rules]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		rewrites {
+		  rules {
+		    old_pages {
+		      from = "^/old/(.*)$"
+		      to = "/new/$1"
+		      code = 301
+		    }
+		    legacy_text {
+		      from = "^/legacy/text$"
+		      to = "/get-text.html"
+		    }
+		    other_host {
+		      from = "^/other$"
+		      to = "/get-text.html"
+		      host = "^example\\.org$"
+		    }
+		    catch_old {
+		      from = "^/old/special$"
+		      to = "/special"
+		      code = 302
+		      order = -1
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initRewrite())
+	assert.Equal(t, 4, len(ts.app.rewriteRules))
+	assert.Equal(t, "catch_old", ts.app.rewriteRules[0].Name)
+	assert.Equal(t, "rewrite(name:old_pages from:^/old/(.*)$ to:/new/$1 code:301)", ts.app.rewriteRules[2].String())
+
+	httpClient := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	// redirect with query string
+	resp, err := httpClient.Get(ts.URL + "/old/blog/post1?page=2")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/new/blog/post1?page=2", resp.Header.Get("Location"))
+
+	// rule order
+	resp, err = httpClient.Get(ts.URL + "/old/special")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/special", resp.Header.Get("Location"))
+
+	// internal rewrite
+	resp, err = httpClient.Get(ts.URL + "/legacy/text")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, "", responseBody(resp))
+
+	// host condition not matched
+	resp, err = httpClient.Get(ts.URL + "/other")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// disabled
+	ts.app.Config().SetBool("rewrites.enable", false)
+	assert.Nil(t, ts.app.initRewrite())
+	assert.Nil(t, ts.app.rewriteRules)
+}
+
+func TestRewriteRuleParseErrors(t *testing.T) {
+	testcases := []struct {
+		label, cfg, err string
+	}{
+		{"from missing", `to = "/new"`, "rewrites: 'r1.from' value is missing"},
+		{"to missing", `from = "^/old$"`, "rewrites: 'r1.to' value is missing"},
+		{"invalid code", "from = \"^/old$\"\nto = \"/new\"\ncode = 200", "rewrites: 'r1.code' value '200' is not a redirect status code"},
+		{"invalid from", "from = \"^/old($\"\nto = \"/new\"", "rewrites: 'r1.from' value is not a valid regex: error parsing regexp: missing closing ): `^/old($`"},
+		{"invalid host", "from = \"^/old$\"\nto = \"/new\"\nhost = \"[a-\"", "rewrites: 'r1.host' value is not a valid regex: error parsing regexp: missing closing ]: `[a-`"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			cfg, err := config.ParseString("rewrites {\nrules {\nr1 {\n" + tc.cfg + "\n}\n}\n}")
+			assert.Nil(t, err)
+			_, err = parseRewriteRule(cfg, "r1")
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
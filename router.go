@@ -22,7 +22,10 @@ import (
 
 // RouteMiddleware method performs the routing logic.
 func RouteMiddleware(ctx *Context, m *Middleware) {
-	if handleRoute(ctx) == flowAbort {
+	sw := ctx.startTiming(TimingRouting)
+	flow := handleRoute(ctx)
+	sw.Stop()
+	if flow == flowAbort {
 		return
 	}
 
@@ -56,7 +59,13 @@ func CORSMiddleware(ctx *Context, m *Middleware) {
 		origin = h[0]
 	}
 	if cors.IsOriginAllowed(origin) {
+		// Origin is echoed, wildcard is not allowed with credentials
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowOrigin, origin)
+	} else if cors.Strict && len(origin) > 0 {
+		ctx.moduleLog("router").Warnf("CORS: strict mode - invalid origin '%s' for %s %s",
+			origin, ctx.Req.Method, ctx.Req.Path)
+		ctx.Reply().Forbidden().Error(newError(router.ErrCORSOriginIsInvalid, http.StatusForbidden))
+		return
 	}
 
 	if len(cors.ExposeHeaders) > 0 {
@@ -73,7 +82,7 @@ func CORSMiddleware(ctx *Context, m *Middleware) {
 }
 
 func handleCORSPreflight(ctx *Context) {
-	ctx.Log().Infof("CORS: preflight request - Path[%v]", ctx.Req.Path)
+	ctx.moduleLog("router").Infof("CORS: preflight request - Path[%v]", ctx.Req.Path)
 	ctx.Reply().
 		HeaderAppend(ahttp.HeaderVary, ahttp.HeaderAccessControlRequestMethod).
 		HeaderAppend(ahttp.HeaderVary, ahttp.HeaderAccessControlRequestHeaders)
@@ -88,7 +97,7 @@ func handleCORSPreflight(ctx *Context) {
 	if cors.IsOriginAllowed(origin) {
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowOrigin, origin)
 	} else {
-		ctx.Log().Warnf("CORS: preflight request - invalid origin '%s' for %s %s",
+		ctx.moduleLog("router").Warnf("CORS: preflight request - invalid origin '%s' for %s %s",
 			origin, ctx.Req.Method, ctx.Req.Path)
 		ctx.Reply().BadRequest().Error(newError(router.ErrCORSOriginIsInvalid, http.StatusBadRequest))
 		return
@@ -102,7 +111,7 @@ func handleCORSPreflight(ctx *Context) {
 	if cors.IsMethodAllowed(method) {
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowMethods, strings.Join(cors.AllowMethods, ", "))
 	} else {
-		ctx.Log().Warnf("CORS: preflight request - method not allowed '%s' for path %s",
+		ctx.moduleLog("router").Warnf("CORS: preflight request - method not allowed '%s' for path %s",
 			method, ctx.Req.Path)
 		ctx.Reply().MethodNotAllowed().Error(newError(router.ErrCORSMethodNotAllowed, http.StatusMethodNotAllowed))
 		return
@@ -118,7 +127,7 @@ func handleCORSPreflight(ctx *Context) {
 			ctx.Reply().Header(ahttp.HeaderAccessControlAllowHeaders, strings.Join(cors.AllowHeaders, ", "))
 		}
 	} else {
-		ctx.Log().Warnf("CORS: preflight request - headers not allowed '%s' for path %s",
+		ctx.moduleLog("router").Warnf("CORS: preflight request - headers not allowed '%s' for path %s",
 			hdrs, ctx.Req.Path)
 		ctx.Reply().Forbidden().Error(newError(router.ErrCORSHeaderNotAllowed, http.StatusForbidden))
 		return
@@ -128,6 +137,11 @@ func handleCORSPreflight(ctx *Context) {
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowCredentials, "true")
 	}
 
+	// Private network access, request from public website to private network
+	if cors.AllowPrivateNetwork && ctx.Req.Header.Get(ahttp.HeaderAccessControlRequestPrivateNetwork) == "true" {
+		ctx.Reply().Header(ahttp.HeaderAccessControlAllowPrivateNetwork, "true")
+	}
+
 	if len(cors.MaxAge) > 0 {
 		ctx.Reply().Header(ahttp.HeaderAccessControlMaxAge, cors.MaxAge)
 	}
@@ -140,12 +154,32 @@ func handleCORSPreflight(ctx *Context) {
 //______________________________________________________________________________
 
 func (a *Application) initRouter() error {
-	rtr, err := router.NewWithApp(a,
+	rtr, err := router.NewWithApp(&moduleApp{Application: a, name: "router"},
 		path.Join(a.VirtualBaseDir(), "config", "routes.conf"))
 	if err != nil {
 		return fmt.Errorf("routes.conf: %s", err)
 	}
+	if err = a.addOpenAPIRoutes(rtr); err != nil {
+		return fmt.Errorf("openapi: %s", err)
+	}
+	if err = a.addForwardAuthRoutes(rtr); err != nil {
+		return fmt.Errorf("forward_auth: %s", err)
+	}
+	if err = a.addAuthServerRoutes(rtr); err != nil {
+		return fmt.Errorf("auth_server: %s", err)
+	}
+	if err = a.addWebAuthnRoutes(rtr); err != nil {
+		return fmt.Errorf("webauthn: %s", err)
+	}
+	if err = a.addQuotaAdminRoutes(rtr); err != nil {
+		return fmt.Errorf("quota: %s", err)
+	}
+	if err = a.addHealthRoutes(rtr); err != nil {
+		return fmt.Errorf("health: %s", err)
+	}
 	a.router = rtr
+	a.initProxy(rtr)
+	a.initMirror()
 	return nil
 }
 
@@ -156,8 +190,10 @@ func (a *Application) initRouter() error {
 // handleRoute method handle route processing for the incoming request.
 // It does-
 //  - finding domain
+//  - redirect root path to locale prefix
 //  - finding route
 //  - handling static route
+//  - handling static site
 //  - handling redirect trailing slash
 //  - auto options
 //  - route not found
@@ -170,18 +206,41 @@ func (a *Application) initRouter() error {
 func handleRoute(ctx *Context) flowResult {
 	ctx.domain = ctx.a.Router().Lookup(ctx.Req.Host)
 	if ctx.domain == nil {
-		ctx.Log().Warnf("Domain not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
+		ctx.moduleLog("router").Warnf("Domain not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
 		ctx.Reply().NotFound().Error(newError(ErrDomainNotFound, http.StatusNotFound))
 		return flowAbort
 	}
 
+	// Redirect root path to best match locale prefix
+	if lp := ctx.domain.LocalePrefix; lp != nil && lp.RedirectRoot && ctx.Req.Path == router.SlashString &&
+		(ctx.Req.Method == ahttp.MethodGet || ctx.Req.Method == ahttp.MethodHead) {
+		targetPath := router.SlashString + lp.Negotiate(ctx.Req.Unwrap())
+		if len(ctx.Req.Unwrap().URL.RawQuery) > 0 {
+			targetPath += "?" + ctx.Req.Unwrap().URL.RawQuery
+		}
+		ctx.moduleLog("router").Debugf("Redirecting to locale prefix path: %s", targetPath)
+		ctx.Reply().Header(ahttp.HeaderVary, ahttp.HeaderAcceptLanguage).Redirect(targetPath)
+		return flowAbort
+	}
+
 	route, urlParams, rts := ctx.domain.Lookup(ctx.Req.Unwrap())
+	if route == nil && ctx.domain.StaticSite != nil &&
+		(ctx.Req.Method == ahttp.MethodGet || ctx.Req.Method == ahttp.MethodHead) {
+		// Serving static site
+		if err := ctx.a.staticMgr.ServeSite(ctx); err == errFileNotFound {
+			ctx.moduleLog("router").Warnf("Static file not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
+			ctx.route = nil // reply via error handler
+			ctx.Reply().NotFound().Error(newError(ErrStaticFileNotFound, http.StatusNotFound))
+		}
+		return flowAbort
+	}
+
 	if route == nil { // route not found
 		if err := handleRtsOptionsMna(ctx, rts); err == nil {
 			return flowAbort
 		}
 
-		ctx.Log().Warnf("Route not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
+		ctx.moduleLog("router").Warnf("Route not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
 		ctx.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
 		return flowAbort
 	}
@@ -191,7 +250,7 @@ func handleRoute(ctx *Context) flowResult {
 	// Serving static file
 	if ctx.route.IsStatic {
 		if err := ctx.a.staticMgr.Serve(ctx); err == errFileNotFound {
-			ctx.Log().Warnf("Static file not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
+			ctx.moduleLog("router").Warnf("Static file not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
 			ctx.Reply().done = false
 			ctx.Reply().NotFound().Error(newError(ErrStaticFileNotFound, http.StatusNotFound))
 		}
@@ -201,12 +260,17 @@ func handleRoute(ctx *Context) flowResult {
 	// Apply route constraints
 	if len(ctx.route.Constraints) > 0 {
 		if errs := valpar.ValidateValues(ctx.Req.URLParams.ToMap(), ctx.route.Constraints); len(errs) > 0 {
-			ctx.Log().Errorf("Route constraints failed: %s", errs)
+			ctx.moduleLog("router").Errorf("Route constraints failed: %s", errs)
 			ctx.Reply().BadRequest().Error(newErrorWithData(router.ErrRouteConstraintFailed, http.StatusBadRequest, errs))
 			return flowAbort
 		}
 	}
 
+	// Shadow traffic
+	if ctx.route.Mirror != nil {
+		ctx.a.mirrorMgr.Mirror(ctx)
+	}
+
 	return flowCont
 }
 
@@ -246,7 +310,7 @@ func handleRtsOptionsMna(ctx *Context, rts bool) error {
 
 			ctx.Req.URL().Path = targetReqPath
 			reply.Redirect(ctx.Req.URL().String())
-			ctx.Log().Debugf("RedirectTrailingSlash: %d, %s ==> %s", reply.Code, reqPath, reply.path)
+			ctx.moduleLog("router").Debugf("RedirectTrailingSlash: %d, %s ==> %s", reply.Code, reqPath, reply.path)
 			return nil
 		}
 	}
@@ -255,7 +319,11 @@ func handleRtsOptionsMna(ctx *Context, rts bool) error {
 	if reqMethod == ahttp.MethodOptions {
 		if domain.AutoOptions {
 			if processAllowedMethods(reply, domain.Allowed(reqMethod, reqPath), "Auto 'OPTIONS', ") {
-				ctx.Reply().Text("")
+				if ctx.a.Type() == "api" {
+					ctx.Reply().JSON(ctx.a.errorMgr.allowedMethodsData(reply))
+				} else {
+					ctx.Reply().Text("")
+				}
 				return nil
 			}
 		}
@@ -264,7 +332,11 @@ func handleRtsOptionsMna(ctx *Context, rts bool) error {
 	// 405 Method Not Allowed
 	if domain.MethodNotAllowed {
 		if processAllowedMethods(reply, domain.Allowed(reqMethod, reqPath), "405 response, ") {
-			ctx.Reply().MethodNotAllowed().Error(newError(ErrHTTPMethodNotAllowed, http.StatusMethodNotAllowed))
+			err := newError(ErrHTTPMethodNotAllowed, http.StatusMethodNotAllowed)
+			if ctx.a.Type() == "api" {
+				err.Data = ctx.a.errorMgr.allowedMethodsData(reply)
+			}
+			ctx.Reply().MethodNotAllowed().Error(err)
 			return nil
 		}
 	}
@@ -276,7 +348,7 @@ func processAllowedMethods(reply *Reply, allowed, prefix string) bool {
 	if len(allowed) > 0 {
 		allowed += ", " + ahttp.MethodOptions
 		reply.Header(ahttp.HeaderAllow, allowed)
-		reply.ctx.Log().Debugf("%sAllowed HTTP Methods: %s", prefix, allowed)
+		reply.ctx.moduleLog("router").Debugf("%sAllowed HTTP Methods: %s", prefix, allowed)
 		return true
 	}
 	return false
@@ -0,0 +1,106 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+)
+
+// API version resolve strategies
+const (
+	APIVersionByPath      = "path"
+	APIVersionByHeader    = "header"
+	APIVersionByMediaType = "media_type"
+)
+
+const defaultAPIVersionHeader = "X-API-Version"
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// APIVersion
+//______________________________________________________________________________
+
+// APIVersion struct holds the domain level API version configuration values.
+// Route version is declared on namespace route via `version` attribute, its
+// path is the version prefix for child routes.
+//
+// 	api_version {
+// 	  # path, header or media_type
+// 	  strategy = "header"
+// 	  header = "X-API-Version"
+// 	  default = "1"
+// 	}
+//
+// Strategy `path` resolves version from request path, i.e. `/v1/users`.
+// Strategy `header` resolves version from request header value and
+// `media_type` resolves version from Accept header, i.e.
+// `application/json; version=2` or `application/vnd.mycompany.myapp-v2+json`.
+// Header and media type strategies lookup routes within version path prefix
+// first then falls back to request path.
+type APIVersion struct {
+	Strategy string
+	Header   string
+	Default  string
+
+	prefixes map[string]string
+}
+
+// Resolve method returns the API version for given request based on configured
+// strategy, if it's not present then it returns default version.
+func (av *APIVersion) Resolve(req *http.Request) string {
+	var version string
+	switch av.Strategy {
+	case APIVersionByHeader:
+		version = strings.TrimSpace(req.Header.Get(av.Header))
+	case APIVersionByMediaType:
+		version = ahttp.NegotiateContentType(req).Version()
+	case APIVersionByPath:
+		version = av.versionByPath(req.URL.Path)
+	}
+	if len(version) == 0 {
+		return av.Default
+	}
+	return version
+}
+
+// Prefix method returns the route path prefix for given API version otherwise
+// empty string.
+func (av *APIVersion) Prefix(version string) string {
+	return av.prefixes[version]
+}
+
+func (av *APIVersion) versionByPath(p string) string {
+	for version, prefix := range av.prefixes {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return version
+		}
+	}
+	return ""
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseAPIVersionSection(domainCfg *config.Config) (*APIVersion, error) {
+	av := &APIVersion{
+		Strategy: strings.ToLower(domainCfg.StringDefault("api_version.strategy", APIVersionByPath)),
+		Header:   domainCfg.StringDefault("api_version.header", defaultAPIVersionHeader),
+		Default:  domainCfg.StringDefault("api_version.default", ""),
+		prefixes: make(map[string]string),
+	}
+
+	switch av.Strategy {
+	case APIVersionByPath, APIVersionByHeader, APIVersionByMediaType:
+	default:
+		return nil, fmt.Errorf("router: unsupported api_version strategy '%s'", av.Strategy)
+	}
+
+	return av, nil
+}
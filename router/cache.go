@@ -0,0 +1,130 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultLookupCacheSize = 1000
+
+// LookupCacheStats holds the domain route lookup cache statistics.
+type LookupCacheStats struct {
+	Hits     uint64
+	Misses   uint64
+	Size     int
+	Capacity int
+}
+
+// HitRate method returns the cache hit rate in the range of 0 to 1.
+func (s LookupCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Route lookup cache
+//___________________________________
+
+type routeCacheKey struct {
+	method string
+	path   string
+}
+
+type routeCacheEntry struct {
+	key   routeCacheKey
+	route *Route
+}
+
+// routeCache is a LRU cache of exact-match routes without path parameters,
+// it sits in front of the routing tree.
+type routeCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	entries  map[routeCacheKey]*list.Element
+	hits     uint64
+	misses   uint64
+	gen      uint64
+}
+
+func newRouteCache(capacity int) *routeCache {
+	if capacity <= 0 {
+		capacity = defaultLookupCacheSize
+	}
+	return &routeCache{
+		capacity: capacity,
+		ll:       list.New(),
+		entries:  make(map[routeCacheKey]*list.Element, capacity),
+	}
+}
+
+func (c *routeCache) get(method, path string) *Route {
+	c.mu.Lock()
+	if e, found := c.entries[routeCacheKey{method: method, path: path}]; found {
+		c.ll.MoveToFront(e)
+		c.mu.Unlock()
+		atomic.AddUint64(&c.hits, 1)
+		return e.Value.(*routeCacheEntry).route
+	}
+	c.mu.Unlock()
+	atomic.AddUint64(&c.misses, 1)
+	return nil
+}
+
+// generation method returns the cache generation, it is changed on every
+// clear.
+func (c *routeCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// add method adds the route into cache if cache is not cleared since the
+// given generation, route looked up from stale trees is not cached.
+func (c *routeCache) add(method, path string, route *Route, gen uint64) {
+	key := routeCacheKey{method: method, path: path}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if e, found := c.entries[key]; found {
+		c.ll.MoveToFront(e)
+		e.Value.(*routeCacheEntry).route = route
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&routeCacheEntry{key: key, route: route})
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*routeCacheEntry).key)
+	}
+}
+
+func (c *routeCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.entries = make(map[routeCacheKey]*list.Element, c.capacity)
+	c.gen++
+}
+
+func (c *routeCache) stats() LookupCacheStats {
+	c.mu.Lock()
+	size := c.ll.Len()
+	c.mu.Unlock()
+	return LookupCacheStats{
+		Hits:     atomic.LoadUint64(&c.hits),
+		Misses:   atomic.LoadUint64(&c.misses),
+		Size:     size,
+		Capacity: c.capacity,
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"aahframe.work/config"
+)
+
+// Canary route variant names.
+const (
+	VariantStable = "stable"
+	VariantCanary = "canary"
+)
+
+// Canary struct holds the canary configuration of the route, configured
+// weight percentage of requests is dispatched to the alternate controller
+// target. Variant assignment is sticky via cookie and it could be forced
+// via request header value `stable` or `canary`.
+//
+// 	canary {
+// 	  target = "v2/UserController"
+// 	  weight = 10
+// 	}
+type Canary struct {
+	Weight int
+	Target string
+	Action string
+	Cookie string
+	Header string
+}
+
+// Variant method returns the variant name for the given request from header,
+// sticky cookie otherwise assigns it by weight. Returns true if variant is
+// newly assigned, so caller sets the sticky cookie.
+func (c *Canary) Variant(r *http.Request) (string, bool) {
+	if len(c.Header) > 0 {
+		if v := parseVariant(r.Header.Get(c.Header)); len(v) > 0 {
+			return v, false
+		}
+	}
+	if cookie, err := r.Cookie(c.Cookie); err == nil {
+		if v := parseVariant(cookie.Value); len(v) > 0 {
+			return v, false
+		}
+	}
+	if rand.Intn(100) < c.Weight {
+		return VariantCanary, true
+	}
+	return VariantStable, true
+}
+
+// String method is Stringer interface.
+func (c *Canary) String() string {
+	return fmt.Sprintf("canary(target:%s action:%s weight:%d)", c.Target, c.Action, c.Weight)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseCanarySection(routeName, routeAction string, cfg *config.Config) (*Canary, error) {
+	target, found := cfg.String("target")
+	if !found || len(strings.TrimSpace(target)) == 0 {
+		return nil, fmt.Errorf("'%v.canary.target' key is missing", routeName)
+	}
+	weight := cfg.IntDefault("weight", 0)
+	if weight < 0 || weight > 100 {
+		return nil, fmt.Errorf("'%v.canary.weight' value '%v' is not in range 0 to 100", routeName, weight)
+	}
+	return &Canary{
+		Weight: weight,
+		Target: strings.TrimSpace(target),
+		Action: cfg.StringDefault("action", routeAction),
+		Cookie: cfg.StringDefault("cookie", "aah_canary_"+routeName),
+		Header: cfg.StringDefault("header", "X-Canary"),
+	}, nil
+}
+
+func parseVariant(v string) string {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case VariantStable:
+		return VariantStable
+	case VariantCanary:
+		return VariantCanary
+	}
+	return ""
+}
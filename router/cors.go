@@ -46,10 +46,12 @@ var (
 // Spec: https://www.w3.org/TR/cors/
 // Friendly Read: https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS
 type CORS struct {
-	AllowCredentials bool
-	allowAllOrigins  bool
-	allowAllMethods  bool
-	allowAllHeaders  bool
+	AllowCredentials    bool
+	AllowPrivateNetwork bool
+	Strict              bool
+	allowAllOrigins     bool
+	allowAllMethods     bool
+	allowAllHeaders     bool
 
 	MaxAge        string
 	maxAgeStr     string
@@ -124,6 +126,23 @@ func (c *CORS) SetAllowCredentials(b bool) *CORS {
 	return c
 }
 
+// SetAllowPrivateNetwork method sets the given boolean into allow private
+// network, it replies `Access-Control-Allow-Private-Network` on preflight
+// request from public website to private network.
+// Spec: https://wicg.github.io/private-network-access/
+func (c *CORS) SetAllowPrivateNetwork(b bool) *CORS {
+	c.AllowPrivateNetwork = b
+	return c
+}
+
+// SetStrict method sets the given boolean into strict mode. In strict mode
+// request with disallowed origin is rejected instead of omitting the CORS
+// headers.
+func (c *CORS) SetStrict(b bool) *CORS {
+	c.Strict = b
+	return c
+}
+
 // IsOriginAllowed method check given origin is allowed or not.
 func (c *CORS) IsOriginAllowed(origin string) bool {
 	if len(origin) == 0 {
@@ -180,6 +199,8 @@ func (c CORS) String() string {
 	b.WriteString(" expose-headers:")
 	b.WriteString(strings.Join(c.ExposeHeaders, ","))
 	b.WriteString(fmt.Sprintf(" allow-credentials:%v", c.AllowCredentials))
+	b.WriteString(fmt.Sprintf(" allow-private-network:%v", c.AllowPrivateNetwork))
+	b.WriteString(fmt.Sprintf(" strict:%v", c.Strict))
 	b.WriteString(fmt.Sprintf(" max-age:%s", c.maxAgeStr))
 	b.WriteByte(')')
 	return b.String()
@@ -230,6 +251,11 @@ func processBaseCORSSection(cfg *config.Config) *CORS {
 	// Access-Control-Allow-Credentials
 	cors.SetAllowCredentials(cfg.BoolDefault("allow_credentials", false))
 
+	// Access-Control-Allow-Private-Network
+	cors.SetAllowPrivateNetwork(cfg.BoolDefault("allow_private_network", false))
+
+	cors.SetStrict(cfg.BoolDefault("strict", false))
+
 	// Access-Control-Expose-Headers
 	if hdrs, found := cfg.StringList("expose_headers"); found {
 		cors.AddExposeHeaders(hdrs)
@@ -272,6 +298,11 @@ func processCORSSection(cfg *config.Config, parent *CORS) (*CORS, error) {
 	// Access-Control-Allow-Credentials
 	cors.SetAllowCredentials(cfg.BoolDefault("allow_credentials", parent.AllowCredentials))
 
+	// Access-Control-Allow-Private-Network
+	cors.SetAllowPrivateNetwork(cfg.BoolDefault("allow_private_network", parent.AllowPrivateNetwork))
+
+	cors.SetStrict(cfg.BoolDefault("strict", parent.Strict))
+
 	// Access-Control-Expose-Headers
 	if hdrs, found := cfg.StringList("expose_headers"); found {
 		cors.AddExposeHeaders(hdrs)
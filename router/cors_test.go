@@ -59,6 +59,8 @@ func TestRouterCORS2(t *testing.T) {
 	assert.True(t, domain.CORS.IsMethodAllowed("DELETE"))
 	assert.True(t, ess.IsSliceContainsString(domain.CORS.ExposeHeaders, "X-Base-Test2"))
 	assert.True(t, domain.CORS.AllowCredentials)
+	assert.True(t, domain.CORS.AllowPrivateNetwork)
+	assert.False(t, domain.CORS.Strict)
 	assert.Equal(t, "172800", domain.CORS.MaxAge)
 
 	routes := router.Lookup("localhost:8080").routes
@@ -73,6 +75,8 @@ func TestRouterCORS2(t *testing.T) {
 	assert.True(t, getUserRoute.CORS.IsMethodAllowed("DELETE"))
 	assert.False(t, getUserRoute.CORS.IsMethodAllowed("HEAD"))
 	assert.True(t, getUserRoute.CORS.AllowCredentials)
+	assert.True(t, getUserRoute.CORS.AllowPrivateNetwork)
+	assert.True(t, getUserRoute.CORS.Strict)
 	assert.Equal(t, "86400", getUserRoute.CORS.MaxAge)
 
 	deleteUserRoute := routes["delete_user"]
@@ -82,6 +86,7 @@ func TestRouterCORS2(t *testing.T) {
 	assert.True(t, deleteUserRoute.CORS.IsMethodAllowed("DELETE"))
 	assert.False(t, deleteUserRoute.CORS.IsMethodAllowed("HEAD"))
 	assert.True(t, deleteUserRoute.CORS.AllowCredentials)
+	assert.False(t, deleteUserRoute.CORS.Strict)
 	assert.Equal(t, "172800", deleteUserRoute.CORS.MaxAge)
 
 	updateUserRoute := routes["update_user"]
@@ -0,0 +1,216 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframe.work/essentials"
+	"aahframe.work/vfs"
+)
+
+// ConfigErrors type holds all the errors found while processing the routes
+// configuration.
+type ConfigErrors []error
+
+// Error method is error interface implementation.
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = "\t" + err.Error()
+	}
+	return fmt.Sprintf("router: routes configuration has %d errors:\n%s", len(e), strings.Join(msgs, "\n"))
+}
+
+// RouteConflictError type is returned when route cannot be added into the
+// routing tree due to existing route.
+type RouteConflictError struct {
+	Route    *Route
+	Existing *Route
+	Err      error
+
+	location         string
+	existingLocation string
+}
+
+// Error method is error interface implementation.
+func (e *RouteConflictError) Error() string {
+	if e.Existing == nil {
+		return fmt.Sprintf("router: route '%s' [%s %s]%s: %v",
+			e.Route.Name, e.Route.Method, e.Route.Path, e.location, e.Err)
+	}
+	return fmt.Sprintf("router: route '%s' [%s %s]%s conflicts with route '%s' [%s %s]%s: %v",
+		e.Route.Name, e.Route.Method, e.Route.Path, e.location,
+		e.Existing.Name, e.Existing.Method, e.Existing.Path, e.existingLocation, e.Err)
+}
+
+// IsConflictError method returns true if the given error is
+// `RouteConflictError` or `ConfigErrors` of only route conflicts.
+func IsConflictError(err error) bool {
+	switch e := err.(type) {
+	case *RouteConflictError:
+		return true
+	case ConfigErrors:
+		for _, er := range e {
+			if _, ok := er.(*RouteConflictError); !ok {
+				return false
+			}
+		}
+		return len(e) > 0
+	}
+	return false
+}
+
+// LookupRoute method returns the route for given reverse URL route name
+// otherwise nil. It supports the subdomain prefix and anchor as used by
+// template funcs `rurl` and `rurlm`, for e.g.: `admin.dashboard#recent`.
+func (r *Router) LookupRoute(routeName string) *Route {
+	domain, name := r.lookupRouteURLDomain("", routeName)
+	if domain == nil {
+		return nil
+	}
+	if i := strings.IndexByte(name, '#'); i > 0 {
+		name = name[:i]
+	}
+	return domain.LookupByName(name)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// addRoutes method adds the given routes into domain, conflicts are collected
+// and returned with diagnostics instead of failing on first conflict.
+func (r *Router) addRoutes(domain *Domain, routes []*Route) []error {
+	var errs []error
+	for _, route := range routes {
+		if err := domain.AddRoute(route); err != nil {
+			errs = append(errs, r.conflictError(domain, route, err))
+		}
+	}
+	return errs
+}
+
+func (r *Router) conflictError(domain *Domain, route *Route, err error) error {
+	ce := &RouteConflictError{Route: route, Existing: domain.conflictRoute(route), Err: err}
+	ce.location = r.sectionLocation(route.section)
+	if ce.Existing != nil {
+		ce.existingLocation = r.sectionLocation(ce.Existing.section)
+	}
+	return ce
+}
+
+// sectionLocation method returns the config section path along with file
+// line number if found, for e.g.:
+// ` at domains.localhost.routes.show_hotel (routes.conf:74)`.
+func (r *Router) sectionLocation(section string) string {
+	if len(section) == 0 {
+		return ""
+	}
+	if line := findSectionLine(r.configSource(), section); line > 0 {
+		return fmt.Sprintf(" at %s (%s:%d)", section, path.Base(r.configPath), line)
+	}
+	return " at " + section
+}
+
+// configSource method returns the routes config file content, it is read
+// once and used for diagnostics purpose only.
+func (r *Router) configSource() []byte {
+	if r.source != nil {
+		return r.source
+	}
+	r.source = []byte{}
+	if ess.IsFileExists(r.configPath) {
+		if b, err := ioutil.ReadFile(r.configPath); err == nil {
+			r.source = b
+		}
+	} else if a, ok := r.app.(interface{ VFS() *vfs.VFS }); ok && a.VFS() != nil {
+		if b, err := a.VFS().ReadFile(r.configPath); err == nil {
+			r.source = b
+		}
+	}
+	return r.source
+}
+
+// conflictRoute method returns the existing route of same HTTP method which
+// is most likely conflicts with the given route. It compares the path
+// parameter normalized paths and picks the longest common prefix.
+func (d *Domain) conflictRoute(route *Route) *Route {
+	np := normalizeParams(route.Path)
+	var found *Route
+	var foundLen int
+	names := make([]string, 0, len(d.routes))
+	for name := range d.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		er := d.routes[name]
+		if er == route || er.Method != route.Method {
+			continue
+		}
+		if er.Path == route.Path {
+			return er
+		}
+		if l := commonPrefixLen(np, normalizeParams(er.Path)); l > foundLen {
+			found, foundLen = er, l
+		}
+	}
+	return found
+}
+
+var paramSegment = regexp.MustCompile(`([:*])[^/]+`)
+
+func normalizeParams(p string) string {
+	return paramSegment.ReplaceAllString(strings.ToLower(p), "$1")
+}
+
+func commonPrefixLen(a, b string) int {
+	i, max := 0, len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// findSectionLine method finds the line number of the given dotted section
+// path in the config source, each section key is searched after the line of
+// its parent section. It returns 0 if not found.
+func findSectionLine(src []byte, section string) int {
+	if len(src) == 0 {
+		return 0
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	line := 0
+	for _, key := range strings.Split(section, ".") {
+		re := regexp.MustCompile(`^\s*"?` + regexp.QuoteMeta(key) + `"?\s*[:=]?\s*\{`)
+		found := false
+		for i := line; i < len(lines); i++ {
+			if re.MatchString(lines[i]) {
+				line, found = i+1, true
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+	return line
+}
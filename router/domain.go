@@ -10,7 +10,10 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
@@ -28,6 +31,9 @@ type Domain struct {
 	MethodNotAllowed      bool
 	RedirectTrailingSlash bool
 	AutoOptions           bool
+	AutoHead              bool
+	Compress              bool
+	Minify                bool
 	AntiCSRFEnabled       bool
 	CORSEnabled           bool
 	Key                   string
@@ -37,8 +43,19 @@ type Domain struct {
 	DefaultAuth           string
 	CORS                  *CORS
 	CatchAllRoute         *Route
+	StaticSite            *StaticSite
+	APIVersion            *APIVersion
+	LocalePrefix          *LocalePrefix
+	Headers               *HeaderPolicy
+	TagHeaders            map[string]*HeaderPolicy
 	trees                 map[string]*tree
 	routes                map[string]*Route
+	versionRoutes         map[string]map[string]*Route
+	localeRoutes          map[string]map[string]*Route
+	cache                 *routeCache
+	disabled              map[string]bool
+	ltrees                atomic.Value // map[string]*tree without disabled routes
+	mu                    sync.Mutex
 }
 
 // Lookup method looks up route if found it returns route, path parameters,
@@ -52,21 +69,61 @@ func (d *Domain) Lookup(req *http.Request) (*Route, ahttp.URLParams, bool) {
 		}
 	}
 
-	// get route tree for request method
-	tree, found := d.trees[req.Method]
+	// get route tree for request method, cache generation is read before
+	// the trees
+	var gen uint64
+	if d.cache != nil {
+		gen = d.cache.generation()
+	}
+	method := req.Method
+	trees := d.lookupTrees()
+	tree, found := trees[method]
 	if !found {
 		// get route tree for CORS access control method
 		if req.Method == ahttp.MethodOptions && d.CORSEnabled {
 			if h := req.Header[ahttp.HeaderAccessControlRequestMethod]; len(h) > 0 {
-				tree, found = d.trees[h[0]]
+				method = h[0]
+				tree, found = trees[method]
 			}
 		}
+		// get route tree of GET for HEAD request, if auto head enabled
+		if req.Method == ahttp.MethodHead && d.AutoHead {
+			method = ahttp.MethodGet
+			tree, found = trees[method]
+		}
 		if !found {
 			return nil, nil, false
 		}
 	}
 
-	route, urlParams, rts := tree.lookup(req.URL.EscapedPath())
+	reqPath := req.URL.EscapedPath()
+	if d.isCacheable() {
+		if route := d.cache.get(method, reqPath); route != nil {
+			return route, nil, false
+		}
+	}
+
+	// API version resolved from header or media type, lookup within
+	// version path prefix first
+	if d.APIVersion != nil && d.APIVersion.Strategy != APIVersionByPath {
+		if prefix := d.APIVersion.Prefix(d.APIVersion.Resolve(req)); len(prefix) > 0 &&
+			!strings.HasPrefix(reqPath, prefix+"/") {
+			if route, urlParams, _ := tree.lookup(path.Join(prefix, reqPath)); route != nil {
+				return route, urlParams, false
+			}
+		}
+	}
+
+	route, urlParams, rts := tree.lookup(reqPath)
+	if route == nil && method == ahttp.MethodHead && d.AutoHead {
+		if gtree, ok := trees[ahttp.MethodGet]; ok {
+			method = ahttp.MethodGet
+			route, urlParams, rts = gtree.lookup(reqPath)
+		}
+	}
+	if route != nil && len(urlParams) == 0 && d.isCacheable() {
+		d.cache.add(method, reqPath, route, gen)
+	}
 
 	// Catch All
 	if route == nil && !rts && d.CatchAllRoute != nil {
@@ -76,6 +133,69 @@ func (d *Domain) Lookup(req *http.Request) (*Route, ahttp.URLParams, bool) {
 	return route, urlParams, rts
 }
 
+// LookupFast method looks up route for given HTTP method and escaped request
+// path, path parameters are populated into given params. Params slice is
+// reset and its backing array reused, so it does not allocate per lookup when
+// caller reuses params, for e.g. via `ahttp.AcquireURLParams`. It returns
+// nil if route not found.
+//
+// Note: It does not perform HTTP method override, API version and redirect
+// trailing slash resolution, use `Domain.Lookup` for those.
+//
+// 	params := ahttp.AcquireURLParams()
+// 	defer ahttp.ReleaseURLParams(params)
+// 	route := domain.LookupFast(r.Method, r.URL.EscapedPath(), params)
+func (d *Domain) LookupFast(method, path string, params *ahttp.URLParams) *Route {
+	var gen uint64
+	if d.cache != nil {
+		gen = d.cache.generation()
+	}
+	tree, found := d.lookupTrees()[method]
+	if !found {
+		return nil
+	}
+	if d.cache != nil {
+		if route := d.cache.get(method, path); route != nil {
+			*params = (*params)[:0]
+			return route
+		}
+	}
+	if route, _ := tree.find(path, params); route != nil {
+		if d.cache != nil && len(*params) == 0 {
+			d.cache.add(method, path, route, gen)
+		}
+		return route
+	}
+	*params = (*params)[:0]
+	return d.CatchAllRoute
+}
+
+// EnableLookupCache method enables the route lookup cache for the domain with
+// given capacity, zero or negative value means default capacity 1000. Cache
+// holds the exact-match routes without path parameters in LRU order, it gives
+// O(1) lookups for static-heavy route tables. It can be enabled via routes
+// config too.
+//
+// 	lookup_cache {
+// 	  enable = true
+// 	  size = 1000
+// 	}
+//
+// Cache is not used for API version resolved from header or media type.
+func (d *Domain) EnableLookupCache(size int) {
+	d.cache = newRouteCache(size)
+}
+
+// LookupCacheStats method returns the route lookup cache statistics, it
+// could be used for metrics purpose. It returns zero value if cache is not
+// enabled.
+func (d *Domain) LookupCacheStats() LookupCacheStats {
+	if d.cache == nil {
+		return LookupCacheStats{}
+	}
+	return d.cache.stats()
+}
+
 // LookupByName method returns the route for given route name otherwise nil.
 func (d *Domain) LookupByName(name string) *Route {
 	if route, found := d.routes[name]; found {
@@ -84,6 +204,45 @@ func (d *Domain) LookupByName(name string) *Route {
 	return nil
 }
 
+// Routes method returns all the routes of domain sorted by path and HTTP method.
+func (d *Domain) Routes() []*Route {
+	routes := make([]*Route, 0, len(d.routes))
+	for _, r := range d.routes {
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+	return routes
+}
+
+// LookupByNameVersion method returns the route for given route name and API
+// version. If route not found for version then it returns route by name
+// otherwise nil.
+func (d *Domain) LookupByNameVersion(name, version string) *Route {
+	if len(version) > 0 {
+		if route, found := d.versionRoutes[version][name]; found {
+			return route
+		}
+	}
+	return d.LookupByName(name)
+}
+
+// LookupByNameLocale method returns the locale prefixed route for given
+// route name and locale. If route not found for locale then it returns
+// route by name and API version otherwise nil.
+func (d *Domain) LookupByNameLocale(name, locale, version string) *Route {
+	if len(locale) > 0 {
+		if route, found := d.localeRoutes[locale][name]; found {
+			return route
+		}
+	}
+	return d.LookupByNameVersion(name, version)
+}
+
 // AddRoute method adds the given route into domain routing tree.
 func (d *Domain) AddRoute(route *Route) error {
 	if ess.IsStrEmpty(route.Method) {
@@ -99,15 +258,41 @@ func (d *Domain) AddRoute(route *Route) error {
 	if err := t.add(route.Path, route); err != nil {
 		return err
 	}
+	if strings.ContainsAny(route.Path, ":*") {
+		t.root.inferwnode()
+	}
 
 	d.routes[route.Name] = route
+	if d.cache != nil {
+		d.cache.clear()
+	}
+	if d.ltrees.Load() != nil {
+		d.mu.Lock()
+		d.swapTrees()
+		d.mu.Unlock()
+	}
+	if len(route.Version) > 0 {
+		if d.versionRoutes == nil {
+			d.versionRoutes = make(map[string]map[string]*Route)
+		}
+		if _, found := d.versionRoutes[route.Version]; !found {
+			d.versionRoutes[route.Version] = make(map[string]*Route)
+		}
+		d.versionRoutes[route.Version][route.Name] = route
+		if d.APIVersion != nil && len(route.versionPrefix) > 0 {
+			if _, found := d.APIVersion.prefixes[route.Version]; !found {
+				d.APIVersion.prefixes[route.Version] = route.versionPrefix
+			}
+		}
+	}
 	return nil
 }
 
 // Allowed method returns the value for header `Allow` otherwise empty string.
 func (d *Domain) Allowed(requestMethod, path string) (allowed string) {
+	trees := d.lookupTrees()
 	if path == "*" { // server-wide
-		for method := range d.trees {
+		for method := range trees {
 			if method != ahttp.MethodOptions {
 				// add request method to list of allowed methods
 				allowed = suffixCommaValue(allowed, method)
@@ -117,19 +302,58 @@ func (d *Domain) Allowed(requestMethod, path string) (allowed string) {
 	}
 
 	// specific path
-	for method := range d.trees {
+	for method, t := range trees {
 		// Skip the requested method - we already tried this one
 		if method != requestMethod && method != ahttp.MethodOptions {
-			if value, _, _ := d.trees[method].lookup(path); value != nil {
+			if value, _, _ := t.lookup(path); value != nil {
 				// add request method to list of allowed methods
 				allowed = suffixCommaValue(allowed, method)
 			}
 		}
 	}
 
+	// HEAD is served by GET route, if auto head enabled
+	if d.AutoHead && requestMethod != ahttp.MethodHead {
+		methods := strings.Split(allowed, ", ")
+		if ess.IsSliceContainsString(methods, ahttp.MethodGet) &&
+			!ess.IsSliceContainsString(methods, ahttp.MethodHead) {
+			allowed = suffixCommaValue(allowed, ahttp.MethodHead)
+		}
+	}
+
 	return
 }
 
+// DisableRoute method disables the route for given route name at runtime,
+// request to it gets `404 Not Found` until it is enabled again. Affected
+// method trees are rebuilt and swapped atomically, so it is safe to call while
+// serving the requests. Reverse URL of disabled route still works. Route can
+// be disabled via routes config too.
+//
+// 	dashboard_v2 {
+// 	  path = "/dashboard/v2"
+// 	  controller = "DashboardController"
+// 	  action = "V2"
+// 	  enabled = false
+// 	}
+func (d *Domain) DisableRoute(name string) error {
+	return d.toggleRoute(name, false)
+}
+
+// EnableRoute method enables the route for given route name which was
+// disabled via `Domain.DisableRoute` or routes config `enabled = false`.
+func (d *Domain) EnableRoute(name string) error {
+	return d.toggleRoute(name, true)
+}
+
+// IsRouteEnabled method returns true if route for given route name is not
+// disabled otherwise false.
+func (d *Domain) IsRouteEnabled(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !d.disabled[name]
+}
+
 // RouteURLNamedArgs composes reverse URL by route name and key-value pair arguments.
 // Additional key-value pairs composed as URL query string.
 // If error occurs then method logs it and returns empty string.
@@ -139,7 +363,26 @@ func (d *Domain) RouteURLNamedArgs(routeName string, args map[string]interface{}
 		log.Errorf("route name '%v' not found", routeName)
 		return ""
 	}
+	return composeRouteURLNamedArgs(route, args)
+}
 
+// RouteURL method composes route reverse URL for given route and
+// arguments based on index order. If error occurs then method logs it
+// and returns empty string.
+func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
+	route, found := d.routes[routeName]
+	if !found {
+		log.Errorf("route name '%v' not found", routeName)
+		return ""
+	}
+	return composeRouteURL(route, args...)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Domain unexpoted methods
+//___________________________________
+
+func composeRouteURLNamedArgs(route *Route, args map[string]interface{}) string {
 	argsLen := len(args)
 	pathParamCnt := countParams(route.Path)
 	if pathParamCnt == 0 && argsLen == 0 { // static URLs or no path params
@@ -188,16 +431,7 @@ func (d *Domain) RouteURLNamedArgs(routeName string, args map[string]interface{}
 	return reverseURL
 }
 
-// RouteURL method composes route reverse URL for given route and
-// arguments based on index order. If error occurs then method logs it
-// and returns empty string.
-func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
-	route, found := d.routes[routeName]
-	if !found {
-		log.Errorf("route name '%v' not found", routeName)
-		return ""
-	}
-
+func composeRouteURL(route *Route, args ...interface{}) string {
 	argsLen := len(args)
 	pathParamCnt := countParams(route.Path)
 	if pathParamCnt == 0 && argsLen == 0 { // static URLs or no path params
@@ -207,14 +441,14 @@ func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
 	// too many arguments
 	if argsLen > int(pathParamCnt) {
 		log.Errorf("too many arguments routename: %s, path: '%v' params count: %v, suppiled values count: %v",
-			routeName, route.Path, pathParamCnt, argsLen)
+			route.Name, route.Path, pathParamCnt, argsLen)
 		return ""
 	}
 
 	// not enough arguments
 	if argsLen < int(pathParamCnt) {
 		log.Errorf("not enough arguments routename: %s, path: '%v' params count: %v, suppiled values count: %v",
-			routeName, route.Path, pathParamCnt, argsLen)
+			route.Name, route.Path, pathParamCnt, argsLen)
 		return ""
 	}
 
@@ -243,9 +477,85 @@ func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
 	return reverseURL
 }
 
-//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
-// Domain unexpoted methods
-//___________________________________
+// lookupTrees method returns the method trees used for lookup, it excludes
+// the disabled routes.
+func (d *Domain) lookupTrees() map[string]*tree {
+	if trees, ok := d.ltrees.Load().(map[string]*tree); ok {
+		return trees
+	}
+	return d.trees
+}
+
+func (d *Domain) toggleRoute(name string, enable bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var methods []string
+	for method, t := range d.trees {
+		for _, r := range t.routes() {
+			if r.Name == name {
+				methods = append(methods, method)
+				break
+			}
+		}
+	}
+	if len(methods) == 0 {
+		return fmt.Errorf("router: route '%s' not exists in domain '%s'", name, d.Key)
+	}
+	if enable {
+		delete(d.disabled, name)
+	} else {
+		if d.disabled == nil {
+			d.disabled = make(map[string]bool)
+		}
+		d.disabled[name] = true
+	}
+	d.swapTrees()
+	return nil
+}
+
+// swapTrees method rebuilds the lookup trees without disabled routes and
+// swaps it atomically. Method tree which has no disabled routes is used
+// as-is and method which has all the routes disabled is excluded.
+func (d *Domain) swapTrees() {
+	trees := make(map[string]*tree, len(d.trees))
+	for method, t := range d.trees {
+		routes := t.routes()
+		hasDisabled := false
+		for _, r := range routes {
+			if d.disabled[r.Name] {
+				hasDisabled = true
+				break
+			}
+		}
+		if !hasDisabled {
+			trees[method] = t
+			continue
+		}
+
+		nt := &tree{root: new(node), tralingSlash: t.tralingSlash}
+		for _, r := range routes {
+			if !d.disabled[r.Name] {
+				_ = nt.add(r.Path, r)
+			}
+		}
+		if nt.root.value == nil && len(nt.root.edges) == 0 {
+			continue // all the routes of method are disabled
+		}
+		nt.root.inferwnode()
+		trees[method] = nt
+	}
+	d.ltrees.Store(trees)
+
+	// cache is cleared after the swap, so lookup in-progress with previous
+	// trees does not add to cache, see `routeCache.add`
+	if d.cache != nil {
+		d.cache.clear()
+	}
+}
+
+func (d *Domain) isCacheable() bool {
+	return d.cache != nil && (d.APIVersion == nil || d.APIVersion.Strategy == APIVersionByPath)
+}
 
 func (d *Domain) inferKey() {
 	if len(d.Port) == 0 {
@@ -268,10 +578,31 @@ func (d *Domain) isAuthConfigured(secMgr *security.Manager) ([]string, bool) {
 
 		if r.Auth == "" {
 			names = append(names, r.Name)
-		} else if secMgr.AuthScheme(r.Auth) == nil {
+		} else if !isAuthSchemesExists(secMgr, r.Auth) {
 			names = append(names, r.Name)
 		}
 	}
 
 	return names, len(names) == 0
 }
+
+// isAuthSchemesExists method returns true if all the auth schemes of route
+// auth value exists. Value is either comma separated `a,b` or fallthrough
+// chain `a?b`, mixing of both is not allowed. Ceremony based schemes `form`,
+// `oauth2` and `webauthn` cannot be part of fallthrough chain.
+func isAuthSchemesExists(secMgr *security.Manager, auth string) bool {
+	chain := strings.Contains(auth, "?")
+	if chain && strings.Contains(auth, ",") {
+		return false
+	}
+	for _, n := range strings.FieldsFunc(auth, func(r rune) bool { return r == ',' || r == '?' }) {
+		s := secMgr.AuthScheme(strings.TrimSpace(n))
+		if s == nil {
+			return false
+		}
+		if chain && (s.Scheme() == "form" || s.Scheme() == "oauth2" || s.Scheme() == "webauthn") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// HeaderPolicy struct holds the response headers to set and remove, it is
+// applied at reply time. Policies are merged hierarchically from domain to
+// namespace to route, child policy takes precedence.
+//
+// 	headers {
+// 	  set = ["X-Robots-Tag: noindex", "Cache-Control: no-store"]
+// 	  remove = ["Server"]
+// 	}
+type HeaderPolicy struct {
+	Set    http.Header
+	Remove []string
+}
+
+// Apply method applies the header policy on given header.
+func (hp *HeaderPolicy) Apply(hdr http.Header) {
+	for _, k := range hp.Remove {
+		hdr.Del(k)
+	}
+	for k, v := range hp.Set {
+		hdr[k] = v
+	}
+}
+
+// String method is Stringer interface.
+func (hp *HeaderPolicy) String() string {
+	keys := make([]string, 0, len(hp.Set))
+	for k := range hp.Set {
+		keys = append(keys, k)
+	}
+	return fmt.Sprintf("headers(set:%s remove:%s)", strings.Join(keys, ","), strings.Join(hp.Remove, ","))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// merge method returns the new header policy of parent merged with given
+// child policy, child values take precedence.
+func (hp *HeaderPolicy) merge(child *HeaderPolicy) *HeaderPolicy {
+	if hp == nil {
+		return child
+	}
+	if child == nil {
+		return hp
+	}
+	m := &HeaderPolicy{Set: http.Header{}}
+	for k, v := range hp.Set {
+		m.Set[k] = v
+	}
+	for _, k := range hp.Remove {
+		if _, found := child.Set[http.CanonicalHeaderKey(k)]; !found {
+			m.Remove = append(m.Remove, k)
+		}
+	}
+	for _, k := range child.Remove {
+		m.Set.Del(k)
+		if !ess.IsSliceContainsString(m.Remove, k) {
+			m.Remove = append(m.Remove, k)
+		}
+	}
+	for k, v := range child.Set {
+		m.Set[k] = v
+	}
+	return m
+}
+
+func parseHeaderPolicy(name string, cfg *config.Config) (*HeaderPolicy, error) {
+	set, err := parseHeaderValues(name+".headers.set", cfg, "set")
+	if err != nil {
+		return nil, err
+	}
+	hp := &HeaderPolicy{Set: set}
+	hp.Remove, _ = cfg.StringList("remove")
+	return hp, nil
+}
+
+// parseTagHeaderPolicies method parses the domain level response header
+// policies by route tag, it's applied on tagged routes between the domain
+// and route policy.
+//
+// 	tag_headers {
+// 	  public {
+// 	    set = ["Cache-Control: public, max-age=300"]
+// 	  }
+// 	}
+func parseTagHeaderPolicies(cfg *config.Config) (map[string]*HeaderPolicy, error) {
+	policies := make(map[string]*HeaderPolicy)
+	for _, tag := range cfg.Keys() {
+		tagCfg, _ := cfg.GetSubConfig(tag)
+		set, err := parseHeaderValues("tag_headers."+tag+".set", tagCfg, "set")
+		if err != nil {
+			return nil, err
+		}
+		hp := &HeaderPolicy{Set: set}
+		hp.Remove, _ = tagCfg.StringList("remove")
+		policies[tag] = hp
+	}
+	return policies, nil
+}
+
+// parseHeaderValues method parses the header values in the format
+// `Name: value` for given key.
+func parseHeaderValues(keyPath string, cfg *config.Config, key string) (http.Header, error) {
+	hdr := http.Header{}
+	values, _ := cfg.StringList(key)
+	for _, v := range values {
+		idx := strings.IndexByte(v, ':')
+		if idx <= 0 {
+			return nil, fmt.Errorf("'%v' value '%v' must be in the format 'Name: value'", keyPath, v)
+		}
+		hdr.Add(strings.TrimSpace(v[:idx]), strings.TrimSpace(v[idx+1:]))
+	}
+	return hdr, nil
+}
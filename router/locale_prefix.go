@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+)
+
+// LocalePrefix struct holds the domain level locale route prefix configuration
+// values. Application routes are added for each locale with path prefix, i.e.
+// route path `/about.html` is served at `/en/about.html`, `/de/about.html`,
+// etc. and `Route.Locale` holds the locale of the route.
+//
+// 	locale_prefix {
+// 	  locales = ["en", "de"]
+// 	  default = "en"
+// 	  redirect_root = true
+// 	}
+type LocalePrefix struct {
+	RedirectRoot bool
+	Default      string
+	Locales      []string
+}
+
+// Negotiate method returns the best match locale of `Accept-Language` header
+// value from configured locales otherwise default locale.
+func (lp *LocalePrefix) Negotiate(req *http.Request) string {
+	for _, spec := range ahttp.ParseAccept(req, ahttp.HeaderAcceptLanguage) {
+		value := strings.ToLower(spec.Value)
+		for _, l := range lp.Locales {
+			if strings.ToLower(l) == value {
+				return l
+			}
+		}
+		language := ahttp.ToLocale(&spec).Language
+		for _, l := range lp.Locales {
+			if strings.EqualFold(ahttp.NewLocale(l).Language, language) {
+				return l
+			}
+		}
+	}
+	return lp.Default
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseLocalePrefixSection(domainCfg *config.Config) (*LocalePrefix, error) {
+	lp := &LocalePrefix{
+		RedirectRoot: domainCfg.BoolDefault("locale_prefix.redirect_root", true),
+	}
+	lp.Locales, _ = domainCfg.StringList("locale_prefix.locales")
+	if len(lp.Locales) == 0 {
+		return nil, fmt.Errorf("router: 'locale_prefix.locales' value is missing")
+	}
+	lp.Default = domainCfg.StringDefault("locale_prefix.default", lp.Locales[0])
+	return lp, nil
+}
+
+// addLocaleRoutes method adds application routes of the domain for each
+// configured locale with locale path prefix. Static, reverse proxy and
+// framework routes are not localized.
+func (r *Router) addLocaleRoutes(d *Domain) error {
+	d.localeRoutes = make(map[string]map[string]*Route)
+	for _, route := range d.routes {
+		if route.IsStatic || route.IsProxy() || route.IsFramework() {
+			continue
+		}
+		for _, locale := range d.LocalePrefix.Locales {
+			lr := *route
+			lr.Locale = locale
+			lr.Path = "/" + locale
+			if route.Path != SlashString {
+				lr.Path += route.Path
+			}
+
+			t := d.trees[lr.Method]
+			if err := t.add(lr.Path, &lr); err != nil {
+				return r.conflictError(d, &lr, err)
+			}
+			if _, found := d.localeRoutes[locale]; !found {
+				d.localeRoutes[locale] = make(map[string]*Route)
+			}
+			d.localeRoutes[locale][route.Name] = &lr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// Mirror struct holds the shadow traffic configuration of the route, sampled
+// copy of the request is replayed asynchronously to the target URL and its
+// response is discarded. Request body is buffered up to `max_body_size`,
+// request with larger body is not mirrored.
+//
+// 	mirror {
+// 	  target_url = "http://new-backend:9000"
+// 	  sample = 0.05
+// 	}
+type Mirror struct {
+	Sample      float64
+	MaxBodySize int64
+	Timeout     time.Duration
+	Target      *url.URL
+}
+
+// String method is Stringer interface.
+func (m *Mirror) String() string {
+	return fmt.Sprintf("mirror(target:%s sample:%v)", m.Target, m.Sample)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseMirrorSection(routeName string, cfg *config.Config) (*Mirror, error) {
+	target, found := cfg.String("target_url")
+	if !found {
+		return nil, fmt.Errorf("'%v.mirror.target_url' key is missing", routeName)
+	}
+	targetURL, err := url.Parse(strings.TrimSpace(target))
+	if err != nil || len(targetURL.Host) == 0 ||
+		(targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		return nil, fmt.Errorf("'%v.mirror.target_url' value '%v' is not a valid http(s) URL", routeName, target)
+	}
+
+	sample := 1.0
+	if v, found := cfg.Get("sample"); found {
+		switch sv := v.(type) {
+		case float64:
+			sample = sv
+		case int64:
+			sample = float64(sv)
+		default:
+			sample = -1
+		}
+	}
+	if sample < 0 || sample > 1 {
+		return nil, fmt.Errorf("'%v.mirror.sample' value '%v' is not in range 0 to 1", routeName, sample)
+	}
+	maxBodySize, err := ess.StrToBytes(cfg.StringDefault("max_body_size", "64kb"))
+	if err != nil {
+		return nil, fmt.Errorf("'%v.mirror.max_body_size' value is not a valid size unit", routeName)
+	}
+	timeout, err := time.ParseDuration(cfg.StringDefault("timeout", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("'%v.mirror.timeout' value is not a valid time unit", routeName)
+	}
+
+	return &Mirror{
+		Sample:      sample,
+		MaxBodySize: maxBodySize,
+		Timeout:     timeout,
+		Target:      targetURL,
+	}, nil
+}
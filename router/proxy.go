@@ -0,0 +1,117 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+)
+
+// ProxyPathParam is the wildcard path parameter name of reverse proxy route,
+// it holds the request path after the route path prefix.
+const ProxyPathParam = "proxypath"
+
+// proxyMethods are the HTTP methods of reverse proxy route if route
+// `method` is not configured.
+const proxyMethods = "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS"
+
+// Proxy struct holds the reverse proxy configuration of the route, request is
+// forwarded to the target instead of controller action. Auth scheme of the
+// route is honored before forwarding the request.
+//
+// 	proxy {
+// 	  target = "http://backend:9000"
+// 	  strip_prefix = true
+// 	  websocket = true
+// 	}
+type Proxy struct {
+	StripPrefix           bool
+	WebSocket             bool
+	PreserveHost          bool
+	Target                *url.URL
+	DialTimeout           time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	RequestHeaders        *ProxyHeaders
+	ResponseHeaders       *ProxyHeaders
+}
+
+// ProxyHeaders struct holds the headers to set and remove while
+// forwarding the request or writing the response.
+type ProxyHeaders struct {
+	Set    http.Header
+	Remove []string
+}
+
+// Apply method applies the header rewrites on given header.
+func (ph *ProxyHeaders) Apply(hdr http.Header) {
+	for _, k := range ph.Remove {
+		hdr.Del(k)
+	}
+	for k, v := range ph.Set {
+		hdr[k] = v
+	}
+}
+
+// String method is Stringer interface.
+func (p *Proxy) String() string {
+	return fmt.Sprintf("proxy(target:%s strip_prefix:%v websocket:%v)", p.Target, p.StripPrefix, p.WebSocket)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseProxySection(routeName string, cfg *config.Config) (*Proxy, error) {
+	target, found := cfg.String("target")
+	if !found {
+		return nil, fmt.Errorf("'%v.proxy.target' key is missing", routeName)
+	}
+	targetURL, err := url.Parse(strings.TrimSpace(target))
+	if err != nil || len(targetURL.Host) == 0 ||
+		(targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		return nil, fmt.Errorf("'%v.proxy.target' value '%v' is not a valid http(s) URL", routeName, target)
+	}
+
+	proxy := &Proxy{
+		StripPrefix:  cfg.BoolDefault("strip_prefix", false),
+		WebSocket:    cfg.BoolDefault("websocket", false),
+		PreserveHost: cfg.BoolDefault("preserve_host", false),
+		Target:       targetURL,
+	}
+
+	for key, d := range map[string]*time.Duration{
+		"timeout.dial":            &proxy.DialTimeout,
+		"timeout.response_header": &proxy.ResponseHeaderTimeout,
+		"timeout.idle":            &proxy.IdleConnTimeout,
+	} {
+		if *d, err = time.ParseDuration(cfg.StringDefault(key, "0s")); err != nil {
+			return nil, fmt.Errorf("'%v.proxy.%v' value is not a valid time unit", routeName, key)
+		}
+	}
+
+	if proxy.RequestHeaders, err = parseProxyHeaders(routeName, cfg, "headers.request"); err != nil {
+		return nil, err
+	}
+	if proxy.ResponseHeaders, err = parseProxyHeaders(routeName, cfg, "headers.response"); err != nil {
+		return nil, err
+	}
+	return proxy, nil
+}
+
+func parseProxyHeaders(routeName string, cfg *config.Config, key string) (*ProxyHeaders, error) {
+	set, err := parseHeaderValues(routeName+".proxy."+key+".set", cfg, key+".set")
+	if err != nil {
+		return nil, err
+	}
+	ph := &ProxyHeaders{Set: set}
+	ph.Remove, _ = cfg.StringList(key + ".remove")
+	return ph, nil
+}
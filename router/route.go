@@ -21,7 +21,12 @@ import (
 type Route struct {
 	IsAntiCSRFCheck bool
 	IsStatic        bool
+	Idempotent      bool
+	Singleflight    bool
+	RawBody         bool
 	ListDir         bool
+	DisableCompress bool
+	DisableMinify   bool
 	MaxBodySize     int64
 	Name            string
 	Path            string
@@ -32,9 +37,19 @@ type Route struct {
 	Auth            string
 	Dir             string
 	File            string
+	Version         string
+	Locale          string
 	CORS            *CORS
+	Proxy           *Proxy
+	Canary          *Canary
+	Mirror          *Mirror
+	Headers         *HeaderPolicy
+	Tags            []string
 	Constraints     map[string]string
 
+	versionPrefix     string
+	section           string
+	disabled          bool
 	authorizationInfo *authorizationInfo
 }
 
@@ -43,6 +58,43 @@ func (r *Route) IsDir() bool {
 	return len(r.Dir) > 0 && len(r.File) == 0
 }
 
+// IsFramework method returns true if route is added by aah framework,
+// for e.g.: form auth login routes.
+func (r *Route) IsFramework() bool {
+	return strings.HasSuffix(r.Name, autoRouteNameSuffix)
+}
+
+// HasTag method returns true if route is tagged with given tag otherwise
+// false. Route tags are configured via `tags` attribute in the routes.conf
+// and inherited by child routes. It returns false for nil route.
+func (r *Route) HasTag(tag string) bool {
+	if r == nil {
+		return false
+	}
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyTag method returns true if route is tagged with any of the given
+// tags otherwise false.
+func (r *Route) HasAnyTag(tags ...string) bool {
+	for _, t := range tags {
+		if r.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProxy method returns true if route is reverse proxy route otherwise false.
+func (r *Route) IsProxy() bool {
+	return r.Proxy != nil
+}
+
 // IsFile method returns true if serving single file otherwise false.
 func (r *Route) IsFile() bool {
 	return len(r.File) > 0
@@ -129,8 +181,13 @@ func (r *Route) String() string {
 		return fmt.Sprintf("staticroute(name:%s path:%s dir:%s listing:%v)", r.Name, r.Path, r.Dir, r.ListDir)
 	}
 
-	return fmt.Sprintf("route(name:%s method:%s path:%s target:%s.%s auth:%s maxbodysize:%v %s %v constraints(%v))",
-		r.Name, r.Method, r.Path, r.Target, r.Action, r.Auth, r.MaxBodySize, r.CORS, r.authorizationInfo, r.Constraints)
+	if r.IsProxy() {
+		return fmt.Sprintf("proxyroute(name:%s method:%s path:%s auth:%s maxbodysize:%v %s)",
+			r.Name, r.Method, r.Path, r.Auth, r.MaxBodySize, r.Proxy)
+	}
+
+	return fmt.Sprintf("route(name:%s method:%s path:%s target:%s.%s auth:%s version:%s maxbodysize:%v %s %v constraints(%v))",
+		r.Name, r.Method, r.Path, r.Target, r.Action, r.Auth, r.Version, r.MaxBodySize, r.CORS, r.authorizationInfo, r.Constraints)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -138,14 +195,26 @@ func (r *Route) String() string {
 //______________________________________________________________________________
 
 type parentRouteInfo struct {
+	Disabled          bool
 	AntiCSRFCheck     bool
+	Idempotent        bool
+	Singleflight      bool
+	RawBody           bool
 	CORSEnabled       bool
+	Compress          bool
+	Minify            bool
 	ParentName        string
 	PrefixPath        string
 	Target            string
 	Auth              string
 	MaxBodySizeStr    string
+	Version           string
+	VersionPrefix     string
+	Section           string
 	CORS              *CORS
+	Headers           *HeaderPolicy
+	TagHeaders        map[string]*HeaderPolicy
+	Tags              []string
 	AuthorizationInfo *authorizationInfo
 }
 
@@ -73,6 +73,10 @@ func New(configPath string, appCfg *config.Config) *Router {
 }
 
 // NewWithApp method creates router instance with aah application instance.
+//
+// If the routes configuration has only route conflict errors then router
+// instance is returned along with error, conflicted routes are not added.
+// It is useful for diagnostics purpose, for e.g.: `aah routes check`.
 func NewWithApp(app interface{}, configPath string) (*Router, error) {
 	a, ok := app.(application)
 	if !ok {
@@ -81,6 +85,9 @@ func NewWithApp(app interface{}, configPath string) (*Router, error) {
 
 	rtr := &Router{configPath: configPath, app: a}
 	if err := rtr.Load(); err != nil {
+		if IsConflictError(err) {
+			return rtr, err
+		}
 		return nil, err
 	}
 
@@ -111,6 +118,7 @@ type Router struct {
 	rootDomain *Domain
 	app        application
 	config     *config.Config
+	source     []byte
 	aCfg       *config.Config // kept for backward purpose, to be removed in subsequent release
 }
 
@@ -121,6 +129,7 @@ func (r *Router) Load() (err error) {
 	if err != nil {
 		return err
 	}
+	r.source = nil
 
 	// apply aah.conf env variables
 	if envRoutesValues, found := r.appConfig().GetSubConfig("routes"); found {
@@ -179,7 +188,7 @@ func (r *Router) RegisteredActions() map[string]map[string]uint8 {
 	methods := map[string]map[string]uint8{}
 	for _, d := range r.Domains {
 		for _, route := range d.routes {
-			if route.IsStatic || route.Method == methodWebSocket ||
+			if route.IsStatic || route.IsProxy() || route.Method == methodWebSocket ||
 				strings.HasSuffix(route.Name, autoRouteNameSuffix) {
 				continue
 			}
@@ -203,8 +212,69 @@ func (r *Router) RegisteredWSActions() map[string]map[string]uint8 {
 	return methods
 }
 
-// CreateRouteURL ...
+// WalkFunc type is called for each route visited by `Router.Walk`. Returning
+// error stops the walk.
+type WalkFunc func(domain *Domain, route *Route) error
+
+// Walk method walks through all the domains in the order of configuration
+// and its routes sorted by path and HTTP method, calling `fn` for each route.
+// Catch-all route of domain is visited last if configured.
+func (r *Router) Walk(fn WalkFunc) error {
+	for _, d := range r.Domains {
+		for _, route := range d.Routes() {
+			if err := fn(d, route); err != nil {
+				return err
+			}
+		}
+		if d.CatchAllRoute != nil {
+			if err := fn(d, d.CatchAllRoute); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DisableRoute method disables the route for given domain key and route name
+// at runtime, refer to `Domain.DisableRoute`. It could be used to kill or
+// dark-launch the feature without redeploying the application.
+func (r *Router) DisableRoute(domainKey, routeName string) error {
+	d := r.findDomain(domainKey)
+	if d == nil {
+		return fmt.Errorf("router: domain '%s' not exists", domainKey)
+	}
+	return d.DisableRoute(routeName)
+}
+
+// EnableRoute method enables the route for given domain key and route name,
+// refer to `Domain.EnableRoute`.
+func (r *Router) EnableRoute(domainKey, routeName string) error {
+	d := r.findDomain(domainKey)
+	if d == nil {
+		return fmt.Errorf("router: domain '%s' not exists", domainKey)
+	}
+	return d.EnableRoute(routeName)
+}
+
+// CreateRouteURL method composes the reverse URL for given host, route name
+// and arguments.
 func (r *Router) CreateRouteURL(host, routeName string, margs map[string]interface{}, args ...interface{}) string {
+	return r.CreateVersionRouteURL(host, "", routeName, margs, args...)
+}
+
+// CreateVersionRouteURL method composes the reverse URL for given host, API
+// version, route name and arguments. Route name is resolved within the
+// routes of given API version first, so the URL includes correct version
+// prefix, otherwise it falls back to route name lookup.
+func (r *Router) CreateVersionRouteURL(host, version, routeName string, margs map[string]interface{}, args ...interface{}) string {
+	return r.CreateLocaleRouteURL(host, "", version, routeName, margs, args...)
+}
+
+// CreateLocaleRouteURL method composes the reverse URL for given host, locale,
+// API version, route name and arguments. Route name is resolved within the
+// locale prefixed routes first, so the URL includes locale prefix, otherwise
+// it falls back to route name and API version lookup.
+func (r *Router) CreateLocaleRouteURL(host, locale, version, routeName string, margs map[string]interface{}, args ...interface{}) string {
 	var domain *Domain
 	domain, routeName = r.lookupRouteURLDomain(host, routeName)
 	if routeName == "host" {
@@ -217,10 +287,16 @@ func (r *Router) CreateRouteURL(host, routeName string, margs map[string]interfa
 		routeName = routeName[:i]
 	}
 
+	route := domain.LookupByNameLocale(routeName, locale, version)
+	if route == nil {
+		log.Errorf("route name '%v' not found", routeName)
+		return ""
+	}
+
 	if margs == nil {
-		return r.composeRouteURL(domain, host, domain.RouteURL(routeName, args...), anchor)
+		return r.composeRouteURL(domain, host, composeRouteURL(route, args...), anchor)
 	}
-	return r.composeRouteURL(domain, host, domain.RouteURLNamedArgs(routeName, margs), anchor)
+	return r.composeRouteURL(domain, host, composeRouteURLNamedArgs(route, margs), anchor)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -301,6 +377,7 @@ func (r *Router) processRoutesConfig() (err error) {
 	r.Domains = make([]*Domain, len(domains))
 	r.app.Log().Debugf("Domain count: %d", len(domains))
 
+	var errs ConfigErrors
 	for idx, key := range domains {
 		domainCfg, _ := r.config.GetSubConfig(key)
 
@@ -329,11 +406,48 @@ func (r *Router) processRoutesConfig() (err error) {
 			MethodNotAllowed:      domainCfg.BoolDefault("method_not_allowed", true),
 			RedirectTrailingSlash: domainCfg.BoolDefault("redirect_trailing_slash", true),
 			AutoOptions:           domainCfg.BoolDefault("auto_options", true),
+			AutoHead:              domainCfg.BoolDefault("auto_head", false),
+			Compress:              domainCfg.BoolDefault("compress", r.appConfig().BoolDefault("render.gzip.enable", true)),
+			Minify:                domainCfg.BoolDefault("minify", true),
 			DefaultAuth:           domainCfg.StringDefault("default_auth", ""),
 			AntiCSRFEnabled:       domainCfg.BoolDefault("anti_csrf_check", true),
 			CORSEnabled:           domainCfg.BoolDefault("cors.enable", false),
 			trees:                 make(map[string]*tree),
 			routes:                make(map[string]*Route),
+			versionRoutes:         make(map[string]map[string]*Route),
+		}
+
+		// Domain level response header policy
+		if headersCfg, found := domainCfg.GetSubConfig("headers"); found {
+			if domain.Headers, err = parseHeaderPolicy(key, headersCfg); err != nil {
+				return
+			}
+		}
+
+		// Domain level response header policy by route tag
+		if tagHeadersCfg, found := domainCfg.GetSubConfig("tag_headers"); found {
+			if domain.TagHeaders, err = parseTagHeaderPolicies(tagHeadersCfg); err != nil {
+				return
+			}
+		}
+
+		// Domain Level API version configuration
+		if domainCfg.IsExists("api_version") {
+			if domain.APIVersion, err = parseAPIVersionSection(domainCfg); err != nil {
+				return
+			}
+		}
+
+		// Domain Level locale route prefix configuration
+		if domainCfg.IsExists("locale_prefix") {
+			if domain.LocalePrefix, err = parseLocalePrefixSection(domainCfg); err != nil {
+				return
+			}
+		}
+
+		// Domain Level route lookup cache
+		if domainCfg.BoolDefault("lookup_cache.enable", false) {
+			domain.EnableLookupCache(domainCfg.IntDefault("lookup_cache.size", defaultLookupCacheSize))
 		}
 
 		// Domain Level CORS configuration
@@ -345,11 +459,12 @@ func (r *Router) processRoutesConfig() (err error) {
 		// Catch All route
 		if domainCfg.IsExists("catch_all") {
 			catchAllRoute := &Route{
-				Path:   "*",
-				Method: "*",
-				Target: domainCfg.StringDefault("catch_all.controller", ""),
-				Action: domainCfg.StringDefault("catch_all.action", ""),
-				Auth:   domainCfg.StringDefault("catch_all.auth", ""),
+				Path:    "*",
+				Method:  "*",
+				Target:  domainCfg.StringDefault("catch_all.controller", ""),
+				Action:  domainCfg.StringDefault("catch_all.action", ""),
+				Auth:    domainCfg.StringDefault("catch_all.auth", ""),
+				Headers: domain.Headers,
 			}
 			if ess.IsStrEmpty(catchAllRoute.Target) || ess.IsStrEmpty(catchAllRoute.Action) {
 				err = fmt.Errorf("catch_all.controller and catch_all.action values are required")
@@ -363,6 +478,8 @@ func (r *Router) processRoutesConfig() (err error) {
 			}
 			catchAllRoute.MaxBodySize = routeMaxBodySize
 			catchAllRoute.IsAntiCSRFCheck = domainCfg.BoolDefault("catch_all.anti_csrf_check", false)
+			catchAllRoute.DisableCompress = !domainCfg.BoolDefault("catch_all.compress", domain.Compress)
+			catchAllRoute.DisableMinify = !domainCfg.BoolDefault("catch_all.minify", domain.Minify)
 
 			if corsCfg, found := domainCfg.GetSubConfig("catch_all.cors"); found {
 				if catchAllRoute.CORS, err = processCORSSection(corsCfg, domain.CORS); err != nil {
@@ -372,18 +489,35 @@ func (r *Router) processRoutesConfig() (err error) {
 			domain.CatchAllRoute = catchAllRoute
 		}
 
+		// Static site, serves directory tree as domain root
+		if siteCfg, found := domainCfg.GetSubConfig("static_site"); found {
+			if domain.StaticSite, err = parseStaticSiteSection(domain, siteCfg); err != nil {
+				return
+			}
+		}
+
 		// Not Found route support is removed in aah v0.8 release,
 		// in-favor of Centralized Error Handler.
 		// Refer to https://docs.aahframework.org/centralized-error-handler.html
 
-		// processing static routes
-		if err = r.processStaticRoutes(domain, domainCfg); err != nil {
-			return
+		// processing static and namespace routes, collects all the errors
+		// of domain routes configuration
+		section := "domains." + key
+		if er := r.processStaticRoutes(domain, domainCfg, section); len(er) > 0 {
+			errs = append(errs, er...)
+			if _, ok := er[0].(*RouteConflictError); !ok {
+				continue // invalid static section, skip the domain
+			}
+		}
+		if er := r.processRoutes(domain, domainCfg, section); er != nil {
+			errs = append(errs, er...)
 		}
 
-		// processing namespace routes
-		if err = r.processRoutes(domain, domainCfg); err != nil {
-			return
+		// add locale prefixed routes of domain
+		if domain.LocalePrefix != nil && len(errs) == 0 {
+			if er := r.addLocaleRoutes(domain); er != nil {
+				errs = append(errs, er)
+			}
 		}
 
 		// add domain routes
@@ -411,23 +545,38 @@ func (r *Router) processRoutesConfig() (err error) {
 		for _, t := range domain.trees {
 			t.root.inferwnode()
 		}
+
+		// disable the routes configured with `enabled = false`
+		for name, dr := range domain.routes {
+			if dr.disabled {
+				_ = domain.DisableRoute(name)
+			}
+		}
 	} // End of domains
 
 	// find out root domain
 	// Note: Assuming of one domain and multiple sub-domains configured
 	// otherwise it will have first non-subdomain reference.
 	for _, d := range r.Domains {
-		if !d.IsSubDomain {
+		if d != nil && !d.IsSubDomain {
 			r.rootDomain = d
 			break
 		}
 	}
 
 	r.config.ClearProfile()
+
+	switch len(errs) {
+	case 0:
+	case 1:
+		err = errs[0]
+	default:
+		err = errs
+	}
 	return
 }
 
-func (r *Router) processStaticRoutes(domain *Domain, domainCfg *config.Config) error {
+func (r *Router) processStaticRoutes(domain *Domain, domainCfg *config.Config, section string) []error {
 	staticCfg, found := domainCfg.GetSubConfig("static")
 	if !found {
 		return nil
@@ -435,19 +584,18 @@ func (r *Router) processStaticRoutes(domain *Domain, domainCfg *config.Config) e
 
 	routes, err := parseStaticSection(staticCfg)
 	if err != nil {
-		return err
+		return []error{err}
 	}
 
-	for idx := range routes {
-		if err = domain.AddRoute(routes[idx]); err != nil {
-			return err
-		}
+	for _, route := range routes {
+		route.section = section + ".static." + route.Name
+		route.DisableCompress = !staticCfg.BoolDefault(route.Name+".compress", domain.Compress)
+		route.Headers = domain.Headers
 	}
-
-	return nil
+	return r.addRoutes(domain, routes)
 }
 
-func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
+func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config, section string) []error {
 	routesCfg, found := domainCfg.GetSubConfig("routes")
 	if !found {
 		return nil
@@ -460,16 +608,19 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 		CORS:              domain.CORS,
 		AntiCSRFCheck:     domain.AntiCSRFEnabled,
 		CORSEnabled:       domain.CORSEnabled,
+		Headers:           domain.Headers,
+		TagHeaders:        domain.TagHeaders,
+		Compress:          domain.Compress,
+		Minify:            domain.Minify,
 		AuthorizationInfo: &authorizationInfo{Satisfy: "either"},
+		Section:           section + ".routes",
 	})
 	if err != nil {
-		return err
+		return []error{err}
 	}
 
-	for idx := range routes {
-		if err = domain.AddRoute(routes[idx]); err != nil {
-			return err
-		}
+	if errs := r.addRoutes(domain, routes); len(errs) > 0 {
+		return errs
 	}
 
 	// Add form login route per security.conf for configured domains
@@ -480,7 +631,7 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 				r.app.Log().Errorf("Auth schemes are configured in 'security.conf', however "+
 					"these routes have invaild auth scheme or not configured: %s",
 					strings.Join(routeNames, ", "))
-				return fmt.Errorf("routes configuration error in domain '%s', please check the logs", domain.Name)
+				return []error{fmt.Errorf("routes configuration error in domain '%s', please check the logs", domain.Name)}
 			}
 		}
 
@@ -491,7 +642,8 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 				name := kn + "_login_submit" + autoRouteNameSuffix // for e.g.: form_auth_login_submit__aah
 				if domain.LookupByName(name) == nil {              // add only if not exists
 					_ = domain.AddRoute(&Route{Name: name, Path: sv.LoginSubmitURL,
-						Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize})
+						Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize,
+						DisableCompress: !domain.Compress, DisableMinify: !domain.Minify})
 				}
 			case *scheme.OAuth2:
 				_ = domain.AddRoute(&Route{
@@ -506,6 +658,12 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 					Method: ahttp.MethodGet,
 					Auth:   kn,
 				})
+			case *scheme.WebAuthn:
+				maxBodySize, _ := ess.StrToBytes(maxBodySizeStr)
+				_ = domain.AddRoute(&Route{Name: kn + "_login_begin" + autoRouteNameSuffix,
+					Path: sv.LoginBeginURL, Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize})
+				_ = domain.AddRoute(&Route{Name: kn + "_login_finish" + autoRouteNameSuffix,
+					Path: sv.LoginFinishURL, Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize})
 			}
 		}
 	}
@@ -524,7 +682,7 @@ func (r *Router) appConfig() *config.Config {
 // Unexported methods
 //______________________________________________________________________________
 
-var payloadSupported = regexp.MustCompile(`(POST|PUT|DELETE)`)
+var payloadSupported = regexp.MustCompile(`(POST|PUT|PATCH|DELETE)`)
 
 func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes []*Route, err error) {
 	for _, routeName := range cfg.Keys() {
@@ -549,8 +707,23 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 			return
 		}
 
+		// reverse proxy route, forwards the request to proxy target
+		var routeProxy *Proxy
+		if proxyCfg, found := cfg.GetSubConfig(routeName + ".proxy"); found {
+			if routeProxy, err = parseProxySection(routeName, proxyCfg); err != nil {
+				return
+			}
+			if !strings.Contains(actualRoutePath, "*") {
+				actualRoutePath = path.Join(actualRoutePath, "*"+ProxyPathParam)
+			}
+		}
+
 		// getting 'method', default to GET, if method not found
-		routeMethod := strings.ToUpper(cfg.StringDefault(routeName+".method", ahttp.MethodGet))
+		defaultMethod := ahttp.MethodGet
+		if routeProxy != nil {
+			defaultMethod = proxyMethods
+		}
+		routeMethod := strings.ToUpper(cfg.StringDefault(routeName+".method", defaultMethod))
 
 		// getting 'target' info for e.g.: controller, websocket
 		routeTarget := cfg.StringDefault(routeName+".controller", cfg.StringDefault(routeName+".websocket", routeInfo.Target))
@@ -561,21 +734,32 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 		routeAction := cfg.StringDefault(routeName+".action", findActionByHTTPMethod(routeMethod))
 
 		notToSkip := true
-		if cfg.IsExists(routeName + ".routes") {
+		if routeProxy != nil {
+			routeTarget, routeAction = "", ""
+		} else if cfg.IsExists(routeName + ".routes") {
 			if ess.IsStrEmpty(routeTarget) || ess.IsStrEmpty(routeAction) {
 				notToSkip = false
 			}
 		}
 
-		if notToSkip && ess.IsStrEmpty(routeTarget) {
+		if notToSkip && routeProxy == nil && ess.IsStrEmpty(routeTarget) {
 			err = fmt.Errorf("'%v.controller' or '%v.websocket' key is missing", routeName, routeName)
 			return
 		}
-		if notToSkip && ess.IsStrEmpty(routeAction) {
+		if notToSkip && routeProxy == nil && ess.IsStrEmpty(routeAction) {
 			err = fmt.Errorf("'%v.action' key is missing or it seems to be multiple HTTP methods", routeName)
 			return
 		}
 
+		// getting route API version, route which declares the version is
+		// the version path prefix for its child routes
+		routeVersion := routeInfo.Version
+		routeVersionPrefix := routeInfo.VersionPrefix
+		if v, found := cfg.String(routeName + ".version"); found {
+			routeVersion = strings.TrimSpace(v)
+			routeVersionPrefix = routePath
+		}
+
 		// getting route authentication scheme name
 		routeAuth := strings.TrimSpace(cfg.StringDefault(routeName+".auth", routeInfo.Auth))
 
@@ -589,7 +773,8 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 		}
 
 		// getting Anti-CSRF check value, GitHub go-aah/aah#115
-		routeAntiCSRFCheck := cfg.BoolDefault(routeName+".anti_csrf_check", routeInfo.AntiCSRFCheck)
+		// not applicable for proxy route by default, target service takes care
+		routeAntiCSRFCheck := cfg.BoolDefault(routeName+".anti_csrf_check", routeInfo.AntiCSRFCheck && routeProxy == nil)
 
 		// Authorization Info
 		routeAuthorizationInfo, er := parseAuthorizationInfo(cfg, routeName, routeInfo)
@@ -612,6 +797,72 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 			}
 		}
 
+		// getting route response compression and HTML minify values
+		routeCompress := cfg.BoolDefault(routeName+".compress", routeInfo.Compress)
+		routeMinify := cfg.BoolDefault(routeName+".minify", routeInfo.Minify)
+
+		// getting route idempotent value, refer to `aah.IdempotencyMiddleware`
+		routeIdempotent := cfg.BoolDefault(routeName+".idempotent", routeInfo.Idempotent)
+
+		// getting route singleflight value, concurrent identical GET requests
+		// shares the single action execution
+		routeSingleflight := cfg.BoolDefault(routeName+".singleflight", routeInfo.Singleflight)
+
+		// getting route raw body value, request body is preserved for
+		// signature verification, audit logging, etc. refer to
+		// `ahttp.Request.RawBody`
+		routeRawBody := cfg.BoolDefault(routeName+".raw_body", routeInfo.RawBody)
+
+		// canary target of the route, not applicable for reverse proxy and
+		// WebSocket route
+		var routeCanary *Canary
+		if canaryCfg, found := cfg.GetSubConfig(routeName + ".canary"); found &&
+			routeProxy == nil && routeMethod != methodWebSocket {
+			if routeCanary, err = parseCanarySection(routeName, routeAction, canaryCfg); err != nil {
+				return
+			}
+		}
+
+		// shadow traffic of the route, not applicable for WebSocket route
+		var routeMirror *Mirror
+		if mirrorCfg, found := cfg.GetSubConfig(routeName + ".mirror"); found && routeMethod != methodWebSocket {
+			if routeMirror, err = parseMirrorSection(routeName, mirrorCfg); err != nil {
+				return
+			}
+		}
+
+		// getting route tags, merged with parent route tags
+		routeTags := routeInfo.Tags
+		if tags, found := cfg.StringList(routeName + ".tags"); found {
+			routeTags = append([]string{}, routeInfo.Tags...)
+			for _, t := range tags {
+				if t = strings.TrimSpace(t); len(t) > 0 && !ess.IsSliceContainsString(routeTags, t) {
+					routeTags = append(routeTags, t)
+				}
+			}
+		}
+
+		// response header policy, merged with parent policy then route tag
+		// policies of the domain
+		routeHeaders := routeInfo.Headers
+		for _, t := range routeTags {
+			if hp, found := routeInfo.TagHeaders[t]; found && !ess.IsSliceContainsString(routeInfo.Tags, t) {
+				routeHeaders = routeHeaders.merge(hp)
+			}
+		}
+		if headersCfg, found := cfg.GetSubConfig(routeName + ".headers"); found {
+			hp, er := parseHeaderPolicy(routeName, headersCfg)
+			if er != nil {
+				err = er
+				return
+			}
+			routeHeaders = routeHeaders.merge(hp)
+		}
+
+		// getting route enabled value, disabled route is not served until it
+		// is enabled via `Domain.EnableRoute`
+		routeEnabled := cfg.BoolDefault(routeName+".enabled", !routeInfo.Disabled)
+
 		// 'anti_csrf_check', 'cors' and 'max_body_size' not applicable for WebSocket
 		if routeMethod == methodWebSocket {
 			routeAntiCSRFCheck = false
@@ -619,6 +870,7 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 			routeMaxBodySize = 0
 		}
 
+		routeSection := routeInfo.Section + "." + routeName
 		if notToSkip {
 			for _, m := range strings.Split(routeMethod, ",") {
 				routes = append(routes, &Route{
@@ -631,8 +883,22 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 					Auth:              routeAuth,
 					MaxBodySize:       routeMaxBodySize,
 					IsAntiCSRFCheck:   routeAntiCSRFCheck,
+					Idempotent:        routeIdempotent,
+					Singleflight:      routeSingleflight,
+					RawBody:           routeRawBody,
+					DisableCompress:   !routeCompress,
+					DisableMinify:     !routeMinify,
+					Version:           routeVersion,
 					CORS:              cors,
 					Constraints:       routeConstraints,
+					Proxy:             routeProxy,
+					Canary:            routeCanary,
+					Mirror:            routeMirror,
+					Headers:           routeHeaders,
+					Tags:              routeTags,
+					versionPrefix:     routeVersionPrefix,
+					section:           routeSection,
+					disabled:          !routeEnabled,
 					authorizationInfo: routeAuthorizationInfo,
 				})
 			}
@@ -641,14 +907,26 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 		// loading child routes
 		if childRoutes, found := cfg.GetSubConfig(routeName + ".routes"); found {
 			croutes, er := parseSectionRoutes(childRoutes, &parentRouteInfo{
+				Disabled:          !routeEnabled,
 				ParentName:        routeName,
 				PrefixPath:        routePath,
 				Target:            routeTarget,
 				Auth:              routeAuth,
 				MaxBodySizeStr:    routeInfo.MaxBodySizeStr,
 				AntiCSRFCheck:     routeAntiCSRFCheck,
+				Idempotent:        routeIdempotent,
+				Singleflight:      routeSingleflight,
+				RawBody:           routeRawBody,
+				Version:           routeVersion,
+				VersionPrefix:     routeVersionPrefix,
+				Section:           routeSection + ".routes",
 				CORS:              cors,
 				CORSEnabled:       routeInfo.CORSEnabled,
+				Headers:           routeHeaders,
+				TagHeaders:        routeInfo.TagHeaders,
+				Tags:              routeTags,
+				Compress:          routeCompress,
+				Minify:            routeMinify,
 				AuthorizationInfo: routeAuthorizationInfo,
 			})
 			if er != nil {
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
@@ -92,6 +93,348 @@ func TestRouterLoadConfiguration(t *testing.T) {
 	assert.Equal(t, "localhost", domain.Key)
 }
 
+func TestRouterDomainLookupFast(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+
+	params := ahttp.AcquireURLParams()
+	defer ahttp.ReleaseURLParams(params)
+
+	route := domain.LookupFast(ahttp.MethodPost, "/hotels/12345/cancel", params)
+	assert.Equal(t, "cancel_booking", route.Name)
+	assert.Equal(t, "12345", params.Get("id"))
+	assert.Equal(t, 1, len(*params))
+
+	// params reused and reset
+	route = domain.LookupFast(ahttp.MethodPost, "/hotels/67890/cancel", params)
+	assert.Equal(t, "cancel_booking", route.Name)
+	assert.Equal(t, ahttp.URLParams{{Key: "id", Value: "67890"}}, *params)
+
+	assert.Nil(t, domain.LookupFast(ahttp.MethodPost, "/not-exists", params))
+	assert.Equal(t, 0, len(*params))
+	assert.Nil(t, domain.LookupFast("UNKNOWN", "/hotels/12345/cancel", params))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = domain.LookupFast(ahttp.MethodPost, "/hotels/12345/cancel", params)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestRouterDomainLookupCache(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+	assert.Equal(t, LookupCacheStats{Capacity: 2}, domain.LookupCacheStats())
+
+	lookup := func(method, path string) *Route {
+		req := createHTTPRequest("localhost:8080", path)
+		req.Method = method
+		route, _, _ := domain.Lookup(req)
+		return route
+	}
+
+	assert.Equal(t, "logout", lookup(ahttp.MethodGet, "/logout").Name)
+	assert.Equal(t, "logout", lookup(ahttp.MethodGet, "/logout").Name)
+	assert.Equal(t, "login", lookup(ahttp.MethodPost, "/login").Name)
+	assert.Equal(t, "register_user", lookup(ahttp.MethodGet, "/register").Name)
+	assert.Equal(t, "edit_user", lookup(ahttp.MethodPost, "/register").Name)
+
+	// path params and not found routes are not cached
+	assert.Equal(t, "show_hotels", lookup(ahttp.MethodGet, "/hotels/12345").Name)
+	assert.Nil(t, lookup(ahttp.MethodGet, "/not-exists"))
+
+	stats := domain.LookupCacheStats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(6), stats.Misses)
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, float64(1)/7, stats.HitRate())
+
+	// least recently used '/logout' evicted
+	params := ahttp.AcquireURLParams()
+	defer ahttp.ReleaseURLParams(params)
+	assert.Equal(t, "edit_user", domain.LookupFast(ahttp.MethodPost, "/register", params).Name)
+	assert.Equal(t, "logout", domain.LookupFast(ahttp.MethodGet, "/logout", params).Name)
+	assert.Equal(t, uint64(2), domain.LookupCacheStats().Hits)
+
+	// invalidated on route add
+	err = domain.AddRoute(&Route{Name: "about", Path: "/about", Method: ahttp.MethodGet})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, domain.LookupCacheStats().Size)
+	assert.Equal(t, "about", lookup(ahttp.MethodGet, "/about").Name)
+
+	assert.Equal(t, float64(0), LookupCacheStats{}.HitRate())
+	domain.cache = nil
+	assert.Equal(t, LookupCacheStats{}, domain.LookupCacheStats())
+}
+
+func TestRouterDisableEnableRoute(t *testing.T) {
+	router, err := createRouter("routes-toggle.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+
+	lookup := func(method, path string) *Route {
+		req := createHTTPRequest("localhost:8080", path)
+		req.Method = method
+		route, _, _ := domain.Lookup(req)
+		return route
+	}
+
+	// disabled via config, child routes inherit
+	assert.Nil(t, lookup(ahttp.MethodGet, "/dashboard/v2"))
+	assert.Nil(t, lookup(ahttp.MethodGet, "/reports"))
+	assert.Nil(t, lookup(ahttp.MethodGet, "/reports/1001"))
+	assert.False(t, domain.IsRouteEnabled("show_report"))
+	assert.Equal(t, "/dashboard/v2", domain.RouteURL("dashboard_v2"))
+
+	err = router.EnableRoute("localhost:8080", "dashboard_v2")
+	assert.Nil(t, err)
+	assert.True(t, domain.IsRouteEnabled("dashboard_v2"))
+	assert.Equal(t, "dashboard_v2", lookup(ahttp.MethodGet, "/dashboard/v2").Name)
+
+	// all the methods of route
+	assert.Equal(t, "users", lookup(ahttp.MethodPost, "/users").Name)
+	err = router.DisableRoute("localhost:8080", "users")
+	assert.Nil(t, err)
+	assert.Nil(t, lookup(ahttp.MethodGet, "/users"))
+	assert.Nil(t, lookup(ahttp.MethodPost, "/users"))
+	assert.Equal(t, "", domain.Allowed(ahttp.MethodPut, "/users"))
+	params := ahttp.AcquireURLParams()
+	defer ahttp.ReleaseURLParams(params)
+	assert.Nil(t, domain.LookupFast(ahttp.MethodPost, "/users", params))
+	assert.Equal(t, "index", lookup(ahttp.MethodGet, "/").Name)
+
+	// route added after disable
+	err = domain.AddRoute(&Route{Name: "about", Path: "/about", Method: ahttp.MethodPut})
+	assert.Nil(t, err)
+	assert.Equal(t, "about", lookup(ahttp.MethodPut, "/about").Name)
+
+	err = domain.EnableRoute("users")
+	assert.Nil(t, err)
+	assert.Equal(t, "users", lookup(ahttp.MethodPost, "/users").Name)
+
+	// method tree which has all the routes disabled stays excluded on
+	// subsequent toggle of other method
+	err = domain.AddRoute(&Route{Name: "contact", Path: "/contact", Method: ahttp.MethodDelete})
+	assert.Nil(t, err)
+	err = domain.AddRoute(&Route{Name: "feedback", Path: "/feedback", Method: ahttp.MethodPatch})
+	assert.Nil(t, err)
+	assert.Nil(t, domain.DisableRoute("contact"))
+	assert.Nil(t, lookup(ahttp.MethodDelete, "/contact"))
+	assert.Nil(t, domain.DisableRoute("feedback"))
+	assert.Nil(t, lookup(ahttp.MethodDelete, "/contact"))
+	assert.Nil(t, lookup(ahttp.MethodPatch, "/feedback"))
+	assert.False(t, domain.IsRouteEnabled("contact"))
+	assert.Nil(t, domain.EnableRoute("feedback"))
+	assert.Nil(t, lookup(ahttp.MethodDelete, "/contact"))
+	assert.Equal(t, "feedback", lookup(ahttp.MethodPatch, "/feedback").Name)
+
+	// lookup cache does not add route looked up before the swap
+	domain.EnableLookupCache(10)
+	gen := domain.cache.generation()
+	assert.Nil(t, domain.DisableRoute("index"))
+	domain.cache.add(ahttp.MethodGet, "/", &Route{Name: "index"}, gen)
+	assert.Nil(t, lookup(ahttp.MethodGet, "/"))
+	assert.Equal(t, 0, domain.LookupCacheStats().Size)
+	assert.Nil(t, domain.EnableRoute("index"))
+	assert.Equal(t, "index", lookup(ahttp.MethodGet, "/").Name)
+	assert.Equal(t, 1, domain.LookupCacheStats().Size)
+
+	// errors
+	err = router.DisableRoute("localhost:8080", "not_exists")
+	assert.Equal(t, "router: route 'not_exists' not exists in domain 'localhost:8080'", err.Error())
+	err = router.EnableRoute("example.com", "users")
+	assert.Equal(t, "router: domain 'example.com' not exists", err.Error())
+	err = router.DisableRoute("example.com", "users")
+	assert.NotNil(t, err)
+}
+
+func TestRouterCanaryLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-canary.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+
+	users := domain.LookupByName("users")
+	assert.Equal(t, &Canary{Weight: 10, Target: "v2/UserController", Action: "Index",
+		Cookie: "aah_canary_users", Header: "X-Canary"}, users.Canary)
+	assert.Equal(t, "canary(target:v2/UserController action:Index weight:10)", users.Canary.String())
+
+	orders := domain.LookupByName("orders")
+	assert.Equal(t, &Canary{Weight: 50, Target: "v2/OrderController", Action: "List",
+		Cookie: "orders_variant", Header: "X-Orders-Variant"}, orders.Canary)
+
+	// header, cookie and weight
+	req, _ := http.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+	req.Header.Set("X-Orders-Variant", "canary")
+	v, assigned := orders.Canary.Variant(req)
+	assert.Equal(t, VariantCanary, v)
+	assert.False(t, assigned)
+
+	req, _ = http.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "orders_variant", Value: "stable"})
+	v, assigned = orders.Canary.Variant(req)
+	assert.Equal(t, VariantStable, v)
+	assert.False(t, assigned)
+
+	req, _ = http.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+	v, assigned = (&Canary{Weight: 0}).Variant(req)
+	assert.Equal(t, VariantStable, v)
+	assert.True(t, assigned)
+	v, _ = (&Canary{Weight: 100}).Variant(req)
+	assert.Equal(t, VariantCanary, v)
+
+	_, err = createRouter("routes-canary-error.conf")
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "'users.canary.weight' value '120' is not in range 0 to 100"))
+}
+
+func TestRouterMirrorLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-mirror.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+
+	users := domain.LookupByName("users")
+	assert.NotNil(t, users.Mirror)
+	assert.Equal(t, "http://new-backend:9000/api", users.Mirror.Target.String())
+	assert.Equal(t, 0.05, users.Mirror.Sample)
+	assert.Equal(t, int64(1048576), users.Mirror.MaxBodySize)
+	assert.Equal(t, 2*time.Second, users.Mirror.Timeout)
+	assert.Equal(t, "mirror(target:http://new-backend:9000/api sample:0.05)", users.Mirror.String())
+
+	orders := domain.LookupByName("orders")
+	assert.Equal(t, float64(1), orders.Mirror.Sample)
+	assert.Equal(t, int64(65536), orders.Mirror.MaxBodySize)
+	assert.Equal(t, 5*time.Second, orders.Mirror.Timeout)
+
+	_, err = createRouter("routes-mirror-error.conf")
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "'users.mirror.target_url' value 'ftp://new-backend' is not a valid http(s) URL"))
+}
+
+func TestRouterHeaderPolicyLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-headers.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+	assert.Equal(t, http.Header{"X-Robots-Tag": {"noindex"}, "X-Frame-Options": {"DENY"}}, domain.Headers.Set)
+	assert.Equal(t, []string{"Server"}, domain.Headers.Remove)
+
+	// domain policy
+	assert.Equal(t, domain.Headers, domain.LookupByName("index").Headers)
+
+	// namespace policy merged with domain
+	admin := domain.LookupByName("admin").Headers
+	assert.Equal(t, http.Header{"X-Frame-Options": {"DENY"}, "Cache-Control": {"no-store"},
+		"Server": {"admin"}}, admin.Set)
+	assert.Equal(t, []string{"X-Robots-Tag"}, admin.Remove)
+
+	// route policy merged with namespace
+	users := domain.LookupByName("admin_users").Headers
+	assert.Equal(t, http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Cache-Control": {"no-store"},
+		"Server": {"admin"}}, users.Set)
+
+	hdr := http.Header{"Server": {"aah"}, "X-Robots-Tag": {"noindex"}}
+	users.Apply(hdr)
+	assert.Equal(t, http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Cache-Control": {"no-store"},
+		"Server": {"admin"}}, hdr)
+	assert.True(t, strings.HasPrefix(users.String(), "headers(set:"))
+
+	_, err = createRouter("routes-headers-error.conf")
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "'index.headers.set' value 'X-Robots-Tag' must be in the format 'Name: value'"))
+}
+
+func TestRouterTagsLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-tags.conf")
+	assert.Nil(t, err)
+	domain := router.Lookup("localhost:8080")
+
+	index := domain.LookupByName("index")
+	assert.Equal(t, []string{"public"}, index.Tags)
+	assert.True(t, index.HasTag("public"))
+	assert.False(t, index.HasTag("api"))
+	assert.Equal(t, http.Header{"X-Frame-Options": {"DENY"},
+		"Cache-Control": {"public, max-age=300"}}, index.Headers.Set)
+
+	invoices := domain.LookupByName("invoices")
+	assert.Equal(t, []string{"api", "billing"}, invoices.Tags)
+	assert.Equal(t, http.Header{"Cache-Control": {"no-store"}}, invoices.Headers.Set)
+
+	// inherited tags
+	download := domain.LookupByName("invoice_download")
+	assert.Equal(t, []string{"api", "billing", "download"}, download.Tags)
+	assert.True(t, download.HasAnyTag("public", "billing"))
+	assert.Equal(t, http.Header{"Cache-Control": {"no-store"}, "X-Download-Options": {"noopen"}}, download.Headers.Set)
+	assert.Equal(t, []string{"X-Frame-Options"}, download.Headers.Remove)
+
+	about := domain.LookupByName("about")
+	assert.Nil(t, about.Tags)
+	assert.False(t, about.HasAnyTag("public", "api"))
+	assert.Equal(t, domain.Headers, about.Headers)
+
+	var nilRoute *Route
+	assert.False(t, nilRoute.HasTag("public"))
+}
+
+func BenchmarkDomainLookup(b *testing.B) {
+	router, _ := createRouter("routes.conf")
+	domain := router.Lookup("localhost:8080")
+	req := createHTTPRequest("localhost:8080", "/hotels/12345/cancel")
+	req.Method = ahttp.MethodPost
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = domain.Lookup(req)
+	}
+}
+
+func BenchmarkDomainLookupFast(b *testing.B) {
+	router, _ := createRouter("routes.conf")
+	domain := router.Lookup("localhost:8080")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params := ahttp.AcquireURLParams()
+		_ = domain.LookupFast(ahttp.MethodPost, "/hotels/12345/cancel", params)
+		ahttp.ReleaseURLParams(params)
+	}
+}
+
+func TestRouterConflictDiagnostics(t *testing.T) {
+	_, err := createRouter("routes-conflict.conf")
+	assert.NotNil(t, err)
+
+	errs, ok := err.(ConfigErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(errs))
+
+	ce := errs[0].(*RouteConflictError)
+	assert.Equal(t, "assets_dup", ce.Route.Name)
+	assert.Equal(t, "assets", ce.Existing.Name)
+	assert.True(t, strings.Contains(ce.Error(), "at domains.admin.static.assets_dup (routes-conflict.conf:42)"))
+
+	ce = errs[1].(*RouteConflictError)
+	assert.Equal(t, "users", ce.Route.Name)
+	assert.Equal(t, "list_users", ce.Existing.Name)
+	assert.True(t, strings.Contains(ce.Error(), "(routes-conflict.conf:8) conflicts with route 'list_users' [GET /users] at "+
+		"domains.localhost.routes.list_users (routes-conflict.conf:24)"))
+
+	ce = errs[2].(*RouteConflictError)
+	assert.Equal(t, "show_user_by_name", ce.Route.Name)
+	assert.Equal(t, "show_user", ce.Existing.Name)
+	assert.Equal(t, "router: route 'show_user_by_name' [GET /users/:name] at "+
+		"domains.localhost.routes.users.routes.show_user_by_name (routes-conflict.conf:17) "+
+		"conflicts with route 'show_user' [GET /users/:id] at "+
+		"domains.localhost.routes.users.routes.show_user (routes-conflict.conf:13): "+
+		"aah/router: parameter based edge already exists[/users/:id...] new[/users/:name...]", ce.Error())
+
+	assert.True(t, strings.HasPrefix(err.Error(), "router: routes configuration has 3 errors:\n\trouter: route 'assets_dup'"))
+
+	// without source
+	ce = &RouteConflictError{Route: &Route{Name: "a", Method: "GET", Path: "/a"}, Err: errNodeExists}
+	assert.Equal(t, "router: route 'a' [GET /a]: aah/router: node exists", ce.Error())
+	assert.Equal(t, 0, findSectionLine([]byte("a {\n}"), "a.b"))
+}
+
 func TestRouterWildcardSubdomain(t *testing.T) {
 	router, err := createRouter("routes.conf")
 	assert.Nil(t, err, "")
@@ -183,6 +526,96 @@ func TestRouterStaticLoadConfiguration(t *testing.T) {
 	assert.Nil(t, params)
 }
 
+func TestRouterStaticSiteLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-static-site.conf")
+	assert.Nil(t, err, "")
+
+	domain := router.Lookup("localhost:8080")
+	assert.Nil(t, domain.StaticSite)
+
+	domain = router.Lookup("docs.localhost:8080")
+	site := domain.StaticSite
+	assert.NotNil(t, site)
+	assert.Equal(t, "sites/docs", site.Dir)
+	assert.Equal(t, "index.html", site.Index)
+	assert.Equal(t, "not-found.html", site.NotFound)
+	assert.False(t, site.CleanURLs)
+	assert.True(t, site.Route.IsStatic)
+	assert.True(t, site.Route.IsDir())
+	assert.True(t, site.Route.IsFramework())
+	assert.True(t, site.Route.DisableCompress)
+
+	// no routes defined in the static site domain
+	req := createHTTPRequest("docs.localhost:8080", "/guide")
+	req.Method = ahttp.MethodGet
+	route, _, _ := domain.Lookup(req)
+	assert.Nil(t, route)
+
+	assert.Equal(t, []string{"index.html"}, site.Candidates("/"))
+	assert.Equal(t, []string{"api/index.html"}, site.Candidates("/api/"))
+	assert.Equal(t, []string{"guide", "guide/index.html"}, site.Candidates("/guide"))
+	assert.Equal(t, []string{"etc/passwd", "etc/passwd/index.html"}, site.Candidates("/../../etc/passwd"))
+
+	site.CleanURLs = true
+	assert.Equal(t, []string{"guide", "guide/index.html", "guide.html"}, site.Candidates("/guide"))
+	assert.Equal(t, []string{"css/app.css", "css/app.css/index.html"}, site.Candidates("/css/app.css"))
+	assert.True(t, site.IsIndex("api/index.html"))
+	assert.False(t, site.IsIndex("guide.html"))
+
+	router, err = createRouter("routes-static-site-error.conf")
+	assert.Nil(t, router)
+	assert.Equal(t, "'static_site.dir' value is missing in domain 'docs_localhost'", err.Error())
+}
+
+func TestRouterProxyLoadConfiguration(t *testing.T) {
+	router, err := createRouter("routes-proxy.conf")
+	assert.Nil(t, err, "")
+	assert.Equal(t, 0, len(router.RegisteredActions()))
+
+	domain := router.Lookup("localhost:8080")
+	for _, method := range []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+		req := createHTTPRequest("localhost:8080", "/backend/users/1")
+		req.Method = method
+		route, pathParams, _ := domain.Lookup(req)
+		assert.NotNil(t, route, method)
+		assert.True(t, route.IsProxy())
+		assert.Equal(t, "users/1", pathParams.Get(ProxyPathParam))
+	}
+
+	route := domain.LookupByName("backend")
+	assert.Equal(t, "/backend/*proxypath", route.Path)
+	assert.Equal(t, "form_auth", route.Auth)
+	assert.Equal(t, "", route.Target)
+	assert.False(t, route.IsAntiCSRFCheck)
+	assert.Equal(t, int64(1048576), route.MaxBodySize)
+	assert.True(t, strings.HasPrefix(route.String(), "proxyroute(name:backend"))
+
+	p := route.Proxy
+	assert.Equal(t, "http://127.0.0.1:9000/api", p.Target.String())
+	assert.True(t, p.StripPrefix)
+	assert.True(t, p.WebSocket)
+	assert.False(t, p.PreserveHost)
+	assert.Equal(t, 5*time.Second, p.DialTimeout)
+	assert.Equal(t, 30*time.Second, p.ResponseHeaderTimeout)
+	assert.Equal(t, time.Duration(0), p.IdleConnTimeout)
+	assert.Equal(t, "proxy(target:http://127.0.0.1:9000/api strip_prefix:true websocket:true)", p.String())
+
+	hdr := http.Header{"Cookie": []string{"a=b"}, "Accept": []string{"*/*"}}
+	p.RequestHeaders.Apply(hdr)
+	assert.Equal(t, http.Header{"Accept": []string{"*/*"}, "X-Env": []string{"test"}, "X-App": []string{"webapp"}}, hdr)
+	assert.Equal(t, []string{"Server"}, p.ResponseHeaders.Remove)
+
+	route = domain.LookupByName("files")
+	assert.Equal(t, "/files/*path", route.Path)
+	assert.Equal(t, "GET", route.Method)
+	assert.False(t, route.Proxy.StripPrefix)
+	assert.Equal(t, "https://files.internal", route.Proxy.Target.String())
+
+	router, err = createRouter("routes-proxy-error.conf")
+	assert.Nil(t, router)
+	assert.Equal(t, "'backend.proxy.target' value 'backend:9000' is not a valid http(s) URL", err.Error())
+}
+
 func TestRouterErrorLoadConfiguration(t *testing.T) {
 	router, err := createRouter("routes-error.conf")
 	assert.NotNilf(t, err, "expected error loading '%v'", "routes-error.conf")
@@ -280,6 +713,39 @@ func TestRouterRegisteredActions(t *testing.T) {
 	assert.Equal(t, 3, len(methods))
 }
 
+func TestRouterWalk(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err, "")
+
+	var count int
+	err = router.Walk(func(d *Domain, r *Route) error {
+		assert.Equal(t, r, d.LookupByName(r.Name))
+		count++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, len(router.Domains[0].routes)+len(router.Domains[1].routes), count)
+
+	errStop := errors.New("stop")
+	count = 0
+	err = router.Walk(func(d *Domain, r *Route) error {
+		count++
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, count)
+
+	// catch-all route
+	router, err = createRouter("routes-cors-1.conf")
+	assert.Nil(t, err, "")
+	var catchAll *Route
+	_ = router.Walk(func(d *Domain, r *Route) error {
+		catchAll = r
+		return nil
+	})
+	assert.Equal(t, "*", catchAll.Path)
+}
+
 func TestRouterIsDefaultAction(t *testing.T) {
 	v1 := IsDefaultAction("Index")
 	assert.True(t, v1)
@@ -317,6 +783,67 @@ func TestRouterDomainAllowed(t *testing.T) {
 	assert.Nil(t, domain)
 }
 
+func TestRouterDomainAutoHead(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err, "")
+
+	req := createHTTPRequest("localhost:8080", "/hotels")
+	req.Method = ahttp.MethodHead
+	domain := router.Lookup(req.Host)
+	assert.False(t, domain.AutoHead)
+	route, _, _ := domain.Lookup(req)
+	assert.Nil(t, route)
+	assert.False(t, strings.Contains(domain.Allowed(ahttp.MethodPost, "/hotels"), ahttp.MethodHead))
+
+	domain.AutoHead = true
+	route, _, _ = domain.Lookup(req)
+	assert.NotNil(t, route)
+	assert.Equal(t, ahttp.MethodGet, route.Method)
+	assert.Equal(t, ahttp.MethodHead, req.Method)
+	assert.True(t, strings.Contains(domain.Allowed(ahttp.MethodPost, "/hotels"), ahttp.MethodHead))
+
+	// HEAD route tree exists however path is served by GET
+	assert.Nil(t, domain.AddRoute(&Route{Name: "head_ping", Path: "/ping", Method: ahttp.MethodHead}))
+	route, _, _ = domain.Lookup(req)
+	assert.NotNil(t, route)
+	assert.Equal(t, ahttp.MethodGet, route.Method)
+
+	req = createHTTPRequest("localhost:8080", "/ping")
+	req.Method = ahttp.MethodHead
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "head_ping", route.Name)
+}
+
+func TestRouterCompressMinify(t *testing.T) {
+	router, err := createRouter("routes-compress.conf")
+	assert.Nil(t, err, "")
+
+	domain := router.Lookup("localhost:8080")
+	assert.True(t, domain.Compress)
+	assert.False(t, domain.Minify)
+
+	testcases := []struct {
+		name            string
+		disableCompress bool
+		disableMinify   bool
+	}{
+		{name: "index", disableCompress: false, disableMinify: true},
+		{name: "image_proxy", disableCompress: true, disableMinify: true},
+		{name: "image_thumb", disableCompress: true, disableMinify: true},
+		{name: "docs", disableCompress: false, disableMinify: false},
+		{name: "images", disableCompress: true, disableMinify: false},
+		{name: "css", disableCompress: false, disableMinify: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			route := domain.LookupByName(tc.name)
+			assert.NotNil(t, route)
+			assert.Equal(t, tc.disableCompress, route.DisableCompress)
+			assert.Equal(t, tc.disableMinify, route.DisableMinify)
+		})
+	}
+}
+
 func TestRouterDomainRouteURL(t *testing.T) {
 	router, err := createRouter("routes.conf")
 	assert.Nil(t, err, "")
@@ -455,6 +982,135 @@ func TestRouterNamespaceConfig(t *testing.T) {
 	assert.Equal(t, "'list_users.action' key is missing or it seems to be multiple HTTP methods", err.Error())
 }
 
+func TestRouterAPIVersionConfig(t *testing.T) {
+	router, err := createRouter("routes-api-version.conf")
+	assert.Nil(t, err, "")
+
+	domain := router.Lookup("localhost:8080")
+	assert.NotNil(t, domain.APIVersion)
+	assert.Equal(t, APIVersionByHeader, domain.APIVersion.Strategy)
+	assert.Equal(t, "/v1", domain.APIVersion.Prefix("1"))
+	assert.Equal(t, "/v2", domain.APIVersion.Prefix("2"))
+	assert.Equal(t, "1", domain.LookupByNameVersion("get_user", "1").Version)
+	assert.Equal(t, "2", domain.LookupByNameVersion("get_user", "2").Version)
+	assert.Equal(t, "", domain.LookupByName("index").Version)
+
+	// header strategy
+	req := createHTTPRequest("localhost:8080", "/users/10")
+	req.Method = ahttp.MethodGet
+	req.Header = http.Header{}
+	route, params, _ := domain.Lookup(req)
+	assert.Equal(t, "/v1/users/:id", route.Path)
+	assert.Equal(t, "10", params.Get("id"))
+
+	req.Header.Set("X-API-Version", "2")
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "/v2/users/:id", route.Path)
+
+	req.URL.Path = "/v1/users"
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "/v1/users", route.Path)
+
+	req.URL.Path = "/"
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "index", route.Name)
+
+	// media type strategy
+	domain.APIVersion.Strategy = APIVersionByMediaType
+	req = createHTTPRequest("localhost:8080", "/users")
+	req.Method = ahttp.MethodGet
+	req.Header = http.Header{}
+	req.Header.Set(ahttp.HeaderAccept, "application/json; version=2")
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "/v2/users", route.Path)
+
+	// path strategy
+	domain.APIVersion.Strategy = APIVersionByPath
+	req.URL.Path = "/v2/users/10"
+	assert.Equal(t, "2", domain.APIVersion.Resolve(req))
+	req.URL.Path = "/users"
+	assert.Equal(t, "1", domain.APIVersion.Resolve(req))
+	route, _, _ = domain.Lookup(req)
+	assert.Nil(t, route)
+
+	// reverse URL
+	assert.Equal(t, "//localhost:8080/v1/users/10", router.CreateVersionRouteURL("localhost:8080", "1", "get_user", nil, 10))
+	assert.Equal(t, "//localhost:8080/v2/users/10", router.CreateVersionRouteURL("localhost:8080", "2", "get_user", map[string]interface{}{"id": 10}))
+	assert.Equal(t, "//localhost:8080/", router.CreateVersionRouteURL("localhost:8080", "2", "index", nil))
+	assert.Equal(t, "", router.CreateVersionRouteURL("localhost:8080", "2", "not_exists", nil))
+
+	// error
+	cfg, _ := config.ParseString(`api_version {
+		strategy = "query"
+	}`)
+	_, err = parseAPIVersionSection(cfg)
+	assert.Equal(t, "router: unsupported api_version strategy 'query'", err.Error())
+}
+
+func TestRouterLocalePrefixConfig(t *testing.T) {
+	router, err := createRouter("routes-locale-prefix.conf")
+	assert.Nil(t, err, "")
+
+	domain := router.Lookup("localhost:8080")
+	assert.NotNil(t, domain.LocalePrefix)
+	assert.Equal(t, "en", domain.LocalePrefix.Default)
+	assert.True(t, domain.LocalePrefix.RedirectRoot)
+
+	testcases := []struct {
+		path, name, locale, param string
+	}{
+		{"/", "index", "", ""},
+		{"/en", "index", "en", ""},
+		{"/de/about.html", "about", "de", ""},
+		{"/zh-cn/hotels/10", "show_hotel", "zh-CN", "10"},
+		{"/hotels/20", "show_hotel", "", "20"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.path, func(t *testing.T) {
+			req := createHTTPRequest("localhost:8080", tc.path)
+			req.Method = ahttp.MethodGet
+			route, params, _ := domain.Lookup(req)
+			assert.NotNil(t, route)
+			assert.Equal(t, tc.name, route.Name)
+			assert.Equal(t, tc.locale, route.Locale)
+			assert.Equal(t, tc.param, params.Get("id"))
+		})
+	}
+
+	// static routes are not localized
+	req := createHTTPRequest("localhost:8080", "/de/static/app.js")
+	req.Method = ahttp.MethodGet
+	route, _, _ := domain.Lookup(req)
+	assert.Nil(t, route)
+
+	// reverse URL
+	assert.Equal(t, "//localhost:8080/de/hotels/10", router.CreateLocaleRouteURL("localhost:8080", "de", "", "show_hotel", nil, 10))
+	assert.Equal(t, "//localhost:8080/en", router.CreateLocaleRouteURL("localhost:8080", "en", "", "index", nil))
+	assert.Equal(t, "//localhost:8080/hotels/10", router.CreateLocaleRouteURL("localhost:8080", "", "", "show_hotel", map[string]interface{}{"id": 10}))
+	assert.Equal(t, "//localhost:8080/about.html", router.CreateLocaleRouteURL("localhost:8080", "fr", "", "about", nil))
+
+	// negotiate
+	for _, tc := range []struct{ acceptLanguage, locale string }{
+		{"de-DE,de;q=0.9,en;q=0.8", "de"},
+		{"zh-CN", "zh-CN"},
+		{"fr-FR,zh-TW;q=0.8", "zh-CN"},
+		{"fr-FR", "en"},
+		{"", "en"},
+	} {
+		req := createHTTPRequest("localhost:8080", "/")
+		req.Header = http.Header{}
+		req.Header.Set(ahttp.HeaderAcceptLanguage, tc.acceptLanguage)
+		assert.Equal(t, tc.locale, domain.LocalePrefix.Negotiate(req))
+	}
+
+	// error
+	cfg, _ := config.ParseString(`locale_prefix {
+		redirect_root = false
+	}`)
+	_, err = parseLocalePrefixSection(cfg)
+	assert.Equal(t, "router: 'locale_prefix.locales' value is missing", err.Error())
+}
+
 func TestRouterNamespaceSimplifiedConfig(t *testing.T) {
 	router, err := createRouter("routes-simplified.conf")
 	assert.Nil(t, err, "")
@@ -656,11 +1312,13 @@ type app struct {
 	cfg *config.Config
 	l   log.Loggerer
 	sec *security.Manager
+	fs  *vfs.VFS
 }
 
 func (a *app) Config() *config.Config             { return a.cfg }
 func (a *app) Log() log.Loggerer                  { return a.l }
 func (a *app) SecurityManager() *security.Manager { return a.sec }
+func (a *app) VFS() *vfs.VFS                      { return a.fs }
 
 func createRouter(filename string) (*Router, error) {
 	rfs := new(vfs.VFS)
@@ -682,7 +1340,7 @@ func createRouter(filename string) (*Router, error) {
 	_ = sec.AddAuthScheme("form", &scheme.FormAuth{LoginSubmitURL: "/login"})
 
 	// config path in vfs, filepath.Join not required
-	return NewWithApp(&app{cfg: appCfg, l: l, sec: sec}, "/app/config/"+filename)
+	return NewWithApp(&app{cfg: appCfg, l: l, sec: sec, fs: rfs}, "/app/config/"+filename)
 }
 
 func createHTTPRequest(host, path string) *http.Request {
@@ -704,3 +1362,24 @@ func testdataBaseDir() string {
 	}
 	return filepath.Join(wd, ".testdata")
 }
+
+func TestRouterAuthSchemesExists(t *testing.T) {
+	sec := security.New()
+	_ = sec.AddAuthScheme("form_auth", &scheme.FormAuth{BaseAuth: scheme.BaseAuth{Name: "form"}})
+	_ = sec.AddAuthScheme("basic_auth", &scheme.BasicAuth{BaseAuth: scheme.BaseAuth{Name: "basic"}})
+	_ = sec.AddAuthScheme("api_auth", &scheme.GenericAuth{BaseAuth: scheme.BaseAuth{Name: "generic"}})
+	_ = sec.AddAuthScheme("passkey_auth", &scheme.WebAuthn{BaseAuth: scheme.BaseAuth{Name: "webauthn"}})
+
+	for auth, expected := range map[string]bool{
+		"basic_auth":            true,
+		"form_auth, basic_auth": true,
+		"api_auth?basic_auth":   true,
+		"api_auth ? basic_auth": true,
+		"api_auth?jwt_auth":     false,
+		"form_auth?basic_auth":  false,
+		"passkey_auth?api_auth": false,
+		"api_auth?basic_auth,x": false,
+	} {
+		assert.Equal(t, expected, isAuthSchemesExists(sec, auth), auth)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// StaticSite struct holds the static site configuration of the domain, it
+// serves an entire directory tree as the domain root. Routes defined in the
+// domain takes priority over static site.
+//
+// 	static_site {
+// 	  dir = "sites/docs"
+// 	  index = "index.html"
+// 	  not_found = "404.html"
+// 	  clean_urls = true
+// 	}
+type StaticSite struct {
+	CleanURLs bool
+	Dir       string
+	Index     string
+	NotFound  string
+	Route     *Route
+}
+
+// Candidates method returns the file paths (relative to site directory) in
+// the order of resolution for given request path. For e.g.: request path
+// `/guide` resolves to `guide`, `guide/index.html` and `guide.html` (clean URL).
+func (s *StaticSite) Candidates(reqPath string) []string {
+	p := strings.TrimPrefix(path.Clean("/"+reqPath), "/")
+	if len(p) == 0 {
+		return []string{s.Index}
+	}
+	if strings.HasSuffix(reqPath, "/") {
+		return []string{path.Join(p, s.Index)}
+	}
+	candidates := []string{p, path.Join(p, s.Index)}
+	if s.CleanURLs && len(path.Ext(p)) == 0 {
+		candidates = append(candidates, p+".html")
+	}
+	return candidates
+}
+
+// IsIndex method returns true if given candidate file path is directory index.
+func (s *StaticSite) IsIndex(candidate string) bool {
+	return path.Base(candidate) == s.Index
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func parseStaticSiteSection(domain *Domain, cfg *config.Config) (*StaticSite, error) {
+	dir := strings.TrimSpace(cfg.StringDefault("dir", ""))
+	if ess.IsStrEmpty(dir) {
+		return nil, fmt.Errorf("'static_site.dir' value is missing in domain '%s'", domain.Name)
+	}
+
+	site := &StaticSite{
+		CleanURLs: cfg.BoolDefault("clean_urls", true),
+		Dir:       dir,
+		Index:     cfg.StringDefault("index", "index.html"),
+		NotFound:  cfg.StringDefault("not_found", "404.html"),
+	}
+	site.Route = &Route{
+		Name:            "static_site" + autoRouteNameSuffix,
+		Path:            "/*filepath",
+		Method:          ahttp.MethodGet,
+		IsStatic:        true,
+		Dir:             dir,
+		DisableCompress: !cfg.BoolDefault("compress", domain.Compress),
+	}
+	return site, nil
+}
@@ -43,14 +43,26 @@ type tree struct {
 	root         *node
 }
 
-func (t *tree) lookup(p string) (r *Route, params ahttp.URLParams, rts bool) {
+func (t *tree) lookup(p string) (*Route, ahttp.URLParams, bool) {
+	var params ahttp.URLParams
+	r, rts := t.find(p, &params)
+	if r == nil || len(params) == 0 {
+		return r, nil, rts
+	}
+	return r, params, rts
+}
+
+// find method looks up the route for given path, path parameters are appended
+// into given params slice after resetting it; backing array is reused if it
+// has enough capacity.
+func (t *tree) find(p string, params *ahttp.URLParams) (r *Route, rts bool) {
 	s, l, sn, pn := strings.ToLower(p), len(p), t.root, t.root
 	ll := l
+	*params = (*params)[:0]
 walk:
 	for {
 		if sn == nil {
-			r, params = nil, nil
-			return
+			return nil, false
 		}
 		i := 0
 		if sn.typ == staticNode {
@@ -66,58 +78,35 @@ walk:
 					sn = pn.wnode
 					continue walk
 				}
-				r, params = nil, nil
-				return
+				return nil, false
 			}
 		} else if sn.typ == paramNode {
 			for i < ll && s[i] != slashByte {
 				i++
 			}
-			if params == nil {
-				params = make(ahttp.URLParams, 0, t.maxParams)
-			}
-			j := len(params)
-			params = params[:j+1]
-			params[j].Key = sn.arg
-			v, _ := url.PathUnescape(p[:i])
-			params[j].Value = v
+			t.addParam(params, sn.arg, p[:i])
 		} else if sn.typ == wildcardNode {
-			if params == nil {
-				params = make(ahttp.URLParams, 0, t.maxParams)
-			}
-			j := len(params)
-			params = params[:j+1]
-			params[j].Key = sn.arg
-			v, _ := url.PathUnescape(p[i:])
-			params[j].Value = v
-			r = sn.value
-			return
+			t.addParam(params, sn.arg, p[i:])
+			return sn.value, false
 		}
 		s, p = s[i:], p[i:]
 		ll = len(s)
 		if ll == 0 {
 			if (i < len(sn.label) || sn.value == nil) && t.tralingSlash {
 				if sn.label[len(sn.label)-1] == slashByte && sn.value != nil {
-					r, params, rts = nil, nil, true
+					rts = true
 				} else if sn = sn.findByIdx(slashByte); sn != nil && sn.value != nil {
-					r, params, rts = nil, nil, true
+					rts = true
 				} else if pn.value != nil {
-					r, params, rts = nil, nil, true
+					rts = true
 				}
-				return
+				return nil, rts
 			} else if sn.value != nil { // edge found
-				r = sn.value
-				return
+				return sn.value, false
 			}
-			params = nil
-			return
+			return nil, false
 		} else if ll == 1 && s == SlashString && len(sn.edges) == 0 {
-			if sn.value != nil {
-				r, params, rts = nil, nil, true
-				return
-			}
-			r, params = nil, nil
-			return
+			return nil, sn.value != nil
 		}
 
 		for _, e := range sn.edges {
@@ -132,6 +121,30 @@ walk:
 	}
 }
 
+// routes method returns all the routes of the tree.
+func (t *tree) routes() []*Route {
+	var routes []*Route
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.value != nil {
+			routes = append(routes, n.value)
+		}
+		for _, e := range n.edges {
+			walk(e)
+		}
+	}
+	walk(t.root)
+	return routes
+}
+
+func (t *tree) addParam(params *ahttp.URLParams, key, value string) {
+	if *params == nil {
+		*params = make(ahttp.URLParams, 0, t.maxParams)
+	}
+	v, _ := url.PathUnescape(value)
+	*params = append(*params, ahttp.URLParam{Key: key, Value: v})
+}
+
 func (t *tree) add(p string, r *Route) error {
 	fp := p
 	p = strings.ToLower(p)
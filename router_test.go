@@ -5,12 +5,14 @@
 package aah
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
 	"aahframe.work/ahttp"
+	"aahframe.work/log"
 	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
 )
@@ -119,3 +121,162 @@ func TestRouterCORS(t *testing.T) {
 	}
 	CORSMiddleware(ctx5, &Middleware{})
 }
+
+func TestRouterCORSStrictAndPrivateNetwork(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	newCORSCtx := func(method, origin string, hdrs map[string]string) *Context {
+		req := httptest.NewRequest(method, "http://localhost:8080/users/edit", nil)
+		req.Header.Set(ahttp.HeaderOrigin, origin)
+		for k, v := range hdrs {
+			req.Header.Set(k, v)
+		}
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		ctx.domain = &router.Domain{CORSEnabled: true}
+		ctx.route = &router.Route{CORS: (&router.CORS{}).
+			AddOrigins([]string{"http://sample.com"}).
+			AddAllowMethods([]string{ahttp.MethodGet, ahttp.MethodPost, ahttp.MethodOptions}).
+			SetAllowCredentials(true).
+			SetAllowPrivateNetwork(true).
+			SetStrict(true)}
+		return ctx
+	}
+
+	// allowed origin is echoed with credentials
+	ctx := newCORSCtx(ahttp.MethodGet, "http://sample.com", nil)
+	CORSMiddleware(ctx, &Middleware{})
+	assert.Equal(t, "http://sample.com", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowCredentials))
+	assert.Nil(t, ctx.Reply().err)
+
+	// strict mode rejects disallowed origin
+	ctx = newCORSCtx(ahttp.MethodGet, "http://example.com", nil)
+	CORSMiddleware(ctx, &Middleware{})
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, http.StatusForbidden, ctx.Reply().Code)
+	assert.Equal(t, router.ErrCORSOriginIsInvalid, ctx.Reply().err.Reason)
+
+	// non-strict mode omits the headers
+	ctx = newCORSCtx(ahttp.MethodGet, "http://example.com", nil)
+	ctx.route.CORS.SetStrict(false)
+	CORSMiddleware(ctx, &Middleware{})
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowOrigin))
+	assert.Nil(t, ctx.Reply().err)
+
+	// preflight with private network access
+	ctx = newCORSCtx(ahttp.MethodOptions, "http://sample.com", map[string]string{
+		ahttp.HeaderAccessControlRequestMethod:         ahttp.MethodPost,
+		ahttp.HeaderAccessControlRequestPrivateNetwork: "true",
+	})
+	CORSMiddleware(ctx, &Middleware{})
+	assert.Equal(t, "true", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowPrivateNetwork))
+	assert.Equal(t, http.StatusOK, ctx.Reply().Code)
+
+	// private network access not allowed
+	ctx = newCORSCtx(ahttp.MethodOptions, "http://sample.com", map[string]string{
+		ahttp.HeaderAccessControlRequestMethod:         ahttp.MethodPost,
+		ahttp.HeaderAccessControlRequestPrivateNetwork: "true",
+	})
+	ctx.route.CORS.SetAllowPrivateNetwork(false)
+	CORSMiddleware(ctx, &Middleware{})
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderAccessControlAllowPrivateNetwork))
+}
+
+func TestRouterAPIAllowedMethods(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+	ts.app.Config().SetString("type", "api")
+	ts.app.Config().SetString("api.documentation_url", "https://docs.example.com/api")
+
+	// OPTIONS
+	w := httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodOptions, "http://localhost:8080/get-xml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET, OPTIONS", w.Header().Get(ahttp.HeaderAllow))
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), w.Header().Get(ahttp.HeaderContentType))
+	assert.JSONEq(t, `{"allowed_methods":["GET","OPTIONS"],"documentation_url":"https://docs.example.com/api"}`, w.Body.String())
+
+	// 405 Method Not Allowed
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/binary-bytes", nil)
+	r.Header.Set(ahttp.HeaderAccept, ahttp.ContentTypeJSON.Mime)
+	ts.app.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, OPTIONS", w.Header().Get(ahttp.HeaderAllow))
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), w.Header().Get(ahttp.HeaderContentType))
+	assert.JSONEq(t, `{"code":405,"message":"Method Not Allowed","data":{"allowed_methods":["GET","OPTIONS"],`+
+		`"documentation_url":"https://docs.example.com/api"}}`, w.Body.String())
+
+	// documentation URL from OpenAPI spec path
+	ts.app.Config().SetString("api.documentation_url", "")
+	ts.app.Config().SetBool("openapi.enable", true)
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodOptions, "http://localhost:8080/get-xml", nil))
+	assert.JSONEq(t, `{"allowed_methods":["GET","OPTIONS"],"documentation_url":"/openapi.json"}`, w.Body.String())
+}
+
+func TestRouterLocalePrefixRedirect(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Router Locale Prefix]: %s", ts.URL)
+
+	domain := ts.app.Router().Lookup("localhost:8080")
+	domain.LocalePrefix = &router.LocalePrefix{RedirectRoot: true, Default: "en", Locales: []string{"en", "de"}}
+	defer func() { domain.LocalePrefix = nil }()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/?ref=home", nil)
+	r.Header.Set(ahttp.HeaderAcceptLanguage, "de-DE,de;q=0.9")
+	ts.app.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/de?ref=home", w.Header().Get(ahttp.HeaderLocation))
+	assert.Equal(t, ahttp.HeaderAcceptLanguage, w.Header().Get(ahttp.HeaderVary))
+
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/en", w.Header().Get(ahttp.HeaderLocation))
+
+	// route locale
+	ctx := ts.app.he.newContext()
+	ctx.Req = ahttp.AcquireRequest(httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/de/get-text.html", nil))
+	assert.Equal(t, "", ctx.RouteLocale())
+	ctx.route = &router.Route{Name: "text_get", Locale: "de"}
+	assert.Equal(t, "de", ctx.RouteLocale())
+	assert.Equal(t, "//localhost:8080/get-text.html", ctx.RouteURL("text_get"))
+}
+
+func TestRouterHeaderPolicy(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	err := ts.app.Router().RootDomain().AddRoute(&router.Route{
+		Name:   "text_noindex",
+		Path:   "/text-noindex.html",
+		Method: ahttp.MethodGet,
+		Target: "testSiteController",
+		Action: "Text",
+		Headers: &router.HeaderPolicy{
+			Set:    http.Header{"X-Robots-Tag": {"noindex"}},
+			Remove: []string{ahttp.HeaderServer},
+		},
+	})
+	assert.Nil(t, err)
+
+	req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/text-noindex.html", nil)
+	result := fireRequest(t, req)
+	assert.Equal(t, 200, result.StatusCode)
+	assert.Equal(t, "noindex", result.Header.Get("X-Robots-Tag"))
+	assert.Equal(t, "", result.Header.Get(ahttp.HeaderServer))
+
+	req, _ = http.NewRequest(ahttp.MethodGet, ts.URL+"/get-text.html", nil)
+	result = fireRequest(t, req)
+	assert.Equal(t, "", result.Header.Get("X-Robots-Tag"))
+}
@@ -0,0 +1,432 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/console"
+	"aahframe.work/essentials"
+	"aahframe.work/router"
+	"aahframe.work/security"
+)
+
+// Route issue kinds reported by `Application.CheckRoutes`.
+const (
+	RouteIssueUnreachable = "unreachable"
+	RouteIssueTarget      = "target"
+	RouteIssueAuth        = "auth"
+	RouteIssueReverseURL  = "reverse-url"
+)
+
+// routeCheckParamValue is used to compose the route path for reachability
+// check of path parameters.
+const routeCheckParamValue = "aah-routes-check"
+
+var (
+	tmplRouteURLRegex   = regexp.MustCompile(`\b(rurlm?)\s+[.$][^\s"]*\s+"([^"]+)"([^}|)]*)`)
+	tmplRouteTokenRegex = regexp.MustCompile(`"[^"]*"|\S+`)
+)
+
+// RouteIssue holds the single issue found by routes check.
+type RouteIssue struct {
+	Kind    string
+	Route   string
+	Message string
+}
+
+// routeListItem holds the route details of routes listing, JSON output is
+// meant for tooling.
+type routeListItem struct {
+	Domain      string `json:"domain"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Controller  string `json:"controller,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Static      string `json:"static,omitempty"`
+	Auth        string `json:"auth,omitempty"`
+	CORS        bool   `json:"cors"`
+	MaxBodySize int64  `json:"max_body_size"`
+}
+
+// String method is stringer interface implementation.
+func (ri *RouteIssue) String() string {
+	if len(ri.Route) == 0 {
+		return fmt.Sprintf("[%s] %s", ri.Kind, ri.Message)
+	}
+	return fmt.Sprintf("[%s] route '%s': %s", ri.Kind, ri.Route, ri.Message)
+}
+
+// CheckRoutes method loads the `routes.conf` without starting the server and
+// reports the issues found-
+//  - unreachable routes, for e.g.: conflicts with another route
+//  - controller or action is not found in the registry
+//  - auth scheme is referenced however not defined in `security.conf`
+//  - reverse URL route not found or path parameter mismatch in view templates
+//
+// Also available via app binary command `<app-binary> routes check`.
+func (a *Application) CheckRoutes() ([]*RouteIssue, error) {
+	var issues []*RouteIssue
+	rtr, err := router.NewWithApp(&routesCheckApp{Application: a},
+		path.Join(a.VirtualBaseDir(), "config", "routes.conf"))
+	if err != nil {
+		if rtr == nil {
+			return nil, err
+		}
+		issues = append(issues, routeConflictIssues(err)...)
+	}
+
+	for _, d := range rtr.Domains {
+		if len(d.DefaultAuth) > 0 && !a.isAuthSchemeDefined(d.DefaultAuth) {
+			issues = append(issues, &RouteIssue{Kind: RouteIssueAuth,
+				Message: fmt.Sprintf("domain '%s' default auth scheme '%s' is not defined in 'security.conf'",
+					d.Name, d.DefaultAuth)})
+		}
+		for _, route := range d.Routes() {
+			if route.IsFramework() {
+				continue
+			}
+			issues = append(issues, checkRouteReachable(d, route)...)
+			if route.IsStatic || route.Method == "WS" {
+				continue
+			}
+			if !route.IsProxy() {
+				issues = append(issues, a.checkRouteTarget(route)...)
+			}
+			if len(route.Auth) > 0 && !a.isAuthSchemeDefined(route.Auth) {
+				issues = append(issues, &RouteIssue{Kind: RouteIssueAuth, Route: route.Name,
+					Message: fmt.Sprintf("auth scheme '%s' is not defined in 'security.conf'", route.Auth)})
+			}
+		}
+	}
+
+	viewIssues, err := a.checkRouteURLsInViews(rtr)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, viewIssues...), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// routesCheckApp hides the security manager from router, so that auth
+// scheme references are reported by routes check instead of failing the
+// routes configuration load.
+type routesCheckApp struct {
+	*Application
+}
+
+func (routesCheckApp) SecurityManager() *security.Manager {
+	return nil
+}
+
+func (a *Application) cliCmdRoutes() console.Command {
+	return console.Command{
+		Name:  "routes",
+		Usage: "Provides utilities to inspect the app routes configuration",
+		Description: `Provides utilities to inspect the app routes configuration.
+	To know more about available 'routes' sub commands:
+		<app-binary> help routes
+
+	To know more about individual sub-commands details:
+		<app-binary> routes help list`,
+		Subcommands: []console.Command{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Usage:   "Lists the app routes per domain",
+				Description: `Lists the app routes per domain with HTTP method, path, route name,
+	controller/action, auth, CORS and max body size. Use JSON output for tooling.
+
+		Example:
+			<app-binary> routes list
+			<app-binary> routes list --host localhost --json`,
+				Flags: []console.Flag{
+					console.StringFlag{
+						Name:  "envprofile, e",
+						Value: "dev",
+						Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+					},
+					console.StringFlag{
+						Name:  "host",
+						Usage: "Domain name or host from 'routes.conf', default is all domains",
+					},
+					console.BoolFlag{
+						Name:  "json",
+						Usage: "Prints the routes in JSON format",
+					},
+				},
+				Action: func(c *console.Context) error {
+					if err := a.initRoutesCLI(cliEnvProfile(c)); err != nil {
+						return err
+					}
+					if err := a.initRouter(); err != nil {
+						return err
+					}
+					items := a.routeListItems(c.String("host"))
+					if c.Bool("json") {
+						b, err := json.MarshalIndent(items, "", "  ")
+						if err != nil {
+							return err
+						}
+						_, err = c.App.Writer.Write(append(b, '\n'))
+						return err
+					}
+					return writeRoutesTable(c.App.Writer, items)
+				},
+			},
+			{
+				Name:  "check",
+				Usage: "Checks the routes configuration for issues without starting the server",
+				Description: `Checks the routes configuration for issues without starting the server.
+	It reports unreachable routes, controllers/actions missing from registry,
+	auth schemes not defined in 'security.conf' and reverse URL route name or
+	parameter mismatches in view templates.
+
+		Example:
+			<app-binary> routes check --envprofile prod`,
+				Flags: []console.Flag{
+					console.StringFlag{
+						Name:  "envprofile, e",
+						Value: "dev",
+						Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+					},
+				},
+				Action: func(c *console.Context) error {
+					if err := a.initRoutesCLI(cliEnvProfile(c)); err != nil {
+						return err
+					}
+					issues, err := a.CheckRoutes()
+					if err != nil {
+						return err
+					}
+					for _, issue := range issues {
+						fmt.Fprintln(c.App.Writer, issue)
+					}
+					if len(issues) > 0 {
+						return fmt.Errorf("aah: routes check found %d issue(s)", len(issues))
+					}
+					fmt.Fprintln(c.App.Writer, "No issues found in the routes configuration")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func (a *Application) initRoutesCLI(envProfile string) error {
+	a.Config().SetString("env.active", envProfile)
+	if err := a.refreshSettings(); err != nil {
+		return err
+	}
+	if err := a.initLog(); err != nil {
+		return err
+	}
+	return a.initSecurity()
+}
+
+func (a *Application) routeListItems(host string) []*routeListItem {
+	items := make([]*routeListItem, 0)
+	_ = a.Router().Walk(func(d *router.Domain, route *router.Route) error {
+		if len(host) > 0 && !strings.EqualFold(host, d.Name) &&
+			!strings.EqualFold(host, d.Host) && !strings.EqualFold(host, d.Key) {
+			return nil
+		}
+		item := &routeListItem{
+			Domain:      d.Name,
+			Method:      route.Method,
+			Path:        route.Path,
+			Name:        route.Name,
+			Auth:        route.Auth,
+			CORS:        route.CORS != nil,
+			MaxBodySize: route.MaxBodySize,
+		}
+		if route.IsStatic {
+			item.Static = path.Join(route.Dir, route.File)
+		} else {
+			item.Controller, item.Action = route.Target, route.Action
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items
+}
+
+func writeRoutesTable(w io.Writer, items []*routeListItem) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var domain string
+	for i, item := range items {
+		if i == 0 || domain != item.Domain {
+			if i > 0 {
+				fmt.Fprintln(tw)
+			}
+			domain = item.Domain
+			fmt.Fprintf(tw, "Domain: %s\n", domain)
+			fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tCONTROLLER/ACTION\tAUTH\tCORS\tMAX BODY")
+		}
+		target := "static: " + item.Static
+		if len(item.Static) == 0 {
+			target = item.Controller + "." + item.Action
+		}
+		cors, maxBodySize := "-", "-"
+		if item.CORS {
+			cors = "yes"
+		}
+		if item.MaxBodySize > 0 {
+			maxBodySize = ess.BytesToStr(item.MaxBodySize)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", item.Method, item.Path, item.Name,
+			target, defaultStr(item.Auth, "-"), cors, maxBodySize)
+	}
+	return tw.Flush()
+}
+
+func defaultStr(v, d string) string {
+	if len(v) == 0 {
+		return d
+	}
+	return v
+}
+
+func (a *Application) isAuthSchemeDefined(name string) bool {
+	if name == "anonymous" || name == "authenticated" {
+		return true
+	}
+	return a.SecurityManager() != nil && a.SecurityManager().AuthScheme(name) != nil
+}
+
+func (a *Application) checkRouteTarget(route *router.Route) []*RouteIssue {
+	issues := a.checkTarget(route.Name, route.Target, route.Action)
+	if route.Canary != nil {
+		issues = append(issues, a.checkTarget(route.Name, route.Canary.Target, route.Canary.Action)...)
+	}
+	return issues
+}
+
+func (a *Application) checkTarget(routeName, targetName, actionName string) []*RouteIssue {
+	target := a.HTTPEngine().registry.Lookup(targetName)
+	if target == nil {
+		return []*RouteIssue{{Kind: RouteIssueTarget, Route: routeName,
+			Message: fmt.Sprintf("controller '%s' not found in the registry", targetName)}}
+	}
+	if target.Lookup(actionName) == nil {
+		return []*RouteIssue{{Kind: RouteIssueTarget, Route: routeName,
+			Message: fmt.Sprintf("action '%s' not found in controller '%s'", actionName, target.FqName)}}
+	}
+	return nil
+}
+
+// checkRouteURLsInViews method scans the view templates for template funcs
+// `rurl` and `rurlm`, then verifies the route name and path parameters count.
+func (a *Application) checkRouteURLsInViews(rtr *router.Router) ([]*RouteIssue, error) {
+	viewsDir := path.Join(a.VirtualBaseDir(), "views")
+	if !a.VFS().IsExists(viewsDir) {
+		return nil, nil
+	}
+
+	fileExt := a.Config().StringDefault("view.ext", defaultViewFileExt)
+	var issues []*RouteIssue
+	err := a.VFS().Walk(viewsDir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(fpath, fileExt) {
+			return nil
+		}
+		b, err := a.VFS().ReadFile(fpath)
+		if err != nil {
+			return err
+		}
+		fname := strings.TrimPrefix(fpath, a.VirtualBaseDir()+"/")
+		for _, m := range tmplRouteURLRegex.FindAllSubmatchIndex(b, -1) {
+			fn, routeName, args := string(b[m[2]:m[3]]), string(b[m[4]:m[5]]), string(b[m[6]:m[7]])
+			location := fmt.Sprintf("%s:%d", fname, bytes.Count(b[:m[0]], []byte("\n"))+1)
+			if issue := checkRouteURL(rtr, fn, routeName, args, location); issue != nil {
+				issues = append(issues, issue)
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func checkRouteURL(rtr *router.Router, fn, routeName, args, location string) *RouteIssue {
+	if routeName == "host" {
+		return nil
+	}
+	route := rtr.LookupRoute(routeName)
+	if route == nil {
+		return &RouteIssue{Kind: RouteIssueReverseURL, Route: routeName,
+			Message: fmt.Sprintf("'%s' route not found at %s", fn, location)}
+	}
+
+	// named args and argument expressions cannot be verified statically
+	if fn == "rurlm" || strings.Contains(args, "(") {
+		return nil
+	}
+	given := len(tmplRouteTokenRegex.FindAllString(args, -1))
+	if expected := routePathParamsCount(route.Path); expected != given {
+		return &RouteIssue{Kind: RouteIssueReverseURL, Route: routeName,
+			Message: fmt.Sprintf("'%s' expects %d path parameter(s), given %d at %s", fn, expected, given, location)}
+	}
+	return nil
+}
+
+// checkRouteReachable method composes the request path for the route and
+// verifies it is resolved to the same route.
+func checkRouteReachable(d *router.Domain, route *router.Route) []*RouteIssue {
+	segments := strings.Split(route.Path, "/")
+	for i, s := range segments {
+		if len(s) > 0 && (s[0] == ':' || s[0] == '*') {
+			segments[i] = routeCheckParamValue
+		}
+	}
+
+	var params ahttp.URLParams
+	if found := d.LookupFast(route.Method, strings.Join(segments, "/"), &params); found != route {
+		msg := fmt.Sprintf("path '%s' is not reachable for method '%s'", route.Path, route.Method)
+		if found != nil {
+			msg += fmt.Sprintf(", it resolves to route '%s'", found.Name)
+		}
+		return []*RouteIssue{{Kind: RouteIssueUnreachable, Route: route.Name, Message: msg}}
+	}
+	return nil
+}
+
+func routeConflictIssues(err error) []*RouteIssue {
+	errs, ok := err.(router.ConfigErrors)
+	if !ok {
+		errs = router.ConfigErrors{err}
+	}
+	issues := make([]*RouteIssue, 0, len(errs))
+	for _, e := range errs {
+		ce := e.(*router.RouteConflictError)
+		issues = append(issues, &RouteIssue{Kind: RouteIssueUnreachable, Route: ce.Route.Name,
+			Message: strings.TrimPrefix(ce.Error(), "router: route '"+ce.Route.Name+"' ")})
+	}
+	return issues
+}
+
+func routePathParamsCount(p string) int {
+	var cnt int
+	for _, s := range strings.Split(p, "/") {
+		if len(s) > 0 && (s[0] == ':' || s[0] == '*') {
+			cnt++
+		}
+	}
+	return cnt
+}
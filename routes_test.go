@@ -0,0 +1,209 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/ainsp"
+	"aahframe.work/console"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type routesCheckController struct {
+	*Context
+}
+
+func (c *routesCheckController) Index() {}
+
+func TestRoutesCheck(t *testing.T) {
+	a := newRoutesCheckApp(t, `
+domains {
+  localhost {
+    host = "localhost"
+    default_auth = "form_auth"
+    routes {
+      index {
+        path = "/"
+        controller = "routesCheckController"
+        auth = "anonymous"
+      }
+      about {
+        path = "/about"
+        controller = "routesCheckController"
+        action = "About"
+        auth = "anonymous"
+      }
+      user_show {
+        path = "/users/:id"
+        controller = "userController"
+        action = "Show"
+        auth = "basic_auth"
+      }
+      user_by_name {
+        path = "/users/:name"
+        controller = "routesCheckController"
+        action = "Index"
+        auth = "anonymous"
+      }
+    }
+  }
+}`, `<a href="{{ rurl . "index" }}">Home</a>
+<a href="{{ rurl $ "user_by_name" .Name | safeHTML }}">{{ .Name }}</a>
+<a href="{{ rurl . "user_by_name" }}">Broken</a>
+<a href="{{ rurlm . "missing" .Args }}">Missing</a>
+<a href="{{ rurl . "host" }}">Host</a>`)
+
+	issues, err := a.CheckRoutes()
+	assert.Nil(t, err)
+
+	var result []string
+	for _, issue := range issues {
+		result = append(result, issue.String())
+	}
+	assert.Equal(t, []string{
+		"[unreachable] route 'user_show': [GET /users/:id] at domains.localhost.routes.user_show (routes.conf:18) " +
+			"conflicts with route 'user_by_name' [GET /users/:name] at domains.localhost.routes.user_by_name (routes.conf:24): " +
+			"aah/router: parameter based edge already exists[/users/:name...] new[/users/:id...]",
+		"[auth] domain 'localhost' default auth scheme 'form_auth' is not defined in 'security.conf'",
+		"[target] route 'about': action 'About' not found in controller 'aahframe.work/routesCheckController'",
+		"[reverse-url] route 'user_by_name': 'rurl' expects 1 path parameter(s), given 0 at views/pages/site/index.html:3",
+		"[reverse-url] route 'missing': 'rurlm' route not found at views/pages/site/index.html:4",
+	}, result)
+
+	// CLI
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdRoutes()}
+	err = cliApp.Run([]string{"app", "routes", "check"})
+	assert.Equal(t, "aah: routes check found 5 issue(s)", err.Error())
+	assert.Contains(t, buf.String(), "[target] route 'about'")
+}
+
+func TestRoutesCheckNoIssues(t *testing.T) {
+	a := newRoutesCheckApp(t, `
+domains {
+  localhost {
+    host = "localhost"
+    routes {
+      index {
+        path = "/"
+        controller = "routesCheckController"
+      }
+      user_by_name {
+        path = "/users/:name"
+        controller = "routesCheckController"
+        action = "Index"
+      }
+      missing {
+        path = "/missing"
+        controller = "routesCheckController"
+        action = "Index"
+      }
+    }
+  }
+}`, `<a href="{{ rurl . "user_by_name" (index .Names 0) }}">User</a>
+<a href="{{ rurlm . "missing" .Args }}">Missing</a>`)
+
+	issues, err := a.CheckRoutes()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(issues), "%v", issues)
+}
+
+func TestRoutesList(t *testing.T) {
+	a := newRoutesCheckApp(t, `
+domains {
+  localhost {
+    host = "localhost"
+    static {
+      favicon {
+        path = "/favicon.ico"
+        base_dir = "static"
+        file = "img/favicon.ico"
+      }
+    }
+    routes {
+      index {
+        path = "/"
+        controller = "routesCheckController"
+        auth = "anonymous"
+      }
+      user_by_name {
+        path = "/users/:name"
+        controller = "routesCheckController"
+        method = "POST"
+        action = "Index"
+        max_body_size = "1mb"
+      }
+    }
+  }
+}`, "")
+
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdRoutes()}
+	err := cliApp.Run([]string{"app", "routes", "list"})
+	assert.Nil(t, err)
+	assert.Equal(t, `Domain: localhost
+METHOD  PATH          NAME          CONTROLLER/ACTION               AUTH       CORS  MAX BODY
+GET     /             index         routesCheckController.Index     anonymous  -     -
+GET     /favicon.ico  favicon       static: static/img/favicon.ico  -          -     -
+POST    /users/:name  user_by_name  routesCheckController.Index     -          -     1024KB
+`, buf.String())
+
+	buf.Reset()
+	err = cliApp.Run([]string{"app", "routes", "list", "--host", "localhost", "--json"})
+	assert.Nil(t, err)
+	var items []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &items))
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, "user_by_name", items[2]["name"])
+	assert.Equal(t, float64(1048576), items[2]["max_body_size"])
+	assert.Equal(t, "static/img/favicon.ico", items[1]["static"])
+
+	buf.Reset()
+	err = cliApp.Run([]string{"app", "routes", "list", "--host", "example.com", "--json"})
+	assert.Nil(t, err)
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func newRoutesCheckApp(t *testing.T, routesConf, view string) *Application {
+	dir, err := ioutil.TempDir("", "routes_check")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	files := map[string]string{
+		"config/aah.conf": `name = "routescheck"
+env {
+  dev {
+  }
+}`,
+		"config/routes.conf":          routesConf,
+		"views/pages/site/index.html": view,
+	}
+	for name, content := range files {
+		fpath := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(fpath), 0755))
+		assert.Nil(t, ioutil.WriteFile(fpath, []byte(content), 0644))
+	}
+
+	a := newApp()
+	assert.Nil(t, a.VFS().AddMount(a.VirtualBaseDir(), dir))
+	a.settings.ImportPath = dir
+	assert.Nil(t, a.initPath())
+	assert.Nil(t, a.initConfig())
+	assert.Nil(t, a.initRoutesCLI("dev"))
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.AddController((*routesCheckController)(nil), []*ainsp.Method{{Name: "Index"}})
+	return a
+}
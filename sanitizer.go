@@ -0,0 +1,84 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"html/template"
+
+	"aahframe.work/config"
+	"aahframe.work/valpar"
+)
+
+const defaultSanitizerPolicy = "strict"
+
+// sanitizerPolicies holds the sanitizer policies by name, configured via
+// `security.sanitizer.policy`. Policy `allowlist` gets registered by
+// build tag `bluemonday` (refer to `sanitizer_bluemonday.go`).
+var sanitizerPolicies = map[string]func(cfg *config.Config) (Sanitizer, error){
+	"strict": func(_ *config.Config) (Sanitizer, error) {
+		return SanitizerFunc(template.HTMLEscapeString), nil
+	},
+}
+
+// Sanitizer interface is used to sanitize the user supplied string values
+// to prevent XSS attacks. aah applies sanitizer consistently on request
+// parameters binding of type string, view template funcs (`qparam`, `fparam`,
+// `pparam`, `config`, `i18n`, `session`) and flash messages (`flash`).
+type Sanitizer interface {
+	Sanitize(value string) string
+}
+
+// SanitizerFunc type is an adapter to allow the use of ordinary func as
+// `Sanitizer`.
+type SanitizerFunc func(value string) string
+
+// Sanitize method calls f(value).
+func (f SanitizerFunc) Sanitize(value string) string {
+	return f(value)
+}
+
+// SetSanitizer method sets the given sanitizer into aah framework, it takes
+// priority over the configured `security.sanitizer.policy`.
+func (a *Application) SetSanitizer(s Sanitizer) {
+	if s == nil {
+		return
+	}
+	a.sanitizer = s
+	valpar.Sanitize = s.Sanitize
+}
+
+// Sanitizer method returns the current sanitizer of aah application.
+func (a *Application) Sanitizer() Sanitizer {
+	return a.sanitizer
+}
+
+func (a *Application) initSanitizer() error {
+	if a.sanitizer != nil { // user supplied sanitizer
+		valpar.Sanitize = a.sanitizer.Sanitize
+		return nil
+	}
+
+	name := a.Config().StringDefault("security.sanitizer.policy", defaultSanitizerPolicy)
+	policyFn, found := sanitizerPolicies[name]
+	if !found {
+		return fmt.Errorf("aah: sanitizer policy '%s' not found", name)
+	}
+	s, err := policyFn(a.Config())
+	if err != nil {
+		return err
+	}
+	a.SetSanitizer(s)
+	return nil
+}
+
+// sanitizeValue method sanitizes the value of type `string`, rest we can't do
+// any. It's a user responsibility.
+func (a *Application) sanitizeValue(value interface{}) interface{} {
+	if v, ok := value.(string); ok && a.sanitizer != nil {
+		return a.sanitizer.Sanitize(v)
+	}
+	return value
+}
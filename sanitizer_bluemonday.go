@@ -0,0 +1,41 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build bluemonday
+
+package aah
+
+import (
+	"aahframe.work/config"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Sanitizer policies of `github.com/microcosm-cc/bluemonday`, it is included
+// in the build with tag `bluemonday`, for e.g.:
+// 	go build -tags bluemonday
+//
+// Add the dependency into application `go.mod` before enabling it.
+func init() {
+	// Allows only configured HTML elements, for e.g.:
+	// 	security.sanitizer {
+	// 	  policy = "allowlist"
+	// 	  allowed_tags = ["b", "i", "em", "strong", "a", "p"]
+	// 	}
+	sanitizerPolicies["allowlist"] = func(cfg *config.Config) (Sanitizer, error) {
+		p := bluemonday.NewPolicy()
+		if tags, found := cfg.StringList("security.sanitizer.allowed_tags"); found {
+			p.AllowElements(tags...)
+		}
+		if cfg.BoolDefault("security.sanitizer.allow_links", false) {
+			p.AllowStandardURLs()
+			p.AllowAttrs("href").OnElements("a")
+		}
+		return SanitizerFunc(p.Sanitize), nil
+	}
+
+	// Allows HTML elements of user generated content.
+	sanitizerPolicies["ugc"] = func(_ *config.Config) (Sanitizer, error) {
+		return SanitizerFunc(bluemonday.UGCPolicy().Sanitize), nil
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"html/template"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/valpar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizer(t *testing.T) {
+	defer func() { valpar.Sanitize = template.HTMLEscapeString }()
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	// default policy
+	assert.NotNil(t, a.Sanitizer())
+	assert.Equal(t, "&lt;b&gt;aah&lt;/b&gt;", a.Sanitizer().Sanitize("<b>aah</b>"))
+	assert.Equal(t, 10, a.sanitizeValue(10))
+
+	// custom sanitizer
+	a.SetSanitizer(SanitizerFunc(func(value string) string {
+		return strings.NewReplacer("<b>", "", "</b>", "").Replace(value)
+	}))
+	a.SetSanitizer(nil) // ignored
+	assert.Equal(t, "aah", a.sanitizeValue("<b>aah</b>"))
+
+	// binding
+	parser, _ := valpar.ValueParser(reflect.TypeOf(""))
+	v, err := parser("name", reflect.TypeOf(""), map[string][]string{"name": {"<b>jeeva</b>"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva", v.String())
+
+	// view funcs
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/?q=<b>search</b>", nil)
+	viewArgs := map[string]interface{}{KeyViewArgRequest: ahttp.AcquireRequest(req)}
+	assert.Equal(t, "search", a.viewMgr.tmplQueryParam(viewArgs, "q"))
+
+	// user supplied sanitizer is retained on init
+	assert.Nil(t, a.initSanitizer())
+	assert.Equal(t, "aah", a.sanitizeValue("<b>aah</b>"))
+
+	// unknown policy
+	a.sanitizer = nil
+	cfg, err := config.ParseString(`security {
+  sanitizer {
+    policy = "unknown"
+  }
+}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	assert.Equal(t, "aah: sanitizer policy 'unknown' not found", a.initSanitizer().Error())
+}
@@ -5,18 +5,26 @@
 package aah
 
 import (
+	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"aahframe.work/ahttp"
 	ess "aahframe.work/essentials"
 	"aahframe.work/internal/util"
 	"aahframe.work/security"
+	"aahframe.work/security/acrypto"
 	"aahframe.work/security/anticsrf"
 	"aahframe.work/security/authc"
 	"aahframe.work/security/authz"
 	"aahframe.work/security/scheme"
+	"aahframe.work/valpar"
+	"gopkg.in/go-playground/validator.v9"
 )
 
 const (
@@ -29,9 +37,14 @@ const (
 	// KeyOAuth2Token key name is used to store OAuth2 Access Token into `aah.Context`.
 	KeyOAuth2Token = "_aahOAuth2Token"
 
-	keyAntiCSRF       = "_aahAntiCSRF"
-	keyOAuth2StateKey = "_aahOAuth2State"
-	keyAuthScheme     = "_aahAuthScheme"
+	// KeyWebAuthnCredential key name is used to store verified WebAuthn
+	// credential into `aah.Context`.
+	KeyWebAuthnCredential = "_aahWebAuthnCredential"
+
+	keyAntiCSRF         = "_aahAntiCSRF"
+	keyOAuth2StateKey   = "_aahOAuth2State"
+	keyWebAuthnStateKey = "_aahWebAuthnState"
+	keyAuthScheme       = "_aahAuthScheme"
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -39,6 +52,7 @@ const (
 //______________________________________________________________________________
 
 func (a *Application) initSecurity() error {
+	a.resolveBreachedList()
 	asecmgr := security.New()
 	asecmgr.IsSSLEnabled = a.IsSSLEnabled()
 	if err := asecmgr.Init(a.Config()); err != nil {
@@ -47,7 +61,40 @@ func (a *Application) initSecurity() error {
 
 	a.securityMgr = asecmgr
 	a.settings.AuthSchemeExists = len(a.securityMgr.AuthSchemes()) > 0
-	return nil
+	if err := a.initThrottle(); err != nil {
+		return err
+	}
+	if err := a.initRateLimiter(); err != nil {
+		return err
+	}
+	if err := a.initQuota(); err != nil {
+		return err
+	}
+	if err := a.initWebhookProviders(); err != nil {
+		return err
+	}
+	if err := a.initAuthServer(); err != nil {
+		return err
+	}
+
+	// Password policy constraint, for e.g.: `validate:"required,password_policy"`
+	if err := valpar.Validator().RegisterValidation("password_policy", validatePasswordPolicy); err != nil {
+		return err
+	}
+	return a.initCookies()
+}
+
+// resolveBreachedList method resolves the relative path of breached passwords
+// list from application base directory.
+func (a *Application) resolveBreachedList() {
+	key := "security.password_policy.breached_list"
+	if file := a.Config().StringDefault(key, ""); len(file) > 0 && !filepath.IsAbs(file) {
+		a.Config().SetString(key, filepath.Join(a.BaseDir(), file))
+	}
+}
+
+func validatePasswordPolicy(fl validator.FieldLevel) bool {
+	return acrypto.ValidatePassword(fl.Field().String()) == nil
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -60,15 +107,21 @@ func AuthcAuthzMiddleware(ctx *Context, m *Middleware) {
 	// 		- Auth scheme is not defined in `security.conf`
 	// 		- Route auth is `anonymous`
 	if !ctx.a.settings.AuthSchemeExists || ctx.route.Auth == "anonymous" {
-		m.Next(ctx)
+		if ctx.a.rateLimiter.allowed(ctx) {
+			m.Next(ctx)
+		}
 		return
 	}
 
+	sw := ctx.startTiming(TimingAuth)
+	defer sw.Stop()
+
 	// If session is authenticated then populate subject and continue the request flow.
 	if ctx.Subject().IsAuthenticated() {
 		if key := ctx.Session().GetString(keyAuthScheme); key != "" {
 			populateAuthorizationInfo(ctx.a.SecurityManager().AuthScheme(key), ctx)
-			if hasAccess(ctx) == flowCont {
+			if hasAccess(ctx) == flowCont && ctx.a.rateLimiter.allowed(ctx) && ctx.a.quota.allowed(ctx) {
+				sw.Stop()
 				m.Next(ctx)
 			}
 			return
@@ -79,26 +132,36 @@ func AuthcAuthzMiddleware(ctx *Context, m *Middleware) {
 		return
 	}
 
-	// Supports one or more auth scheme on route
+	// Supports one or more auth scheme on route -
+	// 	- `a,b` comma separated schemes
+	// 	- `a?b` fallthrough chain, see `doAuthChain`
 	var result flowResult
-	for _, s := range strings.Split(ctx.route.Auth, ",") {
-		authScheme := ctx.a.SecurityManager().AuthScheme(strings.TrimSpace(s))
-		ctx.Log().Debugf("Processing route auth scheme: %s", authScheme.Key())
-		switch authScheme.Scheme() {
-		case "form":
-			result = doFormAuth(authScheme, ctx)
-		case "oauth2":
-			result = doOAuth2(authScheme, ctx)
-		default:
-			result = doAuthScheme(authScheme, ctx)
-		}
+	if strings.Contains(ctx.route.Auth, "?") {
+		result = doAuthChain(ctx)
+	} else {
+		for _, s := range strings.Split(ctx.route.Auth, ",") {
+			authScheme := ctx.a.SecurityManager().AuthScheme(strings.TrimSpace(s))
+			ctx.moduleLog("security").Debugf("Processing route auth scheme: %s", authScheme.Key())
+			switch authScheme.Scheme() {
+			case "form":
+				result = doFormAuth(authScheme, ctx)
+			case "oauth2":
+				result = doOAuth2(authScheme, ctx)
+			case "webauthn":
+				result = doWebAuthn(authScheme, ctx)
+			default:
+				result = doAuthScheme(authScheme, ctx)
+			}
 
-		if result == flowCont {
-			break
+			if result == flowCont {
+				break
+			}
 		}
 	}
 
-	if result == flowCont && hasAccess(ctx) == flowCont {
+	if result == flowCont && hasAccess(ctx) == flowCont &&
+		ctx.a.rateLimiter.allowed(ctx) && ctx.a.quota.allowed(ctx) {
+		sw.Stop()
 		m.Next(ctx)
 	}
 }
@@ -133,7 +196,7 @@ func doFormAuth(authScheme scheme.Schemer, ctx *Context) flowResult {
 	if formAuth.IsAlwaysToDefaultTarget || len(rt) == 0 {
 		ctx.Reply().Redirect(formAuth.DefaultTargetURL)
 	} else {
-		ctx.Log().Debugf("Redirecting to URL found in param '_rt': %s", rt)
+		ctx.moduleLog("security").Debugf("Redirecting to URL found in param '_rt': %s", rt)
 		ctx.Reply().Redirect(rt)
 	}
 
@@ -162,16 +225,16 @@ func doOAuth2(authScheme scheme.Schemer, ctx *Context) flowResult {
 		defer ctx.Session().Del(keyOAuth2StateKey)
 
 		// Validate OAuth2 callback
-		ctx.Log().Debug(ctx.Req.URL().String())
+		ctx.moduleLog("security").Debug(ctx.Req.URL().String())
 		token, err := oauth.ValidateCallback(ctx.Session().GetString(keyOAuth2StateKey), ctx.Req)
 		if err != nil {
-			ctx.Log().Error(err)
+			ctx.moduleLog("security").Error(err)
 			ctx.Reply().Unauthorized().Error(newError(err, http.StatusUnauthorized))
 			return flowAbort
 		}
 
 		// Set successful access token into aah.Context
-		ctx.Log().Info("oauth2: Token obtained from provider")
+		ctx.moduleLog("security").Info("oauth2: Token obtained from provider")
 		ctx.Set(KeyOAuth2Token, token)
 
 		if doAuthentication(authScheme, ctx) == flowAbort {
@@ -189,7 +252,100 @@ func doOAuth2(authScheme scheme.Schemer, ctx *Context) flowResult {
 	}
 
 	// typically it should not reach here
-	ctx.Log().Trace("OAuth2 flow; typically it should not reach here")
+	ctx.moduleLog("security").Trace("OAuth2 flow; typically it should not reach here")
+	return flowAbort
+}
+
+// doWebAuthn method does WebAuthn (passkey) login ceremony. Login begin
+// replies the credential request options and keeps the challenge in the
+// session, login finish verifies the assertion and authenticates the Subject.
+// Optional form value `username` on login begin lists the user credentials
+// otherwise browser discovers the passkey.
+func doWebAuthn(authScheme scheme.Schemer, ctx *Context) flowResult {
+	ctx.e.publishOnPreAuthEvent(ctx)
+	wa := authScheme.(*scheme.WebAuthn)
+
+	// WebAuthn login begin
+	if ctx.Req.Path == wa.LoginBeginURL {
+		opts, state, err := wa.LoginOptions(ctx.Req.FormValue("username"))
+		if err != nil {
+			ctx.moduleLog("security").Errorf("%s: %v", authScheme.Key(), err)
+			ctx.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+			return flowAbort
+		}
+		ctx.Session().Set(keyWebAuthnStateKey, state)
+		ctx.Reply().Header(ahttp.HeaderCacheControl, "no-store").JSON(opts)
+		return flowAbort
+	}
+
+	// WebAuthn login finish
+	if ctx.Req.Path == wa.LoginFinishURL {
+		defer ctx.Session().Del(keyWebAuthnStateKey)
+
+		body, err := ioutil.ReadAll(ctx.Req.Body())
+		if err == nil {
+			var cred *scheme.WebAuthnCredential
+			if cred, err = wa.FinishLogin(ctx.Session().GetString(keyWebAuthnStateKey), body); err == nil {
+				ctx.Set(KeyWebAuthnCredential, cred)
+			}
+		}
+		if err != nil {
+			ctx.moduleLog("security").Infof("%s: Authentication is failed: %v", authScheme.Key(), err)
+			ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
+			return flowAbort
+		}
+
+		if doAuthentication(authScheme, ctx) == flowAbort {
+			return flowAbort
+		}
+
+		populateAuthorizationInfo(authScheme, ctx)
+		debugLogSubjectInfo(ctx)
+
+		ctx.e.publishOnPostAuthEvent(ctx)
+
+		// Ceremony is driven by script, reply the success URL
+		ctx.Reply().JSON(Data{"redirect": wa.SuccessURL})
+		return flowAbort
+	}
+
+	// typically it should not reach here
+	ctx.moduleLog("security").Trace("WebAuthn flow; typically it should not reach here")
+	return flowAbort
+}
+
+// doAuthChain method processes the route auth fallthrough chain `a?b` in the
+// defined order, first scheme authenticates the request wins. Scheme fails
+// with `401 Unauthorized` falls through to the next one and its challenge
+// header `WWW-Authenticate` is collected, all the challenges are sent together
+// if every scheme fails. Any other failure (e.g. throttle, service
+// unavailable) stops the chain.
+func doAuthChain(ctx *Context) flowResult {
+	var challenges []string
+	for _, s := range strings.Split(ctx.route.Auth, "?") {
+		authScheme := ctx.a.SecurityManager().AuthScheme(strings.TrimSpace(s))
+		ctx.moduleLog("security").Debugf("Processing route auth chain scheme: %s", authScheme.Key())
+		if doAuthScheme(authScheme, ctx) == flowCont {
+			ctx.moduleLog("security").Infof("Request authenticated by auth scheme: %s", authScheme.Key())
+			return flowCont
+		}
+
+		if ctx.Reply().Code != http.StatusUnauthorized {
+			return flowAbort
+		}
+
+		hdr := ctx.Res.Header()
+		challenges = append(challenges, hdr[ahttp.HeaderWWWAuthenticate]...)
+		hdr.Del(ahttp.HeaderWWWAuthenticate)
+		ctx.Reply().Ok().Error(nil)
+		ctx.moduleLog("security").Debugf("%s: Authentication is failed, falls through to next auth scheme", authScheme.Key())
+	}
+
+	ctx.moduleLog("security").Infof("Route auth chain '%s' is failed", ctx.route.Auth)
+	for _, c := range challenges {
+		ctx.Reply().HeaderAppend(ahttp.HeaderWWWAuthenticate, c)
+	}
+	ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
 	return flowAbort
 }
 
@@ -222,49 +378,93 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 		// Call Subject principals provider
 		principals, err := c.Principal(authScheme.Key(), ctx)
 		if err != nil {
-			ctx.Log().Error(ErrUnableToGetPrincipal)
+			ctx.moduleLog("security").Error(ErrUnableToGetPrincipal)
 			ctx.Reply().Unauthorized().Error(newError(ErrUnableToGetPrincipal, http.StatusUnauthorized))
 			return flowAbort
 		}
 
-		ctx.Log().Debugf("%s: Subject principals obtained", authScheme.Key())
+		ctx.moduleLog("security").Debugf("%s: Subject principals obtained", authScheme.Key())
 		authcInfo = authc.NewAuthenticationInfo()
 		authcInfo.Principals = append(authcInfo.Principals, principals...)
 	} else {
 		// Call Authentication Info provider
+		authcToken := authScheme.ExtractAuthenticationToken(ctx.Req)
+
+		// Request without credentials is not a login attempt, for e.g.:
+		// first Basic auth request gets the challenge
+		throttled := ctx.a.throttle.isApplicable(authScheme) &&
+			authcToken != nil && len(authcToken.Identity) > 0
+		if throttled {
+			if d := ctx.a.throttle.blocked(ctx, authcToken.Identity); d > 0 {
+				return throttleBlocked(authScheme, ctx, d)
+			}
+		}
+
 		var err error
-		authcInfo, err = authScheme.DoAuthenticate(authScheme.ExtractAuthenticationToken(ctx.Req))
+		authcInfo, err = authScheme.DoAuthenticate(authcToken)
 		if err != nil || authcInfo == nil {
+			if throttled {
+				ctx.a.throttle.failed(ctx, authcToken.Identity)
+			}
 			switch sa := authScheme.(type) {
 			case *scheme.FormAuth:
-				ctx.Log().Infof("%s: Authentication is failed, sending to login failure URL", authScheme.Key())
+				ctx.moduleLog("security").Infof("%s: Authentication is failed, sending to login failure URL", authScheme.Key())
 				ctx.Reply().Redirect(util.AddQueryString(sa.LoginFailureURL, "_rt", ctx.Req.FormValue("_rt")))
 			case *scheme.BasicAuth:
-				ctx.Log().Infof("%s: Authentication is failed", authScheme.Key())
+				ctx.moduleLog("security").Infof("%s: Authentication is failed", authScheme.Key())
 				ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, `Basic realm="`+sa.RealmName+`"`)
 				ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
 			case *scheme.GenericAuth:
 				switch err {
 				case authc.ErrAuthenticationFailed, authc.ErrAuthenticatorIsNil, authc.ErrPrincipalIsNil, authc.ErrSubjectNotExists:
-					ctx.Log().Infof("%s: Authentication is failed", authScheme.Key())
+					ctx.moduleLog("security").Infof("%s: Authentication is failed", authScheme.Key())
+					if len(sa.Challenge) > 0 {
+						ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, sa.Challenge)
+					}
 					ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
 				case authc.ErrInternalServerError:
-					ctx.Log().Errorf("%s: Internal Server Error", authScheme.Key())
+					ctx.moduleLog("security").Errorf("%s: Internal Server Error", authScheme.Key())
 					ctx.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
 				case authc.ErrServiceUnavailable:
-					ctx.Log().Errorf("%s: Service Unavailable", authScheme.Key())
+					ctx.moduleLog("security").Errorf("%s: Service Unavailable", authScheme.Key())
 					ctx.Reply().ServiceUnavailable().Error(newError(err, http.StatusServiceUnavailable))
 				}
+			case *scheme.APIKeyAuth:
+				switch e := err.(type) {
+				case *scheme.RateLimitError:
+					ctx.moduleLog("security").Warnf("%s: %v", authScheme.Key(), e)
+					ctx.Reply().Header(ahttp.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(e.RetryAfter.Seconds())), 10))
+					ctx.Reply().Status(http.StatusTooManyRequests).Error(newError(ErrAuthenticationThrottled, http.StatusTooManyRequests))
+				default:
+					switch err {
+					case authc.ErrAuthenticationFailed, authc.ErrAuthenticatorIsNil, authc.ErrSubjectNotExists:
+						ctx.moduleLog("security").Infof("%s: Authentication is failed", authScheme.Key())
+						ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, `APIKey header="`+sa.HeaderName+`"`)
+						ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
+					case authc.ErrServiceUnavailable:
+						ctx.moduleLog("security").Errorf("%s: Service Unavailable", authScheme.Key())
+						ctx.Reply().ServiceUnavailable().Error(newError(err, http.StatusServiceUnavailable))
+					default:
+						ctx.moduleLog("security").Errorf("%s: Internal Server Error", authScheme.Key())
+						ctx.Reply().InternalServerError().Error(newError(authc.ErrInternalServerError, http.StatusInternalServerError))
+					}
+				}
 			}
 
 			return flowAbort
 		}
+		if throttled {
+			ctx.a.throttle.succeeded(ctx, authcToken.Identity)
+		}
 	}
 
 	populateAuthenticationInfo(authcInfo, ctx)
+	if registerSession(authScheme, ctx) == flowAbort {
+		return flowAbort
+	}
 	ctx.Session().IsAuthenticated = true
 	ctx.Session().Set(keyAuthScheme, authScheme.Key())
-	ctx.Log().Infof("%s: Authentication successful", authScheme.Key())
+	ctx.moduleLog("security").Infof("%s: Authentication successful", authScheme.Key())
 
 	// Add to session its stateful
 	if ctx.a.SessionManager().IsStateful() {
@@ -273,13 +473,48 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 
 	if ctx.a.ViewEngine() != nil {
 		// Change the Anti-CSRF token in use for a request after login for security purposes.
-		ctx.Log().Info("Change Anti-CSRF secret after successful authentication for security purpose")
+		ctx.moduleLog("security").Info("Change Anti-CSRF secret after successful authentication for security purpose")
 		ctx.AddViewArg(keyAntiCSRF, ctx.a.SecurityManager().AntiCSRF.GenerateSecret())
 	}
 
 	return flowCont
 }
 
+// throttleBlocked method replies the login attempt which is delayed or locked
+// out by login throttle.
+func throttleBlocked(authScheme scheme.Schemer, ctx *Context, d time.Duration) flowResult {
+	ctx.moduleLog("security").Warnf("%s: Login attempt is throttled, retry after %s", authScheme.Key(), d)
+	if sa, ok := authScheme.(*scheme.FormAuth); ok {
+		ctx.Reply().Redirect(util.AddQueryString(sa.LoginFailureURL, "_rt", ctx.Req.FormValue("_rt")))
+		return flowAbort
+	}
+	ctx.Reply().Header(ahttp.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+	ctx.Reply().Status(http.StatusTooManyRequests).Error(newError(ErrAuthenticationThrottled, http.StatusTooManyRequests))
+	return flowAbort
+}
+
+// registerSession method registers the authenticated session into session
+// registry, concurrent login policy is applied per `security.session.max_concurrent`.
+func registerSession(authScheme scheme.Schemer, ctx *Context) flowResult {
+	sm := ctx.a.SessionManager()
+	if !sm.IsRegistryEnabled() {
+		return flowCont
+	}
+
+	err := sm.Register(ctx.Subject().PrimaryPrincipal().Value, ctx.Session())
+	if err == nil {
+		return flowCont
+	}
+
+	ctx.moduleLog("security").Warnf("%s: %v", authScheme.Key(), err)
+	if sa, ok := authScheme.(*scheme.FormAuth); ok {
+		ctx.Reply().Redirect(util.AddQueryString(sa.LoginFailureURL, "_rt", ctx.Req.FormValue("_rt")))
+	} else {
+		ctx.Reply().Forbidden().Error(newError(err, http.StatusForbidden))
+	}
+	return flowAbort
+}
+
 func populateAuthenticationInfo(authcInfo *authc.AuthenticationInfo, ctx *Context) {
 	ctx.Subject().AuthenticationInfo = authcInfo
 	ctx.logger = ctx.Log().WithField("principal", ctx.Subject().PrimaryPrincipal().Value)
@@ -287,7 +522,22 @@ func populateAuthenticationInfo(authcInfo *authc.AuthenticationInfo, ctx *Contex
 }
 
 func populateAuthorizationInfo(authScheme scheme.Schemer, ctx *Context) {
-	ctx.Subject().AuthorizationInfo = authScheme.DoAuthorizationInfo(ctx.Subject().AuthenticationInfo)
+	authcInfo := ctx.Subject().AuthenticationInfo
+	if ctx.Subject().IsRunAs() {
+		authcInfo = &authc.AuthenticationInfo{Principals: ctx.Subject().RunAsPrincipals()}
+	}
+	ctx.Subject().AuthorizationInfo = authScheme.DoAuthorizationInfo(authcInfo)
+}
+
+func (ctx *Context) publishRunAsEvent(released bool) {
+	sub := ctx.Subject()
+	name, action := EventOnRunAs, "started"
+	if released {
+		name, action = EventOnReleaseRunAs, "released"
+	}
+	ctx.moduleLog("security").Infof("Subject '%s' %s run as '%s'", sub.RealPrincipal().Value, action,
+		(&authc.AuthenticationInfo{Principals: sub.RunAsPrincipals()}).PrimaryPrincipal())
+	ctx.a.eventStore.PublishSync(&Event{Name: name, Data: ctx})
 }
 
 func hasAccess(ctx *Context) flowResult {
@@ -296,14 +546,14 @@ func hasAccess(ctx *Context) flowResult {
 		return flowCont
 	}
 
-	ctx.Log().Warnf("Authorization failed:%s", reason2String(reasons))
+	ctx.moduleLog("security").Warnf("Authorization failed:%s", reason2String(reasons))
 	ctx.Reply().Forbidden().Error(newErrorWithData(ErrAuthorizationFailed, http.StatusForbidden, reasons))
 	return flowAbort
 }
 
 func debugLogSubjectInfo(ctx *Context) {
-	ctx.Log().Debug(ctx.Subject().AuthenticationInfo)
-	ctx.Log().Debug(ctx.Subject().AuthorizationInfo)
+	ctx.moduleLog("security").Debug(ctx.Subject().AuthenticationInfo)
+	ctx.moduleLog("security").Debug(ctx.Subject().AuthorizationInfo)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -316,12 +566,18 @@ func AntiCSRFMiddleware(ctx *Context, m *Middleware) {
 	ac := ctx.a.SecurityManager().AntiCSRF
 	// If Anti-CSRF is not enabled, move on.
 	// It is highly recommended to enable it for web application.
-	if !ac.Enabled || !ctx.route.IsAntiCSRFCheck || ctx.a.ViewEngine() == nil {
+	if !ac.Enabled || !ctx.route.IsAntiCSRFCheck || (!ac.IsDoubleSubmit() && ctx.a.ViewEngine() == nil) {
 		ac.ClearCookie(ctx.Res, ctx.Req)
 		m.Next(ctx)
 		return
 	}
 
+	// Double submit cookie pattern for SPAs, token does not depend on views
+	if ac.IsDoubleSubmit() {
+		antiCSRFDoubleSubmit(ctx, m, ac)
+		return
+	}
+
 	// Get cipher secret from anti-csrf cookie
 	secret := ac.CipherSecret(ctx.Req)
 	ctx.AddViewArg(keyAntiCSRF, secret)
@@ -329,14 +585,37 @@ func AntiCSRFMiddleware(ctx *Context, m *Middleware) {
 	// HTTP Method is safe per defined in
 	// https://tools.ietf.org/html/rfc7231#section-4.2.1
 	if anticsrf.IsSafeHTTPMethod(ctx.Req.Method) {
-		ctx.Log().Tracef("HTTP %s is safe method per RFC7231", ctx.Req.Method)
+		ctx.moduleLog("security").Tracef("HTTP %s is safe method per RFC7231", ctx.Req.Method)
 		m.Next(ctx)
 		if err := ac.SetCookie(ctx.Res, secret); err != nil {
-			ctx.Log().Error("anticsrf: Unable to write cookie")
+			ctx.moduleLog("security").Error("anticsrf: Unable to write cookie")
 		}
 		return
 	}
 
+	if !isAntiCSRFRefererValid(ctx, ac) {
+		return
+	}
+
+	// Get request cipher secret from HTTP header or Form
+	requestSecret := ac.RequestCipherSecret(ctx.Req)
+	if requestSecret == nil || !ac.IsAuthentic(secret, requestSecret) {
+		ctx.moduleLog("security").Warn("anticsrf: Verification failed, invalid cipher secret")
+		ctx.Reply().Forbidden().Error(newError(anticsrf.ErrNoCookieFound, http.StatusForbidden))
+		return
+	}
+
+	ctx.moduleLog("security").Info("anticsrf: Cipher secret verification passed")
+	m.Next(ctx)
+
+	if err := ac.SetCookie(ctx.Res, secret); err != nil {
+		ctx.moduleLog("security").Error("anticsrf: Unable to write cookie")
+	}
+}
+
+// isAntiCSRFRefererValid method does strict referer check for HTTPS request,
+// it replies forbidden if referer is not valid.
+func isAntiCSRFRefererValid(ctx *Context, ac *anticsrf.AntiCSRF) bool {
 	// Below comment graciously borrowed from django
 	// Suppose user visits http://example.com/
 	// An active network attacker (man-in-the-middle, MITM) sends a
@@ -356,37 +635,47 @@ func AntiCSRFMiddleware(ctx *Context, m *Middleware) {
 	if ctx.Req.Scheme == ahttp.SchemeHTTPS {
 		referer, err := url.Parse(ctx.Req.Referer())
 		if err != nil {
-			ctx.Log().Warnf("anticsrf: Malformed referer %s", ctx.Req.Referer())
+			ctx.moduleLog("security").Warnf("anticsrf: Malformed referer %s", ctx.Req.Referer())
 			ctx.Reply().Forbidden().Error(newError(anticsrf.ErrMalformedReferer, http.StatusForbidden))
-			return
+			return false
 		}
 
 		if len(referer.String()) == 0 {
-			ctx.Log().Warnf("anticsrf: No referer %s", ctx.Req.Referer())
+			ctx.moduleLog("security").Warnf("anticsrf: No referer %s", ctx.Req.Referer())
 			ctx.Reply().Forbidden().Error(newError(anticsrf.ErrNoReferer, http.StatusForbidden))
-			return
+			return false
 		}
 
 		if !anticsrf.IsSameOrigin(ctx.Req.URL(), referer) && !ac.IsTrustedOrigin(referer) {
-			ctx.Log().Warnf("anticsrf: Bad referer %s", ctx.Req.Referer())
+			ctx.moduleLog("security").Warnf("anticsrf: Bad referer %s", ctx.Req.Referer())
 			ctx.Reply().Forbidden().Error(newError(anticsrf.ErrBadReferer, http.StatusForbidden))
-			return
+			return false
 		}
 	}
+	return true
+}
 
-	// Get request cipher secret from HTTP header or Form
-	requestSecret := ac.RequestCipherSecret(ctx.Req)
-	if requestSecret == nil || !ac.IsAuthentic(secret, requestSecret) {
-		ctx.Log().Warn("anticsrf: Verification failed, invalid cipher secret")
-		ctx.Reply().Forbidden().Error(newError(anticsrf.ErrNoCookieFound, http.StatusForbidden))
-		return
+// antiCSRFDoubleSubmit method verifies the double submit token cookie against
+// the HTTP header token for unsafe HTTP methods and issues the token cookie
+// if not exists.
+func antiCSRFDoubleSubmit(ctx *Context, m *Middleware, ac *anticsrf.AntiCSRF) {
+	token, isNew := ac.Token(ctx.Req)
+	if !anticsrf.IsSafeHTTPMethod(ctx.Req.Method) {
+		if !isAntiCSRFRefererValid(ctx, ac) {
+			return
+		}
+		if err := ac.VerifyToken(ctx.Req); err != nil {
+			ctx.moduleLog("security").Warnf("anticsrf: Double submit verification failed, %v", err)
+			ctx.Reply().Forbidden().Error(newError(err, http.StatusForbidden))
+			return
+		}
+		ctx.moduleLog("security").Info("anticsrf: Double submit token verification passed")
 	}
 
-	ctx.Log().Info("anticsrf: Cipher secret verification passed")
 	m.Next(ctx)
 
-	if err := ac.SetCookie(ctx.Res, secret); err != nil {
-		ctx.Log().Error("anticsrf: Unable to write cookie")
+	if isNew {
+		ac.SetTokenCookie(ctx.Res, token)
 	}
 }
 
@@ -0,0 +1,97 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"aahframe.work/essentials"
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2Encoder struct implements `PasswordEncoder` and `PasswordRehasher`
+// interface for `argon2id` hashing.
+type Argon2Encoder struct {
+	memory      uint32 // memory in KiB
+	iterations  uint32 // time cost
+	parallelism uint8  // threads
+	saltLen     int    // random salt bytes length
+	keyLen      uint32 // derived key length
+}
+
+// Generate method returns the `argon2id` password hash based on configured
+// values at `security.password_encoder.argon2id.*`.
+func (ae *Argon2Encoder) Generate(password []byte) ([]byte, error) {
+	salt := ess.GenerateSecureRandomKey(ae.saltLen)
+	key := argon2.IDKey(password, salt, ae.iterations, ae.memory, ae.parallelism, ae.keyLen)
+
+	// Format: $argon2id$v=version$m=memory,t=iterations,p=parallelism$salt$key
+	return []byte(fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		ae.memory, ae.iterations, ae.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))), nil
+}
+
+// Compare method compares given hash password and password using `argon2id`.
+func (ae *Argon2Encoder) Compare(hash, password []byte) bool {
+	p, salt, key, ok := parseArgon2Hash(hash)
+	if !ok {
+		// invalid hash
+		return false
+	}
+
+	otherKey := argon2.IDKey(password, salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	return (subtle.ConstantTimeCompare(key, otherKey) == 1)
+}
+
+// NeedsRehash method returns true if the given hash parameters are weaker
+// than configured values otherwise false.
+func (ae *Argon2Encoder) NeedsRehash(hash []byte) bool {
+	p, _, key, ok := parseArgon2Hash(hash)
+	if !ok {
+		return true
+	}
+	return p.memory < ae.memory || p.iterations < ae.iterations ||
+		p.parallelism < ae.parallelism || uint32(len(key)) < ae.keyLen
+}
+
+func parseArgon2Hash(hash []byte) (*Argon2Encoder, []byte, []byte, bool) {
+	if !strings.HasPrefix(string(hash), argon2idPrefix) {
+		return nil, nil, nil, false
+	}
+
+	// parts: version, params, salt, key
+	parts := strings.Split(strings.TrimPrefix(string(hash), argon2idPrefix), hashDelim)
+	if len(parts) != 4 {
+		return nil, nil, nil, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, false
+	}
+
+	p := &Argon2Encoder{}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil ||
+		p.iterations < 1 || p.parallelism < 1 {
+		return nil, nil, nil, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, false
+	}
+	return p, salt, key, true
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgon2Hashing(t *testing.T) {
+	passEncoders = make(map[string]PasswordEncoder)
+	cfg, _ := config.ParseString(`
+		security {
+			password_encoder {
+				argon2id {
+					memory = 1024
+					iterations = 2
+					parallelism = 1
+				}
+			}
+		}
+	`)
+
+	err := InitPasswordEncoders(cfg)
+	assert.Nil(t, err)
+
+	encoder := PasswordAlgorithm("argon2id")
+	assert.NotNil(t, encoder)
+
+	hash, err := encoder.Generate([]byte("welcome123"))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(hash), "$argon2id$v=19$m=1024,t=2,p=1$"))
+	assert.Equal(t, "argon2id", HashAlgorithm(hash))
+
+	assert.True(t, encoder.Compare(hash, []byte("welcome123")))
+	assert.False(t, encoder.Compare(hash, []byte("welcome@123")))
+
+	// rehash on weaker parameters
+	rehasher := encoder.(PasswordRehasher)
+	assert.False(t, rehasher.NeedsRehash(hash))
+	weak, _ := (&Argon2Encoder{memory: 512, iterations: 1, parallelism: 1, saltLen: 16, keyLen: 32}).Generate([]byte("welcome123"))
+	assert.True(t, rehasher.NeedsRehash(weak))
+	assert.True(t, encoder.Compare(weak, []byte("welcome123")))
+
+	// invalid hashes
+	for _, h := range []string{
+		"$2y$10$2A4GsJ6SmLAMvDe8XmTam.MSkKojdobBVJfIU7GiyoM.lWt.XV3H6",
+		"$argon2id$v=19$m=1024,t=2,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=2,p=1$!!$a2V5",
+		"$argon2id$v=19$m=1024,t=2,p=1$c2FsdA$!!",
+	} {
+		assert.False(t, encoder.Compare([]byte(h), []byte("welcome123")), h)
+		assert.True(t, rehasher.NeedsRehash([]byte(h)), h)
+	}
+
+	// invalid config
+	cfg.SetInt("security.password_encoder.argon2id.parallelism", 0)
+	err = InitPasswordEncoders(cfg)
+	assert.Equal(t, "acrypto/argon2id: invalid memory '1024', iterations '2' or parallelism '0'", err.Error())
+}
@@ -6,8 +6,8 @@ package acrypto
 
 import "golang.org/x/crypto/bcrypt"
 
-// BcryptEncoder struct implements `PasswordEncoder` interface for `bcrypt`
-// hashing.
+// BcryptEncoder struct implements `PasswordEncoder` and `PasswordRehasher`
+// interface for `bcrypt` hashing.
 type BcryptEncoder struct {
 	cost int
 }
@@ -23,3 +23,10 @@ func (be *BcryptEncoder) Compare(hash, password []byte) bool {
 	err := bcrypt.CompareHashAndPassword(hash, password)
 	return err == nil
 }
+
+// NeedsRehash method returns true if the given hash cost is lower than
+// configured cost otherwise false.
+func (be *BcryptEncoder) NeedsRehash(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err != nil || cost < be.cost
+}
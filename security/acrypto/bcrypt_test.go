@@ -35,3 +35,13 @@ func TestBcryptHashing(t *testing.T) {
 	result := encoder.Compare(hashPassword, []byte("welcome@123"))
 	assert.True(t, result)
 }
+
+func TestBcryptNeedsRehash(t *testing.T) {
+	hash := []byte("$2y$10$2A4GsJ6SmLAMvDe8XmTam.MSkKojdobBVJfIU7GiyoM.lWt.XV3H6")
+	assert.Equal(t, "bcrypt", HashAlgorithm(hash))
+	assert.Equal(t, "", HashAlgorithm([]byte("sha-512$10000$salt$key")))
+
+	assert.False(t, (&BcryptEncoder{cost: 10}).NeedsRehash(hash))
+	assert.True(t, (&BcryptEncoder{cost: 12}).NeedsRehash(hash))
+	assert.True(t, (&BcryptEncoder{cost: 10}).NeedsRehash([]byte("invalid")))
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a space efficient probabilistic set, used to check the
+// breached passwords. False positives are possible, false negatives are not.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// newBloomFilter method creates the bloom filter sized for n items with
+// given false positive rate.
+func newBloomFilter(n int, fpRate float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Ceil(math.Ln2 * float64(m) / float64(n)))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+func (b *bloomFilter) Add(v string) {
+	h1, h2 := bloomHash(v)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (b *bloomFilter) Contains(v string) bool {
+	h1, h2 := bloomHash(v)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHash method returns two hash values for double hashing technique.
+func bloomHash(v string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(v))
+	h1 := h.Sum64()
+	h2 := (h1 >> 33) | (h1 << 31) | 1
+	return h1, h2
+}
@@ -32,9 +32,29 @@ type PasswordEncoder interface {
 	Compare(hash, password []byte) bool
 }
 
+// PasswordRehasher interface is implemented by password encoder to report
+// the stored password hash was generated with weaker parameters than
+// configured ones, so that it gets rehashed upon successful login.
+type PasswordRehasher interface {
+	NeedsRehash(hash []byte) bool
+}
+
+// HashAlgorithm method identifies the password algorithm of given hash from
+// its prefix, supports `argon2id` and `bcrypt`. Otherwise empty string.
+func HashAlgorithm(hash []byte) string {
+	h := string(hash)
+	switch {
+	case strings.HasPrefix(h, argon2idPrefix):
+		return "argon2id"
+	case strings.HasPrefix(h, "$2a$"), strings.HasPrefix(h, "$2b$"), strings.HasPrefix(h, "$2y$"):
+		return "bcrypt"
+	}
+	return ""
+}
+
 // PasswordAlgorithm method returns the password encoder for given algorithm,
-// Otherwise nil. Out-of-the-box supported passowrd algorithms are `bcrypt`, `scrypt`
-// and `pbkdf2`. You can add your own if need be via method `AddPasswordEncoder`.
+// Otherwise nil. Out-of-the-box supported passowrd algorithms are `argon2id`,
+// `bcrypt`, `scrypt` and `pbkdf2`. You can add your own if need be via method `AddPasswordEncoder`.
 func PasswordAlgorithm(alg string) PasswordEncoder {
 	if pe, found := passEncoders[alg]; found {
 		return pe
@@ -58,6 +78,23 @@ func AddPasswordAlgorithm(name string, pe PasswordEncoder) error {
 func InitPasswordEncoders(cfg *config.Config) error {
 	keyPrefix := "security.password_encoder"
 
+	// argon2id algorithm
+	if cfg.BoolDefault(keyPrefix+".argon2id.enable", true) {
+		memory := cfg.IntDefault(keyPrefix+".argon2id.memory", 65536)
+		iterations := cfg.IntDefault(keyPrefix+".argon2id.iterations", 3)
+		parallelism := cfg.IntDefault(keyPrefix+".argon2id.parallelism", 2)
+		saltLen := cfg.IntDefault(keyPrefix+".argon2id.salt_length", 16)
+		keyLen := cfg.IntDefault(keyPrefix+".argon2id.key_length", 32)
+		if memory < 8*parallelism || iterations < 1 || parallelism < 1 || parallelism > 255 {
+			return fmt.Errorf("acrypto/argon2id: invalid memory '%d', iterations '%d' or parallelism '%d'",
+				memory, iterations, parallelism)
+		}
+
+		_ = AddPasswordAlgorithm("argon2id", &Argon2Encoder{
+			memory: uint32(memory), iterations: uint32(iterations), parallelism: uint8(parallelism),
+			saltLen: saltLen, keyLen: uint32(keyLen)})
+	}
+
 	// bcrypt algorithm
 	if cfg.BoolDefault(keyPrefix+".bcrypt.enable", true) {
 		bcryptCost := cfg.IntDefault(keyPrefix+".bcrypt.cost", 12)
@@ -0,0 +1,166 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"aahframe.work/config"
+)
+
+// Password policy errors
+var (
+	ErrPasswordTooShort    = errors.New("security/crypto: password is too short")
+	ErrPasswordTooLong     = errors.New("security/crypto: password is too long")
+	ErrPasswordNoUppercase = errors.New("security/crypto: password requires an uppercase letter")
+	ErrPasswordNoLowercase = errors.New("security/crypto: password requires a lowercase letter")
+	ErrPasswordNoDigit     = errors.New("security/crypto: password requires a digit")
+	ErrPasswordNoSpecial   = errors.New("security/crypto: password requires a special character")
+	ErrPasswordBreached    = errors.New("security/crypto: password is found in breached passwords list")
+)
+
+// breachedFalsePositiveRate is the false positive rate of breached passwords
+// bloom filter.
+const breachedFalsePositiveRate = 0.001
+
+var passwordPolicy = &PasswordPolicy{MinLength: 8}
+
+// PasswordPolicy struct holds the password strength rules configured at
+// `security.password_policy { ... }`.
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+
+	breached *bloomFilter
+}
+
+// Validate method validates the given password against the policy rules, it
+// returns the first rule violation error otherwise nil.
+func (p *PasswordPolicy) Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return ErrPasswordTooShort
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return ErrPasswordTooLong
+	}
+
+	var upper, lower, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			special = true
+		}
+	}
+	switch {
+	case p.RequireUppercase && !upper:
+		return ErrPasswordNoUppercase
+	case p.RequireLowercase && !lower:
+		return ErrPasswordNoLowercase
+	case p.RequireDigit && !digit:
+		return ErrPasswordNoDigit
+	case p.RequireSpecial && !special:
+		return ErrPasswordNoSpecial
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		return ErrPasswordBreached
+	}
+	return nil
+}
+
+// ValidatePassword method validates the given password against the
+// application password policy `security.password_policy { ... }`.
+//
+// For e.g.:
+// 	if err := acrypto.ValidatePassword(form.Password); err != nil {
+// 		// password doesn't meet the policy
+// 	}
+func ValidatePassword(password string) error {
+	return passwordPolicy.Validate(password)
+}
+
+// InitPasswordPolicy method initializes the password policy based on
+// configuration `security.password_policy { ... }`. Breached passwords list
+// file `breached_list` has one password per line, it's streamed into bloom
+// filter.
+func InitPasswordPolicy(cfg *config.Config) error {
+	keyPrefix := "security.password_policy"
+	p := &PasswordPolicy{
+		MinLength:        cfg.IntDefault(keyPrefix+".min_length", 8),
+		MaxLength:        cfg.IntDefault(keyPrefix+".max_length", 128),
+		RequireUppercase: cfg.BoolDefault(keyPrefix+".require_uppercase", false),
+		RequireLowercase: cfg.BoolDefault(keyPrefix+".require_lowercase", false),
+		RequireDigit:     cfg.BoolDefault(keyPrefix+".require_digit", false),
+		RequireSpecial:   cfg.BoolDefault(keyPrefix+".require_special", false),
+	}
+
+	if listFile := cfg.StringDefault(keyPrefix+".breached_list", ""); len(listFile) > 0 {
+		breached, err := loadBreachedList(listFile)
+		if err != nil {
+			return fmt.Errorf("acrypto/password_policy: %v", err)
+		}
+		p.breached = breached
+	}
+
+	passwordPolicy = p
+	return nil
+}
+
+// loadBreachedList method reads the list file twice, first pass counts the
+// passwords to size the bloom filter and second pass adds them, so that
+// the list is not held in memory.
+func loadBreachedList(listFile string) (*bloomFilter, error) {
+	f, err := os.Open(listFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	n, err := scanBreachedList(f, func(string) {})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	breached := newBloomFilter(n, breachedFalsePositiveRate)
+	if _, err = scanBreachedList(f, breached.Add); err != nil {
+		return nil, err
+	}
+	return breached, nil
+}
+
+func scanBreachedList(r io.Reader, fn func(password string)) (int, error) {
+	var n int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		fn(line)
+		n++
+	}
+	return n, scanner.Err()
+}
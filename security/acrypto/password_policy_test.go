@@ -0,0 +1,85 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"fmt"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicyDefault(t *testing.T) {
+	cfg, _ := config.ParseString(`security { }`)
+	assert.Nil(t, InitPasswordPolicy(cfg))
+
+	assert.Equal(t, ErrPasswordTooShort, ValidatePassword("short"))
+	assert.Nil(t, ValidatePassword("longenough"))
+	assert.Nil(t, ValidatePassword("password123"))
+}
+
+func TestPasswordPolicyRules(t *testing.T) {
+	cfg, _ := config.ParseString(`
+		security {
+			password_policy {
+				min_length = 10
+				max_length = 20
+				require_uppercase = true
+				require_lowercase = true
+				require_digit = true
+				require_special = true
+				breached_list = "testdata/breached-passwords.txt"
+			}
+		}
+	`)
+	assert.Nil(t, InitPasswordPolicy(cfg))
+
+	testcases := []struct {
+		password string
+		err      error
+	}{
+		{"Ab1@", ErrPasswordTooShort},
+		{"Ab1@Ab1@Ab1@Ab1@Ab1@Ab1@", ErrPasswordTooLong},
+		{"abcdefgh1@", ErrPasswordNoUppercase},
+		{"ABCDEFGH1@", ErrPasswordNoLowercase},
+		{"Abcdefghi@", ErrPasswordNoDigit},
+		{"Abcdefghi1", ErrPasswordNoSpecial},
+		{"Welcome@123", ErrPasswordBreached},
+		{"Wélcome@1234", nil},
+		{"Correct-Horse-9", nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.password, func(t *testing.T) {
+			assert.Equal(t, tc.err, ValidatePassword(tc.password))
+		})
+	}
+
+	cfg.SetString("security.password_policy.breached_list", "testdata/not-exists.txt")
+	err := InitPasswordPolicy(cfg)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "acrypto/password_policy: open testdata/not-exists.txt")
+
+	// reset to default
+	passwordPolicy = &PasswordPolicy{MinLength: 8}
+}
+
+func TestPasswordPolicyBloomFilter(t *testing.T) {
+	bf := newBloomFilter(1000, 0.001)
+	for i := 0; i < 1000; i++ {
+		bf.Add(fmt.Sprintf("breached-%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, bf.Contains(fmt.Sprintf("breached-%d", i)))
+	}
+
+	fp := 0
+	for i := 0; i < 10000; i++ {
+		if bf.Contains(fmt.Sprintf("not-breached-%d", i)) {
+			fp++
+		}
+	}
+	assert.True(t, fp < 50, "false positives %d", fp)
+}
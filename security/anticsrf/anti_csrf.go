@@ -6,7 +6,9 @@ package anticsrf
 
 import (
 	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -17,19 +19,34 @@ import (
 	"aahframe.work/security/cookie"
 )
 
+// Anti-CSRF modes
+const (
+	// ModeStandard is signed and encrypted secret cookie with salted cipher
+	// token in the form or HTTP header, token is rendered via template func.
+	ModeStandard = "standard"
+
+	// ModeDoubleSubmit is non-HttpOnly token cookie, read by JavaScript and
+	// sent back via HTTP header. It is suitable for stateless APIs consumed
+	// by SPAs.
+	ModeDoubleSubmit = "double_submit"
+)
+
 // Anti-CSRF errors
 var (
 	ErrNoReferer        = errors.New("security/anticsrf: no referer")
 	ErrMalformedReferer = errors.New("security/anticsrf: malformed referer")
 	ErrBadReferer       = errors.New("security/anticsrf: bad referer")
 	ErrNoCookieFound    = errors.New("security/anticsrf: no cookie found")
+	ErrTokenMismatch    = errors.New("security/anticsrf: token mismatch")
 )
 
 // AntiCSRF struct hold the implementation of Anti CSRF (aka XSRF) protection.
 type AntiCSRF struct {
 	Enabled        bool
+	mode           string
 	cfg            *config.Config
 	cookieMgr      *cookie.Manager
+	tokenCookie    *cookie.Options
 	secretLength   int
 	cookieName     string
 	headerName     string
@@ -50,6 +67,10 @@ func New(cfg *config.Config) (*AntiCSRF, error) {
 
 	c := &AntiCSRF{cfg: cfg}
 	c.Enabled = c.cfg.BoolDefault(keyPrefix+".enable", true)
+	c.mode = strings.ToLower(c.cfg.StringDefault(keyPrefix+".mode", ModeStandard))
+	if c.mode != ModeStandard && c.mode != ModeDoubleSubmit {
+		return nil, fmt.Errorf("security/anticsrf: unsupported mode '%s'", c.mode)
+	}
 	c.secretLength = c.cfg.IntDefault(keyPrefix+".secret_length", 32)
 	c.headerName = c.cfg.StringDefault(keyPrefix+".header_name", "X-Anti-CSRF-Token")
 	c.formFieldName = c.cfg.StringDefault(keyPrefix+".form_field_name", "anti_csrf_token")
@@ -80,6 +101,16 @@ func New(cfg *config.Config) (*AntiCSRF, error) {
 		return nil, err
 	}
 
+	// Double submit token cookie is read by JavaScript, so no HttpOnly,
+	// signing and encryption
+	if c.IsDoubleSubmit() {
+		c.cookieName = c.cfg.StringDefault(keyPrefix+".prefix", "aah") + "_csrf_token"
+		opts.Name = c.cookieName
+		opts.HTTPOnly = false
+		c.tokenCookie = opts
+		return c, nil
+	}
+
 	if c.cookieMgr, err = cookie.NewManager(opts,
 		c.cfg.StringDefault(keyPrefix+".sign_key", ""),
 		c.cfg.StringDefault(keyPrefix+".enc_key", ""),
@@ -148,6 +179,11 @@ func (ac *AntiCSRF) SaltCipherSecret(secret []byte) string {
 	return string(ess.EncodeToBase64(append(salt, xorBytes(salt, secret)...)))
 }
 
+// FormFieldName method returns the Anti-CSRF form field name.
+func (ac *AntiCSRF) FormFieldName() string {
+	return ac.formFieldName
+}
+
 // SetCookie method write/refresh the Anti-CSRF cookie value and expriy.
 func (ac *AntiCSRF) SetCookie(w http.ResponseWriter, secret []byte) error {
 	if len(secret) == 0 || ac.cookieMgr == nil {
@@ -168,17 +204,64 @@ func (ac *AntiCSRF) SetCookie(w http.ResponseWriter, secret []byte) error {
 
 // ClearCookie method is to clear Anti-CSRF cookie when disabled.
 func (ac *AntiCSRF) ClearCookie(w http.ResponseWriter, r *ahttp.Request) {
-	if !ac.Enabled || ac.cookieMgr == nil {
+	if !ac.Enabled {
+		return
+	}
+
+	var opts cookie.Options
+	switch {
+	case ac.cookieMgr != nil:
+		opts = *ac.cookieMgr.Options
+	case ac.tokenCookie != nil:
+		opts = *ac.tokenCookie
+	default:
 		return
 	}
 
-	if _, err := r.Cookie(ac.cookieMgr.Options.Name); err == nil {
-		opts := *ac.cookieMgr.Options
+	if _, err := r.Cookie(opts.Name); err == nil {
 		opts.MaxAge = -1
 		http.SetCookie(w, cookie.NewWithOptions("", &opts))
 	}
 }
 
+// IsDoubleSubmit method returns true if Anti-CSRF mode is `double_submit`
+// otherwise false.
+func (ac *AntiCSRF) IsDoubleSubmit() bool {
+	return ac.mode == ModeDoubleSubmit
+}
+
+// Token method returns the double submit token from the token cookie if
+// available otherwise generates new token, second return value is true for
+// new token.
+func (ac *AntiCSRF) Token(r *ahttp.Request) (string, bool) {
+	if c, err := r.Cookie(ac.cookieName); err == nil && len(c.Value) > 0 {
+		return c.Value, false
+	}
+	return base64.RawURLEncoding.EncodeToString(ac.GenerateSecret()), true
+}
+
+// SetTokenCookie method writes the double submit token cookie.
+func (ac *AntiCSRF) SetTokenCookie(w http.ResponseWriter, token string) {
+	if len(token) == 0 || ac.tokenCookie == nil {
+		return
+	}
+	http.SetCookie(w, cookie.NewWithOptions(token, ac.tokenCookie))
+}
+
+// VerifyToken method compares the double submit token cookie value and
+// HTTP header token value of the request.
+func (ac *AntiCSRF) VerifyToken(r *ahttp.Request) error {
+	c, err := r.Cookie(ac.cookieName)
+	if err != nil || len(c.Value) == 0 {
+		return ErrNoCookieFound
+	}
+	token := r.Header.Get(ac.headerName)
+	if len(token) == 0 || subtle.ConstantTimeCompare([]byte(c.Value), []byte(token)) != 1 {
+		return ErrTokenMismatch
+	}
+	return nil
+}
+
 // IsTrustedOrigin method returns true if given referrer host
 // listed in config `security.anti_csrf.trusted_origins`
 // otherwise false.
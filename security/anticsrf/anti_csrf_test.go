@@ -136,3 +136,69 @@ func TestAntiCSRFTimeUnit(t *testing.T) {
 	assert.Equal(t, int64(0), v)
 	assert.Equal(t, errors.New("unsupported time unit '10s' on 'security.anti_csrf.ttl'"), err)
 }
+
+func TestAntiCSRFDoubleSubmit(t *testing.T) {
+	cfg, err := config.ParseString(`
+	security {
+		anti_csrf {
+			mode = "double_submit"
+			header_name = "X-XSRF-Token"
+			samesite = "strict"
+		}
+	}
+	`)
+	assert.Nil(t, err)
+
+	antiCSRF, err := New(cfg)
+	assert.Nil(t, err)
+	assert.True(t, antiCSRF.IsDoubleSubmit())
+	assert.Nil(t, antiCSRF.cookieMgr)
+
+	// new token
+	req := ahttp.AcquireRequest(httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	token, isNew := antiCSRF.Token(req)
+	assert.True(t, isNew)
+	assert.NotEqual(t, "", token)
+
+	w := httptest.NewRecorder()
+	antiCSRF.SetTokenCookie(w, token)
+	setCookie := w.Header().Get(ahttp.HeaderSetCookie)
+	assert.True(t, strings.HasPrefix(setCookie, "aah_csrf_token="+token))
+	assert.False(t, strings.Contains(setCookie, "HttpOnly"))
+	assert.True(t, strings.Contains(setCookie, "SameSite=Strict"))
+
+	// existing token
+	r := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/", nil)
+	r.AddCookie(&http.Cookie{Name: "aah_csrf_token", Value: token})
+	req = ahttp.AcquireRequest(r)
+	existing, isNew := antiCSRF.Token(req)
+	assert.False(t, isNew)
+	assert.Equal(t, token, existing)
+
+	// verify
+	assert.Equal(t, ErrTokenMismatch, antiCSRF.VerifyToken(req))
+	r.Header.Set("X-XSRF-Token", "invalid")
+	assert.Equal(t, ErrTokenMismatch, antiCSRF.VerifyToken(ahttp.AcquireRequest(r)))
+	r.Header.Set("X-XSRF-Token", token)
+	assert.Nil(t, antiCSRF.VerifyToken(ahttp.AcquireRequest(r)))
+
+	noCookieReq := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/", nil)
+	noCookieReq.Header.Set("X-XSRF-Token", token)
+	assert.Equal(t, ErrNoCookieFound, antiCSRF.VerifyToken(ahttp.AcquireRequest(noCookieReq)))
+
+	// clear cookie
+	w = httptest.NewRecorder()
+	antiCSRF.ClearCookie(w, ahttp.AcquireRequest(r))
+	assert.True(t, strings.Contains(w.Header().Get(ahttp.HeaderSetCookie), "Max-Age=0"))
+
+	// unsupported mode
+	cfg, _ = config.ParseString(`
+	security {
+		anti_csrf {
+			mode = "stateless"
+		}
+	}
+	`)
+	_, err = New(cfg)
+	assert.Equal(t, "security/anticsrf: unsupported mode 'stateless'", err.Error())
+}
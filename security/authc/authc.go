@@ -56,3 +56,24 @@ type PrincipalProvider interface {
 	// 		 security.auth_schemes.<keyname>
 	Principal(keyName string, v ess.Valuer) ([]*Principal, error)
 }
+
+// LockoutHandler interface is implemented to get notified when the subject
+// reaches the failed login attempts threshold of login throttling
+// `security.throttle.lockout_threshold`. For e.g.: lock the account in the
+// datasource, notify the user, etc.
+type LockoutHandler interface {
+	// Lockout method called by aah with subject identity, client IP address
+	// and failed attempts count.
+	Lockout(identity, clientIP string, failures int)
+}
+
+// CredentialUpdater interface is optionally implemented by `Authenticator` to
+// persist the rehashed credential. aah rehashes the credential upon successful
+// login when the stored hash uses other password algorithm or weaker parameters
+// than configured one, so that application migrates algorithms without a
+// batch job.
+type CredentialUpdater interface {
+	// UpdateCredential method called by auth scheme with authentication info
+	// and new credential hash.
+	UpdateCredential(authcInfo *AuthenticationInfo, credential []byte) error
+}
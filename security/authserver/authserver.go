@@ -0,0 +1,622 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package authserver implements OAuth2 (RFC 6749) and OpenID Connect
+// authorization server, so an aah application can issue tokens for its own
+// API consumers. It supports grant types `authorization_code` (with PKCE)
+// and `client_credentials`, JWKS publishing and token introspection
+// (RFC 7662). Access and ID tokens are JWT signed with `RS256`.
+//
+// Note: Issued authorization codes and token revocations are kept in-memory,
+// they don't survive the application restart and are not shared across
+// multiple instances. On multi-instance deployment, authorize and token
+// requests must be served by the same instance and revocation must be
+// applied on every instance.
+package authserver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// OAuth2 grant types
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+const keyPrefix = "security.auth_server"
+
+// ErrClientStoreIsNil returned when client store is not configured.
+var ErrClientStoreIsNil = errors.New("security/authserver: client store is nil")
+
+var _ ClientStore = (*staticClientStore)(nil)
+
+type (
+	// Server struct is OAuth2/OIDC authorization server.
+	Server struct {
+		Issuer         string
+		AccessTokenTTL time.Duration
+		IDTokenTTL     time.Duration
+		CodeTTL        time.Duration
+
+		appCfg   *config.Config
+		baseURL  string
+		keyID    string
+		key      *rsa.PrivateKey
+		clients  ClientStore
+		codesMu  sync.Mutex
+		codes    map[string]*authCode
+		tokensMu sync.RWMutex
+		revoked  map[string]time.Time
+	}
+
+	// ClientStore interface is used to look up the registered OAuth2 clients.
+	// For e.g.: static config (default), database, etc.
+	ClientStore interface {
+		// Init method gets called by aah during an application start.
+		Init(appCfg *config.Config) error
+
+		// Client method returns the client for given client ID. Returns nil
+		// if client does not exists.
+		Client(id string) (*Client, error)
+	}
+
+	// Client struct is registered OAuth2 client (API consumer).
+	Client struct {
+		ID           string
+		Secret       string
+		RedirectURIs []string
+		GrantTypes   []string
+		Scopes       []string
+
+		// Public client (for e.g.: SPA, mobile app) has no secret, PKCE is
+		// mandatory for public client.
+		Public bool
+
+		// Trusted client skips the consent screen.
+		Trusted bool
+	}
+
+	// AuthorizeRequest struct is validated authorization request, its used
+	// to render consent screen and to issue authorization code.
+	AuthorizeRequest struct {
+		Client              *Client
+		RedirectURI         string
+		State               string
+		Scopes              []string
+		Nonce               string
+		CodeChallenge       string
+		CodeChallengeMethod string
+
+		redirectURIGiven bool
+	}
+
+	// TokenResponse struct is successful token endpoint response.
+	TokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Scope       string `json:"scope,omitempty"`
+		IDToken     string `json:"id_token,omitempty"`
+	}
+
+	// Introspection struct is token introspection response (RFC 7662).
+	Introspection struct {
+		Active    bool   `json:"active"`
+		Scope     string `json:"scope,omitempty"`
+		ClientID  string `json:"client_id,omitempty"`
+		Subject   string `json:"sub,omitempty"`
+		TokenType string `json:"token_type,omitempty"`
+		ExpiresAt int64  `json:"exp,omitempty"`
+		IssuedAt  int64  `json:"iat,omitempty"`
+		Issuer    string `json:"iss,omitempty"`
+	}
+
+	// Error struct is OAuth2 error response.
+	Error struct {
+		Code        string `json:"error"`
+		Description string `json:"error_description,omitempty"`
+		Status      int    `json:"-"`
+	}
+
+	authCode struct {
+		request   *AuthorizeRequest
+		subject   string
+		expiresAt time.Time
+	}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//___________________________________
+
+// New method creates the authorization server from config
+// `security.auth_server { ... }`. Config `issuer` is required, it's the
+// token `iss` claim and base URL of discovery endpoints. Signing key is loaded from
+// `signing_key_file` (PEM encoded RSA private key), otherwise new key is
+// generated on every start and issued tokens become invalid upon restart.
+func New(appCfg *config.Config) (*Server, error) {
+	s := &Server{
+		Issuer:  strings.TrimRight(appCfg.StringDefault(keyPrefix+".issuer", ""), "/"),
+		appCfg:  appCfg,
+		codes:   make(map[string]*authCode),
+		revoked: make(map[string]time.Time),
+	}
+
+	if len(s.Issuer) == 0 {
+		return nil, fmt.Errorf("security/authserver: config '%s.issuer' is required", keyPrefix)
+	}
+	u, err := url.Parse(s.Issuer)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || len(u.Host) == 0 {
+		return nil, fmt.Errorf("security/authserver: config '%s.issuer' is not a valid URL", keyPrefix)
+	}
+	s.baseURL = u.Scheme + "://" + u.Host
+
+	if s.AccessTokenTTL, err = parseDuration(appCfg, "access_token_ttl", "1h"); err != nil {
+		return nil, err
+	}
+	if s.IDTokenTTL, err = parseDuration(appCfg, "id_token_ttl", "1h"); err != nil {
+		return nil, err
+	}
+	if s.CodeTTL, err = parseDuration(appCfg, "code_ttl", "1m"); err != nil {
+		return nil, err
+	}
+
+	if keyFile := appCfg.StringDefault(keyPrefix+".signing_key_file", ""); len(keyFile) > 0 {
+		if s.key, err = loadSigningKey(keyFile); err != nil {
+			return nil, err
+		}
+	} else if s.key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(s.key.PublicKey.N.Bytes())
+	s.keyID = base64.RawURLEncoding.EncodeToString(sum[:12])
+
+	if appCfg.IsExists(keyPrefix + ".clients") {
+		if err = s.SetClientStore(&staticClientStore{}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Server methods
+//___________________________________
+
+// SetClientStore method assigns the given `ClientStore` instance to
+// authorization server.
+func (s *Server) SetClientStore(store ClientStore) error {
+	if store == nil {
+		return ErrClientStoreIsNil
+	}
+	s.clients = store
+	return s.clients.Init(s.appCfg)
+}
+
+// ParseAuthorizeRequest method validates the authorization request of
+// response type `code`. Error with non-empty `RedirectURI` on
+// `AuthorizeRequest` should be sent to client via `ErrorRedirectURL`,
+// otherwise reply it to the user-agent.
+func (s *Server) ParseAuthorizeRequest(r *http.Request) (*AuthorizeRequest, *Error) {
+	_ = r.ParseForm()
+	client, e := s.lookupClient(r.Form.Get("client_id"))
+	if e != nil {
+		return nil, e
+	}
+
+	ar := &AuthorizeRequest{
+		Client:              client,
+		State:               r.Form.Get("state"),
+		Nonce:               r.Form.Get("nonce"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+	}
+	ar.RedirectURI = r.Form.Get("redirect_uri")
+	if ar.redirectURIGiven = len(ar.RedirectURI) > 0; !ar.redirectURIGiven && len(client.RedirectURIs) == 1 {
+		ar.RedirectURI = client.RedirectURIs[0]
+	}
+	if !contains(client.RedirectURIs, ar.RedirectURI) {
+		return nil, newError("invalid_request", "redirect_uri is not registered", http.StatusBadRequest)
+	}
+
+	// Errors below are sent to client redirect URI
+	if r.Form.Get("response_type") != "code" {
+		return ar, newError("unsupported_response_type", "only response type 'code' is supported", http.StatusBadRequest)
+	}
+	if !contains(client.GrantTypes, GrantAuthorizationCode) {
+		return ar, newError("unauthorized_client", "grant type 'authorization_code' is not allowed", http.StatusBadRequest)
+	}
+	if ar.Scopes, e = allowedScopes(client, r.Form.Get("scope"), true); e != nil {
+		return ar, e
+	}
+	if len(ar.CodeChallenge) > 0 && ar.CodeChallengeMethod != "S256" {
+		return ar, newError("invalid_request", "code_challenge_method 'S256' is required", http.StatusBadRequest)
+	}
+	if client.Public && len(ar.CodeChallenge) == 0 {
+		return ar, newError("invalid_request", "PKCE code_challenge is required for public client", http.StatusBadRequest)
+	}
+	return ar, nil
+}
+
+// IssueCode method issues the single-use authorization code for given
+// authorize request and subject, returns the client redirect URL. Code is
+// kept in-memory until its exchange or expiry.
+func (s *Server) IssueCode(ar *AuthorizeRequest, subject string) string {
+	code := ess.SecureRandomString(32)
+	now := time.Now()
+	s.codesMu.Lock()
+	for k, v := range s.codes {
+		if now.After(v.expiresAt) {
+			delete(s.codes, k)
+		}
+	}
+	s.codes[code] = &authCode{request: ar, subject: subject, expiresAt: now.Add(s.CodeTTL)}
+	s.codesMu.Unlock()
+
+	return addQuery(ar.RedirectURI, url.Values{"code": {code}, "state": {ar.State}})
+}
+
+// ErrorRedirectURL method returns the client redirect URL with given error.
+// For e.g.: user denied the consent `access_denied`.
+func (s *Server) ErrorRedirectURL(ar *AuthorizeRequest, e *Error) string {
+	return addQuery(ar.RedirectURI, url.Values{"error": {e.Code},
+		"error_description": {e.Description}, "state": {ar.State}})
+}
+
+// Token method handles the token endpoint request for grant types
+// `authorization_code` and `client_credentials`. Client is authenticated
+// via HTTP basic auth or form values `client_id` and `client_secret`.
+func (s *Server) Token(r *http.Request) (*TokenResponse, *Error) {
+	_ = r.ParseForm()
+	grantType := r.Form.Get("grant_type")
+	switch grantType {
+	case GrantAuthorizationCode:
+		return s.exchangeCode(r)
+	case GrantClientCredentials:
+		client, e := s.authenticateClient(r)
+		if e != nil {
+			return nil, e
+		}
+		if client.Public || !contains(client.GrantTypes, GrantClientCredentials) {
+			return nil, newError("unauthorized_client", "grant type 'client_credentials' is not allowed", http.StatusBadRequest)
+		}
+		scopes, e := allowedScopes(client, r.Form.Get("scope"), false)
+		if e != nil {
+			return nil, e
+		}
+		return s.issueTokens(client, client.ID, scopes, "")
+	}
+	return nil, newError("unsupported_grant_type", fmt.Sprintf("grant type '%s' is not supported", grantType), http.StatusBadRequest)
+}
+
+// Introspect method handles the token introspection request (RFC 7662),
+// client must be authenticated. Inactive token is not an error.
+func (s *Server) Introspect(r *http.Request) (*Introspection, *Error) {
+	_ = r.ParseForm()
+	if _, e := s.authenticateClient(r); e != nil {
+		return nil, e
+	}
+
+	claims, err := s.verifyJWT(r.Form.Get("token"))
+	if err != nil || claims.TokenUse != "access" || s.isRevoked(claims.ID) {
+		return &Introspection{Active: false}, nil
+	}
+	return &Introspection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Subject:   claims.Subject,
+		TokenType: "Bearer",
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+		Issuer:    claims.Issuer,
+	}, nil
+}
+
+// Revoke method revokes the given access token until its expiry. Revocation
+// is kept in-memory, it's applicable only to the current instance.
+func (s *Server) Revoke(token string) {
+	claims, err := s.verifyJWT(token)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	s.tokensMu.Lock()
+	for k, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, k)
+		}
+	}
+	s.revoked[claims.ID] = time.Unix(claims.ExpiresAt, 0)
+	s.tokensMu.Unlock()
+}
+
+// JWKS method returns the JSON Web Key Set of token signing public key.
+func (s *Server) JWKS() map[string]interface{} {
+	return map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": s.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.PublicKey.E)).Bytes()),
+		}},
+	}
+}
+
+// Metadata method returns the OpenID Connect discovery metadata, endpoint
+// URLs are composed from the configured issuer origin and given path prefix.
+func (s *Server) Metadata(pathPrefix string) map[string]interface{} {
+	prefix := s.baseURL + pathPrefix
+	return map[string]interface{}{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                prefix + "/authorize",
+		"token_endpoint":                        prefix + "/token",
+		"introspection_endpoint":                prefix + "/introspect",
+		"jwks_uri":                              prefix + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{GrantAuthorizationCode, GrantClientCredentials},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+	}
+}
+
+// Error method is `error` interface implementation.
+func (e *Error) Error() string {
+	return fmt.Sprintf("security/authserver: %s, %s", e.Code, e.Description)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Server Unexported methods
+//___________________________________
+
+func (s *Server) exchangeCode(r *http.Request) (*TokenResponse, *Error) {
+	s.codesMu.Lock()
+	ac, found := s.codes[r.Form.Get("code")]
+	delete(s.codes, r.Form.Get("code")) // single-use
+	s.codesMu.Unlock()
+	if !found || time.Now().After(ac.expiresAt) {
+		return nil, newError("invalid_grant", "authorization code is invalid or expired", http.StatusBadRequest)
+	}
+
+	ar := ac.request
+	var client *Client
+	var e *Error
+	if ar.Client.Public {
+		if client, e = s.lookupClient(r.Form.Get("client_id")); e != nil {
+			return nil, e
+		}
+	} else if client, e = s.authenticateClient(r); e != nil {
+		return nil, e
+	}
+	if client.ID != ar.Client.ID {
+		return nil, newError("invalid_grant", "authorization code was issued to another client", http.StatusBadRequest)
+	}
+	if ar.redirectURIGiven && r.Form.Get("redirect_uri") != ar.RedirectURI {
+		return nil, newError("invalid_grant", "redirect_uri mismatch", http.StatusBadRequest)
+	}
+	if len(ar.CodeChallenge) > 0 {
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(ar.CodeChallenge)) != 1 {
+			return nil, newError("invalid_grant", "PKCE code_verifier mismatch", http.StatusBadRequest)
+		}
+	}
+	return s.issueTokens(client, ac.subject, ar.Scopes, ar.Nonce)
+}
+
+func (s *Server) issueTokens(client *Client, subject string, scopes []string, nonce string) (*TokenResponse, *Error) {
+	now := time.Now()
+	scope := strings.Join(scopes, " ")
+	accessToken, err := s.signJWT(&claims{
+		Issuer:    s.Issuer,
+		Subject:   subject,
+		Audience:  client.ID,
+		ClientID:  client.ID,
+		Scope:     scope,
+		TokenUse:  "access",
+		ID:        ess.SecureRandomString(24),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, newError("server_error", err.Error(), http.StatusInternalServerError)
+	}
+
+	tr := &TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.AccessTokenTTL.Seconds()),
+		Scope:       scope,
+	}
+
+	// OpenID Connect ID token for end-user
+	if subject != client.ID && contains(scopes, "openid") {
+		if tr.IDToken, err = s.signJWT(&claims{
+			Issuer:    s.Issuer,
+			Subject:   subject,
+			Audience:  client.ID,
+			Nonce:     nonce,
+			TokenUse:  "id",
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(s.IDTokenTTL).Unix(),
+		}); err != nil {
+			return nil, newError("server_error", err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return tr, nil
+}
+
+func (s *Server) lookupClient(id string) (*Client, *Error) {
+	if s.clients == nil {
+		return nil, newError("server_error", ErrClientStoreIsNil.Error(), http.StatusInternalServerError)
+	}
+	if len(id) == 0 {
+		return nil, newError("invalid_request", "client_id is required", http.StatusBadRequest)
+	}
+	client, err := s.clients.Client(id)
+	if err != nil {
+		return nil, newError("server_error", err.Error(), http.StatusInternalServerError)
+	}
+	if client == nil {
+		return nil, newError("invalid_client", "client is not registered", http.StatusUnauthorized)
+	}
+	return client, nil
+}
+
+func (s *Server) authenticateClient(r *http.Request) (*Client, *Error) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+	}
+	client, e := s.lookupClient(id)
+	if e != nil {
+		if e.Code == "invalid_request" {
+			e = newError("invalid_client", "client authentication is required", http.StatusUnauthorized)
+		}
+		return nil, e
+	}
+	if client.Public || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(secret)) != 1 {
+		return nil, newError("invalid_client", "client authentication failed", http.StatusUnauthorized)
+	}
+	return client, nil
+}
+
+func (s *Server) isRevoked(id string) bool {
+	s.tokensMu.RLock()
+	defer s.tokensMu.RUnlock()
+	_, found := s.revoked[id]
+	return found
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+// staticClientStore is `ClientStore` implementation from config
+// `security.auth_server.clients`.
+type staticClientStore struct {
+	clients map[string]*Client
+}
+
+func (cs *staticClientStore) Init(appCfg *config.Config) error {
+	cs.clients = make(map[string]*Client)
+	for _, id := range appCfg.KeysByPath(keyPrefix + ".clients") {
+		prefix := keyPrefix + ".clients." + id
+		c := &Client{
+			ID:      id,
+			Secret:  appCfg.StringDefault(prefix+".secret", ""),
+			Public:  appCfg.BoolDefault(prefix+".public", false),
+			Trusted: appCfg.BoolDefault(prefix+".trusted", false),
+		}
+		c.RedirectURIs, _ = appCfg.StringList(prefix + ".redirect_uris")
+		c.Scopes, _ = appCfg.StringList(prefix + ".scopes")
+		if c.GrantTypes, _ = appCfg.StringList(prefix + ".grant_types"); len(c.GrantTypes) == 0 {
+			c.GrantTypes = []string{GrantAuthorizationCode}
+		}
+		if !c.Public && len(c.Secret) == 0 {
+			return fmt.Errorf("security/authserver: config '%s.secret' is required", prefix)
+		}
+		cs.clients[id] = c
+	}
+	return nil
+}
+
+func (cs *staticClientStore) Client(id string) (*Client, error) {
+	return cs.clients[id], nil
+}
+
+func newError(code, desc string, status int) *Error {
+	return &Error{Code: code, Description: desc, Status: status}
+}
+
+// allowedScopes method returns the requested scopes if client is allowed,
+// otherwise client registered scopes if none requested. Scope `openid` is
+// allowed for end-user authorization.
+func allowedScopes(client *Client, scope string, endUser bool) ([]string, *Error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return client.Scopes, nil
+	}
+	for _, sc := range requested {
+		if !contains(client.Scopes, sc) && !(endUser && sc == "openid") {
+			return nil, newError("invalid_scope", fmt.Sprintf("scope '%s' is not allowed", sc), http.StatusBadRequest)
+		}
+	}
+	return requested, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func addQuery(rawURL string, params url.Values) string {
+	for k, v := range params {
+		if len(v) == 0 || len(v[0]) == 0 {
+			delete(params, k)
+		}
+	}
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + params.Encode()
+}
+
+func parseDuration(appCfg *config.Config, key, defaultValue string) (time.Duration, error) {
+	d, err := time.ParseDuration(appCfg.StringDefault(keyPrefix+"."+key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("security/authserver: '%s.%s' value is not a valid time unit", keyPrefix, key)
+	}
+	return d, nil
+}
+
+func loadSigningKey(keyFile string) (*rsa.PrivateKey, error) {
+	b, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("security/authserver: signing key '%s' is not PEM encoded", keyFile)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("security/authserver: signing key '%s' is not RSA private key", keyFile)
+	}
+	return rsaKey, nil
+}
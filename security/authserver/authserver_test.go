@@ -0,0 +1,266 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authserver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAuthServerConfig = `
+security {
+  auth_server {
+    issuer = "https://auth.example.com"
+    clients {
+      reports_app {
+        secret = "e0f4b2c9a1d84e6f"
+        redirect_uris = ["https://reports.example.com/callback"]
+        grant_types = ["authorization_code", "client_credentials"]
+        scopes = ["orders:read", "orders:write"]
+      }
+      spa_app {
+        public = true
+        redirect_uris = ["https://spa.example.com/cb", "https://spa.example.com/cb2"]
+        scopes = ["orders:read"]
+      }
+    }
+  }
+}
+`
+
+func newTestServer(t *testing.T) *Server {
+	cfg, err := config.ParseString(testAuthServerConfig)
+	assert.Nil(t, err)
+	s, err := New(cfg)
+	assert.Nil(t, err)
+	return s
+}
+
+func formRequest(target string, form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestAuthServerAuthorizationCodeFlow(t *testing.T) {
+	s := newTestServer(t)
+	assert.Equal(t, "https://auth.example.com", s.Issuer)
+	assert.Equal(t, time.Hour, s.AccessTokenTTL)
+
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	// public client with PKCE
+	r := httptest.NewRequest(http.MethodGet, "/oauth2/authorize?response_type=code&client_id=spa_app"+
+		"&redirect_uri=https://spa.example.com/cb&scope=openid+orders:read&state=xyz&nonce=n-0S6"+
+		"&code_challenge="+challenge+"&code_challenge_method=S256", nil)
+	ar, e := s.ParseAuthorizeRequest(r)
+	assert.Nil(t, e)
+	assert.Equal(t, []string{"openid", "orders:read"}, ar.Scopes)
+
+	u, _ := url.Parse(s.IssueCode(ar, "jeeva"))
+	assert.Equal(t, "spa.example.com", u.Host)
+	assert.Equal(t, "xyz", u.Query().Get("state"))
+	code := u.Query().Get("code")
+
+	// PKCE mismatch, code is single-use
+	_, e = s.Token(formRequest("/oauth2/token", url.Values{"grant_type": {"authorization_code"}, "code": {code},
+		"client_id": {"spa_app"}, "redirect_uri": {"https://spa.example.com/cb"}, "code_verifier": {"wrong"}}))
+	assert.Equal(t, "invalid_grant", e.Code)
+	_, e = s.Token(formRequest("/oauth2/token", url.Values{"grant_type": {"authorization_code"}, "code": {code},
+		"client_id": {"spa_app"}, "redirect_uri": {"https://spa.example.com/cb"}, "code_verifier": {verifier}}))
+	assert.Equal(t, "invalid_grant", e.Code)
+
+	u, _ = url.Parse(s.IssueCode(ar, "jeeva"))
+	tr, e := s.Token(formRequest("/oauth2/token", url.Values{"grant_type": {"authorization_code"}, "code": {u.Query().Get("code")},
+		"client_id": {"spa_app"}, "redirect_uri": {"https://spa.example.com/cb"}, "code_verifier": {verifier}}))
+	assert.Nil(t, e)
+	assert.Equal(t, "Bearer", tr.TokenType)
+	assert.Equal(t, int64(3600), tr.ExpiresIn)
+	assert.Equal(t, "openid orders:read", tr.Scope)
+
+	idClaims, err := s.verifyJWT(tr.IDToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva", idClaims.Subject)
+	assert.Equal(t, "spa_app", idClaims.Audience)
+	assert.Equal(t, "n-0S6", idClaims.Nonce)
+
+	// confidential client, redirect uri is not given
+	r = httptest.NewRequest(http.MethodGet, "/oauth2/authorize?response_type=code&client_id=reports_app&scope=orders:write", nil)
+	ar, e = s.ParseAuthorizeRequest(r)
+	assert.Nil(t, e)
+	u, _ = url.Parse(s.IssueCode(ar, "jeeva"))
+	tr2 := formRequest("/oauth2/token", url.Values{"grant_type": {"authorization_code"}, "code": {u.Query().Get("code")}})
+	tr2.SetBasicAuth("reports_app", "e0f4b2c9a1d84e6f")
+	tr, e = s.Token(tr2)
+	assert.Nil(t, e)
+	assert.Empty(t, tr.IDToken)
+
+	// denied consent
+	u, _ = url.Parse(s.ErrorRedirectURL(ar, &Error{Code: "access_denied"}))
+	assert.Equal(t, "access_denied", u.Query().Get("error"))
+	_, found := u.Query()["state"]
+	assert.False(t, found)
+}
+
+func TestAuthServerAuthorizeErrors(t *testing.T) {
+	s := newTestServer(t)
+	for _, tc := range []struct {
+		query, code string
+		redirect    bool
+	}{
+		{"response_type=code", "invalid_request", false},
+		{"response_type=code&client_id=unknown", "invalid_client", false},
+		{"response_type=code&client_id=spa_app", "invalid_request", false},
+		{"response_type=code&client_id=spa_app&redirect_uri=https://evil.example.com", "invalid_request", false},
+		{"response_type=token&client_id=reports_app", "unsupported_response_type", true},
+		{"response_type=code&client_id=reports_app&scope=admin", "invalid_scope", true},
+		{"response_type=code&client_id=reports_app&code_challenge=abc&code_challenge_method=plain", "invalid_request", true},
+		{"response_type=code&client_id=spa_app&redirect_uri=https://spa.example.com/cb", "invalid_request", true},
+	} {
+		ar, e := s.ParseAuthorizeRequest(httptest.NewRequest(http.MethodGet, "/oauth2/authorize?"+tc.query, nil))
+		assert.Equal(t, tc.code, e.Code, tc.query)
+		assert.Equal(t, tc.redirect, ar != nil, tc.query)
+	}
+
+	// authorization code is not allowed
+	s.clients.(*staticClientStore).clients["reports_app"].GrantTypes = []string{GrantClientCredentials}
+	ar, e := s.ParseAuthorizeRequest(httptest.NewRequest(http.MethodGet, "/oauth2/authorize?response_type=code&client_id=reports_app", nil))
+	assert.NotNil(t, ar)
+	assert.Equal(t, "unauthorized_client", e.Code)
+}
+
+func TestAuthServerClientCredentialsAndIntrospect(t *testing.T) {
+	s := newTestServer(t)
+
+	r := formRequest("/oauth2/token", url.Values{"grant_type": {"client_credentials"}, "scope": {"orders:read"},
+		"client_id": {"reports_app"}, "client_secret": {"e0f4b2c9a1d84e6f"}})
+	tr, e := s.Token(r)
+	assert.Nil(t, e)
+	assert.Empty(t, tr.IDToken)
+
+	introspect := func(token string) *Introspection {
+		r := formRequest("/oauth2/introspect", url.Values{"token": {token}})
+		r.SetBasicAuth("reports_app", "e0f4b2c9a1d84e6f")
+		ti, e := s.Introspect(r)
+		assert.Nil(t, e)
+		return ti
+	}
+
+	ti := introspect(tr.AccessToken)
+	assert.True(t, ti.Active)
+	assert.Equal(t, "orders:read", ti.Scope)
+	assert.Equal(t, "reports_app", ti.Subject)
+	assert.Equal(t, "https://auth.example.com", ti.Issuer)
+
+	assert.False(t, introspect("invalid.token.value").Active)
+	s.Revoke(tr.AccessToken)
+	assert.False(t, introspect(tr.AccessToken).Active)
+
+	// errors
+	for _, tc := range []struct {
+		form url.Values
+		code string
+	}{
+		{url.Values{"grant_type": {"password"}}, "unsupported_grant_type"},
+		{url.Values{"grant_type": {"client_credentials"}}, "invalid_client"},
+		{url.Values{"grant_type": {"client_credentials"}, "client_id": {"reports_app"}, "client_secret": {"wrong"}}, "invalid_client"},
+		{url.Values{"grant_type": {"client_credentials"}, "client_id": {"spa_app"}}, "invalid_client"},
+		{url.Values{"grant_type": {"client_credentials"}, "client_id": {"reports_app"}, "client_secret": {"e0f4b2c9a1d84e6f"}, "scope": {"openid"}}, "invalid_scope"},
+	} {
+		_, e = s.Token(formRequest("/oauth2/token", tc.form))
+		assert.Equal(t, tc.code, e.Code, tc.form.Encode())
+	}
+
+	_, e = s.Introspect(formRequest("/oauth2/introspect", url.Values{"token": {tr.AccessToken}}))
+	assert.Equal(t, http.StatusUnauthorized, e.Status)
+	assert.Equal(t, "security/authserver: invalid_client, client authentication is required", e.Error())
+}
+
+func TestAuthServerJWKSAndMetadata(t *testing.T) {
+	s := newTestServer(t)
+	key := s.JWKS()["keys"].([]map[string]string)[0]
+	assert.Equal(t, s.keyID, key["kid"])
+	assert.Equal(t, "AQAB", key["e"])
+
+	n, _ := base64.RawURLEncoding.DecodeString(key["n"])
+	assert.Equal(t, s.key.PublicKey.N.Bytes(), n)
+
+	md := s.Metadata("/oauth2")
+	assert.Equal(t, "https://auth.example.com", md["issuer"])
+	assert.Equal(t, "https://auth.example.com/oauth2/jwks", md["jwks_uri"])
+	assert.Equal(t, []string{"S256"}, md["code_challenge_methods_supported"])
+}
+
+func TestAuthServerConfig(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "authserver")
+	defer os.RemoveAll(dir)
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keyFile := filepath.Join(dir, "signing-key.pem")
+	_ = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+
+	cfg, _ := config.ParseString(`
+	security {
+	  auth_server {
+	    issuer = "https://auth.example.com/"
+	    access_token_ttl = "10m"
+	    signing_key_file = "` + filepath.ToSlash(keyFile) + `"
+	  }
+	}`)
+	s, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 10*time.Minute, s.AccessTokenTTL)
+	assert.Equal(t, "https://auth.example.com", s.Issuer)
+	assert.Equal(t, key.PublicKey.N, s.key.PublicKey.N)
+
+	// client store is not configured
+	_, e := s.Token(formRequest("/oauth2/token", url.Values{"grant_type": {"client_credentials"}, "client_id": {"c1"}}))
+	assert.Equal(t, http.StatusInternalServerError, e.Status)
+	assert.Equal(t, ErrClientStoreIsNil, s.SetClientStore(nil))
+
+	for _, tc := range []struct {
+		cfg, err string
+	}{
+		{"security {\n auth_server {\n }\n}", "security/authserver: config 'security.auth_server.issuer' is required"},
+		{"security {\n auth_server {\n issuer = \"auth.example.com\"\n }\n}", "security/authserver: config 'security.auth_server.issuer' is not a valid URL"},
+		{"security {\n auth_server {\n issuer = \"https://a.com\"\n code_ttl = \"1x\"\n }\n}", "security/authserver: 'security.auth_server.code_ttl' value is not a valid time unit"},
+		{"security {\n auth_server {\n issuer = \"https://a.com\"\n signing_key_file = \"" + filepath.ToSlash(filepath.Join(dir, "not-exists.pem")) + "\"\n }\n}", "no such file or directory"},
+		{"security {\n auth_server {\n issuer = \"https://a.com\"\n clients {\n app1 {\n scopes = [\"a\"]\n }\n }\n }\n}", "security/authserver: config 'security.auth_server.clients.app1.secret' is required"},
+	} {
+		cfg, err := config.ParseString(tc.cfg)
+		assert.Nil(t, err)
+		_, err = New(cfg)
+		assert.True(t, strings.Contains(err.Error(), tc.err), err.Error())
+	}
+
+	// expired and tampered token
+	tk, _ := s.signJWT(&claims{TokenUse: "access", ExpiresAt: time.Now().Add(-time.Second).Unix()})
+	_, err = s.verifyJWT(tk)
+	assert.Equal(t, ErrInvalidToken, err)
+	tk, _ = s.signJWT(&claims{TokenUse: "access", ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	parts := strings.Split(tk, ".")
+	_, err = s.verifyJWT(parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "." + parts[2])
+	assert.Equal(t, ErrInvalidToken, err)
+	assert.Equal(t, errors.New("security/authserver: invalid token"), ErrInvalidToken)
+}
@@ -162,6 +162,12 @@ func (m *Manager) Write(w http.ResponseWriter, value string) {
 //   3) Encodes value into Base64 string
 //   4) Checks max cookie size i.e 4Kb
 func (m *Manager) Encode(b []byte) (string, error) {
+	return m.EncodeNamed(m.Options.Name, b)
+}
+
+// EncodeNamed method encodes given value for the cookie name, name is part
+// of signed data. See `Encode`.
+func (m *Manager) EncodeNamed(name string, b []byte) (string, error) {
 	// Encrypt it
 	if len(m.key.enc) > 0 {
 		b = acrypto.AESEncrypt(m.key.cipherBlock, b)
@@ -171,7 +177,7 @@ func (m *Manager) Encode(b []byte) (string, error) {
 	b = ess.EncodeToBase64(b)
 
 	// compose value of "name|date|value". Pipe is used while Decode
-	b = []byte(fmt.Sprintf("%s|%d|%s|", m.Options.Name, currentTimestamp(), b))
+	b = []byte(fmt.Sprintf("%s|%d|%s|", name, currentTimestamp(), b))
 
 	// Sign it if enabled
 	if len(m.key.sign) > 0 {
@@ -182,7 +188,7 @@ func (m *Manager) Encode(b []byte) (string, error) {
 	}
 
 	// Remove name
-	b = b[len(m.Options.Name)+1:]
+	b = b[len(name)+1:]
 
 	// Encode to base64
 	b = ess.EncodeToBase64(b)
@@ -205,6 +211,12 @@ func (m *Manager) Encode(b []byte) (string, error) {
 //   5) Decodes the value using Base64
 //   6) Decrypts the value
 func (m *Manager) Decode(value string) ([]byte, error) {
+	return m.DecodeNamed(m.Options.Name, value)
+}
+
+// DecodeNamed method decodes the secure cookie value of the cookie name.
+// See `Decode`.
+func (m *Manager) DecodeNamed(name, value string) ([]byte, error) {
 	// Check cookie max size.
 	if len(value) > m.maxCookieSize {
 		return nil, ErrCookieValueIsTooLarge
@@ -222,7 +234,7 @@ func (m *Manager) Decode(value string) ([]byte, error) {
 		return nil, ErrCookieValueIsInvalid
 	}
 
-	b = append([]byte(m.Options.Name+"|"), b[:len(b)-len(parts[2])-1]...)
+	b = append([]byte(name+"|"), b[:len(b)-len(parts[2])-1]...)
 
 	// Verify signed data, if enabled
 	var oldKey bool
@@ -271,6 +283,25 @@ func (m *Manager) Decode(value string) ([]byte, error) {
 	return b, err
 }
 
+// IsSignEnabled method returns true if sign key is configured.
+func (m *Manager) IsSignEnabled() bool {
+	return len(m.key.sign) > 0
+}
+
+// IsEncryptEnabled method returns true if encryption key is configured.
+func (m *Manager) IsEncryptEnabled() bool {
+	return len(m.key.enc) > 0
+}
+
+// SignOnly method returns the copy of cookie manager, which signs the value
+// without encryption.
+func (m *Manager) SignOnly() *Manager {
+	cm := *m
+	cm.key = &key{sign: m.key.sign}
+	cm.oldKey = &key{sign: m.oldKey.sign}
+	return &cm
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
@@ -143,3 +143,29 @@ func TestCookieWithKeysRotation(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, ErrSignVerificationIsFailed, err)
 }
+
+func TestCookieNamedSignOnly(t *testing.T) {
+	cm, err := NewManager(&Options{Name: "aah"},
+		"eFWLXEewECptbDVXExokRTLONWxrTjfV", "KYqklJsgeclPpZutTeQKNOTWlpksRBwA")
+	assert.Nil(t, err)
+	assert.True(t, cm.IsSignEnabled())
+	assert.True(t, cm.IsEncryptEnabled())
+
+	sm := cm.SignOnly()
+	assert.True(t, sm.IsSignEnabled())
+	assert.False(t, sm.IsEncryptEnabled())
+
+	v, err := sm.EncodeNamed("user_pref", []byte("dark"))
+	assert.Nil(t, err)
+	b, err := sm.DecodeNamed("user_pref", v)
+	assert.Nil(t, err)
+	assert.Equal(t, "dark", string(b))
+
+	// name is part of signed data
+	_, err = sm.DecodeNamed("aah", v)
+	assert.Equal(t, ErrSignVerificationIsFailed, err)
+
+	// signed value is readable
+	raw, _ := ess.DecodeBase64([]byte(v))
+	assert.True(t, strings.Contains(string(raw), string(ess.EncodeToBase64([]byte("dark")))))
+}
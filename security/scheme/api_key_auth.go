@@ -0,0 +1,254 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+)
+
+const keyAPIKey = "api_key"
+
+// ErrKeyStoreIsNil returned when API key store is not configured.
+var ErrKeyStoreIsNil = errors.New("security/scheme: api key store is nil")
+
+var (
+	_ Schemer  = (*APIKeyAuth)(nil)
+	_ KeyStore = (*staticKeyStore)(nil)
+)
+
+type (
+	// APIKeyAuth struct provides aah's OOTB API key auth scheme, useful for
+	// machine-to-machine endpoints. API key is read from configured header
+	// or query parameter and looked up via `KeyStore`.
+	//
+	// Key scopes and roles are mapped to Subject's authorization
+	// permissions and roles, key rate limit is applied per minute.
+	APIKeyAuth struct {
+		BaseAuth
+		HeaderName string
+		QueryParam string
+
+		keyStore KeyStore
+		limiter  *keyRateLimiter
+	}
+
+	// KeyStore interface is used to look up the API key for scheme `api_key`.
+	// For e.g.: static config (default), database, cache, etc.
+	KeyStore interface {
+		// Init method gets called by aah during an application start.
+		Init(appCfg *config.Config) error
+
+		// Lookup method returns the API key information for given key value.
+		// Returns nil if key does not exists.
+		Lookup(key string) (*APIKey, error)
+	}
+
+	// APIKey struct holds the API key information returned by `KeyStore`.
+	APIKey struct {
+		// ID is key identifier, used as Subject primary principal. Do not
+		// use the key value itself.
+		ID string
+
+		// Scopes are mapped to authorization permissions, for e.g.:
+		// `orders:read`, `orders:write`.
+		Scopes []string
+
+		// Roles are mapped to authorization roles.
+		Roles []string
+
+		// RateLimit is maximum requests per minute for the key, `0` is
+		// unlimited.
+		RateLimit int
+
+		// ExpiresAt is key expiry time, zero value never expires.
+		ExpiresAt time.Time
+
+		// Revoked is true if key is revoked.
+		Revoked bool
+	}
+
+	// RateLimitError is returned by `APIKeyAuth.DoAuthenticate` when API key
+	// exceeds its rate limit.
+	RateLimitError struct {
+		KeyID      string
+		RetryAfter time.Duration
+	}
+)
+
+// Init method initializes the API key authentication scheme from `security.auth_schemes`.
+func (a *APIKeyAuth) Init(cfg *config.Config, keyName string) error {
+	a.AppConfig = cfg
+	a.KeyName = keyName
+	a.KeyPrefix = "security.auth_schemes." + keyName
+	a.Name, _ = a.AppConfig.String(a.ConfigKey("scheme"))
+	a.HeaderName = http.CanonicalHeaderKey(a.AppConfig.StringDefault(a.ConfigKey("header"), "X-API-Key"))
+	a.QueryParam = a.AppConfig.StringDefault(a.ConfigKey("query_param"), "")
+	a.limiter = &keyRateLimiter{windows: make(map[string]*rateWindow)}
+
+	// Static key store from config
+	if a.AppConfig.IsExists(a.ConfigKey("keys")) {
+		return a.SetKeyStore(&staticKeyStore{keyPrefix: a.ConfigKey("keys")})
+	}
+	return nil
+}
+
+// SetKeyStore method assigns the given `KeyStore` instance to auth scheme.
+func (a *APIKeyAuth) SetKeyStore(keyStore KeyStore) error {
+	if keyStore == nil {
+		return ErrKeyStoreIsNil
+	}
+	a.keyStore = keyStore
+	return a.keyStore.Init(a.AppConfig)
+}
+
+// DoAuthenticate method looks up the API key via `KeyStore` and applies the
+// key rate limit.
+func (a *APIKeyAuth) DoAuthenticate(authcToken *authc.AuthenticationToken) (*authc.AuthenticationInfo, error) {
+	if a.keyStore == nil {
+		log.Warnf("%s: '%s' or key store is not properly configured in security.conf", a.KeyName, a.ConfigKey("keys"))
+		return nil, authc.ErrAuthenticatorIsNil
+	}
+	if len(authcToken.Identity) == 0 {
+		return nil, authc.ErrAuthenticationFailed
+	}
+
+	apiKey, err := a.keyStore.Lookup(authcToken.Identity)
+	if err != nil {
+		log.Errorf("%s: %v", a.KeyName, err)
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, authc.ErrSubjectNotExists
+	}
+	if apiKey.Revoked || (!apiKey.ExpiresAt.IsZero() && time.Now().After(apiKey.ExpiresAt)) {
+		log.Warnf("%s: API key [%s] is revoked or expired", a.KeyName, apiKey.ID)
+		return nil, authc.ErrAuthenticationFailed
+	}
+	if d := a.limiter.allow(apiKey, time.Now()); d > 0 {
+		return nil, &RateLimitError{KeyID: apiKey.ID, RetryAfter: d}
+	}
+
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals, &authc.Principal{Realm: "APIKey", Claim: "KeyID", Value: apiKey.ID, IsPrimary: true})
+	if authcToken.Values == nil {
+		authcToken.Values = make(map[string]interface{})
+	}
+	authcToken.Values[keyAPIKey] = apiKey
+	authcInfo.AuthenticationToken = authcToken
+	return authcInfo, nil
+}
+
+// DoAuthorizationInfo method maps the API key scopes and roles to
+// authorization information. If `Authorizer` is registered, its
+// authorization information is merged.
+func (a *APIKeyAuth) DoAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *authz.AuthorizationInfo {
+	authzInfo := authz.NewAuthorizationInfo()
+	if a.authorizer != nil {
+		if ai := a.authorizer.GetAuthorizationInfo(authcInfo); ai != nil {
+			authzInfo = ai
+		}
+	}
+
+	if authcInfo != nil && authcInfo.AuthenticationToken != nil {
+		if apiKey, ok := authcInfo.AuthenticationToken.Values[keyAPIKey].(*APIKey); ok {
+			authzInfo.AddRole(apiKey.Roles...)
+			authzInfo.AddPermissionString(apiKey.Scopes...)
+		}
+	}
+	return authzInfo
+}
+
+// ExtractAuthenticationToken method extracts the API key from the HTTP
+// request header, fallback to query parameter if configured.
+func (a *APIKeyAuth) ExtractAuthenticationToken(r *ahttp.Request) *authc.AuthenticationToken {
+	key := r.Header.Get(a.HeaderName)
+	if len(key) == 0 && len(a.QueryParam) > 0 {
+		key = r.QueryValue(a.QueryParam)
+	}
+	return &authc.AuthenticationToken{
+		Scheme:   a.Scheme(),
+		Identity: key,
+	}
+}
+
+// Error method is `error` interface implementation.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("security/scheme: api key '%s' rate limit exceeded, retry after %s", e.KeyID, e.RetryAfter)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+// staticKeyStore is `KeyStore` implementation from config
+// `security.auth_schemes.<keyname>.keys`. Key values are held as SHA-256
+// digest, use `ENC(...)` config value for the key.
+type staticKeyStore struct {
+	keyPrefix string
+	keys      map[[sha256.Size]byte]*APIKey
+}
+
+func (s *staticKeyStore) Init(appCfg *config.Config) error {
+	s.keys = make(map[[sha256.Size]byte]*APIKey)
+	for _, id := range appCfg.KeysByPath(s.keyPrefix) {
+		keyPrefix := s.keyPrefix + "." + id
+		value := appCfg.StringDefault(keyPrefix+".key", "")
+		if len(value) == 0 {
+			return fmt.Errorf("security/scheme: config '%s.key' is required", keyPrefix)
+		}
+		apiKey := &APIKey{ID: id, RateLimit: appCfg.IntDefault(keyPrefix+".rate_limit", 0)}
+		apiKey.Scopes, _ = appCfg.StringList(keyPrefix + ".scopes")
+		apiKey.Roles, _ = appCfg.StringList(keyPrefix + ".roles")
+		s.keys[sha256.Sum256([]byte(value))] = apiKey
+	}
+	return nil
+}
+
+func (s *staticKeyStore) Lookup(key string) (*APIKey, error) {
+	return s.keys[sha256.Sum256([]byte(key))], nil
+}
+
+// keyRateLimiter is fixed window (per minute) rate limiter per API key.
+type keyRateLimiter struct {
+	sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// allow method returns zero if request is allowed otherwise the duration
+// until the current window ends.
+func (l *keyRateLimiter) allow(apiKey *APIKey, now time.Time) time.Duration {
+	if apiKey.RateLimit <= 0 {
+		return 0
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	w, found := l.windows[apiKey.ID]
+	if !found || now.Sub(w.start) >= time.Minute {
+		w = &rateWindow{start: now}
+		l.windows[apiKey.ID] = w
+	}
+	if w.count >= apiKey.RateLimit {
+		return w.start.Add(time.Minute).Sub(now)
+	}
+	w.count++
+	return 0
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security/authc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKeyStore struct {
+	keys map[string]*APIKey
+}
+
+func (ks *testKeyStore) Init(appCfg *config.Config) error { return nil }
+
+func (ks *testKeyStore) Lookup(key string) (*APIKey, error) {
+	if key == "db-down" {
+		return nil, errors.New("db down")
+	}
+	return ks.keys[key], nil
+}
+
+func TestSchemeAPIKeyAuth(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	security {
+	  auth_schemes {
+	    api_key_auth {
+	      scheme = "api_key"
+	      query_param = "api_key"
+
+	      keys {
+	        partner_a {
+	          key = "a3f9c1d27e6b4f08"
+	          scopes = ["orders:read", "orders:write"]
+	          roles = ["partner"]
+	          rate_limit = 2
+	        }
+	        partner_b {
+	          key = "b81e0d4a9c2f7365"
+	        }
+	      }
+	    }
+	  }
+	}
+	`)
+
+	apiKeyAuth := New("api_key").(*APIKeyAuth)
+	assert.Nil(t, apiKeyAuth.Init(cfg, "api_key_auth"))
+	assert.Equal(t, "api_key", apiKeyAuth.Scheme())
+	assert.Equal(t, "X-Api-Key", apiKeyAuth.HeaderName)
+	assert.Equal(t, "api_key", apiKeyAuth.QueryParam)
+
+	extract := func(hdr, query string) *authc.AuthenticationToken {
+		req, _ := http.NewRequest("GET", "http://localhost:8080/orders?api_key="+query, nil)
+		req.Header.Set("X-API-Key", hdr)
+		return apiKeyAuth.ExtractAuthenticationToken(ahttp.ParseRequest(req, &ahttp.Request{}))
+	}
+
+	// header takes precedence over query param
+	assert.Equal(t, "a3f9c1d27e6b4f08", extract("a3f9c1d27e6b4f08", "b81e0d4a9c2f7365").Identity)
+	assert.Equal(t, "b81e0d4a9c2f7365", extract("", "b81e0d4a9c2f7365").Identity)
+
+	// Authentication - Failure
+	_, err := apiKeyAuth.DoAuthenticate(extract("", ""))
+	assert.Equal(t, authc.ErrAuthenticationFailed, err)
+	_, err = apiKeyAuth.DoAuthenticate(extract("unknown", ""))
+	assert.Equal(t, authc.ErrSubjectNotExists, err)
+
+	// Authentication - Success, scopes mapped to permissions
+	authcInfo, err := apiKeyAuth.DoAuthenticate(extract("a3f9c1d27e6b4f08", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, "partner_a", authcInfo.PrimaryPrincipal().Value)
+	authzInfo := apiKeyAuth.DoAuthorizationInfo(authcInfo)
+	assert.True(t, authzInfo.HasRole("partner"))
+	assert.True(t, authzInfo.IsPermitted("orders:read"))
+	assert.False(t, authzInfo.IsPermitted("orders:delete"))
+
+	// Rate limit
+	_, err = apiKeyAuth.DoAuthenticate(extract("a3f9c1d27e6b4f08", ""))
+	assert.Nil(t, err)
+	_, err = apiKeyAuth.DoAuthenticate(extract("a3f9c1d27e6b4f08", ""))
+	rle, ok := err.(*RateLimitError)
+	assert.True(t, ok)
+	assert.Equal(t, "partner_a", rle.KeyID)
+	assert.True(t, rle.RetryAfter > 0 && rle.RetryAfter <= time.Minute)
+	assert.Equal(t, time.Duration(0), apiKeyAuth.limiter.allow(&APIKey{ID: "partner_a", RateLimit: 2}, time.Now().Add(time.Minute)))
+
+	// unlimited key
+	for i := 0; i < 5; i++ {
+		_, err = apiKeyAuth.DoAuthenticate(extract("b81e0d4a9c2f7365", ""))
+		assert.Nil(t, err)
+	}
+
+	// Custom key store
+	assert.Equal(t, ErrKeyStoreIsNil, apiKeyAuth.SetKeyStore(nil))
+	assert.Nil(t, apiKeyAuth.SetKeyStore(&testKeyStore{keys: map[string]*APIKey{
+		"revoked": {ID: "k1", Revoked: true},
+		"expired": {ID: "k2", ExpiresAt: time.Now().Add(-time.Hour)},
+	}}))
+	_, err = apiKeyAuth.DoAuthenticate(extract("revoked", ""))
+	assert.Equal(t, authc.ErrAuthenticationFailed, err)
+	_, err = apiKeyAuth.DoAuthenticate(extract("expired", ""))
+	assert.Equal(t, authc.ErrAuthenticationFailed, err)
+	_, err = apiKeyAuth.DoAuthenticate(extract("db-down", ""))
+	assert.Equal(t, errors.New("db down"), err)
+
+	// Key store is not configured
+	apiKeyAuth = &APIKeyAuth{}
+	assert.Nil(t, apiKeyAuth.Init(config.NewEmpty(), "api_key_auth"))
+	_, err = apiKeyAuth.DoAuthenticate(extract("a3f9c1d27e6b4f08", ""))
+	assert.Equal(t, authc.ErrAuthenticatorIsNil, err)
+
+	// Static key store config error
+	cfg, _ = config.ParseString(`
+	security {
+	  auth_schemes {
+	    api_key_auth {
+	      scheme = "api_key"
+	      keys {
+	        partner_a {
+	          scopes = ["orders:read"]
+	        }
+	      }
+	    }
+	  }
+	}
+	`)
+	err = (&APIKeyAuth{}).Init(cfg, "api_key_auth")
+	assert.Equal(t, "security/scheme: config 'security.auth_schemes.api_key_auth.keys.partner_a.key' is required", err.Error())
+}
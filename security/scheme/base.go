@@ -117,6 +117,44 @@ func (b *BaseAuth) DoAuthenticate(authcToken *authc.AuthenticationToken) (*authc
 	return authcInfo, nil
 }
 
+// comparePassword method compares the subject credential hash with given
+// password. Hash of other password algorithm (`argon2id` and `bcrypt`) is
+// compared using its algorithm, then rehashed with configured password encoder
+// if authenticator implements `authc.CredentialUpdater`.
+func (b *BaseAuth) comparePassword(authcInfo *authc.AuthenticationInfo, password []byte) bool {
+	pe := b.passwordEncoder
+	if alg := acrypto.HashAlgorithm(authcInfo.Credential); len(alg) > 0 {
+		if ape := acrypto.PasswordAlgorithm(alg); ape != nil {
+			pe = ape
+		}
+	}
+	if !pe.Compare(authcInfo.Credential, password) {
+		return false
+	}
+
+	needsRehash := pe != b.passwordEncoder
+	if r, ok := b.passwordEncoder.(acrypto.PasswordRehasher); ok && !needsRehash {
+		needsRehash = r.NeedsRehash(authcInfo.Credential)
+	}
+	if updater, ok := b.authenticator.(authc.CredentialUpdater); ok && needsRehash {
+		b.rehashPassword(updater, authcInfo, password)
+	}
+	return true
+}
+
+func (b *BaseAuth) rehashPassword(updater authc.CredentialUpdater, authcInfo *authc.AuthenticationInfo, password []byte) {
+	hash, err := b.passwordEncoder.Generate(password)
+	if err != nil {
+		log.Errorf("%s: unable to rehash the credential: %v", b.KeyName, err)
+		return
+	}
+	if err = updater.UpdateCredential(authcInfo, hash); err != nil {
+		log.Errorf("%s: unable to update the rehashed credential: %v", b.KeyName, err)
+		return
+	}
+	log.Infof("%s: subject credential is rehashed", b.KeyName)
+}
+
 // DoAuthorizationInfo method calls registered `Authorizer` with authentication information.
 func (b *BaseAuth) DoAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *authz.AuthorizationInfo {
 	authzInfo := b.authorizer.GetAuthorizationInfo(authcInfo)
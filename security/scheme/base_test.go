@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"aahframe.work/config"
+	"aahframe.work/security/acrypto"
 	"aahframe.work/security/authc"
 	"aahframe.work/security/authz"
 	"github.com/stretchr/testify/assert"
@@ -58,3 +59,57 @@ func TestSchemeBaseAuth(t *testing.T) {
 	authcToken = baseAuth.ExtractAuthenticationToken(nil)
 	assert.Nil(t, authcToken)
 }
+
+type testCredentialUpdater struct {
+	testFormAuthentication
+	credential []byte
+}
+
+func (u *testCredentialUpdater) UpdateCredential(authcInfo *authc.AuthenticationInfo, credential []byte) error {
+	u.credential = credential
+	return nil
+}
+
+func TestSchemeBaseAuthPasswordMigration(t *testing.T) {
+	cfg, _ := config.ParseString(`
+		security {
+			password_encoder {
+				argon2id {
+					memory = 1024
+					iterations = 2
+					parallelism = 1
+				}
+			}
+		}
+	`)
+	assert.Nil(t, acrypto.InitPasswordEncoders(cfg))
+
+	updater := &testCredentialUpdater{}
+	baseAuth := BaseAuth{
+		KeyName:         "form_auth",
+		authenticator:   updater,
+		passwordEncoder: acrypto.PasswordAlgorithm("argon2id"),
+	}
+
+	// bcrypt hash gets migrated to argon2id
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Credential = []byte("$2y$10$2A4GsJ6SmLAMvDe8XmTam.MSkKojdobBVJfIU7GiyoM.lWt.XV3H6") // welcome123
+	assert.False(t, baseAuth.comparePassword(authcInfo, []byte("welcome@123")))
+	assert.Nil(t, updater.credential)
+	assert.True(t, baseAuth.comparePassword(authcInfo, []byte("welcome123")))
+	assert.Equal(t, "argon2id", acrypto.HashAlgorithm(updater.credential))
+
+	// up-to-date hash, no rehash
+	authcInfo.Credential, updater.credential = updater.credential, nil
+	assert.True(t, baseAuth.comparePassword(authcInfo, []byte("welcome123")))
+	assert.Nil(t, updater.credential)
+
+	// weaker parameters gets rehashed
+	weak, _ := acrypto.PasswordAlgorithm("argon2id").Generate([]byte("welcome123"))
+	cfg.SetInt("security.password_encoder.argon2id.iterations", 3)
+	assert.Nil(t, acrypto.InitPasswordEncoders(cfg))
+	baseAuth.passwordEncoder = acrypto.PasswordAlgorithm("argon2id")
+	authcInfo.Credential = weak
+	assert.True(t, baseAuth.comparePassword(authcInfo, []byte("welcome123")))
+	assert.Contains(t, string(updater.credential), "t=3")
+}
@@ -109,7 +109,7 @@ func (b *BasicAuth) DoAuthenticate(authcToken *authc.AuthenticationToken) (*auth
 	}
 
 	// Compare passwords
-	isPasswordOk := b.passwordEncoder.Compare(authcInfo.Credential, []byte(authcToken.Credential))
+	isPasswordOk := b.comparePassword(authcInfo, []byte(authcToken.Credential))
 	if !isPasswordOk {
 		log.Errorf("Subject [%s] credentials do not match", authcToken.Identity)
 		return nil, authc.ErrAuthenticationFailed
@@ -63,7 +63,7 @@ func (f *FormAuth) DoAuthenticate(authcToken *authc.AuthenticationToken) (*authc
 	}
 
 	// Compare passwords
-	isPasswordOk := f.passwordEncoder.Compare(authcInfo.Credential, []byte(authcToken.Credential))
+	isPasswordOk := f.comparePassword(authcInfo, []byte(authcToken.Credential))
 	if !isPasswordOk {
 		log.Errorf("%s: subject [%s] credentials do not match", f.KeyName, authcToken.Identity)
 		return nil, authc.ErrAuthenticationFailed
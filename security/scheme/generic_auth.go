@@ -19,6 +19,7 @@ type GenericAuth struct {
 	BaseAuth
 	IdentityHeader   string
 	CredentialHeader string
+	Challenge        string
 }
 
 // Init method initializes the Generic authentication scheme from `security.auth_schemes`.
@@ -29,6 +30,7 @@ func (g *GenericAuth) Init(cfg *config.Config, keyName string) error {
 	g.Name, _ = g.AppConfig.String(g.ConfigKey("scheme"))
 	g.IdentityHeader = http.CanonicalHeaderKey(g.AppConfig.StringDefault(g.ConfigKey("header.identity"), "Authorization"))
 	g.CredentialHeader = g.AppConfig.StringDefault(g.ConfigKey("header.credential"), "")
+	g.Challenge = g.AppConfig.StringDefault(g.ConfigKey("challenge"), "")
 	return nil
 }
 
@@ -74,6 +74,8 @@ func TestSchemeAPIAuth(t *testing.T) {
           # Default value is empty string
           #credential = "X-AuthPass"
         }
+
+        challenge = "Bearer realm=\"api\""
       }
     }
   }
@@ -87,6 +89,7 @@ func TestSchemeAPIAuth(t *testing.T) {
 	assert.Equal(t, "generic", genericAuth.Scheme())
 	assert.Equal(t, "X-Authorization", genericAuth.IdentityHeader)
 	assert.Equal(t, "", genericAuth.CredentialHeader)
+	assert.Equal(t, `Bearer realm="api"`, genericAuth.Challenge)
 
 	// Authentication - Failure
 	req, _ := http.NewRequest("GET", "http://localhost:8080/users/10010", nil)
@@ -6,7 +6,9 @@ package scheme
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -58,8 +60,58 @@ var (
 	ErrOAuth2InvalidState       = errors.New("oauth2: invalid state")
 	ErrOAuth2Exchange           = errors.New("oauth2: exchange failed, unable to get token")
 	ErrOAuth2TokenIsValid       = errors.New("oauth2: token is vaild")
+	ErrOAuth2UserInfo           = errors.New("oauth2: unable to get user info")
 )
 
+// keyOAuth2Token is same as `aah.KeyOAuth2Token`.
+const keyOAuth2Token = "_aahOAuth2Token"
+
+// OAuth2UserInfo struct holds the user information obtained from OAuth2
+// provider userinfo endpoint.
+type OAuth2UserInfo struct {
+	ID      string
+	Email   string
+	Name    string
+	Picture string
+	Raw     map[string]interface{}
+}
+
+// OAuth2Preset struct holds the OAuth2 provider preset values, preloaded for
+// `client.provider.name` and overridable via config.
+type OAuth2Preset struct {
+	Scopes      []string
+	UserInfoURL string
+	Claims      map[string]string
+	PKCE        bool
+}
+
+// OAuth2Presets is OAuth2 provider presets, Azure AD preset is applied to
+// provider names with prefix `azure`.
+var OAuth2Presets = map[string]*OAuth2Preset{
+	"azure": {
+		Scopes:      []string{"openid", "email", "profile"},
+		UserInfoURL: "https://graph.microsoft.com/oidc/userinfo",
+		Claims:      map[string]string{"id": "sub", "email": "email", "name": "name", "picture": "picture"},
+		PKCE:        true,
+	},
+	"facebook": {
+		Scopes:      []string{"email", "public_profile"},
+		UserInfoURL: "https://graph.facebook.com/me?fields=id,name,email,picture",
+		Claims:      map[string]string{"id": "id", "email": "email", "name": "name", "picture": "picture.data.url"},
+	},
+	"github": {
+		Scopes:      []string{"read:user", "user:email"},
+		UserInfoURL: "https://api.github.com/user",
+		Claims:      map[string]string{"id": "id", "email": "email", "name": "name", "picture": "avatar_url"},
+	},
+	"google": {
+		Scopes:      []string{"openid", "email", "profile"},
+		UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+		Claims:      map[string]string{"id": "sub", "email": "email", "name": "name", "picture": "picture"},
+		PKCE:        true,
+	},
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // OAuth2 Auth Scheme
 //______________________________________________________________________________
@@ -70,6 +122,10 @@ type OAuth2 struct {
 	LoginURL    string
 	RedirectURL string
 	SuccessURL  string
+	Provider    string
+	UserInfoURL string
+	Claims      map[string]string
+	PKCE        bool
 
 	redirectUpdated bool
 	signSha         string
@@ -102,9 +158,23 @@ func (o *OAuth2) Init(appCfg *config.Config, keyName string) error {
 		ClientSecret: clientSecret,
 	}
 
+	o.Provider = o.AppConfig.StringDefault(o.ConfigKey("client.provider.name"), "nil")
+	preset := inferPreset(o.Provider)
 	o.oauthCfg.Scopes, _ = o.AppConfig.StringList(o.ConfigKey("client.scopes"))
-	provider := o.AppConfig.StringDefault(o.ConfigKey("client.provider.name"), "nil")
-	endpoint := inferEndpoint(provider)
+	if len(o.oauthCfg.Scopes) == 0 {
+		o.oauthCfg.Scopes = preset.Scopes
+	}
+	o.UserInfoURL = o.AppConfig.StringDefault(o.ConfigKey("client.provider.url.userinfo"), preset.UserInfoURL)
+	o.PKCE = o.AppConfig.BoolDefault(o.ConfigKey("client.pkce"), preset.PKCE)
+	o.Claims = make(map[string]string)
+	for k, v := range preset.Claims {
+		o.Claims[k] = v
+	}
+	for _, k := range o.AppConfig.KeysByPath(o.ConfigKey("client.provider.claims")) {
+		o.Claims[k] = o.AppConfig.StringDefault(o.ConfigKey("client.provider.claims."+k), "")
+	}
+
+	endpoint := inferEndpoint(o.Provider)
 	if ess.IsStrEmpty(endpoint.AuthURL) && ess.IsStrEmpty(endpoint.TokenURL) {
 		authURL := o.AppConfig.StringDefault(o.ConfigKey("client.provider.url.auth"), "")
 		tokenURL := o.AppConfig.StringDefault(o.ConfigKey("client.provider.url.token"), "")
@@ -121,7 +191,8 @@ func (o *OAuth2) Init(appCfg *config.Config, keyName string) error {
 
 	principal := o.AppConfig.StringDefault(o.ConfigKey("principal"), "")
 	authorizer := o.AppConfig.StringDefault(o.ConfigKey("authorizer"), "")
-	if ess.IsStrEmpty(principal) || ess.IsStrEmpty(authorizer) {
+	// Principal is optional when provider userinfo endpoint is known, see `Principal`
+	if (ess.IsStrEmpty(principal) && ess.IsStrEmpty(o.UserInfoURL)) || ess.IsStrEmpty(authorizer) {
 		return fmt.Errorf("%s: '%s' and '%s' are required", o.KeyName, o.ConfigKey("principal"), o.ConfigKey("authorizer"))
 	}
 
@@ -172,7 +243,14 @@ func (o *OAuth2) ProviderAuthURL(r *ahttp.Request) (string, string) {
 	}
 
 	state, signedState := o.generateStateKey()
-	authURL := o.oauthCfg.AuthCodeURL(signedState)
+	var opts []oauth2.AuthCodeOption
+	if o.PKCE {
+		sum := sha256.Sum256([]byte(o.codeVerifier(state)))
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:])),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+	}
+	authURL := o.oauthCfg.AuthCodeURL(signedState, opts...)
 	return state, authURL
 }
 
@@ -190,7 +268,11 @@ func (o *OAuth2) ValidateCallback(state string, r *ahttp.Request) (*oauth2.Token
 	}
 
 	// Now get the access token
-	token, err := o.oauthCfg.Exchange(context.TODO(), code)
+	var opts []oauth2.AuthCodeOption
+	if o.PKCE {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", o.codeVerifier(state)))
+	}
+	token, err := o.oauthCfg.Exchange(context.TODO(), code, opts...)
 	if err != nil {
 		return nil, ErrOAuth2Exchange
 	}
@@ -199,18 +281,83 @@ func (o *OAuth2) ValidateCallback(state string, r *ahttp.Request) (*oauth2.Token
 }
 
 // Principal method calls the registered interface `SubjectPrincipalProvider`
-// to obtain Subject principals.
+// to obtain Subject principals. If its not registered and provider userinfo
+// endpoint is known then principals are created from `UserInfo`, primary
+// principal is claim `id` and `email`, `name` are added if present.
 func (o *OAuth2) Principal(keyName string, v ess.Valuer) ([]*authc.Principal, error) {
-	if o.principalProvider == nil {
+	if o.principalProvider != nil {
+		return o.principalProvider.Principal(keyName, v)
+	}
+	if ess.IsStrEmpty(o.UserInfoURL) {
 		return nil, fmt.Errorf("%s: '%s.provider.principal' not configured properly", o.Scheme(), o.KeyPrefix)
 	}
-	return o.principalProvider.Principal(keyName, v)
+
+	token, ok := v.Get(keyOAuth2Token).(*oauth2.Token)
+	if !ok {
+		return nil, ErrOAuth2UserInfo
+	}
+	userInfo, err := o.UserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+	if ess.IsStrEmpty(userInfo.ID) {
+		return nil, ErrOAuth2UserInfo
+	}
+
+	principals := []*authc.Principal{{Realm: o.KeyName, Claim: "ID", Value: userInfo.ID, IsPrimary: true}}
+	if len(userInfo.Email) > 0 {
+		principals = append(principals, &authc.Principal{Realm: o.KeyName, Claim: "Email", Value: userInfo.Email})
+	}
+	if len(userInfo.Name) > 0 {
+		principals = append(principals, &authc.Principal{Realm: o.KeyName, Claim: "Name", Value: userInfo.Name})
+	}
+	return principals, nil
+}
+
+// UserInfo method fetches the user information from provider userinfo
+// endpoint with given token. Claims are mapped per provider preset and
+// `client.provider.claims`, nested field is denoted by dot notation, for
+// e.g.: `picture.data.url`.
+func (o *OAuth2) UserInfo(token *oauth2.Token) (*OAuth2UserInfo, error) {
+	if ess.IsStrEmpty(o.UserInfoURL) {
+		return nil, fmt.Errorf("%s: config '%s' is required", o.KeyName, o.ConfigKey("client.provider.url.userinfo"))
+	}
+
+	resp, err := o.Client(token).Get(o.UserInfoURL)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %v, status code %d", o.KeyName, ErrOAuth2UserInfo, resp.StatusCode)
+	}
+
+	raw := make(map[string]interface{})
+	d := json.NewDecoder(resp.Body)
+	d.UseNumber()
+	if err = d.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	return &OAuth2UserInfo{
+		ID:      claimValue(raw, o.Claims["id"]),
+		Email:   claimValue(raw, o.Claims["email"]),
+		Name:    claimValue(raw, o.Claims["name"]),
+		Picture: claimValue(raw, o.Claims["picture"]),
+		Raw:     raw,
+	}, nil
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // OAuth2 Unexported methods
 //______________________________________________________________________________
 
+// codeVerifier method returns the PKCE code verifier for given state, its
+// derived from state and sign key. So it does not need to be stored.
+func (o *OAuth2) codeVerifier(state string) string {
+	return base64.RawURLEncoding.EncodeToString(acrypto.Sign(o.signKey, []byte("pkce:"+state), o.signSha))
+}
+
 func (o *OAuth2) generateStateKey() (string, string) {
 	state := ess.SecureRandomString(32) + ":" + fmt.Sprintf("%v", time.Now().UTC().UnixNano())
 	return state, base64.RawURLEncoding.EncodeToString(acrypto.Sign(o.signKey, []byte(state), o.signSha))
@@ -241,6 +388,39 @@ func createDefaultURL(keyName, suffix string) string {
 	return "/" + path.Join(keyName, suffix)
 }
 
+func inferPreset(provider string) *OAuth2Preset {
+	if strings.HasPrefix(provider, "azure") {
+		provider = "azure"
+	}
+	if p, found := OAuth2Presets[provider]; found {
+		return p
+	}
+	return &OAuth2Preset{}
+}
+
+// claimValue method returns the string value of claim from user info, nested
+// field is denoted by dot notation.
+func claimValue(raw map[string]interface{}, claim string) string {
+	if len(claim) == 0 {
+		return ""
+	}
+	var v interface{} = raw
+	for _, k := range strings.Split(claim, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[k]
+	}
+	if v == nil {
+		return ""
+	}
+	if _, ok := v.(map[string]interface{}); ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
 // just alphabet order, no preferences
 func inferEndpoint(provider string) oauth2.Endpoint {
 	switch provider {
@@ -60,6 +60,10 @@ func New(authSchemeType string) Schemer {
 		return &OAuth2{}
 	case "generic":
 		return &GenericAuth{}
+	case "api_key":
+		return &APIKeyAuth{}
+	case "webauthn":
+		return &WebAuthn{}
 	}
 	return nil
 }
@@ -72,12 +76,12 @@ func passwordAlgorithm(cfg *config.Config, keyPrefix string) (acrypto.PasswordEn
 	var passAlg string
 	pe, _ := cfg.Get(keyPrefix + ".password_encoder")
 	if _, ok := pe.(string); !ok {
-		passAlg = cfg.StringDefault(keyPrefix+".password_encoder.type", "bcrypt")
+		passAlg = cfg.StringDefault(keyPrefix+".password_encoder.type", "argon2id")
 
 		// DEPRECATED, to be removed in v1.0
 		log.Warnf("DEPRECATED: Config '%s.password_encoder.type' is deprecated in v0.9, use '%s.password_encoder = \"%s\"' instead. Deprecated config will not break your functionality, its good to update to latest config.", keyPrefix, keyPrefix, passAlg)
 	} else {
-		passAlg = cfg.StringDefault(keyPrefix+".password_encoder", "bcrypt")
+		passAlg = cfg.StringDefault(keyPrefix+".password_encoder", "argon2id")
 	}
 
 	passwordEncoder := acrypto.PasswordAlgorithm(passAlg)
@@ -0,0 +1,549 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+)
+
+var _ Schemer = (*WebAuthn)(nil)
+
+// WebAuthn Errors
+var (
+	ErrCredentialStoreIsNil      = errors.New("security/scheme: webauthn credential store is nil")
+	ErrWebAuthnMalformed         = errors.New("webauthn: malformed credential response")
+	ErrWebAuthnChallenge         = errors.New("webauthn: challenge mismatch or expired")
+	ErrWebAuthnOrigin            = errors.New("webauthn: origin is not allowed")
+	ErrWebAuthnRelyingParty      = errors.New("webauthn: relying party id mismatch")
+	ErrWebAuthnUserVerification  = errors.New("webauthn: user presence or verification is required")
+	ErrWebAuthnSignature         = errors.New("webauthn: invalid signature")
+	ErrWebAuthnCredentialUnknown = errors.New("webauthn: credential is not registered")
+	ErrWebAuthnCredentialExists  = errors.New("webauthn: credential is already registered")
+	ErrWebAuthnSignCount         = errors.New("webauthn: sign count is not increased, credential might be cloned")
+)
+
+// keyWebAuthnCredential is same as `aah.KeyWebAuthnCredential`.
+const keyWebAuthnCredential = "_aahWebAuthnCredential"
+
+// authenticator data flags
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+)
+
+type (
+	// WebAuthn struct provides aah's OOTB WebAuthn (passkey) auth scheme for
+	// passwordless login. Registration and login ceremony endpoints are
+	// added by aah, credentials are persisted via `CredentialStore`.
+	//
+	// Attestation conveyance is `none`, attestation statement is not
+	// verified. Supported public key algorithms are `ES256`, `RS256` and
+	// `EdDSA`.
+	WebAuthn struct {
+		BaseAuth
+		RPID              string
+		RPName            string
+		Origins           []string
+		Timeout           time.Duration
+		UserVerification  string
+		RegisterBeginURL  string
+		RegisterFinishURL string
+		LoginBeginURL     string
+		LoginFinishURL    string
+		SuccessURL        string
+
+		credentialStore CredentialStore
+	}
+
+	// CredentialStore interface is used to persist the WebAuthn credentials
+	// for scheme `webauthn`. For e.g.: database, cache, etc.
+	CredentialStore interface {
+		// Init method gets called by aah during an application start.
+		Init(appCfg *config.Config) error
+
+		// Credentials method returns all the credentials registered by the
+		// given user.
+		Credentials(userID string) ([]*WebAuthnCredential, error)
+
+		// Credential method returns the credential for given credential ID.
+		// Returns nil if credential does not exists.
+		Credential(id []byte) (*WebAuthnCredential, error)
+
+		// SaveCredential method persists the newly registered credential.
+		SaveCredential(c *WebAuthnCredential) error
+
+		// UpdateSignCount method updates the credential signature counter
+		// after successful login.
+		UpdateSignCount(id []byte, signCount uint32) error
+	}
+
+	// WebAuthnCredential struct holds the registered WebAuthn credential.
+	WebAuthnCredential struct {
+		ID        []byte
+		UserID    string
+		PublicKey []byte // COSE encoded public key
+		SignCount uint32
+		AAGUID    []byte
+		CreatedAt time.Time
+	}
+
+	// WebAuthnEntity struct is relying party and user entity of the
+	// credential creation options.
+	WebAuthnEntity struct {
+		ID          string `json:"id,omitempty"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName,omitempty"`
+	}
+
+	// WebAuthnCredentialDescriptor struct is used to list the credentials in
+	// the options.
+	WebAuthnCredentialDescriptor struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+
+	// WebAuthnCredentialParam struct is credential type and public key
+	// algorithm.
+	WebAuthnCredentialParam struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	}
+
+	// WebAuthnAuthenticatorSelection struct is authenticator requirements of
+	// the credential creation options.
+	WebAuthnAuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	}
+
+	// WebAuthnCreationOptions struct is `PublicKeyCredentialCreationOptions`
+	// for `navigator.credentials.create`. Binary values are base64url encoded.
+	WebAuthnCreationOptions struct {
+		Challenge              string                         `json:"challenge"`
+		RP                     WebAuthnEntity                 `json:"rp"`
+		User                   WebAuthnEntity                 `json:"user"`
+		PubKeyCredParams       []WebAuthnCredentialParam      `json:"pubKeyCredParams"`
+		Timeout                int64                          `json:"timeout"`
+		ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials,omitempty"`
+		AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation            string                         `json:"attestation"`
+	}
+
+	// WebAuthnRequestOptions struct is `PublicKeyCredentialRequestOptions`
+	// for `navigator.credentials.get`. Binary values are base64url encoded.
+	WebAuthnRequestOptions struct {
+		Challenge        string                         `json:"challenge"`
+		RPID             string                         `json:"rpId"`
+		Timeout          int64                          `json:"timeout"`
+		AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials,omitempty"`
+		UserVerification string                         `json:"userVerification"`
+	}
+)
+
+// Init method initializes the WebAuthn authentication scheme from `security.auth_schemes`.
+func (w *WebAuthn) Init(appCfg *config.Config, keyName string) error {
+	w.AppConfig = appCfg
+	w.KeyName = keyName
+	w.KeyPrefix = "security.auth_schemes." + keyName
+	w.Name, _ = w.AppConfig.String(w.ConfigKey("scheme"))
+
+	var found bool
+	if w.RPID, found = w.AppConfig.String(w.ConfigKey("rp_id")); !found {
+		return w.ConfigError("rp_id")
+	}
+	w.RPName = w.AppConfig.StringDefault(w.ConfigKey("rp_name"), w.RPID)
+	w.Origins, _ = w.AppConfig.StringList(w.ConfigKey("origins"))
+	if len(w.Origins) == 0 {
+		w.Origins = []string{"https://" + w.RPID}
+	}
+
+	timeout, err := time.ParseDuration(w.AppConfig.StringDefault(w.ConfigKey("timeout"), "2m"))
+	if err != nil {
+		return fmt.Errorf("%s: '%s' %v", w.KeyName, w.ConfigKey("timeout"), err)
+	}
+	w.Timeout = timeout
+
+	w.UserVerification = w.AppConfig.StringDefault(w.ConfigKey("user_verification"), "preferred")
+	if !ess.IsSliceContainsString([]string{"required", "preferred", "discouraged"}, w.UserVerification) {
+		return fmt.Errorf("%s: '%s' value should be one of required, preferred or discouraged", w.KeyName, w.ConfigKey("user_verification"))
+	}
+
+	w.RegisterBeginURL = w.AppConfig.StringDefault(w.ConfigKey("url.register_begin"), createDefaultURL(keyName, "register/begin"))
+	w.RegisterFinishURL = w.AppConfig.StringDefault(w.ConfigKey("url.register_finish"), createDefaultURL(keyName, "register/finish"))
+	w.LoginBeginURL = w.AppConfig.StringDefault(w.ConfigKey("url.login_begin"), createDefaultURL(keyName, "login/begin"))
+	w.LoginFinishURL = w.AppConfig.StringDefault(w.ConfigKey("url.login_finish"), createDefaultURL(keyName, "login/finish"))
+	w.SuccessURL = w.AppConfig.StringDefault(w.ConfigKey("url.success"), "/")
+
+	return nil
+}
+
+// SetCredentialStore method assigns the given `CredentialStore` instance to
+// auth scheme.
+func (w *WebAuthn) SetCredentialStore(store CredentialStore) error {
+	if store == nil {
+		return ErrCredentialStoreIsNil
+	}
+	w.credentialStore = store
+	return w.credentialStore.Init(w.AppConfig)
+}
+
+// CredentialStore method returns the registered `CredentialStore` instance.
+func (w *WebAuthn) CredentialStore() CredentialStore {
+	return w.credentialStore
+}
+
+// RegistrationOptions method creates the credential creation options for
+// given user, user's existing credentials are excluded. It returns the
+// options and state, state has to be kept in the session until
+// `FinishRegistration`.
+func (w *WebAuthn) RegistrationOptions(userID, userName, displayName string) (*WebAuthnCreationOptions, string, error) {
+	if w.credentialStore == nil {
+		return nil, "", ErrCredentialStoreIsNil
+	}
+	creds, err := w.credentialStore.Credentials(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	challenge, state := w.newChallenge()
+	opts := &WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP:        WebAuthnEntity{ID: w.RPID, Name: w.RPName},
+		User:      WebAuthnEntity{ID: b64Encode([]byte(userID)), Name: userName, DisplayName: displayName},
+		PubKeyCredParams: []WebAuthnCredentialParam{
+			{Type: "public-key", Alg: coseAlgES256},
+			{Type: "public-key", Alg: coseAlgEdDSA},
+			{Type: "public-key", Alg: coseAlgRS256},
+		},
+		Timeout:                w.Timeout.Milliseconds(),
+		ExcludeCredentials:     credentialDescriptors(creds),
+		AuthenticatorSelection: WebAuthnAuthenticatorSelection{ResidentKey: "preferred", UserVerification: w.UserVerification},
+		Attestation:            "none",
+	}
+	return opts, state, nil
+}
+
+// FinishRegistration method verifies the attestation response of
+// `navigator.credentials.create` for the given state and user, then saves the
+// credential via `CredentialStore`.
+func (w *WebAuthn) FinishRegistration(state, userID string, body []byte) (*WebAuthnCredential, error) {
+	if w.credentialStore == nil {
+		return nil, ErrCredentialStoreIsNil
+	}
+	resp, err := parseCredentialResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.verifyClientData(resp.clientData, "webauthn.create", state); err != nil {
+		return nil, err
+	}
+
+	v, _, err := decodeCBOR(resp.attestationObject)
+	if err != nil {
+		return nil, ErrWebAuthnMalformed
+	}
+	attObj, _ := v.(map[interface{}]interface{})
+	rawAuthData, _ := attObj["authData"].([]byte)
+	ad, err := w.verifyAuthData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if ad.flags&flagAttestedCredData == 0 {
+		return nil, ErrWebAuthnMalformed
+	}
+	if _, err = parseCOSEKey(ad.publicKey); err != nil {
+		return nil, err
+	}
+
+	existing, err := w.credentialStore.Credential(ad.credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrWebAuthnCredentialExists
+	}
+
+	cred := &WebAuthnCredential{
+		ID:        ad.credentialID,
+		UserID:    userID,
+		PublicKey: ad.publicKey,
+		SignCount: ad.signCount,
+		AAGUID:    ad.aaguid,
+		CreatedAt: time.Now(),
+	}
+	if err = w.credentialStore.SaveCredential(cred); err != nil {
+		return nil, err
+	}
+	log.Infof("%s: WebAuthn credential is registered for '%s'", w.KeyName, userID)
+	return cred, nil
+}
+
+// LoginOptions method creates the credential request options. If user ID is
+// given then user's credentials are listed otherwise browser discovers the
+// passkey. It returns the options and state, state has to be kept in the
+// session until `FinishLogin`.
+func (w *WebAuthn) LoginOptions(userID string) (*WebAuthnRequestOptions, string, error) {
+	if w.credentialStore == nil {
+		return nil, "", ErrCredentialStoreIsNil
+	}
+
+	var creds []*WebAuthnCredential
+	if len(userID) > 0 {
+		var err error
+		if creds, err = w.credentialStore.Credentials(userID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	challenge, state := w.newChallenge()
+	return &WebAuthnRequestOptions{
+		Challenge:        challenge,
+		RPID:             w.RPID,
+		Timeout:          w.Timeout.Milliseconds(),
+		AllowCredentials: credentialDescriptors(creds),
+		UserVerification: w.UserVerification,
+	}, state, nil
+}
+
+// FinishLogin method verifies the assertion response of
+// `navigator.credentials.get` for the given state and updates the credential
+// sign count via `CredentialStore`.
+func (w *WebAuthn) FinishLogin(state string, body []byte) (*WebAuthnCredential, error) {
+	if w.credentialStore == nil {
+		return nil, ErrCredentialStoreIsNil
+	}
+	resp, err := parseCredentialResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := w.credentialStore.Credential(resp.rawID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, ErrWebAuthnCredentialUnknown
+	}
+	if len(resp.userHandle) > 0 && string(resp.userHandle) != cred.UserID {
+		return nil, ErrWebAuthnCredentialUnknown
+	}
+
+	if err = w.verifyClientData(resp.clientData, "webauthn.get", state); err != nil {
+		return nil, err
+	}
+	ad, err := w.verifyAuthData(resp.authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(resp.clientData)
+	if err = verifyCOSESignature(cred.PublicKey, append(resp.authenticatorData, clientDataHash[:]...), resp.signature); err != nil {
+		log.Warnf("%s: %v", w.KeyName, err)
+		return nil, err
+	}
+
+	// Sign count zero means authenticator does not support it, for e.g.: synced passkeys
+	if (ad.signCount != 0 || cred.SignCount != 0) && ad.signCount <= cred.SignCount {
+		log.Warnf("%s: WebAuthn credential of '%s' sign count is not increased", w.KeyName, cred.UserID)
+		return nil, ErrWebAuthnSignCount
+	}
+	if ad.signCount != 0 {
+		if err = w.credentialStore.UpdateSignCount(cred.ID, ad.signCount); err != nil {
+			return nil, err
+		}
+		cred.SignCount = ad.signCount
+	}
+	return cred, nil
+}
+
+// Principal method returns the Subject principals for the verified
+// credential. It calls the registered interface `SubjectPrincipalProvider`
+// if configured otherwise primary principal is credential user ID.
+func (w *WebAuthn) Principal(keyName string, v ess.Valuer) ([]*authc.Principal, error) {
+	if w.principalProvider != nil {
+		return w.principalProvider.Principal(keyName, v)
+	}
+	cred, ok := v.Get(keyWebAuthnCredential).(*WebAuthnCredential)
+	if !ok || len(cred.UserID) == 0 {
+		return nil, authc.ErrPrincipalIsNil
+	}
+	return []*authc.Principal{{Realm: w.KeyName, Claim: "UserID", Value: cred.UserID, IsPrimary: true}}, nil
+}
+
+// DoAuthorizationInfo method calls registered `Authorizer` with
+// authentication information, returns empty authorization information if
+// `Authorizer` is not registered.
+func (w *WebAuthn) DoAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *authz.AuthorizationInfo {
+	if w.authorizer == nil {
+		return authz.NewAuthorizationInfo()
+	}
+	return w.BaseAuth.DoAuthorizationInfo(authcInfo)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+type credentialResponse struct {
+	rawID             []byte
+	clientData        []byte
+	attestationObject []byte
+	authenticatorData []byte
+	signature         []byte
+	userHandle        []byte
+}
+
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+// newChallenge method returns the challenge and its state, state is
+// challenge with expiry time.
+func (w *WebAuthn) newChallenge() (string, string) {
+	challenge := b64Encode(ess.GenerateSecureRandomKey(32))
+	return challenge, challenge + "." + strconv.FormatInt(time.Now().Add(w.Timeout).Unix(), 10)
+}
+
+func (w *WebAuthn) verifyClientData(raw []byte, ceremony, state string) error {
+	var cd struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return ErrWebAuthnMalformed
+	}
+	if cd.Type != ceremony {
+		return ErrWebAuthnMalformed
+	}
+
+	idx := strings.LastIndexByte(state, '.')
+	if idx <= 0 {
+		return ErrWebAuthnChallenge
+	}
+	expiresAt, err := strconv.ParseInt(state[idx+1:], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimRight(cd.Challenge, "=")), []byte(state[:idx])) != 1 {
+		return ErrWebAuthnChallenge
+	}
+	if !ess.IsSliceContainsString(w.Origins, cd.Origin) {
+		log.Warnf("%s: WebAuthn origin '%s' is not allowed", w.KeyName, cd.Origin)
+		return ErrWebAuthnOrigin
+	}
+	return nil
+}
+
+func (w *WebAuthn) verifyAuthData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, ErrWebAuthnMalformed
+	}
+	rpIDHash := sha256.Sum256([]byte(w.RPID))
+	if !bytes.Equal(raw[:32], rpIDHash[:]) {
+		return nil, ErrWebAuthnRelyingParty
+	}
+
+	ad := &authenticatorData{flags: raw[32], signCount: binary.BigEndian.Uint32(raw[33:37])}
+	if ad.flags&flagUserPresent == 0 ||
+		(w.UserVerification == "required" && ad.flags&flagUserVerified == 0) {
+		return nil, ErrWebAuthnUserVerification
+	}
+
+	if ad.flags&flagAttestedCredData != 0 {
+		rest := raw[37:]
+		if len(rest) < 18 {
+			return nil, ErrWebAuthnMalformed
+		}
+		ad.aaguid = append([]byte(nil), rest[:16]...)
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < idLen {
+			return nil, ErrWebAuthnMalformed
+		}
+		ad.credentialID = append([]byte(nil), rest[:idLen]...)
+		_, n, err := decodeCBOR(rest[idLen:])
+		if err != nil {
+			return nil, ErrWebAuthnMalformed
+		}
+		ad.publicKey = append([]byte(nil), rest[idLen:idLen+n]...)
+	}
+	return ad, nil
+}
+
+func parseCredentialResponse(body []byte) (*credentialResponse, error) {
+	var cr struct {
+		RawID    string `json:"rawId"`
+		Type     string `json:"type"`
+		Response struct {
+			ClientDataJSON    string `json:"clientDataJSON"`
+			AttestationObject string `json:"attestationObject"`
+			AuthenticatorData string `json:"authenticatorData"`
+			Signature         string `json:"signature"`
+			UserHandle        string `json:"userHandle"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &cr); err != nil || cr.Type != "public-key" {
+		return nil, ErrWebAuthnMalformed
+	}
+
+	resp := &credentialResponse{}
+	for _, f := range []struct {
+		dst *[]byte
+		src string
+	}{
+		{&resp.rawID, cr.RawID},
+		{&resp.clientData, cr.Response.ClientDataJSON},
+		{&resp.attestationObject, cr.Response.AttestationObject},
+		{&resp.authenticatorData, cr.Response.AuthenticatorData},
+		{&resp.signature, cr.Response.Signature},
+		{&resp.userHandle, cr.Response.UserHandle},
+	} {
+		b, err := b64Decode(f.src)
+		if err != nil {
+			return nil, ErrWebAuthnMalformed
+		}
+		*f.dst = b
+	}
+	if len(resp.clientData) == 0 {
+		return nil, ErrWebAuthnMalformed
+	}
+	return resp, nil
+}
+
+func credentialDescriptors(creds []*WebAuthnCredential) []WebAuthnCredentialDescriptor {
+	var descriptors []WebAuthnCredentialDescriptor
+	for _, c := range creds {
+		descriptors = append(descriptors, WebAuthnCredentialDescriptor{Type: "public-key", ID: b64Encode(c.ID)})
+	}
+	return descriptors
+}
+
+func b64Encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func b64Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
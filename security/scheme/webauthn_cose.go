@@ -0,0 +1,187 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+)
+
+// COSE algorithms supported for WebAuthn credential public key
+const (
+	coseAlgES256 = -7
+	coseAlgEdDSA = -8
+	coseAlgRS256 = -257
+)
+
+var errCBORMalformed = errors.New("webauthn: malformed cbor data")
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// COSE key
+//___________________________________
+
+// verifyCOSESignature method verifies the signature of given data using COSE
+// encoded public key (RFC 8152), supports `ES256`, `RS256` and `EdDSA`.
+func verifyCOSESignature(coseKey, data, sig []byte) error {
+	key, err := parseCOSEKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	switch key[int64(3)].(int64) {
+	case coseAlgES256:
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return errors.New("webauthn: invalid ec public key")
+		}
+		sum := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(pub, sum[:], sig) {
+			return ErrWebAuthnSignature
+		}
+	case coseAlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		sum := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) != nil {
+			return ErrWebAuthnSignature
+		}
+	case coseAlgEdDSA:
+		x, _ := key[int64(-2)].([]byte)
+		if len(x) != ed25519.PublicKeySize || !ed25519.Verify(ed25519.PublicKey(x), data, sig) {
+			return ErrWebAuthnSignature
+		}
+	}
+	return nil
+}
+
+// parseCOSEKey method decodes the COSE key and validates its algorithm is
+// supported.
+func parseCOSEKey(coseKey []byte) (map[interface{}]interface{}, error) {
+	v, _, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, errCBORMalformed
+	}
+	switch key[int64(3)] {
+	case int64(coseAlgES256), int64(coseAlgRS256), int64(coseAlgEdDSA):
+		return key, nil
+	}
+	return nil, errors.New("webauthn: unsupported public key algorithm")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// CBOR decoder
+//___________________________________
+
+// decodeCBOR method decodes the CBOR (RFC 7049) data item, its minimal
+// decoder for WebAuthn attestation object and COSE key. Integers are decoded
+// as `int64`, byte strings as `[]byte`, text strings as `string` and maps as
+// `map[interface{}]interface{}`. It returns the number of bytes consumed.
+func decodeCBOR(b []byte) (interface{}, int, error) {
+	if len(b) == 0 {
+		return nil, 0, errCBORMalformed
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+	n, hl, err := cborArg(b, info)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch major {
+	case 0: // unsigned integer
+		if n > math.MaxInt64 {
+			return nil, 0, errCBORMalformed
+		}
+		return int64(n), hl, nil
+	case 1: // negative integer
+		if n > math.MaxInt64 {
+			return nil, 0, errCBORMalformed
+		}
+		return -1 - int64(n), hl, nil
+	case 2, 3: // byte string, text string
+		if uint64(len(b)-hl) < n {
+			return nil, 0, errCBORMalformed
+		}
+		v := b[hl : hl+int(n)]
+		if major == 3 {
+			return string(v), hl + int(n), nil
+		}
+		return append([]byte(nil), v...), hl + int(n), nil
+	case 4: // array
+		pos := hl
+		var arr []interface{}
+		for i := uint64(0); i < n; i++ {
+			v, c, err := decodeCBOR(b[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			arr = append(arr, v)
+			pos += c
+		}
+		return arr, pos, nil
+	case 5: // map
+		pos := hl
+		m := make(map[interface{}]interface{})
+		for i := uint64(0); i < n; i++ {
+			k, c, err := decodeCBOR(b[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			pos += c
+			if _, ok := k.([]byte); ok {
+				return nil, 0, errCBORMalformed
+			}
+			v, c, err := decodeCBOR(b[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			pos += c
+			m[k] = v
+		}
+		return m, pos, nil
+	case 7: // simple values
+		switch info {
+		case 20:
+			return false, 1, nil
+		case 21:
+			return true, 1, nil
+		case 22, 23:
+			return nil, 1, nil
+		}
+	}
+	return nil, 0, errCBORMalformed
+}
+
+// cborArg method returns the argument value of data item and header length.
+func cborArg(b []byte, info byte) (uint64, int, error) {
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info == 24 && len(b) >= 2:
+		return uint64(b[1]), 2, nil
+	case info == 25 && len(b) >= 3:
+		return uint64(binary.BigEndian.Uint16(b[1:])), 3, nil
+	case info == 26 && len(b) >= 5:
+		return uint64(binary.BigEndian.Uint32(b[1:])), 5, nil
+	case info == 27 && len(b) >= 9:
+		return binary.BigEndian.Uint64(b[1:]), 9, nil
+	}
+	return 0, 0, errCBORMalformed
+}
@@ -0,0 +1,343 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/security/authc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCredentialStore struct {
+	creds []*WebAuthnCredential
+}
+
+func (s *testCredentialStore) Init(appCfg *config.Config) error { return nil }
+
+func (s *testCredentialStore) Credentials(userID string) ([]*WebAuthnCredential, error) {
+	var creds []*WebAuthnCredential
+	for _, c := range s.creds {
+		if c.UserID == userID {
+			creds = append(creds, c)
+		}
+	}
+	return creds, nil
+}
+
+func (s *testCredentialStore) Credential(id []byte) (*WebAuthnCredential, error) {
+	for _, c := range s.creds {
+		if bytes.Equal(c.ID, id) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *testCredentialStore) SaveCredential(c *WebAuthnCredential) error {
+	s.creds = append(s.creds, c)
+	return nil
+}
+
+func (s *testCredentialStore) UpdateSignCount(id []byte, signCount uint32) error {
+	c, _ := s.Credential(id)
+	c.SignCount = signCount
+	return nil
+}
+
+// testAuthenticator mimics the browser and authenticator of WebAuthn ceremony.
+type testAuthenticator struct {
+	rpID, origin string
+	credID       []byte
+	signer       crypto.Signer
+	signCount    uint32
+}
+
+func (ta *testAuthenticator) coseKey() []byte {
+	switch pub := ta.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		k := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+		k = append(k, pub.X.FillBytes(make([]byte, 32))...)
+		k = append(k, 0x22, 0x58, 0x20)
+		return append(k, pub.Y.FillBytes(make([]byte, 32))...)
+	case ed25519.PublicKey:
+		k := []byte{0xa4, 0x01, 0x01, 0x03, 0x27, 0x20, 0x06, 0x21, 0x58, 0x20}
+		return append(k, pub...)
+	}
+	return nil
+}
+
+func (ta *testAuthenticator) authData(flags byte, attested bool) []byte {
+	h := sha256.Sum256([]byte(ta.rpID))
+	ad := append(h[:], flags)
+	ad = binary.BigEndian.AppendUint32(ad, ta.signCount)
+	if attested {
+		ad = append(ad, make([]byte, 16)...)
+		ad = binary.BigEndian.AppendUint16(ad, uint16(len(ta.credID)))
+		ad = append(append(ad, ta.credID...), ta.coseKey()...)
+	}
+	return ad
+}
+
+func (ta *testAuthenticator) clientData(typ, challenge string) []byte {
+	b, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": ta.origin})
+	return b
+}
+
+func (ta *testAuthenticator) create(challenge string) []byte {
+	authData := ta.authData(0x45, true)
+	attObj := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0}
+	attObj = append(attObj, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x58, byte(len(authData)))
+	attObj = append(attObj, authData...)
+	return ta.response(map[string]string{
+		"clientDataJSON":    b64Encode(ta.clientData("webauthn.create", challenge)),
+		"attestationObject": b64Encode(attObj),
+	})
+}
+
+func (ta *testAuthenticator) get(challenge, userHandle string) []byte {
+	ta.signCount++
+	authData := ta.authData(0x05, false)
+	clientData := ta.clientData("webauthn.get", challenge)
+	cdHash := sha256.Sum256(clientData)
+	signed := append(append([]byte(nil), authData...), cdHash[:]...)
+
+	var sig []byte
+	if _, ok := ta.signer.(ed25519.PrivateKey); ok {
+		sig, _ = ta.signer.Sign(rand.Reader, signed, crypto.Hash(0))
+	} else {
+		sum := sha256.Sum256(signed)
+		sig, _ = ta.signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	return ta.response(map[string]string{
+		"clientDataJSON":    b64Encode(clientData),
+		"authenticatorData": b64Encode(authData),
+		"signature":         b64Encode(sig),
+		"userHandle":        b64Encode([]byte(userHandle)),
+	})
+}
+
+func (ta *testAuthenticator) response(resp map[string]string) []byte {
+	b, _ := json.Marshal(map[string]interface{}{
+		"id":       b64Encode(ta.credID),
+		"rawId":    b64Encode(ta.credID),
+		"type":     "public-key",
+		"response": resp,
+	})
+	return b
+}
+
+func TestSchemeWebAuthn(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	security {
+	  auth_schemes {
+	    passkey_auth {
+	      scheme = "webauthn"
+	      rp_id = "example.com"
+	      rp_name = "Example"
+	      origins = ["https://example.com", "https://www.example.com"]
+	      user_verification = "required"
+	    }
+	  }
+	}
+	`)
+
+	wa := New("webauthn").(*WebAuthn)
+	assert.Nil(t, wa.Init(cfg, "passkey_auth"))
+	assert.Equal(t, "webauthn", wa.Scheme())
+	assert.Equal(t, "Example", wa.RPName)
+	assert.Equal(t, 2*time.Minute, wa.Timeout)
+	assert.Equal(t, "/passkey-auth/register/begin", wa.RegisterBeginURL)
+	assert.Equal(t, "/passkey-auth/login/finish", wa.LoginFinishURL)
+
+	// Credential store is not configured
+	_, _, err := wa.RegistrationOptions("jeeva", "jeeva", "Jeeva")
+	assert.Equal(t, ErrCredentialStoreIsNil, err)
+	_, err = wa.FinishLogin("", nil)
+	assert.Equal(t, ErrCredentialStoreIsNil, err)
+	assert.Equal(t, ErrCredentialStoreIsNil, wa.SetCredentialStore(nil))
+
+	store := &testCredentialStore{}
+	assert.Nil(t, wa.SetCredentialStore(store))
+	assert.Equal(t, store, wa.CredentialStore())
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ta := &testAuthenticator{rpID: "example.com", origin: "https://example.com", credID: []byte("cred-ec-1"), signer: ecKey}
+
+	// Registration
+	opts, state, err := wa.RegistrationOptions("jeeva", "jeeva", "Jeeva")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", opts.RP.ID)
+	assert.Equal(t, b64Encode([]byte("jeeva")), opts.User.ID)
+	assert.Equal(t, "required", opts.AuthenticatorSelection.UserVerification)
+	assert.Equal(t, int64(120000), opts.Timeout)
+	assert.Len(t, opts.ExcludeCredentials, 0)
+
+	_, err = wa.FinishRegistration(state, "jeeva", ta.create("other-challenge"))
+	assert.Equal(t, ErrWebAuthnChallenge, err)
+	_, err = wa.FinishRegistration(state, "jeeva", []byte(`{"type": "public-key"}`))
+	assert.Equal(t, ErrWebAuthnMalformed, err)
+
+	cred, err := wa.FinishRegistration(state, "jeeva", ta.create(opts.Challenge))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("cred-ec-1"), cred.ID)
+	assert.Equal(t, "jeeva", cred.UserID)
+	assert.Len(t, store.creds, 1)
+
+	_, err = wa.FinishRegistration(state, "jeeva", ta.create(opts.Challenge))
+	assert.Equal(t, ErrWebAuthnCredentialExists, err)
+
+	opts, _, _ = wa.RegistrationOptions("jeeva", "jeeva", "Jeeva")
+	assert.Equal(t, []WebAuthnCredentialDescriptor{{Type: "public-key", ID: b64Encode([]byte("cred-ec-1"))}}, opts.ExcludeCredentials)
+
+	// EdDSA credential
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ta2 := &testAuthenticator{rpID: "example.com", origin: "https://www.example.com", credID: []byte("cred-ed-1"), signer: edKey}
+	opts, state, _ = wa.RegistrationOptions("jeeva", "jeeva", "Jeeva")
+	_, err = wa.FinishRegistration(state, "jeeva", ta2.create(opts.Challenge))
+	assert.Nil(t, err)
+
+	// Login
+	lopts, state, err := wa.LoginOptions("jeeva")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", lopts.RPID)
+	assert.Len(t, lopts.AllowCredentials, 2)
+
+	cred, err = wa.FinishLogin(state, ta.get(lopts.Challenge, "jeeva"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), cred.SignCount)
+
+	principals, err := wa.Principal("passkey_auth", testValuer{keyWebAuthnCredential: cred})
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva", principals[0].Value)
+	assert.True(t, principals[0].IsPrimary)
+	_, err = wa.Principal("passkey_auth", testValuer{})
+	assert.Equal(t, authc.ErrPrincipalIsNil, err)
+	assert.NotNil(t, wa.DoAuthorizationInfo(&authc.AuthenticationInfo{}))
+
+	// discoverable credential, EdDSA
+	lopts, state, _ = wa.LoginOptions("")
+	assert.Len(t, lopts.AllowCredentials, 0)
+	_, err = wa.FinishLogin(state, ta2.get(lopts.Challenge, ""))
+	assert.Nil(t, err)
+
+	// cloned authenticator, sign count is not increased
+	ta.signCount = 0
+	_, err = wa.FinishLogin(state, ta.get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnSignCount, err)
+	ta.signCount = 5
+
+	// tampered signature, user handle mismatch, unknown credential
+	resp := ta.get(lopts.Challenge, "")
+	resp = bytes.Replace(resp, []byte(`"signature":"`), []byte(`"signature":"AA`), 1)
+	_, err = wa.FinishLogin(state, resp)
+	assert.NotNil(t, err)
+	_, err = wa.FinishLogin(state, ta.get(lopts.Challenge, "someone"))
+	assert.Equal(t, ErrWebAuthnCredentialUnknown, err)
+	_, err = wa.FinishLogin(state, (&testAuthenticator{rpID: "example.com", credID: []byte("unknown"), signer: ecKey}).get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnCredentialUnknown, err)
+
+	// origin, relying party, user verification and expired challenge
+	ta.origin = "https://evil.example.org"
+	_, err = wa.FinishLogin(state, ta.get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnOrigin, err)
+	ta.origin, ta.rpID = "https://example.com", "evil.example.org"
+	_, err = wa.FinishLogin(state, ta.get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnRelyingParty, err)
+	ta.rpID = "example.com"
+	_, err = wa.verifyAuthData(ta.authData(0x01, false))
+	assert.Equal(t, ErrWebAuthnUserVerification, err)
+	_, err = wa.FinishLogin(lopts.Challenge+".1", ta.get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnChallenge, err)
+	_, err = wa.FinishLogin("", ta.get(lopts.Challenge, ""))
+	assert.Equal(t, ErrWebAuthnChallenge, err)
+}
+
+func TestSchemeWebAuthnInitError(t *testing.T) {
+	testcases := []struct {
+		label, config string
+		err           error
+	}{
+		{
+			label: "RP ID missing",
+			config: `
+			security {
+			  auth_schemes {
+			    passkey_auth {
+			      scheme = "webauthn"
+			    }
+			  }
+			}`,
+			err: errors.New("passkey_auth: config 'security.auth_schemes.passkey_auth.rp_id' is required"),
+		},
+		{
+			label: "Invalid user verification",
+			config: `
+			security {
+			  auth_schemes {
+			    passkey_auth {
+			      scheme = "webauthn"
+			      rp_id = "example.com"
+			      user_verification = "always"
+			    }
+			  }
+			}`,
+			err: errors.New("passkey_auth: 'security.auth_schemes.passkey_auth.user_verification' value should be one of required, preferred or discouraged"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			cfg, _ := config.ParseString(tc.config)
+			assert.Equal(t, tc.err, (&WebAuthn{}).Init(cfg, "passkey_auth"))
+		})
+	}
+
+	cfg, _ := config.ParseString(`
+	security {
+	  auth_schemes {
+	    passkey_auth {
+	      scheme = "webauthn"
+	      rp_id = "example.com"
+	    }
+	  }
+	}`)
+	wa := &WebAuthn{}
+	assert.Nil(t, wa.Init(cfg, "passkey_auth"))
+	assert.Equal(t, []string{"https://example.com"}, wa.Origins)
+	assert.Equal(t, "example.com", wa.RPName)
+	assert.Equal(t, "preferred", wa.UserVerification)
+}
+
+func TestSchemeWebAuthnCBOR(t *testing.T) {
+	v, n, err := decodeCBOR([]byte{0x83, 0x01, 0x38, 0x18, 0x82, 0xf5, 0xf6, 0xff})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, n)
+	assert.Equal(t, []interface{}{int64(1), int64(-25), []interface{}{true, nil}}, v)
+
+	v, _, err = decodeCBOR([]byte{0xa1, 0x61, 'a', 0x19, 0x01, 0x00})
+	assert.Nil(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"a": int64(256)}, v)
+
+	for _, b := range [][]byte{{}, {0x42, 0x01}, {0x19, 0x01}, {0xa1, 0x41, 0x01, 0x01}, {0xf8}} {
+		_, _, err = decodeCBOR(b)
+		assert.Equal(t, errCBORMalformed, err)
+	}
+
+	_, err = parseCOSEKey([]byte{0xa1, 0x03, 0x38, 0x22})
+	assert.Equal(t, errors.New("webauthn: unsupported public key algorithm"), err)
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"aahframe.work/security/acrypto"
 	"aahframe.work/security/anticsrf"
 	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
 	"aahframe.work/security/scheme"
 	"aahframe.work/security/session"
 )
@@ -81,6 +83,8 @@ type (
 		SessionManager *session.Manager
 		SecureHeaders  *SecureHeaders
 		AntiCSRF       *anticsrf.AntiCSRF
+		Guest          *Guest
+		LockoutHandler authc.LockoutHandler
 		appCfg         *config.Config
 		authSchemes    map[string]scheme.Schemer
 	}
@@ -97,6 +101,16 @@ type (
 
 		Common map[string]string
 	}
+
+	// Guest holds the guest principal and authorization information for
+	// unauthenticated requests based on config `security.guest.*` from
+	// `security.conf`. It's nil if guest is not enabled.
+	Guest struct {
+		Principal   string
+		Roles       []string
+		Permissions []string
+		LazySession bool
+	}
 )
 
 // Init method initialize the application security configuration `security { ... }`.
@@ -110,12 +124,20 @@ func (m *Manager) Init(appCfg *config.Config) error {
 		return err
 	}
 
+	// Initializing password policy
+	if err = acrypto.InitPasswordPolicy(m.appCfg); err != nil {
+		return err
+	}
+
 	// Initialize Secure Headers
 	m.initializeSecureHeaders()
 	Bcrypt = acrypto.PasswordAlgorithm("bcrypt")
 	Scrypt = acrypto.PasswordAlgorithm("scrypt")
 	Pbkdf2 = acrypto.PasswordAlgorithm("pbkdf2")
 
+	// Initialize Guest
+	m.initializeGuest()
+
 	// Initialize Anti-CSRF
 	if m.AntiCSRF, err = anticsrf.New(m.appCfg); err != nil {
 		return err
@@ -178,10 +200,59 @@ func (m *Manager) AuthSchemes() map[string]scheme.Schemer {
 	return m.authSchemes
 }
 
+// OAuth2Schemes method returns the configured OAuth2 auth schemes sorted by
+// key name. Login and callback routes are generated per OAuth2 scheme, use it
+// to render the multi-provider login page, for e.g.: `Provider`, `LoginURL`.
+func (m *Manager) OAuth2Schemes() []*scheme.OAuth2 {
+	var schemes []*scheme.OAuth2
+	for _, s := range m.authSchemes {
+		if o, ok := s.(*scheme.OAuth2); ok {
+			schemes = append(schemes, o)
+		}
+	}
+	sort.Slice(schemes, func(i, j int) bool { return schemes[i].Key() < schemes[j].Key() })
+	return schemes
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Guest methods
+//___________________________________
+
+// Populate method populates the guest principal and authorization
+// information into given unauthenticated subject, so that authorization
+// checks and view functions treats the anonymous user uniformly.
+func (g *Guest) Populate(s *Subject) {
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals,
+		&authc.Principal{Realm: GuestRealm, Claim: "Guest", Value: g.Principal, IsPrimary: true})
+	s.AuthenticationInfo = authcInfo
+	s.AuthorizationInfo = authz.NewAuthorizationInfo().
+		AddRole(g.Roles...).
+		AddPermissionString(g.Permissions...)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Manager Unexported methods
 //___________________________________
 
+func (m *Manager) initializeGuest() {
+	keyPrefix := "security.guest."
+	if !m.appCfg.BoolDefault(keyPrefix+"enable", false) {
+		return
+	}
+
+	m.Guest = &Guest{
+		Principal:   m.appCfg.StringDefault(keyPrefix+"principal", "guest"),
+		LazySession: m.appCfg.BoolDefault(keyPrefix+"lazy_session", false),
+	}
+	if roles, found := m.appCfg.StringList(keyPrefix + "roles"); found {
+		m.Guest.Roles = roles
+	} else {
+		m.Guest.Roles = []string{"guest"}
+	}
+	m.Guest.Permissions, _ = m.appCfg.StringList(keyPrefix + "permissions")
+}
+
 func (m *Manager) initializeSecureHeaders() {
 	keyPrefix := "security.http_header."
 	if !m.appCfg.BoolDefault(keyPrefix+"enable", true) {
@@ -284,4 +355,5 @@ func init() {
 	gob.Register(&authc.AuthenticationInfo{})
 	gob.Register(&authc.Principal{})
 	gob.Register(make([]authc.Principal, 0))
+	gob.Register(make([]*authc.Principal, 0))
 }
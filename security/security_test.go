@@ -53,6 +53,67 @@ func TestSecurityInit(t *testing.T) {
 	assert.True(t, sec.SecureHeaders.PKPReportOnly)
 }
 
+func TestSecurityOAuth2Schemes(t *testing.T) {
+	sec := New()
+	assert.Nil(t, sec.OAuth2Schemes())
+
+	_ = sec.AddAuthScheme("google_auth", &scheme.OAuth2{BaseAuth: scheme.BaseAuth{KeyName: "google_auth"}, Provider: "google"})
+	_ = sec.AddAuthScheme("form_auth", &scheme.FormAuth{})
+	_ = sec.AddAuthScheme("github_auth", &scheme.OAuth2{BaseAuth: scheme.BaseAuth{KeyName: "github_auth"}, Provider: "github"})
+
+	schemes := sec.OAuth2Schemes()
+	assert.Equal(t, 2, len(schemes))
+	assert.Equal(t, "github", schemes[0].Provider)
+	assert.Equal(t, "google", schemes[1].Provider)
+}
+
+func TestSecurityGuest(t *testing.T) {
+	sec := New()
+	assert.Nil(t, sec.Init(config.NewEmpty()))
+	assert.Nil(t, sec.Guest)
+
+	cfg, _ := config.ParseString(`
+	security {
+	  guest {
+	    enable = true
+	    roles = ["guest", "shopper"]
+	    permissions = ["catalog:read"]
+	    lazy_session = true
+	  }
+	}`)
+	sec = New()
+	assert.Nil(t, sec.Init(cfg))
+	assert.Equal(t, "guest", sec.Guest.Principal)
+	assert.True(t, sec.Guest.LazySession)
+
+	subject := AcquireSubject()
+	defer ReleaseSubject(subject)
+	assert.False(t, subject.IsGuest())
+
+	sec.Guest.Populate(subject)
+	assert.True(t, subject.IsGuest())
+	assert.Equal(t, "guest", subject.PrimaryPrincipal().Value)
+	assert.Equal(t, GuestRealm, subject.PrimaryPrincipal().Realm)
+	assert.True(t, subject.HasAllRoles("guest", "shopper"))
+	assert.True(t, subject.IsPermitted("catalog:read"))
+	assert.False(t, subject.IsPermitted("catalog:write"))
+
+	subject.Session = sec.SessionManager.NewSession()
+	subject.Session.IsAuthenticated = true
+	assert.False(t, subject.IsGuest())
+
+	cfg, _ = config.ParseString(`
+	security {
+	  guest {
+	    enable = true
+	  }
+	}`)
+	sec = New()
+	assert.Nil(t, sec.Init(cfg))
+	assert.Equal(t, []string{"guest"}, sec.Guest.Roles)
+	assert.False(t, sec.Guest.LazySession)
+}
+
 func TestSecurityInitError(t *testing.T) {
 	cfg, err := config.ParseString(`
 		security {
@@ -98,3 +98,33 @@ func testSessionStoreSave(t *testing.T, cfgStr string) {
 	assert.Equal(t, true, resultSession.GetBool("my-key-5"))
 	t.Log(resultSession.String())
 }
+
+func TestSessionCookieDomain(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  session {
+	    mode = "stateful"
+	    domain = "example.com"
+	  }
+	}
+  `)
+
+	s := m.NewSession()
+	w := httptest.NewRecorder()
+	assert.Nil(t, m.SaveSession(w, s))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "Domain=example.com")
+
+	s.SetCookieDomain("acme.example.com")
+	w = httptest.NewRecorder()
+	assert.Nil(t, m.SaveSession(w, s))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "Domain=acme.example.com")
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, m.DeleteSession(w, s))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "Domain=acme.example.com")
+
+	s.Reset()
+	w = httptest.NewRecorder()
+	assert.Nil(t, m.SaveSession(w, s))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "Domain=example.com")
+}
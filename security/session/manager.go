@@ -126,11 +126,24 @@ func NewManager(appCfg *config.Config) (*Manager, error) {
 		return nil, err
 	}
 
+	// Idle and absolute timeout
+	if m.idleTimeout, err = toDuration(m.cfg, keyPrefix+".idle_timeout"); err != nil {
+		return nil, err
+	}
+	if m.absoluteTimeout, err = toDuration(m.cfg, keyPrefix+".absolute_timeout"); err != nil {
+		return nil, err
+	}
+
 	// Cleanup
 	if m.cleanupInterval, err = toSeconds(m.cfg.StringDefault(keyPrefix+".cleanup_interval", "30m")); err != nil {
 		return nil, err
 	}
 
+	// Session registry, concurrent login policy
+	if err = m.initRegistry(); err != nil {
+		return nil, err
+	}
+
 	// Schedule cleanup
 	if !m.IsCookieStore() {
 		go func(sm *Manager) {
@@ -159,6 +172,12 @@ type Manager struct {
 	store           Storer
 	cfg             *config.Config
 	cookieMgr       *cookie.Manager
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
+
+	maxConcurrent    int
+	concurrentPolicy string
+	regMu            sync.Mutex
 }
 
 // NewSession method creates a new session for the request.
@@ -168,6 +187,8 @@ func (m *Manager) NewSession() *Session {
 	s.IsNew = true
 	t := time.Now()
 	s.CreatedTime = &t
+	s.LastAccessedTime = &t
+	s.idleTimeout, s.absoluteTimeout = m.idleTimeout, m.absoluteTimeout
 	return s
 }
 
@@ -207,7 +228,19 @@ func (m *Manager) GetSession(r *http.Request) *Session {
 		return nil
 	}
 
+	// Idle and absolute timeout, forces re-authentication
+	session.idleTimeout, session.absoluteTimeout = m.idleTimeout, m.absoluteTimeout
+	now := time.Now()
+	if session.isTimedOut(now) {
+		log.Debugf("Session timed out: %s", session.ID)
+		if !m.IsCookieStore() {
+			_ = m.store.Delete(session.ID)
+		}
+		return nil
+	}
+
 	session.IsNew = false
+	session.LastAccessedTime = &now
 	return session
 }
 
@@ -244,6 +277,12 @@ func (m *Manager) SaveSession(w http.ResponseWriter, s *Session) error {
 		}
 	}
 
+	if len(s.cookieDomain) > 0 {
+		opts := *m.cookieMgr.Options
+		opts.Domain = s.cookieDomain
+		http.SetCookie(w, cookie.NewWithOptions(encodedStr, &opts))
+		return nil
+	}
 	m.cookieMgr.Write(w, encodedStr)
 	return nil
 }
@@ -260,6 +299,9 @@ func (m *Manager) DeleteSession(w http.ResponseWriter, s *Session) error {
 
 	opts := *m.cookieMgr.Options
 	opts.MaxAge = -1
+	if len(s.cookieDomain) > 0 {
+		opts.Domain = s.cookieDomain
+	}
 	http.SetCookie(w, cookie.NewWithOptions("", &opts))
 	return nil
 }
@@ -7,9 +7,12 @@ package session
 import (
 	"encoding/gob"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
 	"aahframe.work/essentials"
@@ -189,3 +192,63 @@ func getTestdataPath() string {
 	wd, _ := os.Getwd()
 	return filepath.Join(wd, "testdata")
 }
+
+func TestSessionIdleAbsoluteTimeout(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  session {
+	    idle_timeout = "15m"
+	    absolute_timeout = "8h"
+	  }
+	}
+  `)
+
+	request := func(s *Session) *http.Request {
+		w := httptest.NewRecorder()
+		assert.Nil(t, m.SaveSession(w, s))
+		r := httptest.NewRequest("GET", "http://localhost:8080/", nil)
+		r.Header.Set("Cookie", w.Header().Get("Set-Cookie"))
+		return r
+	}
+
+	s := m.NewSession()
+	assert.True(t, s.ExpiresIn() > 14*time.Minute && s.ExpiresIn() <= 15*time.Minute)
+
+	// activity renews the idle timeout
+	past := time.Now().Add(-10 * time.Minute)
+	s.LastAccessedTime = &past
+	rs := m.GetSession(request(s))
+	assert.NotNil(t, rs)
+	assert.True(t, rs.ExpiresIn() > 14*time.Minute)
+
+	// idle timeout
+	past = time.Now().Add(-16 * time.Minute)
+	s.LastAccessedTime = &past
+	assert.Nil(t, m.GetSession(request(s)))
+
+	// absolute timeout limits the renewal
+	now := time.Now()
+	created := now.Add(-8*time.Hour + 5*time.Minute)
+	s.CreatedTime, s.LastAccessedTime = &created, &now
+	rs = m.GetSession(request(s))
+	assert.NotNil(t, rs)
+	assert.True(t, rs.ExpiresIn() <= 5*time.Minute)
+
+	created = now.Add(-8 * time.Hour)
+	s.CreatedTime = &created
+	assert.Nil(t, m.GetSession(request(s)))
+	assert.Equal(t, time.Duration(0), s.ExpiresIn())
+
+	// no timeouts
+	assert.Equal(t, time.Duration(0), createTestManager(t, `security { session { } }`).NewSession().ExpiresIn())
+
+	cfg, _ := config.ParseString(`
+	security {
+	  session {
+	    idle_timeout = "15 minutes"
+	  }
+	}
+  `)
+	_, err := NewManager(cfg)
+	assert.Equal(t, "session: 'security.session.idle_timeout' value is not a valid time unit", err.Error())
+}
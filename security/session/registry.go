@@ -0,0 +1,196 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aahframe.work/log"
+)
+
+// Concurrent login policies of session registry.
+const (
+	PolicyDenyNew    = "deny_new"
+	PolicyKickOldest = "kick_oldest"
+
+	registryKeyPrefix = "registry_"
+)
+
+var (
+	// ErrSessionConcurrentLimit returned when principal reached the config
+	// `security.session.max_concurrent` limit with policy `deny_new`.
+	ErrSessionConcurrentLimit = errors.New("security/session: concurrent session limit reached")
+
+	// ErrSessionRegistryNotSupported returned when session registry is used with
+	// cookie store, server-side store is required to track active sessions.
+	ErrSessionRegistryNotSupported = errors.New("security/session: session registry requires server-side store")
+)
+
+func (m *Manager) initRegistry() error {
+	keyPrefix := "security.session"
+	m.maxConcurrent = m.cfg.IntDefault(keyPrefix+".max_concurrent", 0)
+	m.concurrentPolicy = m.cfg.StringDefault(keyPrefix+".concurrent_policy", PolicyKickOldest)
+	if m.maxConcurrent <= 0 {
+		return nil
+	}
+
+	if !m.IsStateful() || m.IsCookieStore() {
+		return ErrSessionRegistryNotSupported
+	}
+	if m.concurrentPolicy != PolicyDenyNew && m.concurrentPolicy != PolicyKickOldest {
+		return fmt.Errorf("session: unsupported concurrent policy '%s'", m.concurrentPolicy)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Session Registry methods
+//___________________________________
+
+// IsRegistryEnabled method returns true if config `security.session.max_concurrent`
+// is greater than zero otherwise false.
+func (m *Manager) IsRegistryEnabled() bool {
+	return m.maxConcurrent > 0
+}
+
+// Register method adds the given session into active sessions of principal
+// and applies the concurrent login policy `security.session.concurrent_policy`.
+//
+//  - `deny_new` returns `ErrSessionConcurrentLimit` upon limit reached.
+//  - `kick_oldest` invalidates the oldest sessions of principal.
+func (m *Manager) Register(principal string, s *Session) error {
+	if !m.IsRegistryEnabled() {
+		return nil
+	}
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+	ids := m.activeSessionIDs(principal, s.ID)
+	if len(ids) >= m.maxConcurrent {
+		if m.concurrentPolicy == PolicyDenyNew {
+			return ErrSessionConcurrentLimit
+		}
+		kick := ids[:len(ids)-m.maxConcurrent+1]
+		for _, id := range kick {
+			log.Infof("Session concurrent limit reached, invalidating oldest session: %s", id)
+			m.deleteStoreSession(id)
+		}
+		ids = ids[len(kick):]
+	}
+
+	return m.saveRegistry(principal, append(ids, s.ID))
+}
+
+// Unregister method removes the given session ID from active sessions of principal.
+func (m *Manager) Unregister(principal, id string) error {
+	if !m.IsRegistryEnabled() {
+		return nil
+	}
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+	return m.saveRegistry(principal, m.activeSessionIDs(principal, id))
+}
+
+// SessionsOf method returns the active sessions of given principal, oldest
+// session first. Returned sessions are decoded from store and read-only.
+func (m *Manager) SessionsOf(principal string) []*Session {
+	if !m.IsRegistryEnabled() {
+		return nil
+	}
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+	var sessions []*Session
+	for _, id := range m.activeSessionIDs(principal, "") {
+		if s, err := m.DecodeToSession(m.store.Read(id)); err == nil {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// Invalidate method deletes the given session ID from store, subsequent
+// request of that session is treated as new one. It's used to invalidate
+// sessions remotely, for e.g.: admin revoking a user session.
+func (m *Manager) Invalidate(principal, id string) error {
+	if m.IsCookieStore() {
+		return ErrSessionRegistryNotSupported
+	}
+	if err := m.store.Delete(id); err != nil {
+		return err
+	}
+	return m.Unregister(principal, id)
+}
+
+// InvalidateAll method invalidates all the active sessions of given principal.
+func (m *Manager) InvalidateAll(principal string) error {
+	if !m.IsRegistryEnabled() {
+		return ErrSessionRegistryNotSupported
+	}
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+	for _, id := range m.activeSessionIDs(principal, "") {
+		m.deleteStoreSession(id)
+	}
+	return m.store.Delete(registryKey(principal))
+}
+
+// activeSessionIDs method returns the session IDs of principal from store
+// excluding given one and the ones deleted or expired.
+func (m *Manager) activeSessionIDs(principal, exclude string) []string {
+	key := registryKey(principal)
+	if !m.store.IsExists(key) {
+		return nil
+	}
+
+	value := m.store.Read(key)
+	if len(value) == 0 {
+		return nil
+	}
+
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id == exclude || !m.store.IsExists(id) {
+			continue
+		}
+		s, err := m.DecodeToSession(m.store.Read(id))
+		if err != nil {
+			continue
+		}
+		s.idleTimeout, s.absoluteTimeout = m.idleTimeout, m.absoluteTimeout
+		if s.isTimedOut(time.Now()) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (m *Manager) saveRegistry(principal string, ids []string) error {
+	if len(ids) == 0 {
+		return m.store.Delete(registryKey(principal))
+	}
+	return m.store.Save(registryKey(principal), strings.Join(ids, ","))
+}
+
+func (m *Manager) deleteStoreSession(id string) {
+	if err := m.store.Delete(id); err != nil {
+		log.Error(err)
+	}
+}
+
+// registryKey method returns the store key of principal, hashed to
+// keep it store safe.
+func registryKey(principal string) string {
+	h := sha256.Sum256([]byte(principal))
+	return registryKeyPrefix + hex.EncodeToString(h[:])
+}
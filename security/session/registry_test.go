@@ -0,0 +1,116 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRegistryKickOldest(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "registry"))
+
+	m := createTestManager(t, `
+	security {
+	  session {
+	    mode = "stateful"
+	    store {
+	      type = "file"
+	      filepath = "testdata/registry"
+	    }
+	    max_concurrent = 2
+	  }
+	}
+  `)
+	assert.True(t, m.IsRegistryEnabled())
+
+	s1 := newTestRegistrySession(t, m)
+	s2 := newTestRegistrySession(t, m)
+	assert.Nil(t, m.Register("jeeva", s1))
+	assert.Nil(t, m.Register("jeeva", s2))
+	assert.Nil(t, m.Register("jeeva", s2)) // already registered
+	assert.Equal(t, 2, len(m.SessionsOf("jeeva")))
+
+	s3 := newTestRegistrySession(t, m)
+	assert.Nil(t, m.Register("jeeva", s3))
+	sessions := m.SessionsOf("jeeva")
+	assert.Equal(t, 2, len(sessions))
+	assert.Equal(t, s2.ID, sessions[0].ID)
+	assert.Equal(t, s3.ID, sessions[1].ID)
+	assert.False(t, m.store.IsExists(s1.ID))
+
+	// remote invalidation
+	assert.Nil(t, m.Invalidate("jeeva", s2.ID))
+	sessions = m.SessionsOf("jeeva")
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, s3.ID, sessions[0].ID)
+
+	assert.Nil(t, m.InvalidateAll("jeeva"))
+	assert.Nil(t, m.SessionsOf("jeeva"))
+	assert.False(t, m.store.IsExists(s3.ID))
+}
+
+func TestSessionRegistryDenyNew(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "registry"))
+
+	m := createTestManager(t, `
+	security {
+	  session {
+	    mode = "stateful"
+	    store {
+	      type = "file"
+	      filepath = "testdata/registry"
+	    }
+	    max_concurrent = 1
+	    concurrent_policy = "deny_new"
+	  }
+	}
+  `)
+
+	s1 := newTestRegistrySession(t, m)
+	assert.Nil(t, m.Register("jeeva", s1))
+	assert.Equal(t, ErrSessionConcurrentLimit, m.Register("jeeva", newTestRegistrySession(t, m)))
+	assert.Nil(t, m.Register("user2", newTestRegistrySession(t, m)))
+
+	// logout of first session allows the new one
+	assert.Nil(t, m.DeleteSession(httptest.NewRecorder(), s1))
+	assert.Nil(t, m.Register("jeeva", newTestRegistrySession(t, m)))
+}
+
+func TestSessionRegistryConfigErrors(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	security {
+	  session {
+	    max_concurrent = 1
+	  }
+	}
+  `)
+	_, err := NewManager(cfg)
+	assert.Equal(t, ErrSessionRegistryNotSupported, err)
+
+	m := createTestManager(t, `
+	security {
+	  session {
+	  }
+	}
+  `)
+	assert.False(t, m.IsRegistryEnabled())
+	assert.Nil(t, m.Register("jeeva", m.NewSession()))
+	assert.Nil(t, m.SessionsOf("jeeva"))
+	assert.Equal(t, ErrSessionRegistryNotSupported, m.Invalidate("jeeva", "id"))
+	assert.Equal(t, ErrSessionRegistryNotSupported, m.InvalidateAll("jeeva"))
+}
+
+func newTestRegistrySession(t *testing.T, m *Manager) *Session {
+	s := m.NewSession()
+	s.IsAuthenticated = true
+	assert.Nil(t, m.SaveSession(httptest.NewRecorder(), s))
+	return s
+}
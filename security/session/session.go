@@ -37,7 +37,14 @@ type Session struct {
 	// CreatedTime is when the session was created.
 	CreatedTime *time.Time
 
-	maxAge int
+	// LastAccessedTime is when the session was last accessed, it's renewed
+	// on each request.
+	LastAccessedTime *time.Time
+
+	maxAge          int
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
+	cookieDomain    string
 }
 
 // Get method returns the value for given key otherwise nil.
@@ -142,6 +149,34 @@ func (s *Session) GetFloat64(key string) float64 {
 	return 0
 }
 
+// ExpiresIn method returns the remaining time to live of session per config
+// `security.session.idle_timeout` and `security.session.absolute_timeout`,
+// whichever is earlier. It's helpful for UI countdowns. It returns 0 if
+// none of the timeouts configured.
+func (s *Session) ExpiresIn() time.Duration {
+	var d time.Duration
+	now := time.Now()
+	if s.idleTimeout > 0 && s.LastAccessedTime != nil {
+		d = s.LastAccessedTime.Add(s.idleTimeout).Sub(now)
+	}
+	if s.absoluteTimeout > 0 && s.CreatedTime != nil {
+		if ad := s.CreatedTime.Add(s.absoluteTimeout).Sub(now); d == 0 || ad < d {
+			d = ad
+		}
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// SetCookieDomain method sets the session cookie domain for current response,
+// it overrides the config `security.session.domain`. For e.g.: per-tenant
+// session cookie domain.
+func (s *Session) SetCookieDomain(domain string) {
+	s.cookieDomain = domain
+}
+
 // String method is stringer interface implementation.
 func (s Session) String() string {
 	return fmt.Sprintf("session(id:%s createdat:%s isnew:%v isauthenticated:%v values:%v)",
@@ -158,6 +193,19 @@ func (s *Session) Reset() {
 	s.Values = make(map[string]interface{})
 	s.IsNew = false
 	s.CreatedTime = nil
+	s.LastAccessedTime = nil
 	s.IsAuthenticated = false
 	s.maxAge = 0
+	s.idleTimeout = 0
+	s.absoluteTimeout = 0
+	s.cookieDomain = ""
+}
+
+// isTimedOut method returns true if session reached the idle or absolute
+// timeout otherwise false.
+func (s *Session) isTimedOut(now time.Time) bool {
+	if s.absoluteTimeout > 0 && s.CreatedTime != nil && now.Sub(*s.CreatedTime) >= s.absoluteTimeout {
+		return true
+	}
+	return s.idleTimeout > 0 && s.LastAccessedTime != nil && now.Sub(*s.LastAccessedTime) >= s.idleTimeout
 }
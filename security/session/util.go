@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"aahframe.work/config"
 )
 
 // toBytes method encodes into byte slice.
@@ -31,3 +33,17 @@ func toSeconds(value string) (int64, error) {
 	}
 	return 0, fmt.Errorf("unsupported time unit '%s' on 'session.ttl'", value)
 }
+
+// toDuration method parses the config value of given key into duration,
+// returns 0 if key not exists.
+func toDuration(cfg *config.Config, key string) (time.Duration, error) {
+	value, found := cfg.String(key)
+	if !found {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("session: '%s' value is not a valid time unit", key)
+	}
+	return d, nil
+}
@@ -5,6 +5,7 @@
 package security
 
 import (
+	"errors"
 	"fmt"
 
 	"aahframe.work/security/authc"
@@ -12,6 +13,17 @@ import (
 	"aahframe.work/security/session"
 )
 
+const keyRunAsPrincipals = "_aahRunAsPrincipals"
+
+// GuestRealm is realm name of guest principal, see `Guest`.
+const GuestRealm = "Guest"
+
+// Run as errors
+var (
+	ErrRunAsNotAuthenticated = errors.New("security: run as requires authenticated subject")
+	ErrRunAsPrincipalsEmpty  = errors.New("security: run as principals is empty")
+)
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Subject and its methods
 //___________________________________
@@ -36,24 +48,43 @@ type Subject struct {
 	AuthenticationInfo *authc.AuthenticationInfo
 	AuthorizationInfo  *authz.AuthorizationInfo
 	Session            *session.Session
+
+	runAsListener func(released bool)
 }
 
 // PrimaryPrincipal method is convenience wrapper. See `AuthenticationInfo.PrimaryPrincipal`.
+// If subject is running as another identity then it returns the run as
+// primary principal, see `RealPrincipal`.
 func (s *Subject) PrimaryPrincipal() *authc.Principal {
+	if s.IsRunAs() {
+		return s.runAsInfo().PrimaryPrincipal()
+	}
 	return s.AuthenticationInfo.PrimaryPrincipal()
 }
 
 // Principal method returns the principal value for given Claim.
 // See `AuthenticationInfo.Principal`.
 func (s *Subject) Principal(claim string) *authc.Principal {
+	if s.IsRunAs() {
+		return s.runAsInfo().Principal(claim)
+	}
 	return s.AuthenticationInfo.Principal(claim)
 }
 
 // AllPrincipals method is convenience wrapper.
 func (s *Subject) AllPrincipals() []*authc.Principal {
+	if s.IsRunAs() {
+		return s.RunAsPrincipals()
+	}
 	return s.AuthenticationInfo.Principals
 }
 
+// RealPrincipal method returns the primary principal of real authenticated
+// identity regardless of run as.
+func (s *Subject) RealPrincipal() *authc.Principal {
+	return s.AuthenticationInfo.PrimaryPrincipal()
+}
+
 // IsAuthenticated method is convenience wrapper. See `Session.IsAuthenticated`.
 func (s *Subject) IsAuthenticated() bool {
 	if s.Session == nil {
@@ -62,6 +93,16 @@ func (s *Subject) IsAuthenticated() bool {
 	return s.Session.IsAuthenticated
 }
 
+// IsGuest method returns true if subject is unauthenticated and populated
+// with guest principal per config `security.guest.*`.
+func (s *Subject) IsGuest() bool {
+	if s.IsAuthenticated() || s.AuthenticationInfo == nil {
+		return false
+	}
+	p := s.AuthenticationInfo.PrimaryPrincipal()
+	return p != nil && p.Realm == GuestRealm
+}
+
 // Logout method is convenience wrapper. See `Session.Clear`.
 func (s *Subject) Logout() {
 	if s.Session != nil {
@@ -69,6 +110,73 @@ func (s *Subject) Logout() {
 	}
 }
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Subject's Run As methods
+//___________________________________
+
+// RunAs method allows the authenticated subject to assume the given identity
+// (aka impersonation) until `ReleaseRunAs` is called. Run as principals are
+// persisted in the session and real authenticated identity is retained, see
+// `RealPrincipal`. Authorization info of run as identity gets populated from
+// next request onwards.
+//
+// For e.g.:
+// 	if ctx.Subject().HasRole("admin") {
+// 		err := ctx.Subject().RunAs(userPrincipals)
+// 	}
+func (s *Subject) RunAs(principals []*authc.Principal) error {
+	if !s.IsAuthenticated() {
+		return ErrRunAsNotAuthenticated
+	}
+	if len(principals) == 0 {
+		return ErrRunAsPrincipalsEmpty
+	}
+	s.Session.Set(keyRunAsPrincipals, principals)
+	if s.runAsListener != nil {
+		s.runAsListener(false)
+	}
+	return nil
+}
+
+// IsRunAs method returns true if subject is running as another identity
+// otherwise false.
+func (s *Subject) IsRunAs() bool {
+	return s.Session != nil && s.Session.IsKeyExists(keyRunAsPrincipals)
+}
+
+// RunAsPrincipals method returns the principals of run as identity otherwise nil.
+func (s *Subject) RunAsPrincipals() []*authc.Principal {
+	if s.Session == nil {
+		return nil
+	}
+	principals, _ := s.Session.Get(keyRunAsPrincipals).([]*authc.Principal)
+	return principals
+}
+
+// ReleaseRunAs method releases the run as identity and returns its principals,
+// subject gets back to real authenticated identity.
+func (s *Subject) ReleaseRunAs() []*authc.Principal {
+	if !s.IsRunAs() {
+		return nil
+	}
+	principals := s.RunAsPrincipals()
+	if s.runAsListener != nil {
+		s.runAsListener(true)
+	}
+	s.Session.Del(keyRunAsPrincipals)
+	return principals
+}
+
+// OnRunAs method sets the listener func, it's called upon `RunAs` and
+// `ReleaseRunAs` (before release) with released flag.
+func (s *Subject) OnRunAs(fn func(released bool)) {
+	s.runAsListener = fn
+}
+
+func (s *Subject) runAsInfo() *authc.AuthenticationInfo {
+	return &authc.AuthenticationInfo{Principals: s.RunAsPrincipals()}
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Subject's Authorization methods
 //___________________________________
@@ -103,6 +211,7 @@ func (s *Subject) Reset() {
 	s.AuthenticationInfo = nil
 	s.AuthorizationInfo = nil
 	s.Session = nil
+	s.runAsListener = nil
 }
 
 // String method is stringer interface implementation.
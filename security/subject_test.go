@@ -75,3 +75,53 @@ func TestSecuritySubject(t *testing.T) {
 
 	ReleaseSubject(sub)
 }
+
+func TestSecuritySubjectRunAs(t *testing.T) {
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals, &authc.Principal{Value: "admin@sample.com", IsPrimary: true})
+
+	cfg, _ := config.ParseString(`
+		security {
+			session {
+			}
+		}
+		`)
+	sessionManager, err := session.NewManager(cfg)
+	assert.Nil(t, err, "unexpected")
+
+	sub := AcquireSubject()
+	sub.AuthenticationInfo = authcInfo
+	sub.Session = sessionManager.NewSession()
+
+	runAs := []*authc.Principal{
+		{Claim: "email", Value: "user@sample.com", IsPrimary: true},
+		{Claim: "id", Value: "1001"},
+	}
+	assert.Equal(t, ErrRunAsNotAuthenticated, sub.RunAs(runAs))
+
+	sub.Session.IsAuthenticated = true
+	assert.Equal(t, ErrRunAsPrincipalsEmpty, sub.RunAs(nil))
+	assert.False(t, sub.IsRunAs())
+	assert.Nil(t, sub.ReleaseRunAs())
+
+	var events []bool
+	sub.OnRunAs(func(released bool) {
+		events = append(events, released)
+		assert.True(t, sub.IsRunAs())
+	})
+
+	assert.Nil(t, sub.RunAs(runAs))
+	assert.True(t, sub.IsRunAs())
+	assert.Equal(t, "user@sample.com", sub.PrimaryPrincipal().Value)
+	assert.Equal(t, "1001", sub.Principal("id").Value)
+	assert.Equal(t, 2, len(sub.AllPrincipals()))
+	assert.Equal(t, "admin@sample.com", sub.RealPrincipal().Value)
+
+	released := sub.ReleaseRunAs()
+	assert.Equal(t, 2, len(released))
+	assert.False(t, sub.IsRunAs())
+	assert.Equal(t, "admin@sample.com", sub.PrimaryPrincipal().Value)
+	assert.Equal(t, []bool{false, true}, events)
+
+	ReleaseSubject(sub)
+}
@@ -7,6 +7,7 @@ package aah
 import (
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
@@ -18,6 +19,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/log"
 	"aahframe.work/router"
 	"aahframe.work/security"
 	"aahframe.work/security/anticsrf"
@@ -103,6 +105,68 @@ func TestSecuritySessionTemplateFuns(t *testing.T) {
 	assert.False(t, v4)
 }
 
+func TestSecurityRunAs(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	var events []string
+	for _, name := range []string{EventOnRunAs, EventOnReleaseRunAs} {
+		a.SubscribeEvent(name, EventCallback{Callback: func(e *Event) {
+			ctx := e.Data.(*Context)
+			events = append(events, e.Name+":"+ctx.Subject().RealPrincipal().Value+":"+
+				ctx.Subject().PrimaryPrincipal().Value)
+		}})
+	}
+
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+	ctx := newContext(httptest.NewRecorder(), req)
+	ctx.a = a
+	populateAuthenticationInfo(testGetAuthenticationInfo(), ctx)
+	ctx.Session().IsAuthenticated = true
+
+	viewArgs := map[string]interface{}{KeyViewArgSubject: ctx.Subject()}
+	assert.False(t, a.viewMgr.tmplIsRunAs(viewArgs))
+
+	err := ctx.Subject().RunAs([]*authc.Principal{{Value: "user1", IsPrimary: true}})
+	assert.Nil(t, err)
+	assert.True(t, a.viewMgr.tmplIsRunAs(viewArgs))
+	assert.Equal(t, "user1", ctx.Subject().PrimaryPrincipal().Value)
+	assert.Equal(t, "jeeva", ctx.Subject().RealPrincipal().Value)
+
+	ctx.Subject().ReleaseRunAs()
+	assert.False(t, a.viewMgr.tmplIsRunAs(viewArgs))
+	assert.Equal(t, []string{"OnRunAs:jeeva:user1", "OnReleaseRunAs:jeeva:user1"}, events)
+}
+
+func TestSecurityPasswordPolicyConstraint(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	type registration struct {
+		Email    string `validate:"required,email"`
+		Password string `validate:"required,password_policy"`
+	}
+
+	errs, err := a.Validate(&registration{Email: "user@example.com", Password: "secret"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "password_policy", errs[0].Tag())
+
+	errs, err = a.Validate(&registration{Email: "user@example.com", Password: "long-secret"})
+	assert.Nil(t, err)
+	assert.Nil(t, errs)
+
+	// breached list relative path is resolved from app base dir
+	key := "security.password_policy.breached_list"
+	a.Config().SetString(key, "config/breached-passwords.txt")
+	a.resolveBreachedList()
+	assert.Equal(t, filepath.Join(a.BaseDir(), "config", "breached-passwords.txt"), a.Config().StringDefault(key, ""))
+	a.resolveBreachedList()
+	assert.Equal(t, filepath.Join(a.BaseDir(), "config", "breached-passwords.txt"), a.Config().StringDefault(key, ""))
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Form Auth test
 //______________________________________________________________________________
@@ -396,6 +460,133 @@ func TestSecurityHandleBasicAuthcAndAuthz(t *testing.T) {
 	AuthcAuthzMiddleware(ctx1, &Middleware{})
 }
 
+func TestSecurityAuthChain(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Security Auth Chain]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    api_auth {
+		      scheme = "generic"
+		      challenge = "Bearer realm=\"api\""
+		    }
+		    basic_auth {
+		      scheme = "basic"
+		      realm_name = "webapp1"
+		    }
+		  }
+		}
+	`)
+	err := ts.app.Config().Merge(cfg)
+	assert.Nil(t, err)
+	err = ts.app.initSecurity()
+	assert.Nil(t, err)
+
+	buf := new(strings.Builder)
+	ts.app.Log().(*log.Logger).SetWriter(buf)
+	ts.app.Log().(*log.Logger).SetLevel("debug")
+	defer ts.app.Log().(*log.Logger).SetLevel("info")
+
+	newChainCtx := func(auth string, user string) *Context {
+		r, _ := http.NewRequest(ahttp.MethodGet, "http://localhost:8080/doc/v0.3/mydoc.html", nil)
+		if user != "" {
+			r.SetBasicAuth(user, "welcome123")
+		}
+		ctx := ts.app.he.newContext()
+		ctx.Req = ahttp.AcquireRequest(r)
+		ctx.Res = ahttp.AcquireResponseWriter(httptest.NewRecorder())
+		ctx.route = &router.Route{Auth: auth}
+		return ctx
+	}
+
+	// all schemes failed, challenges are aggregated in the defined order
+	ctx := newChainCtx("api_auth?basic_auth", "")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusUnauthorized, ctx.Reply().Code)
+	assert.Equal(t, ErrAuthenticationFailed, ctx.Reply().err.Reason)
+	assert.Equal(t, []string{`Bearer realm="api"`, `Basic realm="webapp1"`},
+		ctx.Res.Header()[ahttp.HeaderWWWAuthenticate])
+	assert.True(t, strings.Contains(buf.String(), "Route auth chain 'api_auth?basic_auth' is failed"))
+
+	ctx = newChainCtx("basic_auth?api_auth", "")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, []string{`Basic realm="webapp1"`, `Bearer realm="api"`},
+		ctx.Res.Header()[ahttp.HeaderWWWAuthenticate])
+
+	// falls through to basic auth
+	basicAuth := ts.app.SecurityManager().AuthScheme("basic_auth").(*scheme.BasicAuth)
+	assert.Nil(t, basicAuth.SetAuthenticator(&testBasicAuth{}))
+	assert.Nil(t, basicAuth.SetAuthorizer(&testBasicAuth{}))
+	buf.Reset()
+	ctx = newChainCtx("api_auth?basic_auth", "jeeva")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusOK, ctx.Reply().Code)
+	assert.Nil(t, ctx.Reply().err)
+	assert.Empty(t, ctx.Res.Header()[ahttp.HeaderWWWAuthenticate])
+	assert.True(t, ctx.Subject().IsAuthenticated())
+	assert.True(t, strings.Contains(buf.String(), "Request authenticated by auth scheme: basic_auth"))
+}
+
+func TestSecurityAPIKeyAuth(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Security API Key Auth]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    api_key_auth {
+		      scheme = "api_key"
+		      keys {
+		        partner_a {
+		          key = "a3f9c1d27e6b4f08"
+		          scopes = ["orders:read"]
+		          rate_limit = 1
+		        }
+		      }
+		    }
+		  }
+		}
+	`)
+	err := ts.app.Config().Merge(cfg)
+	assert.Nil(t, err)
+	err = ts.app.initSecurity()
+	assert.Nil(t, err)
+
+	newAPIKeyCtx := func(key string) *Context {
+		r, _ := http.NewRequest(ahttp.MethodGet, "http://localhost:8080/orders", nil)
+		r.Header.Set("X-API-Key", key)
+		ctx := ts.app.he.newContext()
+		ctx.Req = ahttp.AcquireRequest(r)
+		ctx.Res = ahttp.AcquireResponseWriter(httptest.NewRecorder())
+		ctx.route = &router.Route{Auth: "api_key_auth"}
+		return ctx
+	}
+
+	ctx := newAPIKeyCtx("unknown")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusUnauthorized, ctx.Reply().Code)
+	assert.Equal(t, `APIKey header="X-Api-Key"`, ctx.Res.Header().Get(ahttp.HeaderWWWAuthenticate))
+
+	ctx = newAPIKeyCtx("a3f9c1d27e6b4f08")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusOK, ctx.Reply().Code)
+	assert.Equal(t, "partner_a", ctx.Subject().PrimaryPrincipal().Value)
+	assert.True(t, ctx.Subject().IsPermitted("orders:read"))
+
+	ctx = newAPIKeyCtx("a3f9c1d27e6b4f08")
+	AuthcAuthzMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusTooManyRequests, ctx.Reply().Code)
+	assert.Equal(t, ErrAuthenticationThrottled, ctx.Reply().err.Reason)
+	assert.NotEmpty(t, ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+}
+
 func TestSecurityAntiCSRF(t *testing.T) {
 	importPath := filepath.Join(testdataBaseDir(), "webapp1")
 	ts := newTestServer(t, importPath)
@@ -467,3 +658,122 @@ func TestSecurityAntiCSRF(t *testing.T) {
 	err = ts.app.AddPasswordAlgorithm("mypass", nil)
 	assert.NotNil(t, err)
 }
+
+func TestSecurityAntiCSRFDoubleSubmit(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	cfg, _ := config.ParseString(`
+	security {
+		anti_csrf {
+			mode = "double_submit"
+		}
+	}
+	`)
+	ac, err := anticsrf.New(cfg)
+	assert.Nil(t, err)
+	a.SecurityManager().AntiCSRF = ac
+
+	var called int
+	mw := &Middleware{next: func(ctx *Context, m *Middleware) { called++ }}
+	newCSRFCtx := func(method, token, hdrToken string) *Context {
+		r := httptest.NewRequest(method, "http://localhost:8080/api/orders", nil)
+		if len(token) > 0 {
+			r.AddCookie(&http.Cookie{Name: "aah_csrf_token", Value: token})
+		}
+		if len(hdrToken) > 0 {
+			r.Header.Set("X-Anti-CSRF-Token", hdrToken)
+		}
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		ctx.route = &router.Route{IsAntiCSRFCheck: true}
+		return ctx
+	}
+
+	// safe method issues the token cookie
+	ctx := newCSRFCtx(ahttp.MethodGet, "", "")
+	AntiCSRFMiddleware(ctx, mw)
+	assert.Equal(t, 1, called)
+	setCookie := ctx.Res.Header().Get(ahttp.HeaderSetCookie)
+	assert.True(t, strings.HasPrefix(setCookie, "aah_csrf_token="))
+	assert.False(t, strings.Contains(setCookie, "HttpOnly"))
+	token := strings.TrimPrefix(strings.Split(setCookie, ";")[0], "aah_csrf_token=")
+
+	// token cookie exists, not issued again
+	ctx = newCSRFCtx(ahttp.MethodGet, token, "")
+	AntiCSRFMiddleware(ctx, mw)
+	assert.Equal(t, 2, called)
+	assert.Equal(t, "", ctx.Res.Header().Get(ahttp.HeaderSetCookie))
+
+	// valid double submit
+	ctx = newCSRFCtx(ahttp.MethodPost, token, token)
+	AntiCSRFMiddleware(ctx, mw)
+	assert.Equal(t, 3, called)
+	assert.Nil(t, ctx.Reply().err)
+
+	// token mismatch
+	ctx = newCSRFCtx(ahttp.MethodPost, token, "invalid")
+	AntiCSRFMiddleware(ctx, mw)
+	assert.Equal(t, 3, called)
+	assert.Equal(t, http.StatusForbidden, ctx.Reply().Code)
+	assert.Equal(t, anticsrf.ErrTokenMismatch, ctx.Reply().err.Reason)
+
+	// no token cookie
+	ctx = newCSRFCtx(ahttp.MethodDelete, "", token)
+	AntiCSRFMiddleware(ctx, mw)
+	assert.Equal(t, 3, called)
+	assert.Equal(t, anticsrf.ErrNoCookieFound, ctx.Reply().err.Reason)
+}
+
+func TestSecurityGuest(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Security Guest]: %s", ts.URL)
+
+	hasSessionCookie := func() bool {
+		resp, err := http.Get(ts.URL + "/get-text.html")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		for _, c := range resp.Cookies() {
+			if c.Name == "aah_session" {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasSessionCookie())
+
+	cfg, _ := config.ParseString(`
+		security {
+		  guest {
+		    enable = true
+		    roles = ["guest", "shopper"]
+		    permissions = ["catalog:read"]
+		    lazy_session = true
+		  }
+		}
+	`)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initSecurity())
+
+	// Guest session is not created until its used
+	assert.False(t, hasSessionCookie())
+
+	req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+	ctx := newContext(httptest.NewRecorder(), req)
+	ctx.a = ts.app
+	ts.app.SecurityManager().Guest.Populate(ctx.Subject())
+
+	viewArgs := map[string]interface{}{KeyViewArgSubject: ctx.Subject()}
+	assert.True(t, ts.app.viewMgr.tmplIsGuest(viewArgs))
+	assert.True(t, ts.app.viewMgr.tmplHasRole(viewArgs, "shopper"))
+	assert.True(t, ts.app.viewMgr.tmplIsPermitted(viewArgs, "catalog:read"))
+	assert.False(t, ts.app.viewMgr.tmplIsAuthenticated(viewArgs))
+
+	populateAuthenticationInfo(testGetAuthenticationInfo(), ctx)
+	ctx.Session().IsAuthenticated = true
+	assert.False(t, ts.app.viewMgr.tmplIsGuest(viewArgs))
+}
@@ -0,0 +1,266 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"aahframe.work/console"
+	"aahframe.work/essentials"
+)
+
+// ErrSeedNotConfigured returned when seed does not have datasource and
+// `seed.datasource` is not configured in the `aah.conf`.
+var ErrSeedNotConfigured = errors.New("aah: seed datasource is not configured")
+
+// Seeder interface is implemented by seed data loaders. Seeder must be
+// idempotent, it could be run multiple times on the same database, for e.g.:
+// using `INSERT ... ON CONFLICT DO NOTHING` or `SeedContext.Exists`.
+type Seeder interface {
+	Seed(sc *SeedContext) error
+}
+
+// SeederFunc type is an adapter to allow the use of ordinary func as
+// seeder.
+type SeederFunc func(sc *SeedContext) error
+
+// Seed method calls `f(sc)`.
+func (f SeederFunc) Seed(sc *SeedContext) error {
+	return f(sc)
+}
+
+// Seed struct holds the seed data loader registration details.
+type Seed struct {
+	// Name is unique name of the seed.
+	Name string
+
+	// Datasource is the data source name from `datasource { ... }`, default
+	// is `seed.datasource` from `aah.conf`.
+	Datasource string
+
+	// Profiles are the environment profile names, seed is applicable for.
+	// Empty means all the profiles.
+	Profiles []string
+
+	// DependsOn are the seed names which have to be seeded before this one.
+	DependsOn []string
+
+	// Seeder is the seed data loader.
+	Seeder Seeder
+}
+
+// SeedContext struct is passed to the seeder, each seed is run within the
+// database transaction `Tx`. Transaction is rolled back on error or dry-run.
+type SeedContext struct {
+	App     *Application
+	Name    string
+	Profile string
+	DryRun  bool
+	Tx      *sql.Tx
+}
+
+// Exists method returns true if given query returns at least one row. It
+// could be used to make seeder idempotent, for e.g.:
+// 	found, err := sc.Exists("SELECT 1 FROM roles WHERE name = $1", "admin")
+func (sc *SeedContext) Exists(query string, args ...interface{}) (bool, error) {
+	rows, err := sc.Tx.Query(query, args...)
+	if err != nil {
+		return false, err
+	}
+	defer ess.CloseQuietly(rows)
+	return rows.Next(), rows.Err()
+}
+
+// AddSeed method adds the given seed data loader into aah application.
+// Seeds are run in the order of registration and `DependsOn` via
+// `Application.RunSeeds` or app binary command `<app-binary> seed`.
+//
+// 	aah.App().AddSeed(&aah.Seed{
+// 		Name:     "roles",
+// 		Profiles: []string{"dev", "qa"},
+// 		Seeder:   aah.SeederFunc(seedRoles),
+// 	})
+//
+// Configuration goes under `seed { ... }` in the `aah.conf`.
+//
+// 	seed {
+// 	  # default data source name from `datasource { ... }` for seeds
+// 	  datasource = "default"
+// 	}
+func (a *Application) AddSeed(seeds ...*Seed) error {
+	a.Lock()
+	defer a.Unlock()
+	for _, s := range seeds {
+		if ess.IsStrEmpty(s.Name) || s.Seeder == nil {
+			return errors.New("aah: seed name and seeder is required")
+		}
+		for _, es := range a.seeds {
+			if es.Name == s.Name {
+				return fmt.Errorf("aah: seed '%s' already exists", s.Name)
+			}
+		}
+		a.seeds = append(a.seeds, s)
+	}
+	return nil
+}
+
+// RunSeeds method runs the seeds applicable for current environment profile
+// in the dependency order and returns the seeded names. If names given then
+// only those seeds and its dependencies are run. On dry-run seeds are run
+// and its transaction is rolled back.
+func (a *Application) RunSeeds(dryRun bool, names ...string) ([]string, error) {
+	seeds, err := a.resolveSeeds(names)
+	if err != nil {
+		return nil, err
+	}
+	var seeded []string
+	for _, s := range seeds {
+		if err = a.runSeed(s, dryRun); err != nil {
+			return seeded, fmt.Errorf("aah: seed '%s': %v", s.Name, err)
+		}
+		seeded = append(seeded, s.Name)
+	}
+	return seeded, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) cliCmdSeed() console.Command {
+	return console.Command{
+		Name:  "seed",
+		Usage: "Loads the reference or demo data using registered seeds",
+		Description: `Loads the reference or demo data using seeds registered via 'aah.App().AddSeed'
+	for the environment profile. Seeds are run in the dependency order, each within
+	the database transaction.
+
+		Example:
+			<app-binary> seed --envprofile qa
+			<app-binary> seed --name users --dry-run`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+			},
+			console.StringSliceFlag{
+				Name:  "name, n",
+				Usage: "Seed name to run along with its dependencies, default is all seeds",
+			},
+			console.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Runs the seeds and rolls back the changes",
+			},
+		},
+		Action: func(c *console.Context) error {
+			a.Config().SetString("env.active", cliEnvProfile(c))
+			if err := a.refreshSettings(); err != nil {
+				return err
+			}
+			if err := a.initLog(); err != nil {
+				return err
+			}
+			if err := a.initDataSource(); err != nil {
+				return err
+			}
+			defer a.closeDataSources()
+
+			dryRun := c.Bool("dry-run")
+			seeded, err := a.RunSeeds(dryRun, c.StringSlice("name")...)
+			for _, name := range seeded {
+				if dryRun {
+					fmt.Fprintf(c.App.Writer, "Seeded %s (dry-run)\n", name)
+				} else {
+					fmt.Fprintf(c.App.Writer, "Seeded %s\n", name)
+				}
+			}
+			return err
+		},
+	}
+}
+
+func (a *Application) runSeed(s *Seed, dryRun bool) error {
+	dsName := s.Datasource
+	if ess.IsStrEmpty(dsName) {
+		dsName = a.Config().StringDefault("seed.datasource", "")
+	}
+	if ess.IsStrEmpty(dsName) {
+		return ErrSeedNotConfigured
+	}
+	db := a.DB(dsName)
+	if db == nil {
+		return fmt.Errorf("datasource '%s' not exists", dsName)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	sc := &SeedContext{App: a, Name: s.Name, Profile: a.EnvProfile(), DryRun: dryRun, Tx: tx}
+	if err = s.Seeder.Seed(sc); err != nil || dryRun {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// resolveSeeds method returns the seeds applicable for current environment
+// profile in the dependency order.
+func (a *Application) resolveSeeds(names []string) ([]*Seed, error) {
+	a.RLock()
+	defer a.RUnlock()
+	profile := a.EnvProfile()
+	applicable := make(map[string]*Seed)
+	for _, s := range a.seeds {
+		if len(s.Profiles) == 0 || ess.IsSliceContainsString(s.Profiles, profile) {
+			applicable[s.Name] = s
+		}
+	}
+	if len(names) == 0 {
+		for _, s := range a.seeds {
+			if _, found := applicable[s.Name]; found {
+				names = append(names, s.Name)
+			}
+		}
+	}
+
+	var ordered []*Seed
+	state := make(map[string]int) // 1 - visiting, 2 - visited
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("aah: seed dependency cycle '%s'", strings.Join(append(path, name), " -> "))
+		case 2:
+			return nil
+		}
+		s, found := applicable[name]
+		if !found {
+			if len(path) > 0 {
+				return fmt.Errorf("aah: seed '%s' depends on '%s', not exists for profile '%s'", path[len(path)-1], name, profile)
+			}
+			return fmt.Errorf("aah: seed '%s' not exists for profile '%s'", name, profile)
+		}
+		state[name] = 1
+		for _, dep := range s.DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		ordered = append(ordered, s)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/console"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	a := newApp()
+	cfg, err := config.ParseString(`env {
+  active = "dev"
+  dev { }
+  prod { }
+}
+datasource {
+  default {
+    driver = "aahseeddb"
+    dsn = "default"
+  }
+}
+seed {
+  datasource = "default"
+}`)
+	assert.Nil(t, err)
+	a.cfg = cfg
+	assert.Nil(t, a.settings.Refresh(a.Config()))
+	assert.Nil(t, a.initLog())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Nil(t, a.initDataSource())
+	defer a.closeDataSources()
+
+	var calls []string
+	seeder := func(name string) Seeder {
+		return SeederFunc(func(sc *SeedContext) error {
+			calls = append(calls, name)
+			if name == "fail" {
+				return errors.New("seed failed")
+			}
+			return nil
+		})
+	}
+
+	err = a.AddSeed(
+		&Seed{Name: "users", DependsOn: []string{"roles", "tenants"}, Seeder: seeder("users")},
+		&Seed{Name: "roles", Seeder: seeder("roles")},
+		&Seed{Name: "tenants", DependsOn: []string{"roles"}, Seeder: seeder("tenants")},
+		&Seed{Name: "demo", Profiles: []string{"prod"}, Seeder: seeder("demo")},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "aah: seed 'roles' already exists", a.AddSeed(&Seed{Name: "roles", Seeder: seeder("roles")}).Error())
+	assert.Equal(t, "aah: seed name and seeder is required", a.AddSeed(&Seed{Name: "empty"}).Error())
+
+	seeded, err := a.RunSeeds(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"roles", "tenants", "users"}, seeded)
+	assert.Equal(t, seeded, calls)
+	assert.Equal(t, 3, seedTestStore.count("commit"))
+
+	// dry-run using command
+	calls = nil
+	var buf bytes.Buffer
+	cliApp := console.NewApp()
+	cliApp.Writer = &buf
+	cliApp.Commands = []console.Command{a.cliCmdSeed()}
+	err = cliApp.Run([]string{"app", "seed", "--name", "tenants", "--dry-run"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Seeded roles (dry-run)\nSeeded tenants (dry-run)\n", buf.String())
+	assert.Equal(t, 2, seedTestStore.count("rollback"))
+	assert.Nil(t, a.DB("default"), "closed by command")
+	assert.Nil(t, a.initDataSource())
+
+	_, err = a.RunSeeds(false, "demo")
+	assert.Equal(t, "aah: seed 'demo' not exists for profile 'dev'", err.Error())
+
+	assert.Nil(t, a.AddSeed(
+		&Seed{Name: "fail", Datasource: "default", DependsOn: []string{"roles"}, Seeder: seeder("fail")},
+		&Seed{Name: "orphan", DependsOn: []string{"demo"}, Seeder: seeder("orphan")},
+		&Seed{Name: "cycle1", DependsOn: []string{"cycle2"}, Seeder: seeder("cycle1")},
+		&Seed{Name: "cycle2", DependsOn: []string{"cycle1"}, Seeder: seeder("cycle2")},
+		&Seed{Name: "nods", Datasource: "reporting", Seeder: seeder("nods")},
+	))
+	seeded, err = a.RunSeeds(false, "fail")
+	assert.Equal(t, "aah: seed 'fail': seed failed", err.Error())
+	assert.Equal(t, []string{"roles"}, seeded)
+	assert.Equal(t, 3, seedTestStore.count("rollback"))
+
+	_, err = a.RunSeeds(false, "orphan")
+	assert.Equal(t, "aah: seed 'orphan' depends on 'demo', not exists for profile 'dev'", err.Error())
+	_, err = a.RunSeeds(false, "cycle1")
+	assert.Equal(t, "aah: seed dependency cycle 'cycle1 -> cycle2 -> cycle1'", err.Error())
+	_, err = a.RunSeeds(false, "nods")
+	assert.Equal(t, "aah: seed 'nods': datasource 'reporting' not exists", err.Error())
+
+	a.Config().SetString("seed.datasource", "")
+	_, err = a.RunSeeds(false, "roles")
+	assert.True(t, strings.HasSuffix(err.Error(), ErrSeedNotConfigured.Error()))
+}
+
+func init() {
+	sql.Register("aahseeddb", &seedTestDriver{})
+}
+
+var seedTestStore = &seedTestTxStore{counts: make(map[string]int)}
+
+type seedTestTxStore struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func (s *seedTestTxStore) inc(name string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.counts[name]++
+	return nil
+}
+
+func (s *seedTestTxStore) count(name string) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.counts[name]
+}
+
+type seedTestDriver struct{}
+
+func (seedTestDriver) Open(name string) (driver.Conn, error) { return &seedTestConn{}, nil }
+
+type seedTestConn struct{}
+
+func (c *seedTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *seedTestConn) Close() error              { return nil }
+func (c *seedTestConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *seedTestConn) Commit() error             { return seedTestStore.inc("commit") }
+func (c *seedTestConn) Rollback() error           { return seedTestStore.inc("rollback") }
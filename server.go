@@ -19,6 +19,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/settings"
+	"aahframe.work/log"
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -59,15 +60,20 @@ func (a *Application) Start() {
 		a.Log().Infof("App Anti-CSRF Enabled: %t", a.SecurityManager().AntiCSRF.Enabled)
 	}
 
-	var routeDomains []string
-	for _, d := range a.Router().Domains {
-		routeDomains = append(routeDomains, d.Key)
-	}
-	a.Log().Info("App Route Domains: ", strings.Join(routeDomains, ", "))
+	if !a.IsWorker() {
+		var routeDomains []string
+		for _, d := range a.Router().Domains {
+			routeDomains = append(routeDomains, d.Key)
+		}
+		a.Log().Info("App Route Domains: ", strings.Join(routeDomains, ", "))
 
-	redirectEnabled := a.Config().BoolDefault("server.redirect.enable", false)
-	if redirectEnabled {
-		a.Log().Infof("App Redirect(%s) Enabled: true", inferRedirectMode(a.Config().StringDefault("server.redirect.to", nonwww)))
+		redirectEnabled := a.Config().BoolDefault("server.redirect.enable", false)
+		if redirectEnabled {
+			a.Log().Infof("App Redirect(%s) Enabled: true", inferRedirectMode(a.Config().StringDefault("server.redirect.to", nonwww)))
+			for _, rule := range a.redirectRules {
+				a.Log().Infof("App Redirect Rule: %s", rule)
+			}
+		}
 	}
 
 	if a.I18n() != nil {
@@ -94,6 +100,23 @@ func (a *Application) Start() {
 	// Publish `OnStart` event
 	a.EventStore().sortAndPublishSync(&Event{Name: EventOnStart})
 
+	// Start supervised background workers
+	a.supervisor.start()
+	a.webhookMgr.start()
+
+	a.writePID()
+	go a.listenForHotReload()
+	if a.cfgProvider != nil {
+		a.cfgWatchStop = make(chan struct{})
+		go a.watchConfigProvider(a.cfgWatchStop)
+	}
+
+	// Worker application runs without HTTP and WebSocket listeners
+	if a.IsWorker() {
+		a.Log().Info("aah go worker running, no HTTP listener")
+		return
+	}
+
 	hl := a.Log().ToGoLogger()
 	hl.SetOutput(ioutil.Discard)
 
@@ -106,9 +129,7 @@ func (a *Application) Start() {
 	}
 
 	a.server.SetKeepAlivesEnabled(a.Config().BoolDefault("server.keep_alive", true))
-	a.writePID()
-
-	go a.listenForHotReload()
+	go a.listenForUpgrade()
 
 	// Unix Socket
 	if strings.HasPrefix(a.HTTPAddress(), "unix") {
@@ -136,7 +157,12 @@ func (a *Application) Start() {
 //
 // Method performs:
 //    - Graceful server shutdown with timeout by `server.timeout.grace_shutdown`
+//    - Stops the supervised workers
+//    - Stops the webhook delivery workers, undelivered ones are moved to dead letters
+//    - Cancels and waits for the app goroutines started via `Application.Go`
+//    - Drains and closes the broker connections
 //    - Publishes `OnPostShutdown` event
+//    - Delivers buffered log entries and closes the log receiver
 //    - Exits program with code 0
 func (a *Application) Shutdown() {
 	// Publish `OnPreShutdown` event
@@ -146,14 +172,27 @@ func (a *Application) Shutdown() {
 	defer cancel()
 
 	a.Log().Warn("aah go server graceful shutdown triggered with timeout of ", a.settings.ShutdownGraceTimeStr)
-	if err := a.server.Shutdown(ctx); err != nil && err != http.ErrServerClosed {
-		a.Log().Error(err)
+	if a.server != nil {
+		if err := a.server.Shutdown(ctx); err != nil && err != http.ErrServerClosed {
+			a.Log().Error(err)
+		}
 	}
 	a.shutdownRedirectServer()
+	a.stopConfigProviderWatch()
+	a.supervisor.shutdown(a.settings.ShutdownGraceTimeout)
+	a.webhookMgr.shutdown(a.settings.ShutdownGraceTimeout)
+	a.goroutines.shutdown(a, a.settings.ShutdownGraceTimeout)
+	a.closeBrokers()
+	a.closeDataSources()
 	a.Log().Info("aah go server shutdown successfully")
 
 	// Publish `OnPostShutdown` event
 	a.EventStore().sortAndPublishSync(&Event{Name: EventOnPostShutdown})
+
+	// Deliver buffered log entries and close the log receiver
+	if l, ok := a.Log().(*log.Logger); ok {
+		_ = l.Close()
+	}
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -180,11 +219,13 @@ func (a *Application) writePID() {
 
 func (a *Application) startUnix() {
 	sockFile := a.HTTPAddress()[5:]
-	if err := os.Remove(sockFile); !os.IsNotExist(err) {
-		a.Log().Fatal(err)
+	if !a.isInherited("unix", sockFile) {
+		if err := os.Remove(sockFile); !os.IsNotExist(err) {
+			a.Log().Fatal(err)
+		}
 	}
 
-	listener, err := net.Listen("unix", sockFile)
+	listener, err := a.listen("unix", sockFile)
 	if err != nil {
 		a.Log().Fatal(err)
 		return
@@ -232,15 +273,28 @@ func (a *Application) startHTTPS() {
 	// start HTTP redirect server if enabled
 	go a.startHTTPRedirect()
 
+	listener, err := a.listen("tcp", a.server.Addr)
+	if err != nil {
+		a.Log().Fatal(err)
+		return
+	}
+	a.trackTLS()
+
 	a.printStartupNote()
-	if err := a.server.ListenAndServeTLS(a.settings.SSLCert, a.settings.SSLKey); err != nil && err != http.ErrServerClosed {
+	if err := a.server.ServeTLS(listener, a.settings.SSLCert, a.settings.SSLKey); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
 
 func (a *Application) startHTTP() {
+	listener, err := a.listen("tcp", a.server.Addr)
+	if err != nil {
+		a.Log().Fatal(err)
+		return
+	}
+
 	a.printStartupNote()
-	if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := a.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
@@ -266,7 +320,6 @@ func (a *Application) startHTTPRedirect() {
 	}
 	redirectCode := cfg.IntDefault(keyPrefix+".code", http.StatusTemporaryRedirect)
 
-	a.Log().Infof("aah go redirect server running on %s:%s", address, fromPort)
 	a.redirectServer = &http.Server{
 		Addr: address + ":" + fromPort,
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -279,7 +332,14 @@ func (a *Application) startHTTPRedirect() {
 		}),
 	}
 
-	if err := a.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	listener, err := a.inheritOrListen("tcp", a.redirectServer.Addr)
+	if err != nil {
+		a.Log().Error(err)
+		return
+	}
+
+	a.Log().Infof("aah go redirect server running on %s:%s", address, fromPort)
+	if err := a.redirectServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
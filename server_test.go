@@ -85,3 +85,43 @@ func TestServerHTTPRedirect(t *testing.T) {
 	assert.Equal(t, 307, resp.StatusCode)
 	assert.True(t, strings.Contains(responseBody(resp), "Temporary Redirect"))
 }
+
+func TestServerStartWorker(t *testing.T) {
+	defer ess.DeleteFiles("webapp1.pid")
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newApp()
+	a.SetBuildInfo(&BuildInfo{BinaryName: "webapp1", Version: "1.0.0"})
+	assert.Nil(t, a.VFS().AddMount(a.VirtualBaseDir(), importPath))
+	a.settings.ImportPath = importPath
+	assert.Nil(t, a.initPath())
+	assert.Nil(t, a.initConfig())
+	a.Config().SetString("type", "worker")
+	a.Config().SetBool("server.access_log.enable", true)
+	assert.Nil(t, a.settings.Refresh(a.Config()))
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initApp())
+
+	assert.True(t, a.IsWorker())
+	assert.False(t, a.settings.AccessLogEnabled)
+	assert.Nil(t, a.Router())
+	assert.Nil(t, a.errorMgr)
+	assert.Nil(t, a.wse)
+	assert.NotNil(t, a.SecurityManager())
+
+	started := make(chan struct{})
+	assert.Nil(t, a.Supervise("report-generator", func(stop <-chan struct{}) error {
+		close(started)
+		<-stop
+		return nil
+	}))
+
+	a.Start()
+	assert.Nil(t, a.server)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Error("supervised worker is not started")
+	}
+	a.Shutdown()
+}
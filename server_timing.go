@@ -0,0 +1,194 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+// Request processing phases measured by aah HTTP engine, refer to
+// `ctx.Timings()`.
+const (
+	TimingRouting = "routing"
+	TimingBinding = "binding"
+	TimingAuth    = "auth"
+	TimingAction  = "action"
+	TimingRender  = "render"
+	TimingWrite   = "write"
+)
+
+// Timing struct holds the duration of request processing phase.
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// TimingStat struct holds the aggregated durations of request processing
+// phase since application start.
+type TimingStat struct {
+	Count int64         `json:"count"`
+	Total time.Duration `json:"total"`
+	Max   time.Duration `json:"max"`
+}
+
+// Avg method returns the average duration of the phase.
+func (ts TimingStat) Avg() time.Duration {
+	if ts.Count == 0 {
+		return 0
+	}
+	return ts.Total / time.Duration(ts.Count)
+}
+
+// Timings method returns the request processing phase durations measured
+// so far in the order of execution. Phases are measured only if
+// `server.timing.enable` is true.
+func (ctx *Context) Timings() []Timing {
+	return ctx.timings
+}
+
+// AddTiming method adds the given duration to the request processing phase,
+// it's useful to measure application phases, for e.g.: `db`, `cache`.
+// Duration is added up if phase already exists.
+//
+// 	start := time.Now()
+// 	rows, err := db.QueryContext(c.GoContext(), query)
+// 	c.AddTiming("db", time.Since(start))
+func (ctx *Context) AddTiming(name string, d time.Duration) {
+	if !ctx.a.settings.ServerTimingEnabled {
+		return
+	}
+	for i := range ctx.timings {
+		if ctx.timings[i].Name == name {
+			ctx.timings[i].Duration += d
+			return
+		}
+	}
+	ctx.timings = append(ctx.timings, Timing{Name: name, Duration: d})
+}
+
+// ServerTimingStats method returns the aggregated request processing phase
+// durations since application start, it could be used for metrics purpose.
+func (a *Application) ServerTimingStats() map[string]TimingStat {
+	a.timingStats.RLock()
+	defer a.timingStats.RUnlock()
+	stats := make(map[string]TimingStat, len(a.timingStats.phases))
+	for k, v := range a.timingStats.phases {
+		stats[k] = v
+	}
+	return stats
+}
+
+// ServerTimingStatsByTag method returns the aggregated request processing
+// phase durations of the routes tagged with given tag, it could be used as
+// metrics label. Refer to route `tags` in the routes.conf.
+func (a *Application) ServerTimingStatsByTag(tag string) map[string]TimingStat {
+	a.timingStats.RLock()
+	defer a.timingStats.RUnlock()
+	stats := make(map[string]TimingStat, len(a.timingStats.tags[tag]))
+	for k, v := range a.timingStats.tags[tag] {
+		stats[k] = v
+	}
+	return stats
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type timingStats struct {
+	sync.RWMutex
+	phases map[string]TimingStat
+	tags   map[string]map[string]TimingStat
+}
+
+func (ts *timingStats) add(timings []Timing, tags []string) {
+	ts.Lock()
+	defer ts.Unlock()
+	if ts.phases == nil {
+		ts.phases = make(map[string]TimingStat)
+		ts.tags = make(map[string]map[string]TimingStat)
+	}
+	addTimings(ts.phases, timings)
+	for _, tag := range tags {
+		if ts.tags[tag] == nil {
+			ts.tags[tag] = make(map[string]TimingStat)
+		}
+		addTimings(ts.tags[tag], timings)
+	}
+}
+
+func addTimings(phases map[string]TimingStat, timings []Timing) {
+	for _, t := range timings {
+		s := phases[t.Name]
+		s.Count++
+		s.Total += t.Duration
+		if t.Duration > s.Max {
+			s.Max = t.Duration
+		}
+		phases[t.Name] = s
+	}
+}
+
+// stopwatch measures the request processing phase, method `Stop` can be
+// called more than once, only the first call is recorded.
+type stopwatch struct {
+	ctx     *Context
+	name    string
+	start   time.Time
+	stopped bool
+}
+
+func (ctx *Context) startTiming(name string) stopwatch {
+	sw := stopwatch{ctx: ctx, name: name}
+	if ctx.a.settings.ServerTimingEnabled {
+		sw.start = time.Now()
+	}
+	return sw
+}
+
+func (sw *stopwatch) Stop() time.Duration {
+	if sw.stopped || sw.start.IsZero() {
+		return 0
+	}
+	sw.stopped = true
+	d := time.Since(sw.start)
+	if d < 0 {
+		d = 0
+	}
+	sw.ctx.AddTiming(sw.name, d)
+	return d
+}
+
+// exclude method excludes the given duration of nested phase from
+// the stopwatch.
+func (sw *stopwatch) exclude(d time.Duration) {
+	if !sw.start.IsZero() {
+		sw.start = sw.start.Add(d)
+	}
+}
+
+// writeServerTiming method sets the `Server-Timing` response header with
+// the measured phases, it has to be called before writing HTTP status code.
+// Hence the `write` phase is not part of the header.
+func (e *HTTPEngine) writeServerTiming(ctx *Context) {
+	if !e.a.settings.ServerTimingHeader || len(ctx.timings) == 0 {
+		return
+	}
+	buf := acquireBuilder()
+	defer releaseBuilder(buf)
+	for i, t := range ctx.timings {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(t.Name)
+		buf.WriteString(";dur=")
+		buf.WriteString(strconv.FormatFloat(float64(t.Duration)/float64(time.Millisecond), 'f', 3, 64))
+	}
+	ctx.Res.Header().Set(ahttp.HeaderServerTiming, buf.String())
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerTiming(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	// disabled by default
+	w := httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get(ahttp.HeaderServerTiming))
+	assert.Equal(t, 0, len(ts.app.ServerTimingStats()))
+
+	ts.app.settings.ServerTimingEnabled = true
+	ts.app.settings.ServerTimingHeader = true
+	defer func() {
+		ts.app.settings.ServerTimingEnabled = false
+		ts.app.settings.ServerTimingHeader = false
+	}()
+
+	var timings []Timing
+	ts.app.HTTPEngine().OnPostReply(func(e *Event) {
+		ctx := e.Data.(*Context)
+		timings = append([]Timing{}, ctx.Timings()...)
+	})
+
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	hdr := w.Header().Get(ahttp.HeaderServerTiming)
+	for _, name := range []string{TimingRouting, TimingBinding, TimingAction, TimingRender} {
+		assert.True(t, strings.Contains(hdr, name+";dur="), name)
+	}
+	assert.False(t, strings.Contains(hdr, TimingWrite))
+
+	var names []string
+	for _, t := range timings {
+		names = append(names, t.Name)
+	}
+	assert.Equal(t, []string{TimingRouting, TimingBinding, TimingAction, TimingRender, TimingWrite}, names)
+
+	stats := ts.app.ServerTimingStats()
+	assert.Equal(t, int64(1), stats[TimingRouting].Count)
+	assert.Equal(t, int64(1), stats[TimingWrite].Count)
+	assert.True(t, stats[TimingAction].Max >= stats[TimingAction].Avg())
+
+	// header disabled, metrics continues
+	ts.app.settings.ServerTimingHeader = false
+	w = httptest.NewRecorder()
+	ts.app.ServeHTTP(w, httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	assert.Equal(t, "", w.Header().Get(ahttp.HeaderServerTiming))
+	assert.Equal(t, int64(2), ts.app.ServerTimingStats()[TimingRouting].Count)
+}
+
+func TestServerTimingStatsByTag(t *testing.T) {
+	a := newApp()
+	a.timingStats.add([]Timing{{Name: TimingAction, Duration: 2 * time.Millisecond}}, []string{"api", "billing"})
+	a.timingStats.add([]Timing{{Name: TimingAction, Duration: 4 * time.Millisecond}}, []string{"api"})
+	a.timingStats.add([]Timing{{Name: TimingAction, Duration: time.Millisecond}}, nil)
+
+	assert.Equal(t, int64(3), a.ServerTimingStats()[TimingAction].Count)
+	api := a.ServerTimingStatsByTag("api")[TimingAction]
+	assert.Equal(t, int64(2), api.Count)
+	assert.Equal(t, 3*time.Millisecond, api.Avg())
+	assert.Equal(t, 4*time.Millisecond, api.Max)
+	assert.Equal(t, int64(1), a.ServerTimingStatsByTag("billing")[TimingAction].Count)
+	assert.Equal(t, 0, len(a.ServerTimingStatsByTag("public")))
+}
+
+func TestServerTimingAddTiming(t *testing.T) {
+	a := newApp()
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(ahttp.MethodGet, "/", nil))
+	ctx.a = a
+	ctx.e = &HTTPEngine{a: a}
+
+	ctx.AddTiming("db", time.Millisecond)
+	assert.Nil(t, ctx.Timings())
+
+	a.settings.ServerTimingEnabled = true
+	a.settings.ServerTimingHeader = true
+	ctx.AddTiming("db", 1500*time.Microsecond)
+	ctx.AddTiming("cache", 250*time.Microsecond)
+	ctx.AddTiming("db", time.Millisecond)
+	assert.Equal(t, []Timing{{Name: "db", Duration: 2500 * time.Microsecond},
+		{Name: "cache", Duration: 250 * time.Microsecond}}, ctx.Timings())
+
+	sw := ctx.startTiming("render")
+	sw.exclude(time.Hour)
+	assert.Equal(t, time.Duration(0), sw.Stop())
+	assert.Equal(t, time.Duration(0), sw.Stop())
+
+	ctx.e.writeServerTiming(ctx)
+	assert.Equal(t, "db;dur=2.500, cache;dur=0.250, render;dur=0.000", ctx.Res.Header().Get(ahttp.HeaderServerTiming))
+
+	assert.Equal(t, time.Duration(0), TimingStat{}.Avg())
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+// singleflight shares the single controller action execution among the
+// concurrent identical GET requests of the route which has
+// `singleflight = true` in the routes.conf. Requests are identical if route,
+// request URI, cookies, subject principal and configured
+// `request.singleflight.vary` header values are same. Rendered reply of the
+// first request is fanned out to the waiting requests.
+//
+// Reply is not shared if it's an error, redirect, streamed, written
+// directly via `Reply().Done()`, it sets cookies or it's HTML reply with
+// Anti-CSRF secret; waiting requests executes the action on their own.
+type singleflight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+	vary  []string
+}
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	res  *flightReply
+	dups int
+}
+
+type flightReply struct {
+	code     int
+	contType string
+	header   http.Header
+	body     []byte
+	rendered bool
+	gzip     bool
+	etag     string
+	modTime  time.Time
+}
+
+func (a *Application) initSingleflight() error {
+	vary, found := a.Config().StringList("request.singleflight.vary")
+	if !found {
+		vary = []string{ahttp.HeaderAccept, ahttp.HeaderAcceptLanguage, ahttp.HeaderAuthorization}
+	}
+	for idx, h := range vary {
+		vary[idx] = http.CanonicalHeaderKey(strings.TrimSpace(h))
+	}
+
+	a.singleflight = &singleflight{
+		calls: make(map[string]*flightCall),
+		vary:  vary,
+	}
+	return nil
+}
+
+// isApplicable method returns true if the route is marked as `singleflight`
+// and it's a GET request.
+func (sf *singleflight) isApplicable(ctx *Context) bool {
+	if sf == nil || ctx.route == nil || !ctx.route.Singleflight {
+		return false
+	}
+	return ctx.Req.Method == ahttp.MethodGet
+}
+
+// do method executes the given action func once for the concurrent identical
+// requests and shares the reply.
+func (sf *singleflight) do(ctx *Context, exec func(ctx *Context)) {
+	key := sf.key(ctx)
+	sf.mu.Lock()
+	if c, found := sf.calls[key]; found {
+		c.dups++
+		sf.mu.Unlock()
+		c.wg.Wait()
+		if c.res == nil {
+			ctx.Log().Debugf("Singleflight: reply is not shareable, executing action for route '%s'", ctx.route.Name)
+			exec(ctx)
+			return
+		}
+		ctx.Log().Debugf("Singleflight: sharing reply of route '%s'", ctx.route.Name)
+		c.res.apply(ctx)
+		return
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	sf.calls[key] = c
+	sf.mu.Unlock()
+
+	// Waiting requests gets released even if action panics, they execute
+	// the action on their own
+	defer func() {
+		sf.mu.Lock()
+		delete(sf.calls, key)
+		sf.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	header := cloneHeader(ctx.Res.Header())
+	exec(ctx)
+	c.res = sf.capture(ctx, header)
+	if c.res != nil {
+		c.res.apply(ctx)
+	}
+}
+
+// capture method renders the reply of the action and returns it, nil
+// returned if reply is not shareable.
+func (sf *singleflight) capture(ctx *Context, header http.Header) *flightReply {
+	re := ctx.Reply()
+	if re.err != nil || re.done || re.redirect || len(re.cookies) > 0 || re.isStream() {
+		return nil
+	}
+
+	fr := &flightReply{
+		code:     re.Code,
+		contType: re.ContType,
+		header:   make(http.Header),
+		gzip:     re.gzip,
+		etag:     re.etag,
+		modTime:  re.modTime,
+	}
+	if len(fr.contType) == 0 {
+		fr.contType = ctx.detectContentType()
+	}
+
+	// Anti-CSRF secret is per client, view renders its token
+	if _, found := ctx.viewArgs[keyAntiCSRF]; found && ahttp.ContentTypeHTML.IsEqual(fr.contType) {
+		return nil
+	}
+	for k, v := range ctx.Res.Header() {
+		if k == ahttp.HeaderSetCookie || strings.Join(header[k], ",") == strings.Join(v, ",") {
+			continue
+		}
+		fr.header[k] = append([]string(nil), v...)
+	}
+
+	if ctx.a.viewMgr != nil && re.isHTML() {
+		ctx.a.viewMgr.resolve(ctx)
+	}
+	if re.Rdr != nil {
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+		if err := re.Rdr.Render(buf); err != nil {
+			ctx.Log().Errorf("Singleflight: %s", err)
+			return nil
+		}
+		fr.body = append([]byte(nil), buf.Bytes()...)
+		fr.rendered = true
+	}
+	return fr
+}
+
+func (sf *singleflight) key(ctx *Context) string {
+	b := acquireBuilder()
+	defer releaseBuilder(b)
+	b.WriteString(ctx.route.Name)
+	b.WriteByte(' ')
+	b.WriteString(ctx.Req.URL().RequestURI())
+	for _, h := range sf.vary {
+		b.WriteByte('\n')
+		b.WriteString(strconv.Quote(strings.Join(ctx.Req.Header[h], ",")))
+	}
+
+	// client state such as session, flash and Anti-CSRF secret are carried
+	// by cookies, reply is not shared across the clients
+	b.WriteByte('\n')
+	b.WriteString(strconv.Quote(strings.Join(ctx.Req.Header[ahttp.HeaderCookie], ";")))
+	if ctx.subject != nil && ctx.subject.IsAuthenticated() {
+		if p := ctx.subject.PrimaryPrincipal(); p != nil {
+			b.WriteByte('\n')
+			b.WriteString(strconv.Quote(p.Value))
+		}
+	}
+	return b.String()
+}
+
+// apply method sets the shared reply into given context reply.
+func (fr *flightReply) apply(ctx *Context) {
+	for k, v := range fr.header {
+		ctx.Res.Header()[k] = append([]string(nil), v...)
+	}
+
+	re := ctx.Reply().Status(fr.code).ContentType(fr.contType)
+	re.gzip, re.etag, re.modTime = fr.gzip, fr.etag, fr.modTime
+	if fr.rendered {
+		re.Render(RenderFunc(func(w io.Writer) error {
+			_, err := w.Write(fr.body)
+			return err
+		}))
+	}
+}
+
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
@@ -0,0 +1,176 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleflight(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	route := a.Router().Lookup("localhost:8080").LookupByName("get_xml")
+	assert.True(t, route.Singleflight)
+	assert.False(t, a.Router().Lookup("localhost:8080").LookupByName("text_get").Singleflight)
+	sf := a.singleflight
+	assert.Equal(t, []string{"Accept", "Accept-Language", "Authorization"}, sf.vary)
+
+	newCtx := func(accept, inm string) (*Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/get-xml?page=1", nil)
+		req.Header.Set(ahttp.HeaderAccept, accept)
+		if len(inm) > 0 {
+			req.Header.Set(ahttp.HeaderIfNoneMatch, inm)
+		}
+		w := httptest.NewRecorder()
+		ctx := newContext(w, req)
+		ctx.a, ctx.route = a, route
+		return ctx, w
+	}
+
+	var executed int32
+	release := make(chan struct{})
+	exec := func(ctx *Context) {
+		atomic.AddInt32(&executed, 1)
+		<-release
+		ctx.Reply().Header("X-Total-Count", "10").ETag("v1").JSON(Data{"page": 1})
+	}
+
+	leader, leaderW := newCtx(ahttp.ContentTypeJSON.String(), "")
+	assert.True(t, sf.isApplicable(leader))
+
+	var wg sync.WaitGroup
+	run := func(ctx *Context) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sf.do(ctx, exec)
+		}()
+	}
+	run(leader)
+	key := sf.key(leader)
+	waitFor := func(cond func(c *flightCall) bool) {
+		for i := 0; i < 500; i++ {
+			sf.mu.Lock()
+			c := sf.calls[key]
+			ok := c != nil && cond(c)
+			sf.mu.Unlock()
+			if ok {
+				return
+			}
+			time.Sleep(2 * time.Millisecond)
+		}
+		t.Fatal("singleflight call is not in expected state")
+	}
+	waitFor(func(c *flightCall) bool { return true })
+
+	f1, f1W := newCtx(ahttp.ContentTypeJSON.String(), "")
+	f2, f2W := newCtx(ahttp.ContentTypeJSON.String(), `"v1"`)
+	other, otherW := newCtx(ahttp.ContentTypeXML.String(), "")
+	run(f1)
+	run(f2)
+	waitFor(func(c *flightCall) bool { return c.dups == 2 })
+	run(other)
+	close(release)
+	wg.Wait()
+
+	// leader and different `Accept` header request executes the action
+	assert.Equal(t, int32(2), atomic.LoadInt32(&executed))
+	assert.Equal(t, 0, len(sf.calls))
+
+	for _, ctx := range []*Context{leader, f1, f2, other} {
+		a.he.writeReply(ctx)
+	}
+	assert.Equal(t, http.StatusOK, leaderW.Code)
+	assert.Equal(t, "{\"page\":1}\n", leaderW.Body.String())
+	assert.Equal(t, leaderW.Body.String(), f1W.Body.String())
+	assert.Equal(t, "10", f1W.Header().Get("X-Total-Count"))
+	assert.Equal(t, `"v1"`, f1W.Header().Get(ahttp.HeaderETag))
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), f1W.Header().Get(ahttp.HeaderContentType))
+	assert.Equal(t, http.StatusNotModified, f2W.Code)
+	assert.Equal(t, http.StatusOK, otherW.Code)
+
+	// not shareable reply
+	ctx, _ := newCtx(ahttp.ContentTypeJSON.String(), "")
+	ctx.Reply().Cookie(&http.Cookie{Name: "visited", Value: "1"}).JSON(Data{"page": 1})
+	assert.Nil(t, sf.capture(ctx, http.Header{}))
+
+	ctx, _ = newCtx(ahttp.ContentTypeJSON.String(), "")
+	ctx.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
+	assert.Nil(t, sf.capture(ctx, http.Header{}))
+
+	ctx, _ = newCtx(ahttp.ContentTypeHTML.String(), "")
+	ctx.AddViewArg(keyAntiCSRF, []byte("secret"))
+	ctx.Reply().ContentType(ahttp.ContentTypeHTML.String()).Text("<form></form>")
+	assert.Nil(t, sf.capture(ctx, http.Header{}))
+
+	// not applicable
+	req := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/get-xml", nil)
+	ctx = newContext(httptest.NewRecorder(), req)
+	ctx.a, ctx.route = a, route
+	assert.False(t, sf.isApplicable(ctx))
+}
+
+func TestSingleflightAntiCSRFPerClient(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	route := a.Router().Lookup("localhost:8080").LookupByName("get_xml")
+	sf := a.singleflight
+	newCtx := func(cookie string) (*Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/get-xml", nil)
+		req.Header.Set(ahttp.HeaderCookie, cookie)
+		w := httptest.NewRecorder()
+		ctx := newContext(w, req)
+		ctx.a, ctx.route = a, route
+		return ctx, w
+	}
+
+	release := make(chan struct{})
+	var executed int32
+	exec := func(ctx *Context) {
+		atomic.AddInt32(&executed, 1)
+		<-release
+		c, _ := ctx.Req.Cookie("aah_anti_csrf")
+		ctx.Reply().Text("token:%s", c.Value)
+	}
+
+	c1, w1 := newCtx("aah_anti_csrf=client1")
+	c2, w2 := newCtx("aah_anti_csrf=client2")
+	assert.NotEqual(t, sf.key(c1), sf.key(c2))
+
+	var wg sync.WaitGroup
+	for _, ctx := range []*Context{c1, c2} {
+		wg.Add(1)
+		go func(ctx *Context) {
+			defer wg.Done()
+			sf.do(ctx, exec)
+		}(ctx)
+	}
+	for i := 0; i < 500 && atomic.LoadInt32(&executed) < 2; i++ {
+		time.Sleep(2 * time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&executed))
+	a.he.writeReply(c1)
+	a.he.writeReply(c2)
+	assert.Equal(t, "token:client1", w1.Body.String())
+	assert.Equal(t, "token:client2", w2.Body.String())
+}
@@ -6,6 +6,7 @@ package aah
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -16,6 +17,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
@@ -29,12 +31,24 @@ import (
 
 func (a *Application) initStatic() error {
 	a.staticMgr = &staticManager{
-		a:                     a,
-		mimeCacheHdrMap:       make(map[string]string),
-		noCacheHdrValue:       "no-cache, no-store, must-revalidate",
-		dirListDateTimeFormat: "2006-01-02 15:04:05",
+		a:               a,
+		mimeCacheHdrMap: make(map[string]string),
+		noCacheHdrValue: "no-cache, no-store, must-revalidate",
 	}
 
+	// directory listing configuration is from `static.dir_listing.*`
+	a.staticMgr.dirListShowHidden = a.Config().BoolDefault("static.dir_listing.show_hidden", false)
+	a.staticMgr.dirListDateTimeFormat = a.Config().StringDefault("static.dir_listing.date_format", "2006-01-02 15:04:05")
+	if err := a.staticMgr.loadDirListTemplate(); err != nil {
+		return err
+	}
+
+	// serve precompressed sidecar files `.br` and `.gz`
+	a.staticMgr.precompressed = a.Config().BoolDefault("static.precompressed", true)
+
+	// strong ETag from file content checksum
+	a.staticMgr.etag = a.Config().BoolDefault("static.etag", true)
+
 	// default cache header
 	a.staticMgr.defaultCacheHdr = a.Config().StringDefault("cache.static.default_cache_control", "max-age=31536000, public")
 
@@ -59,9 +73,30 @@ type staticManager struct {
 	defaultCacheHdr       string
 	noCacheHdrValue       string
 	dirListDateTimeFormat string
+	dirListShowHidden     bool
+	dirListTmpl           *template.Template
+	precompressed         bool
+	etag                  bool
 	mimeCacheHdrMap       map[string]string
 }
 
+// dirListing struct holds the directory listing info, it is the data of
+// listing template and JSON response.
+type dirListing struct {
+	Path    string          `json:"path"`
+	Sort    string          `json:"sort"`
+	Order   string          `json:"order"`
+	Entries []*dirListEntry `json:"entries"`
+}
+
+type dirListEntry struct {
+	Name    string    `json:"name"`
+	URL     string    `json:"url"`
+	IsDir   bool      `json:"is_dir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
 func (s *staticManager) Serve(ctx *Context) error {
 	// TODO static assets Dynamic minify for JS and CSS for non-dev profile
 
@@ -85,7 +120,12 @@ func (s *staticManager) Serve(ctx *Context) error {
 
 	gf, ok := f.(vfs.Gziper)
 	var fr io.ReadSeeker = f
-	if s.a.settings.GzipEnabled && ctx.Req.IsGzipAccepted {
+	if pf, encoding := s.openPrecompressed(ctx, fi); pf != nil {
+		defer ess.CloseQuietly(pf)
+		ctx.Res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptEncoding)
+		ctx.Res.Header().Add(ahttp.HeaderContentEncoding, encoding)
+		fr = pf
+	} else if ctx.Req.IsGzipAccepted && s.a.he.compressEnabled(ctx) {
 		if ok && gf.IsGzip() {
 			ctx.Res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptEncoding)
 			ctx.Res.Header().Add(ahttp.HeaderContentEncoding, gzipContentEncoding)
@@ -113,6 +153,10 @@ func (s *staticManager) Serve(ctx *Context) error {
 			}
 		}
 
+		if s.etag {
+			s.setETag(ctx)
+		}
+
 		// 'OnPreReply' server extension point
 		s.a.he.publishOnPreReplyEvent(ctx)
 
@@ -130,7 +174,7 @@ func (s *staticManager) Serve(ctx *Context) error {
 	if fi.Mode().IsDir() && ctx.route.ListDir {
 		// redirect if the directory name doesn't end in a slash
 		if ctx.Req.Path[len(ctx.Req.Path)-1] != '/' {
-			ctx.Log().Debugf("redirecting to dir: %s", ctx.Req.Path+"/")
+			ctx.moduleLog("static").Debugf("redirecting to dir: %s", ctx.Req.Path+"/")
 			http.Redirect(ctx.Res, ctx.Req.Unwrap(), path.Base(ctx.Req.Path)+"/", http.StatusMovedPermanently)
 			return nil
 		}
@@ -138,7 +182,7 @@ func (s *staticManager) Serve(ctx *Context) error {
 		// 'OnPreReply' server extension point
 		s.a.he.publishOnPreReplyEvent(ctx)
 
-		s.listDirectory(ctx.Res, ctx.Req.Unwrap(), f)
+		s.listDirectory(ctx, f)
 
 		// 'OnAfterReply' server extension point
 		s.a.he.publishOnPostReplyEvent(ctx)
@@ -146,25 +190,144 @@ func (s *staticManager) Serve(ctx *Context) error {
 	}
 
 	// Flow reached here it means directory listing is not allowed
-	ctx.Log().Warnf("Directory listing not allowed: %s", ctx.Req.Path)
+	ctx.moduleLog("static").Warnf("Directory listing not allowed: %s", ctx.Req.Path)
 	ctx.Res.WriteHeader(http.StatusForbidden)
 	fmt.Fprintf(ctx.Res, "403 Directory listing not allowed")
 
 	return nil
 }
 
+// ServeSite method serves the domain static site for the request, it resolves
+// the index file, clean URLs and falls back to site not found page.
+func (s *staticManager) ServeSite(ctx *Context) error {
+	site := ctx.domain.StaticSite
+	ctx.route = site.Route
+	for _, candidate := range site.Candidates(ctx.Req.Path) {
+		fi, err := s.a.VFS().Stat(s.siteResource(site.Dir, candidate))
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		// redirect to directory path, so that relative links are resolved correctly
+		if site.IsIndex(candidate) && !strings.HasSuffix(ctx.Req.Path, "/") &&
+			path.Base(ctx.Req.Path) != site.Index {
+			target := ctx.Req.Path + "/"
+			if len(ctx.Req.URL().RawQuery) > 0 {
+				target += "?" + ctx.Req.URL().RawQuery
+			}
+			ctx.moduleLog("static").Debugf("redirecting to dir: %s", target)
+			http.Redirect(ctx.Res, ctx.Req.Unwrap(), target, http.StatusMovedPermanently)
+			return nil
+		}
+
+		ctx.Req.URLParams = ahttp.URLParams{{Key: "filepath", Value: candidate}}
+		return s.Serve(ctx)
+	}
+	return s.serveSiteNotFound(ctx)
+}
+
 func (s *staticManager) open(ctx *Context) (vfs.File, error) {
+	resource := s.resource(ctx)
+	ctx.moduleLog("static").Tracef("Static resource: %s", resource)
+	return s.a.VFS().Open(resource)
+}
+
+func (s *staticManager) resource(ctx *Context) string {
 	var filePath string
 	if ctx.route.IsFile() { // this is configured value from routes.conf
 		filePath = parseCacheBustPart(ctx.route.File, s.a.BuildInfo().Version)
 	} else {
 		filePath = parseCacheBustPart(ctx.Req.PathValue("filepath"), s.a.BuildInfo().Version)
 	}
+	return filepath.ToSlash(path.Join(s.a.VirtualBaseDir(), ctx.route.Dir, filePath))
+}
 
-	resource := filepath.ToSlash(path.Join(s.a.VirtualBaseDir(), ctx.route.Dir, filePath))
-	ctx.Log().Tracef("Static resource: %s", resource)
+// openPrecompressed method opens the precompressed sidecar file (`.br` or
+// `.gz`) of the static file as per request `Accept-Encoding` preference, it
+// returns nil if sidecar file does not exists or not accepted.
+func (s *staticManager) openPrecompressed(ctx *Context, fi os.FileInfo) (vfs.File, string) {
+	if !s.precompressed || !fi.Mode().IsRegular() || !s.a.he.compressEnabled(ctx) {
+		return nil, ""
+	}
 
-	return s.a.VFS().Open(resource)
+	var encodings ahttp.AcceptSpecs
+	for _, spec := range ahttp.ParseAcceptEncoding(ctx.Req.Unwrap()) {
+		if _, found := precompressedExts[spec.Value]; found && spec.Q > 0 {
+			encodings = append(encodings, spec)
+		}
+	}
+	// brotli is preferred on same quality factor
+	sort.SliceStable(encodings, func(i, j int) bool {
+		if encodings[i].Q != encodings[j].Q {
+			return encodings[i].Q > encodings[j].Q
+		}
+		return encodings[i].Value == "br"
+	})
+
+	resource := s.resource(ctx)
+	for _, spec := range encodings {
+		ext := precompressedExts[spec.Value]
+		f, err := s.a.VFS().Open(resource + ext)
+		if err != nil {
+			continue
+		}
+		if sfi, err := f.Stat(); err == nil && sfi.Mode().IsRegular() {
+			ctx.moduleLog("static").Tracef("Static resource precompressed: %s", resource+ext)
+			return f, spec.Value
+		}
+		ess.CloseQuietly(f)
+	}
+	return nil, ""
+}
+
+// setETag method sets the strong `ETag` header from the static file checksum,
+// content encoding is suffixed since representation differs.
+func (s *staticManager) setETag(ctx *Context) {
+	sum, err := s.a.VFS().Checksum(s.resource(ctx))
+	if err != nil {
+		ctx.moduleLog("static").Tracef("Unable to compute checksum: %v", err)
+		return
+	}
+	etag := sum[:32]
+	if encoding := ctx.Res.Header().Get(ahttp.HeaderContentEncoding); len(encoding) > 0 {
+		etag += "-" + encoding
+	}
+	ctx.Res.Header().Set(ahttp.HeaderETag, `"`+etag+`"`)
+}
+
+func (s *staticManager) siteResource(dir, name string) string {
+	return filepath.ToSlash(path.Join(s.a.VirtualBaseDir(), dir, name))
+}
+
+// serveSiteNotFound method writes the static site not found page with status
+// code 404, it returns `errFileNotFound` if page does not exists.
+func (s *staticManager) serveSiteNotFound(ctx *Context) error {
+	site := ctx.domain.StaticSite
+	f, err := s.a.VFS().Open(s.siteResource(site.Dir, site.NotFound))
+	if err != nil {
+		return errFileNotFound
+	}
+	defer ess.CloseQuietly(f)
+
+	ctx.moduleLog("static").Warnf("Static site file not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
+	ctx.writeHeaders()
+	ctx.Res.Header().Set(ahttp.HeaderContentType, ahttp.ContentTypeHTML.String())
+	ctx.Res.Header().Set(ahttp.HeaderCacheControl, s.noCacheHdrValue)
+
+	// 'OnPreReply' server extension point
+	s.a.he.publishOnPreReplyEvent(ctx)
+
+	// 'OnHeaderReply' HTTP event
+	s.a.he.publishOnHeaderReplyEvent(ctx.Res.Header())
+
+	ctx.Res.WriteHeader(http.StatusNotFound)
+	if ctx.Req.Method != ahttp.MethodHead {
+		_, _ = io.Copy(ctx.Res, f)
+	}
+
+	// 'OnAfterReply' server extension point
+	s.a.he.publishOnPostReplyEvent(ctx)
+	return nil
 }
 
 func (s *staticManager) cacheHeader(contentType string) string {
@@ -174,46 +337,111 @@ func (s *staticManager) cacheHeader(contentType string) string {
 	return s.defaultCacheHdr
 }
 
-// listDirectory method compose directory listing response
-func (s *staticManager) listDirectory(res http.ResponseWriter, req *http.Request, f http.File) {
+// listDirectory method compose directory listing response, it replies JSON
+// if request accepts `application/json` otherwise HTML using listing template.
+func (s *staticManager) listDirectory(ctx *Context, f http.File) {
+	res := ctx.Res
 	dirs, err := f.Readdir(-1)
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(res, "Error reading directory")
 		return
 	}
-	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() })
 
+	listing := s.newDirListing(ctx.Req, dirs)
+	if ctx.Req.AcceptContentType().Mime == ahttp.ContentTypeJSON.Mime {
+		res.Header().Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+		_ = json.NewEncoder(res).Encode(listing)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err = s.dirListTmpl.Execute(buf, listing); err != nil {
+		ctx.moduleLog("static").Errorf("Directory listing template error: %s", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(res, "Error rendering directory listing")
+		return
+	}
 	res.Header().Set(ahttp.HeaderContentType, ahttp.ContentTypeHTML.String())
-	fmt.Fprintf(res, "<html>\n")
-	fmt.Fprintf(res, "<head><title>Listing of %s</title></head>\n", req.URL.Path)
-	fmt.Fprintf(res, "<body bgcolor=\"white\">\n")
-	fmt.Fprintf(res, "<h1>Listing of %s</h1><hr>\n", req.URL.Path)
-	fmt.Fprintf(res, "<pre><table border=\"0\">\n")
-	fmt.Fprintf(res, "<tr><td collapse=\"2\"><a href=\"../\">../</a></td></tr>\n")
+	_, _ = buf.WriteTo(res)
+}
+
+// newDirListing method creates the directory listing for given directory
+// entries, hidden files are filtered and sorted by query params `sort`
+// (name, size, time) and `order` (asc, desc).
+func (s *staticManager) newDirListing(req *ahttp.Request, dirs []os.FileInfo) *dirListing {
+	listing := &dirListing{
+		Path:    req.URL().Path,
+		Sort:    req.QueryValue("sort"),
+		Order:   req.QueryValue("order"),
+		Entries: make([]*dirListEntry, 0, len(dirs)),
+	}
 	for _, d := range dirs {
 		name := d.Name()
+		if !s.dirListShowHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
 		if d.IsDir() {
 			name += "/"
 		}
 		// name may contain '?' or '#', which must be escaped to remain
 		// part of the URL path, and not indicate the start of a query
 		// string or fragment.
-		url := url.URL{Path: name}
-		fmt.Fprintf(res, "<tr><td><a href=\"%s\">%s</a></td><td width=\"200px\" align=\"right\">%s</td></tr>\n",
-			url.String(),
-			template.HTMLEscapeString(name),
-			d.ModTime().Format(s.dirListDateTimeFormat),
-		)
+		u := url.URL{Path: name}
+		listing.Entries = append(listing.Entries, &dirListEntry{
+			Name:    name,
+			URL:     u.String(),
+			IsDir:   d.IsDir(),
+			Size:    d.Size(),
+			ModTime: d.ModTime(),
+		})
+	}
+
+	var less func(i, j int) bool
+	switch listing.Sort {
+	case "size":
+		less = func(i, j int) bool { return listing.Entries[i].Size < listing.Entries[j].Size }
+	case "time":
+		less = func(i, j int) bool { return listing.Entries[i].ModTime.Before(listing.Entries[j].ModTime) }
+	default:
+		listing.Sort = "name"
+		less = func(i, j int) bool { return listing.Entries[i].Name < listing.Entries[j].Name }
+	}
+	if listing.Order == "desc" {
+		sort.SliceStable(listing.Entries, func(i, j int) bool { return less(j, i) })
+	} else {
+		listing.Order = "asc"
+		sort.SliceStable(listing.Entries, less)
 	}
-	fmt.Fprintf(res, "</table></pre>\n")
-	fmt.Fprintf(res, "<hr></body>\n")
-	fmt.Fprintf(res, "</html>\n")
+	return listing
+}
+
+// loadDirListTemplate method loads the directory listing template from
+// `views/static_listing.html` if exists otherwise default template.
+func (s *staticManager) loadDirListTemplate() error {
+	tmplStr := defaultDirListTemplate
+	tmplFile := path.Join(s.a.VirtualBaseDir(), "views", "static_listing.html")
+	if s.a.VFS().IsExists(tmplFile) {
+		b, err := s.a.VFS().ReadFile(tmplFile)
+		if err != nil {
+			return err
+		}
+		tmplStr = string(b)
+	}
+
+	tmpl, err := template.New("static_listing").Funcs(template.FuncMap{
+		"formattime": func(t time.Time) string { return t.Format(s.dirListDateTimeFormat) },
+	}).Parse(tmplStr)
+	if err != nil {
+		return fmt.Errorf("aah: static listing template: %s", err)
+	}
+	s.dirListTmpl = tmpl
+	return nil
 }
 
 func (s *staticManager) writeError(res ahttp.ResponseWriter, req *ahttp.Request, err error) {
 	if os.IsPermission(err) {
-		s.a.Log().Warnf("Static file permission issue: %s", req.Path)
+		s.a.ModuleLog("static").Warnf("Static file permission issue: %s", req.Path)
 		res.WriteHeader(http.StatusForbidden)
 		fmt.Fprintf(res, "403 Forbidden")
 	} else {
@@ -238,3 +466,23 @@ func wrapGzipWriter(res ahttp.ResponseWriter) ahttp.ResponseWriter {
 	res.Header().Del(ahttp.HeaderContentLength)
 	return ahttp.WrapGzipWriter(res)
 }
+
+// precompressedExts is map of content encoding and its sidecar file extension.
+var precompressedExts = map[string]string{
+	"br":                ".br",
+	gzipContentEncoding: ".gz",
+}
+
+const defaultDirListTemplate = `<html>
+<head><title>Listing of {{ .Path }}</title></head>
+<body bgcolor="white">
+<h1>Listing of {{ .Path }}</h1><hr>
+<pre><table border="0">
+<tr><td collapse="2"><a href="../">../</a></td></tr>
+{{ range .Entries -}}
+<tr><td><a href="{{ .URL }}">{{ .Name }}</a></td><td width="200px" align="right">{{ formattime .ModTime }}</td></tr>
+{{ end -}}
+</table></pre>
+<hr></body>
+</html>
+`
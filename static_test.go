@@ -6,6 +6,7 @@ package aah
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -13,9 +14,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/internal/util"
+	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,8 +56,30 @@ func TestStaticFilesDelivery(t *testing.T) {
 	assert.True(t, strings.Contains(body, "<title>Listing of /assets/</title>"))
 	assert.True(t, strings.Contains(body, "<h1>Listing of /assets/</h1><hr>"))
 	assert.True(t, strings.Contains(body, `<a href="robots.txt">robots.txt</a>`))
+	assert.False(t, strings.Contains(body, ".hidden-file"))
 	assert.Equal(t, "", resp.Header.Get(ahttp.HeaderCacheControl))
 
+	// Directory Listing JSON - /assets/?sort=name&order=desc
+	t.Log("Directory Listing JSON - /assets/?sort=name&order=desc")
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/assets/?sort=name&order=desc", nil)
+	req.Header.Set(ahttp.HeaderAccept, ahttp.ContentTypeJSON.Mime)
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get(ahttp.HeaderContentType), ahttp.ContentTypeJSON.Mime))
+	var listing dirListing
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&listing))
+	assert.Equal(t, "/assets/", listing.Path)
+	assert.Equal(t, "name", listing.Sort)
+	assert.Equal(t, "desc", listing.Order)
+	names := make([]string, 0)
+	for _, e := range listing.Entries {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"robots.txt", "js/", "img/", "css/"}, names)
+	assert.True(t, listing.Entries[2].IsDir)
+	assert.Equal(t, "img/", listing.Entries[2].URL)
+
 	// Static File - /assets/img/aah-framework-logo.png
 	t.Log("Static File - /assets/img/aah-framework-logo.png")
 	resp, err = httpClient.Get(ts.URL + "/assets/img/aah-framework-logo.png")
@@ -63,6 +88,16 @@ func TestStaticFilesDelivery(t *testing.T) {
 	assert.Equal(t, "image/png", resp.Header.Get(ahttp.HeaderContentType))
 	assert.Equal(t, "6990", resp.Header.Get(ahttp.HeaderContentLength))
 	assert.Equal(t, "no-cache, no-store, must-revalidate", resp.Header.Get(ahttp.HeaderCacheControl))
+	etag := resp.Header.Get(ahttp.HeaderETag)
+	assert.Equal(t, 34, len(etag))
+
+	// Conditional request using checksum ETag
+	t.Log("Conditional request - /assets/img/aah-framework-logo.png")
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/assets/img/aah-framework-logo.png", nil)
+	req.Header.Set(ahttp.HeaderIfNoneMatch, etag)
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
 
 	// Static File - /assets/img/notfound/file.txt
 	t.Log("Static File - /assets/img/notfound/file.txt")
@@ -72,6 +107,186 @@ func TestStaticFilesDelivery(t *testing.T) {
 	assert.Equal(t, "0", resp.Header.Get(ahttp.HeaderContentLength))
 }
 
+func TestStaticPrecompressedDelivery(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	httpClient := new(http.Client)
+	get := func(target, acceptEncoding string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+target, nil)
+		req.Header.Set(ahttp.HeaderAcceptEncoding, acceptEncoding)
+		resp, err := httpClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	gzBytes, _ := ioutil.ReadFile(filepath.Join(importPath, "static", "js", "aah.js.gz"))
+	jsBytes, _ := ioutil.ReadFile(filepath.Join(importPath, "static", "js", "aah.js"))
+
+	// brotli preferred on same quality
+	resp := get("/assets/js/aah.js", "gzip, deflate, br")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "br", resp.Header.Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, ahttp.HeaderAcceptEncoding, resp.Header.Get(ahttp.HeaderVary))
+	assert.True(t, strings.HasPrefix(resp.Header.Get(ahttp.HeaderContentType), "application/javascript"))
+	assert.Equal(t, "fake-brotli-aah.js", responseBody(resp))
+
+	// quality factor
+	resp = get("/assets/js/aah.js", "br;q=0.5, gzip")
+	assert.Equal(t, "gzip", resp.Header.Get(ahttp.HeaderContentEncoding))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, gzBytes, body)
+
+	resp = get("/assets/js/aah.js", "br;q=0, gzip;q=0")
+	assert.Equal(t, "", resp.Header.Get(ahttp.HeaderContentEncoding))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, jsBytes, body)
+
+	// no sidecar files
+	resp = get("/assets/css/aah.css", "br")
+	assert.Equal(t, "", resp.Header.Get(ahttp.HeaderContentEncoding))
+
+	// disabled
+	ts.app.staticMgr.precompressed = false
+	resp = get("/assets/js/aah.js", "br")
+	assert.Equal(t, "", resp.Header.Get(ahttp.HeaderContentEncoding))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, jsBytes, body)
+}
+
+func TestStaticSiteDelivery(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	domain := ts.app.Router().RootDomain()
+	domain.StaticSite = &router.StaticSite{
+		CleanURLs: true,
+		Dir:       "sites/docs",
+		Index:     "index.html",
+		NotFound:  "404.html",
+		Route:     &router.Route{Name: "static_site__aah", Path: "/*filepath", Method: ahttp.MethodGet, IsStatic: true, Dir: "sites/docs"},
+	}
+	defer func() { domain.StaticSite = nil }()
+
+	httpClient := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	// defined routes takes priority
+	resp, err := httpClient.Get(ts.URL + "/get-text.html")
+	assert.Nil(t, err)
+	assert.Equal(t, "This is text render response", responseBody(resp))
+
+	// clean URL
+	resp, err = httpClient.Get(ts.URL + "/guide")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.Contains(responseBody(resp), "Docs guide"))
+
+	resp, err = httpClient.Get(ts.URL + "/guide.html")
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(responseBody(resp), "Docs guide"))
+
+	// directory index
+	resp, err = httpClient.Get(ts.URL + "/api?v=1")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/api/?v=1", resp.Header.Get(ahttp.HeaderLocation))
+
+	resp, err = httpClient.Get(ts.URL + "/api/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.Contains(responseBody(resp), "API reference"))
+
+	// not found page
+	resp, err = httpClient.Get(ts.URL + "/not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.True(t, strings.Contains(responseBody(resp), "Docs page not found"))
+
+	// not found page does not exists
+	domain.StaticSite.NotFound = "missing-404.html"
+	resp, err = httpClient.Get(ts.URL + "/not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// static site serves only GET and HEAD
+	resp, err = httpClient.Post(ts.URL+"/guide", ahttp.ContentTypePlainText.String(), strings.NewReader("test"))
+	assert.Nil(t, err)
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestStaticDirListing(t *testing.T) {
+	now := time.Now()
+	dirs := []os.FileInfo{
+		testFileInfo{name: "b.txt", size: 30, modTime: now},
+		testFileInfo{name: ".env", size: 10, modTime: now},
+		testFileInfo{name: "a#1.txt", size: 20, modTime: now.Add(time.Hour)},
+		testFileInfo{name: "docs", isDir: true, modTime: now.Add(-time.Hour)},
+	}
+	newReq := func(target string) *ahttp.Request {
+		return ahttp.AcquireRequest(httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	sm := &staticManager{}
+	listing := sm.newDirListing(newReq("/files/?sort=size"), dirs)
+	assert.Equal(t, "size", listing.Sort)
+	assert.Equal(t, "asc", listing.Order)
+	assert.Equal(t, 3, len(listing.Entries))
+	assert.Equal(t, "docs/", listing.Entries[0].Name)
+	assert.Equal(t, "a%231.txt", listing.Entries[1].URL)
+
+	listing = sm.newDirListing(newReq("/files/?sort=time&order=desc"), dirs)
+	assert.Equal(t, "a#1.txt", listing.Entries[0].Name)
+	assert.Equal(t, "docs/", listing.Entries[2].Name)
+
+	sm.dirListShowHidden = true
+	listing = sm.newDirListing(newReq("/files/?sort=unknown"), dirs)
+	assert.Equal(t, "name", listing.Sort)
+	assert.Equal(t, []string{".env", "a#1.txt", "b.txt", "docs/"}, []string{listing.Entries[0].Name,
+		listing.Entries[1].Name, listing.Entries[2].Name, listing.Entries[3].Name})
+
+	// custom listing template
+	tmpDir, _ := ioutil.TempDir("", "static_listing")
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	_ = os.MkdirAll(filepath.Join(tmpDir, "views"), 0755)
+
+	a := newApp()
+	sm = &staticManager{a: a, dirListDateTimeFormat: "2006-01-02"}
+	assert.Nil(t, a.VFS().AddMount(a.VirtualBaseDir(), tmpDir))
+	assert.Nil(t, sm.loadDirListTemplate())
+	assert.Equal(t, "static_listing", sm.dirListTmpl.Name())
+
+	_ = ioutil.WriteFile(filepath.Join(tmpDir, "views", "static_listing.html"),
+		[]byte(`{{ .Path }}{{ range .Entries }}|{{ .Name }} {{ formattime .ModTime }}{{ end }}`), 0644)
+	assert.Nil(t, sm.loadDirListTemplate())
+	buf := new(bytes.Buffer)
+	assert.Nil(t, sm.dirListTmpl.Execute(buf, sm.newDirListing(newReq("/files/"), dirs[:1])))
+	assert.Equal(t, "/files/|b.txt "+now.Format("2006-01-02"), buf.String())
+
+	_ = ioutil.WriteFile(filepath.Join(tmpDir, "views", "static_listing.html"), []byte(`{{ .Path `), 0644)
+	err := sm.loadDirListTemplate()
+	assert.True(t, strings.HasPrefix(err.Error(), "aah: static listing template:"))
+}
+
+type testFileInfo struct {
+	name    string
+	size    int64
+	isDir   bool
+	modTime time.Time
+}
+
+func (fi testFileInfo) Name() string       { return fi.name }
+func (fi testFileInfo) Size() int64        { return fi.size }
+func (fi testFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi testFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi testFileInfo) IsDir() bool        { return fi.isDir }
+func (fi testFileInfo) Sys() interface{}   { return nil }
+
 func TestStaticDetectContentType(t *testing.T) {
 	testcases := []struct {
 		label    string
@@ -0,0 +1,320 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aahframe.work/aruntime"
+)
+
+// Worker states
+const (
+	WorkerStateRunning    = "running"
+	WorkerStateRestarting = "restarting"
+	WorkerStateFailed     = "failed"
+	WorkerStateStopped    = "stopped"
+)
+
+// errWorkerExited is recorded when worker func returns without an error
+// before the stop signal.
+var errWorkerExited = errors.New("aah: worker exited")
+
+type (
+	// WorkerFunc func type is used for supervised background worker. Worker
+	// should run until the stop channel is closed. Worker returns (with or
+	// without an error) or panics before that is considered as crashed.
+	WorkerFunc func(stop <-chan struct{}) error
+
+	// WorkerStatus struct holds the supervised worker status, it's exposed
+	// via health endpoint.
+	WorkerStatus struct {
+		Name      string    `json:"name"`
+		State     string    `json:"state"`
+		Restarts  int       `json:"restarts"`
+		LastError string    `json:"last_error,omitempty"`
+		StartedAt time.Time `json:"started_at"`
+	}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// Supervise method registers the background worker (for e.g.: jobs, broker
+// consumers, WebSocket hub) with aah supervisor. Workers are started along
+// with aah server and stopped on app shutdown. Crashed worker gets restarted
+// with exponential backoff.
+//
+// 	runtime {
+// 	  supervisor {
+// 	    backoff {
+// 	      # default value is "1s"
+// 	      min = "1s"
+//
+// 	      # default value is "1m"
+// 	      max = "1m"
+// 	    }
+//
+// 	    # Worker is marked as failed after max restarts, it is not restarted
+// 	    # anymore. Default value is 0, unlimited.
+// 	    max_restarts = 0
+// 	  }
+// 	}
+//
+// For e.g.:
+//
+// 	aah.App().Supervise("report-generator", func(stop <-chan struct{}) error {
+// 		ticker := time.NewTicker(5 * time.Minute)
+// 		defer ticker.Stop()
+// 		for {
+// 			select {
+// 			case <-stop:
+// 				return nil
+// 			case <-ticker.C:
+// 				if err := generateReports(); err != nil {
+// 					return err
+// 				}
+// 			}
+// 		}
+// 	})
+func (a *Application) Supervise(name string, fn WorkerFunc) error {
+	return a.supervisor.add(name, fn)
+}
+
+// WorkerStatus method returns the status of supervised workers, sorted by
+// worker name.
+func (a *Application) WorkerStatus() []WorkerStatus {
+	return a.supervisor.status()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func newSupervisor(a *Application) *supervisor {
+	return &supervisor{
+		a:          a,
+		workers:    make(map[string]*worker),
+		stop:       make(chan struct{}),
+		minBackoff: time.Second,
+		maxBackoff: time.Minute,
+	}
+}
+
+func (a *Application) initSupervisor() error {
+	cfg := a.Config()
+	keyPrefix := "runtime.supervisor"
+	s := a.supervisor
+
+	var err error
+	for key, d := range map[string]struct {
+		dst *time.Duration
+		def string
+	}{
+		"backoff.min": {&s.minBackoff, "1s"},
+		"backoff.max": {&s.maxBackoff, "1m"},
+	} {
+		if *d.dst, err = time.ParseDuration(cfg.StringDefault(keyPrefix+"."+key, d.def)); err != nil {
+			return fmt.Errorf("aah: '%s.%s' value is not a valid time unit", keyPrefix, key)
+		}
+	}
+	if s.minBackoff <= 0 || s.maxBackoff < s.minBackoff {
+		return fmt.Errorf("aah: '%s.backoff' min value must be greater than zero and less than or equal to max value", keyPrefix)
+	}
+	s.maxRestarts = cfg.IntDefault(keyPrefix+".max_restarts", 0)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Supervisor
+//______________________________________________________________________________
+
+type supervisor struct {
+	a           *Application
+	mu          sync.RWMutex
+	workers     map[string]*worker
+	started     bool
+	stopped     bool
+	stop        chan struct{}
+	wg          sync.WaitGroup
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxRestarts int
+}
+
+type worker struct {
+	mu     sync.RWMutex
+	fn     WorkerFunc
+	status WorkerStatus
+}
+
+func (s *supervisor) add(name string, fn WorkerFunc) error {
+	if len(name) == 0 || fn == nil {
+		return errors.New("aah: worker name or func is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.workers[name]; found {
+		return fmt.Errorf("aah: worker '%s' already exists", name)
+	}
+	if s.stopped {
+		return fmt.Errorf("aah: worker '%s' cannot be added, supervisor stopped", name)
+	}
+	w := &worker{fn: fn, status: WorkerStatus{Name: name, State: WorkerStateStopped}}
+	s.workers[name] = w
+	if s.started {
+		s.spawn(w)
+	}
+	return nil
+}
+
+// start method starts the registered workers, it's called on aah server start.
+func (s *supervisor) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	for _, w := range s.workers {
+		s.spawn(w)
+	}
+}
+
+// shutdown method signals the workers to stop and waits for them upto
+// given timeout.
+func (s *supervisor) shutdown(timeout time.Duration) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	close(s.stop)
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.a.Log().Warnf("Supervisor: workers did not stop within %s", timeout)
+	}
+}
+
+func (s *supervisor) status() []WorkerStatus {
+	s.mu.RLock()
+	result := make([]WorkerStatus, 0, len(s.workers))
+	for _, w := range s.workers {
+		w.mu.RLock()
+		result = append(result, w.status)
+		w.mu.RUnlock()
+	}
+	s.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func (s *supervisor) spawn(w *worker) {
+	s.wg.Add(1)
+	go s.run(w)
+}
+
+// run method runs the worker and restarts it with exponential backoff on
+// crash until the supervisor stops or max restarts is reached.
+func (s *supervisor) run(w *worker) {
+	defer s.wg.Done()
+	backoff := s.minBackoff
+	for {
+		w.update(func(st *WorkerStatus) {
+			st.State, st.StartedAt = WorkerStateRunning, time.Now()
+		})
+		s.a.Log().Debugf("Supervisor: worker '%s' started", w.status.Name)
+
+		started := time.Now()
+		err := s.invoke(w)
+		if s.isStopping() {
+			w.update(func(st *WorkerStatus) { st.State = WorkerStateStopped })
+			return
+		}
+		if err == nil {
+			err = errWorkerExited
+		}
+
+		// Worker ran long enough, crash is not in a loop
+		if time.Since(started) > s.maxBackoff {
+			backoff = s.minBackoff
+		}
+
+		var restarts int
+		w.update(func(st *WorkerStatus) {
+			st.LastError = err.Error()
+			restarts = st.Restarts
+		})
+		if s.maxRestarts > 0 && restarts >= s.maxRestarts {
+			w.update(func(st *WorkerStatus) { st.State = WorkerStateFailed })
+			s.a.Log().Errorf("Supervisor: worker '%s' crashed: %v, max restarts %d reached", w.status.Name, err, s.maxRestarts)
+			return
+		}
+
+		w.update(func(st *WorkerStatus) {
+			st.State = WorkerStateRestarting
+			st.Restarts++
+		})
+		s.a.Log().Warnf("Supervisor: worker '%s' crashed: %v, restarting in %s", w.status.Name, err, backoff)
+		select {
+		case <-s.stop:
+			w.update(func(st *WorkerStatus) { st.State = WorkerStateStopped })
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// invoke method calls the worker func with panic recovery.
+func (s *supervisor) invoke(w *worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			st := aruntime.NewStacktrace(r, s.a.Config())
+			buf := acquireBuilder()
+			defer releaseBuilder(buf)
+			st.Print(buf)
+
+			s.a.Log().Errorf("Supervisor: recovered from panic on worker '%s'", w.status.Name)
+			s.a.Log().Error(buf.String())
+			s.a.reportPanic(nil, r, st)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return w.fn(s.stop)
+}
+
+func (s *supervisor) isStopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *worker) update(fn func(st *WorkerStatus)) {
+	w.mu.Lock()
+	fn(&w.status)
+	w.mu.Unlock()
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervisorRestart(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`runtime {
+  supervisor {
+    backoff {
+      min = "1ms"
+      max = "4ms"
+    }
+    max_restarts = 3
+  }
+}`)
+	assert.Nil(t, a.initLog())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Nil(t, a.initSupervisor())
+	assert.Equal(t, time.Millisecond, a.supervisor.minBackoff)
+	assert.Equal(t, 3, a.supervisor.maxRestarts)
+
+	var crashes int32
+	assert.Nil(t, a.Supervise("crashing", func(stop <-chan struct{}) error {
+		if atomic.AddInt32(&crashes, 1)%2 == 0 {
+			panic("worker panic")
+		}
+		return errors.New("connection lost")
+	}))
+	assert.Nil(t, a.Supervise("ticker", func(stop <-chan struct{}) error {
+		<-stop
+		return nil
+	}))
+	assert.Equal(t, "aah: worker 'ticker' already exists", a.Supervise("ticker", func(stop <-chan struct{}) error { return nil }).Error())
+	assert.Equal(t, "aah: worker name or func is empty", a.Supervise("", nil).Error())
+
+	status := a.WorkerStatus()
+	assert.Equal(t, 2, len(status))
+	assert.Equal(t, "crashing", status[0].Name)
+	assert.Equal(t, WorkerStateStopped, status[0].State)
+	assert.Equal(t, HealthStatusUp, a.HealthStatus())
+
+	a.supervisor.start()
+	a.supervisor.start() // no-op
+	waitForWorker(t, a, "crashing", WorkerStateFailed)
+
+	status = a.WorkerStatus()
+	assert.Equal(t, 3, status[0].Restarts)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&crashes))
+	assert.Equal(t, "panic: worker panic", status[0].LastError)
+	assert.Equal(t, WorkerStateRunning, status[1].State)
+	assert.Equal(t, HealthStatusDown, a.HealthStatus())
+
+	// added after start
+	var exited int32
+	assert.Nil(t, a.Supervise("exiting", func(stop <-chan struct{}) error {
+		if atomic.AddInt32(&exited, 1) > 1 {
+			<-stop
+		}
+		return nil
+	}))
+	waitForWorker(t, a, "exiting", WorkerStateRunning)
+	for i := 0; i < 500 && atomic.LoadInt32(&exited) < 2; i++ {
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.Equal(t, errWorkerExited.Error(), a.WorkerStatus()[1].LastError)
+
+	a.supervisor.shutdown(time.Second)
+	a.supervisor.shutdown(time.Second) // no-op
+	status = a.WorkerStatus()
+	assert.Equal(t, WorkerStateFailed, status[0].State)
+	assert.Equal(t, WorkerStateStopped, status[1].State)
+	assert.Equal(t, WorkerStateStopped, status[2].State)
+	assert.Equal(t, "aah: worker 'late' cannot be added, supervisor stopped",
+		a.Supervise("late", func(stop <-chan struct{}) error { return nil }).Error())
+}
+
+func TestSupervisorConfigErrors(t *testing.T) {
+	for cfgStr, errStr := range map[string]string{
+		`min = "1x"`:                 "aah: 'runtime.supervisor.backoff.min' value is not a valid time unit",
+		`max = "1x"`:                 "aah: 'runtime.supervisor.backoff.max' value is not a valid time unit",
+		`min = "2m"`:                 "aah: 'runtime.supervisor.backoff' min value must be greater than zero and less than or equal to max value",
+		"min = \"0s\"\nmax = \"1s\"": "aah: 'runtime.supervisor.backoff' min value must be greater than zero and less than or equal to max value",
+	} {
+		a := newApp()
+		cfg, err := config.ParseString("runtime { supervisor { backoff {\n" + cfgStr + "\n} } }")
+		assert.Nil(t, err, cfgStr)
+		a.cfg = cfg
+		assert.Equal(t, errStr, a.initSupervisor().Error())
+	}
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	ts.app.Config().SetBool("runtime.health.enable", true)
+	assert.Nil(t, ts.app.addHealthRoutes(ts.app.Router()))
+	assert.NotNil(t, ts.app.Router().Lookup("localhost:8080").LookupByName(healthRouteName))
+
+	check := func() (int, map[string]interface{}) {
+		resp, err := http.Get(ts.URL + healthDefaultPath)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		result := make(map[string]interface{})
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp.StatusCode, result
+	}
+
+	code, result := check()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusUp, result["status"])
+
+	ts.app.supervisor.workers["consumer"] = &worker{status: WorkerStatus{Name: "consumer", State: WorkerStateRestarting}}
+	code, result = check()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusDegraded, result["status"])
+
+	ts.app.supervisor.workers["hub"] = &worker{status: WorkerStatus{Name: "hub", State: WorkerStateFailed, LastError: "closed"}}
+	code, result = check()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusDown, result["status"])
+	workers := result["workers"].([]interface{})
+	assert.Equal(t, 2, len(workers))
+	assert.Equal(t, "closed", workers[1].(map[string]interface{})["last_error"])
+}
+
+func waitForWorker(t *testing.T, a *Application, name, state string) {
+	for i := 0; i < 1000; i++ {
+		for _, ws := range a.WorkerStatus() {
+			if ws.Name == name && ws.State == state {
+				return
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("worker '%s' is not in state '%s'", name, state)
+}
@@ -0,0 +1,385 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Tabular data types
+//______________________________________________________________________________
+
+// Column type is used to map the row field into tabular data column. `Key` is
+// the struct field name or map key and `Header` is the column header value.
+// If `Header` is empty then `Key` is used as header value.
+type Column struct {
+	Key    string
+	Header string
+}
+
+// Sheet type holds the rows and its column mapping for tabular renderers,
+// used by `Reply().Xlsx`.
+//
+// Rows value could be one of the following:
+// 	1) Slice or array of rows
+// 	2) Channel of rows, rows are rendered till channel gets closed
+//
+// Row value could be `[]string`, `[]interface{}`, struct, pointer to struct or
+// map with string key. For struct and map rows, column mapping is used to pick
+// and order the values. In the absence of column mapping, struct exported
+// fields in its order and map keys in sorted order are used.
+type Sheet struct {
+	Name    string
+	Rows    interface{}
+	Columns []Column
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// CSV Render
+//______________________________________________________________________________
+
+// csvRender streams the rows as CSV into response.
+type csvRender struct {
+	Rows    interface{}
+	Columns []Column
+}
+
+// Render method writes CSV into HTTP response.
+func (c *csvRender) Render(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := eachRow(c.Rows, c.Columns, func(values []interface{}) error {
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = formatCellValue(v)
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// RenderTo method streams CSV directly into HTTP response.
+func (c *csvRender) RenderTo(w io.Writer) error {
+	return c.Render(w)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Xlsx Render
+//______________________________________________________________________________
+
+// xlsxRender streams the sheets as Office Open XML spreadsheet into response.
+// Each sheet rows are written into zip entry as it's read, so the whole
+// file is not built in the memory.
+type xlsxRender struct {
+	Sheets []*Sheet
+}
+
+// Render method writes Xlsx into HTTP response.
+func (x *xlsxRender) Render(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	names := make([]string, len(x.Sheets))
+	for i, s := range x.Sheets {
+		names[i] = s.Name
+		if len(names[i]) == 0 {
+			names[i] = "Sheet" + strconv.Itoa(i+1)
+		}
+	}
+
+	if err := writeZipEntry(zw, "[Content_Types].xml", xlsxContentTypes(len(names))); err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, "_rels/.rels", xlsxRootRels); err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, "xl/workbook.xml", xlsxWorkbook(names)); err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, "xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(names))); err != nil {
+		return err
+	}
+
+	for i, s := range x.Sheets {
+		fw, err := zw.Create("xl/worksheets/sheet" + strconv.Itoa(i+1) + ".xml")
+		if err != nil {
+			return err
+		}
+		if err = writeXlsxSheet(fw, s); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+const (
+	xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxSheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd   = `</sheetData></worksheet>`
+)
+
+func xlsxContentTypes(count int) string {
+	s := xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`
+	for i := 1; i <= count; i++ {
+		s += `<Override PartName="/xl/worksheets/sheet` + strconv.Itoa(i) +
+			`.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+	}
+	return s + `</Types>`
+}
+
+func xlsxWorkbook(names []string) string {
+	s := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	for i, name := range names {
+		id := strconv.Itoa(i + 1)
+		s += `<sheet name="` + xmlEscape(name) + `" sheetId="` + id + `" r:id="rId` + id + `"/>`
+	}
+	return s + `</sheets></workbook>`
+}
+
+func xlsxWorkbookRels(count int) string {
+	s := xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	for i := 1; i <= count; i++ {
+		id := strconv.Itoa(i)
+		s += `<Relationship Id="rId` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet` + id + `.xml"/>`
+	}
+	return s + `</Relationships>`
+}
+
+func writeXlsxSheet(w io.Writer, s *Sheet) error {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(xlsxSheetStart)
+	rowNum := 0
+	err := eachRow(s.Rows, s.Columns, func(values []interface{}) error {
+		rowNum++
+		rn := strconv.Itoa(rowNum)
+		_, _ = bw.WriteString(`<row r="` + rn + `">`)
+		for i, v := range values {
+			ref := xlsxColumnName(i) + rn
+			switch n := v.(type) {
+			case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+				_, _ = bw.WriteString(`<c r="` + ref + `"><v>` + fmt.Sprint(n) + `</v></c>`)
+			case bool:
+				b := "0"
+				if n {
+					b = "1"
+				}
+				_, _ = bw.WriteString(`<c r="` + ref + `" t="b"><v>` + b + `</v></c>`)
+			default:
+				_, _ = bw.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t>` +
+					xmlEscape(formatCellValue(v)) + `</t></is></c>`)
+			}
+		}
+		_, err := bw.WriteString(`</row>`)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = bw.WriteString(xlsxSheetEnd)
+	return bw.Flush()
+}
+
+// xlsxColumnName method returns the spreadsheet column name for given zero
+// based index. For e.g.: 0 => A, 25 => Z, 26 => AA.
+func xlsxColumnName(idx int) string {
+	name := ""
+	for idx >= 0 {
+		name = string(rune('A'+idx%26)) + name
+		idx = idx/26 - 1
+	}
+	return name
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// eachRow method iterates the given rows and calls the func with row values.
+// Header row is composed from column mapping for struct and map rows.
+func eachRow(rows interface{}, columns []Column, fn func(values []interface{}) error) error {
+	rv := reflect.ValueOf(rows)
+	var next func() (reflect.Value, bool)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		i := 0
+		next = func() (reflect.Value, bool) {
+			if i >= rv.Len() {
+				return reflect.Value{}, false
+			}
+			i++
+			return rv.Index(i - 1), true
+		}
+	case reflect.Chan:
+		if rv.Type().ChanDir()&reflect.RecvDir == 0 {
+			return fmt.Errorf("aah: rows channel '%T' is send-only", rows)
+		}
+		next = rv.Recv
+	case reflect.Invalid:
+		return nil
+	default:
+		return fmt.Errorf("aah: unsupported rows type '%T'", rows)
+	}
+
+	headerDone := false
+	for {
+		row, ok := next()
+		if !ok {
+			return nil
+		}
+		row = reflect.Indirect(row)
+		if row.Kind() == reflect.Interface {
+			row = reflect.Indirect(row.Elem())
+		}
+
+		switch row.Kind() {
+		case reflect.Struct, reflect.Map:
+			if !headerDone {
+				if len(columns) == 0 {
+					columns = inferColumns(row)
+				}
+				header := make([]interface{}, len(columns))
+				for i, c := range columns {
+					header[i] = c.Header
+					if len(c.Header) == 0 {
+						header[i] = c.Key
+					}
+				}
+				if err := fn(header); err != nil {
+					return err
+				}
+				headerDone = true
+			}
+			if err := fn(columnValues(row, columns)); err != nil {
+				return err
+			}
+		case reflect.Slice, reflect.Array:
+			if !headerDone && len(columns) > 0 {
+				header := make([]interface{}, len(columns))
+				for i, c := range columns {
+					header[i] = c.Header
+				}
+				if err := fn(header); err != nil {
+					return err
+				}
+			}
+			headerDone = true
+			values := make([]interface{}, row.Len())
+			for i := 0; i < row.Len(); i++ {
+				values[i] = row.Index(i).Interface()
+			}
+			if err := fn(values); err != nil {
+				return err
+			}
+		case reflect.Invalid:
+			continue
+		default:
+			return fmt.Errorf("aah: unsupported row type '%s'", row.Type())
+		}
+	}
+}
+
+func inferColumns(row reflect.Value) []Column {
+	var columns []Column
+	if row.Kind() == reflect.Map {
+		keys := make([]string, 0, row.Len())
+		for _, k := range row.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			columns = append(columns, Column{Key: k})
+		}
+		return columns
+	}
+
+	rt := row.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		if f := rt.Field(i); len(f.PkgPath) == 0 {
+			columns = append(columns, Column{Key: f.Name})
+		}
+	}
+	return columns
+}
+
+func columnValues(row reflect.Value, columns []Column) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		var v reflect.Value
+		if row.Kind() == reflect.Map {
+			if kv := reflect.ValueOf(c.Key); kv.Type().ConvertibleTo(row.Type().Key()) {
+				v = row.MapIndex(kv.Convert(row.Type().Key()))
+			}
+		} else {
+			v = row.FieldByName(c.Key)
+		}
+		if v.IsValid() && v.CanInterface() {
+			values[i] = v.Interface()
+		}
+	}
+	return values
+}
+
+func formatCellValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+		return formatCellValue(*t)
+	case fmt.Stringer:
+		return t.String()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		return formatCellValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v)
+}
+
+func writeZipEntry(zw *zip.Writer, name, content string) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(fw, content)
+	return err
+}
+
+func xmlEscape(s string) string {
+	b := acquireBuilder()
+	defer releaseBuilder(b)
+	_ = xml.EscapeText(b, []byte(s))
+	return b.String()
+}
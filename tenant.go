@@ -0,0 +1,203 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/cache"
+	"aahframe.work/config"
+)
+
+// KeyViewArgTenant key name is used to store resolved tenant instance into
+// `ViewArgs`.
+const KeyViewArgTenant = "_aahTenant"
+
+// TenantResolver func type is used to resolve the tenant of incoming request,
+// for e.g.: by subdomain, header, path, etc. Return `nil` tenant if request
+// does not belong to any tenant. Returned error is replied with `404 Not Found`.
+type TenantResolver func(ctx *Context) (*Tenant, error)
+
+// Tenant struct holds the resolved tenant info of the request.
+//
+// Tenant config overrides are configured at `tenants.<id> { ... }`, supported
+// framework config values are:
+//
+//  - `security.session.domain` session cookie domain of the tenant
+//  - `i18n.default` default locale of the tenant
+//
+// Tenant specific i18n message keys are prefixed with `tenants.<id>.` and
+// tenant view overrides are placed under `views/pages/tenants/<id>/...`,
+// these are looked up first then falls back to application ones.
+type Tenant struct {
+	ID     string
+	Config *config.Config
+
+	a *Application
+}
+
+// Cache method returns the tenant scoped cache for the given cache name,
+// cache keys are namespaced with tenant ID. It returns nil if cache not exists.
+func (t *Tenant) Cache(name string) cache.Cache {
+	c := t.a.CacheManager().Cache(name)
+	if c == nil {
+		return nil
+	}
+	return &tenantCache{Cache: c, prefix: "tenant_" + t.ID + "_"}
+}
+
+// NewTenant method creates the tenant instance for the given tenant ID with
+// config overrides from `tenants.<id> { ... }` if exists otherwise empty config.
+func (a *Application) NewTenant(id string) *Tenant {
+	cfg, found := a.Config().GetSubConfig("tenants." + id)
+	if !found {
+		cfg = config.NewEmpty()
+	}
+	return &Tenant{ID: id, Config: cfg, a: a}
+}
+
+// SetTenantResolver method sets the tenant resolver for the application,
+// resolved tenant is accessible via `ctx.Tenant()`.
+//
+// For e.g.:
+// 	aah.App().SetTenantResolver(aah.SubdomainTenantResolver())
+func (a *Application) SetTenantResolver(resolver TenantResolver) {
+	a.tenantResolver = resolver
+}
+
+// SubdomainTenantResolver method returns the tenant resolver which resolves
+// the tenant ID from request host subdomain, for e.g.: `acme.example.com`
+// resolves to tenant `acme`. Tenant must be configured at `tenants.<id>`.
+func SubdomainTenantResolver() TenantResolver {
+	return func(ctx *Context) (*Tenant, error) {
+		host := ctx.Req.Host
+		if idx := strings.LastIndexByte(host, ':'); idx > 0 {
+			host = host[:idx]
+		}
+		if strings.Count(host, ".") < 2 {
+			return nil, nil
+		}
+		return configuredTenant(ctx, host[:strings.IndexByte(host, '.')])
+	}
+}
+
+// HeaderTenantResolver method returns the tenant resolver which resolves
+// the tenant ID from given request header, for e.g.: `X-Tenant-ID`.
+// Tenant must be configured at `tenants.<id>`.
+func HeaderTenantResolver(hdr string) TenantResolver {
+	return func(ctx *Context) (*Tenant, error) {
+		id := ctx.Req.Header.Get(hdr)
+		if len(id) == 0 {
+			return nil, nil
+		}
+		return configuredTenant(ctx, id)
+	}
+}
+
+// Tenant method returns the resolved tenant of current request otherwise nil.
+func (ctx *Context) Tenant() *Tenant {
+	return ctx.tenant
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func configuredTenant(ctx *Context, id string) (*Tenant, error) {
+	if !ctx.a.Config().IsExists("tenants." + id) {
+		return nil, ErrTenantNotFound
+	}
+	return ctx.a.NewTenant(id), nil
+}
+
+// resolveTenant method resolves the tenant of the request, returns false if
+// tenant resolver failed and reply is prepared.
+func (e *HTTPEngine) resolveTenant(ctx *Context) bool {
+	if e.a.tenantResolver == nil {
+		return true
+	}
+
+	tenant, err := e.a.tenantResolver(ctx)
+	if err != nil {
+		ctx.Log().Warnf("Tenant resolve failed for host '%s': %v", ctx.Req.Host, err)
+		ctx.Reply().NotFound().Error(newError(ErrTenantNotFound, http.StatusNotFound))
+		return false
+	}
+	if tenant != nil {
+		if tenant.a == nil {
+			tenant.a = e.a
+		}
+		if tenant.Config == nil {
+			tenant.Config = config.NewEmpty()
+		}
+		ctx.tenant = tenant
+		ctx.logger = ctx.Log().WithField("tenant", tenant.ID)
+	}
+	return true
+}
+
+// locale method returns the tenant default locale if configured
+// otherwise given locale.
+func (t *Tenant) locale(locale *ahttp.Locale) *ahttp.Locale {
+	if locale == nil {
+		if l := t.Config.StringDefault("i18n.default", ""); len(l) > 0 {
+			return ahttp.NewLocale(l)
+		}
+	}
+	return locale
+}
+
+// i18nLookup method looks up the tenant specific i18n message key
+// `tenants.<id>.<key>` then falls back to given key.
+func (a *Application) i18nLookup(t *Tenant, locale *ahttp.Locale, key string, args ...interface{}) string {
+	if a.I18n() == nil {
+		return ""
+	}
+	if t != nil {
+		locale = t.locale(locale)
+		tkey := "tenants." + t.ID + "." + key
+		if msg := a.I18n().Lookup(locale, tkey, args...); msg != tkey {
+			return msg
+		}
+	}
+	return a.I18n().Lookup(locale, key, args...)
+}
+
+// tmplPath method returns the tenant view override path for given
+// template path.
+func (t *Tenant) tmplPath(tmplPath string) string {
+	return filepath.Join("pages", "tenants", t.ID, strings.TrimPrefix(tmplPath, "pages"))
+}
+
+// tenantCache struct namespaces the cache keys with tenant ID. Note: `Flush`
+// method flushes the underlying cache entries of all the tenants.
+type tenantCache struct {
+	cache.Cache
+	prefix string
+}
+
+func (c *tenantCache) Get(k string) interface{} {
+	return c.Cache.Get(c.prefix + k)
+}
+
+func (c *tenantCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	return c.Cache.GetOrPut(c.prefix+k, v, d)
+}
+
+func (c *tenantCache) Put(k string, v interface{}, d time.Duration) error {
+	return c.Cache.Put(c.prefix+k, v, d)
+}
+
+func (c *tenantCache) Delete(k string) error {
+	return c.Cache.Delete(c.prefix + k)
+}
+
+func (c *tenantCache) Exists(k string) bool {
+	return c.Cache.Exists(c.prefix + k)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantResolveAndOverrides(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	a := ts.app
+	a.Config().SetString("tenants.acme.security.session.domain", "acme.localhost")
+	a.SetTenantResolver(HeaderTenantResolver("X-Tenant-ID"))
+	defer a.SetTenantResolver(nil)
+
+	httpClient := new(http.Client)
+	request := func(urlPath, tenant string) *testResult {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+urlPath, nil)
+		assert.Nil(t, err)
+		if len(tenant) > 0 {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		resp, err := httpClient.Do(req)
+		assert.Nil(t, err)
+		return &testResult{StatusCode: resp.StatusCode, Header: resp.Header, Body: responseBody(resp), Raw: resp}
+	}
+
+	// tenant view and i18n override
+	result := request("/", "acme")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.True(t, strings.Contains(result.Body, "ACME tenant home"))
+	assert.True(t, strings.Contains(result.Body, "This is ACME tenant text"))
+	assert.True(t, strings.Contains(result.Body, "aah framework application - Home"))
+
+	result = request("/get-text.html", "acme")
+	assert.Equal(t, "This is ACME tenant text", result.Body)
+
+	// non-tenant request
+	result = request("/", "")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.False(t, strings.Contains(result.Body, "ACME tenant home"))
+
+	result = request("/get-text.html", "")
+	assert.Equal(t, "This is text render response", result.Body)
+
+	// tenant not configured
+	result = request("/", "unknown")
+	assert.Equal(t, http.StatusNotFound, result.StatusCode)
+}
+
+func TestTenantSubdomainResolver(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Config().SetString("tenants.acme.i18n.default", "en-US")
+
+	resolver := SubdomainTenantResolver()
+	newCtx := func(host string) *Context {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+		ctx := newContext(nil, req)
+		ctx.a = a
+		return ctx
+	}
+
+	tenant, err := resolver(newCtx("acme.example.com:8080"))
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", tenant.ID)
+	assert.Equal(t, "en-US", tenant.Config.StringDefault("i18n.default", ""))
+	assert.Equal(t, "en-US", tenant.locale(nil).String())
+	assert.Equal(t, filepath.Join("pages", "tenants", "acme", "app"), tenant.tmplPath("pages/app"))
+
+	tenant, err = resolver(newCtx("example.com"))
+	assert.Nil(t, err)
+	assert.Nil(t, tenant)
+
+	_, err = resolver(newCtx("other.example.com"))
+	assert.Equal(t, ErrTenantNotFound, err)
+
+	// tenant scoped cache
+	tc := &testThrottleCache{entries: make(map[string]interface{})}
+	a.cacheMgr = cache.NewManager()
+	_ = a.CacheManager().AddProvider("test", &testThrottleCacheProvider{c: tc})
+	assert.Nil(t, a.CacheManager().CreateCache(&cache.Config{Name: "tenant_data", ProviderName: "test"}))
+
+	acme, other := a.NewTenant("acme"), a.NewTenant("other")
+	assert.Nil(t, a.NewTenant("acme").Cache("not_exists"))
+	assert.Nil(t, acme.Cache("tenant_data").Put("plan", "gold", time.Minute))
+	assert.Equal(t, "gold", acme.Cache("tenant_data").Get("plan"))
+	assert.True(t, acme.Cache("tenant_data").Exists("plan"))
+	assert.Nil(t, other.Cache("tenant_data").Get("plan"))
+	v, err := other.Cache("tenant_data").GetOrPut("plan", "silver", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, "silver", v)
+	assert.Equal(t, "gold", tc.entries["tenant_acme_plan"])
+	assert.Nil(t, acme.Cache("tenant_data").Delete("plan"))
+	assert.False(t, acme.Cache("tenant_data").Exists("plan"))
+}
@@ -0,0 +1,170 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/security/scheme"
+)
+
+func init() {
+	gob.Register(&LoginAttempt{})
+}
+
+// LoginAttempt holds the failed login attempts details of subject identity
+// and client IP, stored in the cache configured at `security.throttle.cache`.
+type LoginAttempt struct {
+	Failures     int
+	LastFailedAt time.Time
+	Until        time.Time
+}
+
+type loginThrottle struct {
+	a                 *Application
+	cacheName         string
+	baseDelay         time.Duration
+	maxDelay          time.Duration
+	lockoutThreshold  int
+	lockoutDuration   time.Duration
+	attemptsRetention time.Duration
+}
+
+func (a *Application) initThrottle() error {
+	cfg := a.Config()
+	keyPrefix := "security.throttle"
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		return nil
+	}
+
+	t := &loginThrottle{
+		a:                a,
+		cacheName:        cfg.StringDefault(keyPrefix+".cache", "login_throttle"),
+		lockoutThreshold: cfg.IntDefault(keyPrefix+".lockout_threshold", 5),
+	}
+	var err error
+	for key, d := range map[string]struct {
+		dst *time.Duration
+		def string
+	}{
+		"base_delay":       {&t.baseDelay, "1s"},
+		"max_delay":        {&t.maxDelay, "1m"},
+		"lockout_duration": {&t.lockoutDuration, "15m"},
+		"retention":        {&t.attemptsRetention, "1h"},
+	} {
+		if *d.dst, err = time.ParseDuration(cfg.StringDefault(keyPrefix+"."+key, d.def)); err != nil {
+			return fmt.Errorf("aah: '%s.%s' value is not a valid time unit", keyPrefix, key)
+		}
+	}
+
+	a.throttle = t
+	return nil
+}
+
+// blocked method returns the remaining wait duration if the identity and client
+// IP is delayed or locked out otherwise zero.
+func (t *loginThrottle) blocked(ctx *Context, identity string) time.Duration {
+	la := t.attempt(ctx, identity)
+	if la == nil {
+		return 0
+	}
+	if d := time.Until(la.Until); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// failed method increments the failed attempts, applies the exponential
+// delay and locks out upon reaching the threshold.
+func (t *loginThrottle) failed(ctx *Context, identity string) {
+	c := t.cache(ctx)
+	if c == nil {
+		return
+	}
+
+	la := t.attempt(ctx, identity)
+	if la == nil {
+		la = &LoginAttempt{}
+	}
+	la.Failures++
+	now := time.Now()
+	la.LastFailedAt = now
+	ctx.a.eventStore.PublishSync(&Event{Name: EventOnAuthcFailed, Data: ctx})
+
+	if la.Failures >= t.lockoutThreshold {
+		la.Until = now.Add(t.lockoutDuration)
+		ctx.Log().Warnf("Login throttle: '%s' from '%s' is locked out after %d failed attempts",
+			identity, ctx.Req.ClientIP(), la.Failures)
+		if h := ctx.a.SecurityManager().LockoutHandler; h != nil {
+			h.Lockout(identity, ctx.Req.ClientIP(), la.Failures)
+		}
+		ctx.a.eventStore.PublishSync(&Event{Name: EventOnAuthcLockout, Data: ctx})
+	} else {
+		la.Until = now.Add(t.delay(la.Failures))
+	}
+
+	key := t.key(ctx, identity)
+	_ = c.Delete(key)
+	if err := c.Put(key, la, t.attemptsRetention); err != nil {
+		ctx.Log().Error(err)
+	}
+}
+
+// succeeded method clears the failed attempts of identity and client IP.
+func (t *loginThrottle) succeeded(ctx *Context, identity string) {
+	if c := t.cache(ctx); c != nil {
+		_ = c.Delete(t.key(ctx, identity))
+	}
+}
+
+// delay method returns the exponential delay for given failures count,
+// limited to `security.throttle.max_delay`.
+func (t *loginThrottle) delay(failures int) time.Duration {
+	d := t.baseDelay
+	for i := 1; i < failures && d < t.maxDelay; i++ {
+		d *= 2
+	}
+	if d > t.maxDelay {
+		d = t.maxDelay
+	}
+	return d
+}
+
+func (t *loginThrottle) attempt(ctx *Context, identity string) *LoginAttempt {
+	if c := t.cache(ctx); c != nil {
+		la, _ := c.Get(t.key(ctx, identity)).(*LoginAttempt)
+		return la
+	}
+	return nil
+}
+
+func (t *loginThrottle) cache(ctx *Context) cache.Cache {
+	c := t.a.CacheManager().Cache(t.cacheName)
+	if c == nil {
+		ctx.Log().Errorf("Login throttle: cache '%s' not exists", t.cacheName)
+	}
+	return c
+}
+
+func (t *loginThrottle) key(ctx *Context, identity string) string {
+	return "aah_throttle_" + strconv.Quote(identity) + "_" + ctx.Req.ClientIP()
+}
+
+// isApplicable method returns true if login throttle is enabled and
+// given auth scheme is `form` or `basic`.
+func (t *loginThrottle) isApplicable(authScheme scheme.Schemer) bool {
+	if t == nil {
+		return false
+	}
+	switch authScheme.(type) {
+	case *scheme.FormAuth, *scheme.BasicAuth:
+		return true
+	}
+	return false
+}
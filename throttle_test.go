@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/router"
+	"aahframe.work/security/scheme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	assert.Nil(t, a.throttle)
+	assert.False(t, a.throttle.isApplicable(&scheme.FormAuth{}))
+
+	cfg := a.Config()
+	cfg.SetBool("security.throttle.enable", true)
+	cfg.SetString("security.throttle.base_delay", "1s")
+	cfg.SetString("security.throttle.max_delay", "4s")
+	cfg.SetInt("security.throttle.lockout_threshold", 4)
+	assert.Nil(t, a.initThrottle())
+	assert.True(t, a.throttle.isApplicable(&scheme.FormAuth{}))
+	assert.True(t, a.throttle.isApplicable(&scheme.BasicAuth{}))
+	assert.False(t, a.throttle.isApplicable(&scheme.GenericAuth{}))
+
+	// exponential delay
+	assert.Equal(t, time.Second, a.throttle.delay(1))
+	assert.Equal(t, 2*time.Second, a.throttle.delay(2))
+	assert.Equal(t, 4*time.Second, a.throttle.delay(3))
+	assert.Equal(t, 4*time.Second, a.throttle.delay(10))
+
+	newCtx := func() *Context {
+		req := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/login", nil)
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		return ctx
+	}
+
+	// cache not exists
+	ctx := newCtx()
+	a.throttle.failed(ctx, "jeeva")
+	assert.Equal(t, time.Duration(0), a.throttle.blocked(ctx, "jeeva"))
+
+	tc := &testThrottleCache{entries: make(map[string]interface{})}
+	a.cacheMgr = cache.NewManager()
+	_ = a.CacheManager().AddProvider("test", &testThrottleCacheProvider{c: tc})
+	assert.Nil(t, a.CacheManager().CreateCache(&cache.Config{Name: "login_throttle", ProviderName: "test"}))
+
+	var events []string
+	for _, name := range []string{EventOnAuthcFailed, EventOnAuthcLockout} {
+		a.SubscribeEvent(name, EventCallback{Callback: func(e *Event) {
+			events = append(events, e.Name)
+		}})
+	}
+	lh := &testLockoutHandler{}
+	a.SecurityManager().LockoutHandler = lh
+
+	for i := 1; i <= 3; i++ {
+		a.throttle.failed(ctx, "jeeva")
+		d := a.throttle.blocked(ctx, "jeeva")
+		assert.True(t, d > 0 && d <= a.throttle.delay(i))
+	}
+	assert.Equal(t, 0, lh.failures)
+	assert.Equal(t, time.Duration(0), a.throttle.blocked(ctx, "user2"))
+
+	a.throttle.failed(ctx, "jeeva")
+	assert.True(t, a.throttle.blocked(ctx, "jeeva") > 10*time.Minute)
+	assert.Equal(t, "jeeva", lh.identity)
+	assert.Equal(t, 4, lh.failures)
+	assert.Equal(t, []string{"OnAuthcFailed", "OnAuthcFailed", "OnAuthcFailed",
+		"OnAuthcFailed", "OnAuthcLockout"}, events)
+
+	// blocked reply
+	ctx = newCtx()
+	assert.Equal(t, flowAbort, throttleBlocked(&scheme.BasicAuth{}, ctx, 1500*time.Millisecond))
+	assert.Equal(t, http.StatusTooManyRequests, ctx.Reply().Code)
+	assert.Equal(t, "2", ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+	assert.Equal(t, ErrAuthenticationThrottled, ctx.Reply().err.Reason)
+
+	a.throttle.succeeded(ctx, "jeeva")
+	assert.Equal(t, time.Duration(0), a.throttle.blocked(ctx, "jeeva"))
+
+	cfg.SetString("security.throttle.max_delay", "1 minute")
+	assert.Equal(t, "aah: 'security.throttle.max_delay' value is not a valid time unit", a.initThrottle().Error())
+}
+
+func TestLoginThrottleBasicAuthChallenge(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	a := ts.app
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    basic_auth {
+		      scheme = "basic"
+		      realm_name = "webapp1"
+		    }
+		  }
+		  throttle {
+		    enable = true
+		    lockout_threshold = 2
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initSecurity())
+
+	tc := &testThrottleCache{entries: make(map[string]interface{})}
+	_ = a.CacheManager().AddProvider("throttle_test", &testThrottleCacheProvider{c: tc})
+	assert.Nil(t, a.CacheManager().CreateCache(&cache.Config{Name: "login_throttle", ProviderName: "throttle_test"}))
+
+	// request without credentials always gets the challenge
+	for i := 0; i < 5; i++ {
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/doc/v0.3/mydoc.html", nil)
+		ctx := a.he.newContext()
+		ctx.Req = ahttp.AcquireRequest(r)
+		ctx.Res = ahttp.AcquireResponseWriter(httptest.NewRecorder())
+		ctx.route = &router.Route{Auth: "basic_auth"}
+		AuthcAuthzMiddleware(ctx, &Middleware{})
+		assert.Equal(t, http.StatusUnauthorized, ctx.Reply().Code)
+		assert.Equal(t, `Basic realm="webapp1"`, ctx.Res.Header().Get(ahttp.HeaderWWWAuthenticate))
+	}
+	assert.Equal(t, 0, len(tc.entries))
+}
+
+type testLockoutHandler struct {
+	identity string
+	failures int
+}
+
+func (h *testLockoutHandler) Lockout(identity, clientIP string, failures int) {
+	h.identity, h.failures = identity, failures
+}
+
+type testThrottleCacheProvider struct {
+	c *testThrottleCache
+}
+
+func (p *testThrottleCacheProvider) Init(name string, appCfg *config.Config, logger log.Loggerer) error {
+	return nil
+}
+
+func (p *testThrottleCacheProvider) Create(cfg *cache.Config) (cache.Cache, error) {
+	return p.c, nil
+}
+
+type testThrottleCache struct {
+	mu      sync.Mutex
+	entries map[string]interface{}
+}
+
+func (c *testThrottleCache) Name() string { return "login_throttle" }
+
+func (c *testThrottleCache) Get(k string) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[k]
+}
+
+func (c *testThrottleCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	if e := c.Get(k); e != nil {
+		return e, nil
+	}
+	return v, c.Put(k, v, d)
+}
+
+func (c *testThrottleCache) Put(k string, v interface{}, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.entries[k]; found {
+		return cache.ErrEntryExists
+	}
+	c.entries[k] = v
+	return nil
+}
+
+func (c *testThrottleCache) Delete(k string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, k)
+	return nil
+}
+
+func (c *testThrottleCache) Exists(k string) bool { return c.Get(k) != nil }
+
+func (c *testThrottleCache) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]interface{})
+	return nil
+}
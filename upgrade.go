@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Binary upgrade hands over the server listeners to new process via file
+// descriptor inheritance, listeners are passed as extra files from fd 3
+// onwards in the order of `AAH_UPGRADE_FDS` value.
+const (
+	envUpgradeFds    = "AAH_UPGRADE_FDS"
+	envUpgradeParent = "AAH_UPGRADE_PARENT"
+	upgradeFdStart   = 3
+)
+
+// upgrader keeps track of server listeners for binary upgrade (socket
+// handoff), refer to config `server.upgrade.*`.
+type upgrader struct {
+	sync.Mutex
+	once      sync.Once
+	upgrading bool
+	inherited map[string]net.Listener
+	listeners []*upgradeListener
+}
+
+type upgradeListener struct {
+	key string
+	net.Listener
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// inheritOrListen method returns the listener inherited from the previous
+// process on binary upgrade otherwise creates the new listener.
+func (a *Application) inheritOrListen(network, address string) (net.Listener, error) {
+	a.loadInheritedListeners()
+	u := &a.upgrader
+	key := upgradeKey(network, address)
+	u.Lock()
+	defer u.Unlock()
+	l, found := u.inherited[key]
+	if found {
+		delete(u.inherited, key)
+		a.Log().Infof("Binary upgrade: inherited listener %s", key)
+	} else {
+		var err error
+		if l, err = net.Listen(network, address); err != nil {
+			return nil, err
+		}
+	}
+	u.listeners = append(u.listeners, &upgradeListener{key: key, Listener: l})
+	return l, nil
+}
+
+// isInherited method returns true if the listener for given network and
+// address is available from the previous process.
+func (a *Application) isInherited(network, address string) bool {
+	a.loadInheritedListeners()
+	a.upgrader.Lock()
+	defer a.upgrader.Unlock()
+	_, found := a.upgrader.inherited[upgradeKey(network, address)]
+	return found
+}
+
+// loadInheritedListeners method loads the listeners passed by the old
+// process, environment variable `AAH_UPGRADE_FDS`.
+func (a *Application) loadInheritedListeners() {
+	a.upgrader.once.Do(func() {
+		spec := os.Getenv(envUpgradeFds)
+		if len(spec) == 0 {
+			return
+		}
+		_ = os.Unsetenv(envUpgradeFds)
+		keys := strings.Split(spec, ",")
+		files := make([]*os.File, len(keys))
+		for i, key := range keys {
+			files[i] = os.NewFile(uintptr(upgradeFdStart+i), key)
+		}
+		if err := a.upgrader.inherit(keys, files); err != nil {
+			a.Log().Errorf("Binary upgrade: %v", err)
+		}
+	})
+}
+
+// inherit method creates the listeners from given files, files are closed
+// after use.
+func (u *upgrader) inherit(keys []string, files []*os.File) error {
+	defer closeFiles(files)
+	u.Lock()
+	defer u.Unlock()
+	u.inherited = make(map[string]net.Listener)
+	for i, key := range keys {
+		l, err := net.FileListener(files[i])
+		if err != nil {
+			return fmt.Errorf("unable to inherit listener %s: %v", key, err)
+		}
+		u.inherited[key] = l
+	}
+	return nil
+}
+
+// files method returns the listener file descriptors and its keys for
+// handoff to new process.
+func (u *upgrader) files() ([]*os.File, []string, error) {
+	u.Lock()
+	defer u.Unlock()
+	var files []*os.File
+	var keys []string
+	for _, l := range u.listeners {
+		if ul, ok := l.Listener.(*net.UnixListener); ok {
+			// socket file is owned by new process from now on
+			ul.SetUnlinkOnClose(false)
+		}
+		fl, ok := l.Listener.(interface {
+			File() (*os.File, error)
+		})
+		if !ok {
+			closeFiles(files)
+			return nil, nil, fmt.Errorf("listener %s does not support file descriptor", l.key)
+		}
+		f, err := fl.File()
+		if err != nil {
+			closeFiles(files)
+			return nil, nil, err
+		}
+		files = append(files, f)
+		keys = append(keys, l.key)
+	}
+	return files, keys, nil
+}
+
+func upgradeKey(network, address string) string {
+	return network + "|" + address
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
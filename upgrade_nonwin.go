@@ -0,0 +1,135 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package aah
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenForUpgrade method listens for binary upgrade signal `server.upgrade.signal`
+// (default `SIGUSR2`). On signal new binary is started with inherited
+// listeners, once new process is ready to serve it sends `SIGTERM` to
+// the old process, then old process drains in-flight requests and exits.
+func (a *Application) listenForUpgrade() {
+	cfg := a.Config()
+	if !cfg.BoolDefault("server.upgrade.enable", false) {
+		return
+	}
+	sigStr := strings.ToUpper(cfg.StringDefault("server.upgrade.signal", "SIGUSR2"))
+	sig := upgradeSignal(sigStr)
+	if a.settings.HotReloadEnabled && sig == a.settings.HotReloadSignal() {
+		a.Log().Warnf("Binary upgrade signal (%s) conflicts with config hot-reload signal, "+
+			"binary upgrade is disabled", sigStr)
+		return
+	}
+
+	a.Log().Infof("App Binary Upgrade Signal: %s", sigStr)
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, sig)
+	for range sc {
+		a.Log().Warnf("Binary upgrade signal (%s) received", sigStr)
+		if err := a.upgrade(); err != nil {
+			a.Log().Errorf("Binary upgrade: %v", err)
+		}
+	}
+}
+
+func (a *Application) upgrade() error {
+	u := &a.upgrader
+	u.Lock()
+	if u.upgrading {
+		u.Unlock()
+		return errors.New("upgrade is already in progress")
+	}
+	u.upgrading = true
+	u.Unlock()
+
+	err := a.startUpgradeProcess()
+	if err != nil {
+		u.Lock()
+		u.upgrading = false
+		u.Unlock()
+	}
+	return err
+}
+
+func (a *Application) startUpgradeProcess() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	files, keys, err := a.upgrader.files()
+	if err != nil {
+		return err
+	}
+	defer closeFiles(files)
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		envUpgradeFds+"="+strings.Join(keys, ","),
+		envUpgradeParent+"="+strconv.Itoa(os.Getpid()))
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	a.Log().Infof("Binary upgrade: new process started with pid %d", cmd.Process.Pid)
+	go a.waitUpgradeProcess(cmd)
+	return nil
+}
+
+// waitUpgradeProcess method reaps the new process. On successful upgrade the
+// old process exits before new one, otherwise new process died early so
+// upgrade state is reset to allow subsequent upgrade signals.
+func (a *Application) waitUpgradeProcess(cmd *exec.Cmd) {
+	err := cmd.Wait()
+	u := &a.upgrader
+	u.Lock()
+	u.upgrading = false
+	u.Unlock()
+	if err != nil {
+		a.Log().Errorf("Binary upgrade: new process (pid %d) exited: %v", cmd.Process.Pid, err)
+		return
+	}
+	a.Log().Warnf("Binary upgrade: new process (pid %d) exited", cmd.Process.Pid)
+}
+
+// notifyUpgradeParent method signals the old process to shutdown gracefully,
+// it's called once new process is listening.
+func (a *Application) notifyUpgradeParent() {
+	ppid := os.Getenv(envUpgradeParent)
+	if len(ppid) == 0 {
+		return
+	}
+	_ = os.Unsetenv(envUpgradeParent)
+	pid, err := strconv.Atoi(ppid)
+	if err != nil || pid <= 1 {
+		return
+	}
+	a.Log().Infof("Binary upgrade: signaling old process (pid %d) to shutdown gracefully", pid)
+	if err = syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		a.Log().Errorf("Binary upgrade: %v", err)
+	}
+}
+
+func upgradeSignal(s string) os.Signal {
+	switch s {
+	case "SIGUSR1":
+		return syscall.SIGUSR1
+	case "SIGHUP":
+		return syscall.SIGHUP
+	}
+	return syscall.SIGUSR2
+}
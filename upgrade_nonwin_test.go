@@ -0,0 +1,83 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package aah
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgradeListenerHandoff(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	old := newTestApp(t, importPath)
+	old.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	l1, err := old.inheritOrListen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l1.Close()
+	sockFile := filepath.Join(os.TempDir(), "aah-upgrade-test.sock")
+	_ = os.Remove(sockFile)
+	l2, err := old.inheritOrListen("unix", sockFile)
+	assert.Nil(t, err)
+
+	files, keys, err := old.upgrader.files()
+	assert.Nil(t, err)
+	defer closeFiles(files)
+	assert.Equal(t, []string{"tcp|127.0.0.1:0", "unix|" + sockFile}, keys)
+
+	// socket file is not removed on old process listener close
+	assert.Nil(t, l2.Close())
+	_, err = os.Stat(sockFile)
+	assert.Nil(t, err)
+
+	// new process inherits the listeners
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+	a.upgrader.once.Do(func() {})
+	assert.Nil(t, a.upgrader.inherit(keys[:1], files[:1]))
+	assert.True(t, a.isInherited("tcp", "127.0.0.1:0"))
+	assert.False(t, a.isInherited("tcp", "127.0.0.1:8080"))
+
+	l, err := a.inheritOrListen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	assert.Equal(t, l1.Addr().String(), l.Addr().String())
+	assert.False(t, a.isInherited("tcp", "127.0.0.1:0"))
+
+	conn, err := net.Dial("tcp", l1.Addr().String())
+	assert.Nil(t, err)
+	_ = conn.Close()
+
+	f, err := ioutil.TempFile("", "aah-upgrade")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	err = a.upgrader.inherit([]string{"tcp|127.0.0.1:9999"}, []*os.File{f})
+	assert.NotNil(t, err)
+	_ = os.Remove(sockFile)
+}
+
+func TestUpgradeProcessExitedEarly(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	a.upgrader.upgrading = true
+	assert.NotNil(t, a.upgrade())
+
+	cmd := exec.Command("sh", "-c", "exit 1")
+	assert.Nil(t, cmd.Start())
+	a.waitUpgradeProcess(cmd)
+	assert.False(t, a.upgrader.upgrading)
+	assert.NotNil(t, cmd.ProcessState)
+}
@@ -0,0 +1,17 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package aah
+
+// listenForUpgrade method does nothing, binary upgrade is not supported
+// on Windows OS.
+func (a *Application) listenForUpgrade() {
+	if a.Config().BoolDefault("server.upgrade.enable", false) {
+		a.Log().Warn("OS Windows does not support binary upgrade (server.upgrade), it's disabled")
+	}
+}
+
+func (a *Application) notifyUpgradeParent() {}
@@ -0,0 +1,230 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package valpar
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"aahframe.work/log"
+)
+
+var (
+	nullBytes = []byte("null")
+
+	optionalTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(OptionalString{}):  reflect.TypeOf(""),
+		reflect.TypeOf(OptionalInt{}):     reflect.TypeOf(int(0)),
+		reflect.TypeOf(OptionalInt64{}):   reflect.TypeOf(int64(0)),
+		reflect.TypeOf(OptionalFloat64{}): reflect.TypeOf(float64(0)),
+		reflect.TypeOf(OptionalBool{}):    reflect.TypeOf(false),
+		reflect.TypeOf(OptionalTime{}):    timeType,
+	}
+)
+
+func init() {
+	for typ := range optionalTypes {
+		typeParsers[typ] = handleOptional
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Optional types
+//______________________________________________________________________________
+
+// Optional types are used for action parameters and struct fields where
+// absence of value have to be distinguished from zero value, for e.g.: PATCH
+// style partial updates.
+//
+// `Present` is true if the value is present in the request (params or JSON
+// body) and `Null` is true if value is present with JSON `null` or empty
+// value (except `OptionalString`). For validation, value is nil unless it's
+// present and not null, so `omitempty` and `required` works as expected.
+//
+// 	type UserPatch struct {
+// 		Name valpar.OptionalString `json:"name" validate:"omitempty,min=2"`
+// 		Age  valpar.OptionalInt    `json:"age" validate:"omitempty,gt=0"`
+// 	}
+//
+// 	if v, ok := patch.Age.Get(); ok {
+// 		user.Age = v
+// 	}
+type (
+	// OptionalString is optional value of type `string`.
+	OptionalString struct {
+		Value   string
+		Present bool
+		Null    bool
+	}
+
+	// OptionalInt is optional value of type `int`.
+	OptionalInt struct {
+		Value   int
+		Present bool
+		Null    bool
+	}
+
+	// OptionalInt64 is optional value of type `int64`.
+	OptionalInt64 struct {
+		Value   int64
+		Present bool
+		Null    bool
+	}
+
+	// OptionalFloat64 is optional value of type `float64`.
+	OptionalFloat64 struct {
+		Value   float64
+		Present bool
+		Null    bool
+	}
+
+	// OptionalBool is optional value of type `bool`.
+	OptionalBool struct {
+		Value   bool
+		Present bool
+		Null    bool
+	}
+
+	// OptionalTime is optional value of type `time.Time`.
+	OptionalTime struct {
+		Value   time.Time
+		Present bool
+		Null    bool
+	}
+)
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalString) Get() (string, bool) { return o.Value, o.Present && !o.Null }
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalInt) Get() (int, bool) { return o.Value, o.Present && !o.Null }
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalInt64) Get() (int64, bool) { return o.Value, o.Present && !o.Null }
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalFloat64) Get() (float64, bool) { return o.Value, o.Present && !o.Null }
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalBool) Get() (bool, bool) { return o.Value, o.Present && !o.Null }
+
+// Get method returns the value and true if value is present and not null.
+func (o OptionalTime) Get() (time.Time, bool) { return o.Value, o.Present && !o.Null }
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalString) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalInt) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalInt64) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalFloat64) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalBool) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// UnmarshalJSON method is JSON unmarshaler interface.
+func (o *OptionalTime) UnmarshalJSON(b []byte) error {
+	return unmarshalOptional(b, &o.Value, &o.Present, &o.Null)
+}
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalString) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalInt) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalInt64) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalFloat64) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalBool) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// MarshalJSON method is JSON marshaler interface.
+func (o OptionalTime) MarshalJSON() ([]byte, error) { return marshalOptional(o.Get()) }
+
+// OptionalValueType method returns the value type of given optional type,
+// for e.g.: `int` for `OptionalInt`. It returns false if it's not an
+// optional type.
+func OptionalValueType(typ reflect.Type) (reflect.Type, bool) {
+	typ, _ = checkPtr(typ)
+	vt, found := optionalTypes[typ]
+	return vt, found
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func handleOptional(key string, typ reflect.Type, params url.Values) (reflect.Value, error) {
+	var err error
+	var isPtr bool
+	typ, isPtr = checkPtr(typ)
+	elem := reflect.New(typ).Elem()
+	if _, found := params[key]; !found {
+		if isPtr {
+			return reflect.Zero(reflect.PtrTo(typ)), nil
+		}
+		return elem, nil
+	}
+
+	elem.FieldByName("Present").SetBool(true)
+	value := params.Get(key)
+	if typ != reflect.TypeOf(OptionalString{}) && len(strings.TrimSpace(value)) == 0 {
+		elem.FieldByName("Null").SetBool(true)
+	} else if err = parse(value, elem.FieldByName("Value")); err != nil {
+		log.Errorf("Parameter parse error: %s [type: %s, name: %s, value: %s]", err, typ, key, value)
+	}
+
+	if isPtr {
+		return elem.Addr(), err
+	}
+	return elem, err
+}
+
+func unmarshalOptional(b []byte, v interface{}, present, null *bool) error {
+	*present = true
+	if bytes.Equal(bytes.TrimSpace(b), nullBytes) {
+		*null = true
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func marshalOptional(v interface{}, ok bool) ([]byte, error) {
+	if !ok {
+		return nullBytes, nil
+	}
+	return json.Marshal(v)
+}
+
+// optionalValue method is validator custom type func, it returns nil if
+// optional value is not present or null.
+func optionalValue(field reflect.Value) interface{} {
+	if !field.FieldByName("Present").Bool() || field.FieldByName("Null").Bool() {
+		return nil
+	}
+	return field.FieldByName("Value").Interface()
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package valpar
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userPatch struct {
+	Name   OptionalString  `bind:"name" json:"name" validate:"omitempty,min=2"`
+	Age    OptionalInt     `bind:"age" json:"age" validate:"omitempty,gt=0"`
+	Score  OptionalFloat64 `bind:"score" json:"score"`
+	Active OptionalBool    `bind:"active" json:"active"`
+	Email  OptionalString  `bind:"email" json:"email" validate:"required,email"`
+	Born   *OptionalTime   `bind:"born" json:"born"`
+	Nick   *string         `bind:"nick" json:"nick" validate:"omitempty,min=3"`
+}
+
+func TestParserOptionalStruct(t *testing.T) {
+	params, err := url.ParseQuery("name=&age=&active=true&email=jeeva@example.com&born=2018-06-12")
+	assert.Nil(t, err)
+
+	StructTagName = "bind"
+	TimeFormats = []string{"2006-01-02"}
+	val, err := Struct("", reflect.TypeOf(userPatch{}), params)
+	assert.Nil(t, err)
+
+	p := val.Interface().(userPatch)
+	assert.Equal(t, OptionalString{Present: true}, p.Name)
+	assert.Equal(t, OptionalInt{Present: true, Null: true}, p.Age)
+	assert.Equal(t, OptionalFloat64{}, p.Score)
+	assert.Equal(t, OptionalBool{Value: true, Present: true}, p.Active)
+	assert.Equal(t, "2018-06-12", p.Born.Value.Format("2006-01-02"))
+	assert.Nil(t, p.Nick) // absence is nil for pointer
+
+	v, ok := p.Age.Get()
+	assert.Equal(t, 0, v)
+	assert.False(t, ok)
+
+	// parse error
+	params.Set("age", "ten")
+	_, err = Struct("", reflect.TypeOf(userPatch{}), params)
+	assert.NotNil(t, err)
+}
+
+func TestParserOptionalJSON(t *testing.T) {
+	val, err := Body("application/json", bytes.NewReader([]byte(`{"name":"aah","age":null,"email":"jeeva@example.com"}`)),
+		reflect.TypeOf(userPatch{}))
+	assert.Nil(t, err)
+
+	p := val.Interface().(userPatch)
+	assert.Equal(t, OptionalString{Value: "aah", Present: true}, p.Name)
+	assert.Equal(t, OptionalInt{Present: true, Null: true}, p.Age)
+	assert.False(t, p.Score.Present)
+	assert.Nil(t, p.Born)
+
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"aah","age":null,"score":null,"active":null,"email":"jeeva@example.com","born":null,"nick":null}`, string(b))
+
+	b, err = json.Marshal(OptionalTime{Value: time.Date(2018, 6, 12, 0, 0, 0, 0, time.UTC), Present: true})
+	assert.Nil(t, err)
+	assert.Equal(t, `"2018-06-12T00:00:00Z"`, string(b))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"age":"ten"}`), &p))
+
+	vt, found := OptionalValueType(reflect.TypeOf(&OptionalInt64{}))
+	assert.True(t, found)
+	assert.Equal(t, reflect.TypeOf(int64(0)), vt)
+	_, found = OptionalValueType(reflect.TypeOf(0))
+	assert.False(t, found)
+}
+
+func TestValidateOptional(t *testing.T) {
+	// absent values are skipped by omitempty
+	errs, err := Validate(userPatch{Email: OptionalString{Value: "jeeva@example.com", Present: true}})
+	assert.Nil(t, err)
+	assert.Nil(t, errs)
+
+	// null values are skipped by omitempty
+	errs, _ = Validate(userPatch{
+		Name:  OptionalString{Present: true, Null: true},
+		Age:   OptionalInt{Present: true, Null: true},
+		Email: OptionalString{Value: "jeeva@example.com", Present: true},
+	})
+	assert.Nil(t, errs)
+
+	// present values are validated
+	nick := "ab"
+	errs, _ = Validate(userPatch{
+		Name:  OptionalString{Value: "a", Present: true},
+		Age:   OptionalInt{Value: -1, Present: true},
+		Nick:  &nick,
+		Email: OptionalString{Present: true, Null: true},
+	})
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, "Name", errs[0].Field())
+	assert.Equal(t, "Age", errs[1].Field())
+	assert.Equal(t, "required", errs[2].Tag())
+	assert.Equal(t, "Nick", errs[3].Field())
+}
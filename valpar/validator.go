@@ -7,6 +7,7 @@ package valpar
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"gopkg.in/go-playground/validator.v9"
@@ -31,6 +32,9 @@ func Validator() *validator.Validate {
 		aahValidator = validator.New()
 
 		// Do customizations here
+		for typ := range optionalTypes {
+			aahValidator.RegisterCustomTypeFunc(optionalValue, reflect.New(typ).Elem().Interface())
+		}
 	}
 	return aahValidator
 }
@@ -81,6 +85,22 @@ func ValidateValues(values map[string]string, constraints map[string]string) Err
 	return errs
 }
 
+// NewErrors method converts the validation errors of given struct type into
+// `Errors`. Field name is the value of struct tag `StructTagName`, same as
+// binding otherwise struct field name. i18n key is `validation.<tag>`.
+func NewErrors(typ reflect.Type, errs validator.ValidationErrors) Errors {
+	result := make(Errors, 0, len(errs))
+	for _, fe := range errs {
+		result = append(result, &Error{
+			Field:      fieldName(typ, fe.StructNamespace()),
+			Value:      fmt.Sprint(fe.Value()),
+			Key:        "validation." + fe.Tag(),
+			Constraint: fe.Tag(),
+		})
+	}
+	return result
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Error type and its methods
 //______________________________________________________________________________
@@ -120,6 +140,32 @@ func (e Error) String() string {
 // Unexported methods
 //______________________________________________________________________________
 
+// fieldName method returns the bind field name for the struct namespace,
+// for e.g.: `User.Address.City` => `address.city`.
+func fieldName(typ reflect.Type, ns string) string {
+	parts := strings.Split(ns, ".")[1:]
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		typ, _ = checkPtr(typ)
+		if typ.Kind() != reflect.Struct {
+			names = append(names, p)
+			continue
+		}
+		f, found := typ.FieldByName(p)
+		if !found {
+			names = append(names, p)
+			continue
+		}
+		if tn := f.Tag.Get(StructTagName); len(tn) > 0 && tn != "-" {
+			names = append(names, tn)
+		} else {
+			names = append(names, p)
+		}
+		typ = f.Type
+	}
+	return strings.Join(names, ".")
+}
+
 func checkAndReturn(err error) (validator.ValidationErrors, error) {
 	if err != nil {
 		if ive, ok := err.(*validator.InvalidValidationError); ok {
@@ -137,3 +137,28 @@ func TestValidatorValidateValues(t *testing.T) {
 		}
 	}
 }
+
+func TestValidatorNewErrors(t *testing.T) {
+	StructTagName = "bind"
+	type testAddress struct {
+		City string `bind:"city" validate:"required"`
+	}
+	type testUser struct {
+		Email   string       `bind:"email" validate:"required,email"`
+		Age     int          `validate:"gte=18"`
+		Address *testAddress `bind:"address"`
+	}
+
+	errs, err := Validate(&testUser{Email: "aah", Age: 10, Address: &testAddress{}})
+	assert.Nil(t, err)
+
+	result := NewErrors(reflect.TypeOf(&testUser{}), errs)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, "email", result[0].Field)
+	assert.Equal(t, "aah", result[0].Value)
+	assert.Equal(t, "validation.email", result[0].Key)
+	assert.Equal(t, "email", result[0].Constraint)
+	assert.Equal(t, "Age", result[1].Field)
+	assert.Equal(t, "address.city", result[2].Field)
+	assert.Equal(t, "required", result[2].Constraint)
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"html/template"
 	"io"
+	"net/textproto"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -21,6 +22,23 @@ import (
 	"aahframe.work/log"
 )
 
+// Bind sources of struct field, it's used as prefix in the struct tag value
+// to bind the field from specific source of the request instead of form,
+// query and path params.
+//
+// 	type SearchParams struct {
+// 		ID     int    `bind:"p:id"`
+// 		Page   int    `bind:"q:page"`
+// 		APIKey string `bind:"h:X-Api-Key" validate:"required"`
+// 		Hint   string `bind:"c:session_hint"`
+// 	}
+const (
+	SourcePath   = "p"
+	SourceQuery  = "q"
+	SourceHeader = "h"
+	SourceCookie = "c"
+)
+
 var (
 	// ErrTypeOrParserIsNil returned when supplied `reflect.Type` or parser is nil to
 	// the method `AddValueParser`.
@@ -36,6 +54,10 @@ var (
 	// StructTagName is used while binding struct fields.
 	StructTagName string
 
+	// Sanitize is applied on string values while binding to prevent XSS
+	// attacks, aah sets it from application sanitizer.
+	Sanitize = template.HTMLEscapeString
+
 	kindHandlers = map[reflect.Kind]Parser{
 		reflect.Int:     handleTypes,
 		reflect.Int8:    handleTypes,
@@ -137,7 +159,9 @@ func Struct(key string, typ reflect.Type, params url.Values) (reflect.Value, err
 			continue
 		}
 
-		if len(key) > 0 {
+		if sk, found := SourceKey(fname); found {
+			fname = sk
+		} else if len(key) > 0 {
 			fname = key + "." + fname
 		}
 
@@ -164,6 +188,73 @@ rv:
 	return s.Elem(), err
 }
 
+// Sources method binds the struct fields which has bind source prefix in the
+// struct tag from given params, nested struct fields are bound too. It's used
+// after request body parsing, so that struct could mix the body and request
+// sources.
+func Sources(v reflect.Value, params url.Values) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	st := v.Type()
+	for idx := 0; idx < st.NumField(); idx++ {
+		ft := st.Field(idx)
+		f := v.Field(idx)
+		if !f.CanSet() {
+			continue
+		}
+
+		sk, found := SourceKey(ft.Tag.Get(StructTagName))
+		if !found {
+			if fft, _ := checkPtr(f.Type()); fft.Kind() == reflect.Struct && fft != timeType {
+				if err := Sources(f, params); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if _, found := params[sk]; !found {
+			continue
+		}
+
+		vpFn, found := ValueParser(f.Type())
+		if !found {
+			continue
+		}
+		fv, err := vpFn(sk, f.Type(), params)
+		if err != nil {
+			return err
+		}
+		if fv.IsValid() {
+			f.Set(fv)
+		}
+	}
+	return nil
+}
+
+// SourceKey method returns the params key for given struct tag value if it
+// has bind source prefix, for e.g.: `q:page`, `h:X-Api-Key`. Header name is
+// canonicalized. It returns false if tag value has no bind source prefix.
+func SourceKey(tag string) (string, bool) {
+	if len(tag) < 3 || tag[1] != ':' {
+		return "", false
+	}
+	switch src := tag[:1]; src {
+	case SourcePath, SourceQuery, SourceCookie:
+		return tag, true
+	case SourceHeader:
+		return src + ":" + textproto.CanonicalMIMEHeaderKey(tag[2:]), true
+	}
+	return "", false
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //______________________________________________________________________________
@@ -174,6 +265,9 @@ func handleTypes(key string, typ reflect.Type, params url.Values) (reflect.Value
 	typ, isPtr = checkPtr(typ)
 	elem := reflect.New(typ).Elem()
 	if _, found := params[key]; !found {
+		if isPtr { // absence is nil for pointer type
+			return reflect.Zero(reflect.PtrTo(typ)), nil
+		}
 		goto rv
 	}
 
@@ -289,8 +383,7 @@ func parseBool(value string, elem reflect.Value) error {
 
 func parseString(value string, elem reflect.Value) error {
 	// Sanitize it; to prevent XSS attacks
-	value = template.HTMLEscapeString(value)
-	elem.SetString(value)
+	elem.SetString(Sanitize(value))
 	return nil
 }
 
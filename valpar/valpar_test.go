@@ -265,3 +265,62 @@ func TestParserStructNested(t *testing.T) {
 	assert.Equal(t, "Residence City", s.ResidenceAddress.City)
 	assert.Equal(t, "10002", s.ResidenceAddress.ZipCode)
 }
+
+type requestMeta struct {
+	APIKey string `bind:"h:x-api-key"`
+	Hint   string `bind:"c:session_hint"`
+}
+
+type sourceSample struct {
+	ID    int         `bind:"p:id"`
+	Page  int         `bind:"q:page"`
+	Name  string      `bind:"name" json:"name"`
+	Meta  requestMeta `bind:"meta"`
+	Since *time.Time  `bind:"q:since"`
+}
+
+func TestParserStructSources(t *testing.T) {
+	params := url.Values{
+		"p:id":              []string{"1001"},
+		"q:page":            []string{"3"},
+		"q:since":           []string{"2018-06-12"},
+		"name":              []string{"aah"},
+		"h:X-Api-Key":       []string{"key123"},
+		"c:session_hint":    []string{"hint1"},
+		"meta.api_key":      []string{"ignored"},
+		"meta.session_hint": []string{"ignored"},
+	}
+
+	StructTagName = "bind"
+	TimeFormats = []string{"2006-01-02"}
+	val, err := Struct("", reflect.TypeOf(&sourceSample{}), params)
+	assert.Nil(t, err)
+
+	s := val.Interface().(*sourceSample)
+	assert.Equal(t, 1001, s.ID)
+	assert.Equal(t, 3, s.Page)
+	assert.Equal(t, "aah", s.Name)
+	assert.Equal(t, "key123", s.Meta.APIKey)
+	assert.Equal(t, "hint1", s.Meta.Hint)
+	assert.Equal(t, "2018-06-12", s.Since.Format("2006-01-02"))
+
+	// after body parse
+	val, err = Body("application/json", bytes.NewReader([]byte(`{"name":"from body"}`)), reflect.TypeOf(sourceSample{}))
+	assert.Nil(t, err)
+	assert.Nil(t, Sources(val.Addr(), params))
+	s = val.Addr().Interface().(*sourceSample)
+	assert.Equal(t, "from body", s.Name)
+	assert.Equal(t, 1001, s.ID)
+	assert.Equal(t, "key123", s.Meta.APIKey)
+
+	// conversion error
+	params.Set("q:page", "three")
+	err = Sources(reflect.ValueOf(&sourceSample{}), params)
+	assert.NotNil(t, err)
+
+	// source key
+	for tag, expected := range map[string]string{"h:x-request-id": "h:X-Request-Id", "q:page": "q:page", "page": "", "x:page": "", "q:": ""} {
+		sk, _ := SourceKey(tag)
+		assert.Equal(t, expected, sk, "tag: %s", tag)
+	}
+}
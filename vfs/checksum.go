@@ -0,0 +1,154 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package vfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrManifestNotExists returned when VFS does not have integrity manifest,
+// refer to `VFS.SetManifest`.
+var ErrManifestNotExists = errors.New("vfs: integrity manifest does not exist")
+
+// Manifest holds the SHA-256 checksum (hex) of the files by its virtual path.
+// It is generated for packaged app and used for integrity check.
+type Manifest map[string]string
+
+// IntegrityError holds the files that do not match with the integrity manifest.
+type IntegrityError struct {
+	Mismatched []string
+	Missing    []string
+}
+
+// Error method is error interface implementation.
+func (e *IntegrityError) Error() string {
+	var parts []string
+	if len(e.Mismatched) > 0 {
+		parts = append(parts, fmt.Sprintf("checksum mismatch [%s]", strings.Join(e.Mismatched, ", ")))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing [%s]", strings.Join(e.Missing, ", ")))
+	}
+	return "vfs: integrity check failed, " + strings.Join(parts, ", ")
+}
+
+type fileSum struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+// Checksum method returns the SHA-256 checksum (hex) of the file content.
+// It calls `VFS.Checksum` if fs is VFS otherwise computes it from OS
+// filesystem (fs == nil) or given fs.
+func Checksum(fs FileSystem, name string) (string, error) {
+	if v, ok := fs.(*VFS); ok {
+		return v.Checksum(name)
+	}
+	data, err := ReadFile(fs, name)
+	if err != nil {
+		return "", err
+	}
+	return checksum(data), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// VFS checksum methods
+//______________________________________________________________________________
+
+// Checksum method returns the SHA-256 checksum (hex) of the file content.
+// Checksum is cached until the file size or modification time changes.
+func (v *VFS) Checksum(name string) (string, error) {
+	fi, err := v.Stat(name)
+	if err != nil {
+		return "", err
+	}
+	if fi.IsDir() {
+		return "", &os.PathError{Op: "checksum", Path: name, Err: errors.New("is a directory")}
+	}
+
+	v.sumMu.Lock()
+	defer v.sumMu.Unlock()
+	if fs, found := v.sums[name]; found && fs.size == fi.Size() && fs.modTime.Equal(fi.ModTime()) {
+		return fs.sum, nil
+	}
+	data, err := v.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	if v.sums == nil {
+		v.sums = make(map[string]*fileSum)
+	}
+	fs := &fileSum{size: fi.Size(), modTime: fi.ModTime(), sum: checksum(data)}
+	v.sums[name] = fs
+	return fs.sum, nil
+}
+
+// GenerateManifest method returns the integrity manifest of all the files
+// under given root path.
+func (v *VFS) GenerateManifest(root string) (Manifest, error) {
+	files, err := v.Files(root)
+	if err != nil {
+		return nil, err
+	}
+	m := make(Manifest, len(files))
+	for _, f := range files {
+		data, err := v.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		m[f] = checksum(data)
+	}
+	return m, nil
+}
+
+// SetManifest method sets the integrity manifest into VFS, typically it is
+// done by generated code of packaged app.
+func (v *VFS) SetManifest(m Manifest) {
+	v.manifest = m
+}
+
+// Manifest method returns the integrity manifest of VFS otherwise nil.
+func (v *VFS) Manifest() Manifest {
+	return v.manifest
+}
+
+// VerifyManifest method verifies the files against the integrity manifest,
+// it returns `*IntegrityError` if any of file is tampered or missing.
+func (v *VFS) VerifyManifest() error {
+	if v.manifest == nil {
+		return ErrManifestNotExists
+	}
+	ie := &IntegrityError{}
+	for name, sum := range v.manifest {
+		data, err := v.ReadFile(name)
+		switch {
+		case os.IsNotExist(err):
+			ie.Missing = append(ie.Missing, name)
+		case err != nil:
+			return err
+		case checksum(data) != sum:
+			ie.Mismatched = append(ie.Mismatched, name)
+		}
+	}
+	if len(ie.Mismatched) == 0 && len(ie.Missing) == 0 {
+		return nil
+	}
+	sort.Strings(ie.Mismatched)
+	sort.Strings(ie.Missing)
+	return ie
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package vfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVFSChecksum(t *testing.T) {
+	fs := createVFS(t)
+	physicalFile := filepath.Join(testdataBaseDir(), "vfstest", "config", "aah.conf")
+	data, err := ioutil.ReadFile(physicalFile)
+	assert.Nil(t, err)
+	expected := sha256.Sum256(data)
+
+	// gzip node, checksum of the content
+	sum, err := fs.Checksum("/app/config/aah.conf")
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(expected[:]), sum)
+
+	sum, err = Checksum(fs, "/app/config/aah.conf")
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(expected[:]), sum)
+
+	sum, err = Checksum(nil, physicalFile)
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(expected[:]), sum)
+
+	_, err = fs.Checksum("/app/config")
+	assert.Equal(t, "checksum /app/config: is a directory", err.Error())
+	_, err = fs.Checksum("/app/config/notexists.conf")
+	assert.True(t, os.IsNotExist(err))
+
+	// physical files, cached checksum is refreshed on file change
+	dir, err := ioutil.TempDir("", "vfs-checksum")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("var a = 1;"), 0644))
+
+	pfs := new(VFS)
+	assert.Nil(t, pfs.AddMount("/static", dir))
+	sum1, err := pfs.Checksum("/static/app.js")
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("var a = 2;"), 0644))
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "app.js"), time.Now(), time.Now().Add(time.Minute)))
+	sum2, err := pfs.Checksum("/static/app.js")
+	assert.Nil(t, err)
+	assert.NotEqual(t, sum1, sum2)
+}
+
+func TestVFSManifest(t *testing.T) {
+	fs := createVFS(t)
+	assert.Equal(t, ErrManifestNotExists, fs.VerifyManifest())
+
+	m, err := fs.GenerateManifest("/app/config")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(m))
+
+	fs.SetManifest(m)
+	assert.Equal(t, m, fs.Manifest())
+	assert.Nil(t, fs.VerifyManifest())
+
+	// tampered and missing files
+	tampered := Manifest{}
+	for k, v := range m {
+		tampered[k] = v
+	}
+	tampered["/app/config/routes.conf"] = "0000"
+	tampered["/app/config/removed.conf"] = "0000"
+	fs.SetManifest(tampered)
+	err = fs.VerifyManifest()
+	ie, ok := err.(*IntegrityError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"/app/config/routes.conf"}, ie.Mismatched)
+	assert.Equal(t, []string{"/app/config/removed.conf"}, ie.Missing)
+	assert.Equal(t, "vfs: integrity check failed, checksum mismatch [/app/config/routes.conf], "+
+		"missing [/app/config/removed.conf]", err.Error())
+}
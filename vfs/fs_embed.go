@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.16
+
+package vfs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"time"
+)
+
+// AddMountFS method mounts the given file system tree (for e.g.: `embed.FS`)
+// as a virtual mounted directory and sets the VFS into Embedded Mode. It is
+// the alternative to VFS code generated by aah CLI for single binary build.
+//
+// File system without modification time (`embed.FS`) gets the mount time.
+func (v *VFS) AddMountFS(mountPath string, fsys fs.FS) error {
+	v.SetEmbeddedMode()
+	mp := path.Clean("/" + mountPath)
+	if err := v.AddMount(mp, mp); err != nil {
+		if perr, ok := err.(*os.PathError); !ok || perr.Err != ErrMountExists {
+			return err
+		}
+	}
+	m, err := v.FindMount(mp)
+	if err != nil {
+		return err
+	}
+
+	mountTime := time.Now().UTC()
+	return fs.WalkDir(fsys, ".", func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil || fpath == "." {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		ni := &NodeInfo{Dir: d.IsDir(), Path: path.Join(mp, fpath), Time: fi.ModTime()}
+		if ni.Time.IsZero() {
+			ni.Time = mountTime
+		}
+		if ni.Dir {
+			return m.AddDir(ni)
+		}
+		data, err := fs.ReadFile(fsys, fpath)
+		if err != nil {
+			return err
+		}
+		ni.DataSize = int64(len(data))
+		return m.AddFile(ni, data)
+	})
+}
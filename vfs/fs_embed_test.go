@@ -0,0 +1,54 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build go1.16
+
+package vfs
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVFSAddMountFS(t *testing.T) {
+	modTime := time.Date(2021, 2, 16, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"config/aah.conf":    {Data: []byte("name = \"embedapp\"\n"), ModTime: modTime},
+		"views/index.html":   {Data: []byte("<html></html>")},
+		"static/css/a.css":   {Data: []byte("body{}")},
+		"static/js/.gitkeep": {},
+	}
+
+	v := new(VFS)
+	err := v.AddMountFS("app", fsys)
+	assert.Nil(t, err)
+	assert.True(t, v.IsEmbeddedMode())
+
+	data, err := v.ReadFile("/app/config/aah.conf")
+	assert.Nil(t, err)
+	assert.Equal(t, "name = \"embedapp\"\n", string(data))
+
+	fi, err := v.Stat("/app/config/aah.conf")
+	assert.Nil(t, err)
+	assert.Equal(t, modTime, fi.ModTime())
+	assert.Equal(t, int64(18), fi.Size())
+
+	fi, err = v.Stat("/app/views/index.html")
+	assert.Nil(t, err)
+	assert.False(t, fi.ModTime().IsZero())
+
+	assert.True(t, v.IsExists("/app/static/js/.gitkeep"))
+	dirs, err := v.Dirs("/app/static")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/app/static", "/app/static/css", "/app/static/js"}, dirs)
+
+	// mount exists, adds into existing tree
+	err = v.AddMountFS("/app", fstest.MapFS{"i18n/messages.en": {Data: []byte("label = \"Label\"")}})
+	assert.Nil(t, err)
+	assert.True(t, v.IsExists("/app/i18n/messages.en"))
+	assert.True(t, v.IsExists("/app/config/aah.conf"))
+}
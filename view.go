@@ -5,6 +5,7 @@
 package aah
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"path"
@@ -13,7 +14,6 @@ import (
 
 	"aahframe.work/ahttp"
 	"aahframe.work/internal/settings"
-	"aahframe.work/internal/util"
 	"aahframe.work/security"
 	"aahframe.work/view"
 )
@@ -65,12 +65,18 @@ func (a *Application) initView() error {
 		"session":         viewMgr.tmplSessionValue,
 		"flash":           viewMgr.tmplFlashValue,
 		"isauthenticated": viewMgr.tmplIsAuthenticated,
+		"isrunas":         viewMgr.tmplIsRunAs,
+		"isguest":         viewMgr.tmplIsGuest,
 		"hasrole":         viewMgr.tmplHasRole,
 		"hasallroles":     viewMgr.tmplHasAllRoles,
 		"hasanyrole":      viewMgr.tmplHasAnyRole,
 		"ispermitted":     viewMgr.tmplIsPermitted,
 		"ispermittedall":  viewMgr.tmplIsPermittedAll,
 		"anticsrftoken":   viewMgr.tmplAntiCSRFToken,
+		"tojson":          viewMgr.tmplToJSON,
+		"formfield":       viewMgr.tmplFormField,
+		"formerrors":      viewMgr.tmplFormErrors,
+		"oldinput":        viewMgr.tmplOldInput,
 	})
 
 	if err := viewEngine.Init(a.VFS(), a.Config(), viewsDir); err != nil {
@@ -165,6 +171,14 @@ func (vm *viewManager) resolve(ctx *Context) {
 	tmplPath = filepath.Join("pages", tmplPath)
 
 	ctx.Log().Tracef("view(layout:%s path:%s name:%s)", htmlRdr.Layout, tmplPath, tmplName)
+	// Tenant view override
+	if ctx.tenant != nil {
+		if tmpl, err := vm.engine.Get(htmlRdr.Layout, ctx.tenant.tmplPath(tmplPath), tmplName); err == nil {
+			htmlRdr.Template = tmpl
+			return
+		}
+	}
+
 	var err error
 	if htmlRdr.Template, err = vm.engine.Get(htmlRdr.Layout, tmplPath, tmplName); err != nil {
 		if err == view.ErrTemplateNotFound {
@@ -199,10 +213,15 @@ func (vm *viewManager) addFrameworkValuesIntoViewArgs(ctx *Context) {
 	html.ViewArgs["IsAJAX"] = ctx.Req.IsAJAX()
 	html.ViewArgs["HTTPReferer"] = ctx.Req.Referer()
 	html.ViewArgs["AahVersion"] = Version
+	html.ViewArgs["APIVersion"] = ctx.APIVersion()
+	html.ViewArgs["RouteLocale"] = ctx.RouteLocale()
 	html.ViewArgs[KeyViewArgRequest] = ctx.Req
 	if ctx.subject != nil {
 		html.ViewArgs[KeyViewArgSubject] = ctx.Subject()
 	}
+	if ctx.tenant != nil {
+		html.ViewArgs[KeyViewArgTenant] = ctx.tenant
+	}
 
 	html.ViewArgs["EnvProfile"] = vm.a.EnvProfile()
 	html.ViewArgs["AppBuildInfo"] = vm.a.BuildInfo()
@@ -243,11 +262,11 @@ func (vm *viewManager) tmplRequestParameters(viewArgs map[string]interface{}, fn
 	req := viewArgs[KeyViewArgRequest].(*ahttp.Request)
 	switch fn {
 	case "Q":
-		return util.SanitizeValue(req.QueryValue(key))
+		return vm.a.sanitizeValue(req.QueryValue(key))
 	case "F":
-		return util.SanitizeValue(req.FormValue(key))
+		return vm.a.sanitizeValue(req.FormValue(key))
 	case "P":
-		return util.SanitizeValue(req.PathValue(key))
+		return vm.a.sanitizeValue(req.PathValue(key))
 	}
 	return ""
 }
@@ -259,7 +278,7 @@ func (vm *viewManager) tmplRequestParameters(viewArgs map[string]interface{}, fn
 // tmplConfig method provides access to application config on templates.
 func (vm *viewManager) tmplConfig(key string) interface{} {
 	if value, found := vm.a.Config().Get(key); found {
-		return util.SanitizeValue(value)
+		return vm.a.sanitizeValue(value)
 	}
 	vm.a.Log().Warnf("Configuration key not found: '%s'", key)
 	return ""
@@ -272,15 +291,16 @@ func (vm *viewManager) tmplConfig(key string) interface{} {
 // tmplI18n method is mapped to Go template func for resolving i18n values.
 func (vm *viewManager) tmplI18n(viewArgs map[string]interface{}, key string, args ...interface{}) string {
 	if locale, ok := viewArgs[keyLocale].(*ahttp.Locale); ok {
+		tenant, _ := viewArgs[KeyViewArgTenant].(*Tenant)
 		if len(args) == 0 {
-			return vm.a.I18n().Lookup(locale, key)
+			return vm.a.i18nLookup(tenant, locale, key)
 		}
 
 		sanatizeArgs := make([]interface{}, 0)
 		for _, value := range args {
-			sanatizeArgs = append(sanatizeArgs, util.SanitizeValue(value))
+			sanatizeArgs = append(sanatizeArgs, vm.a.sanitizeValue(value))
 		}
-		return vm.a.I18n().Lookup(locale, key, sanatizeArgs...)
+		return vm.a.i18nLookup(tenant, locale, key, sanatizeArgs...)
 	}
 	return ""
 }
@@ -297,14 +317,21 @@ func (vm *viewManager) tmplURL(viewArgs map[string]interface{}, args ...interfac
 		return template.URL("#")
 	}
 	/* #nosec */
-	return template.URL(vm.a.Router().CreateRouteURL(viewArgs["Host"].(string), args[0].(string), nil, args[1:]...))
+	return template.URL(vm.a.Router().CreateLocaleRouteURL(viewArgs["Host"].(string), viewArgsString(viewArgs, "RouteLocale"), viewArgsString(viewArgs, "APIVersion"), args[0].(string), nil, args[1:]...))
 }
 
 // tmplURLm method returns reverse URL by given route name and
 // map[string]interface{}. Mapped to Go template func.
 func (vm *viewManager) tmplURLm(viewArgs map[string]interface{}, routeName string, args map[string]interface{}) template.URL {
 	/* #nosec */
-	return template.URL(vm.a.Router().CreateRouteURL(viewArgs["Host"].(string), routeName, args))
+	return template.URL(vm.a.Router().CreateLocaleRouteURL(viewArgs["Host"].(string), viewArgsString(viewArgs, "RouteLocale"), viewArgsString(viewArgs, "APIVersion"), routeName, args))
+}
+
+func viewArgsString(viewArgs map[string]interface{}, key string) string {
+	if v, ok := viewArgs[key].(string); ok {
+		return v
+	}
+	return ""
 }
 
 //
@@ -317,7 +344,7 @@ func (vm *viewManager) tmplSessionValue(viewArgs map[string]interface{}, key str
 	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
 		if sub.Session != nil {
 			value := sub.Session.Get(key)
-			return util.SanitizeValue(value)
+			return vm.a.sanitizeValue(value)
 		}
 	}
 	return nil
@@ -328,12 +355,36 @@ func (vm *viewManager) tmplSessionValue(viewArgs map[string]interface{}, key str
 func (vm *viewManager) tmplFlashValue(viewArgs map[string]interface{}, key string) interface{} {
 	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
 		if sub.Session != nil {
-			return util.SanitizeValue(sub.Session.GetFlash(key))
+			return vm.a.sanitizeValue(sub.Session.GetFlash(key))
 		}
 	}
 	return nil
 }
 
+//
+// JSON view functions
+//
+
+// tmplToJSON method returns the JSON of given data for safe embedding in
+// `<script>` blocks. Characters `<`, `>`, `&`, U+2028 and U+2029 are escaped
+// as unicode sequence. JSON is prefixed with `render.secure_json.prefix` if
+// `view.tojson.secure_prefix` is enabled. Mapped to Go template func.
+//
+// For e.g.:
+// 	<script>window.__STATE__ = {{ tojson .State }};</script>
+func (vm *viewManager) tmplToJSON(data interface{}) (template.JS, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return template.JS("null"), err
+	}
+	if vm.a.Config().BoolDefault("view.tojson.secure_prefix", false) {
+		/* #nosec */
+		return template.JS(vm.a.settings.SecureJSONPrefix + string(b)), nil
+	}
+	/* #nosec */
+	return template.JS(b), nil
+}
+
 //
 // Security view functions
 //
@@ -348,6 +399,24 @@ func (vm *viewManager) tmplIsAuthenticated(viewArgs map[string]interface{}) bool
 	return false
 }
 
+// tmplIsRunAs method returns true if subject is running as another identity.
+// Mapped to Go template func.
+func (vm *viewManager) tmplIsRunAs(viewArgs map[string]interface{}) bool {
+	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
+		return sub.IsRunAs()
+	}
+	return false
+}
+
+// tmplIsGuest method returns true if subject is unauthenticated guest, see
+// `security.guest`. Mapped to Go template func.
+func (vm *viewManager) tmplIsGuest(viewArgs map[string]interface{}) bool {
+	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
+		return sub.IsGuest()
+	}
+	return false
+}
+
 // tmplHasRole method returns the value of `Subject.HasRole`.
 func (vm *viewManager) tmplHasRole(viewArgs map[string]interface{}, role string) bool {
 	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
@@ -5,6 +5,8 @@
 package aah
 
 import (
+	"bytes"
+	"html/template"
 	"io"
 	"net/http/httptest"
 	"path/filepath"
@@ -132,3 +134,33 @@ func TestViewMinifier(t *testing.T) {
 		return nil
 	})
 }
+
+func TestViewToJSON(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	tmpl := template.Must(template.New("tojson").Funcs(template.FuncMap{
+		"tojson": ts.app.viewMgr.tmplToJSON,
+	}).Parse(`<script>var state = {{ tojson . }};</script>`))
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]interface{}{
+		"name": "</script><script>alert('aah')</script>",
+		"text": "a & b \u2028 c",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `<script>var state = {"name":"\u003c/script\u003e\u003cscript\u003ealert('aah')\u003c/script\u003e","text":"a \u0026 b \u2028 c"};</script>`, buf.String())
+
+	// secure prefix
+	ts.app.Config().SetBool("view.tojson.secure_prefix", true)
+	defer ts.app.Config().SetBool("view.tojson.secure_prefix", false)
+	v, err := ts.app.viewMgr.tmplToJSON([]int{1, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, template.JS(ts.app.settings.SecureJSONPrefix+"[1,2]"), v)
+
+	// marshal error
+	v, err = ts.app.viewMgr.tmplToJSON(make(chan int))
+	assert.NotNil(t, err)
+	assert.Equal(t, template.JS("null"), v)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+	"aahframe.work/security/scheme"
+)
+
+const (
+	webAuthnTarget      = "webAuthnController"
+	webAuthnMaxBodySize = 64 << 10 // attestation response is small JSON body
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// WebAuthn controller
+//______________________________________________________________________________
+
+// webAuthnController serves the WebAuthn (passkey) registration endpoints,
+// passkey is registered for the authenticated Subject. Registration is denied
+// while Subject is running as another identity. Login endpoints are handled
+// by `AuthcAuthzMiddleware`.
+type webAuthnController struct {
+	*Context
+}
+
+// RegisterBegin method replies the credential creation options and keeps the
+// challenge in the session.
+func (c *webAuthnController) RegisterBegin() {
+	wa := c.webAuthnScheme(func(w *scheme.WebAuthn) string { return w.RegisterBeginURL })
+	if wa == nil {
+		c.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
+		return
+	}
+	if c.isRunAs(wa) {
+		return
+	}
+
+	principal := c.Subject().PrimaryPrincipal()
+	displayName := principal.Value
+	for _, p := range c.Subject().AllPrincipals() {
+		if p.Claim == "Name" {
+			displayName = p.Value
+		}
+	}
+
+	opts, state, err := wa.RegistrationOptions(principal.Value, principal.Value, displayName)
+	if err != nil {
+		c.Log().Errorf("%s: %v", wa.Key(), err)
+		c.Reply().InternalServerError().Error(newError(err, http.StatusInternalServerError))
+		return
+	}
+	c.Session().Set(keyWebAuthnStateKey, state)
+	c.Reply().Header(ahttp.HeaderCacheControl, "no-store").JSON(opts)
+}
+
+// RegisterFinish method verifies the attestation response and saves the
+// credential for the Subject.
+func (c *webAuthnController) RegisterFinish() {
+	wa := c.webAuthnScheme(func(w *scheme.WebAuthn) string { return w.RegisterFinishURL })
+	if wa == nil {
+		c.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
+		return
+	}
+	if c.isRunAs(wa) {
+		return
+	}
+	defer c.Session().Del(keyWebAuthnStateKey)
+
+	body, err := ioutil.ReadAll(c.Req.Body())
+	if err != nil {
+		c.Reply().BadRequest().Error(newError(err, http.StatusBadRequest))
+		return
+	}
+
+	cred, err := wa.FinishRegistration(c.Session().GetString(keyWebAuthnStateKey),
+		c.Subject().PrimaryPrincipal().Value, body)
+	if err != nil {
+		c.Log().Warnf("%s: WebAuthn registration is failed: %v", wa.Key(), err)
+		c.Reply().BadRequest().Error(newError(err, http.StatusBadRequest))
+		return
+	}
+	c.Reply().Created().JSON(Data{"id": base64.RawURLEncoding.EncodeToString(cred.ID), "created_at": cred.CreatedAt})
+}
+
+// isRunAs method replies forbidden if Subject is running as another
+// identity, passkey must not be registered for the impersonated user.
+func (c *webAuthnController) isRunAs(wa *scheme.WebAuthn) bool {
+	if !c.Subject().IsRunAs() {
+		return false
+	}
+	c.Log().Warnf("%s: WebAuthn registration is denied while running as '%s'",
+		wa.Key(), c.Subject().PrimaryPrincipal().Value)
+	c.Reply().Forbidden().Error(newError(ErrAccessDenied, http.StatusForbidden))
+	return true
+}
+
+func (c *webAuthnController) webAuthnScheme(path func(w *scheme.WebAuthn) string) *scheme.WebAuthn {
+	for _, s := range c.a.SecurityManager().AuthSchemes() {
+		if wa, ok := s.(*scheme.WebAuthn); ok && path(wa) == c.Req.Path {
+			return wa
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// addWebAuthnRoutes method adds passkey registration endpoints into all
+// domains for configured WebAuthn auth schemes, endpoints require
+// authenticated Subject.
+func (a *Application) addWebAuthnRoutes(rtr *router.Router) error {
+	if a.SecurityManager() == nil {
+		return nil
+	}
+
+	var schemes []*scheme.WebAuthn
+	for _, s := range a.SecurityManager().AuthSchemes() {
+		if wa, ok := s.(*scheme.WebAuthn); ok {
+			schemes = append(schemes, wa)
+		}
+	}
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	if a.HTTPEngine().registry.Lookup(webAuthnTarget) == nil {
+		a.AddController((*webAuthnController)(nil), []*ainsp.Method{
+			{Name: "RegisterBegin"}, {Name: "RegisterFinish"},
+		})
+	}
+
+	for _, d := range rtr.Domains {
+		for _, wa := range schemes {
+			routes := []*router.Route{
+				{Name: wa.Key() + "_register_begin__aah", Path: wa.RegisterBeginURL, Action: "RegisterBegin"},
+				{Name: wa.Key() + "_register_finish__aah", Path: wa.RegisterFinishURL, Action: "RegisterFinish"},
+			}
+			for _, r := range routes {
+				r.Method = ahttp.MethodPost
+				r.Target = webAuthnTarget
+				r.Auth = "authenticated"
+				r.MaxBodySize = webAuthnMaxBodySize
+				if err := d.AddRoute(r); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/scheme"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/publicsuffix"
+)
+
+type testCredentialStore struct {
+	creds []*scheme.WebAuthnCredential
+}
+
+func (s *testCredentialStore) Init(appCfg *config.Config) error { return nil }
+
+func (s *testCredentialStore) Credentials(userID string) ([]*scheme.WebAuthnCredential, error) {
+	var creds []*scheme.WebAuthnCredential
+	for _, c := range s.creds {
+		if c.UserID == userID {
+			creds = append(creds, c)
+		}
+	}
+	return creds, nil
+}
+
+func (s *testCredentialStore) Credential(id []byte) (*scheme.WebAuthnCredential, error) {
+	for _, c := range s.creds {
+		if bytes.Equal(c.ID, id) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *testCredentialStore) SaveCredential(c *scheme.WebAuthnCredential) error {
+	s.creds = append(s.creds, c)
+	return nil
+}
+
+func (s *testCredentialStore) UpdateSignCount(id []byte, signCount uint32) error {
+	return nil
+}
+
+func TestWebAuthnLoginAndRegistration(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [WebAuthn]: %s", ts.URL)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    passkey_auth {
+		      scheme = "webauthn"
+		      rp_id = "localhost"
+		      origins = ["` + ts.URL + `"]
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initSecurity())
+	assert.Nil(t, ts.app.initRouter())
+
+	domain := ts.app.Router().Lookup(ts.URL[7:])
+	assert.NotNil(t, domain.LookupByName("passkey_auth_login_begin__aah"))
+	assert.NotNil(t, domain.LookupByName("passkey_auth_register_finish__aah"))
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	coseKey := append([]byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}, ecKey.X.FillBytes(make([]byte, 32))...)
+	coseKey = append(append(coseKey, 0x22, 0x58, 0x20), ecKey.Y.FillBytes(make([]byte, 32))...)
+
+	store := &testCredentialStore{creds: []*scheme.WebAuthnCredential{
+		{ID: []byte("cred-1"), UserID: "jeeva", PublicKey: coseKey},
+	}}
+	wa := ts.app.SecurityManager().AuthScheme("passkey_auth").(*scheme.WebAuthn)
+	assert.Nil(t, wa.SetCredentialStore(store))
+
+	rpIDHash := sha256.Sum256([]byte("localhost"))
+	credential := func(credID []byte, typ, challenge string, resp map[string]string) []byte {
+		clientData, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": ts.URL})
+		resp["clientDataJSON"] = b64(clientData)
+		if typ == "webauthn.get" {
+			authData := binary.BigEndian.AppendUint32(append(rpIDHash[:], 0x05), 0)
+			cdHash := sha256.Sum256(clientData)
+			sum := sha256.Sum256(append(authData, cdHash[:]...))
+			sig, _ := ecdsa.SignASN1(rand.Reader, ecKey, sum[:])
+			resp["authenticatorData"], resp["signature"] = b64(authData), b64(sig)
+		}
+		b, _ := json.Marshal(map[string]interface{}{"id": b64(credID), "rawId": b64(credID), "type": "public-key", "response": resp})
+		return b
+	}
+	post := func(client *http.Client, path string, body []byte) (*http.Response, map[string]interface{}) {
+		resp, err := client.Post(ts.URL+path, "application/json", bytes.NewReader(body))
+		assert.Nil(t, err)
+		m := make(map[string]interface{})
+		_ = json.Unmarshal([]byte(responseBody(resp)), &m)
+		return resp, m
+	}
+
+	cookieJar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	client := &http.Client{Jar: cookieJar}
+
+	// Registration requires authenticated subject
+	resp, _ := post(client, "/passkey-auth/register/begin", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Login
+	resp, opts := post(client, "/passkey-auth/login/begin", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "localhost", opts["rpId"])
+	challenge := opts["challenge"].(string)
+
+	resp, _ = post(client, "/passkey-auth/login/finish", credential([]byte("unknown"), "webauthn.get", challenge, map[string]string{}))
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// challenge is consumed by the failed attempt
+	resp, _ = post(client, "/passkey-auth/login/finish", credential([]byte("cred-1"), "webauthn.get", challenge, map[string]string{}))
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, opts = post(client, "/passkey-auth/login/begin", nil)
+	resp, result := post(client, "/passkey-auth/login/finish", credential([]byte("cred-1"), "webauthn.get", opts["challenge"].(string), map[string]string{}))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/", result["redirect"])
+
+	// Register another passkey for the authenticated subject
+	resp, opts = post(client, "/passkey-auth/register/begin", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, b64([]byte("jeeva")), opts["user"].(map[string]interface{})["id"])
+	assert.Len(t, opts["excludeCredentials"], 1)
+
+	authData := binary.BigEndian.AppendUint32(append(rpIDHash[:], 0x45), 0)
+	authData = append(append(authData, make([]byte, 16)...), 0x00, 0x06)
+	authData = append(append(authData, "cred-2"...), coseKey...)
+	attObj := append([]byte("\xa3\x63fmt\x64none\x67attStmt\xa0\x68authData\x58"), byte(len(authData)))
+	attObj = append(attObj, authData...)
+
+	resp, result = post(client, "/passkey-auth/register/finish", credential([]byte("cred-2"), "webauthn.create",
+		opts["challenge"].(string), map[string]string{"attestationObject": b64(attObj)}))
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, b64([]byte("cred-2")), result["id"])
+	assert.Len(t, store.creds, 2)
+	assert.Equal(t, "jeeva", store.creds[1].UserID)
+
+	resp, _ = post(client, "/passkey-auth/register/finish", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWebAuthnRegistrationDeniedOnRunAs(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	cfg, _ := config.ParseString(`
+		security {
+		  auth_schemes {
+		    passkey_auth {
+		      scheme = "webauthn"
+		      rp_id = "localhost"
+		      origins = ["http://localhost:8080"]
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initSecurity())
+
+	for _, path := range []string{"/passkey-auth/register/begin", "/passkey-auth/register/finish"} {
+		req := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080"+path, nil)
+		ctx := newContext(httptest.NewRecorder(), req)
+		ctx.a = a
+		populateAuthenticationInfo(testGetAuthenticationInfo(), ctx)
+		ctx.Session().IsAuthenticated = true
+		assert.Nil(t, ctx.Subject().RunAs([]*authc.Principal{{Value: "user1", IsPrimary: true}}))
+
+		c := &webAuthnController{Context: ctx}
+		if strings.HasSuffix(path, "begin") {
+			c.RegisterBegin()
+		} else {
+			c.RegisterFinish()
+		}
+		assert.Equal(t, http.StatusForbidden, ctx.Reply().Code)
+		assert.Equal(t, ErrAccessDenied, ctx.Reply().err.Reason)
+	}
+}
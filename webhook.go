@@ -0,0 +1,595 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	ess "aahframe.work/essentials"
+	"aahframe.work/security/acrypto"
+)
+
+// Webhook delivery states
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusRetrying  = "retrying"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusDead      = "dead"
+)
+
+// Webhook request headers
+const (
+	HeaderWebhookID        = "X-Webhook-Id"
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+)
+
+// Webhook errors
+var (
+	ErrWebhookNotEnabled       = errors.New("aah: webhook is not enabled")
+	ErrWebhookQueueFull        = errors.New("aah: webhook delivery queue is full")
+	ErrWebhookDeliveryNotFound = errors.New("aah: webhook delivery not found")
+)
+
+type (
+	// WebhookEndpoint struct holds the outbound webhook endpoint details, it
+	// receives the events which are subscribed via `Events`, event name `*`
+	// subscribes to all events.
+	WebhookEndpoint struct {
+		Name   string
+		URL    string
+		Secret string
+		Events []string
+	}
+
+	// WebhookDelivery struct holds the delivery record of single event to
+	// an endpoint, it's exposed via `WebhookManager.Delivery`,
+	// `WebhookManager.Deliveries` and `WebhookManager.DeadLetters`.
+	WebhookDelivery struct {
+		ID             string    `json:"id"`
+		Endpoint       string    `json:"endpoint"`
+		Event          string    `json:"event"`
+		Status         string    `json:"status"`
+		Attempts       int       `json:"attempts"`
+		LastStatusCode int       `json:"last_status_code,omitempty"`
+		LastError      string    `json:"last_error,omitempty"`
+		CreatedAt      time.Time `json:"created_at"`
+		NextAttemptAt  time.Time `json:"next_attempt_at,omitempty"`
+		DeliveredAt    time.Time `json:"delivered_at,omitempty"`
+		Payload        []byte    `json:"-"`
+	}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// WebhookManager method returns aah application outbound webhook manager.
+//
+// 	webhook {
+// 	  enable = true
+// 	  endpoints {
+// 	    billing {
+// 	      url = "https://billing.example.com/hooks"
+// 	      events = ["order.created"]
+// 	      secret = "..."
+// 	    }
+// 	  }
+// 	}
+//
+// Delivering an event:
+//
+// 	ids, err := aah.App().WebhookManager().Deliver("order.created", order)
+//
+// Deliveries are processed by workers started along with aah server, pending
+// deliveries are marked as dead on app shutdown.
+func (a *Application) WebhookManager() *WebhookManager {
+	return a.webhookMgr
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// WebhookManager
+//______________________________________________________________________________
+
+// WebhookManager delivers the events to registered endpoints as JSON payload
+// with HMAC SHA-256 signature header, failed delivery is retried with
+// exponential backoff and moved to dead letters after max attempts.
+//
+// Payload:
+//
+// 	{"id": "<delivery id>", "event": "order.created", "created_at": "...", "data": {...}}
+//
+// Signature header value is `sha256=<hex>` of HMAC SHA-256 over
+// `<X-Webhook-Timestamp>.<body>` using endpoint secret.
+type WebhookManager struct {
+	a           *Application
+	mu          sync.RWMutex
+	enabled     bool
+	started     bool
+	stopped     bool
+	endpoints   map[string]*WebhookEndpoint
+	deliveries  map[string]*WebhookDelivery
+	order       []string
+	deadLetters []string
+	timers      map[string]*time.Timer
+	queue       chan *WebhookDelivery
+	stop        chan struct{}
+	client      *http.Client
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	workers     int
+	maxAttempts int
+	retention   int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	sigHeader   string
+}
+
+// Register method registers the webhook endpoint, it returns an error if
+// endpoint name already exists or URL is invalid.
+func (m *WebhookManager) Register(ep *WebhookEndpoint) error {
+	if ep == nil || len(ep.Name) == 0 {
+		return errors.New("aah: webhook endpoint name is empty")
+	}
+	if u, err := url.Parse(ep.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("aah: webhook endpoint '%s' url is invalid", ep.Name)
+	}
+	if len(ep.Events) == 0 {
+		return fmt.Errorf("aah: webhook endpoint '%s' events is empty", ep.Name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.endpoints[ep.Name]; found {
+		return fmt.Errorf("aah: webhook endpoint '%s' already exists", ep.Name)
+	}
+	m.endpoints[ep.Name] = ep
+	return nil
+}
+
+// Deliver method queues the event delivery to all the endpoints which are
+// subscribed to the event, data is marshalled as JSON. It returns the
+// queued delivery IDs. Delivery which could not be queued is moved to dead
+// letters and its endpoints are reported in the returned error.
+func (m *WebhookManager) Deliver(event string, data interface{}) ([]string, error) {
+	m.mu.RLock()
+	enabled, stopped := m.enabled, m.stopped
+	var eps []*WebhookEndpoint
+	for _, ep := range m.endpoints {
+		if ep.subscribed(event) {
+			eps = append(eps, ep)
+		}
+	}
+	m.mu.RUnlock()
+	if !enabled || stopped {
+		return nil, ErrWebhookNotEnabled
+	}
+	sort.Slice(eps, func(i, j int) bool { return eps[i].Name < eps[j].Name })
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids, failed []string
+	var lastErr error
+	for _, ep := range eps {
+		d := &WebhookDelivery{
+			ID:        ess.SecureRandomString(32),
+			Endpoint:  ep.Name,
+			Event:     event,
+			Status:    WebhookStatusPending,
+			CreatedAt: time.Now().UTC(),
+		}
+		payload, err := json.Marshal(map[string]interface{}{
+			"id": d.ID, "event": event, "created_at": d.CreatedAt, "data": json.RawMessage(body),
+		})
+		if err != nil {
+			return ids, err
+		}
+		d.Payload = payload
+
+		m.mu.Lock()
+		m.track(d)
+		m.mu.Unlock()
+		if err = m.enqueue(d); err != nil {
+			m.dead(d, err.Error())
+			failed, lastErr = append(failed, ep.Name), err
+			continue
+		}
+		ids = append(ids, d.ID)
+	}
+
+	if len(failed) > 0 {
+		return ids, fmt.Errorf("aah: webhook event '%s' delivery to endpoints [%s] failed: %w",
+			event, strings.Join(failed, ", "), lastErr)
+	}
+	return ids, nil
+}
+
+// Redeliver method queues the dead delivery again with reset attempts.
+func (m *WebhookManager) Redeliver(id string) error {
+	m.mu.Lock()
+	if !m.enabled || m.stopped {
+		m.mu.Unlock()
+		return ErrWebhookNotEnabled
+	}
+	d, found := m.deliveries[id]
+	if !found || d.Status != WebhookStatusDead {
+		m.mu.Unlock()
+		return ErrWebhookDeliveryNotFound
+	}
+	d.Status, d.Attempts, d.LastError, d.LastStatusCode = WebhookStatusPending, 0, "", 0
+	for i, dlID := range m.deadLetters {
+		if dlID == id {
+			m.deadLetters = append(m.deadLetters[:i], m.deadLetters[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	if err := m.enqueue(d); err != nil {
+		m.dead(d, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Delivery method returns the copy of delivery record for given ID otherwise
+// nil.
+func (m *WebhookManager) Delivery(id string) *WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if d, found := m.deliveries[id]; found {
+		dc := *d
+		return &dc
+	}
+	return nil
+}
+
+// Deliveries method returns the copy of delivery records of given endpoint
+// name in the order of creation, empty name returns all.
+func (m *WebhookManager) Deliveries(endpoint string) []*WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*WebhookDelivery
+	for _, id := range m.order {
+		if d := m.deliveries[id]; len(endpoint) == 0 || d.Endpoint == endpoint {
+			dc := *d
+			result = append(result, &dc)
+		}
+	}
+	return result
+}
+
+// DeadLetters method returns the copy of delivery records which are failed
+// after max attempts or not delivered before app shutdown. Event
+// `OnWebhookDeadLetter` is published for each, to persist it.
+func (m *WebhookManager) DeadLetters() []*WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*WebhookDelivery, 0, len(m.deadLetters))
+	for _, id := range m.deadLetters {
+		dc := *m.deliveries[id]
+		result = append(result, &dc)
+	}
+	return result
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func newWebhookManager(a *Application) *WebhookManager {
+	m := &WebhookManager{
+		a:          a,
+		endpoints:  make(map[string]*WebhookEndpoint),
+		deliveries: make(map[string]*WebhookDelivery),
+		timers:     make(map[string]*time.Timer),
+		stop:       make(chan struct{}),
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	return m
+}
+
+func (a *Application) initWebhook() error {
+	cfg := a.Config()
+	keyPrefix := "webhook"
+	m := a.webhookMgr
+	if m.enabled = cfg.BoolDefault(keyPrefix+".enable", false); !m.enabled {
+		return nil
+	}
+
+	var err error
+	var timeout time.Duration
+	for key, d := range map[string]struct {
+		dst *time.Duration
+		def string
+	}{
+		"timeout":     {&timeout, "10s"},
+		"backoff.min": {&m.minBackoff, "1s"},
+		"backoff.max": {&m.maxBackoff, "10m"},
+	} {
+		if *d.dst, err = time.ParseDuration(cfg.StringDefault(keyPrefix+"."+key, d.def)); err != nil {
+			return fmt.Errorf("aah: '%s.%s' value is not a valid time unit", keyPrefix, key)
+		}
+	}
+	if m.minBackoff <= 0 || m.maxBackoff < m.minBackoff {
+		return fmt.Errorf("aah: '%s.backoff' min value must be greater than zero and less than or equal to max value", keyPrefix)
+	}
+
+	m.workers = cfg.IntDefault(keyPrefix+".workers", 4)
+	m.maxAttempts = cfg.IntDefault(keyPrefix+".max_attempts", 6)
+	m.retention = cfg.IntDefault(keyPrefix+".retention", 1000)
+	m.sigHeader = cfg.StringDefault(keyPrefix+".signature_header", "X-Webhook-Signature")
+	m.queue = make(chan *WebhookDelivery, cfg.IntDefault(keyPrefix+".queue_size", 1000))
+	m.client = &http.Client{Timeout: timeout}
+	if m.workers <= 0 || m.maxAttempts <= 0 {
+		return fmt.Errorf("aah: '%s.workers' and '%s.max_attempts' value must be greater than zero", keyPrefix, keyPrefix)
+	}
+
+	names := cfg.KeysByPath(keyPrefix + ".endpoints")
+	sort.Strings(names)
+	for _, name := range names {
+		epKey := keyPrefix + ".endpoints." + name
+		ep := &WebhookEndpoint{
+			Name:   name,
+			URL:    cfg.StringDefault(epKey+".url", ""),
+			Secret: cfg.StringDefault(epKey+".secret", ""),
+		}
+		ep.Events, _ = cfg.StringList(epKey + ".events")
+		if err = m.Register(ep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start method starts the delivery workers, it's called on aah server start.
+func (m *WebhookManager) start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.enabled || m.started || m.stopped {
+		return
+	}
+	m.started = true
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+}
+
+// shutdown method stops the workers and waits for in-flight deliveries upto
+// given timeout, undelivered ones are moved to dead letters.
+func (m *WebhookManager) shutdown(timeout time.Duration) {
+	m.mu.Lock()
+	if !m.enabled || m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	m.stopped = true
+	close(m.stop)
+	for id, t := range m.timers {
+		t.Stop()
+		delete(m.timers, id)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		m.a.Log().Warnf("Webhook: workers did not stop within %s", timeout)
+	}
+	m.cancel()
+
+	m.mu.RLock()
+	var undelivered []*WebhookDelivery
+	for _, id := range m.order {
+		if d := m.deliveries[id]; d.Status == WebhookStatusPending || d.Status == WebhookStatusRetrying {
+			undelivered = append(undelivered, d)
+		}
+	}
+	m.mu.RUnlock()
+	for _, d := range undelivered {
+		m.dead(d, "app shutdown")
+	}
+}
+
+func (m *WebhookManager) work() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case d := <-m.queue:
+			m.attempt(d)
+		}
+	}
+}
+
+// attempt method makes the delivery attempt, on failure retry is scheduled
+// with exponential backoff upto max attempts.
+func (m *WebhookManager) attempt(d *WebhookDelivery) {
+	m.mu.Lock()
+	ep := m.endpoints[d.Endpoint]
+	d.Attempts++
+	attempts := d.Attempts
+	m.mu.Unlock()
+	if ep == nil {
+		m.dead(d, fmt.Sprintf("endpoint '%s' not exists", d.Endpoint))
+		return
+	}
+
+	code, err := m.post(ep, d)
+	if err == nil && code >= 200 && code < 300 {
+		m.mu.Lock()
+		d.Status, d.LastStatusCode, d.LastError, d.DeliveredAt = WebhookStatusDelivered, code, "", time.Now().UTC()
+		m.mu.Unlock()
+		m.a.Log().Debugf("Webhook: delivery '%s' of event '%s' to '%s' succeeded", d.ID, d.Event, ep.Name)
+		return
+	}
+
+	reason := fmt.Sprintf("status code %d", code)
+	if err != nil {
+		reason = err.Error()
+	}
+	m.mu.Lock()
+	d.LastStatusCode, d.LastError = code, reason
+	m.mu.Unlock()
+
+	// Client errors are not retried except timeout and too many requests
+	retryable := err != nil || code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+	if !retryable || attempts >= m.maxAttempts {
+		m.dead(d, reason)
+		return
+	}
+
+	delay := m.minBackoff
+	for i := 1; i < attempts && delay < m.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > m.maxBackoff {
+		delay = m.maxBackoff
+	}
+	m.a.Log().Warnf("Webhook: delivery '%s' to '%s' failed: %s, retrying in %s", d.ID, ep.Name, reason, delay)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return
+	}
+	d.Status, d.NextAttemptAt = WebhookStatusRetrying, time.Now().UTC().Add(delay)
+	m.timers[d.ID] = time.AfterFunc(delay, func() {
+		m.mu.Lock()
+		delete(m.timers, d.ID)
+		m.mu.Unlock()
+		if err := m.enqueue(d); err != nil {
+			m.dead(d, err.Error())
+		}
+	})
+}
+
+// post method sends the signed payload to the endpoint and returns the
+// response status code.
+func (m *WebhookManager) post(ep *WebhookEndpoint, d *WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(ahttp.MethodPost, ep.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(m.ctx)
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+	req.Header.Set(ahttp.HeaderUserAgent, "aah-webhook")
+	req.Header.Set(HeaderWebhookID, d.ID)
+	req.Header.Set(HeaderWebhookEvent, d.Event)
+	req.Header.Set(HeaderWebhookTimestamp, ts)
+	if len(ep.Secret) > 0 {
+		req.Header.Set(m.sigHeader, "sha256="+webhookSignature(ep.Secret, ts, d.Payload))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer ess.CloseQuietly(resp.Body)
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func (m *WebhookManager) enqueue(d *WebhookDelivery) error {
+	select {
+	case m.queue <- d:
+		return nil
+	default:
+		return ErrWebhookQueueFull
+	}
+}
+
+// dead method moves the delivery to dead letters and publishes the event
+// `OnWebhookDeadLetter`.
+func (m *WebhookManager) dead(d *WebhookDelivery, reason string) {
+	m.mu.Lock()
+	if d.Status == WebhookStatusDead {
+		m.mu.Unlock()
+		return
+	}
+	d.Status, d.LastError, d.NextAttemptAt = WebhookStatusDead, reason, time.Time{}
+	m.deadLetters = append(m.deadLetters, d.ID)
+	dc := *d
+	m.mu.Unlock()
+
+	m.a.Log().Errorf("Webhook: delivery '%s' of event '%s' to '%s' is dead: %s", d.ID, d.Event, d.Endpoint, reason)
+	m.a.EventStore().PublishSync(&Event{Name: EventOnWebhookDeadLetter, Data: &dc})
+}
+
+// track method adds the delivery record and evicts the oldest completed
+// records beyond the retention, pending and retrying records are retained.
+func (m *WebhookManager) track(d *WebhookDelivery) {
+	m.deliveries[d.ID] = d
+	m.order = append(m.order, d.ID)
+	excess := len(m.order) - m.retention
+	if excess <= 0 {
+		return
+	}
+
+	evicted := make(map[string]bool)
+	order := m.order[:0]
+	for _, id := range m.order {
+		if old := m.deliveries[id]; excess > 0 &&
+			(old.Status == WebhookStatusDelivered || old.Status == WebhookStatusDead) {
+			delete(m.deliveries, id)
+			evicted[id] = true
+			excess--
+			continue
+		}
+		order = append(order, id)
+	}
+	m.order = order
+
+	deadLetters := m.deadLetters[:0]
+	for _, id := range m.deadLetters {
+		if !evicted[id] {
+			deadLetters = append(deadLetters, id)
+		}
+	}
+	m.deadLetters = deadLetters
+}
+
+func (ep *WebhookEndpoint) subscribed(event string) bool {
+	for _, e := range ep.Events {
+		if e == "*" || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookSignature method returns the hex encoded HMAC SHA-256 of
+// `<timestamp>.<payload>` using the secret.
+func webhookSignature(secret, timestamp string, payload []byte) string {
+	return hex.EncodeToString(acrypto.Sign([]byte(secret), append([]byte(timestamp+"."), payload...), "sha-256"))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// WebhookProvider method returns the inbound webhook provider configured at
+// `security.webhooks.<name>` otherwise nil. Route should have
+// `raw_body = true` in the routes.conf to verify the request body via
+// `ctx.Req.RawBody()` after the auto binding.
+//
+// 	security {
+// 	  webhooks {
+// 	    github {
+// 	      provider = "github"
+// 	      secret = "..."
+// 	    }
+// 	  }
+// 	}
+//
+// Verifying the request:
+//
+// 	if err := ctx.Req.VerifyWebhook(aah.App().WebhookProvider("github")); err != nil {
+// 		ctx.Reply().Unauthorized().Text("invalid webhook signature")
+// 		return
+// 	}
+func (a *Application) WebhookProvider(name string) ahttp.WebhookProvider {
+	if p, found := a.webhookProvs[name]; found {
+		return p
+	}
+	return nil
+}
+
+// SetWebhookReplayGuard method sets the replay guard for configured inbound
+// webhook providers, for e.g.: shared store for multi instance deployment.
+// Default is in-memory guard. It has to be set before app initialization.
+func (a *Application) SetWebhookReplayGuard(guard ahttp.WebhookReplayGuard) {
+	a.webhookGuard = guard
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initWebhookProviders() error {
+	cfg := a.Config()
+	keyPrefix := "security.webhooks"
+	names := cfg.KeysByPath(keyPrefix)
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	if a.webhookGuard == nil {
+		a.webhookGuard = ahttp.NewMemoryReplayGuard()
+	}
+
+	providers := make(map[string]ahttp.WebhookProvider)
+	for _, name := range names {
+		key := keyPrefix + "." + name
+		secret := cfg.StringDefault(key+".secret", "")
+		if len(secret) == 0 {
+			return fmt.Errorf("aah: '%s.secret' is required", key)
+		}
+		tolerance, err := time.ParseDuration(cfg.StringDefault(key+".tolerance", "5m"))
+		if err != nil {
+			return fmt.Errorf("aah: '%s.tolerance' value is not a valid time unit", key)
+		}
+		var guard ahttp.WebhookReplayGuard
+		if cfg.BoolDefault(key+".replay_protection", true) {
+			guard = a.webhookGuard
+		}
+
+		switch provider := cfg.StringDefault(key+".provider", name); provider {
+		case "github":
+			providers[name] = &ahttp.GitHubWebhook{Secret: secret, Tolerance: tolerance, ReplayGuard: guard}
+		case "stripe":
+			providers[name] = &ahttp.StripeWebhook{Secret: secret, Tolerance: tolerance, ReplayGuard: guard}
+		case "slack":
+			providers[name] = &ahttp.SlackWebhook{Secret: secret, Tolerance: tolerance, ReplayGuard: guard}
+		default:
+			return fmt.Errorf("aah: '%s.provider' value '%s' is not supported", key, provider)
+		}
+	}
+	a.webhookProvs = providers
+	return nil
+}
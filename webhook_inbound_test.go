@@ -0,0 +1,88 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookInbound(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Inbound Webhook]: %s", ts.URL)
+
+	a := ts.app
+	assert.Nil(t, a.WebhookProvider("github"))
+	assert.True(t, a.Router().Lookup("localhost:8080").LookupByName("github_webhook").RawBody)
+	assert.True(t, a.Router().Lookup("localhost:8080").LookupByName("github_webhook").HasTag("webhook"))
+
+	cfg, _ := config.ParseString(`
+		security {
+		  webhooks {
+		    github {
+		      secret = "s3cr3t"
+		    }
+		    payments {
+		      provider = "stripe"
+		      secret = "whsec"
+		      replay_protection = false
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initWebhookProviders())
+	assert.NotNil(t, a.WebhookProvider("github"))
+	assert.Nil(t, a.WebhookProvider("payments").(*ahttp.StripeWebhook).ReplayGuard)
+
+	body := `{"first_name":"Jeeva","number":1}`
+	newReq := func(secret, id string) *http.Request {
+		req, err := http.NewRequest(ahttp.MethodPost, ts.URL+"/webhooks/github", strings.NewReader(body))
+		assert.Nil(t, err)
+		req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+		req.Header.Set("X-Hub-Signature-256", "sha256="+testInboundWebhookMAC(secret, body))
+		req.Header.Set("X-GitHub-Delivery", id)
+		return req
+	}
+
+	// valid signature, body is bound too
+	result := fireRequest(t, newReq("s3cr3t", "delivery-1"))
+	assert.Equal(t, 200, result.StatusCode)
+	assert.True(t, strings.Contains(result.Body, `"first_name":"Jeeva"`))
+
+	// replayed
+	result = fireRequest(t, newReq("s3cr3t", "delivery-1"))
+	assert.Equal(t, 401, result.StatusCode)
+	assert.Equal(t, ahttp.ErrWebhookReplayed.Error(), result.Body)
+
+	// invalid signature
+	result = fireRequest(t, newReq("other", "delivery-2"))
+	assert.Equal(t, 401, result.StatusCode)
+	assert.Equal(t, ahttp.ErrWebhookSignatureInvalid.Error(), result.Body)
+
+	a.Config().SetString("security.webhooks.github.provider", "gitlab")
+	assert.Equal(t, "aah: 'security.webhooks.github.provider' value 'gitlab' is not supported",
+		a.initWebhookProviders().Error())
+	a.Config().SetString("security.webhooks.github.secret", "")
+	assert.Equal(t, "aah: 'security.webhooks.github.secret' is required", a.initWebhookProviders().Error())
+}
+
+func testInboundWebhookMAC(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,220 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	var failures int32 = 2
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rejected":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case "/flaky":
+			if atomic.AddInt32(&failures, -1) >= 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		received, bodies = append(received, r), append(bodies, b)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	// not enabled
+	_, err := a.WebhookManager().Deliver("order.created", nil)
+	assert.Equal(t, ErrWebhookNotEnabled, err)
+
+	cfg, _ := config.ParseString(`
+		webhook {
+		  enable = true
+		  workers = 2
+		  max_attempts = 3
+		  backoff {
+		    min = "10ms"
+		    max = "20ms"
+		  }
+		  endpoints {
+		    billing {
+		      url = "` + srv.URL + `/flaky"
+		      events = ["order.created"]
+		      secret = "s3cr3t"
+		    }
+		    audit {
+		      url = "` + srv.URL + `/audit"
+		      events = ["*"]
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initWebhook())
+	m := a.WebhookManager()
+	assert.Equal(t, "aah: webhook endpoint 'audit' already exists",
+		m.Register(&WebhookEndpoint{Name: "audit", URL: srv.URL, Events: []string{"*"}}).Error())
+	assert.Equal(t, "aah: webhook endpoint 'crm' url is invalid",
+		m.Register(&WebhookEndpoint{Name: "crm", URL: "ftp://crm", Events: []string{"*"}}).Error())
+	assert.Nil(t, m.Register(&WebhookEndpoint{Name: "crm", URL: srv.URL + "/rejected", Events: []string{"order.created"}}))
+
+	var deadLetters []*WebhookDelivery
+	a.SubscribeEvent(EventOnWebhookDeadLetter, EventCallback{Callback: func(e *Event) {
+		deadLetters = append(deadLetters, e.Data.(*WebhookDelivery))
+	}})
+
+	m.start()
+	ids, err := m.Deliver("order.created", map[string]string{"order_id": "1001"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(ids))
+	ids2, _ := m.Deliver("user.signup", nil)
+	assert.Equal(t, 1, len(ids2))
+
+	waitFor := func(id, status string) *WebhookDelivery {
+		for i := 0; i < 200; i++ {
+			if d := m.Delivery(id); d.Status == status {
+				return d
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("delivery '%s' is not %s: %#v", id, status, m.Delivery(id))
+		return nil
+	}
+
+	// audit, billing, crm by endpoint name
+	d := waitFor(ids[0], WebhookStatusDelivered)
+	assert.Equal(t, 1, d.Attempts)
+	d = waitFor(ids[1], WebhookStatusDelivered)
+	assert.Equal(t, "billing", d.Endpoint)
+	assert.Equal(t, 3, d.Attempts)
+	d = waitFor(ids[2], WebhookStatusDead)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusBadRequest, d.LastStatusCode)
+	waitFor(ids2[0], WebhookStatusDelivered)
+
+	assert.Equal(t, 1, len(m.DeadLetters()))
+	assert.Equal(t, 1, len(deadLetters))
+	assert.Equal(t, "crm", deadLetters[0].Endpoint)
+	assert.Equal(t, 2, len(m.Deliveries("audit")))
+	assert.Equal(t, 4, len(m.Deliveries("")))
+
+	// signature
+	mu.Lock()
+	for i, r := range received {
+		if r.URL.Path != "/flaky" {
+			continue
+		}
+		ts := r.Header.Get(HeaderWebhookTimestamp)
+		assert.Equal(t, ids[1], r.Header.Get(HeaderWebhookID))
+		assert.Equal(t, "order.created", r.Header.Get(HeaderWebhookEvent))
+		assert.Equal(t, "sha256="+webhookSignature("s3cr3t", ts, bodies[i]), r.Header.Get("X-Webhook-Signature"))
+
+		var payload map[string]interface{}
+		assert.Nil(t, json.Unmarshal(bodies[i], &payload))
+		assert.Equal(t, ids[1], payload["id"])
+		assert.Equal(t, "1001", payload["data"].(map[string]interface{})["order_id"])
+	}
+	mu.Unlock()
+
+	// redeliver dead letter
+	assert.Equal(t, ErrWebhookDeliveryNotFound, m.Redeliver(ids[0]))
+	assert.Nil(t, m.Redeliver(ids[2]))
+	waitFor(ids[2], WebhookStatusDead)
+	assert.Equal(t, 1, len(m.DeadLetters()))
+
+	// undelivered ones are dead on shutdown
+	m.mu.Lock()
+	m.endpoints["crm"].URL = srv.URL + "/flaky"
+	m.mu.Unlock()
+	atomic.StoreInt32(&failures, 100)
+	m.minBackoff, m.maxBackoff = time.Minute, time.Minute
+	ids, _ = m.Deliver("order.created", nil)
+	waitFor(ids[1], WebhookStatusRetrying)
+	m.shutdown(time.Second)
+	assert.Equal(t, WebhookStatusDead, m.Delivery(ids[1]).Status)
+	assert.Equal(t, "app shutdown", m.Delivery(ids[1]).LastError)
+	_, err = m.Deliver("order.created", nil)
+	assert.Equal(t, ErrWebhookNotEnabled, err)
+
+	a.webhookMgr = newWebhookManager(a)
+	a.Config().SetString("webhook.backoff.min", "1 second")
+	assert.Equal(t, "aah: 'webhook.backoff.min' value is not a valid time unit", a.initWebhook().Error())
+}
+
+func TestWebhookDeliverQueueFull(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	cfg, _ := config.ParseString(`
+		webhook {
+		  enable = true
+		  queue_size = 1
+		  retention = 3
+		  endpoints {
+		    audit {
+		      url = "http://localhost:8080/audit"
+		      events = ["*"]
+		    }
+		    billing {
+		      url = "http://localhost:8080/billing"
+		      events = ["*"]
+		    }
+		    crm {
+		      url = "http://localhost:8080/crm"
+		      events = ["*"]
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.initWebhook())
+	m := a.WebhookManager()
+
+	// workers are not started, queue accepts only the first one
+	ids, err := m.Deliver("order.created", nil)
+	assert.Equal(t, 1, len(ids))
+	assert.True(t, errors.Is(err, ErrWebhookQueueFull))
+	assert.Equal(t, "aah: webhook event 'order.created' delivery to endpoints [billing, crm] failed: aah: webhook delivery queue is full", err.Error())
+	assert.Equal(t, WebhookStatusPending, m.Delivery(ids[0]).Status)
+	assert.Equal(t, 2, len(m.DeadLetters()))
+
+	// completed records are evicted beyond retention, pending one is retained
+	_, err = m.Deliver("order.created", nil)
+	assert.NotNil(t, err)
+	deliveries := m.Deliveries("")
+	assert.Equal(t, 3, len(deliveries))
+	assert.Equal(t, ids[0], deliveries[0].ID)
+	assert.Equal(t, 2, len(m.DeadLetters()))
+	for _, d := range deliveries[1:] {
+		assert.Equal(t, WebhookStatusDead, d.Status)
+	}
+
+	_, err = m.Deliver("order.created", func() {})
+	assert.NotNil(t, err)
+}
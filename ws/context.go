@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"sync"
 
 	"aahframe.work/ainsp"
 	"aahframe.work/log"
@@ -48,6 +49,8 @@ type Context struct {
 	logger     log.Loggerer
 	reason     error
 	abortCode  int
+	events     *eventBridge
+	wmu        sync.Mutex
 }
 
 // ReadText method reads a text value from WebSocket client.
@@ -93,13 +96,13 @@ func (ctx *Context) ReadXML(t interface{}) error {
 // ReplyText method sends Text data to the WebSocket client returns error
 // if client is gone, network error, etc.
 func (ctx *Context) ReplyText(v string) error {
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, []byte(v)))
+	return ctx.write(gws.OpText, []byte(v))
 }
 
 // ReplyBinary method sends Binary data to the WebSocket client returns
 // error if client is gone, network error, etc.
 func (ctx *Context) ReplyBinary(v []byte) error {
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpBinary, v))
+	return ctx.write(gws.OpBinary, v)
 }
 
 // ReplyJSON method sends JSON data to the WebSocket client returns
@@ -109,7 +112,7 @@ func (ctx *Context) ReplyJSON(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, b))
+	return ctx.write(gws.OpText, b)
 }
 
 // ReplyXML method sends XML data to the WebSocket client returns
@@ -119,7 +122,7 @@ func (ctx *Context) ReplyXML(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, b))
+	return ctx.write(gws.OpText, b)
 }
 
 // Disconnect method disconnects the WebSocket connection immediately. Could be
@@ -171,6 +174,14 @@ func (ctx *Context) callAction() {
 	}
 }
 
+// write method writes the message to WebSocket client, writes are
+// serialized since subscribed events are sent in the background.
+func (ctx *Context) write(op gws.OpCode, b []byte) error {
+	ctx.wmu.Lock()
+	defer ctx.wmu.Unlock()
+	return createError(wsutil.WriteServerMessage(ctx.Conn, op, b))
+}
+
 func (ctx *Context) setTarget(targetName, methodName string) error {
 	if ctx.websocket = ctx.e.registry.Lookup(targetName); ctx.websocket == nil {
 		return ErrNotFound
@@ -85,6 +85,9 @@ type Engine struct {
 	onPostDisconnect EventCallbackFunc
 	onError          EventCallbackFunc
 	idGenerator      IDGenerator
+	eventSubscribe   EventSubscribeFunc
+	eventQueueSize   int
+	eventOverflow    string
 }
 
 // AddWebSocket method adds the given WebSocket implementation into engine.
@@ -172,6 +175,11 @@ func (e *Engine) Handle(w http.ResponseWriter, r *http.Request) {
 	// CallAction method calls the defined action for the WebSocket.
 	ctx.callAction()
 
+	// Remove application event subscriptions
+	if ctx.events != nil {
+		ctx.events.close()
+	}
+
 	if e.onPostDisconnect != nil {
 		e.onPostDisconnect(EventOnPostDisconnect, ctx)
 	}
@@ -16,7 +16,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -159,6 +161,79 @@ func TestEngineWSErrors(t *testing.T) {
 	assert.Equal(t, "405 Method Not Allowed", w.Body.String())
 }
 
+func TestEngineWSEvents(t *testing.T) {
+	cfgStr := `
+    server {
+      websocket {
+        enable = true
+      }
+    }
+  `
+
+	ts := createWSTestServer(t, cfgStr, "routes.conf")
+	wsURL := strings.Replace(ts.ts.URL, "http", "ws", -1) + "/ws/events"
+
+	// event subscriber not set
+	conn, _, _, err := gws.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	assert.Nil(t, wsutil.WriteClientText(conn, []byte("subscribe")))
+	b, err := wsutil.ReadServerText(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrEventSubscriberNotSet.Error(), string(b))
+	_ = conn.Close()
+
+	es := &testEventStore{subs: make(map[string][]*func(data interface{}))}
+	ts.wse.SetEventSubscriber(es.subscribe)
+
+	conn, _, _, err = gws.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	assert.Nil(t, wsutil.WriteClientText(conn, []byte("subscribe")))
+	b, err = wsutil.ReadServerText(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, "subscribed", string(b))
+	assert.Equal(t, 1, es.count("order.updated"))
+
+	es.publish("order.updated", map[string]interface{}{"id": "skip"})
+	es.publish("order.updated", map[string]interface{}{"id": "1001", "status": "shipped"})
+	es.publish("order.created", map[string]interface{}{"id": "1002"})
+	b, err = wsutil.ReadServerText(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"event":"order.updated","data":{"id":"1001","status":"shipped"}}`, string(b))
+
+	// subscriptions are removed on disconnect
+	_ = conn.Close()
+	for i := 0; i < 500 && es.count("order.updated") > 0; i++ {
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.Equal(t, 0, es.count("order.updated"))
+}
+
+func TestEngineWSEventOverflow(t *testing.T) {
+	cfg, _ := config.ParseString(`
+    server {
+      websocket {
+        event {
+          queue_size = 1
+        }
+      }
+    }
+  `)
+	wse := newEngine(t, cfg, "routes.conf")
+	assert.Equal(t, 1, wse.eventQueueSize)
+	assert.Equal(t, "drop", wse.eventOverflow)
+
+	ctx := wse.newContext(httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/ws/events", nil), nil, nil)
+	eb := &eventBridge{ctx: ctx, subs: make(map[string]func()), queue: make(chan *EventMessage, 1)}
+	eb.push(&EventMessage{Event: "order.updated", Data: 1})
+	eb.push(&EventMessage{Event: "order.updated", Data: 2})
+	assert.Equal(t, 1, eb.dropped)
+	assert.Equal(t, 1, (<-eb.queue).Data)
+
+	cfg.SetString("server.websocket.event.overflow", "block")
+	_, err := New(&app{cfg: cfg})
+	assert.Equal(t, "ws: 'server.websocket.event.overflow' value 'block' is not supported", err.Error())
+}
+
 type testServer struct {
 	ts  *httptest.Server
 	wse *Engine
@@ -253,6 +328,7 @@ func addWebSocket(t *testing.T, wse *Engine) {
 		{Name: "Binary", Parameters: []*ainsp.Parameter{{Name: "encoding", Type: reflect.TypeOf((*string)(nil))}}},
 		{Name: "JSON"},
 		{Name: "XML"},
+		{Name: "Events"},
 	})
 }
 
@@ -347,6 +423,62 @@ func (e *testWebSocket) XML() {
 	}
 }
 
+func (e *testWebSocket) Events() {
+	if _, err := e.ReadText(); err != nil {
+		return
+	}
+	if err := e.SubscribeEvent("order.updated", func(data interface{}) bool {
+		return data.(map[string]interface{})["id"] != "skip"
+	}); err != nil {
+		_ = e.ReplyText(err.Error())
+		return
+	}
+	_ = e.ReplyText("subscribed")
+
+	for {
+		if _, err := e.ReadText(); err != nil {
+			return
+		}
+	}
+}
+
+type testEventStore struct {
+	mu   sync.Mutex
+	subs map[string][]*func(data interface{})
+}
+
+func (s *testEventStore) subscribe(eventName string, fn func(data interface{})) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := &fn
+	s.subs[eventName] = append(s.subs[eventName], f)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for idx, sf := range s.subs[eventName] {
+			if sf == f {
+				s.subs[eventName] = append(s.subs[eventName][:idx], s.subs[eventName][idx+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *testEventStore) publish(eventName string, data interface{}) {
+	s.mu.Lock()
+	subs := append([]*func(data interface{}){}, s.subs[eventName]...)
+	s.mu.Unlock()
+	for _, fn := range subs {
+		(*fn)(data)
+	}
+}
+
+func (s *testEventStore) count(eventName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs[eventName])
+}
+
 func testdataBaseDir() string {
 	wd, _ := os.Getwd()
 	if idx := strings.Index(wd, "testdata"); idx > 0 {
@@ -0,0 +1,188 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ws
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	eventOverflowDrop       = "drop"
+	eventOverflowDisconnect = "disconnect"
+)
+
+// ErrEventSubscriberNotSet returned by `Context.SubscribeEvent` if engine does
+// not have application event subscriber.
+var ErrEventSubscriberNotSet = errors.New("aahws: event subscriber not set")
+
+type (
+	// EventSubscribeFunc func type is used to subscribe the application event
+	// by name, returned func unsubscribes it. aah application sets it via
+	// `Engine.SetEventSubscriber`.
+	EventSubscribeFunc func(eventName string, fn func(data interface{})) (unsubscribe func())
+
+	// EventFilter func type is used to filter the application event data for
+	// the WebSocket connection. Return false to skip sending it to client.
+	EventFilter func(data interface{}) bool
+
+	// EventMessage is the JSON message sent to the WebSocket client for
+	// subscribed application event.
+	EventMessage struct {
+		Event string      `json:"event"`
+		Data  interface{} `json:"data"`
+	}
+)
+
+// SetEventSubscriber method sets the application event subscriber, it's
+// used by `Context.SubscribeEvent`.
+func (e *Engine) SetEventSubscriber(fn EventSubscribeFunc) {
+	e.eventSubscribe = fn
+}
+
+// SubscribeEvent method subscribes the WebSocket connection to the given
+// application event, event data is sent to client as JSON `EventMessage`.
+// Optional filter is applied on event data before sending it.
+//
+// Events are queued per connection upto `server.websocket.event.queue_size`
+// and sent in the background. If client is slow and queue is full, event
+// is dropped or connection is disconnected based on config
+// `server.websocket.event.overflow`. Subscriptions are removed once the
+// WebSocket action returns.
+//
+// 	func (w *OrderWebSocket) Updates(orderID string) {
+// 		_ = w.SubscribeEvent("order.updated", func(data interface{}) bool {
+// 			return data.(*models.Order).ID == orderID
+// 		})
+//
+// 		for {
+// 			if _, err := w.ReadText(); err != nil {
+// 				return
+// 			}
+// 		}
+// 	}
+func (ctx *Context) SubscribeEvent(eventName string, filter EventFilter) error {
+	if ctx.e.eventSubscribe == nil {
+		return ErrEventSubscriberNotSet
+	}
+
+	if ctx.events == nil {
+		ctx.events = newEventBridge(ctx)
+	}
+	ctx.events.subscribe(eventName, filter)
+	return nil
+}
+
+// UnsubscribeEvent method unsubscribes the WebSocket connection from the
+// given application event.
+func (ctx *Context) UnsubscribeEvent(eventName string) {
+	if ctx.events != nil {
+		ctx.events.unsubscribe(eventName)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// eventBridge fans out the subscribed application events to the
+// WebSocket connection via bounded queue.
+type eventBridge struct {
+	ctx     *Context
+	mu      sync.Mutex
+	subs    map[string]func()
+	queue   chan *EventMessage
+	done    chan struct{}
+	closed  bool
+	dropped int
+}
+
+func newEventBridge(ctx *Context) *eventBridge {
+	eb := &eventBridge{
+		ctx:   ctx,
+		subs:  make(map[string]func()),
+		queue: make(chan *EventMessage, ctx.e.eventQueueSize),
+		done:  make(chan struct{}),
+	}
+	go eb.write()
+	return eb
+}
+
+func (eb *eventBridge) subscribe(eventName string, filter EventFilter) {
+	eb.unsubscribe(eventName)
+	unsubscribe := eb.ctx.e.eventSubscribe(eventName, func(data interface{}) {
+		if filter != nil && !filter(data) {
+			return
+		}
+		eb.push(&EventMessage{Event: eventName, Data: data})
+	})
+
+	eb.mu.Lock()
+	eb.subs[eventName] = unsubscribe
+	eb.mu.Unlock()
+	eb.ctx.Log().Debugf("WS: subscribed to event '%s'", eventName)
+}
+
+func (eb *eventBridge) unsubscribe(eventName string) {
+	eb.mu.Lock()
+	unsubscribe, found := eb.subs[eventName]
+	delete(eb.subs, eventName)
+	eb.mu.Unlock()
+	if found {
+		unsubscribe()
+	}
+}
+
+// push method queues the event message without blocking the publisher,
+// overflow is handled based on `server.websocket.event.overflow`.
+func (eb *eventBridge) push(m *EventMessage) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	if eb.closed {
+		return
+	}
+
+	select {
+	case eb.queue <- m:
+	default:
+		eb.dropped++
+		if eb.ctx.e.eventOverflow == eventOverflowDisconnect {
+			eb.ctx.Log().Warnf("WS: event queue is full, disconnecting slow client")
+			_ = eb.ctx.Disconnect()
+			return
+		}
+		eb.ctx.Log().Warnf("WS: event queue is full, dropping event '%s' [dropped: %d]", m.Event, eb.dropped)
+	}
+}
+
+func (eb *eventBridge) write() {
+	defer close(eb.done)
+	var failed bool
+	for m := range eb.queue {
+		if failed {
+			continue
+		}
+		if err := eb.ctx.ReplyJSON(m); err != nil {
+			eb.ctx.Log().Errorf("WS: unable to send event '%s': %s", m.Event, err)
+			failed = IsDisconnected(err)
+		}
+	}
+}
+
+// close method removes the event subscriptions and waits for the queued
+// messages to be processed.
+func (eb *eventBridge) close() {
+	eb.mu.Lock()
+	subs := eb.subs
+	eb.subs = make(map[string]func())
+	eb.closed = true
+	close(eb.queue)
+	eb.mu.Unlock()
+
+	for _, unsubscribe := range subs {
+		unsubscribe()
+	}
+	<-eb.done
+}
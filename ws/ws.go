@@ -12,6 +12,7 @@ package ws
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"aahframe.work/ainsp"
 )
@@ -35,6 +36,13 @@ func New(app interface{}) (*Engine, error) {
 
 	eng.checkOrigin = a.Config().BoolDefault(keyPrefix+".origin.check", false)
 
+	// application events bridge
+	eng.eventQueueSize = a.Config().IntDefault(keyPrefix+".event.queue_size", 64)
+	eng.eventOverflow = strings.ToLower(a.Config().StringDefault(keyPrefix+".event.overflow", eventOverflowDrop))
+	if eng.eventOverflow != eventOverflowDrop && eng.eventOverflow != eventOverflowDisconnect {
+		return nil, fmt.Errorf("ws: '%s.event.overflow' value '%s' is not supported", keyPrefix, eng.eventOverflow)
+	}
+
 	// parse whitelist origin urls
 	eng.originWhitelist = make([]*url.URL, 0)
 	if originWhitelist, found := a.Config().StringList(keyPrefix + ".origin.whitelist"); found {